@@ -0,0 +1,159 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// accessLogTimeLayout is the [10/Oct/2000:13:55:36 -0700] timestamp used by Apache and nginx.
+const accessLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogValidator validates web server access logs in Common Log Format (7 fields)
+// or Combined Log Format (9 fields, adding referer and user agent), one request per line.
+//
+// Example:
+//
+//	validator := &AccessLogValidator{baseValidator{format: FormatAccessLog}}
+//	result := validator.ValidateString(`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326`)
+type AccessLogValidator struct {
+	baseValidator
+}
+
+// Validate checks if every non-blank line of the provided byte slice is a Common or Combined
+// Log Format entry with a parseable timestamp and numeric status and byte fields.
+//
+// Example:
+//
+//	validator := &AccessLogValidator{baseValidator{format: FormatAccessLog}}
+//	result := validator.Validate(data)
+func (v *AccessLogValidator) Validate(data []byte) Result {
+	var err error
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if lineErr := parseAccessLogLine(line); lineErr != nil {
+			err = fmt.Errorf("line %d: %w", i+1, lineErr)
+
+			break
+		}
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *AccessLogValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// parseAccessLogLine validates host ident user [time] "request" status bytes ["referer" "agent"].
+func parseAccessLogLine(line string) error {
+	fields, err := splitAccessLogFields(line)
+	if err != nil {
+		return err
+	}
+	if len(fields) != 7 && len(fields) != 9 {
+		return fmt.Errorf("found %d fields, expected 7 (common) or 9 (combined)", len(fields))
+	}
+
+	if !strings.HasPrefix(fields[3], "[") {
+		return errors.New("field 4 must be a bracketed timestamp")
+	}
+	ts := strings.Trim(fields[3], "[]")
+	if _, err := time.Parse(accessLogTimeLayout, ts); err != nil {
+		return fmt.Errorf("invalid timestamp %q: expected dd/Mon/yyyy:hh:mm:ss +zzzz", ts)
+	}
+
+	if !strings.HasPrefix(fields[4], `"`) {
+		return errors.New(`field 5 must be the quoted request line, e.g. "GET / HTTP/1.1"`)
+	}
+
+	status := fields[5]
+	if len(status) != 3 || !isDecimalString(status) || status[0] < '1' || status[0] > '5' {
+		return fmt.Errorf("invalid status code %q", status)
+	}
+	if size := fields[6]; size != "-" && !isDecimalString(size) {
+		return fmt.Errorf("invalid byte count %q: expected a number or '-'", size)
+	}
+
+	if len(fields) == 9 {
+		for i, name := range []string{"referer", "user agent"} {
+			if !strings.HasPrefix(fields[7+i], `"`) {
+				return fmt.Errorf("%s must be quoted", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitAccessLogFields splits on spaces while keeping [bracketed] and "quoted" fields whole.
+// Quoted fields keep their quotes so callers can tell them apart.
+func splitAccessLogFields(line string) ([]string, error) {
+	var fields []string
+	i := 0
+	for i < len(line) {
+		if line[i] == ' ' {
+			i++
+
+			continue
+		}
+
+		start := i
+		switch line[i] {
+		case '[':
+			end := strings.IndexByte(line[i:], ']')
+			if end < 0 {
+				return nil, errors.New("unterminated '[' in timestamp")
+			}
+			i += end + 1
+		case '"':
+			i++
+			for i < len(line) && line[i] != '"' {
+				if line[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(line) {
+				return nil, fmt.Errorf("unterminated quote starting at column %d", start+1)
+			}
+			i++
+		default:
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+		}
+		if i < len(line) && line[i] != ' ' {
+			return nil, fmt.Errorf("missing space after field at column %d", start+1)
+		}
+		fields = append(fields, line[start:i])
+	}
+
+	return fields, nil
+}
+
+// detectAccessLog reports whether every non-blank line is an access log entry.
+func detectAccessLog(lines []string) bool {
+	found := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if parseAccessLogLine(line) != nil {
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
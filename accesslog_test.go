@@ -0,0 +1,59 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAccessLogValidator(t *testing.T) {
+	v := &AccessLogValidator{baseValidator{format: FormatAccessLog}}
+
+	const common = `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`
+	const combined = common + ` "http://www.example.com/start.html" "Mozilla/4.08 [en] (Win98; I ;Nav)"`
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"common", common, true, ""},
+		{"combined", combined, true, ""},
+		{"dash bytes", `::1 - - [10/Oct/2000:13:55:36 +0000] "HEAD / HTTP/1.1" 304 -`, true, ""},
+		{"escaped quote in request", `1.2.3.4 - - [10/Oct/2000:13:55:36 +0000] "GET /\"x HTTP/1.1" 404 0`, true, ""},
+		{"multiple lines", common + "\n\n" + combined + "\n", true, ""},
+		{"too few fields", `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200`, false, "6 fields"},
+		{"bad timestamp", `127.0.0.1 - - [2000-10-10 13:55:36] "GET / HTTP/1.0" 200 1`, false, "invalid timestamp"},
+		{"non-numeric status", `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" OK 1`, false, "status"},
+		{"non-numeric bytes", `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 big`, false, "byte count"},
+		{"unterminated request", `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0 200 1`, false, "unterminated"},
+		{"error line number", common + "\nnot a log line", false, "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestDetectAccessLog(t *testing.T) {
+	input := `10.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 200 512 "-" "curl/8.0"`
+	if got := DetectFormat([]byte(input)); got != FormatAccessLog {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatAccessLog)
+	}
+	for _, name := range []string{"access.log", "/var/log/nginx/site-access.log"} {
+		if got := DetectFormatFromFilename(name); got != FormatAccessLog {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want %v", name, got, FormatAccessLog)
+		}
+	}
+	if got := DetectFormatFromFilename("error.log"); got != FormatUnknown {
+		t.Errorf("DetectFormatFromFilename(error.log) = %v, want %v", got, FormatUnknown)
+	}
+}
@@ -0,0 +1,268 @@
+package serdeval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// arbLocaleRe matches locale tags such as en, en_US, pt-BR, and zh_Hant_TW.
+	arbLocaleRe = regexp.MustCompile(`^[a-z]{2,3}([_-][A-Za-z0-9]{2,8})*$`)
+	// arbKeyRe matches message keys gen-l10n can turn into Dart getters.
+	arbKeyRe = regexp.MustCompile(`^[a-z][a-zA-Z0-9_]*$`)
+)
+
+// ARBValidator validates Flutter Application Resource Bundle (.arb) files.
+// Beyond JSON syntax it checks @@locale, message key naming, that every @key metadata entry
+// has a matching message, and that declared placeholders match those used in the message.
+//
+// Example:
+//
+//	validator := &ARBValidator{baseValidator{format: FormatARB}}
+//	result := validator.ValidateString(`{"@@locale": "en", "hello": "Hello {name}",
+//		"@hello": {"placeholders": {"name": {"type": "String"}}}}`)
+type ARBValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid ARB file.
+//
+// Example:
+//
+//	validator := &ARBValidator{baseValidator{format: FormatARB}}
+//	data, _ := os.ReadFile("lib/l10n/app_en.arb")
+//	result := validator.Validate(data)
+func (v *ARBValidator) Validate(data []byte) Result {
+	var arb map[string]interface{}
+	if err := json.Unmarshal(data, &arb); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid JSON: " + err.Error(),
+			Suggestion: suggestFix(FormatJSON, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	err := validateARB(arb)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *ARBValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateARB(arb map[string]interface{}) error {
+	for _, key := range sortedKeys(arb) {
+		value := arb[key]
+		switch {
+		case key == "@@locale":
+			locale, ok := value.(string)
+			if !ok || !arbLocaleRe.MatchString(locale) {
+				return fmt.Errorf("@@locale: invalid locale %v (expected e.g. \"en\" or \"en_US\")", value)
+			}
+		case strings.HasPrefix(key, "@@"):
+			// Other global attributes (@@last_modified, @@x-*) are free-form
+		case strings.HasPrefix(key, "@"):
+			if err := checkARBMetadata(arb, key, value); err != nil {
+				return err
+			}
+		default:
+			if !arbKeyRe.MatchString(key) {
+				return fmt.Errorf("%s: message keys must start with a lowercase letter and contain only letters, "+
+					"digits, and underscores", key)
+			}
+			message, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s: message must be a string", key)
+			}
+			if _, err := arbPlaceholders(message); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkARBMetadata validates an @key entry against its message.
+func checkARBMetadata(arb map[string]interface{}, key string, value interface{}) error {
+	name := strings.TrimPrefix(key, "@")
+	message, ok := arb[name].(string)
+	if !ok {
+		return fmt.Errorf("%s: metadata has no matching message %q", key, name)
+	}
+	meta, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: metadata must be an object", key)
+	}
+	if desc, ok := meta["description"]; ok {
+		if _, isString := desc.(string); !isString {
+			return fmt.Errorf("%s: description must be a string", key)
+		}
+	}
+
+	declared := make(map[string]bool)
+	if raw, ok := meta["placeholders"]; ok {
+		placeholders, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: placeholders must be an object", key)
+		}
+		for ph, def := range placeholders {
+			if _, ok := def.(map[string]interface{}); !ok {
+				return fmt.Errorf("%s: placeholder %q must be an object", key, ph)
+			}
+			declared[ph] = true
+		}
+	}
+
+	used, err := arbPlaceholders(message)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, ph := range sortedKeys(used) {
+		if !declared[ph] {
+			return fmt.Errorf("%s: placeholder {%s} is used in the message but not declared in %s", name, ph, key)
+		}
+	}
+	for _, ph := range sortedKeys(declared) {
+		if !used[ph] {
+			return fmt.Errorf("%s: placeholder %q is declared but not used in the message", key, ph)
+		}
+	}
+
+	return nil
+}
+
+// arbPlaceholders returns the argument names referenced by an ICU message,
+// including plural and select arguments and placeholders nested in their branches.
+func arbPlaceholders(message string) (map[string]bool, error) {
+	used := make(map[string]bool)
+	rest, err := parseICUMessage(message, used, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.New("unbalanced '}' in message")
+	}
+
+	return used, nil
+}
+
+// parseICUMessage consumes message text up to an unmatched '}' (when nested) or the end.
+func parseICUMessage(s string, used map[string]bool, nested bool) (string, error) {
+	for s != "" {
+		switch s[0] {
+		case '}':
+			// The caller decides whether a closing brace is expected here
+			return s, nil
+		case '{':
+			var err error
+			if s, err = parseICUArgument(s[1:], used); err != nil {
+				return "", err
+			}
+		default:
+			s = s[1:]
+		}
+	}
+	if nested {
+		return "", errors.New("unterminated '{' in message")
+	}
+
+	return "", nil
+}
+
+// parseICUArgument parses "name}" or "name, type[, style|branches]}" after the opening brace.
+func parseICUArgument(s string, used map[string]bool) (string, error) {
+	end := strings.IndexAny(s, ",}")
+	if end < 0 {
+		return "", errors.New("unterminated '{' in message")
+	}
+	name := strings.TrimSpace(s[:end])
+	if name == "" || strings.ContainsAny(name, "{ ") {
+		return "", fmt.Errorf("invalid placeholder name %q", name)
+	}
+	used[name] = true
+
+	if s[end] == '}' {
+		return s[end+1:], nil
+	}
+
+	s = s[end+1:]
+	end = strings.IndexAny(s, ",}")
+	if end < 0 {
+		return "", fmt.Errorf("unterminated argument {%s", name)
+	}
+	kind := strings.TrimSpace(s[:end])
+	if s[end] == '}' {
+		return s[end+1:], nil
+	}
+	s = s[end+1:]
+
+	switch kind {
+	case "plural", "select", "selectordinal":
+		return parseICUBranches(s, name, used)
+	}
+
+	// Simple styles such as {amount, number, currency}
+	end = strings.IndexByte(s, '}')
+	if end < 0 {
+		return "", fmt.Errorf("unterminated argument {%s", name)
+	}
+
+	return s[end+1:], nil
+}
+
+// parseICUBranches parses "selector {message} selector {message} ... }" for plural and select.
+func parseICUBranches(s, name string, used map[string]bool) (string, error) {
+	branches := 0
+	for {
+		s = strings.TrimLeft(s, " \t\n")
+		if s == "" {
+			return "", fmt.Errorf("unterminated argument {%s", name)
+		}
+		if s[0] == '}' {
+			if branches == 0 {
+				return "", fmt.Errorf("argument {%s} has no branches", name)
+			}
+
+			return s[1:], nil
+		}
+
+		open := strings.IndexByte(s, '{')
+		if open <= 0 || strings.TrimSpace(s[:open]) == "" {
+			return "", fmt.Errorf("argument {%s}: expected a selector followed by '{'", name)
+		}
+		rest, err := parseICUMessage(s[open+1:], used, true)
+		if err != nil {
+			return "", err
+		}
+		s = rest[1:]
+		branches++
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order, so reported errors are stable.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// isARB checks if JSON content appears to be a Flutter resource bundle.
+func isARB(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "{") && strings.Contains(trimmed, "\"@@locale\"")
+}
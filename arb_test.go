@@ -0,0 +1,59 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestARBValidator(t *testing.T) {
+	v := &ARBValidator{baseValidator{format: FormatARB}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"simple", `{"@@locale": "en", "title": "My App"}`, true, ""},
+		{"regional locale", `{"@@locale": "pt_BR", "title": "Meu App"}`, true, ""},
+		{"placeholder", `{"hello": "Hello {name}", "@hello": {"placeholders": {"name": {"type": "String"}}}}`, true, ""},
+		{
+			"plural with nested placeholder",
+			`{"items": "{count, plural, =0{No items} =1{One item} other{{count} items by {user}}}",
+			 "@items": {"description": "Item count", "placeholders": {"count": {"type": "int"}, "user": {}}}}`,
+			true, "",
+		},
+		{"select", `{"pronoun": "{gender, select, male{he} female{she} other{they}}"}`, true, ""},
+		{"message without metadata", `{"hello": "Hello {name}"}`, true, ""},
+		{"invalid json", `{"hello": }`, false, "invalid JSON"},
+		{"bad locale", `{"@@locale": "English"}`, false, "invalid locale"},
+		{"uppercase key", `{"Hello": "Hi"}`, false, "lowercase letter"},
+		{"non-string message", `{"count": 3}`, false, "must be a string"},
+		{"orphan metadata", `{"@hello": {"description": "x"}}`, false, "no matching message"},
+		{"undeclared placeholder", `{"hello": "Hi {name}", "@hello": {"placeholders": {}}}`, false, "not declared"},
+		{
+			"unused placeholder",
+			`{"hello": "Hi", "@hello": {"placeholders": {"name": {}}}}`,
+			false, "declared but not used",
+		},
+		{"unbalanced braces", `{"hello": "Hi {name"}`, false, "unterminated"},
+		{"extra closing brace", `{"hello": "Hi }"}`, false, "unbalanced"},
+		{"metadata not object", `{"hello": "Hi", "@hello": "note"}`, false, "must be an object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte(`{"@@locale": "de", "title": "Titel"}`)); got != FormatARB {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatARB)
+	}
+}
@@ -0,0 +1,241 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// azureTaskRe matches task references such as DotNetCoreCLI@2 or PublishBuildArtifacts@1.
+var azureTaskRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+@\d+$`)
+
+// azureStepKeys are the keys that identify a step's type; each step must have exactly one.
+var azureStepKeys = []string{
+	"script", "bash", "pwsh", "powershell", "task", "checkout", "download",
+	"downloadBuild", "getPackage", "publish", "reviewApp", "template",
+}
+
+// AzurePipelinesValidator validates Azure DevOps pipeline definitions (azure-pipelines.yml).
+// Beyond YAML syntax it checks stages/jobs/steps nesting, that each step has exactly one type,
+// that task references use the Name@version form, and that ${{ }} template expressions are balanced.
+//
+// Example:
+//
+//	validator := &AzurePipelinesValidator{baseValidator{format: FormatAzurePipelines}}
+//	data, _ := os.ReadFile("azure-pipelines.yml")
+//	result := validator.Validate(data)
+type AzurePipelinesValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid Azure Pipelines definition.
+//
+// Example:
+//
+//	validator := &AzurePipelinesValidator{baseValidator{format: FormatAzurePipelines}}
+//	result := validator.ValidateString("pool:\n  vmImage: ubuntu-latest\nsteps:\n  - task: DotNetCoreCLI@2\n")
+func (v *AzurePipelinesValidator) Validate(data []byte) Result {
+	if err := checkTemplateExpressions(string(data)); err != nil {
+		return Result{Valid: false, Format: v.format, Error: err.Error()}.locate(data, err)
+	}
+
+	var pipeline map[string]interface{}
+	if err := yaml.Unmarshal(data, &pipeline); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid YAML: " + err.Error(),
+			Suggestion: suggestFix(FormatYAML, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	err := validateAzurePipeline(pipeline)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *AzurePipelinesValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// checkTemplateExpressions verifies every "${{" is closed by "}}" on the same line, without nesting.
+// A stray "}}" outside an expression is left alone, since scripts often contain other templating.
+func checkTemplateExpressions(text string) error {
+	for i, line := range strings.Split(text, "\n") {
+		rest := line
+		for {
+			open := strings.Index(rest, "${{")
+			if open < 0 {
+				break
+			}
+			rest = rest[open+3:]
+			closing := strings.Index(rest, "}}")
+			if closing < 0 {
+				return fmt.Errorf("line %d: unterminated template expression '${{'", i+1)
+			}
+			if nested := strings.Index(rest[:closing], "${{"); nested >= 0 {
+				return fmt.Errorf("line %d: nested '${{' inside a template expression", i+1)
+			}
+			if strings.TrimSpace(rest[:closing]) == "" {
+				return fmt.Errorf("line %d: empty template expression", i+1)
+			}
+			rest = rest[closing+2:]
+		}
+	}
+
+	return nil
+}
+
+func validateAzurePipeline(pipeline map[string]interface{}) error {
+	var present []string
+	for _, key := range []string{"stages", "jobs", "steps", "extends"} {
+		if _, ok := pipeline[key]; ok {
+			present = append(present, key)
+		}
+	}
+	if len(present) > 1 {
+		return fmt.Errorf("pipeline may define only one of stages, jobs, steps, or extends (found %s)",
+			strings.Join(present, ", "))
+	}
+	if len(present) == 0 {
+		return errors.New("pipeline must define stages, jobs, steps, or extends")
+	}
+
+	switch present[0] {
+	case "stages":
+		return walkAzureList(pipeline["stages"], "stages", checkAzureStage)
+	case "jobs":
+		return walkAzureList(pipeline["jobs"], "jobs", checkAzureJob)
+	case "steps":
+		return walkAzureList(pipeline["steps"], "steps", checkAzureStep)
+	}
+
+	return nil
+}
+
+// walkAzureList applies check to each item, descending into ${{ if/each }} conditional blocks.
+func walkAzureList(raw interface{}, path string, check func(map[string]interface{}, string) error) error {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be a list", path)
+	}
+
+	for i, raw := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be a mapping", itemPath)
+		}
+		if key, value, ok := azureConditional(item); ok {
+			if err := walkAzureList(value, itemPath+"."+key, check); err != nil {
+				return err
+			}
+
+			continue
+		}
+		if err := check(item, itemPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkAzureStage(stage map[string]interface{}, path string) error {
+	if _, ok := stage["template"]; ok {
+		return nil
+	}
+	if name, _ := stage["stage"].(string); name == "" {
+		return fmt.Errorf("%s: missing required field stage", path)
+	}
+	jobs, ok := stage["jobs"]
+	if !ok {
+		return fmt.Errorf("%s: stage must define jobs", path)
+	}
+
+	return walkAzureList(jobs, path+".jobs", checkAzureJob)
+}
+
+func checkAzureJob(job map[string]interface{}, path string) error {
+	if _, ok := job["template"]; ok {
+		return nil
+	}
+	if _, ok := job["deployment"]; ok {
+		// Deployment jobs nest steps inside a strategy; only check the name here
+		if name, _ := job["deployment"].(string); name == "" {
+			return fmt.Errorf("%s: deployment name must be a non-empty string", path)
+		}
+
+		return nil
+	}
+	if name, _ := job["job"].(string); name == "" {
+		return fmt.Errorf("%s: missing required field job", path)
+	}
+	steps, ok := job["steps"]
+	if !ok {
+		return fmt.Errorf("%s: job must define steps", path)
+	}
+
+	return walkAzureList(steps, path+".steps", checkAzureStep)
+}
+
+func checkAzureStep(step map[string]interface{}, path string) error {
+	var kinds []string
+	for _, key := range azureStepKeys {
+		if _, ok := step[key]; ok {
+			kinds = append(kinds, key)
+		}
+	}
+	if len(kinds) == 0 {
+		return fmt.Errorf("%s: step must have one of %s", path, strings.Join(azureStepKeys, ", "))
+	}
+	if len(kinds) > 1 {
+		return fmt.Errorf("%s: step has multiple types (%s)", path, strings.Join(kinds, ", "))
+	}
+
+	if kinds[0] == "task" {
+		task, _ := step["task"].(string)
+		if !azureTaskRe.MatchString(task) && !strings.HasPrefix(task, "${{") {
+			return fmt.Errorf("%s: task %q must use the Name@version form, e.g. DotNetCoreCLI@2", path, task)
+		}
+	}
+
+	return nil
+}
+
+// azureConditional reports whether item is a single "${{ if ... }}:" or "${{ each ... }}:" insertion block.
+func azureConditional(item map[string]interface{}) (string, interface{}, bool) {
+	if len(item) != 1 {
+		return "", nil, false
+	}
+	for key, value := range item {
+		if strings.HasPrefix(key, "${{") {
+			return key, value, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// isAzurePipelines checks for a top-level pool together with Azure-specific keys.
+func isAzurePipelines(trimmed string, lines []string) bool {
+	hasPool := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "pool:") {
+			hasPool = true
+
+			break
+		}
+	}
+
+	return hasPool && (strings.Contains(trimmed, "vmImage:") || strings.Contains(trimmed, "- task: ") ||
+		strings.HasPrefix(trimmed, "trigger:"))
+}
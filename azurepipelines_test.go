@@ -0,0 +1,74 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const azureStages = `trigger:
+  - main
+pool:
+  vmImage: ubuntu-latest
+stages:
+  - stage: Build
+    jobs:
+      - job: Compile
+        steps:
+          - checkout: self
+          - task: DotNetCoreCLI@2
+            inputs:
+              command: build
+          - script: echo ${{ parameters.configuration }}
+  - template: stages/deploy.yml
+`
+
+func TestAzurePipelinesValidator(t *testing.T) {
+	v := &AzurePipelinesValidator{baseValidator{format: FormatAzurePipelines}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"stages", azureStages, true, ""},
+		{"steps only", "pool:\n  vmImage: ubuntu-latest\nsteps:\n  - bash: make\n  - task: Npm@1\n", true, ""},
+		{"jobs with deployment", "jobs:\n  - job: A\n    steps:\n      - pwsh: ./build.ps1\n" +
+			"  - deployment: Prod\n    environment: prod\n    strategy:\n      runOnce: {}\n", true, ""},
+		{"conditional insertion", "steps:\n  - ${{ if eq(parameters.test, true) }}:\n    - script: make test\n", true, ""},
+		{"extends", "extends:\n  template: base.yml\n", true, ""},
+		{"mixed levels", "jobs: []\nsteps: []\n", false, "only one of"},
+		{"no work", "trigger:\n  - main\n", false, "must define stages"},
+		{"stage without jobs", "stages:\n  - stage: Build\n", false, "stages[0]: stage must define jobs"},
+		{"job without steps", "jobs:\n  - job: A\n", false, "jobs[0]: job must define steps"},
+		{"step without type", "steps:\n  - displayName: nothing\n", false, "steps[0]: step must have one of"},
+		{"step with two types", "steps:\n  - script: a\n    bash: b\n", false, "multiple types"},
+		{"task without version", "steps:\n  - task: DotNetCoreCLI\n", false, "Name@version"},
+		{"bad conditional body", "steps:\n  - ${{ if true }}:\n    - task: Foo@x\n", false, "Name@version"},
+		{"unterminated expression", "steps:\n  - script: echo ${{ parameters.x\n", false, "line 2: unterminated"},
+		{"nested expression", "steps:\n  - script: ${{ a ${{ b }} }}\n", false, "nested"},
+		{"empty expression", "steps:\n  - script: ${{ }}\n", false, "empty template expression"},
+		{"invalid yaml", "steps:\n  - script: [\n", false, "invalid YAML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte(azureStages)); got != FormatAzurePipelines {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatAzurePipelines)
+	}
+	for _, name := range []string{"azure-pipelines.yml", "ci/azure-pipelines-pr.yaml", ".azure-pipelines/release.yml"} {
+		if got := DetectFormatFromFilename(name); got != FormatAzurePipelines {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want %v", name, got, FormatAzurePipelines)
+		}
+	}
+}
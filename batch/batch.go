@@ -0,0 +1,168 @@
+// Package batch validates many inputs concurrently with a pool of worker goroutines.
+//
+// Validating tens of thousands of configuration files one at a time leaves most cores
+// idle; ValidateAll fans the work out and hands the results back in input order:
+//
+//	inputs := []batch.Input{
+//		{Name: "deploy/app.yaml", Data: app},
+//		{Name: "deploy/db.toml", Data: db},
+//	}
+//	results := batch.ValidateAll(ctx, inputs, batch.WithWorkers(8))
+//	for _, r := range results {
+//		if !r.Valid {
+//			fmt.Printf("%s: %s\n", r.FileName, r.Error)
+//		}
+//	}
+package batch
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// Input is one document to validate.
+type Input struct {
+	// Name identifies the input in its Result's FileName and, when Format is empty,
+	// picks the format by extension (see serdeval.DetectFormatFromFilename)
+	Name string
+	// Data is the content to validate
+	Data []byte
+	// Format forces a format; empty or serdeval.FormatAuto detects it from Name, then Data
+	Format serdeval.Format
+}
+
+// Option configures ValidateAll.
+type Option func(*config)
+
+// config holds the settings applied by Option functions.
+type config struct {
+	workers       int
+	validatorOpts []serdeval.Option
+}
+
+// WithWorkers sets how many inputs are validated at once. The default, and any value of
+// 0 or less, is runtime.GOMAXPROCS(0).
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// WithValidatorOptions passes opts to serdeval.NewValidator and serdeval.ValidateAuto for
+// every input.
+func WithValidatorOptions(opts ...serdeval.Option) Option {
+	return func(c *config) {
+		c.validatorOpts = append(c.validatorOpts, opts...)
+	}
+}
+
+// ValidateAll validates inputs concurrently and returns one Result per input, in the same
+// order, each with FileName set to the input's Name. Validators are created once per
+// format and shared by the workers.
+//
+// When ctx is done, inputs still waiting are not validated and those in progress are
+// abandoned where the validator allows it; their Results are marked Skipped with a
+// "skipped: canceled" error, as from serdeval.ValidateContext.
+func ValidateAll(ctx context.Context, inputs []Input, opts ...Option) []serdeval.Result {
+	c := config{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+	workers := c.workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(inputs))
+
+	p := &pool{opts: c.validatorOpts, validators: map[serdeval.Format]serdeval.Validator{}}
+	results := make([]serdeval.Result, len(inputs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = p.validate(ctx, inputs[i])
+			}
+		}()
+	}
+	for i := range inputs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return results
+}
+
+// pool holds the validators shared by the workers of one ValidateAll call.
+type pool struct {
+	opts []serdeval.Option
+
+	mu         sync.Mutex
+	validators map[serdeval.Format]serdeval.Validator
+}
+
+// validate validates one input with ctx.
+func (p *pool) validate(ctx context.Context, in Input) serdeval.Result {
+	format := in.Format
+	if format == "" || format == serdeval.FormatAuto {
+		format = serdeval.DetectFormatFromFilename(in.Name)
+	}
+
+	var result serdeval.Result
+	switch v, err := p.validator(format); {
+	case ctx.Err() != nil:
+		result = canceledResult(format, ctx.Err())
+	case err != nil:
+		result = serdeval.Result{Valid: false, Format: format, Error: err.Error()}
+	case v == nil:
+		result = serdeval.ValidateAuto(in.Data, p.opts...)
+	default:
+		result = serdeval.ValidateContext(ctx, v, in.Data)
+	}
+	result.FileName = in.Name
+
+	return result
+}
+
+// validator returns the shared validator for format, or nil for FormatUnknown, which is
+// detected from content.
+func (p *pool) validator(format serdeval.Format) (serdeval.Validator, error) {
+	if format == serdeval.FormatUnknown {
+		return nil, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if v, ok := p.validators[format]; ok {
+		return v, nil
+	}
+	v, err := serdeval.NewValidator(format, p.opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.validators[format] = v
+
+	return v, nil
+}
+
+// canceledResult is the Result for an input not validated because ctx ended.
+func canceledResult(format serdeval.Format, err error) serdeval.Result {
+	msg := fmt.Sprintf("skipped: canceled (%v)", err)
+
+	return serdeval.Result{
+		Valid:   false,
+		Skipped: true,
+		Format:  format,
+		Error:   msg,
+		Err:     &serdeval.ValidationError{Code: serdeval.ErrCodeCanceled, Message: msg},
+	}
+}
@@ -0,0 +1,85 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestValidateAll(t *testing.T) {
+	inputs := []Input{
+		{Name: "app.json", Data: []byte(`{"name": "app"}`)},
+		{Name: "broken.yaml", Data: []byte("name: [app")},
+		{Name: "notes", Data: []byte("a,b\n1,2\n")},
+		{Name: "forced.txt", Data: []byte(`host = "db"`), Format: serdeval.FormatTOML},
+		{Name: "bad.txt", Data: []byte("x"), Format: "nope"},
+	}
+	want := []struct {
+		format serdeval.Format
+		valid  bool
+	}{
+		{serdeval.FormatJSON, true},
+		{serdeval.FormatYAML, false},
+		{serdeval.FormatCSV, true},
+		{serdeval.FormatTOML, true},
+		{"nope", false},
+	}
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			results := ValidateAll(context.Background(), inputs, WithWorkers(workers))
+			if len(results) != len(inputs) {
+				t.Fatalf("got %d results, want %d", len(results), len(inputs))
+			}
+			for i, r := range results {
+				if r.FileName != inputs[i].Name || r.Format != want[i].format || r.Valid != want[i].valid {
+					t.Errorf("result %d = %s %s %v, want %s %s %v (error: %s)", i, r.FileName, r.Format, r.Valid,
+						inputs[i].Name, want[i].format, want[i].valid, r.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAllOrder(t *testing.T) {
+	inputs := make([]Input, 500)
+	for i := range inputs {
+		inputs[i] = Input{Name: fmt.Sprintf("%d.json", i), Data: []byte(fmt.Sprintf(`{"n": %d}`, i))}
+	}
+	for i, r := range ValidateAll(context.Background(), inputs, WithWorkers(8)) {
+		if r.FileName != inputs[i].Name || !r.Valid {
+			t.Fatalf("result %d = %s %v, want %s valid", i, r.FileName, r.Valid, inputs[i].Name)
+		}
+	}
+}
+
+func TestValidateAllOptions(t *testing.T) {
+	inputs := []Input{{Name: "dup.json", Data: []byte(`{"a": 1, "a": 2}`)}}
+	if r := ValidateAll(context.Background(), inputs)[0]; !r.Valid {
+		t.Errorf("without options: %s", r.Error)
+	}
+	r := ValidateAll(context.Background(), inputs, WithValidatorOptions(serdeval.WithStrict()))[0]
+	if r.Valid || !strings.Contains(r.Error, "duplicate key") {
+		t.Errorf("with WithStrict: valid %v, error %q, want a duplicate key error", r.Valid, r.Error)
+	}
+}
+
+func TestValidateAllCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := ValidateAll(ctx, []Input{{Name: "a.json", Data: []byte("{}")}, {Name: "b", Data: []byte("{}")}})
+	for _, r := range results {
+		if !r.Skipped || !strings.Contains(r.Error, "skipped: canceled") {
+			t.Errorf("%s: skipped %v, error %q, want a canceled result", r.FileName, r.Skipped, r.Error)
+		}
+	}
+}
+
+func TestValidateAllEmpty(t *testing.T) {
+	if results := ValidateAll(context.Background(), nil); len(results) != 0 {
+		t.Errorf("got %d results, want none", len(results))
+	}
+}
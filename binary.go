@@ -0,0 +1,88 @@
+package serdeval
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// binarySniffLen is how much of the input IsBinary looks at, the same amount git checks
+const binarySniffLen = 8000
+
+// binarySignatures are the leading bytes of common binary files. Signatures that are also
+// plausible text, such as "MZ", are left to the NUL byte check.
+var binarySignatures = []struct {
+	magic string
+	kind  string
+}{
+	{"\x89PNG\r\n\x1a\n", "PNG image"},
+	{"\xff\xd8\xff", "JPEG image"},
+	{"GIF87a", "GIF image"},
+	{"GIF89a", "GIF image"},
+	{"%PDF-", "PDF document"},
+	{"PK\x03\x04", "ZIP archive"},
+	{"\x1f\x8b", "gzip archive"},
+	{"\xfd7zXZ\x00", "xz archive"},
+	{"7z\xbc\xaf\x27\x1c", "7z archive"},
+	{"\x28\xb5\x2f\xfd", "zstd archive"},
+	{"\x7fELF", "ELF executable"},
+	{"\xcf\xfa\xed\xfe", "Mach-O executable"},
+	{"\xca\xfe\xba\xbe", "Mach-O or Java class file"},
+	{"\x00asm", "WebAssembly module"},
+	{"SQLite format 3\x00", "SQLite database"},
+}
+
+// IsBinary reports whether data looks like a binary file, such as an image, archive, or
+// executable, rather than text. It checks for well-known file signatures, then looks for
+// NUL bytes or a high share of control characters in the first 8000 bytes. UTF-16 text,
+// which is full of NUL bytes, is not binary.
+//
+// ValidateAuto and DetectFormat use it to avoid guessing a text format for binary input;
+// binary Ion, which has its own format, is the exception.
+//
+// Example:
+//
+//	if IsBinary(data) {
+//		return fmt.Errorf("%s is not a text file", name)
+//	}
+func IsBinary(data []byte) bool {
+	return binaryKind(data) != ""
+}
+
+// binaryKind names the kind of binary file data is, or returns "" for text.
+func binaryKind(data []byte) string {
+	for _, sig := range binarySignatures {
+		if bytes.HasPrefix(data, []byte(sig.magic)) {
+			return sig.kind
+		}
+	}
+	if enc := DetectEncoding(data); enc == EncodingUTF16LE || enc == EncodingUTF16BE {
+		return ""
+	}
+
+	sniff := data[:min(len(data), binarySniffLen)]
+	if bytes.IndexByte(sniff, 0) >= 0 {
+		return "binary data"
+	}
+	control := 0
+	for _, b := range sniff {
+		if (b < 0x20 && !isTextControl(b)) || b == 0x7f {
+			control++
+		}
+	}
+	if control*10 > len(sniff) {
+		return "binary data"
+	}
+
+	return ""
+}
+
+// isTextControl reports whether b is a control character found in ordinary text files:
+// whitespace, form feeds, and the escape that starts terminal color codes.
+func isTextControl(b byte) bool {
+	return b == '\t' || b == '\n' || b == '\r' || b == '\f' || b == '\v' || b == 0x1b
+}
+
+// binaryResult is the Result for input skipped because it is binary.
+func binaryResult(kind string) Result {
+	return skippedResult(FormatUnknown, ErrCodeBinary, fmt.Sprintf("skipped: binary content (%s)", kind))
+}
@@ -0,0 +1,66 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  bool
+	}{
+		{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), true},
+		{"JPEG", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), true},
+		{"gzip", []byte("\x1f\x8b\x08\x00"), true},
+		{"ZIP", []byte("PK\x03\x04\x14\x00"), true},
+		{"ELF", []byte("\x7fELF\x02\x01\x01"), true},
+		{"PDF", []byte("%PDF-1.7\n"), true},
+		{"NUL bytes", []byte("MZ\x90\x00\x03\x00"), true},
+		{"control characters", []byte("\x01\x02\x03\x04abcdef"), true},
+		{"JSON", []byte(`{"name": "app"}`), false},
+		{"UTF-16", utf16Bytes(`{"name": "app"}`, false), false},
+		{"Latin-1", []byte("name = caf\xe9\n"), false},
+		{"ANSI colors", []byte("\x1b[31merror\x1b[0m: failed\n"), false},
+		{"NUL after the sniffed prefix", []byte(strings.Repeat("a", binarySniffLen) + "\x00"), false},
+		{"empty", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBinary(tt.input); got != tt.want {
+				t.Errorf("IsBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAutoBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		format  Format
+		skipped bool
+		errPart string
+	}{
+		{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), FormatUnknown, true, "binary content (PNG image)"},
+		// Without the guard, the text in this executable made it look like YAML
+		{"executable", []byte("MZ\x90\x00\x03\x00\x00\x00key: value"), FormatUnknown, true, "binary content (binary data)"},
+		{"binary Ion", append(append([]byte{}, ionBVM...), 0x20), FormatIon, false, ""},
+		{"text", []byte("name: app"), FormatYAML, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateAuto(tt.input)
+			if result.Format != tt.format || result.Skipped != tt.skipped {
+				t.Errorf("ValidateAuto() = %v, skipped %v, want %v, skipped %v (error: %s)",
+					result.Format, result.Skipped, tt.format, tt.skipped, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
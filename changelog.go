@@ -0,0 +1,228 @@
+package serdeval
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// changelogVersionRe matches "## [1.2.3] - 2024-01-31" with an optional "[YANKED]" marker.
+	changelogVersionRe = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?\s+-\s+(\S+)(\s+\[YANKED\])?\s*$`)
+	changelogSemverRe  = regexp.MustCompile(
+		`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+)
+
+// changelogChangeTypes are the subsection names Keep a Changelog allows under each release.
+var changelogChangeTypes = map[string]bool{
+	"Added": true, "Changed": true, "Deprecated": true, "Removed": true, "Fixed": true, "Security": true,
+}
+
+// ChangelogValidator validates CHANGELOG.md files against the Keep a Changelog convention.
+// It requires an [Unreleased] section ahead of the releases, version headings of the form
+// "## [1.2.3] - YYYY-MM-DD", change-type subsections (Added, Changed, Deprecated, Removed,
+// Fixed, Security), and versions listed newest first.
+//
+// Example:
+//
+//	validator := &ChangelogValidator{baseValidator{format: FormatChangelog}}
+//	data, _ := os.ReadFile("CHANGELOG.md")
+//	result := validator.Validate(data)
+type ChangelogValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice follows the Keep a Changelog structure.
+//
+// Example:
+//
+//	validator := &ChangelogValidator{baseValidator{format: FormatChangelog}}
+//	result := validator.ValidateString("# Changelog\n\n## [Unreleased]\n\n" +
+//		"## [1.0.0] - 2024-01-31\n### Added\n- First release\n")
+func (v *ChangelogValidator) Validate(data []byte) Result {
+	err := validateChangelog(string(data))
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *ChangelogValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// changelogState tracks the release section being read.
+type changelogState struct {
+	sawTitle      bool
+	sawUnreleased bool
+	inRelease     bool
+	prevVersion   string
+	prevLine      int
+	subsections   map[string]bool
+}
+
+func validateChangelog(text string) error {
+	state := &changelogState{}
+	inFence := false
+	for i, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+
+			continue
+		}
+		if inFence || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := state.heading(strings.TrimRight(line, " \t\r"), i+1); err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+
+	if !state.sawTitle {
+		return errors.New("missing top-level heading (e.g. \"# Changelog\")")
+	}
+	if !state.sawUnreleased {
+		return errors.New("missing \"## [Unreleased]\" section")
+	}
+
+	return nil
+}
+
+func (s *changelogState) heading(line string, lineNum int) error {
+	switch {
+	case strings.HasPrefix(line, "# "):
+		if s.sawTitle {
+			return errors.New("only one top-level heading is allowed")
+		}
+		s.sawTitle = true
+
+		return nil
+	case strings.HasPrefix(line, "## "):
+		if !s.sawTitle {
+			return errors.New("release heading before the top-level heading")
+		}
+		s.inRelease = true
+		s.subsections = make(map[string]bool)
+
+		return s.release(line, lineNum)
+	case strings.HasPrefix(line, "### "):
+		name := strings.TrimSpace(line[4:])
+		if !s.inRelease {
+			return fmt.Errorf("%q subsection outside a release", name)
+		}
+		if !changelogChangeTypes[name] {
+			return fmt.Errorf("unknown change type %q (expected Added, Changed, Deprecated, Removed, Fixed, "+
+				"or Security)", name)
+		}
+		if s.subsections[name] {
+			return fmt.Errorf("duplicate %q subsection", name)
+		}
+		s.subsections[name] = true
+	}
+
+	return nil
+}
+
+func (s *changelogState) release(line string, lineNum int) error {
+	title := strings.TrimSpace(line[3:])
+	if strings.EqualFold(strings.Trim(title, "[]"), "unreleased") {
+		if s.sawUnreleased {
+			return errors.New("duplicate Unreleased section")
+		}
+		if s.prevVersion != "" {
+			return errors.New("the Unreleased section must come before all releases")
+		}
+		s.sawUnreleased = true
+
+		return nil
+	}
+
+	m := changelogVersionRe.FindStringSubmatch(line)
+	if m == nil {
+		return fmt.Errorf("release heading %q must look like \"## [1.2.3] - YYYY-MM-DD\"", title)
+	}
+	version, date := m[1], m[2]
+	if !changelogSemverRe.MatchString(version) {
+		return fmt.Errorf("invalid version %q", version)
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid release date %q (expected YYYY-MM-DD)", date)
+	}
+	if !s.sawUnreleased {
+		return errors.New("missing \"## [Unreleased]\" section before the first release")
+	}
+	if s.prevVersion != "" {
+		switch order := compareSemver(version, s.prevVersion); {
+		case order == 0:
+			return fmt.Errorf("duplicate version %s (also on line %d)", version, s.prevLine)
+		case order > 0:
+			return fmt.Errorf("version %s is newer than %s on line %d; list releases newest first",
+				version, s.prevVersion, s.prevLine)
+		}
+	}
+	s.prevVersion, s.prevLine = version, lineNum
+
+	return nil
+}
+
+// compareSemver compares two versions matched by changelogSemverRe using SemVer precedence.
+func compareSemver(a, b string) int {
+	ma := changelogSemverRe.FindStringSubmatch(a)
+	mb := changelogSemverRe.FindStringSubmatch(b)
+	for i := 1; i <= 3; i++ {
+		x, _ := strconv.Atoi(ma[i])
+		y, _ := strconv.Atoi(mb[i])
+		if x != y {
+			return cmp.Compare(x, y)
+		}
+	}
+
+	// A release has higher precedence than any of its pre-releases
+	switch {
+	case ma[4] == mb[4]:
+		return 0
+	case ma[4] == "":
+		return 1
+	case mb[4] == "":
+		return -1
+	}
+
+	pa, pb := strings.Split(ma[4], "."), strings.Split(mb[4], ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] == pb[i] {
+			continue
+		}
+		x, errX := strconv.Atoi(pa[i])
+		y, errY := strconv.Atoi(pb[i])
+		switch {
+		case errX == nil && errY == nil:
+			return cmp.Compare(x, y)
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		default:
+			return strings.Compare(pa[i], pb[i])
+		}
+	}
+
+	return cmp.Compare(len(pa), len(pb))
+}
+
+// isChangelog checks for a Changelog title together with an Unreleased section or a
+// reference to keepachangelog.com.
+func isChangelog(trimmed string) bool {
+	if !strings.HasPrefix(trimmed, "# Changelog") && !strings.HasPrefix(trimmed, "# Change Log") {
+		return false
+	}
+
+	return strings.Contains(trimmed, "## [Unreleased]") || strings.Contains(trimmed, "keepachangelog.com")
+}
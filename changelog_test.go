@@ -0,0 +1,94 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const changelogSample = `# Changelog
+
+All notable changes to this project will be documented in this file.
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+
+## [Unreleased]
+### Added
+- Streaming mode
+
+## [1.1.0] - 2024-03-05
+### Fixed
+- Crash on empty input
+
+` + "```sh\n## not a heading\n```\n" + `
+## [1.1.0-rc.1] - 2024-02-20 [YANKED]
+### Changed
+- Renamed flags
+
+## 1.0.0 - 2024-01-31
+### Added
+- First release
+
+[unreleased]: https://example.com/compare/v1.1.0...HEAD
+`
+
+func TestChangelogValidator(t *testing.T) {
+	v := &ChangelogValidator{baseValidator{format: FormatChangelog}}
+
+	head := "# Changelog\n\n## [Unreleased]\n\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"sample", changelogSample, true, ""},
+		{"minimal", head, true, ""},
+		{"missing title", "## [Unreleased]\n", false, "release heading before the top-level heading"},
+		{"missing unreleased", "# Changelog\n\n## [1.0.0] - 2024-01-31\n", false, "Unreleased"},
+		{"unreleased after release", "# Changelog\n## [Unreleased]\n## [1.0.0] - 2024-01-31\n## [Unreleased]\n",
+			false, "duplicate Unreleased"},
+		{"missing date", head + "## [1.0.0]\n", false, "line 5: release heading \"[1.0.0]\""},
+		{"bad date", head + "## [1.0.0] - 2024-13-01\n", false, "invalid release date"},
+		{"bad version", head + "## [1.0] - 2024-01-31\n", false, "invalid version \"1.0\""},
+		{"unknown change type", head + "## [1.0.0] - 2024-01-31\n### Improved\n", false, "unknown change type"},
+		{"duplicate change type", head + "## [1.0.0] - 2024-01-31\n### Added\n- a\n### Added\n",
+			false, "duplicate \"Added\""},
+		{"subsection outside release", "# Changelog\n### Added\n## [Unreleased]\n", false, "outside a release"},
+		{"ascending order", head + "## [1.0.0] - 2024-01-31\n## [1.1.0] - 2024-03-05\n", false, "newest first"},
+		{"prerelease after release", head + "## [1.0.0-beta] - 2024-01-01\n## [1.0.0] - 2024-01-31\n",
+			false, "newest first"},
+		{"duplicate version", head + "## [1.0.0] - 2024-01-31\n## [1.0.0] - 2024-01-31\n", false, "duplicate version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte(changelogSample)); got != FormatChangelog {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatChangelog)
+	}
+	if got := DetectFormatFromFilename("docs/CHANGELOG.md"); got != FormatChangelog {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatChangelog)
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "v2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		if got := compareSemver(ordered[i-1], ordered[i]); got != -1 {
+			t.Errorf("compareSemver(%q, %q) = %d, want -1", ordered[i-1], ordered[i], got)
+		}
+		if got := compareSemver(ordered[i], ordered[i-1]); got != 1 {
+			t.Errorf("compareSemver(%q, %q) = %d, want 1", ordered[i], ordered[i-1], got)
+		}
+	}
+}
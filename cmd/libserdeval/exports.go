@@ -0,0 +1,83 @@
+//go:build cgo
+
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"unsafe"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+//export serdeval_validate
+func serdeval_validate(data *C.char, length C.int, format *C.char) *C.char {
+	input, err := goBytes(data, length)
+	if err != nil {
+		return C.CString(resultJSON(serdeval.Result{Valid: false, Format: serdeval.FormatUnknown, Error: err.Error()}))
+	}
+
+	return C.CString(validateJSON(input, C.GoString(format)))
+}
+
+//export serdeval_detect
+func serdeval_detect(data *C.char, length C.int) *C.char {
+	input, err := goBytes(data, length)
+	if err != nil {
+		return C.CString(string(serdeval.FormatUnknown))
+	}
+
+	return C.CString(string(serdeval.DetectFormat(input)))
+}
+
+// goBytes copies the length bytes at data, refusing arguments C.GoBytes would crash on:
+// a negative length, or a NULL pointer with bytes to read.
+func goBytes(data *C.char, length C.int) ([]byte, error) {
+	switch {
+	case length < 0:
+		return nil, errors.New("invalid input: negative length")
+	case data == nil && length > 0:
+		return nil, errors.New("invalid input: NULL data with non-zero length")
+	case length == 0:
+		return []byte{}, nil
+	}
+
+	return C.GoBytes(unsafe.Pointer(data), length), nil
+}
+
+//export serdeval_free
+func serdeval_free(ptr *C.char) {
+	C.free(unsafe.Pointer(ptr))
+}
+
+// validateJSON validates data in the given format and returns the Result as JSON.
+func validateJSON(data []byte, format string) string {
+	var result serdeval.Result
+	if format == "" || serdeval.Format(format) == serdeval.FormatAuto {
+		result = serdeval.ValidateAuto(data)
+	} else {
+		v, err := serdeval.NewValidator(serdeval.Format(format))
+		if err != nil {
+			result = serdeval.Result{Valid: false, Format: serdeval.Format(format), Error: err.Error()}
+		} else {
+			result = v.Validate(data)
+		}
+	}
+
+	return resultJSON(result)
+}
+
+// resultJSON encodes result as JSON.
+func resultJSON(result serdeval.Result) string {
+	out, err := json.Marshal(result)
+	if err != nil {
+		return `{"valid":false,"format":"unknown","error":"internal error encoding result"}`
+	}
+
+	return string(out)
+}
@@ -0,0 +1,22 @@
+// Command libserdeval builds serdeval as a C shared library for FFI consumers.
+//
+// Build with:
+//
+//	go build -buildmode=c-shared -o libserdeval.so ./cmd/libserdeval
+//
+// This produces libserdeval.so (or .dylib/.dll) and libserdeval.h exporting:
+//
+//	char* serdeval_validate(const char* data, int len, const char* format);
+//	char* serdeval_detect(const char* data, int len);
+//	void  serdeval_free(char* ptr);
+//
+// serdeval_validate returns a JSON-encoded Result, e.g. {"valid":true,"format":"json"}.
+// Pass "auto" or an empty format to detect the format from the content.
+// serdeval_detect returns the detected format name, or "unknown".
+// A negative len, or a NULL data with a positive len, is rejected: serdeval_validate
+// returns an invalid Result with the reason and serdeval_detect returns "unknown".
+// Every returned string is allocated by the library and must be released with serdeval_free.
+package main
+
+// main is required by -buildmode=c-shared but never runs.
+func main() {}
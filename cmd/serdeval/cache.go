@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// resultCache stores validation results on disk keyed by a hash of the
+// file content, the validator build, and the options that affect the result.
+type resultCache struct {
+	dir string
+}
+
+// newResultCache creates the cache directory if needed.
+func newResultCache(dir string) (*resultCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &resultCache{dir: dir}, nil
+}
+
+// key derives the cache key for data validated under the given filename and options.
+// The filename only contributes the format it implies, so renames within the same
+// extension still hit the cache.
+func (c *resultCache) key(data []byte, filename string, opts validateOptions) string {
+	h := sha256.New()
+	format := string(serdeval.DetectFormatFromFilename(filename))
+	for _, part := range []string{cacheBuildID(), opts.fingerprint(), format} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(data)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheBuildID identifies the running binary for cache keys. Tagged releases use
+// Version; "dev" builds use the VCS revision, or a hash of the executable when the
+// tree was modified or no revision was stamped, so a rebuilt binary never serves
+// results cached by an older one.
+var cacheBuildID = sync.OnceValue(func() string {
+	if Version != "dev" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision string
+		modified := false
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if revision != "" && !modified {
+			return Version + "+" + revision
+		}
+	}
+	if sum, err := executableHash(); err == nil {
+		return Version + "+" + sum
+	}
+
+	return Version
+})
+
+// executableHash returns the SHA-256 of the running executable.
+func executableHash() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path) // #nosec G304 - hashing our own executable
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// path returns the on-disk location for key, sharded by its first byte.
+func (c *resultCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the cached result for key, if present and readable.
+func (c *resultCache) get(key string) (ValidationResult, bool) {
+	var result ValidationResult
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return result, false
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, false
+	}
+
+	return result, true
+}
+
+// put stores result under key. Failures are ignored: the cache is an optimization only.
+func (c *resultCache) put(key string, result ValidationResult) {
+	result.FileName = ""
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	path := c.path(key)
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return
+	}
+
+	// Write to a temp file and rename so concurrent runs never see partial entries
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+func TestResultCache(t *testing.T) {
+	cache, err := newResultCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := validateOptions{format: autoFormat, cache: cache}
+	data := []byte(`{"a": 1}`)
+
+	// Seed the entry for base with a marker result, so a hit is told apart from a fresh validation
+	if first := validateCached(data, "a.json", base); !first.Valid {
+		t.Fatalf("validateCached() = %+v, want valid", first)
+	}
+	cache.put(cache.key(data, "a.json", base), ValidationResult{Format: "json", Error: "cached"})
+
+	tests := []struct {
+		name     string
+		data     string
+		filename string
+		opts     func(o *validateOptions)
+		wantHit  bool
+	}{
+		{name: "unchanged", data: string(data), filename: "a.json", wantHit: true},
+		{name: "renamed", data: string(data), filename: "b.json", wantHit: true},
+		{name: "content changed", data: `{"a": 2}`, filename: "a.json"},
+		{name: "strict", data: string(data), filename: "a.json",
+			opts: func(o *validateOptions) { o.strict = true }},
+		{name: "max errors", data: string(data), filename: "a.json",
+			opts: func(o *validateOptions) { o.maxErrors = 5 }},
+		{name: "format flag", data: string(data), filename: "a.json",
+			opts: func(o *validateOptions) { o.format = "json" }},
+		{name: "format from name", data: string(data), filename: "a.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := base
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+			got := validateCached([]byte(tt.data), tt.filename, opts)
+			if hit := got.Error == "cached"; hit != tt.wantHit {
+				t.Errorf("validateCached(%q) hit = %t, want %t (result %+v)", tt.filename, hit, tt.wantHit, got)
+			}
+			if got.FileName != tt.filename {
+				t.Errorf("FileName = %q, want %q", got.FileName, tt.filename)
+			}
+		})
+	}
+}
+
+func TestCacheBuildID(t *testing.T) {
+	if Version != "dev" {
+		t.Skip("release builds key the cache by Version")
+	}
+	if got := cacheBuildID(); got == Version {
+		t.Errorf("cacheBuildID() = %q, want the dev version qualified by a revision or executable hash", got)
+	}
+}
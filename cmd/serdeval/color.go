@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// --color values
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// Marks printed before results and changes. setColorMode swaps them for plain ASCII when
+// stdout is not a terminal, so piped logs stay readable.
+var (
+	markValid   = "✓"
+	markInvalid = "✗"
+	markWarning = "⚠"
+	markArrow   = "→"
+)
+
+// Emoji starting the banners of the web server and daemon, dropped with the marks.
+var (
+	bannerServer  = "🌐 "
+	bannerPrivacy = "🔒 "
+	bannerWatch   = "👀 "
+)
+
+// setColorMode applies --color. In auto mode colors are off when NO_COLOR is set, TERM is
+// dumb, or stdout is not a terminal; always forces colors and marks and never turns colors
+// off. Marks become ASCII whenever stdout is not a terminal, unless colors are forced.
+func setColorMode(mode string) error {
+	terminal := isTerminal(os.Stdout)
+	switch mode {
+	case colorAuto:
+		color.NoColor = !terminal || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+	case colorAlways:
+		color.NoColor = false
+		terminal = true
+	case colorNever:
+		color.NoColor = true
+	default:
+		return fmt.Errorf("invalid --color %q: must be auto, always, or never", mode)
+	}
+
+	if !terminal {
+		markValid, markInvalid, markWarning, markArrow = "OK", "FAIL", "WARN", "->"
+		bannerServer, bannerPrivacy, bannerWatch = "", "", ""
+	}
+
+	return nil
+}
+
+// isTerminal reports whether f is a character device, as a terminal is.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// completeColorModes completes --color with its values.
+func completeColorModes(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return []string{colorAuto, colorAlways, colorNever}, cobra.ShellCompDirectiveNoFileComp
+}
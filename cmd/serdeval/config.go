@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/lint"
+)
+
+// configNames are the project config files looked for in the working directory and its
+// parents, in order of preference.
+var configNames = []string{".serdeval.yaml", ".serdeval.yml"}
+
+// projectConfig is a .serdeval.yaml file, which sets defaults for a repository so CI jobs
+// and contributors run the same checks without repeating flags:
+//
+//	validate:
+//	  format: auto
+//	  strict: true
+//	  max-size: 10MB
+//	ignore:
+//	  - vendor/**
+//	  - '*.generated.yaml'
+//	overrides:
+//	  - files: '*.conf'
+//	    format: ini
+//	  - files: feeds/**/*.xml
+//	    xml-schema: schemas/feed.xsd
+//	lint:
+//	  rules:
+//	    json-indent: error
+type projectConfig struct {
+	// Validate sets validate flags by name, such as format or strict, unless given on the
+	// command line
+	Validate map[string]any `yaml:"validate"`
+	// Ignore holds patterns skipped like --exclude
+	Ignore patternList `yaml:"ignore"`
+	// Overrides set the format and schemas of files matching their patterns; the last
+	// matching override wins
+	Overrides []configOverride `yaml:"overrides"`
+	// Lint holds the rule settings of the lint command, as in a --rules file
+	Lint yaml.Node `yaml:"lint"`
+
+	// path is the file the config was read from, for resolving schema paths and errors
+	path string
+}
+
+// configOverride applies to files matching any of Files, matched like --exclude patterns.
+type configOverride struct {
+	Files     patternList `yaml:"files"`
+	Format    string      `yaml:"format"`
+	XMLSchema string      `yaml:"xml-schema"`
+	CSVSchema string      `yaml:"csv-schema"`
+
+	// validatorOpts and the keys are loaded from the schemas when the config is read
+	validatorOpts []serdeval.Option
+	xmlSchemaKey  string
+	csvSchemaKey  string
+}
+
+// patternList is a list of patterns that may be written as a single string.
+type patternList []string
+
+// UnmarshalYAML reads a single pattern or a sequence of them.
+func (p *patternList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = patternList{node.Value}
+
+		return nil
+	}
+
+	return node.Decode((*[]string)(p))
+}
+
+// findConfig returns the path of the project config in dir or its nearest parent that has
+// one, or "" when there is none.
+func findConfig(dir string) string {
+	for {
+		for _, name := range configNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadProjectConfig reads the config named by --config or, without it, the one found from
+// dir up. It returns nil when there is no config to read.
+func loadProjectConfig(cmd *cobra.Command, dir string) (*projectConfig, error) {
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			path = findConfig(abs)
+		}
+		if path == "" {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return nil, err
+	}
+	config, err := parseProjectConfig(data, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// parseProjectConfig reads a config file found at path. Unknown keys are errors, so typos
+// do not go unnoticed, and schemas are loaded so a bad one fails before any file is read.
+func parseProjectConfig(data []byte, path string) (*projectConfig, error) {
+	config := projectConfig{path: path}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	// An empty file is a valid config that sets nothing
+	if err := dec.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if err := checkGlobs(config.Ignore); err != nil {
+		return nil, fmt.Errorf("ignore: %w", err)
+	}
+	dir := filepath.Dir(path)
+	for i := range config.Overrides {
+		if err := config.Overrides[i].load(dir); err != nil {
+			return nil, fmt.Errorf("overrides[%d]: %w", i, err)
+		}
+	}
+
+	return &config, nil
+}
+
+// load checks the override's patterns and format and reads its schemas, resolving their
+// paths against dir, the directory of the config file.
+func (o *configOverride) load(dir string) error {
+	if len(o.Files) == 0 {
+		return errors.New("files is required")
+	}
+	if err := checkGlobs(o.Files); err != nil {
+		return err
+	}
+	format := autoFormat
+	if o.Format != "" {
+		if !isSupportedFormat(o.Format) {
+			return fmt.Errorf("unsupported format %s", o.Format)
+		}
+		format = o.Format
+	}
+
+	if o.XMLSchema != "" {
+		opts, key, err := xmlSchemaOptions(format, resolvePath(dir, o.XMLSchema))
+		if err != nil {
+			return fmt.Errorf("xml-schema: %w", err)
+		}
+		o.validatorOpts, o.xmlSchemaKey = append(o.validatorOpts, opts...), key
+	}
+	if o.CSVSchema != "" {
+		opts, key, err := csvSchemaOptions(format, resolvePath(dir, o.CSVSchema))
+		if err != nil {
+			return fmt.Errorf("csv-schema: %w", err)
+		}
+		o.validatorOpts, o.csvSchemaKey = append(o.validatorOpts, opts...), key
+	}
+
+	return nil
+}
+
+// resolvePath returns name, relative to dir unless it is absolute.
+func resolvePath(dir, name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// runFlags are validate flags about a single run, such as its output, rather than how files
+// are validated. Commands without them, like daemon, ignore them in the validate section.
+var runFlags = []string{
+	"quiet", "json", "output", "template", "summary", "follow", "allow-network",
+	"fail-fast", "warnings-as-errors", "exit-zero", "jobs", "cache-dir",
+}
+
+// applyFlags sets every flag named in the validate section that was not given on the
+// command line. A list sets a repeatable flag once per item.
+func (c *projectConfig) applyFlags(cmd *cobra.Command) error {
+	names := make([]string, 0, len(c.Validate))
+	for name := range c.Validate {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if cmd.Flags().Lookup(name) == nil && slices.Contains(runFlags, name) {
+			continue
+		}
+		if name == "config" || cmd.Flags().Lookup(name) == nil {
+			return fmt.Errorf("validate: unknown flag %q", name)
+		}
+		if cmd.Flags().Changed(name) {
+			continue
+		}
+		values, ok := c.Validate[name].([]any)
+		if !ok {
+			values = []any{c.Validate[name]}
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("validate: %s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// lintConfig returns the lint rule settings of the config, or nil when it has none.
+func (c *projectConfig) lintConfig() (*lint.Config, error) {
+	if c == nil || c.Lint.Kind == 0 {
+		return nil, nil
+	}
+	data, err := yaml.Marshal(&c.Lint)
+	if err != nil {
+		return nil, err
+	}
+	config, err := lint.ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: lint: %w", c.path, err)
+	}
+
+	return config, nil
+}
+
+// forFile returns opts with the overrides matching filename applied. The format of an
+// override only applies when --format was not given on the command line.
+func (o validateOptions) forFile(filename string) validateOptions {
+	if o.config == nil {
+		return o
+	}
+	for _, override := range o.config.Overrides {
+		if !excluded(filename, override.Files) {
+			continue
+		}
+		if override.Format != "" && !o.formatFlagSet {
+			o.format = override.Format
+		}
+		if len(override.validatorOpts) > 0 {
+			o.validatorOpts = append(slices.Clip(o.validatorOpts), override.validatorOpts...)
+		}
+		if override.xmlSchemaKey != "" {
+			o.xmlSchemaKey = override.xmlSchemaKey
+		}
+		if override.csvSchemaKey != "" {
+			o.csvSchemaKey = override.csvSchemaKey
+		}
+	}
+
+	return o
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newValidationCmd returns a command with the validation flags, parsed from args.
+func newValidationCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "test"}
+	addValidationFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+
+	return cmd
+}
+
+func TestProjectConfigApplyFlags(t *testing.T) {
+	config, err := parseProjectConfig([]byte(`
+validate:
+  strict: true
+  max-errors: 5
+  exclude: [a/**, b/**]
+  jobs: 4
+`), "/repo/.serdeval.yaml")
+	if err != nil {
+		t.Fatalf("parseProjectConfig() error = %v", err)
+	}
+
+	// jobs only affects a validate run, so a command without it ignores it
+	cmd := newValidationCmd(t, "--strict=false")
+	if err = config.applyFlags(cmd); err != nil {
+		t.Fatalf("applyFlags() error = %v", err)
+	}
+	strict, _ := cmd.Flags().GetBool("strict")
+	maxErrors, _ := cmd.Flags().GetInt("max-errors")
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	if strict {
+		t.Error("strict = true, want the command line's false to win over the config")
+	}
+	if maxErrors != 5 {
+		t.Errorf("max-errors = %d, want 5 from the config", maxErrors)
+	}
+	if !slices.Equal(exclude, []string{"a/**", "b/**"}) {
+		t.Errorf("exclude = %q, want each item of the config's list", exclude)
+	}
+}
+
+func TestProjectConfigForFile(t *testing.T) {
+	config, err := parseProjectConfig([]byte(`
+overrides:
+  - files: '*.conf'
+    format: ini
+  - files: legacy/**
+    format: yaml
+`), "/repo/.serdeval.yaml")
+	if err != nil {
+		t.Fatalf("parseProjectConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		file          string
+		formatFlagSet bool
+		want          string
+	}{
+		{"override format", "app.conf", false, "ini"},
+		{"last match wins", "legacy/app.conf", false, "yaml"},
+		{"no match", "app.json", false, autoFormat},
+		{"--format wins over the override", "app.conf", true, autoFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := validateOptions{format: autoFormat, config: config, formatFlagSet: tt.formatFlagSet}
+			if got := opts.forFile(tt.file).format; got != tt.want {
+				t.Errorf("forFile(%q).format = %s, want %s", tt.file, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectConfigIgnore(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".serdeval.yaml":   "ignore:\n  - vendor/**\n  - '*.generated.json'\n",
+		"a.json":           "{}",
+		"b.generated.json": "{}",
+		"vendor/c.json":    "{}",
+		"sub/d.json":       "{}",
+	})
+
+	opts := validationOptionsFromFlags(newValidationCmd(t), dir)
+	var got []string
+	if err := opts.walk.walk(dir, func(path string) {
+		rel, _ := filepath.Rel(dir, path)
+		got = append(got, filepath.ToSlash(rel))
+	}); err != nil {
+		t.Fatalf("walk() error = %v", err)
+	}
+	if want := []string{".serdeval.yaml", "a.json", "sub/d.json"}; !slices.Equal(got, want) {
+		t.Errorf("walked %q, want %q", got, want)
+	}
+}
+
+func TestProjectConfigSchemaPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"conf/.serdeval.yaml": "overrides:\n  - files: feeds/*.xml\n    xml-schema: schemas/feed.xsd\n",
+		"conf/schemas/feed.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="feed" type="xs:string"/>
+</xs:schema>`,
+		"feeds/good.xml": "<feed>hello</feed>",
+		"feeds/bad.xml":  "<item>hello</item>",
+	})
+
+	// The schema path is relative to the config file, not the working directory
+	cmd := newValidationCmd(t, "--config", filepath.Join(dir, "conf", ".serdeval.yaml"))
+	opts := validationOptionsFromFlags(cmd, dir)
+	if result := validateFile(filepath.Join(dir, "feeds", "good.xml"), opts); !result.Valid {
+		t.Errorf("good.xml: %s", result.Error)
+	}
+	if result := validateFile(filepath.Join(dir, "feeds", "bad.xml"), opts); result.Valid {
+		t.Error("bad.xml is valid, want the override's schema to reject it")
+	}
+}
+
+func TestParseProjectConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"malformed", "ignore: [vendor/**\n", "did not find expected"},
+		{"unknown key", "validat:\n  strict: true\n", "field validat not found"},
+		{"unknown override key", "overrides:\n  - files: '*.conf'\n    fromat: ini\n", "field fromat not found"},
+		{"override without files", "overrides:\n  - format: ini\n", "overrides[0]: files is required"},
+		{"unsupported override format", "overrides:\n  - files: '*.x'\n    format: nope\n", "unsupported format nope"},
+		{"bad ignore pattern", "ignore: ['a/[b']\n", "ignore:"},
+		{"missing schema", "overrides:\n  - files: '*.xml'\n    xml-schema: missing.xsd\n", "xml-schema:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseProjectConfig([]byte(tt.config), filepath.Join(t.TempDir(), ".serdeval.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseProjectConfig() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	config, err := parseProjectConfig([]byte("validate:\n  nope: 1\n"), "/repo/.serdeval.yaml")
+	if err != nil {
+		t.Fatalf("parseProjectConfig() error = %v", err)
+	}
+	if err = config.applyFlags(newValidationCmd(t)); err == nil || !strings.Contains(err.Error(), `unknown flag "nope"`) {
+		t.Errorf("applyFlags() error = %v, want an unknown flag error", err)
+	}
+}
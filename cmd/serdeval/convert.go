@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+// convertFormats are the formats convert reads and writes.
+var convertFormats = []serdeval.Format{
+	serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML, serdeval.FormatXML,
+}
+
+func runConvert(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+
+	name := "stdin"
+	var data []byte
+	var err error
+	if len(args) == 0 {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		name = args[0]
+		data, err = os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	}
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Cannot read %s: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	out, err := convertData(data, name, from, to)
+	if err != nil {
+		printDocumentError(name, err)
+		os.Exit(1)
+	}
+	_, _ = os.Stdout.Write(out)
+}
+
+// convertData converts data from one format to another.
+func convertData(data []byte, filename, from, to string) ([]byte, error) {
+	if to == "" {
+		return nil, errors.New("no target format: pass json, yaml, toml, or xml")
+	}
+
+	return convert.Convert(data, sourceFormat(from, filename, convertFormats), serdeval.Format(to))
+}
+
+// sourceFormat returns the format named by flag or, for "auto", the one filename maps to
+// when it is among supported, else serdeval.FormatAuto to detect it from the content.
+func sourceFormat(flag, filename string, supported []serdeval.Format) serdeval.Format {
+	if flag != "" && flag != autoFormat {
+		return serdeval.Format(flag)
+	}
+	if format := serdeval.DetectFormatFromFilename(filename); slices.Contains(supported, format) {
+		return format
+	}
+
+	return serdeval.FormatAuto
+}
+
+// printDocumentError reports why the document name could not be converted or formatted,
+// at the line and column of the failure when it did not validate.
+func printDocumentError(name string, err error) {
+	var verr *serdeval.ValidationError
+	if errors.As(err, &verr) && verr.Line > 0 {
+		_, _ = red.Fprintf(os.Stderr, markInvalid+" %s:%d:%d: %s\n", name, verr.Line, verr.Column, verr.Message)
+
+		return
+	}
+	_, _ = red.Fprintf(os.Stderr, markInvalid+" %s: %v\n", name, err)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// csvDialectOptions turns --csv-delimiter, --csv-quote, --csv-comment, and --csv-lazy-quotes
+// into validator options, along with a key describing the dialect for the result cache.
+func csvDialectOptions(delimiter, quote, comment string, lazyQuotes bool) ([]serdeval.Option, string, error) {
+	var dialect serdeval.CSVDialect
+	var err error
+	if dialect.Delimiter, err = csvDialectRune("--csv-delimiter", delimiter); err != nil {
+		return nil, "", err
+	}
+	if dialect.Quote, err = csvDialectRune("--csv-quote", quote); err != nil {
+		return nil, "", err
+	}
+	if dialect.Comment, err = csvDialectRune("--csv-comment", comment); err != nil {
+		return nil, "", err
+	}
+	dialect.LazyQuotes = lazyQuotes
+	if dialect == (serdeval.CSVDialect{}) {
+		return nil, "", nil
+	}
+
+	opts := []serdeval.Option{serdeval.WithCSVDialect(dialect)}
+	// Fail before reading any input if encoding/csv cannot read the dialect
+	if _, err = serdeval.NewValidator(serdeval.FormatCSV, opts...); err != nil {
+		return nil, "", err
+	}
+
+	return opts, fmt.Sprintf("%q%q%q%t", dialect.Delimiter, dialect.Quote, dialect.Comment, lazyQuotes), nil
+}
+
+// csvDialectRune reads a single-character flag value; "tab" and `\t` name the tab
+// character and an empty value or "auto" leaves the setting at its default.
+func csvDialectRune(flag, value string) (rune, error) {
+	switch value {
+	case "", "auto":
+		return 0, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) {
+		return 0, fmt.Errorf("%s must be a single character, got %q", flag, value)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// csvSchemaOptions turns --csv-schema, a JSON table schema, into validator options, along
+// with a fingerprint of the schema for the result cache.
+func csvSchemaOptions(format, schemaPath string) ([]serdeval.Option, string, error) {
+	if schemaPath == "" {
+		return nil, "", nil
+	}
+	if format != autoFormat && format != string(serdeval.FormatCSV) {
+		return nil, "", errors.New("--csv-schema requires --format csv or auto")
+	}
+
+	data, err := os.ReadFile(schemaPath) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return nil, "", err
+	}
+	var schema serdeval.CSVSchema
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&schema); err != nil {
+		return nil, "", err
+	}
+	opts := []serdeval.Option{serdeval.WithCSVSchema(schema)}
+
+	// Fail before reading any input if the schema is unusable
+	if _, err = serdeval.NewValidator(serdeval.FormatCSV, opts...); err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return opts, hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// watchSettle is how long the daemon waits after the last change it is told about before
+// rescanning, so a burst of writes, such as a checkout, costs one scan.
+const watchSettle = 100 * time.Millisecond
+
+// indexEntry is the cached state of a single file in the daemon index.
+type indexEntry struct {
+	modTime time.Time
+	size    int64
+	result  ValidationResult
+}
+
+// resultIndex holds the latest validation result for every file under a root. It is
+// refreshed when the file system reports a change, or by polling where it cannot.
+type resultIndex struct {
+	root string
+	opts validateOptions
+
+	mu       sync.RWMutex
+	entries  map[string]indexEntry
+	lastScan time.Time
+	// dirs are the directories the last scan walked, which are the ones watched
+	dirs map[string]bool
+}
+
+// daemonStatus is returned by the /status endpoint. Skipped counts files that were not
+// validated, such as those over --max-file-size or unreadable ones, which are not invalid.
+type daemonStatus struct {
+	Root     string    `json:"root"`
+	Files    int       `json:"files"`
+	Invalid  int       `json:"invalid"`
+	Skipped  int       `json:"skipped"`
+	LastScan time.Time `json:"last_scan"`
+}
+
+func newResultIndex(root string, opts validateOptions) *resultIndex {
+	return &resultIndex{
+		root:    root,
+		opts:    opts,
+		entries: make(map[string]indexEntry),
+	}
+}
+
+// scan walks the root once, as the walk options allow, re-validating files whose size or
+// modification time changed and dropping files that disappeared.
+func (idx *resultIndex) scan() error {
+	seen := make(map[string]bool)
+	dirs := make(map[string]bool)
+	walk := idx.opts.walk
+	walk.onDir = func(dir string) { dirs[dir] = true }
+
+	err := walk.walk(idx.root, func(path string) {
+		if !isValidatableFile(path, idx.opts.format) {
+			return
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			// Files can vanish between listing and stat; keep walking
+			return
+		}
+		seen[path] = true
+
+		idx.mu.RLock()
+		entry, ok := idx.entries[path]
+		idx.mu.RUnlock()
+		if ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+			return
+		}
+
+		result := validateFile(path, idx.opts)
+		idx.mu.Lock()
+		idx.entries[path] = indexEntry{modTime: info.ModTime(), size: info.Size(), result: result}
+		idx.mu.Unlock()
+	})
+
+	idx.mu.Lock()
+	for path := range idx.entries {
+		if !seen[path] {
+			delete(idx.entries, path)
+		}
+	}
+	idx.lastScan = time.Now()
+	idx.dirs = dirs
+	idx.mu.Unlock()
+
+	return err
+}
+
+// watch keeps the index current until ctx is done. It rescans once changes to the walked
+// directories settle, and falls back to rescanning every interval when the file system
+// cannot report changes, or stops being able to. Changes made in a directory before it was
+// watched go unreported, so a scan that adds watches is followed by another.
+func (idx *resultIndex) watch(ctx context.Context, interval time.Duration) {
+	var added bool
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		defer func() { _ = watcher.Close() }()
+		added, err = idx.syncWatches(watcher)
+	}
+	if err != nil {
+		_, _ = yellow.Fprintf(os.Stderr, "Cannot watch for changes (%v); rescanning every %s\n", err, interval)
+		idx.poll(ctx, interval)
+
+		return
+	}
+
+	settle := time.NewTimer(watchSettle)
+	if !added {
+		settle.Stop()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-watcher.Events:
+			settle.Reset(watchSettle)
+		case <-watcher.Errors:
+			// Events may have been lost, as when the queue overflows; a scan catches up
+			settle.Reset(watchSettle)
+		case <-settle.C:
+			_ = idx.scan()
+			if added, err = idx.syncWatches(watcher); err != nil {
+				_, _ = yellow.Fprintf(os.Stderr, "Cannot watch for changes (%v); rescanning every %s\n", err, interval)
+				idx.poll(ctx, interval)
+
+				return
+			}
+			if added {
+				settle.Reset(watchSettle)
+			}
+		}
+	}
+}
+
+// syncWatches makes the watcher watch exactly the directories the last scan walked, and
+// reports whether it added any.
+func (idx *resultIndex) syncWatches(watcher *fsnotify.Watcher) (bool, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	watched := make(map[string]bool)
+	for _, dir := range watcher.WatchList() {
+		watched[dir] = true
+		if !idx.dirs[dir] {
+			// A directory that is gone has already been dropped
+			_ = watcher.Remove(dir)
+		}
+	}
+	added := false
+	for dir := range idx.dirs {
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return added, err
+		}
+		added = true
+	}
+
+	return added, nil
+}
+
+// poll rescans the tree every interval until ctx is done.
+func (idx *resultIndex) poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = idx.scan()
+		}
+	}
+}
+
+// results returns the indexed results sorted by path, optionally only the invalid ones,
+// which leaves out files that were skipped or could not be validated.
+func (idx *resultIndex) results(invalidOnly bool) []ValidationResult {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	results := make([]ValidationResult, 0, len(idx.entries))
+	for _, entry := range idx.entries {
+		if invalidOnly && (exitPolicy{}).resultCode(entry.result) != exitInvalid {
+			continue
+		}
+		results = append(results, entry.result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].FileName < results[j].FileName })
+
+	return results
+}
+
+// lookup returns the indexed result for a single path.
+func (idx *resultIndex) lookup(path string) (ValidationResult, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	entry, ok := idx.entries[path]
+
+	return entry.result, ok
+}
+
+func (idx *resultIndex) status() daemonStatus {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	s := daemonStatus{Root: idx.root, Files: len(idx.entries), LastScan: idx.lastScan}
+	for _, entry := range idx.entries {
+		switch {
+		case entry.result.Skipped || exitPolicy{}.resultCode(entry.result) == exitUnvalidated:
+			s.Skipped++
+		case !entry.result.Valid:
+			s.Invalid++
+		}
+	}
+
+	return s
+}
+
+// handler exposes the index over HTTP:
+//
+//	GET /status                 counts and last scan time
+//	GET /results[?invalid=1]    all indexed results, sorted by path
+//	GET /result?path=FILE       the result for one file (path as walked from root)
+func (idx *resultIndex) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, idx.status())
+	})
+
+	mux.HandleFunc("/results", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, idx.results(r.URL.Query().Get("invalid") != ""))
+	})
+
+	mux.HandleFunc("/result", func(w http.ResponseWriter, r *http.Request) {
+		path := filepath.Clean(r.URL.Query().Get("path"))
+		result, ok := idx.lookup(path)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "file not indexed: " + path})
+
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+
+	return mux
+}
+
+// writeJSON encodes v as the response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) {
+	root, _ := cmd.Flags().GetString("root")
+	listen, _ := cmd.Flags().GetString("listen")
+	socket, _ := cmd.Flags().GetString("socket")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	opts := validationOptionsFromFlags(cmd, root)
+
+	idx := newResultIndex(filepath.Clean(root), opts)
+	if err := idx.scan(); err != nil {
+		_, _ = red.Printf("Error scanning %s: %v\n", root, err)
+		os.Exit(1)
+	}
+
+	var listener net.Listener
+	var err error
+	if socket != "" {
+		listener, err = listenUnix(socket)
+	} else {
+		listener, err = net.Listen("tcp", listen)
+	}
+	if err != nil {
+		_, _ = red.Printf("Error starting daemon: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go idx.watch(ctx, interval)
+
+	server := &http.Server{
+		Handler:      idx.handler(),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	status := idx.status()
+	_, _ = cyan.Printf("%sWatching %s (%d files, %d invalid, %d skipped) on %s\n", bannerWatch,
+		status.Root, status.Files, status.Invalid, status.Skipped, listener.Addr())
+	fmt.Printf("Press Ctrl+C to stop\n\n")
+
+	// Closing a unix listener also removes its socket file
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		_, _ = red.Printf("Error serving: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// listenUnix listens on the unix socket at path. A socket file left behind by a daemon
+// that did not shut down cleanly is replaced, but nothing else is removed: not a file that
+// is not a socket, nor the socket of a daemon still answering on it.
+func listenUnix(path string) (net.Listener, error) {
+	info, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	case info.Mode()&fs.ModeSocket == 0:
+		return nil, fmt.Errorf("%s exists and is not a socket", path)
+	default:
+		if conn, dialErr := net.DialTimeout("unix", path, time.Second); dialErr == nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		if err = os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return net.Listen("unix", path)
+}
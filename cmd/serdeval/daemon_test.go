@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// writeFiles writes each file under dir, creating its directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newTestIndex(t *testing.T, files map[string]string) (*resultIndex, string) {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	idx := newResultIndex(dir, validateOptions{format: autoFormat, walk: walkOptions{skipDirs: defaultSkipDirs}})
+	if err := idx.scan(); err != nil {
+		t.Fatalf("scan() error = %v", err)
+	}
+
+	return idx, dir
+}
+
+func TestResultIndexScan(t *testing.T) {
+	idx, dir := newTestIndex(t, map[string]string{"a.json": `{"a": 1}`, "b.yaml": "b: 1\n", "sub/c.json": "[]"})
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.yaml")
+	if got := idx.status(); got.Files != 3 || got.Invalid != 0 {
+		t.Fatalf("status() = %+v, want 3 valid files", got)
+	}
+
+	writeFiles(t, dir, map[string]string{"a.json": `{"a": 1,,}`})
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.scan(); err != nil {
+		t.Fatalf("scan() error = %v", err)
+	}
+
+	if result, ok := idx.lookup(a); !ok || result.Valid {
+		t.Errorf("lookup(a.json) = %+v, %v, want the modified file invalid", result, ok)
+	}
+	if _, ok := idx.lookup(b); ok {
+		t.Error("lookup(b.yaml) found the deleted file")
+	}
+	if got := idx.status(); got.Files != 2 || got.Invalid != 1 {
+		t.Errorf("status() = %+v, want 2 files, 1 invalid", got)
+	}
+	if got := idx.results(true); len(got) != 1 || got[0].FileName != a {
+		t.Errorf("results(invalid) = %+v, want only a.json", got)
+	}
+}
+
+func TestResultIndexStatus(t *testing.T) {
+	idx := newResultIndex("root", validateOptions{})
+	idx.entries = map[string]indexEntry{
+		"valid.json":   {result: ValidationResult{Valid: true}},
+		"invalid.json": {result: ValidationResult{Code: codeInvalid, Error: "bad", FileName: "invalid.json"}},
+		"large.json":   {result: ValidationResult{Skipped: true, Error: "skipped: too large"}},
+		"gone.json":    {result: ValidationResult{Code: codeReadError, Error: "permission denied"}},
+		"blob.json":    {result: ValidationResult{Code: string(serdeval.ErrCodeBinary), Error: "binary file"}},
+	}
+
+	if got := idx.status(); got.Files != 5 || got.Invalid != 1 || got.Skipped != 3 {
+		t.Errorf("status() = %+v, want 5 files, 1 invalid, 3 skipped", got)
+	}
+	if got := idx.results(true); len(got) != 1 || got[0].FileName != "invalid.json" {
+		t.Errorf("results(invalid) = %+v, want only invalid.json", got)
+	}
+}
+
+func TestResultIndexScanMaxFileSize(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"small.json": "{}", "large.json": `{"a": "` + strings.Repeat("x", 100) + `"}`})
+	idx := newResultIndex(dir, validateOptions{format: autoFormat, maxFileSize: 50})
+	if err := idx.scan(); err != nil {
+		t.Fatalf("scan() error = %v", err)
+	}
+	if got := idx.status(); got.Files != 2 || got.Invalid != 0 || got.Skipped != 1 {
+		t.Errorf("status() = %+v, want 2 files, 0 invalid, 1 skipped", got)
+	}
+}
+
+func TestResultIndexHandler(t *testing.T) {
+	idx, dir := newTestIndex(t, map[string]string{"a.json": `{"a": 1}`, "b.json": "{"})
+	server := httptest.NewServer(idx.handler())
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"status", "/status", http.StatusOK},
+		{"results", "/results?invalid=1", http.StatusOK},
+		{"indexed file", "/result?path=" + url.QueryEscape(filepath.Join(dir, "a.json")), http.StatusOK},
+		{"file not indexed", "/result?path=" + url.QueryEscape(filepath.Join(dir, "c.json")), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			if resp.StatusCode != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.path, resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	resp, err := http.Get(server.URL + "/results?invalid=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var results []ValidationResult
+	if err = json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].FileName != filepath.Join(dir, "b.json") {
+		t.Errorf("/results?invalid=1 = %+v, want only b.json", results)
+	}
+}
+
+func TestResultIndexWatch(t *testing.T) {
+	idx, dir := newTestIndex(t, map[string]string{"a.json": "{}"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// An hour-long interval means only a change notification can pick up the new file
+	go idx.watch(ctx, time.Hour)
+
+	added := filepath.Join(dir, "sub", "new.json")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		// Rewriting the file until it shows up does not depend on when the watch started
+		writeFiles(t, dir, map[string]string{"sub/new.json": "{"})
+		if result, ok := idx.lookup(added); ok {
+			if result.Valid {
+				t.Errorf("lookup(new.json) = %+v, want invalid", result)
+			}
+
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("watch() did not pick up a file added in a new directory")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestListenUnix(t *testing.T) {
+	dir := t.TempDir()
+
+	regular := filepath.Join(dir, "file")
+	writeFiles(t, dir, map[string]string{"file": "keep me"})
+	if _, err := listenUnix(regular); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("listenUnix(regular file) error = %v, want not a socket", err)
+	}
+	if data, err := os.ReadFile(regular); err != nil || string(data) != "keep me" { // #nosec G304 - test file
+		t.Errorf("regular file changed: %q, %v", data, err)
+	}
+
+	live := filepath.Join(dir, "live.sock")
+	l, err := listenUnix(live)
+	if err != nil {
+		t.Fatalf("listenUnix() error = %v", err)
+	}
+	defer func() { _ = l.Close() }()
+	go func() {
+		for {
+			conn, acceptErr := l.Accept()
+			if acceptErr != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	if _, err = listenUnix(live); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("listenUnix(live socket) error = %v, want in use", err)
+	}
+
+	stale := filepath.Join(dir, "stale.sock")
+	old, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Leave the socket file behind, as a daemon that was killed does
+	old.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = old.Close()
+	l2, err := listenUnix(stale)
+	if err != nil {
+		t.Fatalf("listenUnix(stale socket) error = %v", err)
+	}
+	_ = l2.Close()
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// detectSniffLen is how much of a file detect reads to tell whether it is binary, which
+// serdeval.IsBinary decides from the same amount.
+const detectSniffLen = 8000
+
+// DetectionResult is one line of `serdeval detect` output.
+type DetectionResult struct {
+	FileName string `json:"filename"`
+	Format   string `json:"format"`
+	// Confidence is set when the format was detected from content rather than the file name
+	Confidence float64 `json:"confidence,omitempty"`
+	// Binary is set for images, archives, and other binary files whose format is unknown
+	Binary bool   `json:"binary,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runDetect(cmd *cobra.Command, args []string) {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	walk := walkOptionsFromFlags(cmd)
+	if err := checkGlobs(walk.exclude); err != nil {
+		_, _ = red.Printf("Invalid --exclude: %v\n", err)
+		os.Exit(1)
+	}
+
+	var results []DetectionResult
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		results = append(results, detectionResult(data, "stdin", "", err))
+	}
+	for _, arg := range args {
+		results = append(results, detectPath(arg, walk)...)
+	}
+
+	failed := false
+	for _, result := range results {
+		if result.Error != "" {
+			failed = true
+		}
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(results)
+	} else {
+		for _, result := range results {
+			if result.Error != "" {
+				_, _ = red.Printf(markInvalid+" %s: %s\n", result.FileName, result.Error)
+
+				continue
+			}
+			if result.Binary {
+				fmt.Printf("%s: %s (binary)\n", result.FileName, result.Format)
+
+				continue
+			}
+			if result.Confidence > 0 {
+				fmt.Printf("%s: %s (confidence %.2f)\n", result.FileName, result.Format, result.Confidence)
+
+				continue
+			}
+			fmt.Printf("%s: %s\n", result.FileName, result.Format)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// detectPath detects the format of a file, or of every file under a directory that the
+// walk options reach.
+func detectPath(path string, walk walkOptions) []DetectionResult {
+	info, err := os.Stat(path)
+	if err != nil {
+		return []DetectionResult{{
+			FileName: path,
+			Format:   string(serdeval.FormatUnknown),
+			Error:    fmt.Sprintf("Cannot access file: %v", err),
+		}}
+	}
+	if !info.IsDir() {
+		return []DetectionResult{detectFile(path)}
+	}
+
+	var results []DetectionResult
+	err = walk.walk(path, func(filePath string) {
+		results = append(results, detectFile(filePath))
+	})
+	if err != nil {
+		results = append(results, DetectionResult{
+			FileName: path,
+			Format:   string(serdeval.FormatUnknown),
+			Error:    fmt.Sprintf("Error walking directory: %v", err),
+		})
+	}
+
+	return results
+}
+
+// detectFile detects the format of the file name. A file whose name gives its format is
+// not opened, and of a binary file only the start is read.
+func detectFile(name string) DetectionResult {
+	if format := serdeval.DetectFormatFromFilename(name); format != serdeval.FormatUnknown {
+		return DetectionResult{FileName: name, Format: string(format)}
+	}
+
+	f, err := os.Open(name) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return detectionResult(nil, name, "", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	data := make([]byte, detectSniffLen)
+	n, err := io.ReadFull(f, data)
+	data = data[:n]
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return detectionResult(data, name, "", nil)
+	case err != nil:
+		return detectionResult(nil, name, "", err)
+	case serdeval.IsBinary(data):
+		return DetectionResult{FileName: name, Format: string(serdeval.FormatUnknown), Binary: true}
+	}
+	rest, err := io.ReadAll(f)
+
+	return detectionResult(append(data, rest...), name, "", err)
+}
+
+// detectionResult detects the format of data, using filename (if any) before content.
+func detectionResult(data []byte, displayName, filename string, readErr error) DetectionResult {
+	if readErr != nil {
+		return DetectionResult{
+			FileName: displayName,
+			Format:   string(serdeval.FormatUnknown),
+			Error:    fmt.Sprintf("Cannot read file: %v", readErr),
+		}
+	}
+
+	if filename != "" {
+		if format := serdeval.DetectFormatFromFilename(filename); format != serdeval.FormatUnknown {
+			return DetectionResult{FileName: displayName, Format: string(format)}
+		}
+	}
+	candidates := serdeval.DetectFormatAll(data)
+	if len(candidates) == 0 {
+		return DetectionResult{FileName: displayName, Format: string(serdeval.FormatUnknown), Binary: serdeval.IsBinary(data)}
+	}
+
+	return DetectionResult{
+		FileName:   displayName,
+		Format:     string(candidates[0].Format),
+		Confidence: candidates[0].Confidence,
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+// diffFormats are the formats diff reads.
+var diffFormats = []serdeval.Format{
+	serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML, serdeval.FormatXML,
+}
+
+// Exit statuses of diff, as for diff(1).
+const (
+	diffExitDifferent = 1
+	diffExitTrouble   = 2
+)
+
+func runDiff(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	docs := make([][]byte, len(args))
+	for i, name := range args {
+		data, err := os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Cannot read %s: %v\n", name, err)
+			os.Exit(diffExitTrouble)
+		}
+		docs[i] = data
+	}
+
+	changes, err := convert.DiffAs(docs[0], sourceFormat(format, args[0], diffFormats),
+		docs[1], sourceFormat(format, args[1], diffFormats))
+	var docErr *convert.DocumentError
+	if errors.As(err, &docErr) {
+		printDocumentError(args[docErr.Index], docErr.Err)
+		os.Exit(diffExitTrouble)
+	}
+	if err != nil {
+		printDocumentError(args[1], err)
+		os.Exit(diffExitTrouble)
+	}
+
+	if jsonOutput {
+		if changes == nil {
+			changes = []convert.Change{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(changes)
+	} else {
+		for _, c := range changes {
+			printChange(c)
+		}
+	}
+	if len(changes) > 0 {
+		os.Exit(diffExitDifferent)
+	}
+}
+
+// printChange prints one change as "+ path: new", "- path: old", or "~ path: old → new".
+func printChange(c convert.Change) {
+	// The root is the empty pointer; "/" is the member with the empty key
+	path := c.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	var (
+		sign  string
+		style *color.Color
+		text  string
+	)
+	switch c.Kind {
+	case convert.ChangeAdded:
+		sign, style, text = "+", green, string(c.New)
+	case convert.ChangeRemoved:
+		sign, style, text = "-", red, string(c.Old)
+	default:
+		sign, style, text = "~", yellow, string(c.Old)+" "+markArrow+" "+string(c.New)
+	}
+	_, _ = style.Printf("%s %s: %s\n", sign, path, text)
+}
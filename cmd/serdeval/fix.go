@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+func runFix(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	indent, _ := cmd.Flags().GetInt("indent")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	style := convert.FormatStyle{Indent: indent}
+
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+		out, _, err := convert.Fix(data, serdeval.Format(format), style)
+		if err != nil {
+			printDocumentError("stdin", err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(out)
+
+		return
+	}
+
+	failed := false
+	for _, name := range args {
+		if err := fixFile(name, fixFormat(format, name), style, dryRun); err != nil {
+			printDocumentError(name, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// fixFormat returns the format named by flag or else the one filename maps to. Unlike
+// other commands, fix does not guess from content, so a file of an unknown type only gets
+// the encoding and line ending repairs.
+func fixFormat(flag, filename string) serdeval.Format {
+	if flag != "" && flag != autoFormat {
+		return serdeval.Format(flag)
+	}
+
+	return serdeval.DetectFormatFromFilename(filename)
+}
+
+// fixFile repairs the file name in place and reports the fixes made, or with dryRun prints
+// them as a diff and leaves the file alone.
+func fixFile(name string, format serdeval.Format, style convert.FormatStyle, dryRun bool) error {
+	data, err := os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return err
+	}
+	out, fixes, err := convert.Fix(data, format, style)
+	if err != nil || bytes.Equal(data, out) {
+		return err
+	}
+
+	if dryRun {
+		_, _ = cyan.Fprintf(os.Stderr, "%s: would fix: %s\n", name, strings.Join(fixes, ", "))
+		writeUnifiedDiff(os.Stdout, name, data, out)
+
+		return nil
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(name, out, info.Mode().Perm()); err != nil {
+		return err
+	}
+	_, _ = green.Printf(markValid+" %s: %s\n", name, strings.Join(fixes, ", "))
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// addValidationFlags adds the flags that decide how files are validated, shared by the
+// validate and daemon commands, and the walk flags.
+func addValidationFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("format", "f", "auto",
+		"Format to validate: auto, or any format listed by 'serdeval formats'")
+	_ = cmd.RegisterFlagCompletionFunc("format", completeFormats)
+	cmd.Flags().String("max-size", "",
+		"Fail files larger than this size (e.g. 1GB) without reading them")
+	cmd.Flags().String("max-file-size", "",
+		"Skip files larger than this size (e.g. 512KB, 50MB) and report them as skipped")
+	cmd.Flags().Int("max-errors", 0,
+		"Report up to this many failures per JSONL, CSV, TSV, Dockerfile, or requirements.txt file (-1 for all)")
+	cmd.Flags().Int("max-depth", 0,
+		"Reject JSON, YAML, XML, and TOML nested deeper than this many levels")
+	cmd.Flags().Bool("strict", false,
+		"Reject JSON and YAML that parse but break stricter consumers (duplicate keys, invalid UTF-8, unknown tags)")
+	cmd.Flags().Bool("terraform", false,
+		"Also check that HCL files are valid Terraform configurations (block types, labels, required arguments)")
+	cmd.Flags().Bool("latin1", false,
+		"Read files that are not valid UTF-8 as Latin-1 (UTF-16 and byte order marks are always decoded)")
+	addWalkFlags(cmd)
+	cmd.Flags().Bool("secrets", false,
+		"Warn about likely credentials (AWS keys, private keys, tokens, passwords) in the files")
+	cmd.Flags().Bool("pii", false,
+		"Warn about likely personal data (emails, phone numbers, card numbers) in CSV, TSV, JSON, and JSONL files")
+	cmd.Flags().Bool("check-links", false,
+		"Also check that relative links, images, and anchors in Markdown files resolve (offline)")
+	cmd.Flags().String("config", "",
+		"Project config file (default: .serdeval.yaml in the working directory or its nearest parent)")
+	cmd.Flags().String("proto-descriptor-set", "",
+		"With --format protojson or protobuf, a FileDescriptorSet (protoc --descriptor_set_out) defining --proto-message")
+	cmd.Flags().String("proto-message", "",
+		"With --format protojson or protobuf, the fully qualified message type payloads must decode as")
+	cmd.Flags().String("xml-schema", "",
+		"Also check XML files against this W3C XML Schema (.xsd)")
+	cmd.Flags().String("csv-delimiter", "auto",
+		"CSV field delimiter: a single character such as ; or |, tab, or auto to sniff it")
+	cmd.Flags().String("csv-quote", "", "CSV quote character (default \")")
+	cmd.Flags().String("csv-comment", "",
+		"Skip CSV lines starting with this character (e.g. #)")
+	cmd.Flags().Bool("csv-lazy-quotes", false,
+		"Accept stray quotes in CSV fields instead of reporting them")
+	cmd.Flags().String("csv-schema", "",
+		"Also check CSV files against this JSON table schema (column names, required columns, types)")
+}
+
+// validationOptionsFromFlags returns the validate options set by the flags
+// addValidationFlags added, after applying the project config found from configDir up.
+// Like the rest of the command's setup, it prints the problem and exits on a bad flag or
+// config.
+func validationOptionsFromFlags(cmd *cobra.Command, configDir string) validateOptions {
+	// The config fills in flags, so it is applied before any flag is read
+	formatFlagSet := cmd.Flags().Changed("format")
+	config, err := loadProjectConfig(cmd, configDir)
+	if err == nil && config != nil {
+		err = config.applyFlags(cmd)
+	}
+	if err != nil {
+		_, _ = red.Printf("Invalid config: %v\n", err)
+		os.Exit(1)
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	maxSizeText, _ := cmd.Flags().GetString("max-size")
+	maxFileSizeText, _ := cmd.Flags().GetString("max-file-size")
+	maxErrors, _ := cmd.Flags().GetInt("max-errors")
+	maxDepth, _ := cmd.Flags().GetInt("max-depth")
+	strict, _ := cmd.Flags().GetBool("strict")
+	terraform, _ := cmd.Flags().GetBool("terraform")
+	latin1, _ := cmd.Flags().GetBool("latin1")
+	checkLinks, _ := cmd.Flags().GetBool("check-links")
+	secrets, _ := cmd.Flags().GetBool("secrets")
+	pii, _ := cmd.Flags().GetBool("pii")
+	protoDescriptorSet, _ := cmd.Flags().GetString("proto-descriptor-set")
+	protoMessage, _ := cmd.Flags().GetString("proto-message")
+	xmlSchema, _ := cmd.Flags().GetString("xml-schema")
+	csvDelimiter, _ := cmd.Flags().GetString("csv-delimiter")
+	csvQuote, _ := cmd.Flags().GetString("csv-quote")
+	csvComment, _ := cmd.Flags().GetString("csv-comment")
+	csvLazyQuotes, _ := cmd.Flags().GetBool("csv-lazy-quotes")
+	csvSchema, _ := cmd.Flags().GetString("csv-schema")
+
+	if format != autoFormat && !isSupportedFormat(format) {
+		_, _ = red.Printf("Unsupported format: %s (run 'serdeval formats' to list supported formats, "+
+			"or install a %s%s plugin on PATH)\n", format, pluginPrefix, format)
+		os.Exit(1)
+	}
+	walk := walkOptionsFromFlags(cmd)
+	if config != nil {
+		walk.exclude = append(walk.exclude, config.Ignore...)
+	}
+	if err = checkGlobs(walk.exclude); err != nil {
+		_, _ = red.Printf("Invalid --exclude: %v\n", err)
+		os.Exit(1)
+	}
+	validatorOpts, protoKey, err := protoMessageOptions(format, protoDescriptorSet, protoMessage)
+	if err != nil {
+		_, _ = red.Printf("Invalid protobuf options: %v\n", err)
+		os.Exit(1)
+	}
+	xmlSchemaOpts, xmlSchemaKey, err := xmlSchemaOptions(format, xmlSchema)
+	if err != nil {
+		_, _ = red.Printf("Invalid --xml-schema: %v\n", err)
+		os.Exit(1)
+	}
+	validatorOpts = append(validatorOpts, xmlSchemaOpts...)
+	csvDialectOpts, csvDialectKey, err := csvDialectOptions(csvDelimiter, csvQuote, csvComment, csvLazyQuotes)
+	if err != nil {
+		_, _ = red.Printf("Invalid CSV dialect: %v\n", err)
+		os.Exit(1)
+	}
+	validatorOpts = append(validatorOpts, csvDialectOpts...)
+	csvSchemaOpts, csvSchemaKey, err := csvSchemaOptions(format, csvSchema)
+	if err != nil {
+		_, _ = red.Printf("Invalid --csv-schema: %v\n", err)
+		os.Exit(1)
+	}
+	validatorOpts = append(validatorOpts, csvSchemaOpts...)
+	if maxErrors != 0 {
+		validatorOpts = append(validatorOpts, serdeval.WithMaxErrors(maxErrors))
+	}
+	if maxDepth != 0 {
+		validatorOpts = append(validatorOpts, serdeval.WithMaxDepth(maxDepth))
+	}
+	if strict {
+		validatorOpts = append(validatorOpts, serdeval.WithStrict())
+	}
+	if terraform {
+		validatorOpts = append(validatorOpts, serdeval.WithTerraform())
+	}
+	if latin1 {
+		validatorOpts = append(validatorOpts, serdeval.WithLatin1())
+	}
+	if secrets {
+		validatorOpts = append(validatorOpts, serdeval.WithSecretScan())
+	}
+	if pii {
+		validatorOpts = append(validatorOpts, serdeval.WithPIIScan())
+	}
+
+	opts := validateOptions{
+		format:        format,
+		maxErrors:     maxErrors,
+		maxDepth:      maxDepth,
+		strict:        strict,
+		terraform:     terraform,
+		latin1:        latin1,
+		secrets:       secrets,
+		pii:           pii,
+		checkLinks:    checkLinks,
+		validatorOpts: validatorOpts,
+		protoKey:      protoKey,
+		xmlSchemaKey:  xmlSchemaKey,
+		csvDialectKey: csvDialectKey,
+		csvSchemaKey:  csvSchemaKey,
+		config:        config,
+		formatFlagSet: formatFlagSet,
+		walk:          walk,
+	}
+	if maxFileSizeText != "" {
+		size, err := parseSize(maxFileSizeText)
+		if err != nil {
+			_, _ = red.Printf("Invalid --max-file-size: %v\n", err)
+			os.Exit(1)
+		}
+		opts.maxFileSize = size
+	}
+	if maxSizeText != "" {
+		size, err := parseSize(maxSizeText)
+		if err != nil {
+			_, _ = red.Printf("Invalid --max-size: %v\n", err)
+			os.Exit(1)
+		}
+		opts.maxSize = size
+		opts.validatorOpts = append(opts.validatorOpts, serdeval.WithMaxSize(size))
+	}
+
+	return opts
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+// fmtFormats are the formats fmt lays out.
+var fmtFormats = []serdeval.Format{
+	serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML, serdeval.FormatXML, serdeval.FormatCSV,
+}
+
+func runFmt(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	indent, _ := cmd.Flags().GetInt("indent")
+	tabs, _ := cmd.Flags().GetBool("tabs")
+	write, _ := cmd.Flags().GetBool("write")
+	list, _ := cmd.Flags().GetBool("list")
+	style := convert.FormatStyle{Indent: indent, Tabs: tabs}
+
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+		out, err := convert.Format(data, sourceFormat(format, "", fmtFormats), style)
+		if err != nil {
+			printDocumentError("stdin", err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(out)
+
+		return
+	}
+
+	failed, unformatted := false, false
+	for _, name := range args {
+		fileFormat := sourceFormat(format, name, fmtFormats)
+		changed, err := rewriteFile(name, func(data []byte) ([]byte, error) {
+			return convert.Format(data, fileFormat, style)
+		}, write, list)
+		if err != nil {
+			printDocumentError(name, err)
+			failed = true
+
+			continue
+		}
+		if list && changed {
+			fmt.Println(name)
+			unformatted = true
+		}
+	}
+	if failed || unformatted {
+		os.Exit(1)
+	}
+}
+
+// rewriteFile passes the file name through rewrite, as fmt and minify do, and reports
+// whether its content changed. Unless list or write is set, the result is printed; with
+// write, it replaces the file.
+func rewriteFile(name string, rewrite func([]byte) ([]byte, error), write, list bool) (bool, error) {
+	data, err := os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return false, err
+	}
+	out, err := rewrite(data)
+	if err != nil {
+		return false, err
+	}
+	changed := !bytes.Equal(data, out)
+
+	switch {
+	case list:
+		// The caller prints the names of changed files
+	case write && changed:
+		info, err := os.Stat(name)
+		if err != nil {
+			return changed, err
+		}
+		err = os.WriteFile(name, out, info.Mode().Perm())
+		if err != nil {
+			return changed, err
+		}
+	case !write:
+		_, _ = os.Stdout.Write(out)
+	}
+
+	return changed, nil
+}
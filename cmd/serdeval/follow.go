@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// maxFollowRecord bounds a single line read in --follow mode.
+const maxFollowRecord = 16 << 20
+
+// followStream validates r line by line as records arrive, reporting each invalid
+// record immediately. Blank lines are ignored. It returns the number of invalid
+// records once r is exhausted.
+func followStream(r io.Reader, format, output string, opts ...serdeval.Option) (int, error) {
+	var v serdeval.Validator
+	if format != string(serdeval.FormatAuto) {
+		var err error
+		if v, err = serdeval.NewValidator(serdeval.Format(format), opts...); err != nil {
+			return 0, err
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFollowRecord)
+
+	invalid := 0
+	for line := 1; scanner.Scan(); line++ {
+		record := scanner.Bytes()
+		if len(record) == 0 {
+			continue
+		}
+
+		var result serdeval.Result
+		if v != nil {
+			result = v.Validate(record)
+		} else {
+			result = serdeval.ValidateAuto(record)
+		}
+		if result.Valid {
+			continue
+		}
+
+		invalid++
+		reportRecord(ValidationResult{
+			Valid:      false,
+			Format:     string(result.Format),
+			Code:       codeInvalid,
+			Error:      result.Error,
+			Suggestion: result.Suggestion,
+			FileName:   "stdin",
+			Line:       line,
+		}, output)
+	}
+
+	return invalid, scanner.Err()
+}
+
+// reportRecord writes an invalid record as soon as it is seen.
+func reportRecord(result ValidationResult, output string) {
+	if output == outputNDJSON || output == outputJSON {
+		writeNDJSON(os.Stdout, result)
+
+		return
+	}
+
+	_, _ = red.Printf(markInvalid+" %s:%d: Invalid %s", result.FileName, result.Line, result.Format)
+	if result.Error != "" {
+		fmt.Printf(" - %s", result.Error)
+	}
+	fmt.Println()
+	if result.Suggestion != "" {
+		_, _ = cyan.Printf("  hint: %s\n", result.Suggestion)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// formatInfo describes one supported format in `serdeval formats --json` output.
+type formatInfo struct {
+	Format     string   `json:"format"`
+	Extensions []string `json:"extensions"`
+}
+
+// supportedFormats lists every registered format with its file extensions.
+func supportedFormats() []formatInfo {
+	formats := serdeval.SupportedFormats()
+	infos := make([]formatInfo, 0, len(formats))
+	for _, format := range formats {
+		exts := serdeval.FormatExtensions(format)
+		if exts == nil {
+			exts = []string{}
+		}
+		infos = append(infos, formatInfo{Format: string(format), Extensions: exts})
+	}
+
+	return infos
+}
+
+// isSupportedFormat reports whether name is accepted by --format, loading the plugins when
+// it is not a built-in format.
+func isSupportedFormat(name string) bool {
+	if isBuiltinFormat(name) {
+		return true
+	}
+	loadPlugins()
+
+	return pluginFormats[serdeval.Format(name)]
+}
+
+// isBuiltinFormat reports whether name is a format serdeval validates itself, not through a
+// plugin. The web API accepts only these, so a page cannot run programs on the server.
+func isBuiltinFormat(name string) bool {
+	for _, format := range serdeval.SupportedFormats() {
+		if string(format) == name {
+			return !pluginFormats[format]
+		}
+	}
+
+	return false
+}
+
+// completeFormats offers auto and every registered format, plugins included, for shell
+// completion of --format.
+func completeFormats(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	loadPlugins()
+	var names []string
+	for _, name := range append([]serdeval.Format{serdeval.FormatAuto}, serdeval.SupportedFormats()...) {
+		if strings.HasPrefix(string(name), toComplete) {
+			names = append(names, string(name))
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func listFormats(cmd *cobra.Command, args []string) {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	infos := supportedFormats()
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(infos)
+
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "FORMAT\tEXTENSIONS")
+	for _, info := range infos {
+		exts := "-"
+		if len(info.Extensions) > 0 {
+			exts = "." + strings.Join(info.Extensions, ", .")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", info.Format, exts)
+	}
+	_ = w.Flush()
+}
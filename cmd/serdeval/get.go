@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+// getFormats are the formats get reads.
+var getFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML}
+
+func runGet(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	raw, _ := cmd.Flags().GetBool("raw")
+
+	path, name := args[0], "stdin"
+	var data []byte
+	var err error
+	if len(args) == 1 {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		name = args[1]
+		data, err = os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	}
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Cannot read %s: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	values, err := convert.Get(data, sourceFormat(format, name, getFormats), path)
+	if err != nil {
+		printDocumentError(name, err)
+		os.Exit(1)
+	}
+	if len(values) == 0 {
+		_, _ = red.Fprintf(os.Stderr, markInvalid+" %s: nothing matches %s\n", name, path)
+		os.Exit(1)
+	}
+	for _, v := range values {
+		fmt.Println(valueText(v, raw))
+	}
+}
+
+// valueText returns the JSON value v indented for printing or, with raw, a string's text
+// without quotes.
+func valueText(v json.RawMessage, raw bool) string {
+	var s string
+	if raw && json.Unmarshal(v, &s) == nil {
+		return s
+	}
+	var b bytes.Buffer
+	if json.Indent(&b, v, "", "  ") != nil {
+		return string(v)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// hasGlobMeta reports whether s holds glob syntax: *, ?, [, or {.
+func hasGlobMeta(s string) bool {
+	return strings.ContainsAny(s, "*?[{")
+}
+
+// expandBraces returns the patterns a pattern with {a,b} alternatives stands for, such as
+// "*.{yaml,yml}" for "*.yaml" and "*.yml". Braces may nest.
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+
+	depth := 0
+	var alternatives []string
+	last := start + 1
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			if depth--; depth > 0 {
+				continue
+			}
+			alternatives = append(alternatives, pattern[last:i])
+			var expanded []string
+			for _, alt := range alternatives {
+				expanded = append(expanded, expandBraces(pattern[:start]+alt+pattern[i+1:])...)
+			}
+
+			return expanded
+		}
+	}
+
+	// An unclosed brace is literal
+	return []string{pattern}
+}
+
+// checkGlobs reports the first of patterns that cannot be matched, such as one with an
+// unclosed "[".
+func checkGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		for _, p := range expandBraces(pattern) {
+			for _, segment := range strings.Split(p, "/") {
+				if _, err := path.Match(segment, ""); err != nil {
+					return fmt.Errorf("%q: %w", pattern, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchGlob reports whether the slash-separated name matches pattern. Segments match as
+// with path.Match, except that a "**" segment matches any number of directories,
+// including none, and {a,b} matches either alternative.
+func matchGlob(pattern, name string) bool {
+	names := strings.Split(name, "/")
+	for _, p := range expandBraces(pattern) {
+		if matchSegments(strings.Split(p, "/"), names) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchSegments matches the segments of a name against the segments of a pattern.
+func matchSegments(pattern, names []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(pattern[1:], names[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], names[0]); !ok {
+			return false
+		}
+		pattern, names = pattern[1:], names[1:]
+	}
+
+	return len(names) == 0
+}
+
+// excluded reports whether name, a file or directory found walking, matches one of the
+// --exclude patterns. A pattern matches the whole path or any trailing part of it that
+// starts at a directory, so "vendor/**" excludes every vendor directory and "*.min.json"
+// every minified file.
+func excluded(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(name)), "/")
+	for i := range segments {
+		rest := strings.Join(segments[i:], "/")
+		for _, p := range patterns {
+			if matchGlob(p, rest) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// expandGlob returns the files matching pattern, found by walking from the part of the
+// pattern before its first glob segment under the rules of w.
+func expandGlob(pattern string, w walkOptions) ([]string, error) {
+	pattern = path.Clean(filepath.ToSlash(pattern))
+	segments := strings.Split(pattern, "/")
+	literal := 0
+	for literal < len(segments)-1 && !hasGlobMeta(segments[literal]) {
+		literal++
+	}
+	base := strings.Join(segments[:literal], "/")
+	switch {
+	case base == "" && strings.HasPrefix(pattern, "/"):
+		base = "/"
+	case base == "":
+		base = "."
+	}
+
+	var matches []string
+	err := w.walk(filepath.FromSlash(base), func(p string) {
+		if matchGlob(pattern, filepath.ToSlash(p)) {
+			matches = append(matches, p)
+		}
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		// Nothing matches under a directory that does not exist
+		return nil, nil
+	}
+
+	return matches, err
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.json", "a.json", true},
+		{"*.json", "dir/a.json", false},
+		{"**/*.json", "a.json", true},
+		{"**/*.json", "dir/sub/a.json", true},
+		{"**/*.json", "dir/a.yaml", false},
+		{"dir/**/a.json", "dir/a.json", true},
+		{"dir/**/a.json", "dir/x/y/a.json", true},
+		{"dir/**/a.json", "other/x/a.json", false},
+		{"dir/**", "dir", true},
+		{"dir/**", "dir/x/a.json", true},
+		{"dir/**", "dirt/a.json", false},
+		{"**", "any/thing", true},
+		{"*.{yaml,yml}", "a.yml", true},
+		{"*.{yaml,yml}", "a.json", false},
+		{"{a,b/{c,d}}/*.json", "b/d/x.json", true},
+		{"{a", "{a", true},
+		{"/abs/**/*.json", "/abs/x/a.json", true},
+		{"/abs/**/*.json", "abs/x/a.json", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		patterns []string
+		want     bool
+	}{
+		{"no patterns", "a.json", nil, false},
+		{"trailing part", "src/vendor/a.json", []string{"vendor/**"}, true},
+		{"file name anywhere", "src/app.min.json", []string{"*.min.json"}, true},
+		{"part not starting at a directory", "src/myvendor/a.json", []string{"vendor/**"}, false},
+		{"leading ./", "./vendor/a.json", []string{"vendor/**"}, true},
+		{"./ in the pattern's place", "./a.json", []string{"a.json"}, true},
+		{"absolute path", "/repo/vendor/a.json", []string{"vendor/**"}, true},
+		{"absolute pattern", "/repo/vendor/a.json", []string{"/repo/**"}, true},
+		{"absolute pattern elsewhere", "/other/vendor/a.json", []string{"/repo/**"}, false},
+		// filepath.Join uses the platform separator, a backslash on Windows
+		{"platform separator", filepath.Join("src", "vendor", "a.json"), []string{"src/vendor/*.json"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := excluded(tt.path, tt.patterns); got != tt.want {
+				t.Errorf("excluded(%q, %q) = %v, want %v", tt.path, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckGlobs(t *testing.T) {
+	if err := checkGlobs([]string{"**/*.json", "{a,b}/[xy].yaml"}); err != nil {
+		t.Errorf("checkGlobs() error = %v", err)
+	}
+	if err := checkGlobs([]string{"*.json", "a/[b.json"}); err == nil {
+		t.Error("checkGlobs() accepted an unclosed [")
+	}
+}
+
+func TestExpandGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.yaml", "sub/c.json", "sub/deep/d.json", ".git/e.json"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	abs := filepath.ToSlash(dir)
+	skip := walkOptions{skipDirs: defaultSkipDirs}
+	tests := []struct {
+		name    string
+		pattern string
+		walk    walkOptions
+		want    []string
+	}{
+		{"** at the start", "**/*.json", skip, []string{"a.json", "sub/c.json", "sub/deep/d.json"}},
+		{"** in the middle", "sub/**/*.json", skip, []string{"sub/c.json", "sub/deep/d.json"}},
+		{"** at the end", "sub/**", skip, []string{"sub/c.json", "sub/deep/d.json"}},
+		{"leading ./", "./sub/*.json", skip, []string{"sub/c.json"}},
+		{"absolute", abs + "/**/*.json", skip,
+			[]string{abs + "/a.json", abs + "/sub/c.json", abs + "/sub/deep/d.json"}},
+		{"braces", "*.{json,yaml}", skip, []string{"a.json", "b.yaml"}},
+		{"skipped directory", "**/e.json", skip, nil},
+		{"hidden", "**/e.json", walkOptions{skipDirs: defaultSkipDirs, hidden: true}, []string{".git/e.json"}},
+		{"excluded", "**/*.json", walkOptions{skipDirs: defaultSkipDirs, exclude: []string{"deep/**"}},
+			[]string{"a.json", "sub/c.json"}},
+		{"missing directory", "nope/**/*.json", skip, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandGlob(tt.pattern, tt.walk)
+			if err != nil {
+				t.Fatalf("expandGlob() error = %v", err)
+			}
+			for i := range got {
+				got[i] = filepath.ToSlash(got[i])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandGlob(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+
+	// On Windows a pattern may use backslashes, which are separators there
+	if filepath.Separator == '\\' {
+		got, err := expandGlob(`sub\*.json`, walkOptions{})
+		if err != nil || !slices.Equal(got, []string{filepath.Join("sub", "c.json")}) {
+			t.Errorf(`expandGlob("sub\*.json") = %q, %v`, got, err)
+		}
+	}
+}
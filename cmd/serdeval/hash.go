@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+// hashFormats are the formats hash canonicalizes.
+var hashFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML}
+
+func runHash(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+		sum, err := canonicalHash(data, sourceFormat(format, "", hashFormats))
+		if err != nil {
+			printDocumentError("stdin", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s  -\n", sum)
+
+		return
+	}
+
+	failed := false
+	for _, name := range args {
+		sum, err := hashFile(name, sourceFormat(format, name, hashFormats))
+		if err != nil {
+			printDocumentError(name, err)
+			failed = true
+
+			continue
+		}
+		fmt.Printf("%s  %s\n", sum, name)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// hashFile returns the digest of the canonical form of the file name.
+func hashFile(name string, format serdeval.Format) (string, error) {
+	data, err := os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return "", err
+	}
+
+	return canonicalHash(data, format)
+}
+
+// canonicalHash returns the hex SHA-256 digest of the canonical form of data.
+func canonicalHash(data []byte, format serdeval.Format) (string, error) {
+	canonical, err := convert.Canonicalize(data, format)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies a pre-commit hook written by hook install, which it may replace.
+const hookMarker = "# Installed by serdeval hook install"
+
+// preCommitHook is the script hook install writes.
+const preCommitHook = `#!/bin/sh
+` + hookMarker + `: validates staged files of supported formats.
+# Skip it once with git commit --no-verify.
+exec serdeval hook run --quiet
+`
+
+func runHookInstall(cmd *cobra.Command, args []string) {
+	force, _ := cmd.Flags().GetBool("force")
+
+	path, err := installPreCommitHook(force)
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "serdeval: %v\n", err)
+		os.Exit(1)
+	}
+	_, _ = green.Printf(markValid+" Installed pre-commit hook at %s\n", path)
+}
+
+// installPreCommitHook writes the pre-commit hook of the current repository, honoring
+// core.hooksPath, and returns its path. A hook that hook install did not write is only
+// replaced with force.
+func installPreCommitHook(force bool) (string, error) {
+	out, err := gitOutput("rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSpace(string(out))
+
+	existing, err := os.ReadFile(path) // #nosec G304 - path is the repository's own hook
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return "", err
+	case !force && !bytes.Contains(existing, []byte(hookMarker)):
+		return "", fmt.Errorf("%s already exists; use --force to replace it", path)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+	// #nosec G306 - git only runs hooks that are executable
+	if err = os.WriteFile(path, []byte(preCommitHook), 0o755); err != nil {
+		return "", err
+	}
+
+	return path, os.Chmod(path, 0o755) // #nosec G302 - git only runs hooks that are executable
+}
+
+func runHookRun(cmd *cobra.Command, args []string) {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	// Files pre-commit passes are read from disk, where it has stashed unstaged changes, and
+	// otherwise the files staged in the index are read from it
+	read := func(name string) ([]byte, error) {
+		return os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	}
+	files := args
+	if len(args) == 0 {
+		blobs, err := stagedBlobs()
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "serdeval: %v\n", err)
+			os.Exit(1)
+		}
+		staged := make(map[string]string, len(blobs))
+		files = make([]string, len(blobs))
+		for i, blob := range blobs {
+			staged[blob.path] = blob.sha
+			files[i] = blob.path
+		}
+		read = func(name string) ([]byte, error) {
+			return gitOutput("cat-file", "blob", staged[name])
+		}
+	}
+
+	failed := 0
+	for _, name := range files {
+		if !isValidatableFile(name, autoFormat) {
+			continue
+		}
+		data, err := read(name)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "serdeval: cannot read %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		result := validateData(data, name, autoFormat)
+		if !result.Valid && !result.Skipped {
+			failed++
+		}
+		printResultTo(os.Stderr, result, quiet)
+	}
+	if failed > 0 {
+		_, _ = red.Fprintf(os.Stderr, "serdeval: commit rejected, %d invalid file(s)\n", failed)
+		os.Exit(1)
+	}
+}
+
+// stagedBlobs returns the files added or modified in the index, as they are staged rather
+// than as they are in the working tree.
+func stagedBlobs() ([]changedBlob, error) {
+	base := "HEAD"
+	if _, err := gitOutput("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// The first commit is diffed against the empty tree
+		base = emptyTreeSHA
+	}
+	out, err := gitOutput("diff-index", "--cached", "-r", "-z", "--no-renames", "--diff-filter=AM", base)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRawDiff(out), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// inferSchemaFormats are the formats infer-schema reads samples in.
+var inferSchemaFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatCSV}
+
+func runInferSchema(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+
+	names, samples := args, make([][]byte, len(args))
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+		names, samples = []string{"stdin"}, [][]byte{data}
+	}
+	for i, name := range args {
+		data, err := os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Cannot read %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		samples[i] = data
+	}
+
+	schema, err := serdeval.InferSchema(samples, samplesFormat(format, names))
+	if err != nil {
+		var serr *serdeval.SampleError
+		if errors.As(err, &serr) {
+			printDocumentError(names[serr.Index], serr.Err)
+		} else {
+			printDocumentError(names[0], err)
+		}
+		os.Exit(1)
+	}
+	_, _ = os.Stdout.Write(schema)
+}
+
+// samplesFormat returns the format named by flag or, for "auto", the format every one of
+// names maps to when they agree, else serdeval.FormatAuto to detect each sample's format.
+func samplesFormat(flag string, names []string) serdeval.Format {
+	format := sourceFormat(flag, names[0], inferSchemaFormats)
+	for _, name := range names[1:] {
+		if sourceFormat(flag, name, inferSchemaFormats) != format {
+			return serdeval.FormatAuto
+		}
+	}
+
+	return format
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+)
+
+// workerPool runs tasks on a fixed number of goroutines, for --jobs.
+type workerPool struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+}
+
+// newWorkerPool starts n workers, or one per CPU when n is zero or less.
+func newWorkerPool(n int) *workerPool {
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+	p := &workerPool{tasks: make(chan func(), n)}
+	p.wg.Add(n)
+	for range n {
+		go func() {
+			defer p.wg.Done()
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+
+	return p
+}
+
+// run queues task, waiting while every worker is busy and the queue is full so a large
+// tree is walked no faster than it is validated.
+func (p *workerPool) run(task func()) {
+	p.tasks <- task
+}
+
+// wait stops accepting tasks and returns once every queued task has finished.
+func (p *workerPool) wait() {
+	close(p.tasks)
+	p.wg.Wait()
+}
+
+// validateFileTo validates filename and passes the result to emit, on a worker of
+// opts.pool when --jobs allows more than one file at a time. Files still queued when
+// --fail-fast stops the run are dropped.
+func validateFileTo(filename string, opts validateOptions, emit func(ValidationResult)) {
+	if opts.pool == nil {
+		emit(validateFile(filename, opts))
+
+		return
+	}
+	opts.pool.run(func() {
+		if !opts.stopped() {
+			emit(validateFile(filename, opts))
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestValidateJobsOrder(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{}
+	for i := range 40 {
+		name := fmt.Sprintf("d%d/f%02d", i%4, i)
+		switch i % 4 {
+		case 0:
+			files[name+".json"] = `{"a": 1}`
+		case 1:
+			files[name+".json"] = `{"a": 1,}`
+		case 2:
+			files[name+".yaml"] = "a: 1\n"
+		case 3:
+			files[name+".yaml"] = "a: [1\n"
+		}
+	}
+	writeFiles(t, dir, files)
+
+	// jsonResults decodes --output json with the timings cleared, as they differ run to run
+	jsonResults := func(t *testing.T, out string) []ValidationResult {
+		t.Helper()
+		var results []ValidationResult
+		if err := json.Unmarshal([]byte(out), &results); err != nil {
+			t.Fatal(err)
+		}
+		for i := range results {
+			results[i].Duration = 0
+		}
+
+		return results
+	}
+
+	for _, output := range []string{"json", "text", "csv", "tap"} {
+		t.Run(output, func(t *testing.T) {
+			want, wantCode := runCLIOutput(t, dir, "validate", "--output", output, "--jobs", "1", ".")
+			if wantCode != exitInvalid {
+				t.Fatalf("--jobs 1 exited %d, want %d", wantCode, exitInvalid)
+			}
+			var wantResults []ValidationResult
+			if output == "json" {
+				wantResults = jsonResults(t, want)
+				if len(wantResults) != len(files) {
+					t.Fatalf("--jobs 1 reported %d results, want %d", len(wantResults), len(files))
+				}
+				if !sort.SliceIsSorted(wantResults, func(i, j int) bool {
+					return wantResults[i].FileName < wantResults[j].FileName
+				}) {
+					t.Error("--jobs 1 results are not sorted by file name")
+				}
+			}
+
+			// Repeat so a scheduling-dependent order has a chance to show
+			for range 3 {
+				got, code := runCLIOutput(t, dir, "validate", "--output", output, "--jobs", "8", ".")
+				if code != wantCode {
+					t.Errorf("--jobs 8 exited %d, want %d", code, wantCode)
+				}
+				if output == "json" {
+					if gotResults := jsonResults(t, got); !reflect.DeepEqual(gotResults, wantResults) {
+						t.Fatalf("--jobs 8 results differ from --jobs 1:\n%s\nwant:\n%s", got, want)
+					}
+
+					continue
+				}
+				if got != want {
+					t.Fatalf("--jobs 8 output differs from --jobs 1:\n%s\nwant:\n%s", got, want)
+				}
+			}
+		})
+	}
+}
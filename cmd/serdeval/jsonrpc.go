@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// maxRPCMessage bounds a single newline-delimited message (documents can be large).
+const maxRPCMessage = 64 << 20
+
+// rpcRequest is an incoming JSON-RPC 2.0 request or notification.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is an outgoing JSON-RPC 2.0 response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the error object of a JSON-RPC 2.0 response.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcHandler handles one method call. Returning a nil error with a nil result
+// sends an empty object result.
+type rpcHandler func(method string, params json.RawMessage) (interface{}, *rpcError)
+
+// serveJSONRPC reads newline-delimited JSON-RPC 2.0 messages from r and writes
+// responses to w until r is exhausted. Notifications (requests without an id)
+// are handled but never answered.
+func serveJSONRPC(r io.Reader, w io.Writer, handle rpcHandler) error {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	reply := func(resp rpcResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(resp)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRPCMessage)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			reply(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{Code: rpcParseError, Message: err.Error()}})
+
+			continue
+		}
+		if req.Method == "" {
+			if len(req.ID) > 0 {
+				reply(rpcResponse{JSONRPC: "2.0", ID: req.ID,
+					Error: &rpcError{Code: rpcInvalidRequest, Message: "missing method"}})
+			}
+
+			continue
+		}
+
+		result, rpcErr := handle(req.Method, req.Params)
+		if len(req.ID) == 0 {
+			continue
+		}
+		if rpcErr == nil && result == nil {
+			result = struct{}{}
+		}
+		reply(rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+	}
+
+	return scanner.Err()
+}
+
+// decodeParams unmarshals params into v, mapping failures to an invalid-params error.
+func decodeParams(params json.RawMessage, v interface{}) *rpcError {
+	if len(params) == 0 {
+		return &rpcError{Code: rpcInvalidParams, Message: "missing params"}
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// checkMarkdownLinks fails a valid Markdown result whose relative links, images, or
+// anchors do not resolve, with one diagnostic per broken link. Links are resolved on the
+// local file system, so links reaching above the current directory are checked too.
+// Results are not cached: they depend on files other than filename.
+func checkMarkdownLinks(result ValidationResult, data []byte, filename string) ValidationResult {
+	if !result.Valid || result.Format != string(serdeval.FormatMarkdown) {
+		return result
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return result
+	}
+	root := filepath.VolumeName(abs) + string(filepath.Separator)
+	name := filepath.ToSlash(strings.TrimPrefix(abs, root))
+
+	diags := serdeval.CheckMarkdownLinks(os.DirFS(root), name, data)
+	if len(diags) == 0 {
+		return result
+	}
+	result.Valid = false
+	result.Code = codeBrokenLink
+	result.Diagnostics = diags
+	result.Error = diags[0].Message
+	if len(diags) > 1 {
+		result.Error = fmt.Sprintf("%d broken links", len(diags))
+	}
+
+	return result
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/lint"
+)
+
+// lintReport is the lint result of one file in `serdeval lint --json` output.
+type lintReport struct {
+	FileName string         `json:"filename"`
+	Findings []lint.Finding `json:"findings"`
+	Error    string         `json:"error,omitempty"`
+}
+
+func runLint(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	rulesFile, _ := cmd.Flags().GetString("rules")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if listRules, _ := cmd.Flags().GetBool("list-rules"); listRules {
+		printRules(format)
+
+		return
+	}
+
+	config, err := lintRules(cmd, rulesFile)
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Invalid %v\n", err)
+		os.Exit(1)
+	}
+
+	var reports []lintReport
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		reports = append(reports, lintData(data, "stdin", format, config, err))
+	}
+	for _, name := range args {
+		data, err := os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+		reports = append(reports, lintData(data, name, format, config, err))
+	}
+
+	failed := false
+	for _, report := range reports {
+		failed = failed || report.Error != ""
+		for _, f := range report.Findings {
+			failed = failed || f.Severity == lint.SeverityError
+		}
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(reports)
+	} else {
+		for _, report := range reports {
+			printLintReport(report)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// lintRules reads the rules file, or the lint section of the project config when there
+// is none. A nil config keeps every rule's defaults.
+func lintRules(cmd *cobra.Command, rulesFile string) (*lint.Config, error) {
+	if rulesFile == "" {
+		project, err := loadProjectConfig(cmd, ".")
+		if err == nil {
+			var config *lint.Config
+			if config, err = project.lintConfig(); err == nil {
+				return config, nil
+			}
+		}
+
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	data, err := os.ReadFile(rulesFile) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return nil, fmt.Errorf("rules file %s: %w", rulesFile, err)
+	}
+	config, err := lint.ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("rules file %s: %w", rulesFile, err)
+	}
+
+	return config, nil
+}
+
+// lintData lints the content of the file name, or reports readErr if it could not be read.
+func lintData(data []byte, name, flag string, config *lint.Config, readErr error) lintReport {
+	report := lintReport{FileName: name, Findings: []lint.Finding{}}
+	if readErr != nil {
+		report.Error = readErr.Error()
+
+		return report
+	}
+
+	format := serdeval.Format(flag)
+	if flag == "" || flag == autoFormat {
+		// An unknown extension leaves the format to be detected from the content
+		if format = serdeval.DetectFormatFromFilename(name); format == serdeval.FormatUnknown {
+			format = serdeval.FormatAuto
+		}
+	}
+	findings, err := lint.Lint(data, format, config)
+	if err != nil {
+		report.Error = err.Error()
+
+		return report
+	}
+	report.Findings = findings
+
+	return report
+}
+
+// printLintReport prints one line per finding as name:line:column: severity rule: message.
+func printLintReport(report lintReport) {
+	if report.Error != "" {
+		_, _ = red.Printf(markInvalid+" %s: %s\n", report.FileName, report.Error)
+
+		return
+	}
+
+	for _, f := range report.Findings {
+		style := cyan
+		switch f.Severity {
+		case lint.SeverityError:
+			style = red
+		case lint.SeverityWarning:
+			style = yellow
+		}
+		location := report.FileName
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d:%d", report.FileName, f.Line, f.Column)
+		}
+		fmt.Printf("%s: %s %s: %s\n", location, style.Sprint(f.Severity), f.Rule, f.Message)
+	}
+}
+
+// printRules lists the lint rules, or those of format unless it is auto.
+func printRules(format string) {
+	if format == autoFormat {
+		format = ""
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RULE\tFORMAT\tSEVERITY\tDESCRIPTION")
+	for _, rule := range lint.Rules(serdeval.Format(format)) {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", rule.ID, rule.Format, rule.Severity, rule.Description)
+	}
+	_ = w.Flush()
+}
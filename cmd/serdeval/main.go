@@ -2,37 +2,128 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/web"
 )
 
 var (
-	green = color.New(color.FgGreen)
-	red   = color.New(color.FgRed)
-	cyan  = color.New(color.FgCyan)
+	green  = color.New(color.FgGreen)
+	red    = color.New(color.FgRed)
+	yellow = color.New(color.FgYellow)
+	cyan   = color.New(color.FgCyan)
 
 	// Version is set at build time via -ldflags
 	Version = "dev"
 )
 
 type ValidationResult struct {
-	Valid    bool   `json:"valid"`
-	Format   string `json:"format"`
-	Error    string `json:"error,omitempty"`
-	FileName string `json:"filename,omitempty"`
+	Valid      bool   `json:"valid"`
+	Format     string `json:"format"`
+	Code       string `json:"code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	FileName   string `json:"filename,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Skipped    bool   `json:"skipped,omitempty"`
+
+	// Err is the library's structured error, with the failing element's path and line snippet
+	Err         *serdeval.ValidationError `json:"validation_error,omitempty"`
+	Diagnostics []serdeval.Diagnostic     `json:"diagnostics,omitempty"`
+	// Warnings are issues that do not fail the file, such as tabs in YAML indentation
+	Warnings []serdeval.Diagnostic `json:"warnings,omitempty"`
+	// Attempted lists the formats tried and rejected when content detection fell back to parsing
+	Attempted []serdeval.Format `json:"attempted,omitempty"`
+	// Encoding is the encoding the file was decoded from when it was not plain UTF-8
+	Encoding serdeval.Encoding `json:"encoding,omitempty"`
+	// Duration, BytesRead, and Lines measure the validation, for spotting slow or large files
+	Duration  time.Duration `json:"duration_ns,omitempty"`
+	BytesRead int64         `json:"bytes_read,omitempty"`
+	Lines     int           `json:"lines,omitempty"`
+}
+
+// validateOptions carries per-run settings from the validate command down to each file.
+type validateOptions struct {
+	format       string
+	maxSize      int64
+	maxFileSize  int64
+	maxErrors    int
+	maxDepth     int
+	strict       bool
+	terraform    bool
+	latin1       bool
+	secrets      bool
+	pii          bool
+	allowNetwork bool
+	checkLinks   bool
+	// walk holds the --exclude patterns and recursion flags for directories and globs
+	walk walkOptions
+	// pool runs files on --jobs workers; nil validates them one at a time
+	pool *workerPool
+	// halt is set by the first failure under --fail-fast; nil keeps going
+	halt  *atomic.Bool
+	cache *resultCache
+	// validatorOpts are passed to serdeval.NewValidator for every file
+	validatorOpts []serdeval.Option
+	protoKey      string
+	xmlSchemaKey  string
+	csvDialectKey string
+	csvSchemaKey  string
+	// config holds the per-file overrides of the project config, if any
+	config *projectConfig
+	// formatFlagSet is true when --format was given on the command line, so overrides
+	// keep to it
+	formatFlagSet bool
 }
 
+// fingerprint returns a stable string of every option that can change a result,
+// used to key the result cache.
+func (o validateOptions) fingerprint() string {
+	return fmt.Sprintf("%s|%d|%d|%d|%d|%t|%t|%t|%t|%t|%s|%s|%s|%s", o.format, o.maxSize, o.maxFileSize,
+		o.maxErrors, o.maxDepth, o.strict, o.terraform, o.latin1, o.secrets, o.pii, o.protoKey, o.xmlSchemaKey,
+		o.csvDialectKey, o.csvSchemaKey)
+}
+
+// stopped reports whether --fail-fast has seen a failure, so no more files should start.
+func (o validateOptions) stopped() bool {
+	return o.halt != nil && o.halt.Load()
+}
+
+// formMediaType is the Content-Type of an HTML form posted without file uploads
+const formMediaType = "application/x-www-form-urlencoded"
+
+// autoFormat is the --format value that detects each file's format from its name or content
+const autoFormat = string(serdeval.FormatAuto)
+
+// Error codes attached to invalid results so reports can be filtered without parsing messages
+const (
+	codeAccessError       = "access_error"
+	codeReadError         = "read_error"
+	codeWalkError         = "walk_error"
+	codeUnsupportedFormat = "unsupported_format"
+	codeTooLarge          = "too_large"
+	codeNetworkDisabled   = "network_disabled"
+	codeInvalid           = "invalid"
+	codeBrokenLink        = "broken_link"
+)
+
 func main() {
 	var rootCmd = &cobra.Command{
 		Use:   "serdeval",
@@ -41,11 +132,22 @@ func main() {
 		
 PRIVACY GUARANTEE:
 • No data logging, tracking, or retention
-• No network connections
+• No network connections (unless you pass --allow-network)
 • No clipboard access
 • All validation happens locally
 • Your data never leaves your machine`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Annotations[pluginsAnnotation] != "" {
+				loadPlugins()
+			}
+			mode, _ := cmd.Flags().GetString("color")
+
+			return setColorMode(mode)
+		},
 	}
+	rootCmd.PersistentFlags().String("color", colorAuto,
+		"Color output: auto (off when NO_COLOR is set or stdout is not a terminal), always, or never")
+	_ = rootCmd.RegisterFlagCompletionFunc("color", completeColorModes)
 
 	var validateCmd = &cobra.Command{
 		Use:   "validate [files...]",
@@ -57,20 +159,359 @@ PRIVACY GUARANTEE:
 	var webCmd = &cobra.Command{
 		Use:   "web",
 		Short: "Start web interface",
-		Long:  "Start a local web server with a user-friendly interface for validation and formatting",
-		Run:   startWebServer,
+		Long: `Start a local web server with a user-friendly interface for validation and formatting.
+It also validates and detects documents server-side, for scripts and clients without
+JavaScript:
+
+  POST /api/validate[?format=FORMAT&filename=NAME]  the validation result as JSON
+  POST /api/detect[?filename=NAME]                   the detected format as JSON
+  POST /api/validate/file[?format=FORMAT]            a multipart upload's results as a JSON array
+
+The document is the request body, or the content field of a posted HTML form.
+
+A WebSocket at /ws/validate validates as the user types. Each message is a JSON
+object {"id": 1, "content": "...", "format": "auto", "filename": "..."}; once messages
+stop arriving for --debounce, the latest is validated and answered with
+{"id": 1, "result": {...}}, whose diagnostics give lines and columns for markers.`,
+		Run: startWebServer,
 	}
 
-	var formatFlag string
 	var quietFlag bool
 	var jsonOutputFlag bool
+	var templateFlag string
+	var outputFlag string
+	var summaryFlag bool
+	var cacheDirFlag string
+	var allowNetworkFlag bool
+	var followFlag bool
+	var jobsFlag int
+	var failFastFlag bool
+	var warningsAsErrorsFlag bool
+	var exitZeroFlag bool
 	var portFlag int
 
-	validateCmd.Flags().StringVarP(&formatFlag, "format", "f", "auto", "Format to validate (json, yaml, xml, toml, auto)")
+	addValidationFlags(validateCmd)
 	validateCmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Only show errors")
-	validateCmd.Flags().BoolVarP(&jsonOutputFlag, "json", "j", false, "Output results as JSON")
+	validateCmd.Flags().BoolVarP(&jsonOutputFlag, "json", "j", false, "Output results as JSON (same as --output json)")
+	validateCmd.Flags().StringVarP(&outputFlag, "output", "o", outputText,
+		"Output format (text, json, ndjson, csv, tsv, tap)")
+	validateCmd.Flags().BoolVar(&summaryFlag, "summary", false, "Print an end-of-run summary of counts and elapsed time")
+	validateCmd.Flags().BoolVar(&followFlag, "follow", false,
+		"Treat stdin as an endless stream and report each invalid line-delimited record as it arrives")
+	validateCmd.Flags().BoolVar(&allowNetworkFlag, "allow-network", false,
+		"Allow reading s3://bucket/key and gs://bucket/key arguments (prefixes ending in / are listed)")
+	validateCmd.Flags().BoolVar(&failFastFlag, "fail-fast", false,
+		"Stop at the first invalid file and report only what was validated so far")
+	validateCmd.Flags().BoolVar(&warningsAsErrorsFlag, "warnings-as-errors", false,
+		"Fail valid files that have warnings, with exit code 3")
+	validateCmd.Flags().BoolVar(&exitZeroFlag, "exit-zero", false,
+		"Report results but exit 0 whatever they are; only usage errors fail")
+	validateCmd.Flags().IntVar(&jobsFlag, "jobs", 1,
+		"Number of files to validate at once when walking directories and globs; 0 for one per CPU")
+	validateCmd.Flags().StringVar(&cacheDirFlag, "cache-dir", "",
+		"Cache results by content hash in this directory to skip unchanged files")
+	validateCmd.Flags().StringVarP(&templateFlag, "template", "t", "",
+		"Render each result with a Go text/template (e.g. '{{.FileName}}: {{.Format}}')")
 
 	webCmd.Flags().IntVarP(&portFlag, "port", "p", 8080, "Port to serve web interface on")
+	webCmd.Flags().String("max-size", "10MB",
+		"Reject /api/validate and /api/detect request bodies and /ws/validate messages larger than this size")
+	webCmd.Flags().String("max-upload-size", "100MB",
+		"Reject /api/validate/file uploads larger than this size in total; each file is held to --max-size")
+	webCmd.Flags().Duration("debounce", 250*time.Millisecond,
+		"Wait this long after the last /ws/validate message before validating, so fast typing validates once")
+
+	var daemonCmd = &cobra.Command{
+		Use:   "daemon",
+		Short: "Watch a tree and serve validation results over HTTP or a unix socket",
+		Long: `Keep an in-memory index of validation results for every file under --root,
+re-validating files as they change, and answer queries instantly. Validation flags
+and the project config apply as they do to validate:
+
+  GET /status              file, invalid, and skipped counts, last scan time
+  GET /results[?invalid=1] all results sorted by path
+  GET /result?path=FILE    the result for a single file`,
+		Run: runDaemon,
+	}
+	daemonCmd.Flags().String("root", ".", "Directory tree to watch")
+	daemonCmd.Flags().String("listen", "127.0.0.1:7777", "TCP address to serve the API on")
+	daemonCmd.Flags().String("socket", "", "Serve the API on this unix socket instead of TCP")
+	daemonCmd.Flags().Duration("interval", 2*time.Second,
+		"How often to rescan the tree where the system cannot report changes")
+	addValidationFlags(daemonCmd)
+	daemonCmd.Flags().Lookup("config").Usage =
+		"Project config file (default: .serdeval.yaml in --root or its nearest parent)"
+
+	var mcpCmd = &cobra.Command{
+		Use:   "mcp",
+		Short: "Run a Model Context Protocol server over stdio",
+		Long: `Serve validate, detect, and convert as Model Context Protocol tools over stdin/stdout
+so AI coding assistants can check generated configs locally, without any network access.`,
+		Run: runMCP,
+	}
+
+	var rpcCmd = &cobra.Command{
+		Use:   "rpc",
+		Short: "Run a newline-delimited JSON-RPC 2.0 server over stdio",
+		Long: `Serve validate, detect, version, and ping as JSON-RPC 2.0 methods, one message per line
+on stdin/stdout, for editor plugins that want a persistent process without a full LSP.
+
+  {"jsonrpc":"2.0","id":1,"method":"validate","params":{"content":"a: 1","filename":"x.yaml"}}`,
+		Run: runRPC,
+	}
+
+	var preReceiveCmd = &cobra.Command{
+		Use:   "pre-receive",
+		Short: "Validate pushed files as a git server-side pre-receive hook",
+		Long: `Read ref update lines from stdin as git passes them to a pre-receive hook,
+validate every added or modified file of a supported format straight from the
+object database, and exit non-zero to reject the push if any file is invalid.
+
+Install by calling it from hooks/pre-receive in the server repository:
+
+  #!/bin/sh
+  exec serdeval pre-receive --quiet`,
+		Args: cobra.NoArgs,
+		Run:  runPreReceive,
+	}
+	preReceiveCmd.Flags().BoolP("quiet", "q", false, "Only show errors")
+
+	var hookCmd = &cobra.Command{
+		Use:   "hook",
+		Short: "Install and run a git pre-commit hook that validates staged files",
+	}
+	var hookInstallCmd = &cobra.Command{
+		Use:   "install",
+		Short: "Write a pre-commit hook to the current repository",
+		Long: `Write a pre-commit hook that runs "serdeval hook run" to the current repository,
+honoring core.hooksPath. An existing hook that hook install did not write is only
+replaced with --force.`,
+		Args: cobra.NoArgs,
+		Run:  runHookInstall,
+	}
+	hookInstallCmd.Flags().Bool("force", false, "Replace an existing pre-commit hook")
+	var hookRunCmd = &cobra.Command{
+		Use:   "run [files...]",
+		Short: "Validate staged files of supported formats",
+		Long: `Validate every added or modified file of a supported format as it is staged in
+the index, and exit non-zero to abort the commit if any file is invalid. Files of
+other types are ignored.
+
+Given file arguments, as the pre-commit framework passes them through the hook in
+.pre-commit-hooks.yaml, those files are validated from disk instead:
+
+  - repo: https://github.com/akhilesharora/serdeval
+    rev: <release tag>
+    hooks:
+      - id: serdeval`,
+		Run: runHookRun,
+	}
+	hookRunCmd.Flags().BoolP("quiet", "q", false, "Only show errors")
+	hookCmd.AddCommand(hookInstallCmd, hookRunCmd)
+
+	var schemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for validate's JSON output",
+		Run:   printSchema,
+	}
+
+	var formatsCmd = &cobra.Command{
+		Use:   "formats",
+		Short: "List supported formats and their file extensions",
+		Args:  cobra.NoArgs,
+		Run:   listFormats,
+	}
+	formatsCmd.Flags().BoolP("json", "j", false, "Output the list as JSON")
+
+	var detectCmd = &cobra.Command{
+		Use:   "detect [files...]",
+		Short: "Report the detected format of each file without validating it",
+		Long: `Detect the format of each file (from its name, then its content) and print it
+without validating, for routing files in pipelines where validation happens elsewhere.
+Directories are walked recursively; with no arguments, stdin is read.`,
+		Run: runDetect,
+	}
+	detectCmd.Flags().BoolP("json", "j", false, "Output results as JSON")
+	addWalkFlags(detectCmd)
+
+	var convertCmd = &cobra.Command{
+		Use:   "convert [file]",
+		Short: "Convert a JSON, YAML, TOML, or XML document to another of those formats",
+		Long: `Validate a document and print it converted to --to on stdout. The source format is
+taken from --from, else from the file name, else detected from the content; with no
+file argument, stdin is read. Conversions that would lose data the target cannot
+hold, such as null in TOML, fail instead.
+
+  serdeval convert --to json deploy.yaml > deploy.json`,
+		Args: cobra.MaximumNArgs(1),
+		Run:  runConvert,
+	}
+	convertCmd.Flags().String("from", autoFormat, "Source format: json, yaml, toml, xml, or auto")
+	convertCmd.Flags().StringP("to", "t", "", "Target format: json, yaml, toml, or xml")
+	_ = convertCmd.MarkFlagRequired("to")
+
+	var fmtCmd = &cobra.Command{
+		Use:   "fmt [files...]",
+		Short: "Reformat JSON, YAML, TOML, XML, and CSV files with consistent indentation",
+		Long: `Validate each file and print it laid out consistently: JSON, YAML, and XML indented
+by --indent spaces (or tabs), TOML with tidy spacing and blank lines, and CSV quoted only
+where needed. Comments in YAML, TOML, and XML are kept. With no file arguments, stdin is
+formatted to stdout.
+
+  serdeval fmt -w config/*.yaml   rewrite files in place
+  serdeval fmt -l .github/*.yml   list files that need formatting, exiting 1 if any do`,
+		Run: runFmt,
+	}
+	fmtCmd.Flags().StringP("format", "f", autoFormat, "Format of the files: json, yaml, toml, xml, csv, or auto")
+	fmtCmd.Flags().Int("indent", 2, "Spaces per nesting level in JSON, YAML, and XML")
+	fmtCmd.Flags().Bool("tabs", false, "Indent JSON and XML with tabs instead of spaces")
+	fmtCmd.Flags().BoolP("write", "w", false, "Write the result back to each file instead of printing it")
+	fmtCmd.Flags().BoolP("list", "l", false, "List files whose formatting differs instead of printing them")
+
+	var fixCmd = &cobra.Command{
+		Use:   "fix [files...]",
+		Short: "Repair files in place where the fix cannot change their meaning",
+		Long: `Rewrite each file with the repairs that are always safe: UTF-16 and byte order marks
+become plain UTF-8, CRLF line endings become LF, trailing commas that keep JSON from
+parsing are removed, and JSON, YAML, and TOML are reformatted as fmt lays them out.
+Files whose type is unknown from their name only get the encoding and line ending
+repairs unless --format is given. A file that is still invalid is reported and left
+alone. With no file arguments, stdin is fixed to stdout.
+
+  serdeval fix config/*.json           fix files in place
+  serdeval fix --dry-run settings.json  print what would change as a diff`,
+		Run: runFix,
+	}
+	fixCmd.Flags().StringP("format", "f", autoFormat, "Format of the files: json, yaml, toml, or auto")
+	fixCmd.Flags().Int("indent", 2, "Spaces per nesting level when reformatting JSON and YAML")
+	fixCmd.Flags().BoolP("dry-run", "n", false, "Print the changes as a unified diff instead of writing them")
+
+	var minifyCmd = &cobra.Command{
+		Use:   "minify [files...]",
+		Short: "Strip insignificant whitespace from JSON and XML files",
+		Long: `Validate each file and print it without the whitespace between JSON tokens or XML
+elements, one document per line. Numbers, escapes, XML text, and comments are kept as
+written. With no file arguments, stdin is minified to stdout.
+
+  serdeval minify -w dist/*.json   rewrite files in place, without a trailing newline`,
+		Run: runMinify,
+	}
+	minifyCmd.Flags().StringP("format", "f", autoFormat, "Format of the files: json, xml, or auto")
+	minifyCmd.Flags().BoolP("write", "w", false, "Write the result back to each file instead of printing it")
+
+	var hashCmd = &cobra.Command{
+		Use:   "hash [files...]",
+		Short: "Print a SHA-256 digest of the canonical form of JSON, YAML, and TOML files",
+		Long: `Validate each file and print the SHA-256 digest of its canonical form, as
+sha256sum does. JSON is canonicalized per RFC 8785 and YAML and TOML are rewritten with
+sorted keys, so reformatting, reordering keys, or editing comments leaves the digest
+unchanged while any change to the data alters it. With no file arguments, stdin is hashed.
+
+  serdeval hash config/*.json > config.sum`,
+		Run: runHash,
+	}
+	hashCmd.Flags().StringP("format", "f", autoFormat, "Format of the files: json, yaml, toml, or auto")
+
+	var inferSchemaCmd = &cobra.Command{
+		Use:   "infer-schema [files...]",
+		Short: "Generate a JSON Schema from example JSON, YAML, or CSV documents",
+		Long: `Validate each sample and print a JSON Schema (draft 2020-12) that all of them
+satisfy. Properties present in every sample are required, values seen with several types
+allow each of them, and strings get a format such as date or email when every sample
+matches it. A CSV file is read as an array of rows keyed by its header. With no file
+arguments, stdin is the only sample.
+
+  serdeval infer-schema fixtures/users/*.json > user.schema.json`,
+		Run: runInferSchema,
+	}
+	inferSchemaCmd.Flags().StringP("format", "f", autoFormat, "Format of the samples: json, yaml, csv, or auto")
+
+	var statsCmd = &cobra.Command{
+		Use:   "stats [files...]",
+		Short: "Print structural statistics of JSON, JSON Lines, YAML, TOML, CSV, and TSV files",
+		Long: `Validate each file and print its shape: nesting depth, object and key counts,
+array lengths, values counted by type, the record count of JSON Lines, and the rows,
+columns, and empty cells of CSV and TSV. Handy for sanity-checking a data drop without
+writing a script. With no file arguments, stdin is read.
+
+  serdeval stats exports/*.jsonl`,
+		Run: runStats,
+	}
+	statsCmd.Flags().StringP("format", "f", autoFormat,
+		"Format of the files: json, jsonl, yaml, toml, csv, tsv, or auto")
+	statsCmd.Flags().Bool("json", false, "Print the statistics as JSON")
+
+	var getCmd = &cobra.Command{
+		Use:   "get <path> [file]",
+		Short: "Print the values a JSON Pointer or JSONPath selects in a JSON, YAML, or TOML document",
+		Long: `Validate a document and print, as JSON, each value the path selects. A path starting
+with "/" is a JSON Pointer; one starting with "$" or "." is a JSONPath supporting member
+names, indexes, wildcards, and ".." for any depth. With no file argument, stdin is read.
+Exits 1 when nothing matches.
+
+  serdeval get -r '$.spec.template.spec.containers[0].image' deploy.yaml
+  serdeval get /version package.json`,
+		Args: cobra.RangeArgs(1, 2),
+		Run:  runGet,
+	}
+	getCmd.Flags().StringP("format", "f", autoFormat, "Format of the document: json, yaml, toml, or auto")
+	getCmd.Flags().BoolP("raw", "r", false, "Print strings without JSON quotes")
+
+	var diffCmd = &cobra.Command{
+		Use:   "diff <file> <file>",
+		Short: "Compare the data of two JSON, YAML, TOML, or XML documents",
+		Long: `Validate two documents and print the paths whose values were added (+), removed (-),
+or changed (~) from the first to the second, as JSON Pointers. Layout, comments, and key
+order are ignored, so documents in different formats can be compared. Exits 0 when the
+data is the same, 1 when it differs, and 2 on errors, as diff(1) does.
+
+  serdeval diff deploy.yaml rendered.json`,
+		Args: cobra.ExactArgs(2),
+		Run:  runDiff,
+	}
+	diffCmd.Flags().StringP("format", "f", autoFormat, "Format of both files: json, yaml, toml, xml, or auto")
+	diffCmd.Flags().Bool("json", false, "Print the changes as a JSON array")
+
+	var patchCmd = &cobra.Command{
+		Use:   "patch <patch> [file]",
+		Short: "Apply a JSON Patch or JSON Merge Patch to a JSON, YAML, or TOML document",
+		Long: `Validate a patch and a document and print the document with the patch applied. A
+patch that is a JSON array is a JSON Patch (RFC 6902); an object is a JSON Merge Patch
+(RFC 7386). Nothing is written when any operation fails, so this doubles as a dry run of
+kubectl and kustomize patches. With no file argument, stdin is patched.
+
+  serdeval patch replicas.json deploy.yaml
+  serdeval patch -w bump.json package.json`,
+		Args: cobra.RangeArgs(1, 2),
+		Run:  runPatch,
+	}
+	patchCmd.Flags().StringP("format", "f", autoFormat, "Format of the document: json, yaml, toml, or auto")
+	patchCmd.Flags().BoolP("write", "w", false, "Write the result back to the file instead of printing it")
+
+	var lintCmd = &cobra.Command{
+		Use:   "lint [files...]",
+		Short: "Check the style of valid files with configurable rules",
+		Long: `Validate each file and report what the lint rules of its format find, such as
+inconsistent indentation, key casing, or deprecated constructs, one line per finding with
+its rule ID and severity. A rules file changes severities, turns rules off, and sets their
+options; --list-rules shows the rules. With no file arguments, stdin is linted. Exits 1
+when a file is invalid or a finding has severity error.
+
+  serdeval lint --rules lint.yaml config/*.json
+
+A rules file:
+
+  rules:
+    json-indent: {severity: error, indent: 4}
+    json-key-case: {style: snake}
+    dockerfile-maintainer: off`,
+		Run: runLint,
+	}
+	lintCmd.Flags().StringP("format", "f", autoFormat, "Format of the files, or of the rules to list")
+	lintCmd.Flags().String("rules", "", "YAML or JSON rules file setting severities and options")
+	lintCmd.Flags().String("config", "", "Project config file whose lint section applies when --rules is not given")
+	lintCmd.Flags().Bool("json", false, "Print the findings as JSON")
+	lintCmd.Flags().Bool("list-rules", false, "List the lint rules instead of linting")
 
 	var versionCmd = &cobra.Command{
 		Use:   "version",
@@ -80,8 +521,29 @@ PRIVACY GUARANTEE:
 		},
 	}
 
+	usePlugins(validateCmd, daemonCmd, mcpCmd, rpcCmd, preReceiveCmd, hookRunCmd, formatsCmd, detectCmd)
+
 	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(webCmd)
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(formatsCmd)
+	rootCmd.AddCommand(detectCmd)
+	rootCmd.AddCommand(convertCmd)
+	rootCmd.AddCommand(fmtCmd)
+	rootCmd.AddCommand(fixCmd)
+	rootCmd.AddCommand(minifyCmd)
+	rootCmd.AddCommand(hashCmd)
+	rootCmd.AddCommand(inferSchemaCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(patchCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(rpcCmd)
+	rootCmd.AddCommand(preReceiveCmd)
+	rootCmd.AddCommand(hookCmd)
 	rootCmd.AddCommand(versionCmd)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -91,212 +553,548 @@ PRIVACY GUARANTEE:
 }
 
 func validateFiles(cmd *cobra.Command, args []string) {
-	format, _ := cmd.Flags().GetString("format")
+	opts := validationOptionsFromFlags(cmd, ".")
 	quiet, _ := cmd.Flags().GetBool("quiet")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
+	templateText, _ := cmd.Flags().GetString("template")
+	output, _ := cmd.Flags().GetString("output")
+	showSummary, _ := cmd.Flags().GetBool("summary")
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	warningsAsErrors, _ := cmd.Flags().GetBool("warnings-as-errors")
+	exitZero, _ := cmd.Flags().GetBool("exit-zero")
+	follow, _ := cmd.Flags().GetBool("follow")
+	opts.allowNetwork, _ = cmd.Flags().GetBool("allow-network")
+
+	if jsonOutput {
+		output = outputJSON
+	}
+	if !isValidOutput(output) {
+		_, _ = red.Printf("Unsupported output format: %s\n", output)
+		os.Exit(1)
+	}
+
+	// Parse the template up front so a typo fails before any file is read
+	var tmpl *template.Template
+	if templateText != "" {
+		if output != outputText {
+			_, _ = red.Printf("--template renders text output and cannot be combined with --output %s\n", output)
+			os.Exit(1)
+		}
+		var err error
+		tmpl, err = parseResultTemplate(templateText)
+		if err != nil {
+			_, _ = red.Printf("Invalid template: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if follow {
+		if len(args) > 0 {
+			_, _ = red.Println("--follow reads records from stdin and does not accept file arguments")
+			os.Exit(1)
+		}
+		invalid, err := followStream(os.Stdin, opts.format, output, opts.validatorOpts...)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+		if invalid > 0 {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if jobs < 0 {
+		_, _ = red.Printf("Invalid --jobs: %d (use 0 for one per CPU)\n", jobs)
+		os.Exit(1)
+	}
+	if jobs != 1 {
+		opts.pool = newWorkerPool(jobs)
+	}
+	if failFast {
+		opts.halt = new(atomic.Bool)
+		opts.walk.stopped = opts.stopped
+	}
+	if cacheDir != "" {
+		cache, err := newResultCache(cacheDir)
+		if err != nil {
+			_, _ = red.Printf("Cannot use cache directory: %v\n", err)
+			os.Exit(1)
+		}
+		opts.cache = cache
+	}
+
+	start := time.Now()
 
+	policy := exitPolicy{warningsAsErrors: warningsAsErrors, exitZero: exitZero}
 	var results []ValidationResult
+	var mu sync.Mutex
+	emit := func(result ValidationResult) {
+		// Workers of --jobs emit concurrently
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+		if opts.halt != nil && policy.resultCode(result) != 0 {
+			opts.halt.Store(true)
+		}
+		if output == outputNDJSON {
+			writeNDJSON(os.Stdout, result)
+		}
+	}
 
 	if len(args) == 0 {
-		result := validateStdin(format)
-		results = append(results, result)
+		emit(validateStdin(opts))
 	} else {
 		for _, arg := range args {
-			fileResults := validatePath(arg, format)
-			results = append(results, fileResults...)
+			if opts.stopped() {
+				break
+			}
+			if isRemotePath(arg) {
+				validateRemote(arg, opts, emit)
+
+				continue
+			}
+			validatePath(arg, opts, emit)
 		}
 	}
+	if opts.pool != nil {
+		opts.pool.wait()
+	}
 
-	if jsonOutput {
-		output, _ := json.MarshalIndent(results, "", "  ")
-		fmt.Println(string(output))
+	// NDJSON has already been streamed in completion order; everything else is sorted
+	sortResults(results)
 
-		return
+	var summary *Summary
+	if showSummary {
+		s := summarize(results, time.Since(start))
+		summary = &s
+	}
+
+	switch output {
+	case outputJSON:
+		var data []byte
+		if summary != nil {
+			data, _ = json.MarshalIndent(summaryReport{Results: results, Summary: summary}, "", "  ")
+		} else {
+			data, _ = json.MarshalIndent(results, "", "  ")
+		}
+		fmt.Println(string(data))
+		os.Exit(policy.exitCodeFor(results))
+	case outputNDJSON:
+		// Results were streamed as they finished; only the summary remains
+		if summary != nil {
+			writeNDJSON(os.Stdout, summaryLine{Summary: summary})
+		}
+		os.Exit(policy.exitCodeFor(results))
+	case outputCSV, outputTSV:
+		comma := ','
+		if output == outputTSV {
+			comma = '\t'
+		}
+		if err := writeCSVResults(os.Stdout, results, comma); err != nil {
+			_, _ = red.Printf("Error writing CSV: %v\n", err)
+			os.Exit(1)
+		}
+		// Keep stdout machine-readable; the table goes to stderr
+		if summary != nil {
+			printSummary(os.Stderr, *summary)
+		}
+		os.Exit(policy.exitCodeFor(results))
+	case outputTAP:
+		if err := writeTAPResults(os.Stdout, results); err != nil {
+			_, _ = red.Printf("Error writing TAP: %v\n", err)
+			os.Exit(1)
+		}
+		if summary != nil {
+			printSummary(os.Stderr, *summary)
+		}
+		os.Exit(policy.exitCodeFor(results))
 	}
 
-	exitCode := 0
 	for _, result := range results {
-		if !result.Valid {
-			exitCode = 1
+		if tmpl != nil {
+			if err := printTemplateResult(tmpl, result, quiet); err != nil {
+				_, _ = red.Printf("Template error: %v\n", err)
+				os.Exit(1)
+			}
+
+			continue
 		}
 		printResult(result, quiet)
 	}
 
-	os.Exit(exitCode)
-}
+	if summary != nil {
+		printSummary(os.Stdout, *summary)
+	}
 
-func validatePath(path, format string) []ValidationResult {
-	var results []ValidationResult
+	os.Exit(policy.exitCodeFor(results))
+}
 
+// validatePath validates a file, every validatable file under a directory, or every file
+// matching a glob pattern, passing each result to emit as soon as it is available.
+func validatePath(path string, opts validateOptions, emit func(ValidationResult)) {
 	info, err := os.Stat(path)
+	if err != nil && hasGlobMeta(path) {
+		validateGlob(path, opts, emit)
+
+		return
+	}
 	if err != nil {
-		results = append(results, ValidationResult{
+		emit(ValidationResult{
 			Valid:    false,
 			Format:   "unknown",
+			Code:     codeAccessError,
 			Error:    fmt.Sprintf("Cannot access file: %v", err),
 			FileName: path,
 		})
 
-		return results
+		return
 	}
 
-	if info.IsDir() {
-		err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if !info.IsDir() && isValidatableFile(filePath, format) {
-				result := validateFile(filePath, format)
-				results = append(results, result)
-			}
+	if !info.IsDir() {
+		validateFileTo(path, opts, emit)
 
-			return nil
-		})
-		if err != nil {
-			results = append(results, ValidationResult{
-				Valid:    false,
-				Format:   "unknown",
-				Error:    fmt.Sprintf("Error walking directory: %v", err),
-				FileName: path,
-			})
+		return
+	}
+	err = opts.walk.walk(path, func(filePath string) {
+		if isValidatableFile(filePath, opts.format) {
+			validateFileTo(filePath, opts, emit)
 		}
-	} else {
-		result := validateFile(path, format)
-		results = append(results, result)
+	})
+	if err != nil {
+		emit(ValidationResult{
+			Valid:    false,
+			Format:   "unknown",
+			Code:     codeWalkError,
+			Error:    fmt.Sprintf("Error walking directory: %v", err),
+			FileName: path,
+		})
 	}
+}
 
-	return results
+// validateGlob validates every file matching a glob pattern that the shell left
+// unexpanded, such as 'configs/**/*.y*ml'. Like files named on the command line, matches
+// are validated whatever their extension.
+func validateGlob(pattern string, opts validateOptions, emit func(ValidationResult)) {
+	matches, err := expandGlob(pattern, opts.walk)
+	switch {
+	case err != nil:
+		emit(ValidationResult{
+			Valid:    false,
+			Format:   "unknown",
+			Code:     codeWalkError,
+			Error:    fmt.Sprintf("Error expanding pattern: %v", err),
+			FileName: pattern,
+		})
+	case len(matches) == 0:
+		emit(ValidationResult{
+			Valid:    false,
+			Format:   "unknown",
+			Code:     codeAccessError,
+			Error:    "No files match the pattern",
+			FileName: pattern,
+		})
+	}
+	for _, match := range matches {
+		if opts.stopped() {
+			break
+		}
+		validateFileTo(match, opts, emit)
+	}
 }
 
-func validateFile(filename, format string) ValidationResult {
+func validateFile(filename string, opts validateOptions) ValidationResult {
+	opts = opts.forFile(filename)
+
+	// Check the size before reading so huge files never get loaded into memory
+	if readLimit(opts) > 0 {
+		if info, err := os.Stat(filename); err == nil {
+			if result, tooLarge := sizeLimitResult(filename, info.Size(), opts); tooLarge {
+				return result
+			}
+		}
+	}
+
 	data, err := os.ReadFile(filename) // #nosec G304 - CLI tool needs to read user-specified files
 	if err != nil {
 		return ValidationResult{
 			Valid:    false,
 			Format:   "unknown",
+			Code:     codeReadError,
 			Error:    fmt.Sprintf("Cannot read file: %v", err),
 			FileName: filename,
 		}
 	}
 
-	return validateData(data, filename, format)
+	result := validateCached(data, filename, opts)
+	if opts.checkLinks {
+		result = checkMarkdownLinks(result, data, filename)
+	}
+
+	return result
 }
 
-func validateStdin(format string) ValidationResult {
-	data, err := io.ReadAll(os.Stdin)
+func validateStdin(opts validateOptions) ValidationResult {
+	var reader io.Reader = os.Stdin
+	if limit := readLimit(opts); limit > 0 {
+		// Read one byte past the limit so oversized input is detected without buffering all of it
+		reader = io.LimitReader(os.Stdin, limit+1)
+	}
+
+	data, err := io.ReadAll(reader)
 	if err != nil {
 		return ValidationResult{
 			Valid:    false,
 			Format:   "unknown",
+			Code:     codeReadError,
 			Error:    fmt.Sprintf("Cannot read stdin: %v", err),
 			FileName: "stdin",
 		}
 	}
 
-	return validateData(data, "stdin", format)
+	if result, tooLarge := sizeLimitResult("stdin", int64(len(data)), opts); tooLarge {
+		return result
+	}
+
+	return validateCached(data, "stdin", opts)
 }
 
-func validateData(data []byte, filename, format string) ValidationResult {
-	var result serdeval.Result
+// readLimit returns the most bytes worth reading from one input: the smaller of
+// --max-size and --max-file-size, or 0 when neither is set.
+func readLimit(opts validateOptions) int64 {
+	if opts.maxSize > 0 && (opts.maxFileSize <= 0 || opts.maxSize < opts.maxFileSize) {
+		return opts.maxSize
+	}
+
+	return max(opts.maxFileSize, 0)
+}
+
+// sizeLimitResult returns the result for an input of size bytes that --max-size fails or
+// --max-file-size skips, and false if it is within both limits.
+func sizeLimitResult(filename string, size int64, opts validateOptions) (ValidationResult, bool) {
+	switch {
+	case opts.maxSize > 0 && size > opts.maxSize:
+		msg := fmt.Sprintf("too large (larger than --max-size of %d bytes)", opts.maxSize)
+
+		return ValidationResult{
+			Valid:    false,
+			Format:   string(serdeval.DetectFormatFromFilename(filename)),
+			Code:     codeTooLarge,
+			Error:    msg,
+			FileName: filename,
+			Err:      &serdeval.ValidationError{Code: serdeval.ErrCodeTooLarge, Message: msg},
+		}, true
+	case opts.maxFileSize > 0 && size > opts.maxFileSize:
+		return tooLargeResult(filename, opts.maxFileSize), true
+	}
 
-	const autoFormat = "auto"
+	return ValidationResult{}, false
+}
+
+// tooLargeResult reports a file skipped by --max-file-size.
+func tooLargeResult(filename string, limit int64) ValidationResult {
+	return ValidationResult{
+		Valid:    false,
+		Skipped:  true,
+		Format:   string(serdeval.DetectFormatFromFilename(filename)),
+		Code:     codeTooLarge,
+		Error:    fmt.Sprintf("skipped: too large (larger than %d bytes)", limit),
+		FileName: filename,
+	}
+}
+
+// validateCached consults the result cache, if any, before validating data.
+func validateCached(data []byte, filename string, opts validateOptions) ValidationResult {
+	if opts.cache == nil {
+		return validateData(data, filename, opts.format, opts.validatorOpts...)
+	}
+
+	key := opts.cache.key(data, filename, opts)
+	if result, ok := opts.cache.get(key); ok {
+		result.FileName = filename
+
+		return result
+	}
+
+	result := validateData(data, filename, opts.format, opts.validatorOpts...)
+	opts.cache.put(key, result)
+
+	return result
+}
+
+func validateData(data []byte, filename, format string, validatorOpts ...serdeval.Option) ValidationResult {
+	return validateDataContext(context.Background(), data, filename, format, validatorOpts...)
+}
+
+// validateDataContext is validateData giving up once ctx is done, for requests that can
+// be abandoned.
+func validateDataContext(ctx context.Context, data []byte, filename, format string,
+	validatorOpts ...serdeval.Option) ValidationResult {
+	var result serdeval.Result
 
 	if format == autoFormat {
 		// Try filename first, then content
 		detectedFormat := serdeval.DetectFormatFromFilename(filename)
-		if detectedFormat != serdeval.FormatUnknown {
-			v, _ := serdeval.NewValidator(detectedFormat)
-			result = v.Validate(data)
-		} else {
-			result = serdeval.ValidateAuto(data)
-		}
-	} else {
-		// Try to create validator for the specified format
-		var formatType serdeval.Format
-		switch format {
-		case "json":
-			formatType = serdeval.FormatJSON
-		case "yaml":
-			formatType = serdeval.FormatYAML
-		case "xml":
-			formatType = serdeval.FormatXML
-		case "toml":
-			formatType = serdeval.FormatTOML
-		default:
-			return ValidationResult{
-				Valid:    false,
-				Format:   format,
-				Error:    "unsupported format",
-				FileName: filename,
+		switch {
+		case detectedFormat == serdeval.FormatUnknown:
+			result = serdeval.ValidateAutoContext(ctx, data, validatorOpts...)
+		case isTextFormat(detectedFormat) && serdeval.IsBinary(data):
+			// An image or archive named like a text file is skipped rather than reported as a parse error
+			msg := fmt.Sprintf("skipped: binary content, not %s", detectedFormat)
+			result = serdeval.Result{
+				Skipped: true,
+				Format:  detectedFormat,
+				Error:   msg,
+				Err:     &serdeval.ValidationError{Code: serdeval.ErrCodeBinary, Message: msg},
 			}
+		default:
+			v, _ := serdeval.NewValidator(detectedFormat, validatorOpts...)
+			result = serdeval.ValidateContext(ctx, v, data)
 		}
-
-		v, err := serdeval.NewValidator(formatType)
+	} else {
+		v, err := serdeval.NewValidator(serdeval.Format(format), validatorOpts...)
 		if err != nil {
 			return ValidationResult{
 				Valid:    false,
 				Format:   format,
+				Code:     codeUnsupportedFormat,
 				Error:    err.Error(),
 				FileName: filename,
 			}
 		}
-		result = v.Validate(data)
+		result = serdeval.ValidateContext(ctx, v, data)
+	}
+
+	var code string
+	switch {
+	case result.Err != nil:
+		code = string(result.Err.Code)
+	case !result.Valid:
+		code = codeInvalid
 	}
 
 	return ValidationResult{
-		Valid:    result.Valid,
-		Format:   string(result.Format),
-		Error:    result.Error,
-		FileName: filename,
+		Valid:      result.Valid,
+		Format:     string(result.Format),
+		Code:       code,
+		Error:      result.Error,
+		Suggestion: result.Suggestion,
+		FileName:   filename,
+		Err:        result.Err,
+		Skipped:    result.Skipped,
+
+		Diagnostics: result.Diagnostics,
+		Warnings:    result.Warnings,
+		Attempted:   result.Attempted,
+		Encoding:    result.Encoding,
+		Duration:    result.Duration,
+		BytesRead:   result.BytesRead,
+		Lines:       result.Lines,
 	}
 }
 
+// isTextFormat reports whether format's files are text, so binary content cannot be valid:
+// every built-in format except binary Ion. Plugins may validate binary files.
+func isTextFormat(format serdeval.Format) bool {
+	return format != serdeval.FormatIon && !pluginFormats[format]
+}
+
+// isValidatableFile reports whether a directory walk should pick up filename: every file
+// when a format is forced, otherwise only files whose name maps to a registered format.
 func isValidatableFile(filename, format string) bool {
-	const autoFormat = "auto"
 	if format != autoFormat {
 		return true
 	}
 
-	ext := strings.ToLower(filepath.Ext(filename))
-	validExts := []string{".json", ".yaml", ".yml", ".xml", ".toml"}
+	return serdeval.DetectFormatFromFilename(filepath.ToSlash(filename)) != serdeval.FormatUnknown
+}
 
-	for _, validExt := range validExts {
-		if ext == validExt {
-			return true
+func printResult(result ValidationResult, quiet bool) {
+	printResultTo(os.Stdout, result, quiet)
+}
+
+// printResultTo writes the human-readable line for result to w.
+func printResultTo(w io.Writer, result ValidationResult, quiet bool) {
+	if result.Skipped {
+		if !quiet {
+			_, _ = yellow.Fprintf(w, markWarning+" %s: %s\n", result.FileName, result.Error)
 		}
-	}
 
-	return false
-}
+		return
+	}
 
-func printResult(result ValidationResult, quiet bool) {
 	if result.Valid {
 		if !quiet {
-			_, _ = green.Printf("✓ %s: Valid %s\n", result.FileName, result.Format)
+			_, _ = green.Fprintf(w, markValid+" %s: Valid %s\n", result.FileName, result.Format)
+			printWarnings(w, result)
+		}
+	} else if len(result.Diagnostics) > 1 {
+		// --max-errors: one line per failure under a header
+		_, _ = red.Fprintf(w, markInvalid+" %s: Invalid %s - %d errors\n",
+			result.FileName, result.Format, len(result.Diagnostics))
+		for _, d := range result.Diagnostics {
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", diagnosticLocation(result.FileName, d.Line, d.Column), d.Message)
+		}
+		if result.Suggestion != "" {
+			_, _ = cyan.Fprintf(w, "  hint: %s\n", result.Suggestion)
 		}
 	} else {
-		_, _ = red.Printf("✗ %s: Invalid %s", result.FileName, result.Format)
+		line, column := resultPosition(result)
+		_, _ = red.Fprintf(w, markInvalid+" %s: Invalid %s", diagnosticLocation(result.FileName, line, column), result.Format)
+		if result.Err != nil && result.Err.Path != "" {
+			_, _ = fmt.Fprintf(w, " at %s", result.Err.Path)
+		}
 		if result.Error != "" {
-			fmt.Printf(" - %s", result.Error)
+			_, _ = fmt.Fprintf(w, " - %s", result.Error)
+		}
+		_, _ = fmt.Fprintln(w)
+		if result.Suggestion != "" {
+			_, _ = cyan.Fprintf(w, "  hint: %s\n", result.Suggestion)
 		}
-		fmt.Println()
+	}
+}
+
+// printWarnings writes one indented line per warning on result.
+func printWarnings(w io.Writer, result ValidationResult) {
+	for _, d := range result.Warnings {
+		_, _ = yellow.Fprintf(w, "  warning: %s: %s\n", diagnosticLocation(result.FileName, d.Line, d.Column), d.Message)
 	}
 }
 
 func startWebServer(cmd *cobra.Command, args []string) {
 	port, _ := cmd.Flags().GetInt("port")
+	maxSizeText, _ := cmd.Flags().GetString("max-size")
+	maxSize, err := parseSize(maxSizeText)
+	if err != nil || maxSize <= 0 {
+		_, _ = red.Printf("Invalid --max-size: %s\n", maxSizeText)
+		os.Exit(1)
+	}
+	maxUploadText, _ := cmd.Flags().GetString("max-upload-size")
+	maxUpload, err := parseSize(maxUploadText)
+	if err != nil || maxUpload <= 0 {
+		_, _ = red.Printf("Invalid --max-upload-size: %s\n", maxUploadText)
+		os.Exit(1)
+	}
+	debounce, _ := cmd.Flags().GetDuration("debounce")
 
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, "web/static/index.html")
-	})
+	http.Handle("/", http.FileServerFS(web.Static))
 
 	http.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(map[string]string{"version": Version})
 	})
 
-	_, _ = cyan.Printf("🌐 SerdeVal web interface starting on http://localhost:%d\n", port)
-	_, _ = cyan.Printf("🔒 Privacy-first: All validation happens in your browser\n")
+	http.HandleFunc("/api/validate", webValidateHandler(maxSize))
+	http.HandleFunc("/api/detect", webDetectHandler(maxSize))
+	http.HandleFunc("/api/validate/file", webUploadHandler(maxSize, maxUpload))
+	http.HandleFunc("/ws/validate", webSocketValidateHandler(maxSize, debounce))
+
+	_, _ = cyan.Printf("%sSerdeVal web interface starting on http://localhost:%d\n", bannerServer, port)
+	_, _ = cyan.Printf("%sPrivacy-first: Documents are validated in your browser or by this server, nowhere else\n",
+		bannerPrivacy)
 	fmt.Printf("Press Ctrl+C to stop\n\n")
 
 	server := &http.Server{
@@ -312,3 +1110,281 @@ func startWebServer(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 }
+
+// webValidateHandler returns the /api/validate handler, which reads request bodies of up
+// to maxSize bytes.
+func webValidateHandler(maxSize int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handleWebValidate(w, r, maxSize)
+	}
+}
+
+// webDetectHandler returns the /api/detect handler, which reads request bodies of up to
+// maxSize bytes.
+func webDetectHandler(maxSize int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handleWebDetect(w, r, maxSize)
+	}
+}
+
+// handleWebValidate validates a POSTed request body. The format comes from the ?format=
+// parameter, then the Content-Type header (application/json, text/yaml, text/csv, ...),
+// then the ?filename= parameter and the content, as for files on the command line.
+// Bodies over maxSize bytes are rejected with 413 Request Entity Too Large.
+func handleWebValidate(w http.ResponseWriter, r *http.Request, maxSize int64) {
+	data, params, ok := readWebRequest(w, r, maxSize)
+	if !ok {
+		return
+	}
+
+	format := params.Get("format")
+	if format == "" {
+		format = autoFormat
+		if detected := serdeval.DetectFormatFromMIME(r.Header.Get("Content-Type")); detected != serdeval.FormatUnknown {
+			format = string(detected)
+		}
+	}
+	if format != autoFormat && !isBuiltinFormat(format) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported format: " + format})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, validateDataContext(r.Context(), data, params.Get("filename"), format))
+}
+
+// handleWebDetect reports the format of a POSTed request body without validating it, from
+// the ?filename= parameter and then the content, as serdeval detect does.
+func handleWebDetect(w http.ResponseWriter, r *http.Request, maxSize int64) {
+	data, params, ok := readWebRequest(w, r, maxSize)
+	if !ok {
+		return
+	}
+	if r.Context().Err() != nil {
+		// The client is gone, so there is no one to detect for
+		return
+	}
+	filename := params.Get("filename")
+
+	writeJSON(w, http.StatusOK, detectionResult(data, filename, filename, nil))
+}
+
+// readWebRequest reads the document POSTed to an API endpoint and its parameters. The
+// document is the request body, and the parameters come from the query string, except
+// that an HTML form posts both: the document in its content field and the parameters as
+// the other fields. If the request cannot be read, it writes an error response and
+// returns false.
+func readWebRequest(w http.ResponseWriter, r *http.Request, maxSize int64) ([]byte, url.Values, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+
+		return nil, nil, false
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSize))
+	if err != nil {
+		writeReadError(w, err)
+
+		return nil, nil, false
+	}
+
+	params := r.URL.Query()
+	// curl --data sends any body as a form, so only one with a content field is taken as one
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == formMediaType {
+		if form, formErr := url.ParseQuery(string(data)); formErr == nil && form.Has("content") {
+			data = []byte(form.Get("content"))
+			for name, values := range form {
+				if !params.Has(name) {
+					params[name] = values
+				}
+			}
+		}
+	}
+
+	return data, params, true
+}
+
+// writeReadError responds to a request whose body could not be read, with 413 Request
+// Entity Too Large if it went over its limit.
+func writeReadError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// webUploadHandler returns the /api/validate/file handler, which accepts files of up to
+// maxSize bytes each in uploads of up to maxUpload bytes.
+func webUploadHandler(maxSize, maxUpload int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handleWebUpload(w, r, maxSize, maxUpload)
+	}
+}
+
+// handleWebUpload validates every file of a multipart/form-data upload and responds with
+// a JSON array of their results, in upload order. The format comes from the ?format=
+// parameter, or else is detected for each file from its name and content. A file over
+// maxSize bytes gets a too_large result, and an upload over maxUpload bytes is rejected
+// with 413 Request Entity Too Large.
+func handleWebUpload(w http.ResponseWriter, r *http.Request, maxSize, maxUpload int64) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = autoFormat
+	}
+	if format != autoFormat && !isBuiltinFormat(format) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported format: " + format})
+
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "expected a multipart/form-data upload"})
+
+		return
+	}
+
+	results := []ValidationResult{}
+	for {
+		part, partErr := reader.NextPart()
+		if errors.Is(partErr, io.EOF) {
+			break
+		}
+		if partErr != nil {
+			writeReadError(w, partErr)
+
+			return
+		}
+		name := part.FileName()
+		if name == "" {
+			// Form fields other than files are ignored
+			continue
+		}
+
+		// One byte over the limit is enough to know the file is too large
+		data, readErr := io.ReadAll(io.LimitReader(part, maxSize+1))
+		if readErr != nil {
+			writeReadError(w, readErr)
+
+			return
+		}
+		if result, tooLarge := sizeLimitResult(name, int64(len(data)), validateOptions{maxSize: maxSize}); tooLarge {
+			results = append(results, result)
+
+			continue
+		}
+		results = append(results, validateDataContext(r.Context(), data, name, format))
+	}
+	if len(results) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "no files uploaded"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// wsValidateRequest is a message sent to /ws/validate.
+type wsValidateRequest struct {
+	// ID is echoed in the response, so clients can ignore answers to stale content
+	ID       int64  `json:"id"`
+	Content  string `json:"content"`
+	Format   string `json:"format,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// wsValidateResponse answers a wsValidateRequest with its result, or an error for a
+// message that could not be validated.
+type wsValidateResponse struct {
+	ID     int64             `json:"id"`
+	Result *ValidationResult `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// webSocketValidateHandler returns the /ws/validate handler, which accepts messages of up
+// to maxSize bytes and waits for debounce without messages before validating.
+func webSocketValidateHandler(maxSize int64, debounce time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handleWebSocketValidate(w, r, maxSize, debounce)
+	}
+}
+
+// handleWebSocketValidate validates documents sent over a WebSocket as the user edits
+// them. Only the latest message is validated once none has arrived for debounce, so a
+// burst of keystrokes costs one validation.
+func handleWebSocketValidate(w http.ResponseWriter, r *http.Request, maxSize int64, debounce time.Duration) {
+	conn, err := upgradeWebSocket(w, r, maxSize)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	requests := make(chan wsValidateRequest)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(requests)
+		for {
+			message, readErr := conn.readMessage()
+			if readErr != nil {
+				return
+			}
+			var req wsValidateRequest
+			if readErr = json.Unmarshal(message, &req); readErr != nil {
+				_ = conn.writeJSON(wsValidateResponse{Error: "invalid message: " + readErr.Error()})
+
+				continue
+			}
+			select {
+			case requests <- req:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var pending *wsValidateRequest
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case req, ok := <-requests:
+			if !ok {
+				return
+			}
+			pending = &req
+			timer.Reset(debounce)
+		case <-timer.C:
+			if err = conn.writeJSON(validateWebSocketRequest(r.Context(), *pending)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// validateWebSocketRequest validates the content of req as the format it names, or as
+// detected from its filename and content.
+func validateWebSocketRequest(ctx context.Context, req wsValidateRequest) wsValidateResponse {
+	format := req.Format
+	if format == "" {
+		format = autoFormat
+	}
+	if format != autoFormat && !isBuiltinFormat(format) {
+		return wsValidateResponse{ID: req.ID, Error: "unsupported format: " + format}
+	}
+	result := validateDataContext(ctx, []byte(req.Content), req.Filename, format)
+
+	return wsValidateResponse{ID: req.ID, Result: &result}
+}
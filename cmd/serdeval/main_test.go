@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain runs the CLI itself when runCLI re-executes the test binary.
+func TestMain(m *testing.M) {
+	if os.Getenv("SERDEVAL_TEST_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI runs serdeval with args in dir and returns its exit code.
+func runCLI(t *testing.T, dir string, args ...string) int {
+	t.Helper()
+	_, code := runCLIOutput(t, dir, args...)
+
+	return code
+}
+
+// runCLIOutput runs serdeval with args in dir and returns its stdout and exit code.
+func runCLIOutput(t *testing.T, dir string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...) // #nosec G204 - the test binary re-executes itself
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "SERDEVAL_TEST_MAIN=1", "NO_COLOR=1")
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return string(out), 0
+	case errors.As(err, &exitErr):
+		return string(out), exitErr.ExitCode()
+	}
+	t.Fatalf("running serdeval %v: %v", args, err)
+
+	return "", -1
+}
+
+func TestValidateExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"good.json":      `{"a": 1}`,
+		"bad.json":       `{"a": 1,}`,
+		"warn.yaml":      "a:\t1\n",
+		".serdeval.yaml": "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outputs := []string{"text", "json", "ndjson", "csv", "tsv", "tap"}
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "valid", args: []string{"good.json"}, want: 0},
+		{name: "invalid", args: []string{"good.json", "bad.json"}, want: exitInvalid},
+		{name: "fail fast", args: []string{"--fail-fast", "bad.json", "good.json"}, want: exitInvalid},
+		{name: "missing", args: []string{"missing.json"}, want: exitUnvalidated},
+		{name: "invalid beats missing", args: []string{"missing.json", "bad.json"}, want: exitInvalid},
+		{name: "warnings", args: []string{"warn.yaml"}, want: 0},
+		{name: "warnings as errors", args: []string{"--warnings-as-errors", "warn.yaml"}, want: exitWarnings},
+		{name: "exit zero", args: []string{"--exit-zero", "bad.json"}, want: 0},
+	}
+	for _, tt := range tests {
+		for _, output := range outputs {
+			t.Run(tt.name+"/"+output, func(t *testing.T) {
+				args := append([]string{"validate", "--quiet", "--output", output}, tt.args...)
+				if got := runCLI(t, dir, args...); got != tt.want {
+					t.Errorf("serdeval %v exited %d, want %d", args, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateTemplateOutput(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "good.json"), []byte(`{"a": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "text", args: []string{"--output", "text"}, want: 0},
+		{name: "json", args: []string{"--output", "json"}, want: 1},
+		{name: "json shorthand", args: []string{"--json"}, want: 1},
+		{name: "ndjson", args: []string{"--output", "ndjson"}, want: 1},
+		{name: "csv", args: []string{"--output", "csv"}, want: 1},
+		{name: "tsv", args: []string{"--output", "tsv"}, want: 1},
+		{name: "tap", args: []string{"--output", "tap"}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"validate", "--template", "{{.FileName}}"}, tt.args...)
+			args = append(args, "good.json")
+			if got := runCLI(t, dir, args...); got != tt.want {
+				t.Errorf("serdeval %v exited %d, want %d", args, got, tt.want)
+			}
+		})
+	}
+}
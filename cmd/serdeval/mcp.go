@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// mcpProtocolVersion is the Model Context Protocol revision this server implements.
+const mcpProtocolVersion = "2024-11-05"
+
+// mcpTool describes a tool in the tools/list response.
+type mcpTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// mcpContent is a single content block of a tools/call result.
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// mcpToolResult is the result of a tools/call request.
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+// mcpToolArgs are the arguments accepted by serdeval's tools.
+type mcpToolArgs struct {
+	Content  string `json:"content"`
+	Format   string `json:"format,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	// To is the target format of the convert tool
+	To string `json:"to,omitempty"`
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name: "validate",
+		Description: "Validate a document locally. Returns a JSON result with valid, format, and error. " +
+			"The format is detected from filename or content when omitted.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"content":{"type":"string","description":"Document to validate"},` +
+			`"format":{"type":"string","description":"Format name, e.g. json, yaml, toml (default auto)"},` +
+			`"filename":{"type":"string","description":"Optional file name used for format detection"}},` +
+			`"required":["content"]}`),
+	},
+	{
+		Name:        "detect",
+		Description: "Detect the format of a document from its filename and/or content.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"content":{"type":"string","description":"Document to inspect"},` +
+			`"filename":{"type":"string","description":"Optional file name used for format detection"}},` +
+			`"required":["content"]}`),
+	},
+	{
+		Name: "convert",
+		Description: "Convert a document between JSON, YAML, TOML, and XML after validating it. " +
+			"Returns a JSON result with the target format and the converted content.",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{` +
+			`"content":{"type":"string","description":"Document to convert"},` +
+			`"to":{"type":"string","enum":["json","yaml","toml","xml"],"description":"Target format"},` +
+			`"format":{"type":"string","description":"Source format: json, yaml, toml, or xml (default auto)"},` +
+			`"filename":{"type":"string","description":"Optional file name used for format detection"}},` +
+			`"required":["content","to"]}`),
+	},
+}
+
+func runMCP(cmd *cobra.Command, args []string) {
+	if err := serveJSONRPC(os.Stdin, os.Stdout, handleMCP); err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handleMCP dispatches Model Context Protocol methods.
+func handleMCP(method string, params json.RawMessage) (interface{}, *rpcError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "serdeval", "version": Version},
+		}, nil
+	case "ping", "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": mcpTools}, nil
+	case "tools/call":
+		var call struct {
+			Name      string      `json:"name"`
+			Arguments mcpToolArgs `json:"arguments"`
+		}
+		if err := decodeParams(params, &call); err != nil {
+			return nil, err
+		}
+
+		return callMCPTool(call.Name, call.Arguments)
+	}
+
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + method}
+}
+
+// callMCPTool runs a tool and wraps its JSON output as text content.
+// An invalid document is a successful tool call; isError is reserved for calls
+// the tool could not perform.
+func callMCPTool(name string, args mcpToolArgs) (interface{}, *rpcError) {
+	var out interface{}
+
+	switch name {
+	case "validate":
+		format := args.Format
+		if format == "" {
+			format = string(serdeval.FormatAuto)
+		}
+		out = validateData([]byte(args.Content), args.Filename, format)
+	case "detect":
+		out = map[string]string{"format": string(detectFormat([]byte(args.Content), args.Filename))}
+	case "convert":
+		converted, err := convertData([]byte(args.Content), args.Filename, args.Format, args.To)
+		if err != nil {
+			return mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		out = map[string]string{"format": args.To, "content": string(converted)}
+	default:
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: "unknown tool: " + name}}, IsError: true}, nil
+	}
+
+	text, _ := json.Marshal(out)
+
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(text)}}}, nil
+}
+
+// detectFormat detects a format from the filename first, then from content.
+func detectFormat(data []byte, filename string) serdeval.Format {
+	if filename != "" {
+		if format := serdeval.DetectFormatFromFilename(filename); format != serdeval.FormatUnknown {
+			return format
+		}
+	}
+
+	return serdeval.DetectFormat(data)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+// minifyFormats are the formats minify compacts.
+var minifyFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatXML}
+
+func runMinify(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	write, _ := cmd.Flags().GetBool("write")
+
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+		out, err := convert.Minify(data, sourceFormat(format, "", minifyFormats))
+		if err != nil {
+			printDocumentError("stdin", err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(append(out, '\n'))
+
+		return
+	}
+
+	failed := false
+	for _, name := range args {
+		fileFormat := sourceFormat(format, name, minifyFormats)
+		_, err := rewriteFile(name, func(data []byte) ([]byte, error) {
+			out, err := convert.Minify(data, fileFormat)
+			if err != nil || write {
+				return out, err
+			}
+
+			// Printed documents each end their own line
+			return append(out, '\n'), nil
+		}, write, false)
+		if err != nil {
+			printDocumentError(name, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// Output formats accepted by --output
+const (
+	outputText   = "text"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+	outputCSV    = "csv"
+	outputTSV    = "tsv"
+	outputTAP    = "tap"
+)
+
+var (
+	// lineRe and columnRe pull positions out of parser error messages,
+	// e.g. "yaml: line 3: ..." or "invalid JSON on line 2".
+	lineRe   = regexp.MustCompile(`\bline (\d+)`)
+	columnRe = regexp.MustCompile(`\bcolumn (\d+)`)
+)
+
+// isValidOutput reports whether the given --output value is supported.
+func isValidOutput(output string) bool {
+	switch output {
+	case outputText, outputJSON, outputNDJSON, outputCSV, outputTSV, outputTAP:
+		return true
+	}
+
+	return false
+}
+
+// Exit codes of the validate command. When results differ, the lowest nonzero code wins,
+// so an invalid file is never hidden behind an unreadable one.
+const (
+	// exitInvalid means a file failed to parse or broke its schema
+	exitInvalid = 1
+	// exitUnvalidated means a file could not be checked: unreadable, binary, or in an
+	// unknown or unsupported format
+	exitUnvalidated = 2
+	// exitWarnings means a valid file had warnings, under --warnings-as-errors
+	exitWarnings = 3
+)
+
+// unvalidatedCodes are the result codes of files that could not be checked at all.
+var unvalidatedCodes = []string{
+	codeAccessError, codeReadError, codeWalkError, codeUnsupportedFormat, codeNetworkDisabled,
+	string(serdeval.ErrCodeUnknownFormat), string(serdeval.ErrCodeBinary),
+}
+
+// exitPolicy holds the flags that turn results into an exit code.
+type exitPolicy struct {
+	// warningsAsErrors fails valid files that have warnings
+	warningsAsErrors bool
+	// exitZero reports results but always exits 0
+	exitZero bool
+}
+
+// exitCodeFor returns the exit code for results under the policy, 0 when none failed.
+// Skipped results do not fail the run.
+func (p exitPolicy) exitCodeFor(results []ValidationResult) int {
+	code := 0
+	for _, result := range results {
+		if c := p.resultCode(result); c != 0 && (code == 0 || c < code) {
+			code = c
+		}
+	}
+	if p.exitZero {
+		return 0
+	}
+
+	return code
+}
+
+// resultCode returns the exit code one result calls for, 0 when it passes.
+func (p exitPolicy) resultCode(result ValidationResult) int {
+	switch {
+	case isFailure(result) && slices.Contains(unvalidatedCodes, result.Code):
+		return exitUnvalidated
+	case isFailure(result):
+		return exitInvalid
+	case p.warningsAsErrors && result.Valid && len(result.Warnings) > 0:
+		return exitWarnings
+	}
+
+	return 0
+}
+
+// isFailure reports whether a result should count as a failed validation.
+func isFailure(result ValidationResult) bool {
+	return !result.Valid && !result.Skipped
+}
+
+// writeNDJSON writes v as a single line of JSON.
+// Encoding errors are ignored, matching the buffered JSON output.
+func writeNDJSON(w io.Writer, v interface{}) {
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// parseResultTemplate parses a user-supplied --template string.
+// A trailing newline is appended when missing so each result renders on its own line.
+func parseResultTemplate(text string) (*template.Template, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	return template.New("result").Option("missingkey=error").Parse(text)
+}
+
+// printTemplateResult renders a single result through the user template.
+// Valid results are suppressed in quiet mode, mirroring printResult.
+func printTemplateResult(tmpl *template.Template, result ValidationResult, quiet bool) error {
+	if quiet && result.Valid {
+		return nil
+	}
+
+	return tmpl.Execute(os.Stdout, result)
+}
+
+// writeCSVResults writes one row per result with a header row first, separated by comma.
+// Line and column come from the first diagnostic, falling back to the error message.
+func writeCSVResults(w io.Writer, results []ValidationResult, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write([]string{
+		"filename", "format", "valid", "error_code", "error_message", "line", "column", "suggestion",
+	}); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		line, column := resultPosition(result)
+		row := []string{
+			result.FileName,
+			result.Format,
+			strconv.FormatBool(result.Valid),
+			result.Code,
+			result.Error,
+			positionField(line),
+			positionField(column),
+			result.Suggestion,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// resultPosition returns the line and column of the first failure, or 0 when unknown.
+func resultPosition(result ValidationResult) (line, column int) {
+	if len(result.Diagnostics) > 0 {
+		return result.Diagnostics[0].Line, result.Diagnostics[0].Column
+	}
+	line, _ = strconv.Atoi(firstSubmatch(lineRe, result.Error))
+	column, _ = strconv.Atoi(firstSubmatch(columnRe, result.Error))
+
+	return line, column
+}
+
+// diagnosticLocation formats name:line:column, leaving out unknown parts.
+func diagnosticLocation(name string, line, column int) string {
+	if line > 0 {
+		name += ":" + strconv.Itoa(line)
+		if column > 0 {
+			name += ":" + strconv.Itoa(column)
+		}
+	}
+
+	return name
+}
+
+// positionField formats a line or column for CSV output, leaving unknown positions empty.
+func positionField(n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(n)
+}
+
+// firstSubmatch returns the first capture group of re in s, or "" if it does not match.
+func firstSubmatch(re *regexp.Regexp, s string) string {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+
+	return m[1]
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+	"github.com/akhilesharora/serdeval/convert"
+)
+
+// patchFormats are the formats of the documents patch edits.
+var patchFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML}
+
+func runPatch(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	write, _ := cmd.Flags().GetBool("write")
+
+	patchName, name := args[0], "stdin"
+	patch, err := os.ReadFile(patchName) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Cannot read %s: %v\n", patchName, err)
+		os.Exit(1)
+	}
+	if len(args) == 1 && write {
+		_, _ = red.Fprintln(os.Stderr, "--write needs a file to patch")
+		os.Exit(1)
+	}
+
+	var doc []byte
+	if len(args) == 1 {
+		doc, err = io.ReadAll(os.Stdin)
+	} else {
+		name = args[1]
+		doc, err = os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	}
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Cannot read %s: %v\n", name, err)
+		os.Exit(1)
+	}
+
+	out, err := convert.ApplyAs(doc, sourceFormat(format, name, patchFormats), patch)
+	var docErr *convert.DocumentError
+	if errors.As(err, &docErr) {
+		printDocumentError([]string{name, patchName}[docErr.Index], docErr.Err)
+		os.Exit(1)
+	}
+	if err != nil {
+		printDocumentError(patchName, err)
+		os.Exit(1)
+	}
+
+	if !write {
+		_, _ = os.Stdout.Write(out)
+
+		return
+	}
+	info, err := os.Stat(name)
+	if err == nil {
+		err = os.WriteFile(name, out, info.Mode().Perm())
+	}
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Cannot write %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// pluginPrefix starts the names of executables on PATH that add a format to the CLI:
+// serdeval-rego validates --format rego and, unless a built-in format claims it, .rego files.
+const pluginPrefix = "serdeval-"
+
+// pluginsAnnotation marks the commands that validate with plugins, which loadPlugins
+// registers before they run. Other commands never read PATH for them.
+const pluginsAnnotation = "serdeval/plugins"
+
+// pluginFormats holds the formats registerPlugins registered
+var pluginFormats = map[serdeval.Format]bool{}
+
+// pluginsOnce makes loadPlugins search PATH at most once
+var pluginsOnce sync.Once
+
+// pluginFormatPattern matches the format names plugins may register
+var pluginFormatPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// pluginValidator validates a format by running its plugin executable with the data on stdin.
+//
+// A plugin reports its verdict either as a JSON Result on stdout, the same shape as one entry
+// of `serdeval validate --json` ({"valid": false, "error": "...", "diagnostics": [...]}),
+// or through its exit status: zero for valid, non-zero for invalid with the reason on stderr.
+type pluginValidator struct {
+	format serdeval.Format
+	path   string
+	// exts are the file extensions DetectFormatFromFilename maps to the plugin
+	exts []string
+}
+
+// Format returns the format the plugin validates.
+func (v *pluginValidator) Format() serdeval.Format {
+	return v.format
+}
+
+// Extensions returns the extension named after the format, unless a built-in format uses it.
+func (v *pluginValidator) Extensions() []string {
+	return v.exts
+}
+
+// Validate runs the plugin on data.
+func (v *pluginValidator) Validate(data []byte) serdeval.Result {
+	return v.ValidateContext(context.Background(), data)
+}
+
+// ValidateString runs the plugin on data.
+func (v *pluginValidator) ValidateString(data string) serdeval.Result {
+	return v.Validate([]byte(data))
+}
+
+// ValidateContext runs the plugin on data, killing it if ctx is done first.
+func (v *pluginValidator) ValidateContext(ctx context.Context, data []byte) serdeval.Result {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, v.path) // #nosec G204 - plugins are executables the user put on PATH
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		msg := fmt.Sprintf("skipped: canceled (%v)", ctx.Err())
+
+		return serdeval.Result{
+			Skipped: true,
+			Format:  v.format,
+			Error:   msg,
+			Err:     &serdeval.ValidationError{Code: serdeval.ErrCodeCanceled, Message: msg},
+		}
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	var result serdeval.Result
+	if bytes.HasPrefix(out, []byte("{")) && json.Unmarshal(out, &result) == nil {
+		result.Format = v.format
+		if !result.Valid && result.Error == "" {
+			result.Error = "invalid " + string(v.format)
+		}
+
+		return result
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil:
+		return serdeval.Result{Valid: true, Format: v.format}
+	case errors.As(runErr, &exitErr):
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = string(out)
+		}
+		if msg == "" {
+			msg = fmt.Sprintf("%s exited with status %d", filepath.Base(v.path), exitErr.ExitCode())
+		}
+
+		return serdeval.Result{Format: v.format, Error: msg}
+	}
+
+	return serdeval.Result{Format: v.format, Error: fmt.Sprintf("running plugin %s: %v", v.path, runErr)}
+}
+
+// usePlugins marks cmds as validating with plugins.
+func usePlugins(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = map[string]string{}
+		}
+		cmd.Annotations[pluginsAnnotation] = "true"
+	}
+}
+
+// loadPlugins registers the plugins on PATH the first time it is called, when a command
+// that validates with them starts or a format is not otherwise known.
+func loadPlugins() {
+	pluginsOnce.Do(registerPlugins)
+}
+
+// registerPlugins registers a validator for every plugin on PATH whose format is not built in,
+// so plugin formats work with --format, detection by extension, and every command that loads
+// them.
+func registerPlugins() {
+	builtin := make(map[serdeval.Format]bool)
+	for _, format := range serdeval.SupportedFormats() {
+		builtin[format] = true
+	}
+
+	for format, path := range findPlugins(os.Getenv("PATH")) {
+		if builtin[format] {
+			continue
+		}
+		v := &pluginValidator{format: format, path: path}
+		if serdeval.DetectFormatFromFilename("file."+string(format)) == serdeval.FormatUnknown {
+			v.exts = []string{string(format)}
+		}
+		serdeval.Register(format, func() serdeval.Validator { return v })
+		pluginFormats[format] = true
+	}
+}
+
+// findPlugins returns the plugin executables in the directories of pathList by format.
+// As with command lookup, a plugin in an earlier directory hides one of the same name later.
+func findPlugins(pathList string) map[serdeval.Format]string {
+	plugins := make(map[serdeval.Format]string)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+			}
+			format := serdeval.Format(strings.TrimPrefix(name, pluginPrefix))
+			if !strings.HasPrefix(name, pluginPrefix) || !pluginFormatPattern.MatchString(string(format)) ||
+				format == serdeval.FormatAuto || format == serdeval.FormatUnknown || plugins[format] != "" {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if isExecutable(path) {
+				plugins[format] = path
+			}
+		}
+	}
+
+	return plugins
+}
+
+// isExecutable reports whether path, after following symlinks, is a file the user can run.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// zeroSHA is the object name git uses for a created or deleted ref
+	zeroSHA = "0000000000000000000000000000000000000000"
+	// emptyTreeSHA is the well-known object name of the empty tree
+	emptyTreeSHA = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+)
+
+// changedBlob is a file added or modified by a push.
+type changedBlob struct {
+	sha  string
+	path string
+}
+
+func runPreReceive(cmd *cobra.Command, args []string) {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	blobs, err := pushedBlobs(os.Stdin)
+	if err != nil {
+		_, _ = red.Fprintf(os.Stderr, "serdeval: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, blob := range blobs {
+		data, err := gitOutput("cat-file", "blob", blob.sha)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "serdeval: cannot read %s: %v\n", blob.path, err)
+			os.Exit(1)
+		}
+
+		result := validateData(data, blob.path, "auto")
+		if !result.Valid {
+			failed++
+		}
+		// git relays hook stderr to the pushing client
+		printResultTo(os.Stderr, result, quiet)
+	}
+
+	if failed > 0 {
+		_, _ = red.Fprintf(os.Stderr, "serdeval: push rejected, %d invalid file(s)\n", failed)
+		os.Exit(1)
+	}
+}
+
+// pushedBlobs reads pre-receive ref update lines ("<old> <new> <ref>") and returns
+// every validatable blob added or modified by the pushed commits.
+func pushedBlobs(r io.Reader) ([]changedBlob, error) {
+	seen := make(map[string]bool)
+	var blobs []changedBlob
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		oldSHA, newSHA := fields[0], fields[1]
+		if newSHA == zeroSHA {
+			// Ref deletion: nothing to validate
+			continue
+		}
+
+		base := oldSHA
+		if oldSHA == zeroSHA {
+			var err error
+			if base, err = newRefBase(newSHA); err != nil {
+				return nil, err
+			}
+		}
+
+		changed, err := changedBlobs(base, newSHA)
+		if err != nil {
+			return nil, err
+		}
+		for _, blob := range changed {
+			key := blob.sha + "\x00" + blob.path
+			if !seen[key] && isValidatableFile(blob.path, "auto") {
+				seen[key] = true
+				blobs = append(blobs, blob)
+			}
+		}
+	}
+
+	return blobs, scanner.Err()
+}
+
+// newRefBase picks the commit to diff a newly created ref against: the parent of
+// the oldest commit not reachable from any existing ref, or the empty tree.
+func newRefBase(newSHA string) (string, error) {
+	out, err := gitOutput("rev-list", "--topo-order", "--reverse", newSHA, "--not", "--all")
+	if err != nil {
+		return "", err
+	}
+	commits := strings.Fields(string(out))
+	if len(commits) == 0 {
+		// Every commit is already known (e.g. a new tag on an existing commit)
+		return newSHA, nil
+	}
+
+	parent, err := gitOutput("rev-parse", "--verify", "--quiet", commits[0]+"^")
+	if err != nil {
+		return emptyTreeSHA, nil
+	}
+
+	return strings.TrimSpace(string(parent)), nil
+}
+
+// changedBlobs lists blobs added or modified between two tree-ishes (renames count as additions).
+func changedBlobs(base, head string) ([]changedBlob, error) {
+	out, err := gitOutput("diff-tree", "-r", "-z", "--no-renames", "--diff-filter=AM", base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRawDiff(out), nil
+}
+
+// parseRawDiff returns the regular file blobs in the -z raw output of git diff-tree or
+// diff-index, taking the new side of each entry.
+func parseRawDiff(out []byte) []changedBlob {
+	// -z output is ":<old mode> <new mode> <old sha> <new sha> <status>\0<path>\0" per entry
+	var blobs []changedBlob
+	parts := bytes.Split(out, []byte{0})
+	for i := 0; i+1 < len(parts); i += 2 {
+		meta := strings.Fields(strings.TrimPrefix(string(parts[i]), ":"))
+		if len(meta) < 5 || !strings.HasPrefix(meta[1], "100") {
+			// Skip submodules and symlinks
+			continue
+		}
+		blobs = append(blobs, changedBlob{sha: meta[3], path: string(parts[i+1])})
+	}
+
+	return blobs
+}
+
+// gitOutput runs git with args and returns its stdout.
+func gitOutput(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
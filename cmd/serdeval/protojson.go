@@ -0,0 +1,40 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// protoMessageOptions turns --proto-descriptor-set and --proto-message into validator options,
+// along with a fingerprint of the descriptor set for the result cache.
+func protoMessageOptions(format, descriptorPath, message string) ([]serdeval.Option, string, error) {
+	if descriptorPath == "" && message == "" {
+		return nil, "", nil
+	}
+	if descriptorPath == "" || message == "" {
+		return nil, "", errors.New("--proto-descriptor-set and --proto-message must be used together")
+	}
+	if format != string(serdeval.FormatProtoJSON) && format != string(serdeval.FormatProtobuf) {
+		return nil, "", errors.New("--proto-message requires --format protojson or protobuf")
+	}
+
+	set, err := os.ReadFile(descriptorPath) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return nil, "", err
+	}
+	opts := []serdeval.Option{serdeval.WithProtoMessage(set, message)}
+
+	// Fail before reading any input if the message cannot be resolved
+	if _, err = serdeval.NewValidator(serdeval.Format(format), opts...); err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(set)
+
+	return opts, fmt.Sprintf("%s@%s", message, hex.EncodeToString(sum[:])), nil
+}
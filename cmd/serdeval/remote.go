@@ -0,0 +1,543 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Scheme  = "s3://"
+	gcsScheme = "gs://"
+
+	// remoteTimeout bounds every request to an object store
+	remoteTimeout = 60 * time.Second
+)
+
+// remoteClient is shared by all object store requests.
+var remoteClient = &http.Client{Timeout: remoteTimeout}
+
+// isRemotePath reports whether arg names an object store location.
+func isRemotePath(arg string) bool {
+	return strings.HasPrefix(arg, s3Scheme) || strings.HasPrefix(arg, gcsScheme)
+}
+
+// objectStore lists and fetches objects from one bucket.
+type objectStore interface {
+	list(ctx context.Context, prefix string) ([]string, error)
+	get(ctx context.Context, key string, maxSize int64) ([]byte, bool, error)
+}
+
+// validateRemote validates a single object, or every validatable object under a
+// prefix when the key is empty or ends with "/".
+func validateRemote(arg string, opts validateOptions, emit func(ValidationResult)) {
+	if !opts.allowNetwork {
+		emit(ValidationResult{
+			Valid:    false,
+			Format:   "unknown",
+			Code:     codeNetworkDisabled,
+			Error:    "network access is disabled; pass --allow-network to read object storage",
+			FileName: arg,
+		})
+
+		return
+	}
+
+	ctx := context.Background()
+	store, bucket, key := parseRemotePath(arg)
+	scheme := arg[:strings.Index(arg, "://")+3]
+
+	if key != "" && !strings.HasSuffix(key, "/") {
+		emit(validateObject(ctx, store, arg, key, opts))
+
+		return
+	}
+
+	keys, err := store.list(ctx, key)
+	if err != nil {
+		emit(ValidationResult{
+			Valid:    false,
+			Format:   "unknown",
+			Code:     codeReadError,
+			Error:    fmt.Sprintf("Cannot list objects: %v", err),
+			FileName: arg,
+		})
+
+		return
+	}
+	for _, k := range keys {
+		if opts.stopped() {
+			return
+		}
+		if strings.HasSuffix(k, "/") || !isValidatableFile(k, opts.format) {
+			continue
+		}
+		emit(validateObject(ctx, store, scheme+bucket+"/"+k, k, opts))
+	}
+}
+
+// validateObject downloads and validates one object.
+func validateObject(ctx context.Context, store objectStore, name, key string, opts validateOptions) ValidationResult {
+	limit := readLimit(opts)
+	data, tooLarge, err := store.get(ctx, key, limit)
+	if err != nil {
+		return ValidationResult{
+			Valid:    false,
+			Format:   "unknown",
+			Code:     codeReadError,
+			Error:    fmt.Sprintf("Cannot read object: %v", err),
+			FileName: name,
+		}
+	}
+	if tooLarge {
+		result, _ := sizeLimitResult(name, limit+1, opts)
+
+		return result
+	}
+
+	return validateCached(data, name, opts)
+}
+
+// parseRemotePath splits s3://bucket/key or gs://bucket/key into a store, bucket, and key.
+func parseRemotePath(arg string) (objectStore, string, string) {
+	rest := arg
+	isGCS := strings.HasPrefix(arg, gcsScheme)
+	if isGCS {
+		rest = strings.TrimPrefix(rest, gcsScheme)
+	} else {
+		rest = strings.TrimPrefix(rest, s3Scheme)
+	}
+
+	bucket, key, _ := strings.Cut(rest, "/")
+	if isGCS {
+		return newGCSStore(bucket), bucket, key
+	}
+
+	return newS3Store(bucket), bucket, key
+}
+
+// readLimited reads body, reporting whether it exceeded maxSize (0 means no limit).
+func readLimited(body io.Reader, maxSize int64) ([]byte, bool, error) {
+	if maxSize <= 0 {
+		data, err := io.ReadAll(body)
+
+		return data, false, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, true, nil
+	}
+
+	return data, false, nil
+}
+
+// doRequest sends req and returns the response, turning non-2xx statuses into errors.
+func doRequest(req *http.Request) (*http.Response, error) {
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
+
+// s3Store talks to S3 (or an S3-compatible endpoint) using Signature Version 4.
+// Credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and optionally
+// AWS_SESSION_TOKEN; requests are unsigned when no credentials are set.
+// AWS_ENDPOINT_URL selects a custom endpoint with path-style addressing. Buckets whose
+// names contain dots are addressed path-style too, as their virtual-hosted names do not
+// match the wildcard certificate of S3.
+type s3Store struct {
+	bucket       string
+	region       string
+	endpoint     string
+	pathStyle    bool
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+func newS3Store(bucket string) *s3Store {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	s := &s3Store{
+		bucket:       bucket,
+		region:       region,
+		endpoint:     fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if strings.Contains(bucket, ".") {
+		s.endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+		s.pathStyle = true
+	}
+	if endpoint := os.Getenv("AWS_ENDPOINT_URL"); endpoint != "" {
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+		s.pathStyle = true
+	}
+
+	return s
+}
+
+// objectURL builds the request URL for key with the given query.
+func (s *s3Store) objectURL(key string, query url.Values) *url.URL {
+	path := "/" + key
+	if s.pathStyle {
+		path = "/" + s.bucket + path
+	}
+
+	u, _ := url.Parse(s.endpoint)
+	u.Path = path
+	u.RawPath = uriEncode(path, false)
+	u.RawQuery = canonicalQuery(query)
+
+	return u
+}
+
+func (s *s3Store) newRequest(ctx context.Context, key string, query url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key, query).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, time.Now().UTC())
+
+	return req, nil
+}
+
+// s3ListResult is the subset of the ListObjectsV2 response that is used.
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *s3Store) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := s.newRequest(ctx, "", query)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := doRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var page s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, obj.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (s *s3Store) get(ctx context.Context, key string, maxSize int64) ([]byte, bool, error) {
+	req, err := s.newRequest(ctx, key, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := doRequest(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		return nil, true, nil
+	}
+
+	return readLimited(resp.Body, maxSize)
+}
+
+// emptyPayloadHash is the SHA-256 of an empty body, which every request to S3 has.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds AWS Signature Version 4 headers to req. Requests stay anonymous
+// when no credentials are configured, which works for public buckets.
+func (s *s3Store) sign(req *http.Request, now time.Time) {
+	if s.accessKey == "" || s.secretKey == "" {
+		return
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	signer := sigV4{service: "s3", region: s.region, accessKey: s.accessKey, secretKey: s.secretKey}
+	signer.sign(req, now, emptyPayloadHash)
+}
+
+// sigV4 signs requests to one AWS service and region with Signature Version 4.
+type sigV4 struct {
+	service   string
+	region    string
+	accessKey string
+	secretKey string
+}
+
+// sign sets the X-Amz-Date and Authorization headers of req, signing its host, every
+// header already set, and a body whose SHA-256 is payloadHash.
+func (v sigV4) sign(req *http.Request, now time.Time, payloadHash string) {
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	canonical, signedHeaders := canonicalRequest(req, payloadHash)
+	signature := v.signature(now, v.stringToSign(now, canonical))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		v.accessKey, v.scope(now), signedHeaders, signature))
+}
+
+// scope is the credential scope of a signature made at now.
+func (v sigV4) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + v.region + "/" + v.service + "/aws4_request"
+}
+
+// stringToSign is what is signed for the canonical request of a request made at now.
+func (v sigV4) stringToSign(now time.Time, canonical string) string {
+	return strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		v.scope(now),
+		hexSHA256([]byte(canonical)),
+	}, "\n")
+}
+
+// signature signs stringToSign with the key derived for the scope of now.
+func (v sigV4) signature(now time.Time, stringToSign string) string {
+	key := hmacSHA256([]byte("AWS4"+v.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, v.region)
+	key = hmacSHA256(key, v.service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalRequest returns the SigV4 canonical request of req, covering its host and
+// headers, and the semicolon-separated names of the headers it signs.
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		// Values are trimmed, with inner runs of spaces collapsed
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	return strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// gcsStore talks to Google Cloud Storage through the JSON API.
+// GOOGLE_OAUTH_ACCESS_TOKEN supplies a bearer token (e.g. from
+// `gcloud auth print-access-token`); public buckets work without one.
+// STORAGE_EMULATOR_HOST points requests at a local emulator.
+type gcsStore struct {
+	bucket   string
+	endpoint string
+	token    string
+}
+
+func newGCSStore(bucket string) *gcsStore {
+	endpoint := "https://storage.googleapis.com"
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		endpoint = strings.TrimSuffix(host, "/")
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+	}
+
+	return &gcsStore{bucket: bucket, endpoint: endpoint, token: os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")}
+}
+
+func (g *gcsStore) newRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	return req, nil
+}
+
+func (g *gcsStore) list(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	pageToken := ""
+
+	for {
+		query := url.Values{"prefix": {prefix}, "fields": {"items(name),nextPageToken"}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		rawURL := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", g.endpoint, url.PathEscape(g.bucket), query.Encode())
+		req, err := g.newRequest(ctx, rawURL)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := doRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			keys = append(keys, item.Name)
+		}
+		if page.NextPageToken == "" {
+			return keys, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (g *gcsStore) get(ctx context.Context, key string, maxSize int64) ([]byte, bool, error) {
+	rawURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", g.endpoint, url.PathEscape(g.bucket), url.PathEscape(key))
+	req, err := g.newRequest(ctx, rawURL)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := doRequest(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		return nil, true, nil
+	}
+
+	return readLimited(resp.Body, maxSize)
+}
+
+// uriEncode percent-encodes s as required by SigV4: every byte except unreserved
+// characters, and '/' unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+// canonicalQuery encodes query sorted by key with SigV4 escaping.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
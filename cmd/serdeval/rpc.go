@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// rpcDocumentParams are the params accepted by the rpc validate and detect methods.
+type rpcDocumentParams struct {
+	Content  string `json:"content"`
+	Format   string `json:"format,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+func runRPC(cmd *cobra.Command, args []string) {
+	if err := serveJSONRPC(os.Stdin, os.Stdout, handleRPC); err != nil {
+		_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handleRPC dispatches the lightweight editor protocol:
+//
+//	validate {content, format?, filename?} -> result object as in --json output
+//	detect   {content, filename?}          -> {"format": "..."}
+//	version                                -> {"version": "..."}
+//	ping                                   -> {}
+func handleRPC(method string, params json.RawMessage) (interface{}, *rpcError) {
+	switch method {
+	case "validate":
+		var p rpcDocumentParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Format == "" {
+			p.Format = string(serdeval.FormatAuto)
+		}
+
+		return validateData([]byte(p.Content), p.Filename, p.Format), nil
+	case "detect":
+		var p rpcDocumentParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+
+		return map[string]string{"format": string(detectFormat([]byte(p.Content), p.Filename))}, nil
+	case "version":
+		return map[string]string{"version": Version}, nil
+	case "ping":
+		return nil, nil
+	}
+
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + method}
+}
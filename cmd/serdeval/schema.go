@@ -0,0 +1,27 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// resultsSchema is the JSON Schema describing the validate command's JSON output.
+// Bump the file name (results.v2.json) for any incompatible change.
+//
+//go:embed schema/results.v1.json
+var resultsSchema string
+
+func printSchema(cmd *cobra.Command, args []string) {
+	fmt.Print(resultsSchema)
+}
+
+// sortResults orders results by filename so output is stable regardless of
+// argument order, directory walk order, or concurrency.
+func sortResults(results []ValidationResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].FileName < results[j].FileName
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// statsFormats are the formats stats reads.
+var statsFormats = []serdeval.Format{
+	serdeval.FormatJSON, serdeval.FormatJSONL, serdeval.FormatYAML, serdeval.FormatTOML,
+	serdeval.FormatCSV, serdeval.FormatTSV,
+}
+
+// statsResult is one file's entry in the --json output of stats.
+type statsResult struct {
+	Filename string          `json:"filename"`
+	Stats    *serdeval.Stats `json:"stats,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func runStats(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var results []statsResult
+	failed := false
+	report := func(name string, stats *serdeval.Stats, err error) {
+		if err != nil {
+			failed = true
+		}
+		if jsonOutput {
+			result := statsResult{Filename: name, Stats: stats}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+
+			return
+		}
+		if err != nil {
+			printDocumentError(name, err)
+
+			return
+		}
+		writeStats(os.Stdout, name, stats)
+	}
+
+	if len(args) == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			_, _ = red.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+		stats, err := serdeval.ComputeStats(data, sourceFormat(format, "", statsFormats))
+		report("stdin", stats, err)
+	}
+	for _, name := range args {
+		stats, err := statsFile(name, sourceFormat(format, name, statsFormats))
+		report(name, stats, err)
+	}
+
+	if jsonOutput {
+		out, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(out))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// statsFile returns the stats of the file name.
+func statsFile(name string, format serdeval.Format) (*serdeval.Stats, error) {
+	data, err := os.ReadFile(name) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return nil, err
+	}
+
+	return serdeval.ComputeStats(data, format)
+}
+
+// writeStats prints stats for the file name as an aligned block, leaving out the lines
+// that do not apply to its format.
+func writeStats(w io.Writer, name string, stats *serdeval.Stats) {
+	_, _ = cyan.Fprintf(w, "%s (%s)\n", name, stats.Format)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(label, format string, a ...any) {
+		_, _ = fmt.Fprintf(tw, "  %s\t"+format+"\n", append([]any{label}, a...)...)
+	}
+
+	switch stats.Format {
+	case serdeval.FormatCSV, serdeval.FormatTSV:
+		row("rows", "%d", stats.Rows)
+		row("columns", "%d", stats.Columns)
+		row("empty cells", "%d", stats.EmptyCells)
+	default:
+		if stats.Format == serdeval.FormatJSONL {
+			row("records", "%d", stats.Documents)
+		} else if stats.Documents > 1 {
+			row("documents", "%d", stats.Documents)
+		}
+		row("max depth", "%d", stats.MaxDepth)
+		row("objects", "%d (%d keys, %d unique, at most %d in one object)",
+			stats.Objects, stats.Keys, stats.UniqueKeys, stats.MaxKeys)
+		if stats.Arrays > 0 {
+			row("arrays", "%d (%d items, lengths %d to %d)",
+				stats.Arrays, stats.ArrayItems, stats.MinArrayLength, stats.MaxArrayLength)
+		} else {
+			row("arrays", "0")
+		}
+	}
+
+	types := make([]string, 0, len(stats.Types))
+	for name := range stats.Types {
+		types = append(types, name)
+	}
+	slices.Sort(types)
+	for i, name := range types {
+		types[i] = fmt.Sprintf("%s %d", name, stats.Types[name])
+	}
+	row("types", "%s", strings.Join(types, ", "))
+	_ = tw.Flush()
+}
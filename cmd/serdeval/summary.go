@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Summary aggregates the outcome of a validate run.
+type Summary struct {
+	FilesScanned int                     `json:"files_scanned"`
+	Passed       int                     `json:"passed"`
+	Failed       int                     `json:"failed"`
+	Skipped      int                     `json:"skipped"`
+	Errors       int                     `json:"errors"`
+	Warnings     int                     `json:"warnings"`
+	ByFormat     map[string]FormatCounts `json:"by_format"`
+	ElapsedMS    int64                   `json:"elapsed_ms"`
+	BytesRead    int64                   `json:"bytes_read"`
+	Lines        int                     `json:"lines"`
+	// Slowest is the file that took longest to validate, if any took measurable time
+	Slowest *SlowestFile `json:"slowest,omitempty"`
+
+	elapsed time.Duration
+}
+
+// SlowestFile names the file that took longest to validate in a run.
+type SlowestFile struct {
+	FileName   string  `json:"filename"`
+	DurationMS float64 `json:"duration_ms"`
+
+	duration time.Duration
+}
+
+// FormatCounts holds pass/fail counts for a single format.
+type FormatCounts struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+}
+
+// summaryReport is the JSON envelope used when --summary is combined with JSON output.
+type summaryReport struct {
+	Results []ValidationResult `json:"results"`
+	Summary *Summary           `json:"summary"`
+}
+
+// summaryLine is the trailing record written when --summary is combined with NDJSON output.
+type summaryLine struct {
+	Summary *Summary `json:"summary"`
+}
+
+// summarize builds a Summary from the collected results. Errors counts the failures the
+// failed files report, one or more per file.
+func summarize(results []ValidationResult, elapsed time.Duration) Summary {
+	s := Summary{
+		FilesScanned: len(results),
+		ByFormat:     make(map[string]FormatCounts),
+		ElapsedMS:    elapsed.Milliseconds(),
+		elapsed:      elapsed,
+	}
+
+	for _, result := range results {
+		s.BytesRead += result.BytesRead
+		s.Lines += result.Lines
+		s.Warnings += len(result.Warnings)
+		if result.Duration > 0 && (s.Slowest == nil || result.Duration > s.Slowest.duration) {
+			s.Slowest = &SlowestFile{
+				FileName:   result.FileName,
+				DurationMS: float64(result.Duration.Microseconds()) / 1000,
+				duration:   result.Duration,
+			}
+		}
+		if result.Skipped {
+			s.Skipped++
+
+			continue
+		}
+
+		counts := s.ByFormat[result.Format]
+		if result.Valid {
+			s.Passed++
+			counts.Passed++
+		} else {
+			s.Failed++
+			// A file reporting several failures, such as CSV rows with --max-errors, counts
+			// each of them
+			s.Errors += max(len(result.Diagnostics), 1)
+			counts.Failed++
+		}
+		s.ByFormat[result.Format] = counts
+	}
+
+	return s
+}
+
+// printSummary writes the summary as an aligned table.
+func printSummary(w io.Writer, s Summary) {
+	formats := make([]string, 0, len(s.ByFormat))
+	for format := range s.ByFormat {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw)
+	_, _ = fmt.Fprintln(tw, "FORMAT\tPASSED\tFAILED")
+	for _, format := range formats {
+		counts := s.ByFormat[format]
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\n", format, counts.Passed, counts.Failed)
+	}
+	_, _ = fmt.Fprintf(tw, "TOTAL\t%d\t%d\n", s.Passed, s.Failed)
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintf(w, "\nFiles scanned: %d  Skipped: %d  Errors: %d  Warnings: %d  Elapsed: %s\n",
+		s.FilesScanned, s.Skipped, s.Errors, s.Warnings, s.elapsed.Round(time.Microsecond))
+	_, _ = fmt.Fprintf(w, "Bytes read: %d  Lines: %d", s.BytesRead, s.Lines)
+	if s.Slowest != nil {
+		_, _ = fmt.Fprintf(w, "  Slowest: %s (%s)", s.Slowest.FileName, s.Slowest.duration.Round(time.Microsecond))
+	}
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestSummarize(t *testing.T) {
+	rows := []serdeval.Diagnostic{
+		{Line: 2, Message: "wrong number of fields"},
+		{Line: 5, Message: "wrong number of fields"},
+		{Line: 9, Message: "bare quote"},
+	}
+	results := []ValidationResult{
+		{Valid: true, Format: "json", FileName: "a.json", Lines: 3, BytesRead: 20, Duration: time.Millisecond},
+		{Valid: true, Format: "yaml", FileName: "b.yaml",
+			Warnings: []serdeval.Diagnostic{{Line: 1, Message: "tab"}}, Duration: 3 * time.Millisecond},
+		{Format: "csv", FileName: "c.csv", Error: "wrong number of fields", Diagnostics: rows, Lines: 10},
+		{Format: "json", FileName: "d.json", Error: "unexpected end of JSON input"},
+		{Format: "json", FileName: "e.json", Skipped: true, Error: "skipped: too large"},
+	}
+
+	s := summarize(results, 5*time.Millisecond)
+	want := Summary{FilesScanned: 5, Passed: 2, Failed: 2, Skipped: 1, Errors: 4, Warnings: 1,
+		BytesRead: 20, Lines: 13, ElapsedMS: 5}
+	if s.FilesScanned != want.FilesScanned || s.Passed != want.Passed || s.Failed != want.Failed ||
+		s.Skipped != want.Skipped || s.Errors != want.Errors || s.Warnings != want.Warnings ||
+		s.BytesRead != want.BytesRead || s.Lines != want.Lines || s.ElapsedMS != want.ElapsedMS {
+		t.Errorf("summarize() = %+v, want %+v", s, want)
+	}
+
+	wantFormats := map[string]FormatCounts{"json": {Passed: 1, Failed: 1}, "yaml": {Passed: 1}, "csv": {Failed: 1}}
+	if !maps.Equal(s.ByFormat, wantFormats) {
+		t.Errorf("ByFormat = %v, want %v", s.ByFormat, wantFormats)
+	}
+	if s.Slowest == nil || s.Slowest.FileName != "b.yaml" || s.Slowest.DurationMS != 3 {
+		t.Errorf("Slowest = %+v, want b.yaml at 3ms", s.Slowest)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// tapDetails is the YAML diagnostic block written under a TAP test line.
+type tapDetails struct {
+	Message     string        `yaml:"message,omitempty"`
+	Severity    string        `yaml:"severity,omitempty"`
+	Format      string        `yaml:"format,omitempty"`
+	Code        string        `yaml:"code,omitempty"`
+	At          *tapLocation  `yaml:"at,omitempty"`
+	Suggestion  string        `yaml:"suggestion,omitempty"`
+	Diagnostics []tapLocation `yaml:"diagnostics,omitempty"`
+	Warnings    []tapLocation `yaml:"warnings,omitempty"`
+}
+
+// tapLocation is a position in a file, with the message reported there.
+type tapLocation struct {
+	File    string `yaml:"file,omitempty"`
+	Line    int    `yaml:"line,omitempty"`
+	Column  int    `yaml:"column,omitempty"`
+	Message string `yaml:"message,omitempty"`
+}
+
+// writeTAPResults writes results as a TAP version 13 stream, one test per file, for
+// prove- and bats-style harnesses. Invalid files are "not ok" with a YAML block giving
+// the error and its position; skipped files are "ok" with a SKIP directive.
+func writeTAPResults(w io.Writer, results []ValidationResult) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "TAP version 13\n1..%d\n", len(results))
+	for i, result := range results {
+		status := "ok"
+		if isFailure(result) {
+			status = "not ok"
+		}
+		_, _ = fmt.Fprintf(bw, "%s %d - %s", status, i+1, tapEscape(result.FileName))
+		if result.Skipped {
+			_, _ = fmt.Fprintf(bw, " # SKIP %s", strings.TrimPrefix(result.Error, "skipped: "))
+		}
+		_, _ = fmt.Fprintln(bw)
+
+		details := tapDetailsFor(result)
+		if details == nil {
+			continue
+		}
+		var block strings.Builder
+		enc := yaml.NewEncoder(&block)
+		enc.SetIndent(2)
+		if err := enc.Encode(details); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(bw, "  ---")
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(block.String(), "\n"), "\n") {
+			_, _ = fmt.Fprint(bw, "  ", line)
+		}
+		_, _ = fmt.Fprint(bw, "\n  ...\n")
+	}
+
+	return bw.Flush()
+}
+
+// tapDetailsFor returns the YAML block of result, or nil when it has nothing to report.
+func tapDetailsFor(result ValidationResult) *tapDetails {
+	var details tapDetails
+	switch {
+	case result.Skipped:
+	case !result.Valid:
+		line, column := resultPosition(result)
+		details = tapDetails{
+			Message:    result.Error,
+			Severity:   "fail",
+			Format:     result.Format,
+			Code:       result.Code,
+			At:         &tapLocation{File: result.FileName, Line: line, Column: column},
+			Suggestion: result.Suggestion,
+		}
+		if len(result.Diagnostics) > 1 {
+			details.Diagnostics = tapLocations(result.Diagnostics)
+		}
+	}
+	details.Warnings = tapLocations(result.Warnings)
+	if details.Severity == "" && len(details.Warnings) == 0 {
+		return nil
+	}
+
+	return &details
+}
+
+// tapLocations converts diagnostics for a YAML block.
+func tapLocations(diagnostics []serdeval.Diagnostic) []tapLocation {
+	var locations []tapLocation
+	for _, d := range diagnostics {
+		locations = append(locations, tapLocation{Line: d.Line, Column: d.Column, Message: d.Message})
+	}
+
+	return locations
+}
+
+// tapEscape escapes the characters of a test description that TAP would read as a
+// directive.
+func tapEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "#", `\#`, "\n", " ").Replace(s)
+}
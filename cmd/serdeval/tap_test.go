@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestWriteTAPResults(t *testing.T) {
+	results := []ValidationResult{
+		{Valid: true, Format: "json", FileName: "a.json"},
+		{
+			Valid:      false,
+			Format:     "json",
+			Code:       codeInvalid,
+			Error:      "invalid character '}' looking for beginning of object key string",
+			Suggestion: "remove the trailing comma",
+			FileName:   "b.json",
+			Diagnostics: []serdeval.Diagnostic{
+				{Line: 1, Column: 9, Message: "invalid character '}' looking for beginning of object key string"},
+			},
+		},
+		{Valid: false, Skipped: true, Format: "json", Code: codeTooLarge,
+			Error: "skipped: too large (larger than 10 bytes)", FileName: "c.json"},
+		{Valid: true, Format: "yaml", FileName: "d#1.yaml",
+			Warnings: []serdeval.Diagnostic{{Line: 2, Column: 1, Message: "tab in indentation"}}},
+	}
+
+	var out strings.Builder
+	if err := writeTAPResults(&out, results); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `TAP version 13
+1..4
+ok 1 - a.json
+not ok 2 - b.json
+  ---
+  message: invalid character '}' looking for beginning of object key string
+  severity: fail
+  format: json
+  code: invalid
+  at:
+    file: b.json
+    line: 1
+    column: 9
+  suggestion: remove the trailing comma
+  ...
+ok 3 - c.json # SKIP too large (larger than 10 bytes)
+ok 4 - d\#1.yaml
+  ---
+  warnings:
+    - line: 2
+      column: 1
+      message: tab in indentation
+  ...
+`
+	if got := out.String(); got != want {
+		t.Errorf("writeTAPResults() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteTAPResultsEmpty(t *testing.T) {
+	var out strings.Builder
+	if err := writeTAPResults(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "TAP version 13\n1..0\n"; got != want {
+		t.Errorf("writeTAPResults(nil) = %q, want %q", got, want)
+	}
+}
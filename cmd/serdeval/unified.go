@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change in a unified diff.
+const diffContext = 3
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed, or '+' added.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// writeUnifiedDiff writes the changes from before to after as a unified diff of the file
+// name, as diff -u prints it. Nothing is written when they are equal.
+func writeUnifiedDiff(w io.Writer, name string, before, after []byte) {
+	ops := diffLines(splitLines(string(before)), splitLines(string(after)))
+	hunks := diffHunks(ops)
+	if len(hunks) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", name, name)
+	for _, h := range hunks {
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:h[0]] {
+			oldStart += boolInt(op.kind != '+')
+			newStart += boolInt(op.kind != '-')
+		}
+		oldLines, newLines := 0, 0
+		for _, op := range ops[h[0]:h[1]] {
+			oldLines += boolInt(op.kind != '+')
+			newLines += boolInt(op.kind != '-')
+		}
+		_, _ = fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLines), hunkRange(newStart, newLines))
+		for _, op := range ops[h[0]:h[1]] {
+			_, _ = fmt.Fprintf(w, "%c%s\n", op.kind, strings.TrimSuffix(op.line, "\n"))
+			if !strings.HasSuffix(op.line, "\n") {
+				_, _ = fmt.Fprintln(w, `\ No newline at end of file`)
+			}
+		}
+	}
+}
+
+// splitLines splits s after each newline, keeping them so a missing final newline shows.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the shortest edit script turning a into b, found with Myers'
+// algorithm after setting aside the lines they start and end with in common.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	return ops
+}
+
+// myers returns the shortest edit script turning a into b. For each edit distance d it
+// keeps the furthest x reached on every diagonal k, in trace[d][k+d], to walk back from
+// the end.
+func myers(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	var trace [][]int
+	v := map[int]int{1: 0}
+	for d := 0; d <= n+m; d++ {
+		row := make([]int, 2*d+1)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			row[k+d] = x
+			if x >= n && y >= m {
+				return myersPath(append(trace, row), a, b)
+			}
+		}
+		trace = append(trace, row)
+	}
+
+	return nil
+}
+
+// myersPath walks the trace of myers back from the end of a and b to their start.
+func myersPath(trace [][]int, a, b []string) []diffOp {
+	x, y := len(a), len(b)
+	var ops []diffOp
+	for d := len(trace) - 1; d > 0; d-- {
+		k := x - y
+		prev := trace[d-1]
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			prevK = k + 1
+		}
+		prevX := prev[prevK+d-1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[y-1]})
+			y--
+		} else {
+			ops = append(ops, diffOp{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 {
+		ops = append(ops, diffOp{' ', a[x-1]})
+		x--
+	}
+	slices.Reverse(ops)
+
+	return ops
+}
+
+// diffHunks returns the ranges of ops shown as hunks: each change with diffContext lines
+// around it, merging changes whose context would overlap.
+func diffHunks(ops []diffOp) [][2]int {
+	var hunks [][2]int
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		from, to := max(i-diffContext, 0), min(i+1+diffContext, len(ops))
+		if n := len(hunks); n > 0 && from <= hunks[n-1][1] {
+			hunks[n-1][1] = to
+		} else {
+			hunks = append(hunks, [2]int{from, to})
+		}
+	}
+
+	return hunks
+}
+
+// hunkRange formats the start and length of one side of a hunk as diff -u does.
+func hunkRange(start, lines int) string {
+	switch lines {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// boolInt returns 1 for true and 0 for false.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
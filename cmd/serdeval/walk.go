@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultSkipDirs are the directories --skip-dir leaves out by default: version control
+// metadata and dependency or tool caches, which hold many files nobody maintains by hand.
+var defaultSkipDirs = []string{
+	".git", ".hg", ".svn", "node_modules", "bower_components", "__pycache__", ".venv", ".tox",
+	".terraform", ".idea",
+}
+
+// errWalkStopped ends a walk early without reporting an error.
+var errWalkStopped = errors.New("walk stopped")
+
+// walkOptions controls which files walking a directory or a glob reaches.
+type walkOptions struct {
+	// exclude holds the --exclude patterns skipped when walking directories and globs
+	exclude []string
+	// skipDirs are directory names never descended into
+	skipDirs []string
+	// maxDepth is how many levels below the starting directory files are taken from, as
+	// find -maxdepth counts them; zero means no limit
+	maxDepth int
+	// hidden walks into the skipDirs whose names start with ".", such as .git; other dot
+	// files and directories, such as .github, are always walked
+	hidden bool
+	// followSymlinks descends into symbolic links to directories, each real directory once
+	followSymlinks bool
+	// stopped, if set, ends the walk once it returns true, for --fail-fast
+	stopped func() bool
+	// onDir, if set, is called with every directory walked, for the daemon to watch
+	onDir func(dir string)
+}
+
+// addWalkFlags adds the flags that control walking directories to cmd.
+func addWalkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("exclude", nil,
+		"Skip files and directories matching this glob (** for any depth, {a,b} for either) when walking; repeatable")
+	cmd.Flags().StringSlice("skip-dir", defaultSkipDirs,
+		"Directory names never walked into; pass --skip-dir= to walk into all of them")
+	cmd.Flags().Int("max-dir-depth", 0,
+		"Only take files this many directory levels deep when walking, 1 for a directory's own files (0 for no limit)")
+	cmd.Flags().Bool("hidden", false,
+		"Also walk into the --skip-dir directories whose names start with \".\", such as .git")
+	cmd.Flags().Bool("follow-symlinks", false,
+		"Walk into symbolic links to directories, each real directory once")
+}
+
+// walkOptionsFromFlags returns the walk options set by the flags addWalkFlags added.
+func walkOptionsFromFlags(cmd *cobra.Command) walkOptions {
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	skipDirs, _ := cmd.Flags().GetStringSlice("skip-dir")
+	maxDepth, _ := cmd.Flags().GetInt("max-dir-depth")
+	hidden, _ := cmd.Flags().GetBool("hidden")
+	followSymlinks, _ := cmd.Flags().GetBool("follow-symlinks")
+
+	return walkOptions{
+		exclude:        exclude,
+		skipDirs:       skipDirs,
+		maxDepth:       maxDepth,
+		hidden:         hidden,
+		followSymlinks: followSymlinks,
+	}
+}
+
+// walk calls fn with every file under root, in lexical order, that the options let
+// through. The entries of root itself are at depth 1. The first error reading a
+// directory ends the walk and is returned.
+func (w walkOptions) walk(root string, fn func(path string)) error {
+	err := w.walkDir(root, 1, map[string]bool{}, fn)
+	if errors.Is(err, errWalkStopped) {
+		return nil
+	}
+
+	return err
+}
+
+// walkDir walks the entries of dir, which are at depth. Following symbolic links, visited
+// holds the real directories already walked so a link to an ancestor does not loop.
+func (w walkOptions) walkDir(dir string, depth int, visited map[string]bool, fn func(path string)) error {
+	if w.followSymlinks {
+		real, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return err
+		}
+		if visited[real] {
+			return nil
+		}
+		visited[real] = true
+	}
+	if w.onDir != nil {
+		w.onDir(dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if w.stopped != nil && w.stopped() {
+			return errWalkStopped
+		}
+		if err = w.visit(filepath.Join(dir, entry.Name()), entry, depth, visited, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// visit passes the file p, an entry at depth, to fn, or walks into it when it is a
+// directory, unless the options leave it out.
+func (w walkOptions) visit(p string, entry fs.DirEntry, depth int, visited map[string]bool,
+	fn func(path string)) error {
+	name := entry.Name()
+	if excluded(p, w.exclude) {
+		return nil
+	}
+
+	isDir := entry.IsDir()
+	if entry.Type()&fs.ModeSymlink != 0 {
+		// A dangling link is passed on like a file, so reading it reports the problem
+		info, err := os.Stat(p)
+		if isDir = err == nil && info.IsDir(); isDir && !w.followSymlinks {
+			return nil
+		}
+	}
+
+	switch {
+	case !isDir:
+		if w.maxDepth == 0 || depth <= w.maxDepth {
+			fn(p)
+		}
+	case w.skipsDir(name), w.maxDepth != 0 && depth >= w.maxDepth:
+	default:
+		return w.walkDir(p, depth+1, visited, fn)
+	}
+
+	return nil
+}
+
+// skipsDir reports whether the directory called name is left out of the walk.
+func (w walkOptions) skipsDir(name string) bool {
+	return slices.Contains(w.skipDirs, name) && !(w.hidden && strings.HasPrefix(name, "."))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"a.json", ".gitlab-ci.yml", ".github/workflows/ci.yml", ".github/dependabot.yml",
+		".git/config.json", ".venv/lib.json", "node_modules/pkg/package.json", "sub/deep/b.yaml",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		walk walkOptions
+		want []string
+	}{
+		{"defaults", walkOptions{skipDirs: defaultSkipDirs},
+			[]string{".github/dependabot.yml", ".github/workflows/ci.yml", ".gitlab-ci.yml", "a.json", "sub/deep/b.yaml"}},
+		{"hidden", walkOptions{skipDirs: defaultSkipDirs, hidden: true},
+			[]string{".git/config.json", ".github/dependabot.yml", ".github/workflows/ci.yml", ".gitlab-ci.yml",
+				".venv/lib.json", "a.json", "sub/deep/b.yaml"}},
+		{"no skipped directories", walkOptions{},
+			[]string{".git/config.json", ".github/dependabot.yml", ".github/workflows/ci.yml", ".gitlab-ci.yml",
+				".venv/lib.json", "a.json", "node_modules/pkg/package.json", "sub/deep/b.yaml"}},
+		{"excluded", walkOptions{skipDirs: defaultSkipDirs, exclude: []string{".github/**", "*.json"}},
+			[]string{".gitlab-ci.yml", "sub/deep/b.yaml"}},
+		{"max depth", walkOptions{skipDirs: defaultSkipDirs, maxDepth: 2},
+			[]string{".github/dependabot.yml", ".gitlab-ci.yml", "a.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := tt.walk.walk(dir, func(path string) {
+				rel, _ := filepath.Rel(dir, path)
+				got = append(got, filepath.ToSlash(rel))
+			})
+			if err != nil {
+				t.Fatalf("walk() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("walk() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1" // #nosec G505 - RFC 6455 fixes SHA-1 for the handshake; it protects nothing
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is appended to the client's key to compute Sec-WebSocket-Accept (RFC 6455 1.3).
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes (RFC 6455 5.2)
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// WebSocket close status codes (RFC 6455 7.4.1)
+const (
+	wsCloseProtocolError = 1002
+	wsCloseTooBig        = 1009
+)
+
+// errWebSocketClosed is returned by readMessage once the peer has closed the connection.
+var errWebSocketClosed = errors.New("websocket closed")
+
+// wsConn is the server side of a WebSocket connection. Only messages the live validation
+// endpoint needs are supported: text and binary messages, fragmented or not, with pings
+// answered and close handshakes completed.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	// maxSize is the largest message accepted, after reassembling fragments
+	maxSize int64
+	// mu serializes writes, which the reading goroutine makes to answer pings and closes
+	mu sync.Mutex
+}
+
+// upgradeWebSocket completes the opening handshake of a WebSocket request and takes over
+// its connection. Requests from a page on another origin are refused, so a site the user
+// visits cannot drive the local server. On error nothing has been written, and the caller
+// can still respond over HTTP.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, maxSize int64) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		return nil, errors.New("websocket handshake must use GET")
+	}
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		return nil, errors.New("not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, errors.New("unsupported websocket version: only 13 is supported")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return nil, fmt.Errorf("cross-origin websocket from %s refused", origin)
+		}
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// The server's read and write timeouts are for requests, not long-lived connections
+	_ = conn.SetDeadline(time.Time{})
+
+	_, _ = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(key))
+	if err = rw.Flush(); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, rw: rw, maxSize: maxSize}, nil
+}
+
+// websocketAccept returns the Sec-WebSocket-Accept value answering the client's key.
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID)) // #nosec G401 - required by RFC 6455
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHasToken reports whether the comma-separated header name lists token, ignoring case.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// readMessage returns the payload of the next text or binary message. Control frames in
+// between are handled as they arrive. It returns errWebSocketClosed after a close
+// handshake, and closes the connection with a status code on a protocol violation.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsPing:
+			if err = c.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			// Echo the status code, completing the close handshake
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			_ = c.writeFrame(wsClose, payload)
+
+			return nil, errWebSocketClosed
+		case wsText, wsBinary:
+			if started {
+				return nil, c.fail(wsCloseProtocolError, "new message before the last one finished")
+			}
+			started = true
+		case wsContinuation:
+			if !started {
+				return nil, c.fail(wsCloseProtocolError, "continuation frame without a message")
+			}
+		default:
+			return nil, c.fail(wsCloseProtocolError, fmt.Sprintf("unknown opcode %d", opcode))
+		}
+
+		if int64(len(message)+len(payload)) > c.maxSize {
+			return nil, c.fail(wsCloseTooBig, fmt.Sprintf("message larger than %d bytes", c.maxSize))
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads one frame and returns its unmasked payload.
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.rw, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = header[0]&0x80 != 0, header[0]&0x0F
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, c.fail(wsCloseProtocolError, "client frames must be masked")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		_, err = io.ReadFull(c.rw, ext[:])
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, err = io.ReadFull(c.rw, ext[:])
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if err != nil {
+		return false, 0, nil, err
+	}
+	if length > uint64(c.maxSize) {
+		return false, 0, nil, c.fail(wsCloseTooBig, fmt.Sprintf("frame larger than %d bytes", c.maxSize))
+	}
+	if opcode >= wsClose && (length > 125 || !fin) {
+		return false, 0, nil, c.fail(wsCloseProtocolError, "invalid control frame")
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes payload as a single unmasked frame, as servers send them.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+
+	return c.rw.Flush()
+}
+
+// writeJSON sends v encoded as JSON in a text message.
+func (c *wsConn) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return c.writeFrame(wsText, data)
+}
+
+// fail closes the connection with a status code and reason, and returns the reason as
+// an error.
+func (c *wsConn) fail(code uint16, reason string) error {
+	_ = c.writeFrame(wsClose, append(binary.BigEndian.AppendUint16(nil, code), reason...))
+
+	return errors.New(reason)
+}
+
+// Close closes the underlying connection.
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// clientFrame encodes a frame as a client sends it, masked unless unmasked is set.
+func clientFrame(fin bool, opcode byte, payload []byte, unmasked bool) []byte {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	maskBit := byte(0x80)
+	if unmasked {
+		maskBit = 0
+	}
+
+	frame := []byte{first}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if unmasked {
+		return append(frame, payload...)
+	}
+
+	mask := [4]byte{0x37, 0xfa, 0x21, 0x3d}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	return frame
+}
+
+// serverFrame encodes an unmasked frame as the server sends it.
+func serverFrame(opcode byte, payload []byte) []byte {
+	var out bytes.Buffer
+	c := &wsConn{rw: bufio.NewReadWriter(bufio.NewReader(&bytes.Buffer{}), bufio.NewWriter(&out))}
+	_ = c.writeFrame(opcode, payload)
+
+	return out.Bytes()
+}
+
+// closeFrame encodes the close frame the server sends with code and reason.
+func closeFrame(code uint16, reason string) []byte {
+	return serverFrame(wsClose, append(binary.BigEndian.AppendUint16(nil, code), reason...))
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	// The sample handshake of RFC 6455 section 1.3
+	if got := websocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("websocketAccept() = %s, want s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+	}
+}
+
+func TestWebSocketReadMessage(t *testing.T) {
+	medium := bytes.Repeat([]byte("m"), 300)
+	large := bytes.Repeat([]byte("l"), 70000)
+	concat := func(frames ...[]byte) []byte { return bytes.Join(frames, nil) }
+	// hel and lo are the two fragments of the text message "hello"
+	hel := clientFrame(false, wsText, []byte("hel"), false)
+	lo := clientFrame(true, wsContinuation, []byte("lo"), false)
+
+	tests := []struct {
+		name    string
+		maxSize int64
+		input   []byte
+		want    []byte
+		wantErr string
+		// sent is what the server writes back: pongs and close frames
+		sent []byte
+	}{
+		{name: "masked text", maxSize: 1024, input: clientFrame(true, wsText, []byte("hello"), false),
+			want: []byte("hello")},
+		{name: "binary", maxSize: 1024, input: clientFrame(true, wsBinary, []byte{0, 1, 2}, false),
+			want: []byte{0, 1, 2}},
+		{name: "empty", maxSize: 1024, input: clientFrame(true, wsText, nil, false), want: []byte{}},
+		{name: "unmasked", maxSize: 1024, input: clientFrame(true, wsText, []byte("hello"), true),
+			wantErr: "client frames must be masked", sent: closeFrame(wsCloseProtocolError, "client frames must be masked")},
+		{name: "16-bit length", maxSize: 1024, input: clientFrame(true, wsText, medium, false), want: medium},
+		{name: "64-bit length", maxSize: 100000, input: clientFrame(true, wsText, large, false), want: large},
+		{name: "frame over the limit", maxSize: 100, input: clientFrame(true, wsText, medium, false),
+			wantErr: "frame larger than 100 bytes", sent: closeFrame(wsCloseTooBig, "frame larger than 100 bytes")},
+		{name: "fragments", maxSize: 1024, input: concat(hel, lo), want: []byte("hello")},
+		{name: "fragments over the limit", maxSize: 4,
+			input:   concat(hel, lo),
+			wantErr: "message larger than 4 bytes", sent: closeFrame(wsCloseTooBig, "message larger than 4 bytes")},
+		{name: "ping between fragments", maxSize: 1024,
+			input: concat(hel, clientFrame(true, wsPing, []byte("p"), false), lo),
+			want:  []byte("hello"), sent: serverFrame(wsPong, []byte("p"))},
+		{name: "pong ignored", maxSize: 1024,
+			input: concat(clientFrame(true, wsPong, nil, false), clientFrame(true, wsText, []byte("hi"), false)),
+			want:  []byte("hi")},
+		{name: "close echoes the status code", maxSize: 1024,
+			input:   clientFrame(true, wsClose, append(binary.BigEndian.AppendUint16(nil, 1000), "bye"...), false),
+			wantErr: errWebSocketClosed.Error(), sent: serverFrame(wsClose, binary.BigEndian.AppendUint16(nil, 1000))},
+		{name: "close without a status code", maxSize: 1024, input: clientFrame(true, wsClose, nil, false),
+			wantErr: errWebSocketClosed.Error(), sent: serverFrame(wsClose, nil)},
+		{name: "control frame too long", maxSize: 1024, input: clientFrame(true, wsPing, medium, false),
+			wantErr: "invalid control frame", sent: closeFrame(wsCloseProtocolError, "invalid control frame")},
+		{name: "fragmented control frame", maxSize: 1024, input: clientFrame(false, wsPing, nil, false),
+			wantErr: "invalid control frame", sent: closeFrame(wsCloseProtocolError, "invalid control frame")},
+		{name: "continuation without a message", maxSize: 1024,
+			input:   lo,
+			wantErr: "continuation frame without a message",
+			sent:    closeFrame(wsCloseProtocolError, "continuation frame without a message")},
+		{name: "new message inside a fragmented one", maxSize: 1024,
+			input:   concat(hel, clientFrame(true, wsText, []byte("lo"), false)),
+			wantErr: "new message before the last one finished",
+			sent:    closeFrame(wsCloseProtocolError, "new message before the last one finished")},
+		{name: "unknown opcode", maxSize: 1024, input: clientFrame(true, 0x3, nil, false),
+			wantErr: "unknown opcode 3", sent: closeFrame(wsCloseProtocolError, "unknown opcode 3")},
+		{name: "truncated frame", maxSize: 1024, input: clientFrame(true, wsText, []byte("hello"), false)[:8],
+			wantErr: "unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			c := &wsConn{
+				rw:      bufio.NewReadWriter(bufio.NewReader(bytes.NewReader(tt.input)), bufio.NewWriter(&out)),
+				maxSize: tt.maxSize,
+			}
+			got, err := c.readMessage()
+			switch {
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Fatalf("readMessage() error = %v, want %s", err, tt.wantErr)
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("readMessage() error = %v", err)
+			case tt.wantErr == "" && !bytes.Equal(got, tt.want):
+				t.Errorf("readMessage() = %q, want %q", got, tt.want)
+			}
+			if !bytes.Equal(out.Bytes(), tt.sent) {
+				t.Errorf("sent %x, want %x", out.Bytes(), tt.sent)
+			}
+		})
+	}
+}
+
+func TestWebSocketWriteFrame(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+		header []byte
+	}{
+		{"7-bit length", 125, []byte{0x81, 125}},
+		{"16-bit length", 126, []byte{0x81, 126, 0, 126}},
+		{"largest 16-bit length", 0xFFFF, []byte{0x81, 126, 0xFF, 0xFF}},
+		{"64-bit length", 0x10000, []byte{0x81, 127, 0, 0, 0, 0, 0, 1, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := serverFrame(wsText, bytes.Repeat([]byte("x"), tt.length))
+			if !bytes.HasPrefix(frame, tt.header) || len(frame) != len(tt.header)+tt.length {
+				t.Errorf("frame header = %x (length %d), want %x followed by %d bytes",
+					frame[:min(len(frame), 10)], len(frame), tt.header, tt.length)
+			}
+		})
+	}
+}
+
+func TestUpgradeWebSocket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgradeWebSocket(w, r, 1024)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    int
+		wantErr string
+	}{
+		{name: "handshake", want: http.StatusSwitchingProtocols},
+		{name: "same origin", headers: map[string]string{"Origin": "http://" + host}, want: http.StatusSwitchingProtocols},
+		{name: "same origin, host in another case", headers: map[string]string{"Origin": "http://" + strings.ToUpper(host)},
+			want: http.StatusSwitchingProtocols},
+		{name: "cross origin", headers: map[string]string{"Origin": "http://evil.example"},
+			want: http.StatusBadRequest, wantErr: "cross-origin websocket from http://evil.example refused"},
+		{name: "same host on another port", headers: map[string]string{"Origin": "http://127.0.0.1:1"},
+			want: http.StatusBadRequest, wantErr: "cross-origin"},
+		{name: "not GET", method: http.MethodPost, want: http.StatusBadRequest, wantErr: "must use GET"},
+		{name: "no upgrade", headers: map[string]string{"Upgrade": ""}, want: http.StatusBadRequest,
+			wantErr: "not a websocket handshake"},
+		{name: "old version", headers: map[string]string{"Sec-WebSocket-Version": "8"}, want: http.StatusBadRequest,
+			wantErr: "only 13 is supported"},
+		{name: "no key", headers: map[string]string{"Sec-WebSocket-Key": ""}, want: http.StatusBadRequest,
+			wantErr: "missing Sec-WebSocket-Key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, err := http.NewRequest(method, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Connection", "keep-alive, Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want == http.StatusSwitchingProtocols {
+				if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+					t.Errorf("Sec-WebSocket-Accept = %s, want s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+				}
+
+				return
+			}
+			var body bytes.Buffer
+			_, _ = body.ReadFrom(resp.Body)
+			if !strings.Contains(body.String(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", body.String(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// xmlSchemaOptions turns --xml-schema into validator options, along with a fingerprint of
+// the schema for the result cache.
+func xmlSchemaOptions(format, schemaPath string) ([]serdeval.Option, string, error) {
+	if schemaPath == "" {
+		return nil, "", nil
+	}
+	if format != autoFormat && format != string(serdeval.FormatXML) {
+		return nil, "", errors.New("--xml-schema requires --format xml or auto")
+	}
+
+	xsd, err := os.ReadFile(schemaPath) // #nosec G304 - CLI tool needs to read user-specified files
+	if err != nil {
+		return nil, "", err
+	}
+	opts := []serdeval.Option{serdeval.WithXMLSchema(xsd)}
+
+	// Fail before reading any input if the schema does not compile
+	if _, err = serdeval.NewValidator(serdeval.FormatXML, opts...); err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(xsd)
+
+	return opts, hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,146 @@
+package serdeval
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	codeownersUserRe    = regexp.MustCompile(`^@[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+	codeownersTeamRe    = regexp.MustCompile(`^@[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})/[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	codeownersEmailRe   = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	codeownersSectionRe = regexp.MustCompile(`^\^?\[[^\]]+\](?:\[\d+\])?$`)
+)
+
+// CodeownersValidator validates CODEOWNERS files as used by GitHub and GitLab.
+// Each rule is a path pattern followed by owners, which must be @user, @org/team, or an email address.
+// Patterns may not use '!' negation or '[ ]' character ranges; GitLab [Section] headers are accepted.
+//
+// Example:
+//
+//	validator := &CodeownersValidator{baseValidator{format: FormatCodeowners}}
+//	result := validator.ValidateString("*.go @golang-team\n/docs/ @acme/writers docs@example.com\n")
+type CodeownersValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid CODEOWNERS file.
+//
+// Example:
+//
+//	validator := &CodeownersValidator{baseValidator{format: FormatCodeowners}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. `line 4: invalid owner "team-a"`
+//	}
+func (v *CodeownersValidator) Validate(data []byte) Result {
+	var err error
+	for i, line := range strings.Split(string(data), "\n") {
+		if lineErr := checkCodeownersLine(line); lineErr != nil {
+			err = fmt.Errorf("line %d: %w", i+1, lineErr)
+
+			break
+		}
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *CodeownersValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func checkCodeownersLine(line string) error {
+	fields := splitCodeownersFields(strings.TrimSpace(line))
+	if len(fields) == 0 {
+		return nil
+	}
+
+	pattern := fields[0]
+	if strings.HasPrefix(pattern, "[") || strings.HasPrefix(pattern, "^[") {
+		section := strings.Join(fields, " ")
+		end := strings.LastIndex(section, "]") + 1
+		if !codeownersSectionRe.MatchString(section[:end]) {
+			return fmt.Errorf("invalid section header %q", section[:end])
+		}
+
+		return checkCodeownersOwners(splitCodeownersFields(section[end:]))
+	}
+
+	if err := checkCodeownersPattern(pattern); err != nil {
+		return err
+	}
+
+	return checkCodeownersOwners(fields[1:])
+}
+
+func checkCodeownersPattern(pattern string) error {
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		return fmt.Errorf("pattern %q: negation with '!' is not supported", pattern)
+	case strings.ContainsAny(pattern, "[]"):
+		return fmt.Errorf("pattern %q: character ranges with '[ ]' are not supported", pattern)
+	case strings.Contains(pattern, "***"):
+		return fmt.Errorf("pattern %q: invalid wildcard '***'", pattern)
+	}
+
+	return nil
+}
+
+func checkCodeownersOwners(owners []string) error {
+	for _, owner := range owners {
+		switch {
+		case strings.HasPrefix(owner, "@") && strings.Contains(owner, "/"):
+			if !codeownersTeamRe.MatchString(owner) {
+				return fmt.Errorf("invalid team %q (expected @org/team)", owner)
+			}
+		case strings.HasPrefix(owner, "@"):
+			if !codeownersUserRe.MatchString(owner) {
+				return fmt.Errorf("invalid username %q", owner)
+			}
+		case strings.Contains(owner, "@"):
+			if !codeownersEmailRe.MatchString(owner) {
+				return fmt.Errorf("invalid email address %q", owner)
+			}
+		default:
+			return fmt.Errorf("invalid owner %q (expected @user, @org/team, or an email address)", owner)
+		}
+	}
+
+	return nil
+}
+
+// splitCodeownersFields splits on unescaped whitespace and drops a trailing '#' comment.
+func splitCodeownersFields(line string) []string {
+	var fields []string
+	var current strings.Builder
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			current.WriteByte(c)
+			current.WriteByte(line[i+1])
+			i++
+		case c == ' ' || c == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		case c == '#' && current.Len() == 0:
+			return fields
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+
+	return fields
+}
@@ -0,0 +1,50 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeownersValidator(t *testing.T) {
+	v := &CodeownersValidator{baseValidator{format: FormatCodeowners}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"github rules", "# Owners\n* @global-owner\n*.js    @js-owner # frontend\n/docs/ docs@example.com\n", true, ""},
+		{"team", "/build/logs/ @acme/build-team @octocat\n", true, ""},
+		{"no owners", "/apps/github\n", true, ""},
+		{"escaped space", "/my\\ docs/ @writer\n", true, ""},
+		{"escaped hash", "\\#notes.md @writer\n", true, ""},
+		{"gitlab sections", "[Docs][2] @acme/writers\n*.md\n^[Optional]\n/scripts/ @ops\n", true, ""},
+		{"negation", "!*.md @writer\n", false, "line 1: pattern \"!*.md\": negation"},
+		{"character range", "*.[ch] @c-team\n", false, "character ranges"},
+		{"bare owner", "*.go golang-team\n", false, `invalid owner "golang-team"`},
+		{"bad username", "*.go @-leading\n", false, "invalid username"},
+		{"bad team", "*.go @acme/\n", false, "invalid team"},
+		{"bad email", "*.go dev@localhost\n", false, "invalid email"},
+		{"bad section", "[Docs @writer\n", false, "invalid section header"},
+		{"later line", "*.go @gopher\n\n*.rs rustacean\n", false, "line 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	for _, name := range []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS"} {
+		if got := DetectFormatFromFilename(name); got != FormatCodeowners {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want %v", name, got, FormatCodeowners)
+		}
+	}
+}
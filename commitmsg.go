@@ -0,0 +1,161 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// commitHeaderMaxLength and commitBodyMaxLineLength follow the commitlint conventional defaults.
+	commitHeaderMaxLength   = 100
+	commitBodyMaxLineLength = 100
+)
+
+var (
+	commitHeaderRe   = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+	commitBreakingRe = regexp.MustCompile(`(?i)^breaking[ -]change\s*:`)
+)
+
+// commitTypes are the Conventional Commits types accepted in a header.
+var commitTypes = map[string]bool{
+	"build": true, "chore": true, "ci": true, "docs": true, "feat": true, "fix": true,
+	"perf": true, "refactor": true, "revert": true, "style": true, "test": true,
+}
+
+// CommitMsgValidator validates commit messages against the Conventional Commits specification.
+// It checks the "type(scope)!: subject" header and allowed types, the blank line before the body,
+// body line length, and BREAKING CHANGE footers. Comment lines and everything below git's
+// scissors line are ignored, so it can run directly on .git/COMMIT_EDITMSG from a commit-msg hook.
+// Merge, revert, fixup!, and squash! messages generated by git are accepted as-is.
+//
+// Example:
+//
+//	validator := &CommitMsgValidator{baseValidator{format: FormatCommitMsg}}
+//	result := validator.ValidateString("feat(parser)!: drop YAML 1.1 support\n\n" +
+//		"BREAKING CHANGE: octal literals are parsed as decimal\n")
+type CommitMsgValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid Conventional Commits message.
+//
+// Example:
+//
+//	validator := &CommitMsgValidator{baseValidator{format: FormatCommitMsg}}
+//	data, _ := os.ReadFile(".git/COMMIT_EDITMSG")
+//	result := validator.Validate(data)
+func (v *CommitMsgValidator) Validate(data []byte) Result {
+	err := validateCommitMsg(string(data))
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *CommitMsgValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// commitMsgLines returns the message lines git would keep: comments and the scissors section are
+// dropped, and leading and trailing blank lines trimmed.
+func commitMsgLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "# ") && strings.Contains(line, ">8") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+func validateCommitMsg(text string) error {
+	lines := commitMsgLines(text)
+	if len(lines) == 0 {
+		return errors.New("empty commit message")
+	}
+
+	header := lines[0]
+	for _, prefix := range []string{"Merge ", "Revert \"", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(header, prefix) {
+			return nil
+		}
+	}
+	if err := checkCommitHeader(header); err != nil {
+		return fmt.Errorf("line 1: %w", err)
+	}
+
+	if len(lines) > 1 && lines[1] != "" {
+		return errors.New("line 2: must be blank between the header and the body")
+	}
+
+	for i, line := range lines[1:] {
+		lineNum := i + 2
+		if m := commitBreakingRe.FindString(line); m != "" {
+			token := strings.TrimSpace(strings.TrimSuffix(m, ":"))
+			if token != "BREAKING CHANGE" && token != "BREAKING-CHANGE" {
+				return fmt.Errorf("line %d: footer token %q must be written BREAKING CHANGE", lineNum, token)
+			}
+			if strings.TrimSpace(line[len(m):]) == "" {
+				return fmt.Errorf("line %d: BREAKING CHANGE footer needs a description", lineNum)
+			}
+		}
+		// Long unbroken tokens such as URLs cannot be wrapped
+		if len(line) > commitBodyMaxLineLength && strings.ContainsAny(line, " \t") {
+			return fmt.Errorf("line %d: body line is %d characters (max %d)", lineNum, len(line),
+				commitBodyMaxLineLength)
+		}
+	}
+
+	return nil
+}
+
+func checkCommitHeader(header string) error {
+	if len(header) > commitHeaderMaxLength {
+		return fmt.Errorf("header is %d characters (max %d)", len(header), commitHeaderMaxLength)
+	}
+
+	m := commitHeaderRe.FindStringSubmatch(header)
+	if m == nil {
+		return errors.New("header must look like \"type(scope): subject\"")
+	}
+	commitType, scope, subject := m[1], m[2], m[4]
+	if !commitTypes[commitType] {
+		if commitTypes[strings.ToLower(commitType)] {
+			return fmt.Errorf("type %q must be lower case", commitType)
+		}
+
+		return fmt.Errorf("unknown type %q (expected one of %s)", commitType,
+			strings.Join(sortedKeys(commitTypes), ", "))
+	}
+	if strings.HasPrefix(header[len(commitType):], "(") && strings.TrimSpace(scope) == "" {
+		return errors.New("scope must not be empty")
+	}
+
+	switch {
+	case strings.TrimSpace(subject) == "":
+		return errors.New("subject must not be empty")
+	case subject != strings.TrimLeft(subject, " \t"):
+		return errors.New("subject must follow \": \" with a single space")
+	case strings.HasSuffix(subject, "."):
+		return errors.New("subject must not end with a period")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommitMsgValidator(t *testing.T) {
+	v := &CommitMsgValidator{baseValidator{format: FormatCommitMsg}}
+
+	editMsg := "fix(cli): handle empty stdin\n\n# Please enter the commit message for your changes.\n" +
+		"# ------------------------ >8 ------------------------\ndiff --git a/main.go b/main.go\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"simple", "feat: add TOML support", true, ""},
+		{"scope and body", "fix(parser): reject tabs in YAML indentation\n\nTabs were silently accepted.\n", true, ""},
+		{"breaking bang", "feat(api)!: rename Validate to Check", true, ""},
+		{"breaking footer", "refactor: drop Go 1.20\n\nBREAKING CHANGE: requires Go 1.21\nRefs: #42\n", true, ""},
+		{"hyphenated footer", "chore: bump deps\n\nBREAKING-CHANGE: new minimum version\n", true, ""},
+		{"git editor file", editMsg, true, ""},
+		{"merge commit", "Merge branch 'main' into feature", true, ""},
+		{"fixup", "fixup! feat: add TOML support", true, ""},
+		{"long url", "docs: add link\n\n" + "https://example.com/" + strings.Repeat("a", 120), true, ""},
+		{"parens in subject", "feat: support (nested) lists", true, ""},
+		{"empty", "# only comments\n\n", false, "empty commit message"},
+		{"no type", "Add TOML support", false, "line 1: header must look like"},
+		{"unknown type", "feature: add TOML", false, `unknown type "feature"`},
+		{"upper-case type", "Fix: crash", false, `type "Fix" must be lower case`},
+		{"empty scope", "fix(): crash", false, "scope must not be empty"},
+		{"empty subject", "fix: ", false, "header must look like"},
+		{"double space", "fix:  crash", false, "single space"},
+		{"trailing period", "fix: crash on start.", false, "must not end with a period"},
+		{"long header", "feat: " + strings.Repeat("x", 100), false, "header is 106 characters"},
+		{"no blank line", "fix: crash\nMore detail", false, "line 2: must be blank"},
+		{"long body line", "fix: crash\n\n" + strings.Repeat("word ", 25), false, "line 3: body line is"},
+		{"lower-case breaking", "fix: crash\n\nbreaking change: api\n", false, "must be written BREAKING CHANGE"},
+		{"empty breaking", "fix: crash\n\nBREAKING CHANGE:\n", false, "needs a description"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormatFromFilename(".git/COMMIT_EDITMSG"); got != FormatCommitMsg {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatCommitMsg)
+	}
+}
@@ -0,0 +1,97 @@
+package serdeval
+
+import (
+	"context"
+	"fmt"
+)
+
+// contextCheckInterval is how many records line-oriented validators read between checks
+// of their context, starting before the first, so the check costs little on huge inputs.
+const contextCheckInterval = 1024
+
+// canceledResult is the Result returned when ctx ends a validation before it finishes.
+// Like inputs skipped by WithMaxFileSize, the data was not fully checked, so the Result
+// is marked Skipped rather than reporting a verdict.
+func canceledResult(format Format, err error) Result {
+	return skippedResult(format, ErrCodeCanceled, fmt.Sprintf("skipped: canceled (%v)", err))
+}
+
+// ContextValidator is implemented by validators that can give up once ctx is canceled or
+// its deadline passes, returning a Result with Skipped set. Validators from NewValidator
+// implement it, and so do validators passed to Register that check ctx themselves.
+type ContextValidator interface {
+	Validator
+	ValidateContext(ctx context.Context, data []byte) Result
+}
+
+// ValidateContext validates data with v, giving up once ctx is canceled or its deadline
+// passes. It uses v's own ValidateContext when v is a ContextValidator. Line-oriented
+// validators (CSV, TSV, and JSON Lines) then stop reading; others stop being waited for
+// and finish in the background, their Result discarded.
+func ValidateContext(ctx context.Context, v Validator, data []byte) Result {
+	if cv, ok := v.(ContextValidator); ok {
+		return cv.ValidateContext(ctx, data)
+	}
+
+	return validateContext(ctx, v.Format(), data, v.Validate)
+}
+
+// ValidateAutoContext is like ValidateAuto but gives up once ctx is canceled or its
+// deadline passes, as ValidateContext does.
+func ValidateAutoContext(ctx context.Context, data []byte, opts ...Option) Result {
+	return buildOptions(opts).run(FormatUnknown, data, func(text []byte) Result {
+		if err := ctx.Err(); err != nil {
+			return canceledResult(FormatUnknown, err)
+		}
+
+		return validateAuto(ctx, text, opts)
+	})
+}
+
+// ValidateContext applies the configured limits and decodes data, then validates it with
+// ctx, adding cancellation once here for every format.
+func (v *optionValidator) ValidateContext(ctx context.Context, data []byte) Result {
+	return v.opts.run(v.Format(), data, func(text []byte) Result {
+		return ValidateContext(ctx, v.Validator, text)
+	})
+}
+
+// validateContext runs validate on data, returning a canceled Result as soon as ctx is done.
+// It is for validators that cannot check ctx themselves, so it cannot stop them: the
+// abandoned validation keeps running in its goroutine until it finishes, and its Result
+// is discarded.
+func validateContext(ctx context.Context, format Format, data []byte, validate func([]byte) Result) Result {
+	if err := ctx.Err(); err != nil {
+		return canceledResult(format, err)
+	}
+	if ctx.Done() == nil {
+		// The context can never be canceled, so there is nothing to wait for
+		return validate(data)
+	}
+
+	done := make(chan Result, 1)
+	go func() {
+		done <- validate(data)
+	}()
+	select {
+	case result := <-done:
+		return result
+	case <-ctx.Done():
+		return canceledResult(format, ctx.Err())
+	}
+}
+
+// ValidateContext is like Validate but stops reading records once ctx is done.
+func (v *CSVValidator) ValidateContext(ctx context.Context, data []byte) Result {
+	return v.validate(ctx, data)
+}
+
+// ValidateContext is like Validate but stops reading records once ctx is done.
+func (v *TSVValidator) ValidateContext(ctx context.Context, data []byte) Result {
+	return v.validate(ctx, data)
+}
+
+// ValidateContext is like Validate but stops reading records once ctx is done.
+func (v *JSONLValidator) ValidateContext(ctx context.Context, data []byte) Result {
+	return v.validate(ctx, data)
+}
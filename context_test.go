@@ -0,0 +1,125 @@
+package serdeval
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for format := range validatorMap {
+		t.Run(string(format), func(t *testing.T) {
+			v, err := NewValidator(format)
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			data := []byte("a")
+			want := v.Validate(data)
+			if got := ValidateContext(context.Background(), v, data); got.Valid != want.Valid || got.Error != want.Error {
+				t.Errorf("ValidateContext(Background) = %+v, want %+v", got, want)
+			}
+			got := ValidateContext(canceled, v, data)
+			if got.Valid || !got.Skipped || got.Format != format || got.Error != "skipped: canceled (context canceled)" {
+				t.Errorf("ValidateContext(canceled) = %+v, want a skipped %s result", got, format)
+			}
+		})
+	}
+}
+
+func TestValidateContextDeadline(t *testing.T) {
+	rows := strings.Repeat("1,Ada,ada@example.com\n", 200000)
+	tests := []struct {
+		format Format
+		input  string
+	}{
+		{FormatCSV, "id,name,email\n" + rows},
+		{FormatTSV, "id\tname\n" + strings.Repeat("1\tAda\n", 200000)},
+		{FormatJSONL, strings.Repeat(`{"id": 1, "name": "Ada"}`+"\n", 200000)},
+		{FormatJSON, "[" + strings.Repeat(`{"id": 1, "name": "Ada"},`, 200000) + "1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			v, err := NewValidator(tt.format)
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+			defer cancel()
+			<-ctx.Done()
+			result := ValidateContext(ctx, v, []byte(tt.input))
+			if !result.Skipped || !strings.Contains(result.Error, "context deadline exceeded") {
+				t.Errorf("ValidateContext() = %+v, want a deadline result", result)
+			}
+			if result := ValidateContext(context.Background(), v, []byte(tt.input)); !result.Valid {
+				t.Errorf("ValidateContext(Background) error = %s", result.Error)
+			}
+		})
+	}
+}
+
+func TestValidateContextMaxFileSize(t *testing.T) {
+	v, err := NewValidator(FormatJSON, WithMaxFileSize(4))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	result := ValidateContext(context.Background(), v, []byte(`{"a": 1}`))
+	if !result.Skipped || !strings.Contains(result.Error, "too large") {
+		t.Errorf("ValidateContext() = %+v, want a too large result", result)
+	}
+}
+
+func TestValidateContextPlainValidator(t *testing.T) {
+	// Validators with only Validate, like a bare JSONValidator, still satisfy Validator
+	var plain Validator = &JSONValidator{baseValidator{format: FormatJSON}}
+	if result := ValidateContext(context.Background(), plain, []byte(`{"a": 1}`)); !result.Valid {
+		t.Errorf("ValidateContext(plain) error = %s", result.Error)
+	}
+
+	// A validator that never checks ctx is stopped being waited for once it is done
+	release := make(chan struct{})
+	defer close(release)
+	blocking := NewFuncValidator("blocking", func([]byte) Result {
+		<-release
+
+		return Result{Valid: true}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result := ValidateContext(ctx, blocking, nil)
+	if !result.Skipped || result.Format != "blocking" || !strings.Contains(result.Error, "context deadline exceeded") {
+		t.Errorf("ValidateContext(blocking) = %+v, want a deadline result", result)
+	}
+}
+
+func TestValidateAutoContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	csv := "id,name\n" + strings.Repeat("1,Ada\n", 200000)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		input       string
+		wantValid   bool
+		wantSkipped bool
+	}{
+		{"background", context.Background(), `{"a": 1}`, true, false},
+		{"canceled before detection", canceled, `{"a": 1}`, false, true},
+		{"background with records", context.Background(), csv, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateAutoContext(tt.ctx, []byte(tt.input))
+			if result.Valid != tt.wantValid || result.Skipped != tt.wantSkipped {
+				t.Errorf("ValidateAutoContext() = %+v, want Valid %v and Skipped %v",
+					result, tt.wantValid, tt.wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// canonicalFormats are the formats Canonicalize rewrites.
+var canonicalFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML}
+
+// Canonicalize validates data as format and returns its canonical form, which is the same
+// for every document holding the same data however it is laid out, for hashing and change
+// detection. An empty format or serdeval.FormatAuto is detected from the content.
+//
+// JSON follows the JSON Canonicalization Scheme of RFC 8785: no whitespace, object keys
+// sorted by their UTF-16 code units, numbers written as the shortest IEEE 754 double that
+// reads back the same, and strings escaped only where JSON requires it. YAML and TOML
+// are rewritten with keys sorted the same way, YAML aliases and merge keys resolved, and
+// comments dropped.
+//
+// Example:
+//
+//	out, err := convert.Canonicalize([]byte(`{"b": 1.0, "a": "é"}`), serdeval.FormatJSON)
+//	// out is {"a":"é","b":1}
+func Canonicalize(data []byte, format serdeval.Format) ([]byte, error) {
+	text, format, err := load(data, format, "canonicalize", canonicalFormats)
+	if err != nil {
+		return nil, err
+	}
+	v, err := codecs[format].decode(text)
+	if err != nil {
+		return nil, err
+	}
+	sortKeys(v)
+	if format != serdeval.FormatJSON {
+		return codecs[format].encode(v)
+	}
+
+	var b bytes.Buffer
+	if err := writeCanonicalJSON(&b, v, ""); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// sortKeys sorts the keys of every object in v by their UTF-16 code units.
+func sortKeys(v any) {
+	switch t := v.(type) {
+	case *object:
+		slices.SortFunc(t.keys, compareUTF16)
+		for _, value := range t.values {
+			sortKeys(value)
+		}
+	case []any:
+		for _, item := range t {
+			sortKeys(item)
+		}
+	}
+}
+
+// compareUTF16 orders a and b by their UTF-16 code units, as RFC 8785 sorts keys. This
+// differs from byte order only for characters above U+FFFF.
+func compareUTF16(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+// writeCanonicalJSON writes v, found at path, to b as RFC 8785 canonical JSON. The keys
+// of objects must already be sorted.
+func writeCanonicalJSON(b *bytes.Buffer, v any, path string) error {
+	switch t := v.(type) {
+	case *object:
+		return writeCanonicalObject(b, t, path)
+	case []any:
+		return writeCanonicalArray(b, t, path)
+	case string:
+		writeCanonicalString(b, t)
+	case time.Time:
+		writeCanonicalString(b, formatTime(t))
+	case int64:
+		return writeCanonicalNumber(b, float64(t), path)
+	case uint64:
+		return writeCanonicalNumber(b, float64(t), path)
+	case *big.Int:
+		// RFC 8785 numbers are IEEE 754 doubles, so integers beyond 2^53 are rounded as
+		// every I-JSON reader rounds them
+		f, _ := new(big.Float).SetInt(t).Float64()
+
+		return writeCanonicalNumber(b, f, path)
+	case float64:
+		return writeCanonicalNumber(b, t, path)
+	case bool:
+		b.WriteString(strconv.FormatBool(t))
+	case nil:
+		b.WriteString("null")
+	default:
+		return fmt.Errorf("cannot canonicalize the %T at %s", v, displayPath(path))
+	}
+
+	return nil
+}
+
+// writeCanonicalObject writes o, found at path, to b, as writeCanonicalJSON does.
+func writeCanonicalObject(b *bytes.Buffer, o *object, path string) error {
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeCanonicalString(b, key)
+		b.WriteByte(':')
+		if err := writeCanonicalJSON(b, o.values[key], childPath(path, key)); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+
+	return nil
+}
+
+// writeCanonicalArray writes items, found at path, to b, as writeCanonicalJSON does.
+func writeCanonicalArray(b *bytes.Buffer, items []any, path string) error {
+	b.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := writeCanonicalJSON(b, item, childPath(path, strconv.Itoa(i))); err != nil {
+			return err
+		}
+	}
+	b.WriteByte(']')
+
+	return nil
+}
+
+// writeCanonicalNumber writes f to b as ECMAScript's Number.prototype.toString does,
+// which RFC 8785 adopts: integers up to 1e21 in full, other numbers in their shortest
+// round-tripping form, with an exponent outside 1e-7 to 1e21.
+func writeCanonicalNumber(b *bytes.Buffer, f float64, path string) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("cannot canonicalize %v at %s: JSON has no infinity or NaN", f, displayPath(path))
+	}
+	if f == 0 {
+		b.WriteByte('0')
+
+		return nil
+	}
+	if f < 0 {
+		b.WriteByte('-')
+		f = -f
+	}
+
+	// Shortest digits d.ddd and exponent e, so the decimal point follows digit n = e+1
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+	n, k := e+1, len(digits)
+
+	switch {
+	case k <= n && n <= 21:
+		b.WriteString(digits)
+		b.WriteString(strings.Repeat("0", n-k))
+	case 0 < n && n <= 21:
+		b.WriteString(digits[:n])
+		b.WriteByte('.')
+		b.WriteString(digits[n:])
+	case -6 < n && n <= 0:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", -n))
+		b.WriteString(digits)
+	default:
+		b.WriteString(mantissa)
+		b.WriteByte('e')
+		if e > 0 {
+			b.WriteByte('+')
+		}
+		b.WriteString(strconv.Itoa(e))
+	}
+
+	return nil
+}
+
+// writeCanonicalString writes s to b as a JSON string, escaping only quotes, backslashes,
+// and control characters, with the short escapes where JSON has them.
+func writeCanonicalString(b *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				b.WriteString(`\u00`)
+				b.WriteByte(hex[c>>4])
+				b.WriteByte(hex[c&0xf])
+
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+}
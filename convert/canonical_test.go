@@ -0,0 +1,90 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format serdeval.Format
+		want   string
+	}{
+		{
+			name:   "json sorts keys and drops whitespace",
+			input:  "{\n  \"b\": [true, null],\n  \"a\": {\"y\": \"<\", \"x\": {}}\n}\n",
+			format: serdeval.FormatJSON,
+			want:   `{"a":{"x":{},"y":"<"},"b":[true,null]}`,
+		},
+		{
+			name: "json numbers",
+			input: `[333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001, -0, 1e21, 1e20,` +
+				` 9007199254740993, -1.5e-7, 1e-6, 10, 123e-2]`,
+			format: serdeval.FormatJSON,
+			want: `[333333333.3333333,1e+30,4.5,0.002,1e-27,0,1e+21,100000000000000000000,` +
+				`9007199254740992,-1.5e-7,0.000001,10,1.23]`,
+		},
+		{
+			name:   "json strings",
+			input:  `["\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "\u00e9\t"]`,
+			format: serdeval.FormatJSON,
+			want:   `["€$\u000f\nA'B\"\\\\\"/","é\t"]`,
+		},
+		{
+			name:   "json keys in utf-16 order",
+			input:  `{"\u20ac": 1, "\r": 2, "\ufb33": 3, "1": 4, "\ud83d\ude00": 5, "\u0080": 6, "\u00f6": 7}`,
+			format: serdeval.FormatJSON,
+			want:   "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"ö\":7,\"€\":1,\"😀\":5,\"\ufb33\":3}",
+		},
+		{
+			name:   "yaml sorts keys and resolves aliases",
+			input:  "# app\nb: &x [1, 2]\na: {d: *x, c: 3}\n",
+			format: serdeval.FormatYAML,
+			want:   "a:\n  c: 3\n  d:\n    - 1\n    - 2\nb:\n  - 1\n  - 2\n",
+		},
+		{
+			name:   "toml",
+			input:  "b = 1 # one\na = 2\n",
+			format: serdeval.FormatTOML,
+			want:   "a = 2\nb = 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Canonicalize([]byte(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("Canonicalize() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Canonicalize() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  serdeval.Format
+		wantErr string
+	}{
+		{"invalid", `{"a": }`, serdeval.FormatJSON, "invalid character"},
+		{"unsupported", "<a/>", serdeval.FormatXML, "cannot canonicalize xml"},
+		{"undetected", "a,b\n1,2\n", serdeval.FormatAuto, "not detected as json, yaml, or toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Canonicalize([]byte(tt.input), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Canonicalize() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
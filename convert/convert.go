@@ -0,0 +1,231 @@
+// Package convert translates documents between JSON, YAML, TOML, and XML. It also
+// rewrites documents in their own format, with Format, Fix, Minify, and Canonicalize,
+// extracts values from them with Get, compares them with Diff, and patches them with
+// Apply.
+//
+// Validating a file and then converting it is a common pairing: a YAML manifest checked
+// in CI is often needed as JSON by the tool that applies it.
+//
+//	out, err := convert.Convert(data, serdeval.FormatYAML, serdeval.FormatJSON)
+//	if err != nil {
+//		var verr *serdeval.ValidationError
+//		if errors.As(err, &verr) {
+//			fmt.Printf("line %d: %s\n", verr.Line, verr.Message)
+//		}
+//	}
+//
+// Input is validated before it is converted, and a document that fails validation
+// returns its *serdeval.ValidationError. Objects keep their keys in document order,
+// except in TOML output, where keys are sorted and plain values come before tables.
+//
+// Not every document survives every conversion:
+//
+//   - XML maps to an object holding the root element. Attributes become keys prefixed
+//     with "@", text next to child elements becomes "#text", repeated child elements
+//     become arrays, and an empty element becomes null. Every value read from XML is a
+//     string, and comments and the order of mixed content are lost.
+//   - XML output needs an object with exactly one key, naming the root element.
+//   - TOML output needs an object at the top level and has no null.
+//   - JSON has no infinity or NaN, which YAML and TOML floats can hold.
+//   - Dates and times are written to JSON and XML as RFC 3339 strings. YAML timestamps
+//     with a time of day are read in UTC, and TOML times of day without a date become
+//     strings in YAML.
+//   - A YAML stream of several documents is rejected rather than cut to its first.
+package convert
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// indent is the indentation of JSON, YAML, and XML output.
+const indent = "  "
+
+// codec reads and writes one format as values made of *object, []any, string, int64,
+// uint64, *big.Int, float64, bool, time.Time, and nil.
+type codec struct {
+	decode func(data []byte) (any, error)
+	encode func(v any) ([]byte, error)
+}
+
+// codecs holds the readers and writers of convertFormats.
+var codecs = map[serdeval.Format]codec{
+	serdeval.FormatJSON: {decode: decodeJSON, encode: encodeJSON},
+	serdeval.FormatYAML: {decode: decodeYAML, encode: encodeYAML},
+	serdeval.FormatTOML: {decode: decodeTOML, encode: encodeTOML},
+	serdeval.FormatXML:  {decode: decodeXML, encode: encodeXML},
+}
+
+// Convert validates data as from and returns it rewritten as to. An empty from or
+// serdeval.FormatAuto detects which of the four formats the content is in. UTF-16 input and
+// byte order marks are decoded first, as for validation.
+//
+// Example:
+//
+//	out, err := convert.Convert([]byte("name: app\nreplicas: 3\n"), serdeval.FormatAuto, serdeval.FormatJSON)
+//	// out is {"name": "app", "replicas": 3} indented by two spaces
+func Convert(data []byte, from, to serdeval.Format) ([]byte, error) {
+	if _, ok := codecs[to]; !ok {
+		return nil, unsupported("convert", to, convertFormats)
+	}
+	text, from, err := load(data, from, "convert", convertFormats)
+	if err != nil {
+		return nil, err
+	}
+	v, err := codecs[from].decode(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return codecs[to].encode(v)
+}
+
+// convertFormats are the formats Convert reads and writes.
+var convertFormats = []serdeval.Format{
+	serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML, serdeval.FormatXML,
+}
+
+// load decodes data to UTF-8 and validates it as format, one of supported. An empty
+// format or serdeval.FormatAuto is detected from the content. The action, such as
+// "convert", names what failed in errors.
+func load(data []byte, format serdeval.Format, action string, supported []serdeval.Format) (
+	[]byte, serdeval.Format, error) {
+	text, _, err := serdeval.DecodeText(data)
+	if err != nil {
+		return nil, format, err
+	}
+	if format == "" || format == serdeval.FormatAuto {
+		if format = detect(text, supported); format == serdeval.FormatUnknown {
+			return nil, format, fmt.Errorf("cannot %s: the input was not detected as %s", action,
+				formatList(supported, "or"))
+		}
+	}
+	if !slices.Contains(supported, format) {
+		return nil, format, unsupported(action, format, supported)
+	}
+
+	validator, err := serdeval.NewValidator(format)
+	if err != nil {
+		return nil, format, err
+	}
+	if result := validator.Validate(text); !result.Valid {
+		return nil, format, result.Err
+	}
+
+	return text, format, nil
+}
+
+// detect returns the most likely format of data among supported, or
+// serdeval.FormatUnknown. TOML, for one, can also pass for INI.
+func detect(data []byte, supported []serdeval.Format) serdeval.Format {
+	for _, c := range serdeval.DetectFormatAll(data) {
+		if slices.Contains(supported, c.Format) {
+			return c.Format
+		}
+	}
+
+	return serdeval.FormatUnknown
+}
+
+// unsupported is the error for a format that action does not handle.
+func unsupported(action string, format serdeval.Format, supported []serdeval.Format) error {
+	return fmt.Errorf("cannot %s %s: supported formats are %s", action, format, formatList(supported, "and"))
+}
+
+// formatList names formats in a sentence joined by conj, such as "json, yaml, and toml".
+func formatList(formats []serdeval.Format, conj string) string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	if len(names) < 3 {
+		return strings.Join(names, " "+conj+" ")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + ", " + conj + " " + names[len(names)-1]
+}
+
+// object is a mapping that keeps its keys in document order.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+// newObject returns an empty object.
+func newObject() *object {
+	return &object{values: map[string]any{}}
+}
+
+// set sets key to v, adding key after the others unless it is already present.
+func (o *object) set(key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// remove removes key and its value.
+func (o *object) remove(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+
+			break
+		}
+	}
+}
+
+// childPath returns the JSON Pointer of key under path, for locating values that cannot
+// be written in the target format.
+func childPath(path, key string) string {
+	return path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// displayPath returns path for an error message, naming the root "/".
+func displayPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
+
+// formatTime writes t as RFC 3339, or as its date, time, or date and time alone when it
+// was read from a TOML local date, time, or date-time, which have no offset.
+func formatTime(t time.Time) string {
+	switch t.Location().String() {
+	case "date-local":
+		return t.Format(time.DateOnly)
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+// scalarText returns the text of a scalar value, as used in XML.
+func scalarText(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64)
+	case time.Time:
+		return formatTime(t)
+	case nil:
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,192 @@
+package convert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		from, to serdeval.Format
+		want     string
+	}{
+		{
+			name:  "yaml to json keeps key order and resolves merges",
+			input: "base: &b {x: 1, y: 2}\nname: app\nobj:\n  <<: *b\n  y: 3\ntags: [a, \"true\", <b>]\n",
+			from:  serdeval.FormatYAML, to: serdeval.FormatJSON,
+			want: `{
+  "base": {
+    "x": 1,
+    "y": 2
+  },
+  "name": "app",
+  "obj": {
+    "x": 1,
+    "y": 3
+  },
+  "tags": [
+    "a",
+    "true",
+    "<b>"
+  ]
+}
+`,
+		},
+		{
+			name:  "json to yaml quotes strings that would change type",
+			input: `{"z": "true", "a": [1, 2.5, null], "e": {}}`,
+			from:  serdeval.FormatJSON, to: serdeval.FormatYAML,
+			want: "z: \"true\"\na:\n  - 1\n  - 2.5\n  - null\ne: {}\n",
+		},
+		{
+			name:  "json integers beyond int64 stay exact",
+			input: `{"id": 12345678901234567890, "big": 123456789012345678901234567890, "f": 1.5}`,
+			from:  serdeval.FormatJSON, to: serdeval.FormatYAML,
+			want: "id: 12345678901234567890\nbig: !!int 123456789012345678901234567890\nf: 1.5\n",
+		},
+		{
+			name:  "toml local dates and times to json",
+			input: "day = 1979-05-27\nat = 07:32:00\nlocal = 1979-05-27T07:32:00\nzoned = 1979-05-27T07:32:00Z\n",
+			from:  serdeval.FormatTOML, to: serdeval.FormatJSON,
+			want: `{
+  "day": "1979-05-27",
+  "at": "07:32:00",
+  "local": "1979-05-27T07:32:00",
+  "zoned": "1979-05-27T07:32:00Z"
+}
+`,
+		},
+		{
+			name:  "yaml date to toml local date",
+			input: "released: 2001-12-14\n",
+			from:  serdeval.FormatYAML, to: serdeval.FormatTOML,
+			want: "released = 2001-12-14\n",
+		},
+		{
+			name:  "json array of objects to toml array of tables",
+			input: `{"title": "x", "servers": [{"host": "a"}, {"host": "b"}]}`,
+			from:  serdeval.FormatJSON, to: serdeval.FormatTOML,
+			want: "title = \"x\"\n\n[[servers]]\n  host = \"a\"\n\n[[servers]]\n  host = \"b\"\n",
+		},
+		{
+			name:  "toml keeps definition order",
+			input: "zeta = 1\nalpha = 2\n\n[[item]]\nname = \"a\"\nid = 1\n",
+			from:  serdeval.FormatTOML, to: serdeval.FormatJSON,
+			want: `{
+  "zeta": 1,
+  "alpha": 2,
+  "item": [
+    {
+      "name": "a",
+      "id": 1
+    }
+  ]
+}
+`,
+		},
+		{
+			name:  "xml to json",
+			input: `<cat x:v="1" xmlns:x="urn:x"><book id="1"><title>A &amp; B</title><empty/></book><book>Two</book></cat>`,
+			from:  serdeval.FormatXML, to: serdeval.FormatJSON,
+			want: `{
+  "cat": {
+    "@x:v": "1",
+    "@xmlns:x": "urn:x",
+    "book": [
+      {
+        "@id": "1",
+        "title": "A & B",
+        "empty": null
+      },
+      "Two"
+    ]
+  }
+}
+`,
+		},
+		{
+			name:  "json to xml",
+			input: `{"cat": {"@id": 7, "book": [{"title": "A & B"}, {"#text": "Two", "@lang": "en"}], "n": null}}`,
+			from:  serdeval.FormatJSON, to: serdeval.FormatXML,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<cat id="7">
+  <book>
+    <title>A &amp; B</title>
+  </book>
+  <book lang="en">Two</book>
+  <n></n>
+</cat>
+`,
+		},
+		{
+			name:  "auto detects toml that also passes for ini",
+			input: "[server]\nport = 8080\n",
+			from:  serdeval.FormatAuto, to: serdeval.FormatJSON,
+			want: "{\n  \"server\": {\n    \"port\": 8080\n  }\n}\n",
+		},
+		{
+			name:  "utf-8 byte order mark",
+			input: "\xef\xbb\xbf{\"a\": 1}",
+			from:  serdeval.FormatJSON, to: serdeval.FormatYAML,
+			want: "a: 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert([]byte(tt.input), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Convert() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		from, to serdeval.Format
+		wantErr  string
+	}{
+		{"unsupported source", "a,b\n1,2\n", serdeval.FormatCSV, serdeval.FormatJSON, "cannot convert csv"},
+		{"unsupported target", `{}`, serdeval.FormatJSON, serdeval.FormatINI, "cannot convert ini"},
+		{"undetected", "a,b\n1,2\n", serdeval.FormatAuto, serdeval.FormatJSON, "not detected as json"},
+		{"null in toml", `{"a": {"b": null}}`, serdeval.FormatJSON, serdeval.FormatTOML, "null at /a/b"},
+		{"array in toml", `[1]`, serdeval.FormatJSON, serdeval.FormatTOML, "must be an object"},
+		{"big integer in toml", `{"a": 12345678901234567890}`, serdeval.FormatJSON, serdeval.FormatTOML, "64-bit"},
+		{"nan in json", "a: [.nan]\n", serdeval.FormatYAML, serdeval.FormatJSON, "NaN at /a/0"},
+		{"several xml roots", `{"a": 1, "b": 2}`, serdeval.FormatJSON, serdeval.FormatXML, "one key"},
+		{"bad xml name", `{"a": {"1b": 2}}`, serdeval.FormatJSON, serdeval.FormatXML, `"1b" at /a/1b`},
+		{"nested xml array", `{"a": {"b": [[1]]}}`, serdeval.FormatJSON, serdeval.FormatXML, "at /a/b/0"},
+		{"yaml stream", "a: 1\n---\nb: 2\n", serdeval.FormatYAML, serdeval.FormatJSON, "several documents"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Convert([]byte(tt.input), tt.from, tt.to)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Convert() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConvertInvalidInput(t *testing.T) {
+	_, err := Convert([]byte("{\n  \"a\": 1,\n}"), serdeval.FormatJSON, serdeval.FormatYAML)
+	var verr *serdeval.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Convert() error = %v, want a *serdeval.ValidationError", err)
+	}
+	if verr.Code != serdeval.ErrCodeInvalid || verr.Line != 3 {
+		t.Errorf("ValidationError = %+v, want code invalid on line 3", verr)
+	}
+}
@@ -0,0 +1,222 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// diffFormats are the formats Diff reads.
+var diffFormats = []serdeval.Format{
+	serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML, serdeval.FormatXML,
+}
+
+// ChangeKind says how a value differs between two documents.
+type ChangeKind string
+
+// Kinds of Change.
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is one difference Diff reports.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Path is the JSON Pointer of the value, "" for the whole document
+	Path string `json:"path"`
+	// Old is the value in the first document as compact JSON, unless Kind is ChangeAdded
+	Old json.RawMessage `json:"old,omitempty"`
+	// New is the value in the second document as compact JSON, unless Kind is ChangeRemoved
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+// Diff validates two JSON, YAML, TOML, or XML documents, detecting the format of each,
+// and returns how the data of b differs from that of a. See DiffAs.
+//
+// Example:
+//
+//	changes, err := convert.Diff([]byte("replicas: 3\n"), []byte(`{"replicas": 5}`))
+//	// changes is [{changed /replicas 3 5}]
+func Diff(a, b []byte) ([]Change, error) {
+	return DiffAs(a, serdeval.FormatAuto, b, serdeval.FormatAuto)
+}
+
+// DiffAs validates a as aFormat and b as bFormat and returns how the data of b differs
+// from that of a, in document order; no changes means the documents hold the same data.
+// Layout, comments, and the order of object keys are ignored, and numbers compare by
+// value, so 1 equals 1.0. Array items are compared by index: an item inserted in the
+// middle changes every item after it.
+func DiffAs(a []byte, aFormat serdeval.Format, b []byte, bFormat serdeval.Format) ([]Change, error) {
+	va, err := decodeAs(a, aFormat)
+	if err != nil {
+		return nil, &DocumentError{Index: 0, Err: err}
+	}
+	vb, err := decodeAs(b, bFormat)
+	if err != nil {
+		return nil, &DocumentError{Index: 1, Err: err}
+	}
+
+	d := differ{}
+	d.compare(va, vb, "")
+
+	return d.changes, d.err
+}
+
+// DocumentError reports which of several documents given to a function could not be
+// read, such as one that fails validation.
+type DocumentError struct {
+	// Index is the position of the document among the arguments, 0 for the first
+	Index int
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("document %d: %v", e.Index+1, e.Err)
+}
+
+// Unwrap returns the underlying error, such as a *serdeval.ValidationError.
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// decodeAs validates data as format, one of diffFormats, and returns its value.
+func decodeAs(data []byte, format serdeval.Format) (any, error) {
+	text, format, err := load(data, format, "diff", diffFormats)
+	if err != nil {
+		return nil, err
+	}
+
+	return codecs[format].decode(text)
+}
+
+// differ collects the changes between two values.
+type differ struct {
+	changes []Change
+	err     error
+}
+
+// compare records the changes from a to b, both found at path.
+func (d *differ) compare(a, b any, path string) {
+	switch ta := a.(type) {
+	case *object:
+		if tb, ok := b.(*object); ok {
+			d.compareObjects(ta, tb, path)
+
+			return
+		}
+	case []any:
+		if tb, ok := b.([]any); ok {
+			d.compareArrays(ta, tb, path)
+
+			return
+		}
+	}
+	if !equalScalars(a, b) {
+		d.add(ChangeChanged, path, a, b)
+	}
+}
+
+// compareObjects records the changes from the members of a to those of b.
+func (d *differ) compareObjects(a, b *object, path string) {
+	for _, key := range a.keys {
+		if vb, ok := b.values[key]; ok {
+			d.compare(a.values[key], vb, childPath(path, key))
+		} else {
+			d.add(ChangeRemoved, childPath(path, key), a.values[key], nil)
+		}
+	}
+	for _, key := range b.keys {
+		if _, ok := a.values[key]; !ok {
+			d.add(ChangeAdded, childPath(path, key), nil, b.values[key])
+		}
+	}
+}
+
+// compareArrays records the changes from the items of a to those of b.
+func (d *differ) compareArrays(a, b []any, path string) {
+	for i := range max(len(a), len(b)) {
+		at := childPath(path, strconv.Itoa(i))
+		switch {
+		case i >= len(b):
+			d.add(ChangeRemoved, at, a[i], nil)
+		case i >= len(a):
+			d.add(ChangeAdded, at, nil, b[i])
+		default:
+			d.compare(a[i], b[i], at)
+		}
+	}
+}
+
+// add records a change of kind at path from a to b, where a or b is absent for removals
+// and additions.
+func (d *differ) add(kind ChangeKind, path string, a, b any) {
+	c := Change{Kind: kind, Path: path}
+	if kind != ChangeAdded {
+		c.Old = d.json(a, path)
+	}
+	if kind != ChangeRemoved {
+		c.New = d.json(b, path)
+	}
+	d.changes = append(d.changes, c)
+}
+
+// json returns v, found at path, as compact JSON, keeping the first error.
+func (d *differ) json(v any, path string) json.RawMessage {
+	var b bytes.Buffer
+	if err := writeJSON(&b, v, "", "", path); err != nil && d.err == nil {
+		d.err = err
+	}
+
+	return b.Bytes()
+}
+
+// equalScalars reports whether two values that are not both objects or both arrays are
+// equal, comparing numbers by exact value and times by instant.
+func equalScalars(a, b any) bool {
+	if na, ok := exactNumber(a); ok {
+		nb, isNumber := exactNumber(b)
+
+		return isNumber && (na == nil && nb == nil || na != nil && nb != nil && na.Cmp(nb) == 0)
+	}
+	if ta, ok := a.(time.Time); ok {
+		tb, isTime := b.(time.Time)
+
+		return isTime && ta.Equal(tb)
+	}
+	switch a.(type) {
+	case *object, []any:
+		return false
+	}
+
+	return a == b
+}
+
+// exactNumber returns v as a big.Float holding it exactly if it is a number, so integers
+// beyond 2^53 compare without rounding. NaN, which a big.Float cannot hold, is nil.
+func exactNumber(v any) (*big.Float, bool) {
+	switch t := v.(type) {
+	case int64:
+		return new(big.Float).SetInt64(t), true
+	case uint64:
+		return new(big.Float).SetUint64(t), true
+	case *big.Int:
+		return new(big.Float).SetInt(t), true
+	case float64:
+		if math.IsNaN(t) {
+			return nil, true
+		}
+
+		return new(big.Float).SetFloat64(t), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,95 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{
+			name: "same data in another format and order",
+			a:    "# app\nname: app\nreplicas: 3\nratio: 0.5\n",
+			b:    `{"ratio": 0.50, "replicas": 3.0, "name": "app"}`,
+			want: nil,
+		},
+		{
+			name: "added, removed, and changed members",
+			a:    `{"name": "app", "spec": {"replicas": 3, "debug": true}}`,
+			b:    "name: app\nspec:\n  replicas: 5\n  image: nginx\n",
+			want: []string{
+				"changed /spec/replicas 3 5",
+				"removed /spec/debug true ",
+				"added /spec/image  \"nginx\"",
+			},
+		},
+		{
+			name: "array items by index",
+			a:    `{"ports": [80, 443, 8080]}`,
+			b:    `{"ports": [80, 8443]}`,
+			want: []string{"changed /ports/1 443 8443", "removed /ports/2 8080 "},
+		},
+		{
+			name: "type change",
+			a:    `{"a": {"b": 1}}`,
+			b:    `{"a": [1]}`,
+			want: []string{`changed /a {"b":1} [1]`},
+		},
+		{
+			name: "large integers",
+			a:    `{"id": 9007199254740993}`,
+			b:    `{"id": 9007199254740992}`,
+			want: []string{"changed /id 9007199254740993 9007199254740992"},
+		},
+		{
+			name: "integers beyond uint64",
+			a:    `{"id": 12345678901234567890, "big": 123456789012345678901234567890}`,
+			b:    `{"id": 12345678901234567891, "big": 123456789012345678901234567890}`,
+			want: []string{"changed /id 12345678901234567890 12345678901234567891"},
+		},
+		{
+			name: "escaped keys",
+			a:    `{"a/b": 1}`,
+			b:    `{"a/b": null}`,
+			want: []string{"changed /a~1b 1 null"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff([]byte(tt.a), []byte(tt.b))
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			got := make([]string, len(changes))
+			for i, c := range changes {
+				got[i] = fmt.Sprintf("%s %s %s %s", c.Kind, c.Path, string(c.Old), string(c.New))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Diff() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestDiffAsErrors(t *testing.T) {
+	_, err := DiffAs([]byte(`{}`), serdeval.FormatJSON, []byte("a,b\n"), serdeval.FormatCSV)
+	if err == nil || !strings.Contains(err.Error(), "cannot diff csv") {
+		t.Errorf("DiffAs() error = %v, want it to contain %q", err, "cannot diff csv")
+	}
+
+	_, err = DiffAs([]byte(`{}`), serdeval.FormatJSON, []byte("{\n\"a\":"), serdeval.FormatJSON)
+	var docErr *DocumentError
+	var verr *serdeval.ValidationError
+	if !errors.As(err, &docErr) || docErr.Index != 1 || !errors.As(err, &verr) || verr.Line != 2 {
+		t.Errorf("DiffAs() error = %v, want a validation error on line 2 of document 2", err)
+	}
+}
@@ -0,0 +1,108 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// fixFormats are the formats Fix reformats. Other content only gets the encoding and
+// line ending repairs.
+var fixFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML}
+
+// Fix applies the repairs that cannot change what a document means and returns the
+// result with a short description of each repair made, in order:
+//
+//   - UTF-16 text is decoded to UTF-8 and a byte order mark is removed.
+//   - CRLF and lone CR line endings become LF.
+//   - JSON that only fails because of trailing commas before "}" or "]", as JSONC allows,
+//     has them removed.
+//   - JSON, YAML, and TOML are reformatted in style, as Format lays them out.
+//
+// An empty format or serdeval.FormatAuto is detected from the content; content detected
+// as none of JSON, YAML, or TOML, like a format outside them, only gets the first two
+// repairs. A JSON, YAML, or TOML document that is still invalid after them returns its
+// *serdeval.ValidationError, as nothing safe can fix it.
+//
+// Example:
+//
+//	out, fixes, err := convert.Fix([]byte("{\"a\": [1, 2,],}\r\n"), serdeval.FormatJSON, convert.FormatStyle{})
+//	// fixes is ["converted CRLF line endings to LF", "removed trailing commas", "reformatted"]
+func Fix(data []byte, format serdeval.Format, style FormatStyle) ([]byte, []string, error) {
+	var fixes []string
+	text, enc, err := serdeval.DecodeText(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch enc {
+	case serdeval.EncodingUTF8BOM:
+		fixes = append(fixes, "removed byte order mark")
+	case serdeval.EncodingUTF16LE, serdeval.EncodingUTF16BE:
+		fixes = append(fixes, fmt.Sprintf("converted %s to UTF-8", enc))
+	}
+
+	if bytes.IndexByte(text, '\r') >= 0 {
+		text = bytes.ReplaceAll(bytes.ReplaceAll(text, []byte("\r\n"), []byte("\n")), []byte("\r"), []byte("\n"))
+		fixes = append(fixes, "converted CRLF line endings to LF")
+	}
+
+	if format == "" || format == serdeval.FormatAuto {
+		format = detect(text, fixFormats)
+	}
+	if !slices.Contains(fixFormats, format) {
+		return text, fixes, nil
+	}
+
+	if format == serdeval.FormatJSON && !valid(text, format) {
+		if trimmed := removeTrailingCommas(text); valid(trimmed, format) {
+			text = trimmed
+			fixes = append(fixes, "removed trailing commas")
+		}
+	}
+
+	out, err := Format(text, format, style)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(out, text) {
+		fixes = append(fixes, "reformatted")
+	}
+
+	return out, fixes, nil
+}
+
+// valid reports whether text validates as format.
+func valid(text []byte, format serdeval.Format) bool {
+	validator, err := serdeval.NewValidator(format)
+
+	return err == nil && validator.Validate(text).Valid
+}
+
+// removeTrailingCommas drops every comma outside a string that is followed, after
+// whitespace, by "}" or "]".
+func removeTrailingCommas(text []byte) []byte {
+	out := make([]byte, 0, len(text))
+	inString := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(text):
+			out = append(out, c, text[i+1])
+			i++
+
+			continue
+		case c == '"':
+			inString = !inString
+		case !inString && c == ',':
+			rest := bytes.TrimLeft(text[i+1:], " \t\r\n")
+			if len(rest) > 0 && (rest[0] == '}' || rest[0] == ']') {
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+
+	return out
+}
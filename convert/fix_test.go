@@ -0,0 +1,77 @@
+package convert
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestFix(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		format    serdeval.Format
+		want      string
+		wantFixes []string
+	}{
+		{
+			name:      "json trailing commas and crlf",
+			input:     "{\"a\": [1, 2,],\r\n \"s\": \",]\",\r\n}\r\n",
+			format:    serdeval.FormatJSON,
+			want:      "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"s\": \",]\"\n}\n",
+			wantFixes: []string{"converted CRLF line endings to LF", "removed trailing commas", "reformatted"},
+		},
+		{
+			name:      "utf-8 byte order mark",
+			input:     "\xef\xbb\xbfa: 1\n",
+			format:    serdeval.FormatAuto,
+			want:      "a: 1\n",
+			wantFixes: []string{"removed byte order mark"},
+		},
+		{
+			name:      "utf-16",
+			input:     "\xff\xfe{\x00}\x00",
+			format:    serdeval.FormatJSON,
+			want:      "{}\n",
+			wantFixes: []string{"converted utf-16le to UTF-8", "reformatted"},
+		},
+		{
+			name:   "already tidy",
+			input:  "title = \"x\"\n",
+			format: serdeval.FormatTOML,
+			want:   "title = \"x\"\n",
+		},
+		{
+			name:      "other formats only get line endings fixed",
+			input:     "[a]\r\nb = 1\r\n",
+			format:    serdeval.FormatINI,
+			want:      "[a]\nb = 1\n",
+			wantFixes: []string{"converted CRLF line endings to LF"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes, err := Fix([]byte(tt.input), tt.format, FormatStyle{})
+			if err != nil {
+				t.Fatalf("Fix() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Fix() =\n%s\nwant:\n%s", got, tt.want)
+			}
+			if !slices.Equal(fixes, tt.wantFixes) {
+				t.Errorf("Fix() fixes = %q, want %q", fixes, tt.wantFixes)
+			}
+		})
+	}
+}
+
+func TestFixInvalid(t *testing.T) {
+	_, _, err := Fix([]byte("{\"a\": 1,, }"), serdeval.FormatJSON, FormatStyle{})
+	var verr *serdeval.ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("Fix() error = %v, want a *serdeval.ValidationError", err)
+	}
+}
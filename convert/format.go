@@ -0,0 +1,139 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// FormatStyle configures Format. The zero value indents by two spaces.
+type FormatStyle struct {
+	// Indent is the number of spaces per nesting level in JSON, YAML, and XML; zero means 2
+	Indent int
+	// Tabs indents JSON and XML with one tab per level instead of spaces. YAML does not
+	// allow tabs in indentation.
+	Tabs bool
+}
+
+// indentString returns the indentation of one nesting level.
+func (s FormatStyle) indentString() string {
+	if s.Tabs {
+		return "\t"
+	}
+	if s.Indent <= 0 {
+		return indent
+	}
+
+	return strings.Repeat(" ", s.Indent)
+}
+
+// formatFormats are the formats Format rewrites.
+var formatFormats = []serdeval.Format{
+	serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML, serdeval.FormatXML, serdeval.FormatCSV,
+}
+
+// Format validates data as format and returns it laid out consistently in style. An
+// empty format or serdeval.FormatAuto is detected from the content. Only layout
+// changes; the document reads back the same:
+//
+//   - JSON is indented one member per line, keeping key order, numbers, and escapes as
+//     written.
+//   - YAML is re-indented, keeping comments, anchors, and every document of a stream.
+//   - XML is indented one element per line, keeping comments, processing instructions,
+//     and text; whitespace between elements is replaced and empty elements self-close.
+//   - TOML keeps its lines and comments. Spacing around "=" becomes a single space,
+//     trailing whitespace and runs of blank lines are removed, and table headers are
+//     set off by a blank line. Indentation is left as written.
+//   - CSV keeps its delimiter, quotes only the fields that need it, and ends lines with
+//     "\n".
+//
+// Example:
+//
+//	out, err := convert.Format([]byte(`{"a":[1,2]}`), serdeval.FormatJSON, convert.FormatStyle{Indent: 4})
+//	// out is {"a": [1, 2]} with each member and item on its own line, indented by 4 spaces
+func Format(data []byte, format serdeval.Format, style FormatStyle) ([]byte, error) {
+	text, format, err := load(data, format, "format", formatFormats)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case serdeval.FormatJSON:
+		return formatJSON(text, style.indentString())
+	case serdeval.FormatYAML:
+		if style.Tabs {
+			return nil, errors.New("cannot format YAML with tabs: YAML only allows spaces in indentation")
+		}
+
+		return formatYAML(text, len(style.indentString()))
+	case serdeval.FormatTOML:
+		return formatTOML(text), nil
+	case serdeval.FormatXML:
+		return formatXML(text, style.indentString())
+	}
+
+	return formatCSV(text)
+}
+
+// formatJSON indents a valid JSON document.
+func formatJSON(text []byte, indent string) ([]byte, error) {
+	var b bytes.Buffer
+	if err := json.Indent(&b, bytes.TrimSpace(text), "", indent); err != nil {
+		return nil, err
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+// formatYAML re-indents every document of a YAML stream by n spaces per level.
+func formatYAML(text []byte, n int) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(text))
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(n)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// formatCSV rewrites a valid CSV document with its own delimiter.
+func formatCSV(text []byte) ([]byte, error) {
+	r := csv.NewReader(bytes.NewReader(text))
+	r.Comma = serdeval.CSVDialect{}.DelimiterFor(text)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Comma = r.Comma
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
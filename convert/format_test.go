@@ -0,0 +1,130 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format serdeval.Format
+		style  FormatStyle
+		want   string
+	}{
+		{
+			name:   "json keeps numbers and key order",
+			input:  `{"z":1.50,"a":[1e3,{}],"s":"<\u00e9>"}`,
+			format: serdeval.FormatJSON,
+			want:   "{\n  \"z\": 1.50,\n  \"a\": [\n    1e3,\n    {}\n  ],\n  \"s\": \"<\\u00e9>\"\n}\n",
+		},
+		{
+			name:   "json with four spaces",
+			input:  `[1]`,
+			format: serdeval.FormatJSON,
+			style:  FormatStyle{Indent: 4},
+			want:   "[\n    1\n]\n",
+		},
+		{
+			name:   "json with tabs",
+			input:  `{"a": {"b": true}}`,
+			format: serdeval.FormatJSON,
+			style:  FormatStyle{Tabs: true},
+			want:   "{\n\t\"a\": {\n\t\t\"b\": true\n\t}\n}\n",
+		},
+		{
+			name:   "yaml keeps comments and documents",
+			input:  "# app\nname:   app\nlist:\n    - a    # first\n    - b\n---\nother: 1\n",
+			format: serdeval.FormatYAML,
+			want:   "# app\nname: app\nlist:\n  - a # first\n  - b\n---\nother: 1\n",
+		},
+		{
+			name:   "yaml with four spaces",
+			input:  "a:\n  b: 1\n",
+			format: serdeval.FormatYAML,
+			style:  FormatStyle{Indent: 4},
+			want:   "a:\n    b: 1\n",
+		},
+		{
+			name: "toml spacing and blank lines",
+			input: "title=\"x\"   \n\n\n# server\n[server]\nport   =  8080 # http\nhosts = [\n  \"a\",  \n]\n" +
+				"note = \"\"\"\nkeep = this  \n\"\"\"\n[db]\nurl='a=b'\n",
+			format: serdeval.FormatTOML,
+			want: "title = \"x\"\n\n# server\n[server]\nport = 8080 # http\nhosts = [\n  \"a\",\n]\n" +
+				"note = \"\"\"\nkeep = this  \n\"\"\"\n\n[db]\nurl = 'a=b'\n",
+		},
+		{
+			name: "xml",
+			input: `<?xml version="1.0"?><!-- c --><cat a="x&amp;y"><book><title>A &amp; B</title><empty></empty>` +
+				`</book><p>Some <b>bold</b> text</p></cat>`,
+			format: serdeval.FormatXML,
+			want: `<?xml version="1.0"?>
+<!-- c -->
+<cat a="x&amp;y">
+  <book>
+    <title>A &amp; B</title>
+    <empty/>
+  </book>
+  <p>Some <b>bold</b> text</p>
+</cat>
+`,
+		},
+		{
+			name:   "xml with tabs",
+			input:  "<a>\n    <b>1</b>\n</a>",
+			format: serdeval.FormatXML,
+			style:  FormatStyle{Tabs: true},
+			want:   "<a>\n\t<b>1</b>\n</a>\n",
+		},
+		{
+			name:   "csv keeps its delimiter",
+			input:  "name;note\r\n\"Ada\";\"a;b\"\r\n",
+			format: serdeval.FormatCSV,
+			want:   "name;note\nAda;\"a;b\"\n",
+		},
+		{
+			name:   "auto",
+			input:  `{"a":1}`,
+			format: serdeval.FormatAuto,
+			want:   "{\n  \"a\": 1\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format([]byte(tt.input), tt.format, tt.style)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  serdeval.Format
+		style   FormatStyle
+		wantErr string
+	}{
+		{"invalid", `{"a":}`, serdeval.FormatJSON, FormatStyle{}, "invalid character"},
+		{"unsupported", "[a]\nb=1\n", serdeval.FormatINI, FormatStyle{}, "cannot format ini"},
+		{"yaml tabs", "a: 1\n", serdeval.FormatYAML, FormatStyle{Tabs: true}, "only allows spaces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Format([]byte(tt.input), tt.format, tt.style)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Format() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// getFormats are the formats Get reads.
+var getFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML}
+
+// Get validates data as format, JSON, YAML, or TOML, and returns the values path selects,
+// each as compact JSON with object keys in document order. An empty format or
+// serdeval.FormatAuto is detected from the content.
+//
+// A path starting with "/" is a JSON Pointer (RFC 6901), which selects exactly one value
+// and is an error when nothing is there; "" selects the whole document. A path starting
+// with "$" or "." is a JSONPath, which selects any number of values and supports
+//
+//   - .name and ['name'] for an object member,
+//   - [n] for an array item, counting from the end when n is negative,
+//   - .* and [*] for every member or item, and
+//   - ..name, ..* and ..[n] to match at any depth below.
+//
+// Example:
+//
+//	values, err := convert.Get([]byte("spec:\n  replicas: 3\n"), serdeval.FormatYAML, "$.spec.replicas")
+//	// values is [3]
+func Get(data []byte, format serdeval.Format, path string) ([]json.RawMessage, error) {
+	text, format, err := load(data, format, "query", getFormats)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := codecs[format].decode(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []any
+	switch {
+	case path == "" || path[0] == '/':
+		var v any
+		v, err = resolvePointer(doc, path)
+		matches = []any{v}
+	case path[0] == '$' || path[0] == '.':
+		matches, err = selectJSONPath(doc, path)
+	default:
+		err = fmt.Errorf("invalid path %q: start a JSON Pointer with \"/\" or a JSONPath with \"$\"", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]json.RawMessage, len(matches))
+	for i, v := range matches {
+		var b bytes.Buffer
+		if err := writeJSON(&b, v, "", "", ""); err != nil {
+			return nil, err
+		}
+		values[i] = b.Bytes()
+	}
+
+	return values, nil
+}
+
+// resolvePointer returns the value the JSON Pointer pointer refers to in doc.
+func resolvePointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+
+	v, at := doc, ""
+	for _, token := range strings.Split(pointer[1:], "/") {
+		key := strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		at = childPath(at, key)
+		var ok bool
+		switch t := v.(type) {
+		case *object:
+			v, ok = t.values[key]
+		case []any:
+			var i int
+			i, ok = pointerIndex(token, len(t))
+			if ok {
+				v = t[i]
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("no value at %s", at)
+		}
+	}
+
+	return v, nil
+}
+
+// pointerIndex parses a JSON Pointer array index, which has no sign or leading zeros, and
+// reports whether it is within an array of n items.
+func pointerIndex(token string, n int) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.Trim(token, "0123456789") != "" {
+		return 0, false
+	}
+	i, err := strconv.Atoi(token)
+
+	return i, err == nil && i < n
+}
+
+// pathStep is one step of a JSONPath: a member name, an array index, or a wildcard,
+// matched against the current values or, when descendant is set, every value below them.
+type pathStep struct {
+	name       string
+	index      int
+	isIndex    bool
+	wildcard   bool
+	descendant bool
+}
+
+// selectJSONPath returns the values of doc that the JSONPath path selects.
+func selectJSONPath(doc any, path string) ([]any, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath %q: %w", path, err)
+	}
+
+	values := []any{doc}
+	for _, step := range steps {
+		var next []any
+		for _, v := range values {
+			if !step.descendant {
+				next = step.match(next, v)
+
+				continue
+			}
+			for _, d := range descendants(nil, v) {
+				next = step.match(next, d)
+			}
+		}
+		values = next
+	}
+
+	return values, nil
+}
+
+// parseJSONPath splits path into its steps.
+func parseJSONPath(path string) ([]pathStep, error) {
+	rest := strings.TrimPrefix(path, "$")
+	var steps []pathStep
+	for rest != "" {
+		step := pathStep{descendant: strings.HasPrefix(rest, "..")}
+		dotted := step.descendant || rest[0] == '.'
+		if dotted {
+			rest = strings.TrimPrefix(rest[1:], ".")
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(rest, "["):
+			rest, err = step.parseBracket(rest)
+		case dotted:
+			rest, err = step.parseName(rest)
+		default:
+			err = fmt.Errorf("unexpected %q", rest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// parseName parses the member name or * at the start of rest, up to the next "." or "[",
+// and returns what follows.
+func (step *pathStep) parseName(rest string) (string, error) {
+	end := strings.IndexAny(rest, ".[")
+	if end < 0 {
+		end = len(rest)
+	}
+	if end == 0 {
+		return "", errors.New("missing name after \".\"")
+	}
+	step.name, step.wildcard = rest[:end], rest[:end] == "*"
+
+	return rest[end:], nil
+}
+
+// parseBracket parses the bracketed selector at the start of rest, such as [0], [*], or
+// ['name'], and returns what follows.
+func (step *pathStep) parseBracket(rest string) (string, error) {
+	if len(rest) > 1 && (rest[1] == '\'' || rest[1] == '"') {
+		name, after, err := parseQuoted(rest[1:])
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(after, "]") {
+			return "", errors.New("missing \"]\" after a quoted name")
+		}
+		step.name = name
+
+		return after[1:], nil
+	}
+
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return "", errors.New("missing \"]\"")
+	}
+	inside := rest[1:end]
+	if inside == "*" {
+		step.wildcard = true
+
+		return rest[end+1:], nil
+	}
+	i, err := strconv.Atoi(inside)
+	if err != nil {
+		return "", fmt.Errorf("unsupported selector [%s]: use a quoted name, an index, or *", inside)
+	}
+	step.index, step.isIndex = i, true
+
+	return rest[end+1:], nil
+}
+
+// parseQuoted reads the quoted name at the start of s, where a backslash escapes the
+// next character, and returns it and what follows the closing quote.
+func parseQuoted(s string) (string, string, error) {
+	quote := s[0]
+	var name strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			return name.String(), s[i+1:], nil
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", "", errors.New("unterminated name")
+			}
+		}
+		name.WriteByte(s[i])
+	}
+
+	return "", "", errors.New("unterminated name")
+}
+
+// match appends the values step selects in v to matches.
+func (step pathStep) match(matches []any, v any) []any {
+	switch t := v.(type) {
+	case *object:
+		if step.wildcard {
+			for _, key := range t.keys {
+				matches = append(matches, t.values[key])
+			}
+		} else if value, ok := t.values[step.name]; ok && !step.isIndex {
+			matches = append(matches, value)
+		}
+	case []any:
+		i := step.index
+		if i < 0 {
+			i += len(t)
+		}
+		switch {
+		case step.wildcard:
+			matches = append(matches, t...)
+		case step.isIndex && i >= 0 && i < len(t):
+			matches = append(matches, t[i])
+		}
+	}
+
+	return matches
+}
+
+// descendants appends v and every value nested in it, in document order, to values.
+func descendants(values []any, v any) []any {
+	values = append(values, v)
+	switch t := v.(type) {
+	case *object:
+		for _, key := range t.keys {
+			values = descendants(values, t.values[key])
+		}
+	case []any:
+		for _, item := range t {
+			values = descendants(values, item)
+		}
+	}
+
+	return values
+}
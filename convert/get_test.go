@@ -0,0 +1,80 @@
+package convert
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestGet(t *testing.T) {
+	const doc = `{"name": "app", "spec": {"replicas": 3, "ports": [{"port": 80}, {"port": 443, "name": "tls"}]},` +
+		` "a/b": {"~c": true}}`
+
+	tests := []struct {
+		name   string
+		input  string
+		format serdeval.Format
+		path   string
+		want   []string
+	}{
+		{"pointer", doc, serdeval.FormatJSON, "/spec/ports/1/port", []string{"443"}},
+		{"pointer escapes", doc, serdeval.FormatJSON, "/a~1b/~0c", []string{"true"}},
+		{"pointer to root", `{"b": 1, "a": [2]}`, serdeval.FormatJSON, "", []string{`{"b":1,"a":[2]}`}},
+		{"big integer", `{"id": 12345678901234567890}`, serdeval.FormatJSON, "/id", []string{"12345678901234567890"}},
+		{"member", doc, serdeval.FormatJSON, "$.spec.replicas", []string{"3"}},
+		{"without root", doc, serdeval.FormatJSON, ".name", []string{`"app"`}},
+		{"quoted member", doc, serdeval.FormatJSON, `$['a/b']["~c"]`, []string{"true"}},
+		{"negative index", doc, serdeval.FormatJSON, "$.spec.ports[-1].port", []string{"443"}},
+		{"wildcard", doc, serdeval.FormatJSON, "$.spec.ports[*].port", []string{"80", "443"}},
+		{"member wildcard", `{"a": 1, "b": [2]}`, serdeval.FormatJSON, "$.*", []string{"1", "[2]"}},
+		{"descendants", doc, serdeval.FormatJSON, "$..name", []string{`"app"`, `"tls"`}},
+		{"descendant index", `{"a": [1, [2, 3]]}`, serdeval.FormatJSON, "$..[0]", []string{"1", "2"}},
+		{"no match", doc, serdeval.FormatJSON, "$.missing", []string{}},
+		{"yaml", "spec:\n  image: nginx:1.25\n", serdeval.FormatYAML, "$.spec.image", []string{`"nginx:1.25"`}},
+		{"toml", "[server]\nport = 8080\n", serdeval.FormatTOML, "/server", []string{`{"port":8080}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Get([]byte(tt.input), tt.format, tt.path)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			got := make([]string, len(values))
+			for i, v := range values {
+				got[i] = string(v)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		path    string
+		wantErr string
+	}{
+		{"missing member", `{"a": {}}`, "/a/b", "no value at /a/b"},
+		{"index out of range", `{"a": [1]}`, "/a/1", "no value at /a/1"},
+		{"leading zero", `{"a": [1]}`, "/a/00", "no value at /a/00"},
+		{"not a path", `{}`, "a.b", "invalid path"},
+		{"bad selector", `{}`, "$[?(@.a)]", "unsupported selector"},
+		{"missing name", `{}`, "$.a.", "missing name"},
+		{"unterminated", `{}`, "$['a", "unterminated name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Get([]byte(tt.input), serdeval.FormatJSON, tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Get() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
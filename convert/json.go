@@ -0,0 +1,186 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// decodeJSON reads a JSON document, keeping the order of object keys. Integers stay
+// integers, as a *big.Int when they do not fit in an int64, so none is rounded.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return readJSON(dec)
+}
+
+// readJSON reads the next value from dec.
+func readJSON(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return readJSONObject(dec)
+		}
+		items := []any{}
+		for dec.More() {
+			item, itemErr := readJSON(dec)
+			if itemErr != nil {
+				return nil, itemErr
+			}
+			items = append(items, item)
+		}
+		_, err = dec.Token() // ']'
+
+		return items, err
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		if !strings.ContainsAny(string(t), ".eE") {
+			if i, ok := new(big.Int).SetString(string(t), 10); ok {
+				return i, nil
+			}
+		}
+
+		return t.Float64()
+	}
+
+	return tok, nil
+}
+
+// readJSONObject reads the members of an object whose '{' dec has just read.
+func readJSONObject(dec *json.Decoder) (any, error) {
+	o := newObject()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		value, err := readJSON(dec)
+		if err != nil {
+			return nil, err
+		}
+		o.set(key, value)
+	}
+	_, err := dec.Token() // '}'
+
+	return o, err
+}
+
+// encodeJSON writes v as JSON indented by two spaces, without escaping HTML characters.
+func encodeJSON(v any) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeJSON(&b, v, indent, "\n", ""); err != nil {
+		return nil, err
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+// writeJSON writes v, found at path, to b, starting each nested line with newline and
+// then indent once per level; an empty indent writes compact JSON.
+func writeJSON(b *bytes.Buffer, v any, indent, newline, path string) error {
+	switch t := v.(type) {
+	case *object:
+		return writeJSONObject(b, t, indent, newline, path)
+	case []any:
+		return writeJSONArray(b, t, indent, newline, path)
+	case time.Time:
+		return writeJSONScalar(b, formatTime(t), path)
+	case float64:
+		if math.IsInf(t, 0) || math.IsNaN(t) {
+			return fmt.Errorf("cannot convert %v at %s to JSON, which has no infinity or NaN", t, displayPath(path))
+		}
+	}
+
+	return writeJSONScalar(b, v, path)
+}
+
+// writeJSONObject writes o, found at path, to b, as writeJSON does.
+func writeJSONObject(b *bytes.Buffer, o *object, indent, newline, path string) error {
+	if len(o.keys) == 0 {
+		b.WriteString("{}")
+
+		return nil
+	}
+
+	inner := newline + indent
+	if indent == "" {
+		newline, inner = "", ""
+	}
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(inner)
+		if err := writeJSONScalar(b, key, path); err != nil {
+			return err
+		}
+		b.WriteByte(':')
+		if indent != "" {
+			b.WriteByte(' ')
+		}
+		if err := writeJSON(b, o.values[key], indent, inner, childPath(path, key)); err != nil {
+			return err
+		}
+	}
+	b.WriteString(newline)
+	b.WriteByte('}')
+
+	return nil
+}
+
+// writeJSONArray writes items, found at path, to b, as writeJSON does.
+func writeJSONArray(b *bytes.Buffer, items []any, indent, newline, path string) error {
+	if len(items) == 0 {
+		b.WriteString("[]")
+
+		return nil
+	}
+
+	inner := newline + indent
+	if indent == "" {
+		newline, inner = "", ""
+	}
+	b.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(inner)
+		if err := writeJSON(b, item, indent, inner, childPath(path, strconv.Itoa(i))); err != nil {
+			return err
+		}
+	}
+	b.WriteString(newline)
+	b.WriteByte(']')
+
+	return nil
+}
+
+// writeJSONScalar writes a string, number, bool, or null to b.
+func writeJSONScalar(b *bytes.Buffer, v any, path string) error {
+	var scalar bytes.Buffer
+	enc := json.NewEncoder(&scalar)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("cannot convert the value at %s to JSON: %w", displayPath(path), err)
+	}
+	b.Write(bytes.TrimSuffix(scalar.Bytes(), []byte("\n")))
+
+	return nil
+}
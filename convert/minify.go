@@ -0,0 +1,37 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// minifyFormats are the formats Minify compacts.
+var minifyFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatXML}
+
+// Minify validates data as format, JSON or XML, and returns it without the whitespace
+// between tokens or elements, for shipping compact payloads. An empty format or
+// serdeval.FormatAuto is detected from the content. Everything else is kept as written:
+// JSON numbers and escapes, and XML text, comments, and processing instructions.
+//
+// Example:
+//
+//	out, err := convert.Minify([]byte("{\n  \"a\": [1, 2]\n}\n"), serdeval.FormatJSON)
+//	// out is {"a":[1,2]}
+func Minify(data []byte, format serdeval.Format) ([]byte, error) {
+	text, format, err := load(data, format, "minify", minifyFormats)
+	if err != nil {
+		return nil, err
+	}
+	if format == serdeval.FormatXML {
+		return minifyXML(text)
+	}
+
+	var b bytes.Buffer
+	if err := json.Compact(&b, text); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestMinify(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format serdeval.Format
+		want   string
+	}{
+		{
+			name:   "json keeps numbers and escapes",
+			input:  "{\n  \"a\": [1.50, 1e3],\n  \"s\": \"a b\\u00e9\"\n}\n",
+			format: serdeval.FormatJSON,
+			want:   `{"a":[1.50,1e3],"s":"a b\u00e9"}`,
+		},
+		{
+			name: "xml keeps text, comments, and mixed content",
+			input: "<?xml version=\"1.0\"?>\n<!-- c -->\n<a x=\"1\">\n  <b> keep  this </b>\n  <c></c>\n" +
+				"  <p>Some <i>it</i> text</p>\n</a>\n",
+			format: serdeval.FormatXML,
+			want:   `<?xml version="1.0"?><!-- c --><a x="1"><b> keep  this </b><c/><p>Some <i>it</i> text</p></a>`,
+		},
+		{"auto", "[ 1,\n 2 ]", serdeval.FormatAuto, `[1,2]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Minify([]byte(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("Minify() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Minify() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinifyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  serdeval.Format
+		wantErr string
+	}{
+		{"invalid", `{"a": [1,}`, serdeval.FormatJSON, "invalid character"},
+		{"unsupported", "a: 1\n", serdeval.FormatYAML, "cannot minify yaml: supported formats are json and xml"},
+		{"undetected", "a: 1\n", serdeval.FormatAuto, "not detected as json or xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Minify([]byte(tt.input), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Minify() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// patchFormats are the formats of the documents Apply patches.
+var patchFormats = []serdeval.Format{serdeval.FormatJSON, serdeval.FormatYAML, serdeval.FormatTOML}
+
+// Apply patches doc, a JSON, YAML, or TOML document whose format is detected, with patch
+// and returns the result. See ApplyAs.
+//
+// Example:
+//
+//	out, err := convert.Apply([]byte("replicas: 3\n"), []byte(`[{"op": "replace", "path": "/replicas", "value": 5}]`))
+//	// out is "replicas: 5\n"
+func Apply(doc, patch []byte) ([]byte, error) {
+	return ApplyAs(doc, serdeval.FormatAuto, patch)
+}
+
+// ApplyAs validates doc as format and patch as a JSON Patch (RFC 6902) when it is an
+// array or a JSON Merge Patch (RFC 7386) otherwise, and returns doc with the patch
+// applied, written in format as Convert writes it. A patch that does not validate, or
+// whose operations cannot be applied, such as a remove of a missing member or a failed
+// test, is an error and nothing is returned. A document or patch that fails validation
+// is returned as a *DocumentError with Index 0 or 1.
+//
+// Patches are JSON; a patch kept as YAML can be converted to JSON with Convert first.
+func ApplyAs(doc []byte, format serdeval.Format, patch []byte) ([]byte, error) {
+	text, format, err := load(doc, format, "patch", patchFormats)
+	if err != nil {
+		return nil, &DocumentError{Index: 0, Err: err}
+	}
+	v, err := codecs[format].decode(text)
+	if err != nil {
+		return nil, &DocumentError{Index: 0, Err: err}
+	}
+
+	patchFormat := serdeval.FormatMergePatch
+	if bytes.HasPrefix(bytes.TrimSpace(patch), []byte("[")) {
+		patchFormat = serdeval.FormatJSONPatch
+	}
+	patchText, _, err := load(patch, patchFormat, "apply", []serdeval.Format{patchFormat})
+	if err != nil {
+		return nil, &DocumentError{Index: 1, Err: err}
+	}
+	p, err := decodeJSON(patchText)
+	if err != nil {
+		return nil, &DocumentError{Index: 1, Err: err}
+	}
+
+	if patchFormat == serdeval.FormatMergePatch {
+		v = mergePatch(v, p)
+	} else if v, err = applyJSONPatch(v, p.([]any)); err != nil {
+		return nil, err
+	}
+
+	return codecs[format].encode(v)
+}
+
+// mergePatch returns target with the JSON Merge Patch patch applied: members of patch
+// that are null are removed, objects are merged recursively, and other values replace
+// what was there.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(*object)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(*object)
+	if !ok {
+		t = newObject()
+	}
+	for _, key := range p.keys {
+		if p.values[key] == nil {
+			t.remove(key)
+
+			continue
+		}
+		t.set(key, mergePatch(t.values[key], p.values[key]))
+	}
+
+	return t
+}
+
+// applyJSONPatch returns doc with the operations of a valid JSON Patch applied in order.
+func applyJSONPatch(doc any, ops []any) (any, error) {
+	for i, item := range ops {
+		op := item.(*object)
+		name, _ := op.values["op"].(string)
+		path, _ := op.values["path"].(string)
+		from, _ := op.values["from"].(string)
+
+		var err error
+		switch name {
+		case "add":
+			doc, err = editPointer(doc, path, addMember(op.values["value"]))
+		case "remove":
+			doc, err = editPointer(doc, path, removeMember)
+		case "replace":
+			doc, err = editPointer(doc, path, replaceMember(op.values["value"]))
+		case "move", "copy":
+			doc, err = copyValue(doc, from, path, name == "move")
+		case "test":
+			err = testValue(doc, path, op.values["value"])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, name, displayPath(path), err)
+		}
+	}
+
+	return doc, nil
+}
+
+// copyValue adds the value at from in doc to path, removing it from from when move is
+// set.
+func copyValue(doc any, from, path string, move bool) (any, error) {
+	v, err := resolvePointer(doc, from)
+	if err != nil {
+		return nil, err
+	}
+	if move {
+		if from == path {
+			return doc, nil
+		}
+		if doc, err = editPointer(doc, from, removeMember); err != nil {
+			return nil, err
+		}
+	} else {
+		v = cloneValue(v)
+	}
+
+	return editPointer(doc, path, addMember(v))
+}
+
+// testValue checks that the value at path in doc equals want.
+func testValue(doc any, path string, want any) error {
+	v, err := resolvePointer(doc, path)
+	if err != nil {
+		return err
+	}
+	d := differ{}
+	d.compare(v, want, path)
+	if len(d.changes) > 0 {
+		var got bytes.Buffer
+		_ = writeJSON(&got, v, "", "", path)
+
+		return fmt.Errorf("test failed: the value is %s", got.String())
+	}
+
+	return nil
+}
+
+// memberEdit changes the member key of container, an object or array found at path,
+// and returns the container.
+type memberEdit func(container any, key, path string) (any, error)
+
+// editPointer applies edit to the container of the value the JSON Pointer pointer refers
+// to in doc and returns doc. The empty pointer edits doc itself, as the only member of an
+// object holding it.
+func editPointer(doc any, pointer string, edit memberEdit) (any, error) {
+	if pointer == "" {
+		holder := newObject()
+		holder.set("", doc)
+		if _, err := edit(holder, "", ""); err != nil {
+			return nil, err
+		}
+		v, ok := holder.values[""]
+		if !ok {
+			return nil, errors.New("cannot remove the whole document")
+		}
+
+		return v, nil
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return editTokens(doc, tokens, "", edit)
+}
+
+// editTokens applies edit below v, found at path, following the unescaped pointer tokens.
+func editTokens(v any, tokens []string, path string, edit memberEdit) (any, error) {
+	if len(tokens) == 1 {
+		return edit(v, tokens[0], childPath(path, tokens[0]))
+	}
+
+	key, at := tokens[0], childPath(path, tokens[0])
+	switch t := v.(type) {
+	case *object:
+		child, ok := t.values[key]
+		if !ok {
+			return nil, fmt.Errorf("no value at %s", at)
+		}
+		edited, err := editTokens(child, tokens[1:], at, edit)
+		if err != nil {
+			return nil, err
+		}
+		t.values[key] = edited
+
+		return t, nil
+	case []any:
+		i, ok := pointerIndex(key, len(t))
+		if !ok {
+			return nil, fmt.Errorf("no value at %s", at)
+		}
+		edited, err := editTokens(t[i], tokens[1:], at, edit)
+		if err != nil {
+			return nil, err
+		}
+		t[i] = edited
+
+		return t, nil
+	}
+
+	return nil, fmt.Errorf("no value at %s", at)
+}
+
+// addMember returns an edit that sets a member to value, inserting into arrays before the
+// item at the index or, for "-", after the last item.
+func addMember(value any) memberEdit {
+	return func(container any, key, path string) (any, error) {
+		switch t := container.(type) {
+		case *object:
+			t.set(key, value)
+
+			return t, nil
+		case []any:
+			i, ok := len(t), key == "-"
+			if !ok {
+				i, ok = pointerIndex(key, len(t)+1)
+			}
+			if !ok {
+				return nil, fmt.Errorf("index %s is out of range at %s", key, path)
+			}
+
+			return append(t[:i], append([]any{value}, t[i:]...)...), nil
+		}
+
+		return nil, fmt.Errorf("no object or array to add %s to", path)
+	}
+}
+
+// removeMember removes a member, which must exist.
+func removeMember(container any, key, path string) (any, error) {
+	switch t := container.(type) {
+	case *object:
+		if _, ok := t.values[key]; ok {
+			t.remove(key)
+
+			return t, nil
+		}
+	case []any:
+		if i, ok := pointerIndex(key, len(t)); ok {
+			return append(t[:i], t[i+1:]...), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no value at %s", path)
+}
+
+// replaceMember returns an edit that sets a member, which must exist, to value.
+func replaceMember(value any) memberEdit {
+	return func(container any, key, path string) (any, error) {
+		switch t := container.(type) {
+		case *object:
+			if _, ok := t.values[key]; ok {
+				t.values[key] = value
+
+				return t, nil
+			}
+		case []any:
+			if i, ok := pointerIndex(key, len(t)); ok {
+				t[i] = value
+
+				return t, nil
+			}
+		}
+
+		return nil, fmt.Errorf("no value at %s", path)
+	}
+}
+
+// cloneValue returns a deep copy of v.
+func cloneValue(v any) any {
+	switch t := v.(type) {
+	case *object:
+		o := newObject()
+		for _, key := range t.keys {
+			o.set(key, cloneValue(t.values[key]))
+		}
+
+		return o
+	case []any:
+		items := make([]any, len(t))
+		for i, item := range t {
+			items[i] = cloneValue(item)
+		}
+
+		return items
+	}
+
+	return v
+}
@@ -0,0 +1,121 @@
+package convert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "add member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  "{\n  \"foo\": \"bar\",\n  \"baz\": \"qux\"\n}\n",
+		},
+		{
+			name: "add, remove, and replace array items",
+			doc:  "foo: [bar, baz, qux]\n",
+			patch: `[{"op": "add", "path": "/foo/1", "value": "x"}, {"op": "remove", "path": "/foo/3"},
+				{"op": "replace", "path": "/foo/0", "value": 1}, {"op": "add", "path": "/foo/-", "value": true}]`,
+			want: "foo:\n  - 1\n  - x\n  - baz\n  - true\n",
+		},
+		{
+			name: "move and copy",
+			doc:  "a: {b: 1, c: [1]}\nd: {}\n",
+			patch: `[{"op": "move", "from": "/a/b", "path": "/d/b"}, {"op": "copy", "from": "/a/c", "path": "/d/c"},
+				{"op": "add", "path": "/d/c/-", "value": 2}]`,
+			want: "a:\n  c:\n    - 1\nd:\n  b: 1\n  c:\n    - 1\n    - 2\n",
+		},
+		{
+			name:  "test passes by value",
+			doc:   `{"a": {"x": 1.0, "y": [2]}}`,
+			patch: `[{"op": "test", "path": "/a", "value": {"y": [2], "x": 1}}, {"op": "remove", "path": "/a/y"}]`,
+			want:  "{\n  \"a\": {\n    \"x\": 1\n  }\n}\n",
+		},
+		{
+			name:  "replace the whole document",
+			doc:   "a: 1\n",
+			patch: `[{"op": "replace", "path": "", "value": {"b": 2}}]`,
+			want:  "b: 2\n",
+		},
+		{
+			name:  "escaped pointer",
+			doc:   `{"a/b": {"m~n": 1}}`,
+			patch: `[{"op": "replace", "path": "/a~1b/m~0n", "value": 2}]`,
+			want:  "{\n  \"a/b\": {\n    \"m~n\": 2\n  }\n}\n",
+		},
+		{
+			name:  "merge patch",
+			doc:   "title: Goodbye!\nauthor: {givenName: John, familyName: Doe}\ntags: [example, sample]\ncontent: text\n",
+			patch: `{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`,
+			want: "title: Hello!\nauthor:\n  givenName: John\ntags:\n  - example\ncontent: text\n" +
+				"phoneNumber: +01-123-456-7890\n",
+		},
+		{
+			name:  "toml",
+			doc:   "[server]\nport = 80\n",
+			patch: `{"server": {"port": 8080}}`,
+			want:  "[server]\n  port = 8080\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Apply() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		wantErr string
+	}{
+		{"remove missing", `{"a": 1}`, `[{"op": "remove", "path": "/b"}]`, "operation 0 (remove /b): no value at /b"},
+		{"missing parent", `{"a": 1}`, `[{"op": "add", "path": "/b/c", "value": 1}]`, "no value at /b"},
+		{
+			"index out of range", `{"a": [1]}`, `[{"op": "add", "path": "/a/3", "value": 1}]`,
+			"index 3 is out of range at /a/3",
+		},
+		{"leading zero index", `{"a": [1, 2]}`, `[{"op": "replace", "path": "/a/01", "value": 1}]`, "no value at /a/01"},
+		{"failed test", `{"a": "x"}`, `[{"op": "test", "path": "/a", "value": "y"}]`, `test failed: the value is "x"`},
+		{"remove root", `{"a": 1}`, `[{"op": "remove", "path": ""}]`, "cannot remove the whole document"},
+		{"into a scalar", `{"a": 1}`, `[{"op": "add", "path": "/a/b", "value": 1}]`, "no object or array to add /a/b to"},
+		{"null in toml", "a = 1\n", `[{"op": "add", "path": "/b", "value": null}]`, "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Apply() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyInvalidPatch(t *testing.T) {
+	_, err := ApplyAs([]byte(`{}`), serdeval.FormatJSON, []byte("[\n  {\"op\": \"ad\", \"path\": \"/a\"}\n]"))
+	var docErr *DocumentError
+	var verr *serdeval.ValidationError
+	if !errors.As(err, &docErr) || docErr.Index != 1 || !errors.As(err, &verr) || verr.Line != 2 {
+		t.Errorf("ApplyAs() error = %v, want a validation error on line 2 of the patch", err)
+	}
+}
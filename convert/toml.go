@@ -0,0 +1,271 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlLocalDate is the location of the times the TOML decoder returns for local dates,
+// which its encoder writes back as dates.
+var tomlLocalDate = func() *time.Location {
+	var v map[string]any
+	if _, err := toml.Decode("d = 1970-01-01", &v); err != nil {
+		return time.UTC
+	}
+	d, _ := v["d"].(time.Time)
+
+	return d.Location()
+}()
+
+// decodeTOML reads a TOML document, keeping keys in the order they are first defined.
+func decodeTOML(data []byte) (any, error) {
+	var m map[string]any
+	md, err := toml.Decode(string(data), &m)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys of the tables of an array table are ordered together, as they share a path
+	order := map[string][]string{}
+	seen := map[string]bool{}
+	for _, key := range md.Keys() {
+		parent, child := strings.Join(key[:len(key)-1], "\x00"), key[len(key)-1]
+		if !seen[parent+"\x00\x00"+child] {
+			seen[parent+"\x00\x00"+child] = true
+			order[parent] = append(order[parent], child)
+		}
+	}
+
+	return tomlValue(m, nil, order), nil
+}
+
+// tomlValue returns the value of v, found at the key path in the document, with the keys
+// of its tables in the given order.
+func tomlValue(v any, path []string, order map[string][]string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		o := newObject()
+		for _, key := range order[strings.Join(path, "\x00")] {
+			if value, ok := t[key]; ok {
+				o.set(key, tomlValue(value, append(path[:len(path):len(path)], key), order))
+			}
+		}
+		// Keys missing from the order, if any, follow in sorted order
+		rest := make([]string, 0, len(t)-len(o.keys))
+		for key := range t {
+			if _, ok := o.values[key]; !ok {
+				rest = append(rest, key)
+			}
+		}
+		sort.Strings(rest)
+		for _, key := range rest {
+			o.set(key, tomlValue(t[key], append(path[:len(path):len(path)], key), order))
+		}
+
+		return o
+	case []map[string]any:
+		items := make([]any, 0, len(t))
+		for _, item := range t {
+			items = append(items, tomlValue(item, path, order))
+		}
+
+		return items
+	case []any:
+		items := make([]any, 0, len(t))
+		for _, item := range t {
+			items = append(items, tomlValue(item, path, order))
+		}
+
+		return items
+	}
+
+	return v
+}
+
+// encodeTOML writes v, which must be an object, as a TOML document.
+func encodeTOML(v any) ([]byte, error) {
+	o, ok := v.(*object)
+	if !ok {
+		return nil, errors.New("cannot convert to TOML: the document must be an object")
+	}
+	m, err := tomlPlain(o, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	enc := toml.NewEncoder(&b)
+	enc.Indent = indent
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// tomlPlain returns v, found at path, as the maps and slices the TOML encoder writes.
+// An array of objects becomes an array of tables.
+func tomlPlain(v any, path string) (any, error) {
+	switch t := v.(type) {
+	case *object:
+		m := make(map[string]any, len(t.keys))
+		for _, key := range t.keys {
+			value, err := tomlPlain(t.values[key], childPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+
+		return m, nil
+	case []any:
+		items := make([]any, 0, len(t))
+		tables := make([]map[string]any, 0, len(t))
+		for i, item := range t {
+			value, err := tomlPlain(item, childPath(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+			if table, ok := value.(map[string]any); ok {
+				tables = append(tables, table)
+			}
+		}
+		if len(t) > 0 && len(tables) == len(t) {
+			return tables, nil
+		}
+
+		return items, nil
+	case nil:
+		return nil, fmt.Errorf("cannot convert null at %s to TOML, which has no null value", displayPath(path))
+	case *big.Int:
+		return nil, fmt.Errorf("cannot convert %s at %s to TOML, whose integers are 64-bit", t, displayPath(path))
+	}
+
+	return v, nil
+}
+
+// formatTOML tidies the layout of a valid TOML document line by line, leaving the lines
+// of multi-line strings and arrays as written.
+func formatTOML(text []byte) []byte {
+	var out []string
+	delim, depth, blank := "", 0, false
+	for _, raw := range strings.Split(string(text), "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		if delim != "" || depth > 0 {
+			line := raw
+			if delim == "" {
+				// Trailing whitespace is only part of the value inside a string
+				line = strings.TrimRight(raw, " \t")
+			}
+			out = append(out, line)
+			var d int
+			delim, d, _ = scanTOMLLine(raw, delim)
+			depth += d
+
+			continue
+		}
+
+		line := strings.TrimRight(raw, " \t")
+		content := strings.TrimLeft(line, " \t")
+		if content == "" {
+			blank = true
+
+			continue
+		}
+		var eq int
+		delim, depth, eq = scanTOMLLine(content, "")
+		if eq > 0 && content[0] != '[' {
+			content = strings.TrimRight(content[:eq], " \t") + " = " + strings.TrimLeft(content[eq+1:], " \t")
+		}
+		if blank && len(out) > 0 {
+			out = append(out, "")
+		}
+		blank = false
+		if content[0] == '[' {
+			out = separateTOMLHeader(out)
+		}
+		out = append(out, line[:len(line)-len(strings.TrimLeft(line, " \t"))]+content)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// separateTOMLHeader adds a blank line to the end of out, above any comments there, which
+// belong to the table header that follows.
+func separateTOMLHeader(out []string) []string {
+	k := len(out)
+	for k > 0 && strings.HasPrefix(strings.TrimSpace(out[k-1]), "#") {
+		k--
+	}
+	if k == 0 || out[k-1] == "" {
+		return out
+	}
+
+	return append(out[:k], append([]string{""}, out[k:]...)...)
+}
+
+// scanTOMLLine scans a line of TOML that starts inside the multi-line string closed by
+// delim, if delim is not empty. It returns the delimiter of a multi-line string still
+// open at the end of the line, the change in the depth of brackets and braces, and the
+// index of the first "=" outside strings and brackets, or -1.
+func scanTOMLLine(line, delim string) (open string, depth, eq int) {
+	eq = -1
+	i := 0
+	if delim != "" {
+		end := strings.Index(line, delim)
+		if end < 0 {
+			return delim, 0, eq
+		}
+		i = end + len(delim)
+	}
+	for ; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '#':
+			return "", depth, eq
+		case strings.HasPrefix(line[i:], `"""`), strings.HasPrefix(line[i:], "'''"):
+			end := strings.Index(line[i+3:], line[i:i+3])
+			if end < 0 {
+				return line[i : i+3], depth, eq
+			}
+			i += end + 5
+		case strings.IndexByte(`"'`, c) >= 0:
+			i = tomlStringEnd(line, i)
+		case strings.IndexByte("[{", c) >= 0:
+			depth++
+		case strings.IndexByte("]}", c) >= 0:
+			depth--
+		case c == '=' && depth == 0 && eq < 0:
+			eq = i
+		}
+	}
+
+	return "", depth, eq
+}
+
+// tomlStringEnd returns the index of the quote that closes the single-line string opened
+// at start, or len(line) if it is not closed.
+func tomlStringEnd(line string, start int) int {
+	quote := line[start]
+	for i := start + 1; i < len(line); i++ {
+		switch {
+		case line[i] == quote:
+			return i
+		case quote == '"' && line[i] == '\\':
+			i++
+		}
+	}
+
+	return len(line)
+}
@@ -0,0 +1,370 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// xmlTextKey holds the text of an element that also has attributes or child elements.
+const xmlTextKey = "#text"
+
+// xmlAttrPrefix starts the keys that hold attributes.
+const xmlAttrPrefix = "@"
+
+// decodeXML reads an XML document as an object holding its root element. Names keep
+// their namespace prefixes, such as "soap:Envelope".
+func decodeXML(data []byte) (any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.RawToken()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			value, err := xmlElement(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			root := newObject()
+			root.set(xmlName(start.Name), value)
+
+			return root, nil
+		}
+	}
+}
+
+// xmlElement reads the content of the element that start opened, up to its end tag.
+func xmlElement(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	o := newObject()
+	for _, attr := range start.Attr {
+		o.set(xmlAttrPrefix+xmlName(attr.Name), attr.Value)
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.RawToken()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			value, err := xmlElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			addXMLChild(o, xmlName(t.Name), value)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(o.keys) == 0 {
+				if s == "" {
+					return nil, nil
+				}
+
+				return s, nil
+			}
+			if s != "" {
+				o.set(xmlTextKey, s)
+			}
+
+			return o, nil
+		}
+	}
+}
+
+// addXMLChild sets name to value in o, collecting repeated elements into an array.
+func addXMLChild(o *object, name string, value any) {
+	existing, ok := o.values[name]
+	if !ok {
+		o.set(name, value)
+
+		return
+	}
+	if items, ok := existing.([]any); ok {
+		o.set(name, append(items, value))
+
+		return
+	}
+	o.set(name, []any{existing, value})
+}
+
+// xmlName returns name as written, with its namespace prefix.
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+// encodeXML writes v, which must be an object with a single key naming the root
+// element, as an XML document indented by two spaces.
+func encodeXML(v any) ([]byte, error) {
+	root, ok := v.(*object)
+	if !ok || len(root.keys) != 1 {
+		return nil, errors.New("cannot convert to XML: the document must be an object with one key, the root element")
+	}
+	name := root.keys[0]
+	if _, ok := root.values[name].([]any); ok {
+		return nil, errors.New("cannot convert to XML: the root element cannot be an array")
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(&b)
+	enc.Indent("", indent)
+	if err := writeXMLElement(enc, name, root.values[name], childPath("", name)); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+// writeXMLElement writes the element name, found at path, with v as its content.
+func writeXMLElement(enc *xml.Encoder, name string, v any, path string) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("cannot convert key %q at %s to XML: not a valid element name", name, displayPath(path))
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	switch t := v.(type) {
+	case *object:
+		return writeXMLObject(enc, start, t, path)
+	case []any:
+		return fmt.Errorf("cannot convert the array nested in an array at %s to XML", displayPath(path))
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if text := scalarText(v); text != "" {
+		if err := enc.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// writeXMLObject writes the element start with o's "@" keys as its attributes and its
+// other keys as text and child elements, each item of an array as its own element.
+func writeXMLObject(enc *xml.Encoder, start xml.StartElement, o *object, path string) error {
+	for _, key := range o.keys {
+		name, ok := strings.CutPrefix(key, xmlAttrPrefix)
+		if !ok {
+			continue
+		}
+		value := o.values[key]
+		switch value.(type) {
+		case *object, []any:
+			return fmt.Errorf("cannot convert attribute %q at %s to XML: its value is not a scalar",
+				name, displayPath(path))
+		}
+		if !isXMLName(name) {
+			return fmt.Errorf("cannot convert key %q at %s to XML: not a valid attribute name", key, displayPath(path))
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: scalarText(value)})
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, key := range o.keys {
+		value := o.values[key]
+		var err error
+		switch items, isArray := value.([]any); {
+		case strings.HasPrefix(key, xmlAttrPrefix):
+			// Written as attributes above
+		case key == xmlTextKey:
+			err = enc.EncodeToken(xml.CharData(scalarText(value)))
+		case isArray:
+			for i, item := range items {
+				if err = writeXMLElement(enc, key, item, childPath(childPath(path, key), strconv.Itoa(i))); err != nil {
+					break
+				}
+			}
+		default:
+			err = writeXMLElement(enc, key, value, childPath(path, key))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// isXMLName reports whether name can name an element or attribute.
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || r == ':':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// xmlPrinter writes the tokens of an XML document one element per line.
+type xmlPrinter struct {
+	b      bytes.Buffer
+	indent string
+	depth  int
+	// compact writes every token on one line, without indentation
+	compact bool
+	// open is set while the last start tag lacks its closing '>', so an end tag right
+	// after it can close the element as <name/> instead
+	open bool
+	// inline is the depth of the element whose text is being written, or 0. Its content,
+	// such as <b> in <p>Some <b>bold</b> text</p>, is written as it was, without line breaks.
+	inline int
+}
+
+// formatXML indents a valid XML document.
+func formatXML(text []byte, indent string) ([]byte, error) {
+	p := &xmlPrinter{indent: indent}
+	if err := p.print(text); err != nil {
+		return nil, err
+	}
+	p.b.WriteByte('\n')
+
+	return p.b.Bytes(), nil
+}
+
+// minifyXML removes the whitespace between the elements of a valid XML document.
+func minifyXML(text []byte) ([]byte, error) {
+	p := &xmlPrinter{compact: true}
+	if err := p.print(text); err != nil {
+		return nil, err
+	}
+
+	return p.b.Bytes(), nil
+}
+
+// print writes the tokens of text.
+func (p *xmlPrinter) print(text []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(text))
+	for {
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		p.token(tok)
+	}
+}
+
+// token writes tok.
+func (p *xmlPrinter) token(tok xml.Token) {
+	switch t := tok.(type) {
+	case xml.StartElement:
+		p.startLine()
+		p.b.WriteString("<" + xmlName(t.Name))
+		for _, attr := range t.Attr {
+			p.b.WriteString(" " + xmlName(attr.Name) + `="`)
+			writeXMLEscaped(&p.b, attr.Value, true)
+			p.b.WriteByte('"')
+		}
+		p.open = true
+		p.depth++
+	case xml.EndElement:
+		p.depth--
+		if p.open {
+			p.b.WriteString("/>")
+			p.open = false
+		} else {
+			p.startLine()
+			p.b.WriteString("</" + xmlName(t.Name) + ">")
+		}
+		if p.inline > p.depth {
+			p.inline = 0
+		}
+	case xml.CharData:
+		if p.inline == 0 && len(bytes.TrimSpace(t)) == 0 {
+			return
+		}
+		p.closeStart()
+		if p.inline == 0 {
+			p.inline = p.depth
+		}
+		writeXMLEscaped(&p.b, string(t), false)
+	case xml.Comment:
+		p.startLine()
+		p.b.WriteString("<!--" + string(t) + "-->")
+	case xml.ProcInst:
+		p.startLine()
+		p.b.WriteString("<?" + t.Target)
+		if len(t.Inst) > 0 {
+			p.b.WriteString(" " + string(t.Inst))
+		}
+		p.b.WriteString("?>")
+	case xml.Directive:
+		p.startLine()
+		p.b.WriteString("<!" + string(t) + ">")
+	}
+}
+
+// closeStart ends a start tag still waiting for its '>'.
+func (p *xmlPrinter) closeStart() {
+	if p.open {
+		p.b.WriteByte('>')
+		p.open = false
+	}
+}
+
+// startLine starts a new line indented to the current depth, unless inside text or
+// compact.
+func (p *xmlPrinter) startLine() {
+	p.closeStart()
+	if p.inline > 0 || p.compact {
+		return
+	}
+	if p.b.Len() > 0 {
+		p.b.WriteByte('\n')
+	}
+	p.b.WriteString(strings.Repeat(p.indent, p.depth))
+}
+
+// writeXMLEscaped writes s to b with the characters markup gives meaning to escaped, and
+// in attribute values also quotes and the whitespace that parsers would normalize.
+func writeXMLEscaped(b *bytes.Buffer, s string, attr bool) {
+	for _, r := range s {
+		switch {
+		case r == '&':
+			b.WriteString("&amp;")
+		case r == '<':
+			b.WriteString("&lt;")
+		case r == '>':
+			b.WriteString("&gt;")
+		case attr && r == '"':
+			b.WriteString("&quot;")
+		case attr && r == '\n':
+			b.WriteString("&#xA;")
+		case attr && r == '\t':
+			b.WriteString("&#x9;")
+		case r == '\r':
+			b.WriteString("&#xD;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
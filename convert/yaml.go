@@ -0,0 +1,185 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlMergeTag is the tag of a `<<` key that merges other mappings into its own.
+const yamlMergeTag = "!!merge"
+
+// decodeYAML reads a single YAML document, keeping the order of mapping keys and
+// resolving aliases and merge keys.
+func decodeYAML(data []byte) (any, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	var next yaml.Node
+	if err := dec.Decode(&next); !errors.Is(err, io.EOF) {
+		return nil, errors.New("cannot convert a YAML stream of several documents")
+	}
+
+	return yamlValue(&doc)
+}
+
+// yamlValue returns the value of n.
+func yamlValue(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+
+		return yamlValue(n.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.MappingNode:
+		return yamlMapping(n)
+	case yaml.SequenceNode:
+		items := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			item, err := yamlValue(c)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		return items, nil
+	}
+
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch t := v.(type) {
+	case int:
+		return int64(t), nil
+	case time.Time:
+		if len(n.Value) == len(time.DateOnly) {
+			// A date alone, which TOML can hold as a local date
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tomlLocalDate), nil
+		}
+	}
+
+	return v, nil
+}
+
+// yamlMapping returns the object for a mapping node. Keys set in the mapping itself
+// override those merged in with `<<`, wherever the merge key appears.
+func yamlMapping(n *yaml.Node) (any, error) {
+	own := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Tag != yamlMergeTag {
+			own[n.Content[i].Value] = true
+		}
+	}
+
+	o := newObject()
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, node := n.Content[i], n.Content[i+1]
+		if key.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("cannot convert the mapping key at line %d, which is not a scalar", key.Line)
+		}
+		value, err := yamlValue(node)
+		if err != nil {
+			return nil, err
+		}
+		if key.Tag != yamlMergeTag {
+			o.set(key.Value, value)
+
+			continue
+		}
+		merged, ok := value.([]any)
+		if !ok {
+			merged = []any{value}
+		}
+		for _, m := range merged {
+			if mo, ok := m.(*object); ok {
+				for _, k := range mo.keys {
+					if _, done := o.values[k]; !done && !own[k] {
+						o.set(k, mo.values[k])
+					}
+				}
+			}
+		}
+	}
+
+	return o, nil
+}
+
+// encodeYAML writes v as a YAML document indented by two spaces.
+func encodeYAML(v any) ([]byte, error) {
+	node, err := yamlNode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(len(indent))
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// yamlNode returns the node for v.
+func yamlNode(v any) (*yaml.Node, error) {
+	switch t := v.(type) {
+	case *object:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range t.keys {
+			value, err := yamlNode(t.values[key])
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+		}
+
+		return n, nil
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range t {
+			value, err := yamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, value)
+		}
+
+		return n, nil
+	case time.Time:
+		if t.Location().String() == "time-local" {
+			// YAML has no time of day without a date
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: formatTime(t)}, nil
+		}
+
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: formatTime(t)}, nil
+	case *big.Int:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: t.String()}, nil
+	}
+
+	n := &yaml.Node{}
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,179 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSVDialect describes how a CSV file is written. The zero value sniffs the delimiter from
+// the first records and expects RFC 4180 double quotes and no comment lines.
+type CSVDialect struct {
+	// Delimiter separates fields, such as ',', ';', '\t', or '|'. Zero sniffs it from the
+	// first records, trying those four and preferring a comma when several fit equally well.
+	Delimiter rune
+	// Quote encloses fields that contain delimiters or line breaks, and is doubled to escape
+	// itself inside them. Zero means '"'. It must be an ASCII character.
+	Quote rune
+	// Comment starts a line that is skipped, such as '#'. Zero disables comment lines.
+	Comment rune
+	// LazyQuotes accepts a quote inside an unquoted field and a lone quote inside a quoted field.
+	LazyQuotes bool
+}
+
+// csvSniffDelimiters are the delimiters tried, in order of preference, when none is set.
+var csvSniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// csvSniffRecords is the number of records read to choose a delimiter.
+const csvSniffRecords = 10
+
+// check reports a dialect that encoding/csv cannot read.
+func (d CSVDialect) check() error {
+	quote := d.quote()
+	switch {
+	case quote >= utf8.RuneSelf || quote == '\r' || quote == '\n':
+		return fmt.Errorf("invalid CSV quote character %q: must be ASCII and not a line break", quote)
+	case d.Delimiter != 0 && !validCSVRune(d.Delimiter):
+		return fmt.Errorf("invalid CSV delimiter %q", d.Delimiter)
+	case d.Comment != 0 && !validCSVRune(d.Comment):
+		return fmt.Errorf("invalid CSV comment character %q", d.Comment)
+	case d.Delimiter != 0 && d.Delimiter == d.Comment:
+		return fmt.Errorf("CSV delimiter and comment character are both %q", d.Delimiter)
+	case d.Delimiter == quote || d.Comment == quote:
+		return fmt.Errorf("CSV quote character %q cannot also be the delimiter or comment character", quote)
+	}
+
+	return nil
+}
+
+// validCSVRune reports whether r can be a delimiter or comment character in encoding/csv.
+// '"' is rejected too, because Quote takes its place when another quote character is set.
+func validCSVRune(r rune) bool {
+	return r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
+
+// quote returns the quote character, defaulting to '"'.
+func (d CSVDialect) quote() rune {
+	if d.Quote == 0 {
+		return '"'
+	}
+
+	return d.Quote
+}
+
+// reader returns a csv.Reader for data in this dialect. encoding/csv only understands
+// double quotes, so another quote character is swapped with '"' byte for byte, which keeps
+// every offset, line, and column in the reader's errors pointing at the original input.
+func (d CSVDialect) reader(data []byte) *pooledCSVReader {
+	var swapped *[]byte
+	if quote := byte(d.quote()); quote != '"' {
+		swapped = getBytes(len(data))
+		for i, b := range data {
+			switch b {
+			case quote:
+				b = '"'
+			case '"':
+				b = quote
+			}
+			(*swapped)[i] = b
+		}
+		data = *swapped
+	}
+
+	delimiter := d.Delimiter
+	if delimiter == 0 {
+		delimiter = d.sniffDelimiter(data)
+	}
+
+	return d.newReader(data, swapped, delimiter)
+}
+
+// newReader returns a csv.Reader over data, which must already use '"' for quotes and is
+// returned to bytesPool on release when it is the copy held by swapped.
+func (d CSVDialect) newReader(data []byte, swapped *[]byte, delimiter rune) *pooledCSVReader {
+	r := newPooledCSVReader(data, swapped)
+	r.Comma = delimiter
+	r.Comment = d.Comment
+	r.LazyQuotes = d.LazyQuotes
+
+	return r
+}
+
+// DelimiterFor returns the delimiter that validation uses for data: Delimiter, or the one
+// sniffed from data when it is zero.
+//
+// Example:
+//
+//	delimiter := CSVDialect{}.DelimiterFor([]byte("a;b\n1;2\n")) // ';'
+func (d CSVDialect) DelimiterFor(data []byte) rune {
+	if d.Delimiter != 0 {
+		return d.Delimiter
+	}
+	r := d.reader(data)
+	defer r.release()
+
+	return r.Comma
+}
+
+// sniffDelimiter picks the candidate delimiter that splits the first records into the most
+// fields, the same number in each. Comma is used when no candidate gives at least two.
+func (d CSVDialect) sniffDelimiter(data []byte) rune {
+	best, bestFields := ',', 1
+	for _, delimiter := range csvSniffDelimiters {
+		if fields := d.sampleFields(data, delimiter); fields > bestFields {
+			best, bestFields = delimiter, fields
+		}
+	}
+
+	return best
+}
+
+// sampleFields returns how many fields each of the first records has when split on
+// delimiter, or 0 when they do not parse or disagree.
+func (d CSVDialect) sampleFields(data []byte, delimiter rune) int {
+	r := d.newReader(data, nil, delimiter)
+	defer r.release()
+	fields := 0
+	for i := 0; i < csvSniffRecords; i++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0
+		}
+		fields = len(record)
+	}
+
+	return fields
+}
+
+// detectSemicolonCSV reports whether lines look like semicolon-delimited CSV, as written by
+// spreadsheets in locales that use a decimal comma. Lines ending in a semicolon are taken
+// to be code rather than data.
+func detectSemicolonCSV(lines []string) bool {
+	if len(lines) <= 1 || strings.HasSuffix(strings.TrimSpace(lines[0]), ";") {
+		return false
+	}
+
+	return consistentDelimiters(lines, ";")
+}
+
+// consistentDelimiters reports whether the first line contains delimiter and the next few
+// non-empty lines contain it the same number of times.
+func consistentDelimiters(lines []string, delimiter string) bool {
+	count := strings.Count(lines[0], delimiter)
+	if count == 0 {
+		return false
+	}
+
+	for i := 1; i < len(lines) && i < 5; i++ {
+		if lines[i] != "" && strings.Count(lines[i], delimiter) != count {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,111 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSVDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect CSVDialect
+		input   string
+		errSub  string
+	}{
+		{"sniffed semicolons", CSVDialect{}, "name;price\nApfel;1,50\nBirne;2,25\n", ""},
+		{"sniffed tabs", CSVDialect{}, "a\tb\tc\n1\t2\t3\n", ""},
+		{"sniffed pipes", CSVDialect{}, "a|b\n1|2\n", ""},
+		{"sniffed commas win ties", CSVDialect{}, "a,b;c\n1,2;3\n", ""},
+		{"sniffed semicolon rows disagree", CSVDialect{}, "a;b\n" + strings.Repeat("1;2\n", 10) + "3;4;5\n",
+			"wrong number of fields"},
+		{"explicit comma rejects semicolon data", CSVDialect{Delimiter: ','}, "name;price\nApfel;1,50\n",
+			"wrong number of fields"},
+		{"explicit semicolon", CSVDialect{Delimiter: ';'}, "a;\"b;c\"\n1;2\n", ""},
+		{"single quotes", CSVDialect{Quote: '\''}, "a,'b,c'\n1,'say \"hi\"'\n", ""},
+		{"single quote escaped by doubling", CSVDialect{Quote: '\''}, "a,'it''s'\n", ""},
+		{"single quotes unterminated", CSVDialect{Quote: '\''}, "a,b\n1,'open\n", "extraneous or missing"},
+		{"comments", CSVDialect{Comment: '#'}, "# exported 2024-01-01\na,b\n1,2\n", ""},
+		{"comments disabled", CSVDialect{Delimiter: ','}, "# exported, daily\na,b,c\n", "wrong number of fields"},
+		{"strict quotes", CSVDialect{}, "a,b\n1,x\"y\n", "bare \" in non-quoted-field"},
+		{"lazy quotes", CSVDialect{LazyQuotes: true}, "a,b\n1,x\"y\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatCSV, WithCSVDialect(tt.dialect))
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != (tt.errSub == "") {
+				t.Fatalf("Valid = %v, want %v (error %q)", result.Valid, tt.errSub == "", result.Error)
+			}
+			if !strings.Contains(result.Error, tt.errSub) {
+				t.Errorf("Error = %q, want substring %q", result.Error, tt.errSub)
+			}
+		})
+	}
+}
+
+func TestCSVDialectErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect CSVDialect
+	}{
+		{"non-ASCII quote", CSVDialect{Quote: '«'}},
+		{"newline delimiter", CSVDialect{Delimiter: '\n'}},
+		{"double quote delimiter", CSVDialect{Delimiter: '"'}},
+		{"delimiter equals comment", CSVDialect{Delimiter: '#', Comment: '#'}},
+		{"quote equals delimiter", CSVDialect{Delimiter: '\'', Quote: '\''}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewValidator(FormatCSV, WithCSVDialect(tt.dialect)); err == nil {
+				t.Errorf("NewValidator(%+v) error = nil, want error", tt.dialect)
+			}
+		})
+	}
+}
+
+func TestDetectSemicolonCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		csv   bool
+	}{
+		{"semicolon export", "Name;Preis;Menge\nApfel;1,50;3\nBirne;2,25;1\n", true},
+		{"statements", "call(a);\ncall(b);\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tt.input)); (got == FormatCSV) != tt.csv {
+				t.Errorf("DetectFormat() = %v, want CSV %v", got, tt.csv)
+			}
+		})
+	}
+}
+
+func TestCSVDialectDelimiterFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect CSVDialect
+		input   string
+		want    rune
+	}{
+		{"sniffed semicolons", CSVDialect{}, "name;price\nApfel;1,50\n", ';'},
+		{"sniffed tabs", CSVDialect{}, "a\tb\n1\t2\n", '\t'},
+		{"sniffed with single quotes", CSVDialect{Quote: '\''}, "'a|b'|c\n'1|2'|3\n", '|'},
+		{"explicit", CSVDialect{Delimiter: '|'}, "a,b\n1,2\n", '|'},
+		{"no delimiter found", CSVDialect{}, "one\n", ','},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.DelimiterFor([]byte(tt.input)); got != tt.want {
+				t.Errorf("DelimiterFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package serdeval
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVType is the type of the values in a CSVSchema column.
+type CSVType string
+
+const (
+	// CSVString accepts any value
+	CSVString CSVType = "string"
+	// CSVInteger accepts base-10 integers such as -42
+	CSVInteger CSVType = "integer"
+	// CSVNumber accepts finite decimal numbers such as 3.14 or 1e-3
+	CSVNumber CSVType = "number"
+	// CSVBoolean accepts true, false, True, False, TRUE, FALSE, 1, and 0
+	CSVBoolean CSVType = "boolean"
+	// CSVDate accepts ISO 8601 calendar dates such as 2024-01-31
+	CSVDate CSVType = "date"
+	// CSVDateTime accepts RFC 3339 timestamps such as 2024-01-31T09:30:00Z
+	CSVDateTime CSVType = "datetime"
+	// CSVEmail accepts bare email addresses such as ada@example.com
+	CSVEmail CSVType = "email"
+	// CSVURL accepts absolute URLs with a scheme and host
+	CSVURL CSVType = "url"
+)
+
+// csvTypeChecks holds the value check for each CSVType other than CSVString.
+var csvTypeChecks = map[CSVType]func(string) bool{
+	CSVInteger: func(s string) bool {
+		_, err := strconv.ParseInt(s, 10, 64)
+
+		return err == nil
+	},
+	CSVNumber: func(s string) bool {
+		f, err := strconv.ParseFloat(s, 64)
+
+		return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) && !strings.ContainsAny(s, "xX_")
+	},
+	CSVBoolean: func(s string) bool {
+		switch s {
+		case "true", "True", "TRUE", "1", "false", "False", "FALSE", "0":
+			return true
+		}
+
+		return false
+	},
+	CSVDate: func(s string) bool {
+		_, err := time.Parse(time.DateOnly, s)
+
+		return err == nil
+	},
+	CSVDateTime: func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+
+		return err == nil
+	},
+	CSVEmail: func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+
+		return err == nil && addr.Name == "" && addr.Address == s
+	},
+	CSVURL: func(s string) bool {
+		u, err := url.Parse(s)
+
+		return err == nil && u.Scheme != "" && u.Host != ""
+	},
+}
+
+// CSVColumn describes one column of a CSVSchema.
+type CSVColumn struct {
+	// Name is the header of the column.
+	Name string `json:"name"`
+	// Type is the type every non-empty value must have; empty means CSVString.
+	Type CSVType `json:"type,omitempty"`
+	// Required makes the column mandatory in the header and its values non-empty.
+	// Columns that are not required may be missing, and their empty values are accepted.
+	Required bool `json:"required,omitempty"`
+}
+
+// CSVSchema describes the header and values a CSV file must have. The first record is the
+// header; its columns are matched to the schema by name, so they may appear in any order.
+//
+// A schema can be loaded from JSON:
+//
+//	{"columns": [{"name": "id", "type": "integer", "required": true}, {"name": "email", "type": "email"}]}
+type CSVSchema struct {
+	// Columns lists the known columns.
+	Columns []CSVColumn `json:"columns"`
+	// AllowExtraColumns accepts header columns that are not in Columns, without checking them.
+	AllowExtraColumns bool `json:"allowExtraColumns,omitempty"`
+}
+
+// check reports a schema that cannot be applied.
+func (s *CSVSchema) check() error {
+	seen := make(map[string]bool, len(s.Columns))
+	for _, col := range s.Columns {
+		if col.Name == "" {
+			return errors.New("invalid CSV schema: column without a name")
+		}
+		if seen[col.Name] {
+			return fmt.Errorf("invalid CSV schema: column %q listed twice", col.Name)
+		}
+		seen[col.Name] = true
+		if _, ok := csvTypeChecks[col.Type]; !ok && col.Type != "" && col.Type != CSVString {
+			return fmt.Errorf("invalid CSV schema: column %q has unknown type %q", col.Name, col.Type)
+		}
+	}
+
+	return nil
+}
+
+// csvTable applies a CSVSchema to the records of one document.
+type csvTable struct {
+	schema *CSVSchema
+	// columns holds the schema column for each header position, nil for extra columns;
+	// it is nil until the header has been read
+	columns []*CSVColumn
+	row     int
+}
+
+// record checks one record read by r, reporting each violation to errs.
+// It returns true once errs is full.
+func (t *csvTable) record(r *csv.Reader, fields []string, errs *errorCollector) bool {
+	if t.columns == nil {
+		return t.header(r, fields, errs)
+	}
+
+	t.row++
+	for i, value := range fields {
+		col := t.columns[i]
+		if col == nil {
+			continue
+		}
+		var problem string
+		switch {
+		case value == "" && col.Required:
+			problem = "is required"
+		case value == "":
+			continue
+		case csvTypeChecks[col.Type] != nil && !csvTypeChecks[col.Type](value):
+			problem = fmt.Sprintf("%q is not a valid %s", value, col.Type)
+		default:
+			continue
+		}
+		line, column := r.FieldPos(i)
+		err := &positionError{line: line, column: column,
+			msg: fmt.Sprintf("row %d, column %q: %s", t.row, col.Name, problem)}
+		if errs.add(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// finish reports a document that ended before its header when the schema requires columns.
+func (t *csvTable) finish(errs *errorCollector) {
+	if t.columns != nil || errs.full() {
+		return
+	}
+	for _, col := range t.schema.Columns {
+		if col.Required {
+			errs.add(errors.New("header: missing header row"))
+
+			return
+		}
+	}
+}
+
+// header matches the header fields to schema columns.
+func (t *csvTable) header(r *csv.Reader, fields []string, errs *errorCollector) bool {
+	byName := make(map[string]*CSVColumn, len(t.schema.Columns))
+	for i := range t.schema.Columns {
+		byName[t.schema.Columns[i].Name] = &t.schema.Columns[i]
+	}
+
+	t.columns = make([]*CSVColumn, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for i, name := range fields {
+		var problem string
+		switch {
+		case seen[name]:
+			problem = fmt.Sprintf("duplicate column %q", name)
+		case byName[name] != nil:
+			t.columns[i] = byName[name]
+		case !t.schema.AllowExtraColumns:
+			problem = fmt.Sprintf("unexpected column %q", name)
+		}
+		seen[name] = true
+		if problem == "" {
+			continue
+		}
+		line, column := r.FieldPos(i)
+		if errs.add(&positionError{line: line, column: column, msg: "header: " + problem}) {
+			return true
+		}
+	}
+
+	line, _ := r.FieldPos(0)
+	for _, col := range t.schema.Columns {
+		if col.Required && !seen[col.Name] {
+			err := &positionError{line: line, msg: fmt.Sprintf("header: missing required column %q", col.Name)}
+			if errs.add(err) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
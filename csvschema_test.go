@@ -0,0 +1,102 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+var peopleSchema = CSVSchema{Columns: []CSVColumn{
+	{Name: "id", Type: CSVInteger, Required: true},
+	{Name: "email", Type: CSVEmail, Required: true},
+	{Name: "score", Type: CSVNumber},
+	{Name: "active", Type: CSVBoolean},
+	{Name: "joined", Type: CSVDate},
+	{Name: "seen", Type: CSVDateTime},
+	{Name: "site", Type: CSVURL},
+	{Name: "note"},
+}}
+
+func TestCSVSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema CSVSchema
+		input  string
+		errSub string
+		line   int
+		column int
+	}{
+		{"all types", peopleSchema, "id,email,score,active,joined,seen,site,note\n" +
+			"1,ada@example.com,3.5,true,2024-01-31,2024-01-31T09:30:00Z,https://example.com,hi\n", "", 0, 0},
+		{"columns in any order", peopleSchema, "email,id\nada@example.com,7\n", "", 0, 0},
+		{"optional empty values", peopleSchema, "id,email,score,joined\n1,a@b.io,,\n", "", 0, 0},
+		{"bad integer", peopleSchema, "id,email\n1,a@b.io\nx2,c@d.io\n",
+			`row 2, column "id": "x2" is not a valid integer`, 3, 1},
+		{"bad email", peopleSchema, "id,email\n1,Ada <ada@example.com>\n",
+			`column "email": "Ada <ada@example.com>" is not a valid email`, 2, 3},
+		{"bad number", peopleSchema, "id,email,score\n1,a@b.io,NaN\n", `"NaN" is not a valid number`, 2, 10},
+		{"bad boolean", peopleSchema, "id,email,active\n1,a@b.io,yes\n", `"yes" is not a valid boolean`, 2, 10},
+		{"bad date", peopleSchema, "id,email,joined\n1,a@b.io,2024-02-30\n", `"2024-02-30" is not a valid date`, 2, 10},
+		{"bad datetime", peopleSchema, "id,email,seen\n1,a@b.io,2024-01-31 09:30\n", "is not a valid datetime", 2, 10},
+		{"relative url", peopleSchema, "id,email,site\n1,a@b.io,/about\n", `"/about" is not a valid url`, 2, 10},
+		{"required value empty", peopleSchema, "id,email\n1,\n", `row 1, column "email": is required`, 2, 3},
+		{"missing required column", peopleSchema, "id,note\n1,x\n", `header: missing required column "email"`, 1, 1},
+		{"unexpected column", peopleSchema, "id,email,age\n1,a@b.io,3\n", `header: unexpected column "age"`, 1, 10},
+		{"extra columns allowed", CSVSchema{Columns: peopleSchema.Columns, AllowExtraColumns: true},
+			"id,email,age\n1,a@b.io,x\n", "", 0, 0},
+		{"duplicate header", peopleSchema, "id,email,id\n1,a@b.io,2\n", `header: duplicate column "id"`, 1, 10},
+		{"no header", peopleSchema, "", "missing header row", 0, 0},
+		{"quoted field position", peopleSchema, "id,email\n\"1\",\"a b\"\n", `"a b" is not a valid email`, 2, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatCSV, WithCSVSchema(tt.schema))
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != (tt.errSub == "") {
+				t.Fatalf("Valid = %v, want %v (error %q)", result.Valid, tt.errSub == "", result.Error)
+			}
+			if !strings.Contains(result.Error, tt.errSub) {
+				t.Errorf("Error = %q, want substring %q", result.Error, tt.errSub)
+			}
+			if tt.line == 0 {
+				return
+			}
+			if d := result.Diagnostics[0]; d.Line != tt.line || d.Column != tt.column {
+				t.Errorf("position = %d:%d, want %d:%d", d.Line, d.Column, tt.line, tt.column)
+			}
+		})
+	}
+}
+
+func TestCSVSchemaMaxErrors(t *testing.T) {
+	v, err := NewValidator(FormatCSV, WithCSVSchema(peopleSchema), WithMaxErrors(-1))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	result := v.ValidateString("id,email,extra\na,b,c\n2,x@y.io,d\n")
+	if got := len(result.Diagnostics); got != 3 {
+		t.Errorf("Diagnostics = %+v, want 3 (extra column, bad id, bad email)", result.Diagnostics)
+	}
+}
+
+func TestCSVSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema CSVSchema
+	}{
+		{"unnamed column", CSVSchema{Columns: []CSVColumn{{Type: CSVInteger}}}},
+		{"duplicate column", CSVSchema{Columns: []CSVColumn{{Name: "a"}, {Name: "a"}}}},
+		{"unknown type", CSVSchema{Columns: []CSVColumn{{Name: "a", Type: "uuid"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewValidator(FormatCSV, WithCSVSchema(tt.schema)); err == nil {
+				t.Error("NewValidator() error = nil, want error")
+			}
+		})
+	}
+}
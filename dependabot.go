@@ -0,0 +1,198 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dependabotUpdateKeys are the options allowed on each entry of a dependabot.yml updates list.
+var dependabotUpdateKeys = []string{
+	"package-ecosystem", "directory", "directories", "schedule", "allow", "assignees", "commit-message",
+	"cooldown", "exclude-paths", "groups", "ignore", "insecure-external-code-execution", "labels", "milestone",
+	"multi-ecosystem-group", "open-pull-requests-limit", "patterns", "pull-request-branch-name",
+	"rebase-strategy", "registries", "reviewers", "target-branch", "vendor", "versioning-strategy",
+}
+
+// dependabotEcosystems are the package-ecosystem values Dependabot supports.
+var dependabotEcosystems = map[string]bool{
+	"bun": true, "bundler": true, "cargo": true, "composer": true, "conda": true, "devcontainers": true,
+	"docker": true, "docker-compose": true, "dotnet-sdk": true, "elm": true, "github-actions": true,
+	"gitsubmodule": true, "gomod": true, "gradle": true, "helm": true, "maven": true, "mix": true, "npm": true,
+	"nuget": true, "pip": true, "pub": true, "swift": true, "terraform": true, "uv": true, "vcpkg": true,
+}
+
+// dependabotIntervals are the allowed values of schedule.interval.
+var dependabotIntervals = map[string]bool{
+	"daily": true, "weekly": true, "monthly": true, "quarterly": true, "semiannually": true, "yearly": true,
+	"cron": true,
+}
+
+var dependabotTimeRe = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+
+// DependabotValidator validates GitHub Dependabot configuration (.github/dependabot.yml).
+// Beyond YAML syntax it requires version 2 and a non-empty updates list, flags unknown keys,
+// and checks each update's package-ecosystem, directory, and schedule.
+//
+// Example:
+//
+//	validator := &DependabotValidator{baseValidator{format: FormatDependabot}}
+//	data, _ := os.ReadFile(".github/dependabot.yml")
+//	result := validator.Validate(data)
+type DependabotValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid Dependabot configuration.
+//
+// Example:
+//
+//	validator := &DependabotValidator{baseValidator{format: FormatDependabot}}
+//	result := validator.ValidateString("version: 2\nupdates:\n  - package-ecosystem: gomod\n" +
+//		"    directory: /\n    schedule:\n      interval: weekly\n")
+func (v *DependabotValidator) Validate(data []byte) Result {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid YAML: " + err.Error(),
+			Suggestion: suggestFix(FormatYAML, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	err := validateDependabotConfig(config)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *DependabotValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateDependabotConfig(config map[string]interface{}) error {
+	allowed := []string{"version", "updates", "registries", "enable-beta-ecosystems", "multi-ecosystem-groups"}
+	if err := checkAllowedKeys(config, "", allowed); err != nil {
+		return err
+	}
+
+	version, ok := config["version"]
+	if !ok {
+		return errors.New("missing required field: version")
+	}
+	if version != 2 {
+		return fmt.Errorf("version must be 2, got %v", version)
+	}
+
+	raw, ok := config["updates"]
+	if !ok {
+		return errors.New("missing required field: updates")
+	}
+	updates, ok := raw.([]interface{})
+	if !ok || len(updates) == 0 {
+		return errors.New("updates must be a non-empty list")
+	}
+
+	seen := make(map[string]int)
+	for i, rawUpdate := range updates {
+		path := fmt.Sprintf("updates[%d]", i)
+		update, isMap := rawUpdate.(map[string]interface{})
+		if !isMap {
+			return fmt.Errorf("%s must be a mapping", path)
+		}
+		if err := checkDependabotUpdate(update, path); err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("%v|%v|%v|%v", update["package-ecosystem"], update["directory"],
+			update["directories"], update["target-branch"])
+		if prev, dup := seen[key]; dup {
+			return fmt.Errorf("%s: duplicates updates[%d] (same ecosystem, directory, and target-branch)", path, prev)
+		}
+		seen[key] = i
+	}
+
+	return nil
+}
+
+func checkDependabotUpdate(update map[string]interface{}, path string) error {
+	if err := checkAllowedKeys(update, path, dependabotUpdateKeys); err != nil {
+		return err
+	}
+
+	ecosystem, _ := update["package-ecosystem"].(string)
+	if ecosystem == "" {
+		return fmt.Errorf("%s: missing required field: package-ecosystem", path)
+	}
+	if !dependabotEcosystems[ecosystem] {
+		return fmt.Errorf("%s: unknown package-ecosystem %q", path, ecosystem)
+	}
+
+	_, hasDir := update["directory"]
+	_, hasDirs := update["directories"]
+	switch {
+	case hasDir && hasDirs:
+		return fmt.Errorf("%s: directory and directories are mutually exclusive", path)
+	case hasDir:
+		if dir, _ := update["directory"].(string); dir == "" {
+			return fmt.Errorf("%s: directory must be a non-empty string", path)
+		}
+	case hasDirs:
+		dirs, err := optionalStringList(update, "directories", path+".directories")
+		if err != nil {
+			return err
+		}
+		if len(dirs) == 0 {
+			return fmt.Errorf("%s.directories must not be empty", path)
+		}
+	default:
+		return fmt.Errorf("%s: missing required field: directory", path)
+	}
+
+	return checkDependabotSchedule(update, path)
+}
+
+func checkDependabotSchedule(update map[string]interface{}, path string) error {
+	raw, ok := update["schedule"]
+	if !ok {
+		return fmt.Errorf("%s: missing required field: schedule", path)
+	}
+	schedule, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s.schedule must be a mapping", path)
+	}
+	path += ".schedule"
+
+	interval, _ := schedule["interval"].(string)
+	if !dependabotIntervals[interval] {
+		return fmt.Errorf("%s: interval must be one of daily, weekly, monthly, quarterly, semiannually, "+
+			"yearly, or cron", path)
+	}
+	if interval == "cron" {
+		if cron, _ := schedule["cronjob"].(string); len(strings.Fields(cron)) != 5 {
+			return fmt.Errorf("%s: cron interval requires a five-field cronjob", path)
+		}
+	}
+	if day, ok := schedule["day"]; ok {
+		switch strings.ToLower(fmt.Sprint(day)) {
+		case "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday":
+		default:
+			return fmt.Errorf("%s: invalid day %q", path, day)
+		}
+	}
+	if t, ok := schedule["time"]; ok {
+		if s, _ := t.(string); !dependabotTimeRe.MatchString(s) {
+			return fmt.Errorf("%s: time must be HH:MM, got %v", path, t)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const dependabotConfig = `version: 2
+updates:
+  - package-ecosystem: gomod
+    directory: /
+    schedule:
+      interval: weekly
+      day: monday
+      time: "09:00"
+  - package-ecosystem: github-actions
+    directories: ["/", "/tools"]
+    schedule:
+      interval: cron
+      cronjob: "0 6 * * 1"
+`
+
+func TestDependabotValidator(t *testing.T) {
+	v := &DependabotValidator{baseValidator{format: FormatDependabot}}
+
+	update := "  - package-ecosystem: npm\n    directory: /\n    schedule:\n      interval: daily\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"valid", dependabotConfig, true, ""},
+		{"missing version", "updates:\n" + update, false, "missing required field: version"},
+		{"wrong version", "version: 1\nupdates:\n" + update, false, "version must be 2"},
+		{"missing updates", "version: 2\n", false, "missing required field: updates"},
+		{"empty updates", "version: 2\nupdates: []\n", false, "non-empty list"},
+		{"unknown top-level", "version: 2\nupdate:\n" + update, false, `unknown top-level key "update"`},
+		{"unknown update key", "version: 2\nupdates:\n" + update + "    labelz: [x]\n", false, `updates[0]: unknown key`},
+		{"unknown ecosystem", "version: 2\nupdates:\n  - package-ecosystem: npmm\n    directory: /\n" +
+			"    schedule:\n      interval: daily\n", false, `unknown package-ecosystem "npmm"`},
+		{"missing directory", "version: 2\nupdates:\n  - package-ecosystem: npm\n    schedule:\n" +
+			"      interval: daily\n", false, "missing required field: directory"},
+		{"missing schedule", "version: 2\nupdates:\n  - package-ecosystem: npm\n    directory: /\n",
+			false, "missing required field: schedule"},
+		{"bad interval", strings.Replace("version: 2\nupdates:\n"+update, "daily", "hourly", 1),
+			false, "interval must be one of"},
+		{"bad day", "version: 2\nupdates:\n" + update + "      day: someday\n", false, "invalid day"},
+		{"bad time", "version: 2\nupdates:\n" + update + "      time: \"25:00\"\n", false, "time must be HH:MM"},
+		{"cron without job", strings.Replace("version: 2\nupdates:\n"+update, "daily", "cron", 1),
+			false, "five-field cronjob"},
+		{"duplicate", "version: 2\nupdates:\n" + update + update, false, "updates[1]: duplicates updates[0]"},
+		{"invalid yaml", "version: 2\nupdates: [\n", false, "invalid YAML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormatFromFilename(".github/dependabot.yml"); got != FormatDependabot {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatDependabot)
+	}
+}
@@ -22,7 +22,7 @@ DOMAIN="YOUR_DOMAIN_HERE"       # e.g., freedatavalidator.xyz
 SERVER_IP="YOUR_SERVER_IP"      # Your server's IP address
 
 echo "📁 Creating service directory..."
-mkdir -p $SERVICE_DIR/web/static
+mkdir -p $SERVICE_DIR
 
 echo "🔧 Setting up service user and permissions..."
 # Create service user if doesn't exist
@@ -39,12 +39,7 @@ else
     echo "⚠️  serdeval binary not found. Please upload it first."
 fi
 
-# Copy web files
-if [ -f "./web/static/index.html" ]; then
-    cp -r ./web/static/* $SERVICE_DIR/web/static/
-else
-    echo "⚠️  Web files not found. Please upload them first."
-fi
+# The web interface is embedded in the binary
 
 # Set ownership
 chown -R serdeval:serdeval $SERVICE_DIR
@@ -0,0 +1,90 @@
+package serdeval
+
+import (
+	"fmt"
+	"sort"
+)
+
+// checkJSONDepth reports the first object or array in data nested more than limit levels
+// deep, before the document is parsed. Brackets inside strings are ignored.
+func checkJSONDepth(data []byte, limit int) error {
+	depth := 0
+	inString, escaped := false, false
+	for i, c := range data {
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > limit {
+				msg := fmt.Sprintf("nesting exceeds max depth of %d at byte offset %d", limit, i)
+
+				return &offsetError{offset: i, msg: msg}
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// checkTOMLDepth reports the first table or array in the decoded document v nested more
+// than limit levels deep, counting the root table as the first level.
+func checkTOMLDepth(v interface{}, limit int) error {
+	if path, ok := tomlTooDeep(v, 1, limit, ""); ok {
+		return fmt.Errorf("nesting exceeds max depth of %d at %s", limit, displayPath(path))
+	}
+
+	return nil
+}
+
+// tomlTooDeep returns the path of the first table or array, v itself or below it, that is
+// deeper than limit, where v is at depth. It never descends past limit+1 levels.
+func tomlTooDeep(v interface{}, depth, limit int, path string) (string, bool) {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, []map[string]interface{}:
+		if depth > limit {
+			return path, true
+		}
+	default:
+		return "", false
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if p, ok := tomlTooDeep(t[k], depth+1, limit, path+"/"+pointerSegment(k)); ok {
+				return p, true
+			}
+		}
+	case []interface{}:
+		for i, item := range t {
+			if p, ok := tomlTooDeep(item, depth+1, limit, fmt.Sprintf("%s/%d", path, i)); ok {
+				return p, true
+			}
+		}
+	case []map[string]interface{}:
+		for i, item := range t {
+			if p, ok := tomlTooDeep(item, depth+1, limit, fmt.Sprintf("%s/%d", path, i)); ok {
+				return p, true
+			}
+		}
+	}
+
+	return "", false
+}
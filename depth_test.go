@@ -0,0 +1,51 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithMaxDepth(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		depth  int
+		input  string
+		error  string // empty when valid
+	}{
+		{"json within limit", FormatJSON, 3, `{"a": {"b": [1]}}`, ""},
+		{"json over limit", FormatJSON, 2, `{"a": {"b": [1]}}`, "nesting exceeds max depth of 2 at byte offset 12"},
+		{"json brackets in strings", FormatJSON, 1, `{"a": "[[{{", "b\"[": 1}`, ""},
+		{"json without limit", FormatJSON, 0, strings.Repeat("[", 500) + strings.Repeat("]", 500), ""},
+		{"json negative disables", FormatJSON, -1, `[[[1]]]`, ""},
+		{"toml within limit", FormatTOML, 3, "[a.b]\nc = 1\n", ""},
+		{"toml table over limit", FormatTOML, 2, "[a.b]\nc = 1\n", "nesting exceeds max depth of 2 at /a/b"},
+		{"toml array over limit", FormatTOML, 3, "x = [[1], [[2]]]\n", "nesting exceeds max depth of 3 at /x/1/0"},
+		{"toml array of tables", FormatTOML, 2, "[[srv]]\nport = 1\n", "nesting exceeds max depth of 2 at /srv/0"},
+		{"yaml over limit", FormatYAML, 2, "a:\n  b: 1\n", "nesting exceeds max depth of 2"},
+		{"yaml negative disables", FormatYAML, -1, nestedYAML(1500), ""},
+		{"xml over limit", FormatXML, 2, "<a><b><c/></b></a>", "element nesting exceeds max depth of 2"},
+		{"xml within limit", FormatXML, 3, "<a><b><c/></b></a>", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.format, WithMaxDepth(tt.depth))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != (tt.error == "") || !strings.Contains(result.Error, tt.error) {
+				t.Errorf("Valid = %v, Error = %q, want error %q", result.Valid, result.Error, tt.error)
+			}
+		})
+	}
+}
+
+func TestMaxDepthPath(t *testing.T) {
+	v, _ := NewValidator(FormatJSON, WithMaxDepth(3))
+	result := v.ValidateString(`{"a": {"b": {"c": [1]}}}`)
+	if result.Err == nil || result.Err.Path != "/a/b/c" {
+		t.Errorf("Err = %+v, want path /a/b/c", result.Err)
+	}
+}
@@ -0,0 +1,192 @@
+package serdeval
+
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DetectionCandidate is one plausible format for some data and how confident detection is
+// in it. The confidences of the candidates DetectFormatAll returns add up to at most 1.
+type DetectionCandidate struct {
+	Format     Format  `json:"format"`
+	Confidence float64 `json:"confidence"`
+}
+
+// detectionTrialLimit is the largest input DetectFormatAll trial-parses. Larger input is
+// ranked on the detection heuristics alone so detection stays cheap.
+const detectionTrialLimit = 256 << 10
+
+// Evidence weights: being DetectFormat's answer counts most, then matching a format's
+// heuristic, then parsing as it.
+const (
+	detectedWeight = 0.5
+	shapeWeight    = 0.25
+	parseWeight    = 0.25
+)
+
+// trialFormat is a general-purpose format that DetectFormatAll weighs against the answer
+// of DetectFormat. Formats whose parsers accept almost any text (a YAML scalar, a
+// one-column CSV, INI with ":" delimiters) are weak: they are candidates only when their
+// heuristic matches too. Flat key = value pairs count as INI and HCL as well as TOML.
+type trialFormat struct {
+	format Format
+	shape  func(trimmed string, lines []string) bool
+	weak   bool
+}
+
+// trialFormats lists the formats ambiguous content is most often mistaken between.
+var trialFormats = []trialFormat{
+	{FormatJSON, func(trimmed string, _ []string) bool { return isJSON(trimmed) }, false},
+	{FormatJSONL, func(_ string, lines []string) bool { return isJSONLines(lines) }, true},
+	{FormatXML, func(trimmed string, _ []string) bool { return isXML(trimmed) }, false},
+	{FormatTOML, func(trimmed string, _ []string) bool { return isTOML(trimmed) }, false},
+	{FormatINI, func(trimmed string, lines []string) bool { return isINI(trimmed, lines) || isTOML(trimmed) }, true},
+	{FormatHCL, func(trimmed string, _ []string) bool { return isHCL(trimmed) || isTOML(trimmed) }, true},
+	{FormatYAML, func(trimmed string, _ []string) bool { return isYAML(trimmed) }, true},
+	{FormatCSV, detectCSV, true},
+	{FormatTSV, func(_ string, lines []string) bool { return detectTSV(lines) }, true},
+}
+
+// DetectFormatAll returns every plausible format for data, most likely first, each with a
+// confidence between 0 and 1. The first candidate is always the format DetectFormat
+// returns; the others are general-purpose formats (JSON, JSON Lines, XML, TOML, INI, HCL,
+// YAML, CSV, TSV) that data also parses as, so callers can tell a clear answer from a
+// close call such as INI versus TOML.
+//
+// Confidence weighs three pieces of evidence: being DetectFormat's pick, matching a
+// format's detection heuristic, and parsing as that format. Input over 256 KiB is not
+// trial-parsed, so its candidates are those whose heuristics match.
+//
+// Returns nil if the format cannot be detected.
+//
+// Example:
+//
+//	for _, c := range DetectFormatAll([]byte("name = \"app\"\nport = 8080")) {
+//		fmt.Printf("%s %.2f\n", c.Format, c.Confidence)
+//	}
+func DetectFormatAll(data []byte) []DetectionCandidate {
+	detected := DetectFormat(data)
+	if detected == FormatUnknown {
+		return nil
+	}
+
+	if text, _, err := DecodeText(data); err == nil {
+		data = text
+	}
+	trimmed := strings.TrimSpace(string(data))
+	pooled := splitLines(trimmed)
+	defer putLines(pooled)
+	lines := *pooled
+	trial := len(data) <= detectionTrialLimit
+
+	// The detected format matched its own heuristic by definition
+	scores := map[Format]float64{detected: detectedWeight + shapeWeight}
+	if trial && parsesAs(detected, data) {
+		scores[detected] += parseWeight
+	}
+	order := []Format{detected}
+
+	for _, tf := range trialFormats {
+		if tf.format == detected {
+			continue
+		}
+		if score := tf.score(data, trimmed, lines, trial); score > 0 {
+			scores[tf.format] = score
+			order = append(order, tf.format)
+		}
+	}
+
+	// Scale by the total evidence, but never up: a lone candidate that fails to parse
+	// stays below 1
+	var total float64
+	for _, score := range scores {
+		total += score
+	}
+	total = max(total, 1)
+	candidates := make([]DetectionCandidate, len(order))
+	for i, format := range order {
+		candidates[i] = DetectionCandidate{Format: format, Confidence: math.Round(scores[format]/total*100) / 100}
+	}
+	slices.SortStableFunc(candidates, func(a, b DetectionCandidate) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+
+	return candidates
+}
+
+// score returns the evidence that data is in the trial format, or 0 if it is not a
+// candidate. Without trial parsing, a matching heuristic is the only evidence.
+func (tf trialFormat) score(data []byte, trimmed string, lines []string, trial bool) float64 {
+	shape := tf.shape(trimmed, lines)
+	switch {
+	case tf.weak && !shape:
+		return 0
+	case !trial && shape:
+		return shapeWeight
+	case !trial || !parsesAs(tf.format, data):
+		return 0
+	case shape:
+		return parseWeight + shapeWeight
+	}
+
+	return parseWeight
+}
+
+// parsesAs reports whether data is valid in format with default options.
+func parsesAs(format Format, data []byte) bool {
+	validator, err := NewValidator(format)
+
+	return err == nil && validator.Validate(data).Valid
+}
+
+// fallbackFormats are the formats ValidateAuto tries in order when no detection heuristic
+// matches data. Each parser is strict enough that success means something, except that
+// any text is a YAML scalar, so YAML counts only with a mapping or sequence at the root.
+var fallbackFormats = []Format{FormatJSON, FormatXML, FormatTOML, FormatHCL, FormatYAML, FormatINI}
+
+// validateFallback validates data as the first of fallbackFormats it parses as.
+func validateFallback(data []byte, opts []Option) Result {
+	var attempted []Format
+	if len(bytes.TrimSpace(data)) > 0 {
+		for _, format := range fallbackFormats {
+			validator, err := NewValidator(format, opts...)
+			if err != nil {
+				continue
+			}
+			result := validator.Validate(data)
+			if result.Valid && (format != FormatYAML || isYAMLCollection(data)) {
+				result.Confidence = parseWeight
+				result.Attempted = attempted
+
+				return result
+			}
+			attempted = append(attempted, format)
+		}
+	}
+
+	result := Result{
+		Valid:     false,
+		Format:    FormatUnknown,
+		Error:     "unable to detect format",
+		Attempted: attempted,
+	}.locate(data, nil)
+	result.Err.Code = ErrCodeUnknownFormat
+
+	return result
+}
+
+// isYAMLCollection reports whether the first YAML document in data is a mapping or sequence.
+func isYAMLCollection(data []byte) bool {
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) != nil || len(doc.Content) == 0 {
+		return false
+	}
+	kind := doc.Content[0].Kind
+
+	return kind == yaml.MappingNode || kind == yaml.SequenceNode
+}
@@ -0,0 +1,106 @@
+package serdeval
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormatAll(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []DetectionCandidate
+	}{
+		{"unambiguous CSV", "a,b\n1,2", []DetectionCandidate{{FormatCSV, 1}}},
+		{"unambiguous YAML", "name: app\nport: 8080", []DetectionCandidate{{FormatYAML, 1}}},
+		{"JSON is also YAML", `{"name": "app"}`, []DetectionCandidate{{FormatJSON, 0.67}, {FormatYAML, 0.33}}},
+		{"flat key value pairs", "name = \"app\"\nport = 8080",
+			[]DetectionCandidate{{FormatTOML, 0.5}, {FormatINI, 0.25}, {FormatHCL, 0.25}}},
+		{"INI section", "[server]\nport = 8080", []DetectionCandidate{{FormatINI, 0.8}, {FormatTOML, 0.2}}},
+		{"detected format fails to parse", "[server]\nports = [80, 443]",
+			[]DetectionCandidate{{FormatJSON, 0.5}, {FormatINI, 0.33}, {FormatTOML, 0.17}}},
+		{"invalid YAML", "a: [1", []DetectionCandidate{{FormatYAML, 0.75}}},
+		{"specific format", "FROM alpine\nRUN echo hi", []DetectionCandidate{{FormatDockerfile, 1}}},
+		{"unknown", "hello world", nil},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectFormatAll([]byte(tt.input))
+			if len(got) != len(tt.want) {
+				t.Fatalf("DetectFormatAll() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("candidate %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			if len(got) > 0 && got[0].Format != DetectFormat([]byte(tt.input)) {
+				t.Errorf("first candidate %v, want DetectFormat's answer %v", got[0].Format, DetectFormat([]byte(tt.input)))
+			}
+		})
+	}
+}
+
+func TestDetectFormatAllLargeInput(t *testing.T) {
+	// Past the trial limit nothing is parsed, so only the heuristics score
+	input := strings.Repeat("name,port\n", detectionTrialLimit/10+1)
+	got := DetectFormatAll([]byte(input))
+	if len(got) != 1 || got[0] != (DetectionCandidate{FormatCSV, 0.75}) {
+		t.Errorf("DetectFormatAll() = %v, want [{csv 0.75}]", got)
+	}
+}
+
+func TestValidateAutoConfidence(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  float64
+	}{
+		{"clear answer", "a,b\n1,2", 1},
+		{"close call", "name = \"app\"\nport = 8080", 0.5},
+		{"unknown", "hello world", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateAuto([]byte(tt.input)).Confidence; got != tt.want {
+				t.Errorf("Confidence = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAutoFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		valid     bool
+		format    Format
+		attempted []Format
+	}{
+		{"TOML with a URL", `url = "https://example.com"`, true, FormatTOML, []Format{FormatJSON, FormatXML}},
+		{"YAML with a URL", "homepage: https://example.com", true, FormatYAML,
+			[]Format{FormatJSON, FormatXML, FormatTOML, FormatHCL}},
+		{"INI with a URL", "name = app\nurl = https://example.com", true, FormatINI,
+			[]Format{FormatJSON, FormatXML, FormatTOML, FormatHCL, FormatYAML}},
+		{"JSON scalar", "42", true, FormatJSON, nil},
+		{"plain text", "random text", false, FormatUnknown, fallbackFormats},
+		{"empty", "", false, FormatUnknown, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateAuto([]byte(tt.input))
+			if result.Valid != tt.valid || result.Format != tt.format {
+				t.Errorf("ValidateAuto() = %v %v, want %v %v (error: %s)",
+					result.Valid, result.Format, tt.valid, tt.format, result.Error)
+			}
+			if !slices.Equal(result.Attempted, tt.attempted) {
+				t.Errorf("Attempted = %v, want %v", result.Attempted, tt.attempted)
+			}
+		})
+	}
+}
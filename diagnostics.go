@@ -0,0 +1,211 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/hashicorp/hcl/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic locates a single validation failure in the input.
+// Line and Column are 1-based (columns count bytes) and Offset is the 0-based byte offset;
+// all three are zero when the failure cannot be tied to a position, such as a missing field.
+type Diagnostic struct {
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+	Message string `json:"message"`
+}
+
+// diagnosticLineRe finds positions embedded in error messages, in the forms validators and
+// parsers use: "line 3", "line 3:7", "line 3, column 7", "line 3 col 7", and "(3:7)".
+var diagnosticLineRe = regexp.MustCompile(
+	`(?i)\bline (\d+)(?::(\d+)|,? col(?:umn)? (\d+))?|\((\d+):(\d+)\)`)
+
+// offsetError is a failure found by a validator's own checks at a byte offset in the input.
+type offsetError struct {
+	offset int
+	msg    string
+}
+
+func (e *offsetError) Error() string {
+	return e.msg
+}
+
+// positionError is a failure found by a validator's own checks at a 1-based line and column.
+type positionError struct {
+	line   int
+	column int
+	msg    string
+}
+
+func (e *positionError) Error() string {
+	return e.msg
+}
+
+// locate fills r.Diagnostics for a failed result from err, or from r.Error when err is nil.
+// Typed parser errors give exact positions; otherwise the position is read from the message.
+func (r Result) locate(data []byte, err error) Result {
+	if r.Valid || r.Error == "" {
+		return r
+	}
+	if err == nil {
+		err = errors.New(r.Error)
+	}
+	r.Diagnostics = diagnose(data, err, r.Error)
+	r.Err = newValidationError(ErrCodeInvalid, data, r)
+
+	return r
+}
+
+// diagnose converts err into diagnostics, using message as the text of single-position errors.
+// Failures collected under WithMaxErrors are located one by one.
+func diagnose(data []byte, err error, message string) []Diagnostic {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return diagnoseOne(data, err, message)
+	}
+
+	var diags []Diagnostic
+	for _, e := range joined.Unwrap() {
+		diags = append(diags, diagnose(data, e, e.Error())...)
+	}
+
+	return diags
+}
+
+// diagnoseOne converts a single failure into diagnostics.
+func diagnoseOne(data []byte, err error, message string) []Diagnostic {
+	if diag, ok := ownDiagnostic(data, err, message); ok {
+		return []Diagnostic{diag}
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		tomlErr   toml.ParseError
+		xmlErr    *xml.SyntaxError
+		csvErr    *csv.ParseError
+		yamlErr   *yaml.TypeError
+		hclDiags  hcl.Diagnostics
+		gqlErr    *gqlerrors.Error
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return []Diagnostic{atOffset(data, int(syntaxErr.Offset)-1, message)}
+	case errors.As(err, &typeErr):
+		return []Diagnostic{atOffset(data, int(typeErr.Offset)-1, message)}
+	case errors.As(err, &tomlErr):
+		return []Diagnostic{atOffset(data, tomlErr.Position.Start, message)}
+	case errors.As(err, &xmlErr):
+		return []Diagnostic{atLine(data, xmlErr.Line, 0, message)}
+	case errors.As(err, &csvErr):
+		return []Diagnostic{atLine(data, csvErr.Line, csvErr.Column, message)}
+	case errors.As(err, &yamlErr):
+		diags := make([]Diagnostic, 0, len(yamlErr.Errors))
+		for _, e := range yamlErr.Errors {
+			diags = append(diags, fromMessage(data, e))
+		}
+
+		return diags
+	case errors.As(err, &hclDiags):
+		diags := make([]Diagnostic, 0, len(hclDiags))
+		for _, d := range hclDiags {
+			if d.Severity != hcl.DiagError {
+				continue
+			}
+			diag := Diagnostic{Message: d.Summary + "; " + d.Detail}
+			if d.Subject != nil {
+				diag.Line, diag.Column, diag.Offset = d.Subject.Start.Line, d.Subject.Start.Column, d.Subject.Start.Byte
+			}
+			diags = append(diags, diag)
+		}
+
+		return diags
+	case errors.As(err, &gqlErr) && len(gqlErr.Locations) > 0:
+		return []Diagnostic{atLine(data, gqlErr.Locations[0].Line, gqlErr.Locations[0].Column, message)}
+	}
+
+	return []Diagnostic{fromMessage(data, message)}
+}
+
+// ownDiagnostic locates the position-carrying errors that validators raise from their own checks.
+func ownDiagnostic(data []byte, err error, message string) (Diagnostic, bool) {
+	var (
+		offsetErr   *offsetError
+		positionErr *positionError
+	)
+
+	switch {
+	case errors.As(err, &offsetErr):
+		return atOffset(data, offsetErr.offset, message), true
+	case errors.As(err, &positionErr):
+		return atLine(data, positionErr.line, positionErr.column, message), true
+	}
+
+	return Diagnostic{}, false
+}
+
+// fromMessage reads the first position mentioned in message, if any.
+func fromMessage(data []byte, message string) Diagnostic {
+	m := diagnosticLineRe.FindStringSubmatch(message)
+	if m == nil {
+		return Diagnostic{Message: message}
+	}
+
+	line, col := m[1], m[2]+m[3]
+	if line == "" {
+		line, col = m[4], m[5]
+	}
+	lineNum, _ := strconv.Atoi(line)
+	colNum, _ := strconv.Atoi(col)
+
+	return atLine(data, lineNum, colNum, message)
+}
+
+// atLine builds a diagnostic for a 1-based line and column (0 for the start of the line).
+func atLine(data []byte, line, column int, message string) Diagnostic {
+	if line < 1 {
+		return Diagnostic{Message: message}
+	}
+
+	offset := 0
+	for i := 1; i < line; i++ {
+		next := bytes.IndexByte(data[offset:], '\n')
+		if next < 0 {
+			// The line is past the end of the input; report it without an offset
+			return Diagnostic{Line: line, Column: column, Message: message}
+		}
+		offset += next + 1
+	}
+	if column < 1 {
+		column = 1
+	}
+
+	return Diagnostic{Line: line, Column: column, Offset: offset + column - 1, Message: message}
+}
+
+// atOffset builds a diagnostic for a 0-based byte offset, deriving its line and column.
+func atOffset(data []byte, offset int, message string) Diagnostic {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(data) {
+		offset = len(data)
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte{'\n'}) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+
+	return Diagnostic{Line: line, Column: column, Offset: offset, Message: message}
+}
@@ -0,0 +1,53 @@
+package serdeval
+
+import "testing"
+
+func TestResultDiagnostics(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		line   int
+		column int
+		offset int
+	}{
+		{"json syntax", FormatJSON, "{\n  \"a\": 1,\n  \"b\": x\n}", 3, 8, 19},
+		{"json first line", FormatJSON, "[1,]", 1, 4, 3},
+		{"toml", FormatTOML, "a = 1\nb = \n", 2, 5, 10},
+		{"xml", FormatXML, "<a>\n<b></c>\n</a>", 2, 1, 4},
+		{"csv", FormatCSV, "a,b\n1,\"x\"y\n", 2, 5, 8},
+		{"yaml", FormatYAML, "a: 1\nb: [\n", 2, 1, 5},
+		{"hcl", FormatHCL, "a = 1\nb = {\n", 3, 1, 12},
+		{"graphql", FormatGraphQL, "query {\n  a\n", 3, 1, 12},
+		{"robots", FormatRobots, "User-agent: *\nNoindex: /\n", 2, 1, 14},
+		{"no position", FormatHAR, `{"log": {}}`, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid {
+				t.Fatalf("ValidateString() = valid, want invalid")
+			}
+			if len(result.Diagnostics) == 0 {
+				t.Fatalf("Diagnostics is empty for error %q", result.Error)
+			}
+			d := result.Diagnostics[0]
+			if d.Line != tt.line || d.Column != tt.column || d.Offset != tt.offset {
+				t.Errorf("Diagnostic = %d:%d offset %d, want %d:%d offset %d (error: %s)",
+					d.Line, d.Column, d.Offset, tt.line, tt.column, tt.offset, result.Error)
+			}
+			if d.Message == "" {
+				t.Error("Diagnostic.Message is empty")
+			}
+		})
+	}
+
+	if result := ValidateAuto([]byte(`{"a": 1}`)); len(result.Diagnostics) != 0 {
+		t.Errorf("Diagnostics = %v for valid input, want none", result.Diagnostics)
+	}
+}
@@ -15,6 +15,30 @@ for all supported formats.
   - Simple, consistent API across all formats
   - Privacy-focused: no logging, network calls, or data retention
   - Comprehensive error messages for validation failures
+  - Line, column, and byte offset diagnostics for validation failures
+  - Typed ValidationError with an error code, position, and source snippet on every failure
+  - Path of the failing element (JSON Pointer, or XPath-like for XML) for JSON, YAML, TOML, and XML
+  - Non-fatal warnings (YAML tabs, CSV trailing whitespace, deprecated Dockerfile instructions)
+  - Opt-in scanning for likely credentials with WithSecretScan or ScanSecrets
+  - Opt-in scanning of data files for personal data with WithPIIScan or ScanPII
+  - Cancellation and deadlines through ValidateContext and ValidateAutoContext
+  - JSON Schema generated from example JSON, YAML, or CSV documents with InferSchema
+  - Offline checks of relative links, images, and anchors in Markdown with CheckMarkdownLinks
+  - Whole file systems (embed.FS, zip archives, os.DirFS) validated with ValidateFS
+  - Results streamed over a channel as files are validated with ValidatePaths
+  - Concurrent validation of many inputs with the batch subpackage
+  - UTF-16 and byte order marks decoded before validation, and Latin-1 with WithLatin1
+  - Binary files (images, archives, executables) recognized with IsBinary and skipped by ValidateAuto
+  - Duration, bytes read, and line count reported on every Result
+  - Custom formats added with Register take part in NewValidator and auto-detection
+  - XML Schema (XSD) validation with WithXMLSchema
+  - Strict JSON and YAML modes (duplicate keys, invalid UTF-8, unknown tags) with WithStrict
+  - Input size ceiling with WithMaxSize, checked before files are read
+  - Nesting depth limit for JSON, YAML, XML, and TOML with WithMaxDepth
+  - YAML alias expansion, depth, and node count limits against billion-laughs input
+  - XML entity, depth, and attribute limits, with an option to forbid DOCTYPE
+  - CSV dialects (delimiter sniffing, quote, comment, lazy quotes) with WithCSVDialect
+  - CSV table schemas (required columns, integer/date/email/... values) with WithCSVSchema
 
 # Basic Usage
 
@@ -45,11 +69,26 @@ Use ValidateAuto for automatic format detection:
 	fmt.Printf("Detected format: %s\n", result.Format)
 	fmt.Printf("Valid: %v\n", result.Valid)
 
+ValidateAuto sets result.Confidence to how sure detection is of the format. For ambiguous
+content, DetectFormatAll lists every plausible format with its confidence:
+
+	for _, c := range serdeval.DetectFormatAll([]byte("name = \"app\"\nport = 8080")) {
+		fmt.Printf("%s %.2f\n", c.Format, c.Confidence) // toml first, then ini and hcl
+	}
+
+Content no heuristic recognizes is parsed as JSON, XML, TOML, HCL, YAML, and INI in turn;
+the first that succeeds is the result's Format, and result.Attempted lists the rest tried.
+
 Detect format from filename:
 
 	format := serdeval.DetectFormatFromFilename("config.yaml")
 	// format == serdeval.FormatYAML
 
+Or from an HTTP Content-Type header:
+
+	format := serdeval.DetectFormatFromMIME("application/yaml; charset=utf-8")
+	// format == serdeval.FormatYAML
+
 # Supported Formats
 
 The package supports the following formats:
@@ -59,15 +98,44 @@ The package supports the following formats:
   - XML (FormatXML): Well-formed XML validation
   - TOML (FormatTOML): TOML v1.0.0 format
   - CSV (FormatCSV): Comma-separated values with consistent columns
+  - TSV (FormatTSV): Tab-separated values with consistent columns
   - GraphQL (FormatGraphQL): GraphQL queries, mutations, and schemas
   - INI (FormatINI): INI configuration files with sections
-  - HCL (FormatHCL): HashiCorp Configuration Language (HCL2)
-  - Protobuf (FormatProtobuf): Protocol Buffers text format
+  - HCL (FormatHCL): HashiCorp Configuration Language (HCL2) in native or JSON syntax,
+    optionally checked as a Terraform configuration with WithTerraform
+  - Protobuf (FormatProtobuf): Protocol Buffers text format, optionally checked against a message type
   - Markdown (FormatMarkdown): CommonMark specification
   - JSON Lines (FormatJSONL): Newline-delimited JSON
   - Jupyter (FormatJupyter): Jupyter Notebook .ipynb files
   - Requirements (FormatRequirements): Python requirements.txt
   - Dockerfile (FormatDockerfile): Docker container definitions
+  - Ion (FormatIon): Amazon Ion text and binary streams
+  - logfmt (FormatLogfmt): key=value structured log lines
+  - Syslog (FormatSyslog): RFC 5424 and legacy RFC 3164 messages
+  - Access log (FormatAccessLog): Common and Combined Log Format lines
+  - HAR (FormatHAR): HTTP Archive 1.2 browser exports
+  - WARC (FormatWARC): Web ARChive records, plain or gzip-compressed
+  - SRT (FormatSRT): SubRip subtitles with sequential, ordered cues
+  - WebVTT (FormatWebVTT): Web Video Text Tracks subtitles
+  - PO (FormatPO): gettext translation catalogs
+  - XLIFF (FormatXLIFF): XLIFF 1.2 and 2.x localization files
+  - ARB (FormatARB): Flutter Application Resource Bundles
+  - robots.txt (FormatRobots): Crawler user-agent groups and rules
+  - Sitemap (FormatSitemap): sitemaps.org XML sitemaps and sitemap indexes
+  - SSH keys (FormatSSHKey): OpenSSH private keys, public keys, and authorized_keys
+  - OpenTelemetry Collector (FormatOtelCollector): Collector configs with pipeline references checked
+  - Traefik (FormatTraefik): Dynamic routing configuration in YAML or TOML
+  - Azure Pipelines (FormatAzurePipelines): Azure DevOps stages, jobs, steps, and tasks
+  - Renovate (FormatRenovate): Renovate bot configuration in JSON or JSON5
+  - Dependabot (FormatDependabot): GitHub Dependabot version updates configuration
+  - CODEOWNERS (FormatCodeowners): Path patterns and code owners for GitHub and GitLab
+  - Changelog (FormatChangelog): CHANGELOG.md files following Keep a Changelog
+  - SPDX (FormatSPDX): License expressions and SBOM documents in tag-value or JSON form
+  - Commit messages (FormatCommitMsg): Conventional Commits headers, bodies, and footers
+  - Protobuf JSON (FormatProtoJSON): Protobuf JSON mapping payloads, optionally checked against a message type
+  - Protobuf schema (FormatProtoIDL): .proto files in proto2, proto3, or editions syntax
+  - JSON Patch (FormatJSONPatch): RFC 6902 patch operations, as used by kubectl and kustomize
+  - JSON Merge Patch (FormatMergePatch): RFC 7386 merge patch objects
 
 # Advanced Usage
 
@@ -0,0 +1,133 @@
+package serdeval
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding names a text encoding that validators decode to UTF-8 before parsing.
+type Encoding string
+
+const (
+	// EncodingUTF8 is UTF-8 without a byte order mark, which needs no decoding
+	EncodingUTF8 Encoding = "utf-8"
+	// EncodingUTF8BOM is UTF-8 that starts with a byte order mark
+	EncodingUTF8BOM Encoding = "utf-8-bom"
+	// EncodingUTF16LE is little-endian UTF-16, as Windows tools often write it
+	EncodingUTF16LE Encoding = "utf-16le"
+	// EncodingUTF16BE is big-endian UTF-16
+	EncodingUTF16BE Encoding = "utf-16be"
+	// EncodingLatin1 is ISO 8859-1, decoded only with WithLatin1
+	EncodingLatin1 Encoding = "iso-8859-1"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectEncoding returns the encoding of data from its byte order mark. Without one,
+// text that starts with two ASCII characters in UTF-16 (every other byte zero) is
+// recognized as UTF-16 too, as RFC 4627 describes for JSON. Anything else is reported as
+// EncodingUTF8, including input that is not valid UTF-8.
+//
+// Example:
+//
+//	data, _ := os.ReadFile("export.csv")
+//	if DetectEncoding(data) != EncodingUTF8 {
+//		fmt.Println("not plain UTF-8")
+//	}
+func DetectEncoding(data []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return EncodingUTF8BOM
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingUTF16BE
+	case len(data) >= 4 && data[0] != 0 && data[1] == 0 && data[2] != 0 && data[3] == 0:
+		return EncodingUTF16LE
+	case len(data) >= 4 && data[0] == 0 && data[1] != 0 && data[2] == 0 && data[3] != 0:
+		return EncodingUTF16BE
+	}
+
+	return EncodingUTF8
+}
+
+// DecodeText returns data as UTF-8 without a byte order mark, and the encoding it was
+// decoded from. UTF-8 input is returned as is, apart from dropping a byte order mark.
+// It returns an error for UTF-16 input with an odd number of bytes; unpaired surrogates
+// become U+FFFD.
+//
+// Example:
+//
+//	text, enc, err := DecodeText(data)
+//	if err == nil && enc != EncodingUTF8 {
+//		fmt.Printf("decoded from %s\n", enc)
+//	}
+func DecodeText(data []byte) ([]byte, Encoding, error) {
+	enc := DetectEncoding(data)
+	switch enc {
+	case EncodingUTF8BOM:
+		return data[len(bomUTF8):], enc, nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		text, err := decodeUTF16(data, enc == EncodingUTF16BE)
+
+		return text, enc, err
+	}
+
+	return data, enc, nil
+}
+
+// decodeUTF16 converts UTF-16 data, with or without a byte order mark, to UTF-8.
+func decodeUTF16(data []byte, bigEndian bool) ([]byte, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("invalid UTF-16 text: odd number of bytes (%d)", len(data))
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		hi, lo := data[2*i+1], data[2*i]
+		if bigEndian {
+			hi, lo = lo, hi
+		}
+		units[i] = uint16(hi)<<8 | uint16(lo)
+	}
+	if len(units) > 0 && units[0] == 0xFEFF {
+		units = units[1:]
+	}
+
+	return []byte(string(utf16.Decode(units))), nil
+}
+
+// decodeLatin1 converts ISO 8859-1 data, where every byte is the code point of the same
+// value, to UTF-8.
+func decodeLatin1(data []byte) []byte {
+	text := make([]byte, 0, len(data)+len(data)/8)
+	for _, b := range data {
+		text = utf8.AppendRune(text, rune(b))
+	}
+
+	return text
+}
+
+// decodeInput decodes data to UTF-8 as NewValidator's validators do: byte order marks and
+// UTF-16 always, and input that is not valid UTF-8 as Latin-1 when latin1 is set.
+// The encoding is empty when data was plain UTF-8.
+func decodeInput(data []byte, latin1 bool) ([]byte, Encoding, error) {
+	text, enc, err := DecodeText(data)
+	if err != nil {
+		return nil, enc, err
+	}
+	if enc == EncodingUTF8 {
+		if !latin1 || utf8.Valid(data) {
+			return data, "", nil
+		}
+
+		return decodeLatin1(data), EncodingLatin1, nil
+	}
+
+	return text, enc, nil
+}
@@ -0,0 +1,121 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// utf16Bytes encodes s as UTF-16 with a byte order mark, as Windows tools save it.
+func utf16Bytes(s string, bigEndian bool) []byte {
+	units := append([]uint16{0xFEFF}, utf16.Encode([]rune(s))...)
+	data := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		if bigEndian {
+			data = append(data, byte(u>>8), byte(u))
+		} else {
+			data = append(data, byte(u), byte(u>>8))
+		}
+	}
+
+	return data
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		want    string
+		enc     Encoding
+		errPart string
+	}{
+		{"plain UTF-8", []byte(`{"a": "é"}`), `{"a": "é"}`, EncodingUTF8, ""},
+		{"UTF-8 BOM", []byte("\ufeff{}"), "{}", EncodingUTF8BOM, ""},
+		{"UTF-16LE BOM", utf16Bytes(`{"a": "é"}`, false), `{"a": "é"}`, EncodingUTF16LE, ""},
+		{"UTF-16BE BOM", utf16Bytes("a,b\n1,2", true), "a,b\n1,2", EncodingUTF16BE, ""},
+		{"UTF-16LE without BOM", []byte("{\x00}\x00"), "{}", EncodingUTF16LE, ""},
+		{"UTF-16BE without BOM", []byte("\x00[\x00]"), "[]", EncodingUTF16BE, ""},
+		{"surrogate pair", utf16Bytes("😀", false), "😀", EncodingUTF16LE, ""},
+		{"odd length", []byte("\xff\xfe{\x00}"), "", EncodingUTF16LE, "odd number of bytes (5)"},
+		{"Latin-1 left alone", []byte("caf\xe9"), "caf\xe9", EncodingUTF8, ""},
+		{"empty", nil, "", EncodingUTF8, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, enc, err := DecodeText(tt.input)
+			if enc != tt.enc {
+				t.Errorf("encoding = %v, want %v", enc, tt.enc)
+			}
+			if tt.errPart != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errPart) {
+					t.Errorf("error = %v, want it to contain %q", err, tt.errPart)
+				}
+
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("DecodeText() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorDecodesInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		input   []byte
+		opts    []Option
+		valid   bool
+		enc     Encoding
+		errPart string
+	}{
+		{"UTF-16LE JSON", FormatJSON, utf16Bytes(`{"name": "app"}`, false), nil, true, EncodingUTF16LE, ""},
+		{"UTF-16BE CSV", FormatCSV, utf16Bytes("a,b\n1,2\n", true), nil, true, EncodingUTF16BE, ""},
+		{"UTF-8 BOM YAML", FormatYAML, []byte("\ufeffname: app\n"), nil, true, EncodingUTF8BOM, ""},
+		{"truncated UTF-16", FormatJSON, []byte("\xff\xfe{\x00}"), nil, false, EncodingUTF16LE, "odd number of bytes"},
+		{"Latin-1 rejected by strict JSON", FormatJSON, []byte(`{"a": "caf` + "\xe9" + `"}`),
+			[]Option{WithStrict()}, false, "", "UTF-8"},
+		{"Latin-1 decoded", FormatJSON, []byte(`{"a": "caf` + "\xe9" + `"}`),
+			[]Option{WithStrict(), WithLatin1()}, true, EncodingLatin1, ""},
+		{"UTF-8 untouched by Latin-1", FormatJSON, []byte(`{"a": "café"}`), []Option{WithLatin1()}, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.format, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := v.Validate(tt.input)
+			if result.Valid != tt.valid || result.Encoding != tt.enc {
+				t.Errorf("Validate() = %v, %q, want %v, %q (error: %s)",
+					result.Valid, result.Encoding, tt.valid, tt.enc, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestValidateAutoDecodesInput(t *testing.T) {
+	result := ValidateAuto(utf16Bytes("id,name\n1,app\n", false))
+	if !result.Valid || result.Format != FormatCSV || result.Encoding != EncodingUTF16LE {
+		t.Errorf("ValidateAuto() = %v %v %q, want valid csv from utf-16le (error: %s)",
+			result.Valid, result.Format, result.Encoding, result.Error)
+	}
+	if got := DetectFormat(utf16Bytes(`{"a": 1}`, true)); got != FormatJSON {
+		t.Errorf("DetectFormat() = %v, want json", got)
+	}
+}
+
+func TestDecodedDiagnostics(t *testing.T) {
+	// Positions refer to the decoded text, not the UTF-16 bytes
+	v, _ := NewValidator(FormatJSON)
+	result := v.Validate(utf16Bytes("{\n  \"a\": }", false))
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Line != 2 || result.Diagnostics[0].Column != 8 {
+		t.Errorf("Diagnostics = %+v, want line 2, column 8", result.Diagnostics)
+	}
+}
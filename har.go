@@ -0,0 +1,185 @@
+package serdeval
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// HARValidator validates HTTP Archive (HAR 1.2) files.
+// Beyond JSON syntax it checks log.version, log.creator, the entries array,
+// and the fields HAR requires on each request, response, and timings object.
+//
+// Example:
+//
+//	validator := &HARValidator{baseValidator{format: FormatHAR}}
+//	data, _ := os.ReadFile("session.har")
+//	result := validator.Validate(data)
+type HARValidator struct {
+	baseValidator
+}
+
+// harFields lists required fields and their JSON kinds for each HAR object.
+var harFields = map[string][][2]string{
+	"log":     {{"version", "string"}, {"creator", "object"}, {"entries", "array"}},
+	"creator": {{"name", "string"}, {"version", "string"}},
+	"entry": {
+		{"startedDateTime", "string"}, {"time", "number"}, {"request", "object"},
+		{"response", "object"}, {"cache", "object"}, {"timings", "object"},
+	},
+	"request": {
+		{"method", "string"}, {"url", "string"}, {"httpVersion", "string"}, {"cookies", "array"},
+		{"headers", "array"}, {"queryString", "array"}, {"headersSize", "number"}, {"bodySize", "number"},
+	},
+	"response": {
+		{"status", "number"}, {"statusText", "string"}, {"httpVersion", "string"}, {"cookies", "array"},
+		{"headers", "array"}, {"content", "object"}, {"redirectURL", "string"},
+		{"headersSize", "number"}, {"bodySize", "number"},
+	},
+	"content": {{"size", "number"}, {"mimeType", "string"}},
+	"timings": {{"send", "number"}, {"wait", "number"}, {"receive", "number"}},
+	"header":  {{"name", "string"}, {"value", "string"}},
+}
+
+// Validate checks if the provided byte slice contains a structurally valid HAR file.
+// Errors name the offending field by path, e.g. "log.entries[2].request: missing required field: url".
+//
+// Example:
+//
+//	validator := &HARValidator{baseValidator{format: FormatHAR}}
+//	result := validator.ValidateString(`{"log": {"version": "1.2",
+//		"creator": {"name": "devtools", "version": "1"}, "entries": []}}`)
+func (v *HARValidator) Validate(data []byte) Result {
+	var har map[string]interface{}
+	if err := json.Unmarshal(data, &har); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid JSON: " + err.Error(),
+			Suggestion: suggestFix(FormatJSON, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	err := validateHAR(har)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *HARValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateHAR(har map[string]interface{}) error {
+	log, ok := har["log"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("missing required field: log")
+	}
+	if err := checkHARObject(log, "log", "log"); err != nil {
+		return err
+	}
+	if version := log["version"].(string); version != "1.1" && version != "1.2" {
+		return fmt.Errorf("log.version: unsupported HAR version %q (expected 1.1 or 1.2)", version)
+	}
+	if err := checkHARObject(log["creator"].(map[string]interface{}), "log.creator", "creator"); err != nil {
+		return err
+	}
+
+	for i, raw := range log["entries"].([]interface{}) {
+		path := fmt.Sprintf("log.entries[%d]", i)
+		entry, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: entry must be an object", path)
+		}
+		if err := validateHAREntry(entry, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateHAREntry(entry map[string]interface{}, path string) error {
+	if err := checkHARObject(entry, path, "entry"); err != nil {
+		return err
+	}
+
+	request := entry["request"].(map[string]interface{})
+	if err := checkHARObject(request, path+".request", "request"); err != nil {
+		return err
+	}
+	response := entry["response"].(map[string]interface{})
+	if err := checkHARObject(response, path+".response", "response"); err != nil {
+		return err
+	}
+	content := response["content"].(map[string]interface{})
+	if err := checkHARObject(content, path+".response.content", "content"); err != nil {
+		return err
+	}
+	if err := checkHARObject(entry["timings"].(map[string]interface{}), path+".timings", "timings"); err != nil {
+		return err
+	}
+
+	for _, list := range []struct {
+		obj   map[string]interface{}
+		path  string
+		field string
+	}{
+		{request, path + ".request", "headers"},
+		{request, path + ".request", "queryString"},
+		{response, path + ".response", "headers"},
+	} {
+		for j, raw := range list.obj[list.field].([]interface{}) {
+			itemPath := fmt.Sprintf("%s.%s[%d]", list.path, list.field, j)
+			item, ok := raw.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s: must be an object", itemPath)
+			}
+			if err := checkHARObject(item, itemPath, "header"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkHARObject verifies obj has every field required for kind, with the right JSON type.
+func checkHARObject(obj map[string]interface{}, path, kind string) error {
+	for _, field := range harFields[kind] {
+		name, want := field[0], field[1]
+		value, ok := obj[name]
+		if !ok {
+			return fmt.Errorf("%s: missing required field: %s", path, name)
+		}
+
+		var match bool
+		switch want {
+		case "string":
+			_, match = value.(string)
+		case "number":
+			_, match = value.(float64)
+		case "array":
+			_, match = value.([]interface{})
+		case "object":
+			_, match = value.(map[string]interface{})
+		}
+		if !match {
+			return fmt.Errorf("%s.%s: expected %s", path, name, want)
+		}
+	}
+
+	return nil
+}
+
+// isHAR checks if JSON content appears to be an HTTP Archive.
+func isHAR(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "{") &&
+		strings.Contains(trimmed, "\"log\"") &&
+		strings.Contains(trimmed, "\"entries\"") &&
+		strings.Contains(trimmed, "\"creator\"")
+}
@@ -0,0 +1,69 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const harEntry = `{
+  "startedDateTime": "2024-01-05T10:00:00.000Z", "time": 50, "cache": {},
+  "request": {"method": "GET", "url": "https://example.com/", "httpVersion": "HTTP/1.1",
+    "cookies": [], "headers": [{"name": "Accept", "value": "*/*"}], "queryString": [],
+    "headersSize": -1, "bodySize": 0},
+  "response": {"status": 200, "statusText": "OK", "httpVersion": "HTTP/1.1", "cookies": [],
+    "headers": [], "content": {"size": 12, "mimeType": "text/html"}, "redirectURL": "",
+    "headersSize": -1, "bodySize": 12},
+  "timings": {"send": 1, "wait": 40, "receive": 9}
+}`
+
+func harDoc(version, entries string) string {
+	return `{"log": {"version": "` + version + `", "creator": {"name": "test", "version": "1.0"}, "entries": [` +
+		entries + `]}}`
+}
+
+func TestHARValidator(t *testing.T) {
+	v := &HARValidator{baseValidator{format: FormatHAR}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"empty entries", harDoc("1.2", ""), true, ""},
+		{"one entry", harDoc("1.2", harEntry), true, ""},
+		{"invalid json", `{"log": }`, false, "invalid JSON"},
+		{"missing log", `{}`, false, "missing required field: log"},
+		{"bad version", harDoc("2.0", ""), false, "unsupported HAR version"},
+		{"missing creator", `{"log": {"version": "1.2", "entries": []}}`, false, "log: missing required field: creator"},
+		{
+			"entry missing url", harDoc("1.2", strings.Replace(harEntry, `"url": "https://example.com/", `, "", 1)),
+			false, "log.entries[0].request: missing required field: url",
+		},
+		{
+			"status wrong type", harDoc("1.2", strings.Replace(harEntry, `"status": 200`, `"status": "200"`, 1)),
+			false, "log.entries[0].response.status: expected number",
+		},
+		{
+			"header missing value", harDoc("1.2", strings.Replace(harEntry, `, "value": "*/*"`, "", 1)),
+			false, "request.headers[0]: missing required field: value",
+		},
+		{"entry not object", harDoc("1.2", `42`), false, "entry must be an object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte(harDoc("1.2", harEntry))); got != FormatHAR {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatHAR)
+	}
+}
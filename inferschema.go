@@ -0,0 +1,342 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// inferFormats are the formats InferSchema reads samples in.
+var inferFormats = []Format{FormatJSON, FormatYAML, FormatCSV}
+
+// InferSchema returns a JSON Schema (draft 2020-12) that every one of samples satisfies,
+// written as indented JSON. Samples are JSON, YAML, or CSV documents in format; with
+// FormatAuto, the format of each sample is detected from its content. Every document of
+// a YAML stream is a sample of its own, and a CSV sample is an array of objects, one per
+// row, keyed by the header.
+//
+// The schema is as strict as the samples allow:
+//
+//   - Object properties present in every sample of the object are required, and the
+//     others are optional. Unknown properties are not forbidden.
+//   - A value that is an integer in one sample and a fraction in another is a number; a
+//     value of several types lists all of them.
+//   - Strings get a format (date, date-time, email, or uri) when every sample matches it.
+//   - Array items share one schema, merged from every item of every sample.
+//   - Empty CSV cells count as missing properties, and other cells are typed as integer,
+//     number, boolean, or string.
+//
+// The result is plain JSON Schema, to check further documents with any JSON Schema
+// validator, or to edit into a stricter contract.
+//
+// Example:
+//
+//	schema, err := InferSchema([][]byte{[]byte(`{"id": 1, "tags": ["a"]}`), []byte(`{"id": 2}`)}, FormatJSON)
+//	// schema requires an integer id and allows an array of strings as tags
+//
+// A sample that cannot be read returns a *SampleError naming it.
+func InferSchema(samples [][]byte, format Format) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("cannot infer a schema without samples")
+	}
+
+	root := &schemaNode{}
+	for i, sample := range samples {
+		values, err := decodeSample(sample, format)
+		if err != nil {
+			return nil, &SampleError{Index: i, Err: err}
+		}
+		for _, v := range values {
+			root.add(v)
+		}
+	}
+
+	schema := root.schema()
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, '\n'), nil
+}
+
+// SampleError reports a sample given to InferSchema that could not be read, such as one
+// that fails validation.
+type SampleError struct {
+	// Index is the position of the sample, 0 for the first
+	Index int
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *SampleError) Error() string {
+	return fmt.Sprintf("sample %d: %v", e.Index+1, e.Err)
+}
+
+// Unwrap returns the underlying error, such as a *ValidationError.
+func (e *SampleError) Unwrap() error {
+	return e.Err
+}
+
+// decodeSample validates a sample as format and returns its documents as values made of
+// map[string]any, []any, string, int64, float64, bool, and nil.
+func decodeSample(data []byte, format Format) ([]any, error) {
+	text, _, err := DecodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(text)
+	}
+	if !slices.Contains(inferFormats, format) {
+		return nil, fmt.Errorf("cannot infer a schema from %s: supported formats are json, yaml, and csv", format)
+	}
+	validator, err := NewValidator(format)
+	if err != nil {
+		return nil, err
+	}
+	if result := validator.Validate(text); !result.Valid {
+		return nil, result.Err
+	}
+
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+
+		return []any{v}, nil
+	case FormatYAML:
+		return decodeYAMLSamples(text)
+	}
+
+	return decodeCSVSample(text)
+}
+
+// decodeYAMLSamples returns every document of a YAML stream.
+func decodeYAMLSamples(text []byte) ([]any, error) {
+	var docs []any
+	dec := yaml.NewDecoder(bytes.NewReader(text))
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, v)
+	}
+}
+
+// decodeCSVSample returns a CSV document as an array of objects keyed by its header,
+// leaving out empty cells.
+func decodeCSVSample(text []byte) ([]any, error) {
+	r := csv.NewReader(bytes.NewReader(text))
+	r.Comma = CSVDialect{}.DelimiterFor(text)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []any{}
+	for _, record := range records[min(1, len(records)):] {
+		row := map[string]any{}
+		for i, cell := range record {
+			if cell != "" && i < len(records[0]) {
+				row[records[0][i]] = csvCellValue(cell)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return []any{rows}, nil
+}
+
+// csvCellValue returns a CSV cell as the value its text reads as.
+func csvCellValue(cell string) any {
+	switch {
+	case csvTypeChecks[CSVInteger](cell):
+		return json.Number(cell)
+	case csvTypeChecks[CSVNumber](cell):
+		return json.Number(cell)
+	case csvTypeChecks[CSVBoolean](cell):
+		return cell[0] == 't' || cell[0] == 'T'
+	}
+
+	return cell
+}
+
+// stringFormat is a JSON Schema string format, with the check each string must pass.
+type stringFormat struct {
+	name  string
+	check func(string) bool
+}
+
+// stringFormats are the string formats InferSchema recognizes, in order of preference.
+var stringFormats = []stringFormat{
+	{"date", csvTypeChecks[CSVDate]},
+	{"date-time", csvTypeChecks[CSVDateTime]},
+	{"email", csvTypeChecks[CSVEmail]},
+	{"uri", csvTypeChecks[CSVURL]},
+}
+
+// schemaNode accumulates the values seen at one place in the samples.
+type schemaNode struct {
+	types map[string]bool
+	// formats holds the string formats every string seen so far matches
+	formats []stringFormat
+	strings int
+	// objects counts the objects seen, and properties and present their members
+	objects    int
+	properties map[string]*schemaNode
+	present    map[string]int
+	items      *schemaNode
+}
+
+// add merges v into the node.
+func (n *schemaNode) add(v any) {
+	if n.types == nil {
+		n.types = map[string]bool{}
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		n.addObject(v)
+	case map[any]any:
+		object := make(map[string]any, len(v))
+		for key, value := range v {
+			object[fmt.Sprint(key)] = value
+		}
+		n.addObject(object)
+	case []any:
+		n.types["array"] = true
+		if n.items == nil {
+			n.items = &schemaNode{}
+		}
+		for _, item := range v {
+			n.items.add(item)
+		}
+	case string:
+		n.addString(v)
+	case time.Time:
+		// YAML reads unquoted dates and timestamps as times; a date alone is at midnight UTC
+		if v.Location() == time.UTC && v.Equal(v.Truncate(24*time.Hour)) {
+			n.addString(v.Format(time.DateOnly))
+		} else {
+			n.addString(v.Format(time.RFC3339Nano))
+		}
+	default:
+		n.types[scalarType(v)] = true
+	}
+}
+
+// scalarType returns the JSON Schema type of a number, boolean, or null.
+func scalarType(v any) string {
+	switch v := v.(type) {
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+
+		return "number"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+
+	return "null"
+}
+
+// addObject merges an object into the node.
+func (n *schemaNode) addObject(object map[string]any) {
+	n.types["object"] = true
+	if n.properties == nil {
+		n.properties, n.present = map[string]*schemaNode{}, map[string]int{}
+	}
+	n.objects++
+	for key, value := range object {
+		if n.properties[key] == nil {
+			n.properties[key] = &schemaNode{}
+		}
+		n.properties[key].add(value)
+		n.present[key]++
+	}
+}
+
+// addString merges a string into the node, keeping the formats it matches.
+func (n *schemaNode) addString(s string) {
+	n.types["string"] = true
+	if n.strings == 0 {
+		n.formats = slices.Clone(stringFormats)
+	}
+	n.strings++
+	n.formats = slices.DeleteFunc(n.formats, func(f stringFormat) bool {
+		return !f.check(s)
+	})
+}
+
+// jsonSchema is the subset of JSON Schema that InferSchema writes.
+type jsonSchema struct {
+	Schema     string                 `json:"$schema,omitempty"`
+	Type       any                    `json:"type,omitempty"`
+	Format     string                 `json:"format,omitempty"`
+	Properties map[string]*jsonSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Items      *jsonSchema            `json:"items,omitempty"`
+}
+
+// schema returns the JSON Schema of the values merged into the node. A node with no
+// values, such as the items of arrays that were always empty, allows anything.
+func (n *schemaNode) schema() *jsonSchema {
+	s := &jsonSchema{}
+	if n.types["integer"] && n.types["number"] {
+		delete(n.types, "integer")
+	}
+	var types []string
+	for t := range n.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	switch len(types) {
+	case 0:
+	case 1:
+		s.Type = types[0]
+	default:
+		s.Type = types
+	}
+
+	if len(n.formats) > 0 {
+		s.Format = n.formats[0].name
+	}
+	if n.properties != nil {
+		s.Properties = make(map[string]*jsonSchema, len(n.properties))
+		for key, child := range n.properties {
+			s.Properties[key] = child.schema()
+			if n.present[key] == n.objects {
+				s.Required = append(s.Required, key)
+			}
+		}
+		sort.Strings(s.Required)
+	}
+	if n.items != nil && n.items.types != nil {
+		s.Items = n.items.schema()
+	}
+
+	return s
+}
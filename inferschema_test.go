@@ -0,0 +1,179 @@
+package serdeval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []string
+		format  Format
+		want    string
+	}{
+		{
+			name: "json objects",
+			samples: []string{
+				`{"id": 1, "price": 9, "tags": ["a"], "owner": {"email": "ada@example.com"}, "note": null}`,
+				`{"id": 2, "price": 9.5, "tags": [], "owner": {"email": "bob@example.com", "since": "2024-01-31"}}`,
+			},
+			format: FormatJSON,
+			want: `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "id": {
+      "type": "integer"
+    },
+    "note": {
+      "type": "null"
+    },
+    "owner": {
+      "type": "object",
+      "properties": {
+        "email": {
+          "type": "string",
+          "format": "email"
+        },
+        "since": {
+          "type": "string",
+          "format": "date"
+        }
+      },
+      "required": [
+        "email"
+      ]
+    },
+    "price": {
+      "type": "number"
+    },
+    "tags": {
+      "type": "array",
+      "items": {
+        "type": "string"
+      }
+    }
+  },
+  "required": [
+    "id",
+    "owner",
+    "price",
+    "tags"
+  ]
+}
+`,
+		},
+		{
+			name:    "yaml stream with mixed types",
+			samples: []string{"at: 2024-01-31T09:30:00Z\nport: 80\n---\nat: 2024-02-01T10:00:00Z\nport: http\n"},
+			format:  FormatYAML,
+			want: `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "at": {
+      "type": "string",
+      "format": "date-time"
+    },
+    "port": {
+      "type": [
+        "integer",
+        "string"
+      ]
+    }
+  },
+  "required": [
+    "at",
+    "port"
+  ]
+}
+`,
+		},
+		{
+			name:    "csv rows",
+			samples: []string{"id;active;site\n1;true;https://example.com\n2;false;\n"},
+			format:  FormatAuto,
+			want: `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "array",
+  "items": {
+    "type": "object",
+    "properties": {
+      "active": {
+        "type": "boolean"
+      },
+      "id": {
+        "type": "integer"
+      },
+      "site": {
+        "type": "string",
+        "format": "uri"
+      }
+    },
+    "required": [
+      "active",
+      "id"
+    ]
+  }
+}
+`,
+		},
+		{
+			name:    "empty array",
+			samples: []string{`[]`},
+			format:  FormatJSON,
+			want:    "{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"type\": \"array\"\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([][]byte, len(tt.samples))
+			for i, s := range tt.samples {
+				samples[i] = []byte(s)
+			}
+			got, err := InferSchema(samples, tt.format)
+			if err != nil {
+				t.Fatalf("InferSchema() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("InferSchema() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []string
+		format  Format
+		wantErr string
+	}{
+		{"no samples", nil, FormatJSON, "without samples"},
+		{"invalid sample", []string{`{}`, `{"a":}`}, FormatJSON, "sample 2: "},
+		{"unsupported format", []string{"a = 1\n"}, FormatTOML, "cannot infer a schema from toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([][]byte, len(tt.samples))
+			for i, s := range tt.samples {
+				samples[i] = []byte(s)
+			}
+			_, err := InferSchema(samples, tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("InferSchema() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err := InferSchema([][]byte{[]byte(`{}`), []byte("{\n  \"a\": 1,\n}")}, FormatJSON)
+	var serr *SampleError
+	var verr *ValidationError
+	if !errors.As(err, &serr) || serr.Index != 1 || !errors.As(err, &verr) || verr.Line != 3 {
+		t.Errorf("InferSchema() error = %#v, want a *SampleError for sample 1 wrapping a *ValidationError", err)
+	}
+}
@@ -0,0 +1,865 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ionBVM is the binary version marker that starts every binary Ion 1.0 stream.
+var ionBVM = []byte{0xE0, 0x01, 0x00, 0xEA}
+
+// IonValidator validates Amazon Ion documents.
+// Text Ion is parsed fully (annotations, s-expressions, timestamps, blobs, clobs, long strings);
+// binary Ion is recognized by its version marker and its value framing is checked.
+//
+// Example:
+//
+//	validator := &IonValidator{baseValidator{format: FormatIon}}
+//	result := validator.ValidateString(`order::{ id: 42, placed: 2024-01-05T10:00Z, tags: [rush] }`)
+type IonValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid Ion text or binary stream.
+//
+// Example:
+//
+//	validator := &IonValidator{baseValidator{format: FormatIon}}
+//	result := validator.Validate([]byte(`{ name: "widget", price: 9.99d0 }`))
+func (v *IonValidator) Validate(data []byte) Result {
+	var err error
+	if bytes.HasPrefix(data, ionBVM) {
+		err = validateIonBinary(data)
+	} else {
+		p := &ionParser{data: data, line: 1}
+		err = p.parseStream()
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *IonValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// ionParser is a recursive descent parser for the Ion 1.0 text format.
+type ionParser struct {
+	data []byte
+	pos  int
+	line int
+}
+
+func (p *ionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *ionParser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *ionParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.data[p.pos]
+}
+
+func (p *ionParser) peekAt(offset int) byte {
+	if p.pos+offset >= len(p.data) {
+		return 0
+	}
+
+	return p.data[p.pos+offset]
+}
+
+func (p *ionParser) next() byte {
+	c := p.data[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+
+	return c
+}
+
+// skipSpace skips whitespace and comments.
+func (p *ionParser) skipSpace() error {
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f':
+			p.next()
+		case c == '/' && p.peekAt(1) == '/':
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		case c == '/' && p.peekAt(1) == '*':
+			start := p.line
+			p.pos += 2
+			for {
+				if p.eof() {
+					return fmt.Errorf("line %d: unterminated block comment", start)
+				}
+				if p.peek() == '*' && p.peekAt(1) == '/' {
+					p.pos += 2
+
+					break
+				}
+				p.next()
+			}
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// parseStream parses top-level values until the end of input.
+func (p *ionParser) parseStream() error {
+	for {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.eof() {
+			return nil
+		}
+		if err := p.parseValue(false); err != nil {
+			return err
+		}
+	}
+}
+
+// parseValue parses an optionally annotated value. inSexp allows operator symbols.
+func (p *ionParser) parseValue(inSexp bool) error {
+	for {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.eof() {
+			return p.errorf("unexpected end of input, expected a value")
+		}
+
+		annotated, err := p.tryAnnotation()
+		if err != nil {
+			return err
+		}
+		if !annotated {
+			break
+		}
+	}
+
+	c := p.peek()
+	switch {
+	case c == '{' && p.peekAt(1) == '{':
+		return p.parseLob()
+	case c == '{':
+		return p.parseStruct()
+	case c == '[':
+		return p.parseList()
+	case c == '(':
+		return p.parseSexp()
+	case c == '"':
+		return p.parseShortString()
+	case c == '\'' && p.peekAt(1) == '\'' && p.peekAt(2) == '\'':
+		return p.parseLongStrings()
+	case c == '\'':
+		return p.parseQuotedSymbol()
+	case c == '-' && p.peekAt(1) == 'i':
+		return p.parseKeyword()
+	case c == '+' && p.peekAt(1) == 'i':
+		return p.parseKeyword()
+	case c == '-' || (c >= '0' && c <= '9'):
+		if inSexp && c == '-' && !isIonDigit(p.peekAt(1)) {
+			return p.parseOperator()
+		}
+
+		return p.parseNumberOrTimestamp()
+	case isIonIdentStart(c):
+		return p.parseKeyword()
+	case inSexp && isIonOperator(c):
+		return p.parseOperator()
+	}
+
+	return p.errorf("unexpected character %q", c)
+}
+
+// tryAnnotation consumes "symbol::" if present and reports whether it did.
+func (p *ionParser) tryAnnotation() (bool, error) {
+	start, startLine := p.pos, p.line
+
+	var err error
+	switch c := p.peek(); {
+	case c == '\'' && !(p.peekAt(1) == '\'' && p.peekAt(2) == '\''):
+		err = p.parseQuotedSymbol()
+	case isIonIdentStart(c):
+		p.scanIdentifier()
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := p.skipSpace(); err != nil {
+		return false, err
+	}
+	if p.peek() == ':' && p.peekAt(1) == ':' {
+		p.pos += 2
+
+		return true, nil
+	}
+
+	// Not an annotation: rewind so the value is parsed normally
+	p.pos, p.line = start, startLine
+
+	return false, nil
+}
+
+func (p *ionParser) scanIdentifier() string {
+	start := p.pos
+	for !p.eof() && isIonIdentPart(p.peek()) {
+		p.next()
+	}
+
+	return string(p.data[start:p.pos])
+}
+
+// parseKeyword parses identifiers: symbols, null[.type], booleans, nan, and ±inf.
+func (p *ionParser) parseKeyword() error {
+	if p.peek() == '+' || p.peek() == '-' {
+		sign := p.next()
+		if word := p.scanIdentifier(); word != "inf" {
+			return p.errorf("invalid token %q", string(sign)+word)
+		}
+
+		return p.checkValueEnd()
+	}
+
+	word := p.scanIdentifier()
+	if word == "null" && p.peek() == '.' {
+		p.next()
+		switch t := p.scanIdentifier(); t {
+		case "null", "bool", "int", "float", "decimal", "timestamp", "string", "symbol",
+			"blob", "clob", "list", "sexp", "struct":
+		default:
+			return p.errorf("invalid null type %q", t)
+		}
+	}
+
+	return p.checkValueEnd()
+}
+
+func (p *ionParser) parseOperator() error {
+	for !p.eof() && isIonOperator(p.peek()) {
+		p.next()
+	}
+
+	return nil
+}
+
+// checkValueEnd ensures a scalar is followed by a delimiter rather than junk.
+func (p *ionParser) checkValueEnd() error {
+	if p.eof() {
+		return nil
+	}
+	c := p.peek()
+	if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f' ||
+		c == ',' || c == ']' || c == '}' || c == ')' || c == ':' || c == '(' || c == '[' || c == '{' ||
+		c == '/' || c == '"' || c == '\'' || isIonOperator(c) {
+		return nil
+	}
+
+	return p.errorf("unexpected character %q after value", c)
+}
+
+func (p *ionParser) parseQuotedSymbol() error {
+	p.next()
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return p.errorf("unterminated quoted symbol")
+		}
+		c := p.next()
+		if c == '\\' {
+			if err := p.parseEscape(); err != nil {
+				return err
+			}
+
+			continue
+		}
+		if c == '\'' {
+			return nil
+		}
+	}
+}
+
+func (p *ionParser) parseShortString() error {
+	p.next()
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return p.errorf("unterminated string")
+		}
+		c := p.next()
+		if c == '\\' {
+			if err := p.parseEscape(); err != nil {
+				return err
+			}
+
+			continue
+		}
+		if c == '"' {
+			return p.checkValueEnd()
+		}
+	}
+}
+
+// parseLongStrings parses one or more adjacent triple-quoted segments, which Ion concatenates.
+func (p *ionParser) parseLongStrings() error {
+	for {
+		if err := p.parseLongString(); err != nil {
+			return err
+		}
+		start, startLine := p.pos, p.line
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if !(p.peek() == '\'' && p.peekAt(1) == '\'' && p.peekAt(2) == '\'') {
+			p.pos, p.line = start, startLine
+
+			return nil
+		}
+	}
+}
+
+func (p *ionParser) parseLongString() error {
+	startLine := p.line
+	p.pos += 3
+	for {
+		if p.eof() {
+			return fmt.Errorf("line %d: unterminated long string", startLine)
+		}
+		if p.peek() == '\'' && p.peekAt(1) == '\'' && p.peekAt(2) == '\'' {
+			p.pos += 3
+
+			return nil
+		}
+		if p.next() == '\\' {
+			if err := p.parseEscape(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseEscape validates the escape sequence following a backslash.
+func (p *ionParser) parseEscape() error {
+	if p.eof() {
+		return p.errorf("unterminated escape sequence")
+	}
+	c := p.next()
+	switch c {
+	case 'a', 'b', 't', 'n', 'f', 'r', 'v', '?', '0', '/', '\'', '"', '\\', '\n':
+		return nil
+	case '\r':
+		if p.peek() == '\n' {
+			p.next()
+		}
+
+		return nil
+	case 'x':
+		return p.expectHex(2)
+	case 'u':
+		return p.expectHex(4)
+	case 'U':
+		return p.expectHex(8)
+	}
+
+	return p.errorf("invalid escape sequence \\%c", c)
+}
+
+func (p *ionParser) expectHex(n int) error {
+	for i := 0; i < n; i++ {
+		if p.eof() || !isHexDigit(p.peek()) {
+			return p.errorf("invalid hex escape")
+		}
+		p.next()
+	}
+
+	return nil
+}
+
+// parseNumberOrTimestamp scans a numeric token and classifies it.
+func (p *ionParser) parseNumberOrTimestamp() error {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if isIonDigit(c) || unicode.IsLetter(rune(c)) || c == '.' || c == '_' || c == ':' || c == '-' || c == '+' {
+			// A '::' ends the token (annotations cannot start with digits, but be strict)
+			if c == ':' && p.peekAt(1) == ':' {
+				break
+			}
+			p.next()
+
+			continue
+		}
+
+		break
+	}
+	token := string(p.data[start:p.pos])
+
+	if !isIonNumber(token) && !isIonTimestamp(token) {
+		return p.errorf("invalid numeric or timestamp value %q", token)
+	}
+
+	return p.checkValueEnd()
+}
+
+func (p *ionParser) parseLob() error {
+	startLine := p.line
+	p.pos += 2
+	if err := p.skipSpace(); err != nil {
+		return err
+	}
+
+	if p.peek() == '"' || p.peek() == '\'' {
+		// clob: a short string or long strings
+		var err error
+		if p.peek() == '"' {
+			err = p.parseShortString()
+		} else {
+			err = p.parseLongStrings()
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+	} else {
+		// blob: base64 with optional whitespace
+		var b64 strings.Builder
+		for !p.eof() && p.peek() != '}' {
+			c := p.next()
+			if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+				continue
+			}
+			b64.WriteByte(c)
+		}
+		if _, err := base64.StdEncoding.DecodeString(b64.String()); err != nil {
+			return fmt.Errorf("line %d: invalid base64 in blob: %v", startLine, err)
+		}
+	}
+
+	if p.peek() != '}' || p.peekAt(1) != '}' {
+		return fmt.Errorf("line %d: unterminated blob or clob, expected }}", startLine)
+	}
+	p.pos += 2
+
+	return nil
+}
+
+func (p *ionParser) parseList() error {
+	return p.parseSequence('[', ']', false)
+}
+
+func (p *ionParser) parseSexp() error {
+	return p.parseSequence('(', ')', true)
+}
+
+// parseSequence parses a list (comma separated) or s-expression (space separated).
+func (p *ionParser) parseSequence(open, closing byte, sexp bool) error {
+	startLine := p.line
+	p.next()
+	for {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.eof() {
+			return fmt.Errorf("line %d: unterminated %c", startLine, open)
+		}
+		if p.peek() == closing {
+			p.next()
+
+			return nil
+		}
+		if err := p.parseValue(sexp); err != nil {
+			return err
+		}
+		if sexp {
+			continue
+		}
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		switch p.peek() {
+		case ',':
+			p.next()
+		case closing:
+		default:
+			if p.eof() {
+				return fmt.Errorf("line %d: unterminated %c", startLine, open)
+			}
+
+			return p.errorf("expected ',' or '%c' in list", closing)
+		}
+	}
+}
+
+func (p *ionParser) parseStruct() error {
+	startLine := p.line
+	p.next()
+	for {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.eof() {
+			return fmt.Errorf("line %d: unterminated struct", startLine)
+		}
+		if p.peek() == '}' {
+			p.next()
+
+			return nil
+		}
+
+		if err := p.parseFieldName(); err != nil {
+			return err
+		}
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.peek() != ':' || p.peekAt(1) == ':' {
+			return p.errorf("expected ':' after struct field name")
+		}
+		p.next()
+
+		if err := p.parseValue(false); err != nil {
+			return err
+		}
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		switch p.peek() {
+		case ',':
+			p.next()
+		case '}':
+		default:
+			if p.eof() {
+				return fmt.Errorf("line %d: unterminated struct", startLine)
+			}
+
+			return p.errorf("expected ',' or '}' in struct")
+		}
+	}
+}
+
+func (p *ionParser) parseFieldName() error {
+	switch c := p.peek(); {
+	case c == '"':
+		p.next()
+		for {
+			if p.eof() || p.peek() == '\n' {
+				return p.errorf("unterminated field name")
+			}
+			ch := p.next()
+			if ch == '\\' {
+				if err := p.parseEscape(); err != nil {
+					return err
+				}
+
+				continue
+			}
+			if ch == '"' {
+				return nil
+			}
+		}
+	case c == '\'' && p.peekAt(1) == '\'' && p.peekAt(2) == '\'':
+		return p.parseLongStrings()
+	case c == '\'':
+		return p.parseQuotedSymbol()
+	case isIonIdentStart(c):
+		p.scanIdentifier()
+
+		return nil
+	}
+
+	return p.errorf("invalid struct field name")
+}
+
+func isIonDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isIonDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isIonIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIonIdentPart(c byte) bool {
+	return isIonIdentStart(c) || isIonDigit(c)
+}
+
+func isIonOperator(c byte) bool {
+	return strings.IndexByte("!#%&*+-./;<=>?@^`|~", c) >= 0
+}
+
+// isIonNumber reports whether token is an Ion int, decimal, or float.
+func isIonNumber(token string) bool {
+	s := strings.TrimPrefix(token, "-")
+	if s == "" {
+		return false
+	}
+
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, "0x") {
+		return isDigitsWithUnderscores(lower[2:], isHexDigit)
+	}
+	if strings.HasPrefix(lower, "0b") {
+		return isDigitsWithUnderscores(lower[2:], func(c byte) bool { return c == '0' || c == '1' })
+	}
+
+	// Split off exponent (e/E for float, d/D for decimal)
+	mantissa, exponent := lower, ""
+	if i := strings.IndexAny(lower, "ed"); i >= 0 {
+		mantissa, exponent = lower[:i], lower[i+1:]
+		exponent = strings.TrimPrefix(strings.TrimPrefix(exponent, "+"), "-")
+		if exponent == "" || !isDigitsWithUnderscores(exponent, isIonDigit) {
+			return false
+		}
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(mantissa, ".")
+	if !isDigitsWithUnderscores(intPart, isIonDigit) {
+		return false
+	}
+	// Ion forbids leading zeros on integers
+	if len(intPart) > 1 && intPart[0] == '0' {
+		return false
+	}
+	if hasDot && fracPart != "" && !isDigitsWithUnderscores(fracPart, isIonDigit) {
+		return false
+	}
+
+	return true
+}
+
+// isDigitsWithUnderscores checks s is non-empty digits with single underscores between them.
+func isDigitsWithUnderscores(s string, isDigit func(byte) bool) bool {
+	if s == "" || s[0] == '_' || s[len(s)-1] == '_' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '_' {
+			if s[i-1] == '_' {
+				return false
+			}
+
+			continue
+		}
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isIonTimestamp reports whether token is an Ion timestamp such as 2007T, 2007-02T,
+// 2007-02-23, or 2007-02-23T12:14:33.079-08:00.
+func isIonTimestamp(token string) bool {
+	digits := func(s string, n int) bool {
+		if len(s) != n {
+			return false
+		}
+		for i := 0; i < n; i++ {
+			if !isIonDigit(s[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if len(token) < 5 || !digits(token[:4], 4) {
+		return false
+	}
+	rest := token[4:]
+	if rest == "T" {
+		return true
+	}
+	if len(rest) < 3 || rest[0] != '-' || !digits(rest[1:3], 2) {
+		return false
+	}
+	rest = rest[3:]
+	if rest == "T" {
+		return true
+	}
+	if len(rest) < 3 || rest[0] != '-' || !digits(rest[1:3], 2) {
+		return false
+	}
+	rest = rest[3:]
+	if rest == "" || rest == "T" {
+		return true
+	}
+	if rest[0] != 'T' {
+		return false
+	}
+	rest = rest[1:]
+
+	// hh:mm[:ss[.fff]] followed by an offset
+	if len(rest) < 5 || !digits(rest[:2], 2) || rest[2] != ':' || !digits(rest[3:5], 2) {
+		return false
+	}
+	rest = rest[5:]
+	if len(rest) >= 3 && rest[0] == ':' {
+		if !digits(rest[1:3], 2) {
+			return false
+		}
+		rest = rest[3:]
+		if len(rest) > 0 && rest[0] == '.' {
+			i := 1
+			for i < len(rest) && isIonDigit(rest[i]) {
+				i++
+			}
+			if i == 1 {
+				return false
+			}
+			rest = rest[i:]
+		}
+	}
+
+	if rest == "Z" || rest == "z" {
+		return true
+	}
+
+	return len(rest) == 6 && (rest[0] == '+' || rest[0] == '-') &&
+		digits(rest[1:3], 2) && rest[3] == ':' && digits(rest[4:6], 2)
+}
+
+// validateIonBinary walks the value framing of a binary Ion 1.0 stream.
+func validateIonBinary(data []byte) error {
+	pos := 0
+	for pos < len(data) {
+		if bytes.HasPrefix(data[pos:], ionBVM) {
+			pos += len(ionBVM)
+
+			continue
+		}
+		next, err := ionBinaryValue(data, pos, len(data))
+		if err != nil {
+			return err
+		}
+		pos = next
+	}
+
+	return nil
+}
+
+// ionBinaryValue validates the value at pos (bounded by end) and returns the offset after it.
+func ionBinaryValue(data []byte, pos, end int) (int, error) {
+	if pos >= end {
+		return 0, fmt.Errorf("offset %d: unexpected end of binary value", pos)
+	}
+	td := data[pos]
+	typeCode, lengthCode := td>>4, td&0x0F
+	pos++
+
+	if typeCode == 15 {
+		return 0, fmt.Errorf("offset %d: reserved type code", pos-1)
+	}
+	if lengthCode == 15 {
+		// Typed null
+		return pos, nil
+	}
+	if typeCode == 1 {
+		if lengthCode > 1 {
+			return 0, fmt.Errorf("offset %d: invalid bool representation", pos-1)
+		}
+
+		return pos, nil
+	}
+
+	length := int(lengthCode)
+	// Sorted structs use L=1 to flag a VarUInt length
+	if lengthCode == 14 || (typeCode == 13 && lengthCode == 1) {
+		n, next, err := readVarUInt(data, pos, end)
+		if err != nil {
+			return 0, err
+		}
+		length, pos = n, next
+	}
+	if typeCode == 4 && length != 0 && length != 4 && length != 8 {
+		return 0, fmt.Errorf("offset %d: invalid float length %d", pos, length)
+	}
+	if pos+length > end {
+		return 0, fmt.Errorf("offset %d: value length %d exceeds container", pos, length)
+	}
+	valueEnd := pos + length
+
+	switch typeCode {
+	case 11, 12:
+		for p := pos; p < valueEnd; {
+			next, err := ionBinaryValue(data, p, valueEnd)
+			if err != nil {
+				return 0, err
+			}
+			p = next
+		}
+	case 13:
+		for p := pos; p < valueEnd; {
+			_, next, err := readVarUInt(data, p, valueEnd)
+			if err != nil {
+				return 0, err
+			}
+			if p, err = ionBinaryValue(data, next, valueEnd); err != nil {
+				return 0, err
+			}
+		}
+	case 14:
+		annotLength, next, err := readVarUInt(data, pos, valueEnd)
+		if err != nil {
+			return 0, err
+		}
+		if annotLength == 0 || next+annotLength >= valueEnd {
+			return 0, fmt.Errorf("offset %d: invalid annotation wrapper", pos)
+		}
+		inner, err := ionBinaryValue(data, next+annotLength, valueEnd)
+		if err != nil {
+			return 0, err
+		}
+		if inner != valueEnd {
+			return 0, fmt.Errorf("offset %d: annotation wrapper must contain exactly one value", pos)
+		}
+	}
+
+	return valueEnd, nil
+}
+
+// readVarUInt decodes an Ion VarUInt (7 bits per byte, high bit marks the last byte).
+func readVarUInt(data []byte, pos, end int) (int, int, error) {
+	value := 0
+	for i := 0; pos < end; i++ {
+		if i >= 8 {
+			return 0, 0, fmt.Errorf("offset %d: VarUInt too long", pos)
+		}
+		b := data[pos]
+		pos++
+		value = value<<7 | int(b&0x7F)
+		if b&0x80 != 0 {
+			return value, pos, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("offset %d: truncated VarUInt", pos)
+}
@@ -0,0 +1,81 @@
+package serdeval
+
+import "testing"
+
+func TestIonValidator(t *testing.T) {
+	v := &IonValidator{baseValidator{format: FormatIon}}
+
+	tests := []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{"struct", `{ name: "widget", price: 9.99d0, qty: 1_000 }`, true},
+		{"annotations", `order::shipping::{ id: 42 }`, true},
+		{"list and sexp", `[1, 2.5e0, null.int, (+ 1 2)]`, true},
+		{"timestamps", `[2007T, 2007-02T, 2007-02-23, 2007-02-23T12:14:33.079-08:00, 2024-01-05T10:00Z]`, true},
+		{"blob and clob", `{{ aGVsbG8= }} {{ "raw bytes" }}`, true},
+		{"long strings", "'''first ''' '''second'''", true},
+		{"symbols", `[foo, 'quoted symbol', $10, nan, +inf, -inf]`, true},
+		{"comments", "// line\n/* block */ { a: 1 }", true},
+		{"hex and binary ints", `[0x1F, -0b1010]`, true},
+		{"multiple top-level values", `1 2 "three"`, true},
+		{"empty", ``, true},
+		{"unterminated struct", `{ a: 1`, false},
+		{"missing colon", `{ a 1 }`, false},
+		{"leading zero", `[007]`, false},
+		{"bad timestamp", `2007-13`, false},
+		{"bad escape", `"bad \q"`, false},
+		{"bad blob", `{{ not*base64 }}`, false},
+		{"list missing comma", `[1 2]`, false},
+		{"invalid null type", `null.widget`, false},
+		{"unterminated comment", `/* open`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if result.Format != FormatIon {
+				t.Errorf("Format = %v, want %v", result.Format, FormatIon)
+			}
+		})
+	}
+}
+
+func TestIonValidatorBinary(t *testing.T) {
+	v := &IonValidator{baseValidator{format: FormatIon}}
+
+	tests := []struct {
+		name  string
+		input []byte
+		valid bool
+	}{
+		{"version marker only", []byte{0xE0, 0x01, 0x00, 0xEA}, true},
+		{"int and bool", []byte{0xE0, 0x01, 0x00, 0xEA, 0x21, 0x2A, 0x11}, true},
+		{"list of ints", []byte{0xE0, 0x01, 0x00, 0xEA, 0xB4, 0x21, 0x01, 0x21, 0x02}, true},
+		{"struct", []byte{0xE0, 0x01, 0x00, 0xEA, 0xD2, 0x84, 0x10}, true},
+		{"annotated int", []byte{0xE0, 0x01, 0x00, 0xEA, 0xE4, 0x81, 0x84, 0x21, 0x07}, true},
+		{"truncated int", []byte{0xE0, 0x01, 0x00, 0xEA, 0x22, 0x01}, false},
+		{"bad float length", []byte{0xE0, 0x01, 0x00, 0xEA, 0x42, 0x00, 0x00}, false},
+		{"reserved type", []byte{0xE0, 0x01, 0x00, 0xEA, 0xF0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("Validate() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte{0xE0, 0x01, 0x00, 0xEA, 0x20}); got != FormatIon {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatIon)
+	}
+	if got := DetectFormatFromFilename("events.10n"); got != FormatIon {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatIon)
+	}
+}
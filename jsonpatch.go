@@ -0,0 +1,204 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JSONPatchValidator validates JSON Patch documents (RFC 6902), the
+// application/json-patch+json payloads that kubectl patch --type json and kustomize
+// apply. Beyond JSON syntax it checks that the document is an array of operations, each
+// with a known op, a JSON Pointer path, and the value or from member its op needs.
+//
+// Example:
+//
+//	validator := &JSONPatchValidator{baseValidator{format: FormatJSONPatch}}
+//	result := validator.ValidateString(`[{"op": "replace", "path": "/spec/replicas", "value": 3}]`)
+type JSONPatchValidator struct {
+	baseValidator
+}
+
+// jsonPatchMember is the member each op needs besides op and path.
+var jsonPatchMember = map[string]string{
+	"add":     "value",
+	"remove":  "",
+	"replace": "value",
+	"move":    "from",
+	"copy":    "from",
+	"test":    "value",
+}
+
+// Validate checks if the provided byte slice contains a valid JSON Patch document.
+// Errors name the operation by index, e.g. "[2].path: invalid JSON Pointer "spec": must
+// be empty or start with "/"", and are located at the start of that operation.
+func (v *JSONPatchValidator) Validate(data []byte) Result {
+	if err := json.Unmarshal(data, new(interface{})); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid JSON: " + err.Error(),
+			Suggestion: suggestFix(FormatJSON, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	err := validateJSONPatch(data)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *JSONPatchValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// validateJSONPatch checks each operation of a syntactically valid JSON Patch.
+func validateJSONPatch(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, _ := dec.Token(); tok != json.Delim('[') {
+		return errors.New("a JSON Patch must be an array of operations")
+	}
+
+	for i := 0; dec.More(); i++ {
+		start := int(dec.InputOffset())
+		var op map[string]json.RawMessage
+		if err := dec.Decode(&op); err != nil {
+			return &offsetError{offset: nextJSONValue(data, start), msg: fmt.Sprintf("[%d]: operation must be an object", i)}
+		}
+		if err := checkJSONPatchOperation(op); err != nil {
+			return &offsetError{offset: nextJSONValue(data, start), msg: fmt.Sprintf("[%d]%v", i, err)}
+		}
+	}
+
+	return nil
+}
+
+// nextJSONValue returns the offset of the value that follows offset in data, past
+// whitespace and a separating comma.
+func nextJSONValue(data []byte, offset int) int {
+	for offset < len(data) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+		offset++
+	}
+
+	return offset
+}
+
+// checkJSONPatchOperation checks one operation; errors start with the member they are
+// about, such as ".path: ...", or with ": " for the operation as a whole.
+func checkJSONPatchOperation(op map[string]json.RawMessage) error {
+	var name string
+	if raw, ok := op["op"]; !ok {
+		return errors.New(": missing required field: op")
+	} else if json.Unmarshal(raw, &name) != nil {
+		return errors.New(".op: must be a string")
+	}
+	member, known := jsonPatchMember[name]
+	if !known {
+		return fmt.Errorf(".op: unknown operation %q (expected add, remove, replace, move, copy, or test)", name)
+	}
+
+	path, err := jsonPatchPointer(op, "path")
+	if err != nil {
+		return err
+	}
+	if member == "value" {
+		if _, ok := op["value"]; !ok {
+			return fmt.Errorf(": missing required field for %s: value", name)
+		}
+	}
+	if member != "from" {
+		return nil
+	}
+
+	from, err := jsonPatchPointer(op, "from")
+	if err != nil {
+		return err
+	}
+	if name == "move" && strings.HasPrefix(path, from+"/") {
+		return fmt.Errorf(": cannot move %s into its own child %s", displayPath(from), path)
+	}
+
+	return nil
+}
+
+// jsonPatchPointer returns the JSON Pointer in the member name of op.
+func jsonPatchPointer(op map[string]json.RawMessage, name string) (string, error) {
+	raw, ok := op[name]
+	if !ok {
+		return "", fmt.Errorf(": missing required field: %s", name)
+	}
+	var pointer string
+	if json.Unmarshal(raw, &pointer) != nil {
+		return "", fmt.Errorf(".%s: must be a string", name)
+	}
+	if err := checkJSONPointer(pointer); err != nil {
+		return "", fmt.Errorf(".%s: %w", name, err)
+	}
+
+	return pointer, nil
+}
+
+// checkJSONPointer reports whether pointer is a valid JSON Pointer (RFC 6901).
+func checkJSONPointer(pointer string) error {
+	if pointer != "" && pointer[0] != '/' {
+		return fmt.Errorf("invalid JSON Pointer %q: must be empty or start with \"/\"", pointer)
+	}
+	for i := 0; i < len(pointer); i++ {
+		if pointer[i] == '~' && (i+1 == len(pointer) || (pointer[i+1] != '0' && pointer[i+1] != '1')) {
+			return fmt.Errorf("invalid JSON Pointer %q: \"~\" must be followed by 0 or 1", pointer)
+		}
+	}
+
+	return nil
+}
+
+// MergePatchValidator validates JSON Merge Patch documents (RFC 7386), the
+// application/merge-patch+json payloads of kubectl patch --type merge. A merge patch
+// must be a JSON object: any other value replaces the target whole rather than patching
+// it, which is rarely what a patch file means to do.
+//
+// Example:
+//
+//	validator := &MergePatchValidator{baseValidator{format: FormatMergePatch}}
+//	result := validator.ValidateString(`{"spec": {"replicas": 3, "paused": null}}`)
+type MergePatchValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a JSON Merge Patch object.
+func (v *MergePatchValidator) Validate(data []byte) Result {
+	var patch interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid JSON: " + err.Error(),
+			Suggestion: suggestFix(FormatJSON, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	var err error
+	if _, ok := patch.(map[string]interface{}); !ok {
+		err = &offsetError{
+			offset: nextJSONValue(data, 0),
+			msg:    "a JSON Merge Patch must be an object; any other value replaces the whole document",
+		}
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *MergePatchValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
@@ -0,0 +1,89 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONPatchValidator(t *testing.T) {
+	v := &JSONPatchValidator{baseValidator{format: FormatJSONPatch}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"empty", `[]`, true, ""},
+		{
+			"every op",
+			`[{"op": "add", "path": "/a/-", "value": 1}, {"op": "remove", "path": "/b"},
+			  {"op": "replace", "path": "", "value": null}, {"op": "move", "from": "/c", "path": "/d"},
+			  {"op": "copy", "from": "/a~1b", "path": "/e"}, {"op": "test", "path": "/f~0", "value": "x"}]`,
+			true, "",
+		},
+		{"invalid json", `[{"op": }]`, false, "invalid JSON"},
+		{"not an array", `{"op": "add"}`, false, "must be an array of operations"},
+		{"operation not object", `[1]`, false, "[0]: operation must be an object"},
+		{"missing op", `[{"path": "/a"}]`, false, "[0]: missing required field: op"},
+		{
+			"unknown op", `[{"op": "remove", "path": "/a"}, {"op": "ad", "path": "/a"}]`,
+			false, `[1].op: unknown operation "ad"`,
+		},
+		{"missing path", `[{"op": "remove"}]`, false, "missing required field: path"},
+		{"relative path", `[{"op": "remove", "path": "a"}]`, false, `[0].path: invalid JSON Pointer "a"`},
+		{"bad escape", `[{"op": "remove", "path": "/a~2"}]`, false, `"~" must be followed by 0 or 1`},
+		{"missing value", `[{"op": "add", "path": "/a"}]`, false, "missing required field for add: value"},
+		{"missing from", `[{"op": "copy", "path": "/a"}]`, false, "missing required field: from"},
+		{
+			"move into child", `[{"op": "move", "from": "/a", "path": "/a/b"}]`,
+			false, "cannot move /a into its own child /a/b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	result := v.ValidateString("[\n  {\"op\": \"remove\", \"path\": \"/a\"},\n  {\"op\": \"add\", \"path\": \"/b\"}\n]")
+	if result.Err == nil || result.Err.Line != 3 || result.Err.Column != 3 {
+		t.Errorf("Err = %+v, want the second operation at 3:3", result.Err)
+	}
+}
+
+func TestMergePatchValidator(t *testing.T) {
+	v := &MergePatchValidator{baseValidator{format: FormatMergePatch}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"object", `{"spec": {"replicas": 3, "paused": null}}`, true, ""},
+		{"empty object", `{}`, true, ""},
+		{"invalid json", `{"a": }`, false, "invalid JSON"},
+		{"array", `[1]`, false, "must be an object"},
+		{"null", `null`, false, "replaces the whole document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options holds the options of a rule by name.
+type Options map[string]any
+
+// Int returns the integer option name, or def when it is not set to an integer.
+func (o Options) Int(name string, def int) int {
+	if v, ok := o[name].(int); ok {
+		return v
+	}
+
+	return def
+}
+
+// Bool returns the boolean option name, or def when it is not set to a boolean.
+func (o Options) Bool(name string, def bool) bool {
+	if v, ok := o[name].(bool); ok {
+		return v
+	}
+
+	return def
+}
+
+// String returns the string option name, or def when it is not set to a string.
+func (o Options) String(name, def string) string {
+	if v, ok := o[name].(string); ok {
+		return v
+	}
+
+	return def
+}
+
+// Config holds the settings of a rules file.
+type Config struct {
+	// Rules holds the settings of rules by ID; other rules keep their defaults
+	Rules map[string]RuleConfig `yaml:"rules"`
+}
+
+// RuleConfig is the setting of one rule in a rules file.
+type RuleConfig struct {
+	// Severity replaces the rule's default severity when set
+	Severity Severity
+	// Options replaces the defaults of the options it sets
+	Options Options
+}
+
+// UnmarshalYAML reads a rule setting written as a severity alone, such as "off", or as a
+// mapping of "severity" and the rule's options.
+func (c *RuleConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&c.Severity)
+	}
+
+	var settings map[string]any
+	if err := node.Decode(&settings); err != nil {
+		return err
+	}
+	if severity, ok := settings["severity"]; ok {
+		s, isString := severity.(string)
+		if !isString {
+			return fmt.Errorf("line %d: severity must be a string", node.Line)
+		}
+		c.Severity = Severity(s)
+		delete(settings, "severity")
+	}
+	c.Options = settings
+
+	return nil
+}
+
+// ParseConfig reads a YAML or JSON rules file, such as
+//
+//	rules:
+//	  json-indent:
+//	    severity: error
+//	    indent: 4
+//	  json-key-case: warning
+//	  dockerfile-maintainer: off
+//
+// Unknown rules, unknown options, and options of the wrong type are errors, so typos do
+// not go unnoticed.
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	for id, setting := range config.Rules {
+		rule, ok := lookup(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q", id)
+		}
+		if setting.Severity != "" && !setting.Severity.valid() {
+			return nil, fmt.Errorf("rule %s: unknown severity %q (expected error, warning, info, or off)",
+				id, setting.Severity)
+		}
+		for name, value := range setting.Options {
+			def, known := rule.Options[name]
+			if !known {
+				return nil, fmt.Errorf("rule %s: unknown option %q", id, name)
+			}
+			if reflect.TypeOf(value) != reflect.TypeOf(def) {
+				return nil, fmt.Errorf("rule %s: option %s must be a %T", id, name, def)
+			}
+		}
+	}
+
+	return &config, nil
+}
+
+// settings returns the severity and options rule runs with under c, which may be nil.
+func (c *Config) settings(rule Rule) (Severity, Options) {
+	severity, options := rule.Severity, rule.Options
+	if c == nil {
+		return severity, options
+	}
+	setting, ok := c.Rules[rule.ID]
+	if !ok {
+		return severity, options
+	}
+
+	if setting.Severity != "" {
+		severity = setting.Severity
+	} else if severity == SeverityOff {
+		// Setting only the options of a rule that is off by default enables it
+		severity = SeverityWarning
+	}
+	if len(setting.Options) > 0 {
+		options = maps.Clone(options)
+		if options == nil {
+			options = Options{}
+		}
+		maps.Copy(options, setting.Options)
+	}
+
+	return severity, options
+}
@@ -0,0 +1,51 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(
+		`{"rules": {"json-indent": {"severity": "error", "indent": 4}, "json-key-case": "info"}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	indent, _ := lookup("json-indent")
+	severity, options := config.settings(indent)
+	if severity != SeverityError || options.Int("indent", 0) != 4 {
+		t.Errorf("settings(json-indent) = %s, %v, want error with indent 4", severity, options)
+	}
+	if indent.Options.Int("indent", 0) != 2 {
+		t.Errorf("the defaults of json-indent changed to %v", indent.Options)
+	}
+	keyCase, _ := lookup("json-key-case")
+	if severity, options = config.settings(keyCase); severity != SeverityInfo || options.String("style", "") != "camel" {
+		t.Errorf("settings(json-key-case) = %s, %v, want info with style camel", severity, options)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"unknown rule", "rules:\n  json-indnet: off\n", `unknown rule "json-indnet"`},
+		{"unknown severity", "rules:\n  json-indent: fatal\n", `unknown severity "fatal"`},
+		{"unknown option", "rules:\n  json-indent: {size: 4}\n", `unknown option "size"`},
+		{"option type", "rules:\n  json-indent: {indent: four}\n", "option indent must be a int"},
+		{"severity type", "rules:\n  json-indent: {severity: [error]}\n", "severity must be a string"},
+		{"not yaml", "rules: [\n", "yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseConfig() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func init() {
+	dockerfileRules := []struct {
+		id, description string
+		check           func(instructions []dockerInstruction, data []byte) []serdeval.Diagnostic
+	}{
+		{"dockerfile-maintainer", "The deprecated MAINTAINER instruction is replaced by a maintainer label.",
+			checkDockerMaintainer},
+		{"dockerfile-image-tag", "Base images are pinned to a tag or digest (hadolint DL3006).", checkDockerImageTag},
+		{"dockerfile-latest-tag", "Base images do not use the latest tag (hadolint DL3007).", checkDockerLatestTag},
+		{"dockerfile-apt-layers", "Consecutive RUN instructions using apt-get are combined into one layer.",
+			checkDockerAptLayers},
+		{"dockerfile-user", "The final stage switches to a user other than root (hadolint DL3002).", checkDockerUser},
+		{"dockerfile-add", "COPY is used instead of ADD for local files and directories (hadolint DL3020).",
+			checkDockerAdd},
+	}
+	for _, r := range dockerfileRules {
+		check := r.check
+		Register(Rule{
+			ID:          r.id,
+			Format:      serdeval.FormatDockerfile,
+			Description: r.description,
+			Severity:    SeverityWarning,
+			Check: func(data []byte, _ Options) []serdeval.Diagnostic {
+				return check(parseDockerfile(data), data)
+			},
+		})
+	}
+}
+
+// dockerInstruction is one instruction of a Dockerfile, with its continuation lines joined.
+type dockerInstruction struct {
+	// keyword is the instruction in upper case, such as "RUN"
+	keyword string
+	// args is the text after the keyword
+	args string
+	// offset is the byte offset of the keyword
+	offset int
+}
+
+// escapeDirective matches the parser directive that changes the escape character.
+var escapeDirective = regexp.MustCompile("(?i)^#\\s*escape\\s*=\\s*([\\\\`])\\s*$")
+
+// parseDockerfile returns the instructions of a Dockerfile. Comments are skipped, and lines
+// ending in the escape character continue on the next line.
+func parseDockerfile(data []byte) []dockerInstruction {
+	escape := "\\"
+	var instructions []dockerInstruction
+	var current *dockerInstruction
+	directives := true
+	offset := 0
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		start := offset
+		offset += len(line)
+		text := strings.TrimSpace(line)
+
+		if directives {
+			if m := escapeDirective.FindStringSubmatch(text); m != nil {
+				escape = m[1]
+
+				continue
+			}
+			directives = strings.HasPrefix(text, "#") && strings.Contains(text, "=")
+		}
+		if current == nil && (text == "" || strings.HasPrefix(text, "#")) {
+			continue
+		}
+		if current != nil && strings.HasPrefix(text, "#") {
+			// Comment lines inside a continued instruction are dropped
+			continue
+		}
+
+		continued := strings.HasSuffix(text, escape)
+		text = strings.TrimSuffix(text, escape)
+		if current == nil {
+			keyword, args, _ := strings.Cut(text, " ")
+			instructions = append(instructions, dockerInstruction{
+				keyword: strings.ToUpper(keyword),
+				args:    strings.TrimSpace(args),
+				offset:  start + strings.Index(line, keyword),
+			})
+			current = &instructions[len(instructions)-1]
+		} else {
+			current.args = strings.TrimSpace(current.args + " " + text)
+		}
+		if !continued {
+			current = nil
+		}
+	}
+
+	return instructions
+}
+
+// words returns the arguments of an instruction in exec form, a JSON array, or split on
+// whitespace otherwise.
+func (in dockerInstruction) words() []string {
+	var exec []string
+	if strings.HasPrefix(in.args, "[") && json.Unmarshal([]byte(in.args), &exec) == nil {
+		return exec
+	}
+
+	return strings.Fields(in.args)
+}
+
+// fromImage returns the image a FROM instruction builds on, without flags such as
+// --platform, and the name it gives the stage, if any.
+func (in dockerInstruction) fromImage() (image, stage string) {
+	var rest []string
+	for _, w := range in.words() {
+		if !strings.HasPrefix(w, "--") {
+			rest = append(rest, w)
+		}
+	}
+	if len(rest) == 0 {
+		return "", ""
+	}
+	if len(rest) == 3 && strings.EqualFold(rest[1], "AS") {
+		stage = strings.ToLower(rest[2])
+	}
+
+	return rest[0], stage
+}
+
+// imageTag returns the tag of an image reference, such as "3.19" in "alpine:3.19", and
+// whether the reference is pinned by digest.
+func imageTag(image string) (tag string, digest bool) {
+	if strings.Contains(image, "@") {
+		return "", true
+	}
+	// A colon before the last slash belongs to a registry port, as in localhost:5000/app
+	if i := strings.LastIndexByte(image, ':'); i > strings.LastIndexByte(image, '/') {
+		return image[i+1:], false
+	}
+
+	return "", false
+}
+
+// baseImages calls fn with each FROM instruction that builds on an external image rather
+// than on scratch, an earlier stage, or an image named by a build argument.
+func baseImages(instructions []dockerInstruction, fn func(in dockerInstruction, image string)) {
+	stages := map[string]bool{}
+	for _, in := range instructions {
+		if in.keyword != "FROM" {
+			continue
+		}
+		image, stage := in.fromImage()
+		if image != "" && image != "scratch" && !stages[strings.ToLower(image)] && !strings.Contains(image, "$") {
+			fn(in, image)
+		}
+		if stage != "" {
+			stages[stage] = true
+		}
+	}
+}
+
+// checkDockerMaintainer reports MAINTAINER instructions.
+func checkDockerMaintainer(instructions []dockerInstruction, data []byte) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	for _, in := range instructions {
+		if in.keyword == "MAINTAINER" {
+			diags = append(diags, At(data, in.offset, "MAINTAINER is deprecated; use LABEL maintainer=\"name <email>\""))
+		}
+	}
+
+	return diags
+}
+
+// checkDockerImageTag reports base images without a tag or digest.
+func checkDockerImageTag(instructions []dockerInstruction, data []byte) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	baseImages(instructions, func(in dockerInstruction, image string) {
+		if tag, digest := imageTag(image); tag == "" && !digest {
+			diags = append(diags, At(data, in.offset, fmt.Sprintf("pin %s to a tag or digest", image)))
+		}
+	})
+
+	return diags
+}
+
+// checkDockerLatestTag reports base images tagged latest.
+func checkDockerLatestTag(instructions []dockerInstruction, data []byte) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	baseImages(instructions, func(in dockerInstruction, image string) {
+		if tag, _ := imageTag(image); tag == "latest" {
+			diags = append(diags, At(data, in.offset,
+				fmt.Sprintf("%s changes whenever a new version is pushed; pin a specific tag", image)))
+		}
+	})
+
+	return diags
+}
+
+// checkDockerAptLayers reports RUN instructions using apt-get right after another.
+func checkDockerAptLayers(instructions []dockerInstruction, data []byte) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	for i := 1; i < len(instructions); i++ {
+		prev, in := instructions[i-1], instructions[i]
+		if in.keyword == "RUN" && prev.keyword == "RUN" && usesAptGet(in) && usesAptGet(prev) {
+			diags = append(diags, At(data, in.offset,
+				"combine with the previous RUN apt-get into one layer, so apt-get update is never cached apart "+
+					"from apt-get install"))
+		}
+	}
+
+	return diags
+}
+
+// usesAptGet reports whether a RUN instruction runs apt-get.
+func usesAptGet(in dockerInstruction) bool {
+	for _, w := range strings.FieldsFunc(in.args, func(r rune) bool { return strings.ContainsRune(" \t;&|()[]\",", r) }) {
+		if w == "apt-get" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkDockerUser reports a final stage that runs as root, because it has no USER
+// instruction or its last one names root.
+func checkDockerUser(instructions []dockerInstruction, data []byte) []serdeval.Diagnostic {
+	var last, user *dockerInstruction
+	for i := range instructions {
+		switch instructions[i].keyword {
+		case "FROM":
+			last, user = &instructions[i], nil
+		case "USER":
+			user = &instructions[i]
+		}
+	}
+
+	switch {
+	case last == nil:
+		return nil
+	case user == nil:
+		return []serdeval.Diagnostic{At(data, last.offset,
+			"the final stage has no USER instruction, so the container runs as root")}
+	}
+	name, _, _ := strings.Cut(user.args, ":")
+	if name == "root" || name == "0" {
+		return []serdeval.Diagnostic{At(data, user.offset, "the last USER is root; switch to an unprivileged user")}
+	}
+
+	return nil
+}
+
+// checkDockerAdd reports ADD instructions that copy local files, which COPY does without
+// ADD's implicit extraction and downloads.
+func checkDockerAdd(instructions []dockerInstruction, data []byte) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	for _, in := range instructions {
+		if in.keyword != "ADD" {
+			continue
+		}
+		var args []string
+		for _, w := range in.words() {
+			if !strings.HasPrefix(w, "--") {
+				args = append(args, w)
+			}
+		}
+		if len(args) < 2 {
+			continue
+		}
+		for _, src := range args[:len(args)-1] {
+			if !isRemoteSource(src) && !isArchive(src) {
+				diags = append(diags, At(data, in.offset, fmt.Sprintf("use COPY instead of ADD for %s", src)))
+
+				break
+			}
+		}
+	}
+
+	return diags
+}
+
+// isRemoteSource reports whether an ADD source is a URL or Git repository.
+func isRemoteSource(src string) bool {
+	for _, prefix := range []string{"http://", "https://", "git@", "git://"} {
+		if strings.HasPrefix(src, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isArchive reports whether an ADD source is a local archive, which ADD extracts.
+func isArchive(src string) bool {
+	name := strings.ToLower(path.Base(src))
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz", ".tar.zst"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+
+	return false
+}
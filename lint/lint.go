@@ -0,0 +1,174 @@
+// Package lint checks the style of valid documents with configurable rules.
+//
+// Validation says a file parses; linting says it is written well. Each rule checks one
+// format for one thing, such as indentation, key casing, or a deprecated construct, and
+// reports problems under its ID with a severity:
+//
+//	findings, err := lint.Lint(data, serdeval.FormatJSON, nil)
+//	for _, f := range findings {
+//		fmt.Printf("%d:%d: %s %s: %s\n", f.Line, f.Column, f.Severity, f.Rule, f.Message)
+//	}
+//
+// A rules file, read with ParseConfig, changes the severity of rules, turns them off, and
+// sets their options. Formats add rules with Register.
+package lint
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+// Severity is how much a rule's findings matter.
+type Severity string
+
+// Severities of rules, from the most to the least severe. SeverityOff disables a rule.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off"
+)
+
+// valid reports whether s is one of the defined severities.
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return true
+	}
+
+	return false
+}
+
+// Rule is one style check of a format.
+type Rule struct {
+	// ID names the rule in findings and rules files, such as "json-indent"
+	ID string
+	// Format is the format of the documents the rule checks
+	Format serdeval.Format
+	// Description says what the rule checks, in one sentence
+	Description string
+	// Severity is the severity of the rule's findings unless a rules file sets another;
+	// SeverityOff leaves the rule disabled until a rules file enables it
+	Severity Severity
+	// Options holds the rule's options and their defaults. A rules file can set only
+	// these options, to values of the same type.
+	Options Options
+	// Check returns the problems in data, a valid document of Format, given the rule's
+	// options. Diagnostics without a position apply to the whole document.
+	Check func(data []byte, options Options) []serdeval.Diagnostic
+}
+
+// Finding is a problem a rule reported.
+type Finding struct {
+	// Rule is the ID of the rule
+	Rule string `json:"rule"`
+	// Severity is the rule's configured severity
+	Severity Severity `json:"severity"`
+	serdeval.Diagnostic
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]Rule{}
+)
+
+// Register adds rule, replacing a rule registered before with the same ID. It is safe for
+// concurrent use, though it is usually called from an init function. It panics if the ID
+// or format is empty, the severity is not one of the defined severities, or Check is nil.
+//
+// Example:
+//
+//	lint.Register(lint.Rule{
+//		ID:          "ini-no-empty-sections",
+//		Format:      serdeval.FormatINI,
+//		Description: "Sections have at least one key.",
+//		Severity:    lint.SeverityWarning,
+//		Check:       checkEmptySections,
+//	})
+func Register(rule Rule) {
+	if rule.ID == "" || rule.Format == "" || !rule.Severity.valid() || rule.Check == nil {
+		panic(fmt.Sprintf("lint: Register called with an incomplete rule %q", rule.ID))
+	}
+
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[rule.ID] = rule
+}
+
+// Rules returns the registered rules sorted by ID, or only those of format when it is
+// not empty.
+func Rules(format serdeval.Format) []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	var list []Rule
+	for _, rule := range rules {
+		if format == "" || rule.Format == format {
+			list = append(list, rule)
+		}
+	}
+	slices.SortFunc(list, func(a, b Rule) int { return cmp.Compare(a.ID, b.ID) })
+
+	return list
+}
+
+// lookup returns the rule registered under id.
+func lookup(id string) (Rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	rule, ok := rules[id]
+
+	return rule, ok
+}
+
+// Lint validates data as format and returns what the rules of format find in it, ordered
+// by position, with the severities and options of config; a nil config uses every rule's
+// defaults. An empty format or serdeval.FormatAuto is detected from the content. A
+// document that fails validation returns its *serdeval.ValidationError and is not linted.
+func Lint(data []byte, format serdeval.Format, config *Config) ([]Finding, error) {
+	text, _, err := serdeval.DecodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" || format == serdeval.FormatAuto {
+		format = serdeval.DetectFormat(text)
+	}
+	validator, err := serdeval.NewValidator(format)
+	if err != nil {
+		return nil, err
+	}
+	if result := validator.Validate(text); !result.Valid {
+		return nil, result.Err
+	}
+
+	findings := []Finding{}
+	for _, rule := range Rules(format) {
+		severity, options := config.settings(rule)
+		if severity == SeverityOff {
+			continue
+		}
+		for _, d := range rule.Check(text, options) {
+			findings = append(findings, Finding{Rule: rule.ID, Severity: severity, Diagnostic: d})
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+
+	return findings, nil
+}
+
+// At returns a diagnostic with message at the 0-based byte offset in data, for rules that
+// find problems by offset.
+func At(data []byte, offset int, message string) serdeval.Diagnostic {
+	offset = min(max(offset, 0), len(data))
+	line := bytes.Count(data[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+
+	return serdeval.Diagnostic{Line: line, Column: column, Offset: offset, Message: message}
+}
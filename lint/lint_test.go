@@ -0,0 +1,136 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format serdeval.Format
+		config string
+		want   []string
+	}{
+		{
+			name:   "defaults",
+			input:  "{\n    \"a\": 1,\n  \"b_c\": 2\n}\n",
+			format: serdeval.FormatJSON,
+			want:   []string{"2:5 warning json-indent: indented by 4 spaces, want 2"},
+		},
+		{
+			name:   "configured severity and options",
+			input:  "{\n    \"a\": 1,\n    \"b_c\": 2\n}\n",
+			format: serdeval.FormatJSON,
+			config: "rules:\n  json-indent: {indent: 4}\n  json-key-case: error\n",
+			want:   []string{`3:5 error json-key-case: key "b_c" is not camel case`},
+		},
+		{
+			name:   "options alone enable a rule that is off",
+			input:  "aB: 1\nc_d: 2\n",
+			format: serdeval.FormatYAML,
+			config: "rules:\n  yaml-key-case: {style: snake}\n",
+			want:   []string{`1:1 warning yaml-key-case: key "aB" is not snake case`},
+		},
+		{
+			name:   "rule turned off",
+			input:  "FROM alpine:3.19\nMAINTAINER me\nUSER app\n",
+			format: serdeval.FormatDockerfile,
+			config: "rules:\n  dockerfile-maintainer: off\n",
+			want:   nil,
+		},
+		{
+			name:   "detected format",
+			input:  "FROM alpine:3.19\nmaintainer me\nUSER app\n",
+			format: serdeval.FormatAuto,
+			want:   []string{`2:1 warning dockerfile-maintainer: MAINTAINER is deprecated; use LABEL maintainer="name <email>"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config *Config
+			if tt.config != "" {
+				var err error
+				if config, err = ParseConfig([]byte(tt.config)); err != nil {
+					t.Fatalf("ParseConfig() error = %v", err)
+				}
+			}
+			findings, err := Lint([]byte(tt.input), tt.format, config)
+			if err != nil {
+				t.Fatalf("Lint() error = %v", err)
+			}
+			got := make([]string, len(findings))
+			for i, f := range findings {
+				got[i] = fmt.Sprintf("%d:%d %s %s: %s", f.Line, f.Column, f.Severity, f.Rule, f.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Lint() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestLintInvalidDocument(t *testing.T) {
+	_, err := Lint([]byte("{\n  \"a\": 1,\n}"), serdeval.FormatJSON, nil)
+	var verr *serdeval.ValidationError
+	if !errors.As(err, &verr) || verr.Line != 3 {
+		t.Errorf("Lint() error = %v, want a validation error on line 3", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(Rule{
+		ID:       "test-ini-no-keys",
+		Format:   serdeval.FormatINI,
+		Severity: SeverityInfo,
+		Check: func(data []byte, _ Options) []serdeval.Diagnostic {
+			return []serdeval.Diagnostic{At(data, strings.IndexByte(string(data), ']'), "bracket")}
+		},
+	})
+	t.Cleanup(func() {
+		rulesMu.Lock()
+		delete(rules, "test-ini-no-keys")
+		rulesMu.Unlock()
+	})
+
+	if got := Rules(serdeval.FormatINI); len(got) != 1 || got[0].ID != "test-ini-no-keys" {
+		t.Errorf("Rules(ini) = %v, want the registered rule", got)
+	}
+	findings, err := Lint([]byte("[a]\nb = 1\n"), serdeval.FormatINI, nil)
+	if err != nil || len(findings) != 1 || findings[0].Line != 1 || findings[0].Column != 3 {
+		t.Errorf("Lint() = %+v, %v, want one info finding at 1:3", findings, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() with no Check did not panic")
+		}
+	}()
+	Register(Rule{ID: "broken", Format: serdeval.FormatINI, Severity: SeverityInfo})
+}
+
+func TestAt(t *testing.T) {
+	data := []byte("ab\ncd\n")
+	tests := []struct {
+		offset, line, column int
+	}{
+		{0, 1, 1},
+		{1, 1, 2},
+		{3, 2, 1},
+		{4, 2, 2},
+		{99, 3, 1},
+	}
+
+	for _, tt := range tests {
+		d := At(data, tt.offset, "m")
+		if d.Line != tt.line || d.Column != tt.column {
+			t.Errorf("At(%d) = %d:%d, want %d:%d", tt.offset, d.Line, d.Column, tt.line, tt.column)
+		}
+	}
+}
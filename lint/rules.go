@@ -0,0 +1,208 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func init() {
+	Register(Rule{
+		ID:          "json-indent",
+		Format:      serdeval.FormatJSON,
+		Description: "Lines are indented by the same number of spaces per nesting level.",
+		Severity:    SeverityWarning,
+		Options:     Options{"indent": 2},
+		Check:       checkJSONIndent,
+	})
+	Register(Rule{
+		ID:          "json-key-case",
+		Format:      serdeval.FormatJSON,
+		Description: "Object keys follow one naming style: camel, pascal, snake, kebab, or screaming-snake.",
+		Severity:    SeverityOff,
+		Options:     Options{"style": "camel"},
+		Check:       checkJSONKeyCase,
+	})
+	Register(Rule{
+		ID:          "yaml-key-case",
+		Format:      serdeval.FormatYAML,
+		Description: "Mapping keys follow one naming style: camel, pascal, snake, kebab, or screaming-snake.",
+		Severity:    SeverityOff,
+		Options:     Options{"style": "camel"},
+		Check:       checkYAMLKeyCase,
+	})
+}
+
+// checkJSONIndent reports lines whose indentation is not the configured number of spaces
+// for each level of nesting they start at. JSON on a single line is not checked.
+func checkJSONIndent(data []byte, options Options) []serdeval.Diagnostic {
+	indent := options.Int("indent", 2)
+	var diags []serdeval.Diagnostic
+	depth, inString, escaped := 0, false, false
+	for offset := 0; offset < len(data); offset++ {
+		c := data[offset]
+		switch {
+		case inString:
+			inString = escaped || c != '"'
+			escaped = !escaped && c == '\\'
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		case c == '\n':
+			if d, ok := checkJSONLine(data, offset+1, depth, indent); !ok {
+				diags = append(diags, d)
+			}
+		}
+	}
+
+	return diags
+}
+
+// checkJSONLine checks the indentation of the line starting at offset, at the given
+// nesting depth, and returns the problem if there is one.
+func checkJSONLine(data []byte, offset, depth, indent int) (serdeval.Diagnostic, bool) {
+	end := offset
+	for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	if end == len(data) || data[end] == '\n' || data[end] == '\r' {
+		return serdeval.Diagnostic{}, true
+	}
+	if data[end] == '}' || data[end] == ']' {
+		depth--
+	}
+
+	whitespace := data[offset:end]
+	if bytes.IndexByte(whitespace, '\t') >= 0 {
+		return At(data, offset, "indented with tabs instead of spaces"), false
+	}
+	if want := depth * indent; len(whitespace) != want {
+		return At(data, end, fmt.Sprintf("indented by %d spaces, want %d", len(whitespace), want)), false
+	}
+
+	return serdeval.Diagnostic{}, true
+}
+
+// keyStyles match the keys of each naming style.
+var keyStyles = map[string]*regexp.Regexp{
+	"camel":           regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"pascal":          regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	"snake":           regexp.MustCompile(`^[a-z][a-z0-9_]*$`),
+	"kebab":           regexp.MustCompile(`^[a-z][a-z0-9-]*$`),
+	"screaming-snake": regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`),
+}
+
+// identifierKey matches keys made only of letters, digits, "_", and "-". Other keys, such
+// as label names, URLs, and file paths, are data rather than names and are not checked.
+var identifierKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// keyStyle returns the configured naming style and the pattern of its keys.
+func keyStyle(options Options) (string, *regexp.Regexp, error) {
+	style := options.String("style", "camel")
+	re, ok := keyStyles[style]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown key style %q (expected camel, pascal, snake, kebab, or screaming-snake)",
+			style)
+	}
+
+	return style, re, nil
+}
+
+// badKeyCase reports whether key, when it is a name, does not match re.
+func badKeyCase(key string, re *regexp.Regexp) bool {
+	return identifierKey.MatchString(key) && !re.MatchString(key)
+}
+
+// checkJSONKeyCase reports object keys that do not follow the configured style.
+func checkJSONKeyCase(data []byte, options Options) []serdeval.Diagnostic {
+	style, re, err := keyStyle(options)
+	if err != nil {
+		return []serdeval.Diagnostic{{Message: err.Error()}}
+	}
+
+	var diags []serdeval.Diagnostic
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Each open object or array, and whether the next string in it is a key
+	type frame struct{ object, expectKey bool }
+	var stack []*frame
+	for {
+		start := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return diags
+		}
+
+		if key, ok := tok.(string); ok && len(stack) > 0 && stack[len(stack)-1].expectKey {
+			if badKeyCase(key, re) {
+				diags = append(diags, At(data, start+bytes.IndexByte(data[start:], '"'),
+					fmt.Sprintf("key %q is not %s case", key, style)))
+			}
+			stack[len(stack)-1].expectKey = false
+
+			continue
+		}
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, &frame{object: true, expectKey: true})
+
+			continue
+		case json.Delim('['):
+			stack = append(stack, &frame{})
+
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		}
+		// A value ended; in an object, a key comes next
+		if len(stack) > 0 {
+			stack[len(stack)-1].expectKey = stack[len(stack)-1].object
+		}
+	}
+}
+
+// checkYAMLKeyCase reports mapping keys that do not follow the configured style.
+func checkYAMLKeyCase(data []byte, options Options) []serdeval.Diagnostic {
+	style, re, err := keyStyle(options)
+	if err != nil {
+		return []serdeval.Diagnostic{{Message: err.Error()}}
+	}
+
+	var diags []serdeval.Diagnostic
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if dec.Decode(&doc) != nil {
+			return diags
+		}
+		diags = yamlKeyCase(&doc, style, re, diags)
+	}
+}
+
+// yamlKeyCase appends the problems with the keys of node and the nodes below it.
+func yamlKeyCase(node *yaml.Node, style string, re *regexp.Regexp, diags []serdeval.Diagnostic) []serdeval.Diagnostic {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Kind == yaml.ScalarNode && key.Tag != "!!merge" {
+				if badKeyCase(key.Value, re) {
+					diags = append(diags, serdeval.Diagnostic{
+						Line: key.Line, Column: key.Column, Message: fmt.Sprintf("key %q is not %s case", key.Value, style),
+					})
+				}
+			}
+		}
+	}
+	for _, child := range node.Content {
+		diags = yamlKeyCase(child, style, re, diags)
+	}
+
+	return diags
+}
@@ -0,0 +1,200 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	const combineApt = "combine with the previous RUN apt-get into one layer, so apt-get update is never cached " +
+		"apart from apt-get install"
+	tests := []struct {
+		name    string
+		rule    string
+		input   string
+		options Options
+		want    []string
+	}{
+		{
+			name:  "json indent",
+			rule:  "json-indent",
+			input: "{\n  \"a\": [\n    1,\n   2\n  ],\n\t\"b\": \"}\\\"{\"\n}",
+			want:  []string{"4:4 indented by 3 spaces, want 4", "6:1 indented with tabs instead of spaces"},
+		},
+		{
+			name:    "json indent by four",
+			rule:    "json-indent",
+			input:   "[\n    {\n        \"a\": 1\n    }\n]\n",
+			options: Options{"indent": 4},
+		},
+		{name: "single-line json", rule: "json-indent", input: `{"a": {"b": [1, 2]}}`},
+		{
+			name:  "json key case",
+			rule:  "json-key-case",
+			input: `{"apiVersion": 1, "a_b": {"io.k8s/name": "x_y", "C": [{"dE": 1}]}}`,
+			want:  []string{`1:19 key "a_b" is not camel case`, `1:49 key "C" is not camel case`},
+		},
+		{
+			name:    "json key case snake",
+			rule:    "json-key-case",
+			input:   "{\n  \"a_b\": 1,\n  \"cD\": 2\n}",
+			options: Options{"style": "snake"},
+			want:    []string{`3:3 key "cD" is not snake case`},
+		},
+		{
+			name:    "unknown key style",
+			rule:    "json-key-case",
+			input:   `{}`,
+			options: Options{"style": "train"},
+			want:    []string{`0:0 unknown key style "train" (expected camel, pascal, snake, kebab, or screaming-snake)`},
+		},
+		{
+			name:  "yaml key case",
+			rule:  "yaml-key-case",
+			input: "base: &b {x-y: 1}\nobj:\n  <<: *b\n  list:\n    - Name: a\n---\nsnake_case: 1\n",
+			want: []string{
+				`1:11 key "x-y" is not camel case`,
+				`5:7 key "Name" is not camel case`,
+				`7:1 key "snake_case" is not camel case`,
+			},
+		},
+		{
+			name:    "yaml key case kebab",
+			rule:    "yaml-key-case",
+			input:   "a-b: 1\nc_d: 2\n",
+			options: Options{"style": "kebab"},
+			want:    []string{`2:1 key "c_d" is not kebab case`},
+		},
+		{
+			name: "yaml indentation",
+			rule: "yaml-indentation",
+			input: "a:\n  b:\n     c: 1\n  list:\n  - x\n  other:\n      - y\n  flow: {\n    d: 1}\n  inline: {e: 1}\n" +
+				"---\nf:\n    g: 1\n",
+			want: []string{"3:6 indented by 3 spaces from its key, want 2", "7:7 indented by 4 spaces from its key, want 2"},
+		},
+		{
+			name:    "yaml indentation by four",
+			rule:    "yaml-indentation",
+			input:   "a:\n  b: 1\n",
+			options: Options{"spaces": 4},
+			want:    []string{"2:3 indented by 2 spaces from its key, want 4"},
+		},
+		{
+			name:  "yaml trailing spaces",
+			rule:  "yaml-trailing-spaces",
+			input: "a: 1 \r\nb: |\n  text\t\n\nc: 2  ",
+			want:  []string{"1:5 trailing whitespace", "3:7 trailing whitespace", "5:5 trailing whitespace"},
+		},
+		{
+			name:  "yaml truthy",
+			rule:  "yaml-truthy",
+			input: "on:\n  push: yes\nenabled: true\nquoted: \"no\"\ntagged: !!str off\nlist: [Y, N, maybe, FALSE]\n",
+			want: []string{
+				`1:1 truthy value "on": write true or false, or quote it if it is a string`,
+				`2:9 truthy value "yes": write true or false, or quote it if it is a string`,
+				`6:8 truthy value "Y": write true or false, or quote it if it is a string`,
+				`6:11 truthy value "N": write true or false, or quote it if it is a string`,
+				`6:21 truthy value "FALSE": write true or false, or quote it if it is a string`,
+			},
+		},
+		{
+			name:    "yaml truthy values only",
+			rule:    "yaml-truthy",
+			input:   "on:\n  push: off\n",
+			options: Options{"check-keys": false},
+			want:    []string{`2:9 truthy value "off": write true or false, or quote it if it is a string`},
+		},
+		{
+			name:  "yaml octal",
+			rule:  "yaml-octal",
+			input: "mode: 0755\nperm: 0o644\nquoted: \"0755\"\nzero: 0\ndecimal: 0.5\nnine: 089\n",
+			want: []string{
+				"1:7 0755 is octal in YAML 1.1 but decimal in YAML 1.2; quote it or write it in decimal",
+				"2:7 0o644 is octal in YAML 1.2 but a string in YAML 1.1; quote it or write it in decimal",
+			},
+		},
+		{
+			name:    "yaml line length",
+			rule:    "yaml-line-length",
+			input:   "short: line\ndescription: a line that is too long\nurl:\n  - https://example.com/a/very/long/path\n",
+			options: Options{"max": 20},
+			want:    []string{"2:21 line is 36 characters long, want at most 20"},
+		},
+		{
+			name:  "dockerfile maintainer",
+			rule:  "dockerfile-maintainer",
+			input: "FROM alpine\n  maintainer me\nLABEL a=1 \\\n  MAINTAINER=me\n",
+			want:  []string{`2:3 MAINTAINER is deprecated; use LABEL maintainer="name <email>"`},
+		},
+		{
+			name: "dockerfile image tag",
+			rule: "dockerfile-image-tag",
+			input: "FROM golang AS build\nFROM localhost:5000/app\nFROM --platform=$P build\nFROM scratch\n" +
+				"FROM alpine@sha256:abc\nFROM ${BASE}\nFROM debian:12\n",
+			want: []string{"1:1 pin golang to a tag or digest", "2:1 pin localhost:5000/app to a tag or digest"},
+		},
+		{
+			name:  "dockerfile latest tag",
+			rule:  "dockerfile-latest-tag",
+			input: "FROM node:latest AS build\nFROM latest:1\n",
+			want:  []string{"1:1 node:latest changes whenever a new version is pushed; pin a specific tag"},
+		},
+		{
+			name: "dockerfile apt layers",
+			rule: "dockerfile-apt-layers",
+			input: "FROM debian:12\nRUN apt-get update\nRUN apt-get install -y \\\n    curl\n# tools\n" +
+				"RUN [\"apt-get\", \"clean\"]\nRUN echo apt-get-free\nRUN apt-get update && apt-get install -y git\n",
+			want: []string{
+				"3:1 " + combineApt,
+				"6:1 " + combineApt,
+			},
+		},
+		{
+			name:  "dockerfile missing user",
+			rule:  "dockerfile-user",
+			input: "FROM golang:1.23 AS build\nUSER app\nFROM alpine:3.19\nCOPY --from=build /app /app\n",
+			want:  []string{"3:1 the final stage has no USER instruction, so the container runs as root"},
+		},
+		{
+			name:  "dockerfile root user",
+			rule:  "dockerfile-user",
+			input: "FROM alpine:3.19\nUSER app\nUSER 0:0\n",
+			want:  []string{"3:1 the last USER is root; switch to an unprivileged user"},
+		},
+		{name: "dockerfile user", rule: "dockerfile-user", input: "FROM alpine:3.19\nUSER root\nRUN id\nUSER app\n"},
+		{
+			name: "dockerfile add",
+			rule: "dockerfile-add",
+			input: "FROM alpine:3.19\nADD https://example.com/a.tgz /a\nADD --chown=app rootfs.tar.gz /\n" +
+				"ADD [\"conf\", \"app.tar\", \"/etc/\"]\nadd . /src\n",
+			want: []string{"4:1 use COPY instead of ADD for conf", "5:1 use COPY instead of ADD for ."},
+		},
+		{
+			name:  "dockerfile escape directive",
+			rule:  "dockerfile-add",
+			input: "# escape=`\nFROM mcr.microsoft.com/windows:ltsc2022\nRUN dir `\n  ADD x y\nADD C:\\app C:\\app\n",
+			want:  []string{`5:1 use COPY instead of ADD for C:\app`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := lookup(tt.rule)
+			if !ok {
+				t.Fatalf("rule %s is not registered", tt.rule)
+			}
+			options := tt.options
+			if options == nil {
+				options = rule.Options
+			}
+			var got []string
+			for _, d := range rule.Check([]byte(tt.input), options) {
+				got = append(got, fmt.Sprintf("%d:%d %s", d.Line, d.Column, d.Message))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Check() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
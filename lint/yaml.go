@@ -0,0 +1,243 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/akhilesharora/serdeval"
+)
+
+func init() {
+	Register(Rule{
+		ID:          "yaml-indentation",
+		Format:      serdeval.FormatYAML,
+		Description: "Block mappings and sequences are indented from their key by spaces, or as the first one when 0.",
+		Severity:    SeverityWarning,
+		Options:     Options{"spaces": 0},
+		Check:       checkYAMLIndentation,
+	})
+	Register(Rule{
+		ID:          "yaml-trailing-spaces",
+		Format:      serdeval.FormatYAML,
+		Description: "Lines do not end in spaces or tabs.",
+		Severity:    SeverityWarning,
+		Check:       checkYAMLTrailingSpaces,
+	})
+	Register(Rule{
+		ID:          "yaml-truthy",
+		Format:      serdeval.FormatYAML,
+		Description: "Booleans are written true or false, not yes, no, on, or off, which YAML 1.1 reads as booleans.",
+		Severity:    SeverityWarning,
+		Options:     Options{"check-keys": true},
+		Check:       checkYAMLTruthy,
+	})
+	Register(Rule{
+		ID:          "yaml-octal",
+		Format:      serdeval.FormatYAML,
+		Description: "Numbers such as 0755 or 0o755, which YAML 1.1 and 1.2 read differently, are quoted.",
+		Severity:    SeverityWarning,
+		Check:       checkYAMLOctal,
+	})
+	Register(Rule{
+		ID:          "yaml-line-length",
+		Format:      serdeval.FormatYAML,
+		Description: "Lines are at most max characters long, unless they hold a single word such as a URL.",
+		Severity:    SeverityWarning,
+		Options:     Options{"max": 80},
+		Check:       checkYAMLLineLength,
+	})
+}
+
+// yamlDocuments returns the documents of a YAML stream.
+func yamlDocuments(data []byte) []*yaml.Node {
+	var docs []*yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if dec.Decode(&doc) != nil {
+			return docs
+		}
+		docs = append(docs, &doc)
+	}
+}
+
+// walkYAML calls fn with every node below node and the mapping key it is the value of,
+// if any. Aliases are not followed.
+func walkYAML(node, key *yaml.Node, fn func(node, key *yaml.Node)) {
+	fn(node, key)
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walkYAML(node.Content[i], nil, fn)
+			walkYAML(node.Content[i+1], node.Content[i], fn)
+		}
+
+		return
+	}
+	for _, child := range node.Content {
+		walkYAML(child, nil, fn)
+	}
+}
+
+// plainScalar reports whether node is a scalar written without quotes or a tag, whose
+// type the parser infers from its text.
+func plainScalar(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Style == 0
+}
+
+// checkYAMLIndentation reports block mappings and sequences on their own lines whose
+// indentation from the key they belong to is not the configured number of spaces. With
+// 0 spaces, the first indentation found sets the number for the document. Sequences
+// may also sit at the same column as their key, as is common in Kubernetes manifests.
+func checkYAMLIndentation(data []byte, options Options) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	for _, doc := range yamlDocuments(data) {
+		spaces := options.Int("spaces", 0)
+		walkYAML(doc, nil, func(node, key *yaml.Node) {
+			block := node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode
+			if key == nil || !block || node.Style&yaml.FlowStyle != 0 || node.Line == key.Line {
+				return
+			}
+			indent := node.Column - key.Column
+			if node.Kind == yaml.SequenceNode && indent == 0 {
+				return
+			}
+			if spaces == 0 && indent > 0 {
+				spaces = indent
+			}
+			if indent != spaces {
+				diags = append(diags, serdeval.Diagnostic{
+					Line: node.Line, Column: node.Column,
+					Message: fmt.Sprintf("indented by %d spaces from its key, want %d", indent, spaces),
+				})
+			}
+		})
+	}
+
+	return diags
+}
+
+// checkYAMLTrailingSpaces reports lines ending in spaces or tabs.
+func checkYAMLTrailingSpaces(data []byte, _ Options) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	for offset := 0; offset < len(data); {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += offset
+		}
+		line := bytes.TrimSuffix(data[offset:end], []byte{'\r'})
+		if trimmed := bytes.TrimRight(line, " \t"); len(trimmed) < len(line) {
+			diags = append(diags, At(data, offset+len(trimmed), "trailing whitespace"))
+		}
+		offset = end + 1
+	}
+
+	return diags
+}
+
+// truthyValue matches the plain scalars YAML 1.1 reads as booleans, except true and
+// false.
+var truthyValue = regexp.MustCompile(`^(?:[yYnN]|[yY]es|YES|[nN]o|NO|[oO]n|ON|[oO]ff|OFF|True|TRUE|False|FALSE)$`)
+
+// checkYAMLTruthy reports booleans written other than true or false, in values and,
+// unless check-keys is false, in keys.
+func checkYAMLTruthy(data []byte, options Options) []serdeval.Diagnostic {
+	checkKeys := options.Bool("check-keys", true)
+	var diags []serdeval.Diagnostic
+	for _, doc := range yamlDocuments(data) {
+		var keys map[*yaml.Node]bool
+		if !checkKeys {
+			keys = yamlKeys(doc)
+		}
+		walkYAML(doc, nil, func(node, _ *yaml.Node) {
+			if !plainScalar(node) || !truthyValue.MatchString(node.Value) || keys[node] {
+				return
+			}
+			diags = append(diags, serdeval.Diagnostic{
+				Line: node.Line, Column: node.Column,
+				Message: fmt.Sprintf("truthy value %q: write true or false, or quote it if it is a string", node.Value),
+			})
+		})
+	}
+
+	return diags
+}
+
+// yamlKeys returns the set of mapping keys under root.
+func yamlKeys(root *yaml.Node) map[*yaml.Node]bool {
+	keys := map[*yaml.Node]bool{}
+	walkYAML(root, nil, func(node, _ *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i < len(node.Content); i += 2 {
+				keys[node.Content[i]] = true
+			}
+		}
+	})
+
+	return keys
+}
+
+// Octal-looking numbers: YAML 1.1 reads 0755 as octal and 0o755 as a string, and YAML
+// 1.2 reads 0755 as decimal and 0o755 as octal.
+var (
+	implicitOctal = regexp.MustCompile(`^[-+]?0[0-7_]+$`)
+	explicitOctal = regexp.MustCompile(`^0o[0-7]+$`)
+)
+
+// checkYAMLOctal reports unquoted octal-looking numbers, whose value depends on the
+// YAML version of the parser.
+func checkYAMLOctal(data []byte, _ Options) []serdeval.Diagnostic {
+	var diags []serdeval.Diagnostic
+	for _, doc := range yamlDocuments(data) {
+		walkYAML(doc, nil, func(node, _ *yaml.Node) {
+			if !plainScalar(node) {
+				return
+			}
+			var msg string
+			switch {
+			case implicitOctal.MatchString(node.Value):
+				msg = fmt.Sprintf("%s is octal in YAML 1.1 but decimal in YAML 1.2; quote it or write it in decimal",
+					node.Value)
+			case explicitOctal.MatchString(node.Value):
+				msg = fmt.Sprintf("%s is octal in YAML 1.2 but a string in YAML 1.1; quote it or write it in decimal",
+					node.Value)
+			default:
+				return
+			}
+			diags = append(diags, serdeval.Diagnostic{Line: node.Line, Column: node.Column, Message: msg})
+		})
+	}
+
+	return diags
+}
+
+// checkYAMLLineLength reports lines longer than max characters. A line holding a single
+// word after its indentation and any "- " or "# ", such as a long URL, cannot be broken
+// and is not reported.
+func checkYAMLLineLength(data []byte, options Options) []serdeval.Diagnostic {
+	limit := options.Int("max", 80)
+	var diags []serdeval.Diagnostic
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		length := utf8.RuneCountInString(line)
+		if length <= limit {
+			continue
+		}
+		word := strings.TrimLeft(line, " ")
+		word = strings.TrimPrefix(strings.TrimPrefix(word, "- "), "# ")
+		if !strings.ContainsAny(word, " \t") {
+			continue
+		}
+		diags = append(diags, serdeval.Diagnostic{
+			Line: i + 1, Column: limit + 1, Message: fmt.Sprintf("line is %d characters long, want at most %d", length, limit),
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,202 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LogfmtValidator validates logfmt structured log lines (key=value pairs separated by spaces).
+// Each non-blank line must parse cleanly, quoted values must be properly escaped and closed,
+// and a key may appear only once per line.
+//
+// Example:
+//
+//	validator := &LogfmtValidator{baseValidator{format: FormatLogfmt}}
+//	result := validator.ValidateString(`level=info msg="request done" status=200 duration=12ms`)
+type LogfmtValidator struct {
+	baseValidator
+}
+
+// Validate checks if every line of the provided byte slice is a valid logfmt record.
+// Errors are reported with the 1-based line number of the first offending line.
+//
+// Example:
+//
+//	validator := &LogfmtValidator{baseValidator{format: FormatLogfmt}}
+//	result := validator.Validate([]byte("ts=2024-01-05T10:00:00Z level=warn msg=\"disk low\"\n"))
+func (v *LogfmtValidator) Validate(data []byte) Result {
+	var err error
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, lineErr := parseLogfmtLine(line); lineErr != nil {
+			err = fmt.Errorf("line %d: %w", i+1, lineErr)
+
+			break
+		}
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *LogfmtValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// logfmtPair is a single key and its (possibly empty) value.
+type logfmtPair struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+// parseLogfmtLine splits a logfmt line into its pairs, rejecting malformed
+// quoting, empty keys, and duplicate keys.
+func parseLogfmtLine(line string) ([]logfmtPair, error) {
+	var pairs []logfmtPair
+	seen := make(map[string]bool)
+
+	i := 0
+	for {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= len(line) {
+			return pairs, nil
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' {
+			if line[i] == '"' {
+				return nil, fmt.Errorf("column %d: unexpected quote in key", i+1)
+			}
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			return nil, fmt.Errorf("column %d: missing key before '='", i+1)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("column %d: duplicate key %q", start+1, key)
+		}
+		seen[key] = true
+
+		pair := logfmtPair{key: key}
+		if i < len(line) && line[i] == '=' {
+			i++
+			pair.hasValue = true
+
+			var err error
+			if i < len(line) && line[i] == '"' {
+				pair.value, i, err = parseLogfmtQuoted(line, i)
+			} else {
+				pair.value, i, err = parseLogfmtBare(line, i)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+}
+
+// parseLogfmtBare reads an unquoted value starting at i.
+func parseLogfmtBare(line string, i int) (string, int, error) {
+	start := i
+	for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+		if line[i] == '"' || line[i] == '=' {
+			return "", 0, fmt.Errorf("column %d: unexpected %q in unquoted value; quote the value", i+1, line[i])
+		}
+		i++
+	}
+
+	return line[start:i], i, nil
+}
+
+// parseLogfmtQuoted reads a double-quoted value whose opening quote is at i.
+func parseLogfmtQuoted(line string, i int) (string, int, error) {
+	open := i
+	var b strings.Builder
+	i++
+	for i < len(line) {
+		c := line[i]
+		switch c {
+		case '"':
+			i++
+			if i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				return "", 0, fmt.Errorf("column %d: missing space after quoted value", i+1)
+			}
+
+			return b.String(), i, nil
+		case '\\':
+			if i+1 >= len(line) {
+				return "", 0, errors.New("unterminated escape sequence")
+			}
+			switch esc := line[i+1]; esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'u':
+				if i+6 > len(line) || !isHexString(line[i+2:i+6]) {
+					return "", 0, fmt.Errorf("column %d: invalid unicode escape", i+1)
+				}
+				i += 4
+			default:
+				return "", 0, fmt.Errorf("column %d: invalid escape sequence \\%c", i+1, esc)
+			}
+			i += 2
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return "", 0, fmt.Errorf("column %d: unterminated quoted value", open+1)
+}
+
+func isHexString(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isHexDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// detectLogfmt reports whether every non-blank line is logfmt with at least
+// two key=value pairs, which keeps single-assignment INI/TOML/env files out.
+func detectLogfmt(lines []string) bool {
+	found := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		pairs, err := parseLogfmtLine(line)
+		if err != nil || len(pairs) < 2 {
+			return false
+		}
+		for _, p := range pairs {
+			if !p.hasValue {
+				return false
+			}
+		}
+		found = true
+	}
+
+	return found
+}
@@ -0,0 +1,61 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogfmtValidator(t *testing.T) {
+	v := &LogfmtValidator{baseValidator{format: FormatLogfmt}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"simple", `level=info msg=started port=8080`, true, ""},
+		{"quoted with escapes", `msg="said \"hi\"\n" path="C:\\tmp"`, true, ""},
+		{"bare key and empty value", `debug level= msg=ok`, true, ""},
+		{"multiple lines", "a=1 b=2\n\nc=3 d=\"x y\"\n", true, ""},
+		{"unicode escape", `msg="caf\u00e9"`, true, ""},
+		{"duplicate key", "a=1 b=2\na=1 a=2", false, "line 2"},
+		{"unterminated quote", `msg="oops`, false, "unterminated"},
+		{"bad escape", `msg="\q"`, false, "invalid escape"},
+		{"missing key", `=value`, false, "missing key"},
+		{"quote in bare value", `msg=a"b`, false, "quote the value"},
+		{"no space after quote", `a="x"b=1`, false, "missing space"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestDetectLogfmt(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"log lines", "ts=1 level=info msg=\"a, b, c\"\nts=2 level=warn msg=done", true},
+		{"single assignment", "name=value", false},
+		{"bare words", "hello world", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat([]byte(tt.input)) == FormatLogfmt; got != tt.want {
+				t.Errorf("DetectFormat(%q) == FormatLogfmt is %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package serdeval
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// CheckMarkdownLinks reports the relative links and images of a Markdown document that do
+// not resolve, without touching the network. name is the slash-separated path of the
+// document in fsys, and link targets are resolved from its directory.
+//
+// A target must name a file or directory in fsys. A fragment, as in "#usage" or
+// "guide.md#usage", must match a heading of the Markdown document it points to, or an
+// HTML anchor with that id or name. Headings map to anchors as on GitHub: lower-cased,
+// with punctuation dropped and spaces turned into hyphens, and repeated headings get
+// "-1", "-2", and so on. Links with a scheme, such as https: or mailto:, and links
+// starting with "/", whose root depends on where the document is served, are not checked.
+//
+// Example:
+//
+//	diags := CheckMarkdownLinks(os.DirFS("."), "docs/README.md", data)
+//	for _, d := range diags {
+//		fmt.Printf("docs/README.md:%d:%d: %s\n", d.Line, d.Column, d.Message)
+//	}
+func CheckMarkdownLinks(fsys fs.FS, name string, data []byte) []Diagnostic {
+	source, _, err := DecodeText(data)
+	if err != nil {
+		return nil
+	}
+
+	links, anchors := parseMarkdownLinks(source)
+	checker := &linkChecker{fsys: fsys, name: name, anchors: map[string]map[string]bool{name: anchors}}
+	var diags []Diagnostic
+	for _, link := range links {
+		if msg := checker.check(link); msg != "" {
+			diags = append(diags, atOffset(source, link.offset, msg))
+		}
+	}
+
+	return diags
+}
+
+// markdownLink is a link or image of a Markdown document.
+type markdownLink struct {
+	dest   string
+	offset int
+	image  bool
+}
+
+// htmlAnchor matches the id or name of an HTML element written in Markdown, as in
+// <a name="install"></a>.
+var htmlAnchor = regexp.MustCompile(`<[a-zA-Z][^>]*\s(?:id|name)\s*=\s*["']([^"']+)["']`)
+
+// parseMarkdownLinks returns the links and images of a Markdown document, and the set of
+// anchors its headings and HTML elements define.
+func parseMarkdownLinks(source []byte) ([]markdownLink, map[string]bool) {
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+	var links []markdownLink
+	anchors := map[string]bool{}
+	headings := map[string]int{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Heading:
+			anchor := headingAnchor(inlineText(n, source))
+			if count := headings[anchor]; count > 0 {
+				anchors[fmt.Sprintf("%s-%d", anchor, count)] = true
+			} else {
+				anchors[anchor] = true
+			}
+			headings[anchor]++
+		case *ast.Link:
+			links = append(links, markdownLink{dest: string(n.Destination), offset: linkOffset(n, source)})
+		case *ast.Image:
+			links = append(links, markdownLink{dest: string(n.Destination), offset: linkOffset(n, source), image: true})
+		}
+
+		return ast.WalkContinue, nil
+	})
+	for _, m := range htmlAnchor.FindAllSubmatch(source, -1) {
+		anchors[string(m[1])] = true
+	}
+
+	return links, anchors
+}
+
+// inlineText returns the text of an inline node and its children, without markup.
+func inlineText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			switch c := c.(type) {
+			case *ast.Text:
+				b.Write(c.Segment.Value(source))
+			case *ast.String:
+				b.Write(c.Value)
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return b.String()
+}
+
+// headingAnchor returns the anchor GitHub gives a heading with text.
+func headingAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case r == ' ':
+			b.WriteByte('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// linkOffset returns the offset of the "[" or "![" opening a link or image. Inline nodes
+// carry no position of their own, so it is found from the first text inside the link,
+// or the start of the enclosing block when the link has no text.
+func linkOffset(n ast.Node, source []byte) int {
+	start := -1
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			start = t.Segment.Start
+
+			return ast.WalkStop, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+	if start < 0 {
+		for p := n.Parent(); p != nil; p = p.Parent() {
+			if p.Type() == ast.TypeBlock && p.Lines().Len() > 0 {
+				return p.Lines().At(0).Start
+			}
+		}
+
+		return 0
+	}
+
+	lineStart := bytes.LastIndexByte(source[:start], '\n') + 1
+	if i := bytes.LastIndexByte(source[lineStart:start], '['); i >= 0 {
+		start = lineStart + i
+	}
+	if _, ok := n.(*ast.Image); ok && start > 0 && source[start-1] == '!' {
+		start--
+	}
+
+	return start
+}
+
+// linkChecker resolves the links of one document, reading the anchors of each Markdown
+// document a fragment points into once.
+type linkChecker struct {
+	fsys    fs.FS
+	name    string
+	anchors map[string]map[string]bool
+}
+
+// check returns why link does not resolve, or "" if it does or is not checked.
+func (c *linkChecker) check(link markdownLink) string {
+	kind := "link"
+	if link.image {
+		kind = "image"
+	}
+	target, fragment, ok := splitLinkTarget(link.dest)
+	if !ok {
+		return ""
+	}
+
+	file := c.name
+	if target != "" {
+		file = path.Join(path.Dir(c.name), target)
+		if !fs.ValidPath(file) {
+			return fmt.Sprintf("broken %s %s: outside the checked directory", kind, link.dest)
+		}
+		info, err := fs.Stat(c.fsys, file)
+		if err != nil {
+			return fmt.Sprintf("broken %s %s: no such file or directory", kind, link.dest)
+		}
+		if info.IsDir() || DetectFormatFromFilename(file) != FormatMarkdown {
+			return ""
+		}
+	}
+	if fragment == "" || c.hasAnchor(file, fragment) {
+		return ""
+	}
+	if target == "" {
+		return fmt.Sprintf("broken %s %s: no heading or anchor %q in this document", kind, link.dest, fragment)
+	}
+
+	return fmt.Sprintf("broken %s %s: no heading or anchor %q in %s", kind, link.dest, fragment, target)
+}
+
+// splitLinkTarget returns the unescaped path and fragment of a link destination, and false
+// for destinations that are not checked: empty ones, those with a scheme, and those
+// starting with "/".
+func splitLinkTarget(dest string) (target, fragment string, ok bool) {
+	if dest == "" || strings.HasPrefix(dest, "/") {
+		return "", "", false
+	}
+	if u, err := url.Parse(dest); err == nil && u.Scheme != "" {
+		return "", "", false
+	}
+
+	target, fragment, _ = strings.Cut(dest, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+
+	return target, fragment, true
+}
+
+// hasAnchor reports whether the Markdown document file defines anchor. Heading anchors
+// are lower case, so the fragment matches them in any case.
+func (c *linkChecker) hasAnchor(file, anchor string) bool {
+	anchors, ok := c.anchors[file]
+	if !ok {
+		data, err := fs.ReadFile(c.fsys, file)
+		if err == nil {
+			data, _, err = DecodeText(data)
+		}
+		if err != nil {
+			// The file exists but cannot be read; there is nothing to check the anchor against
+			return true
+		}
+		_, anchors = parseMarkdownLinks(data)
+		c.anchors[file] = anchors
+	}
+
+	return anchors[anchor] || anchors[strings.ToLower(anchor)]
+}
@@ -0,0 +1,76 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCheckMarkdownLinks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/guide.md":     {Data: []byte("# Guide\n\n## Install `serdeval`\n\n<a name=\"legacy\"></a>\n")},
+		"docs/img/logo.png": {Data: []byte{0x89}},
+		"docs/api/v1.txt":   {Data: []byte("v1")},
+		"README.md":         {Data: []byte("# Home\n")},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "resolving links",
+			input: "# Usage\n\n## Usage\n\nSee [install](guide.md#install-serdeval), [legacy](./guide.md#legacy),\n" +
+				"[home](../README.md), [api](api/), [text](api/v1.txt#L1), [again](#usage-1), [case](#USAGE).\n\n" +
+				"![logo](img/logo.png \"Logo\") [web](https://example.com/x.md) [mail](mailto:a@b.c) [root](/x)\n\n" +
+				"[ref]: guide.md\n\nUse [the ref][ref] and <https://example.com>.\n",
+		},
+		{
+			name: "broken links",
+			input: "# Title\n\nA [missing](nope.md) file and **[bold](guide.md#nowhere)**.\n\n" +
+				"  ![](img/missing%20logo.png)\n\n[up](../../x.md) [anchor](#titel)\n",
+			want: []string{
+				"3:3 broken link nope.md: no such file or directory",
+				`3:33 broken link guide.md#nowhere: no heading or anchor "nowhere" in guide.md`,
+				"5:3 broken image img/missing%20logo.png: no such file or directory",
+				"7:1 broken link ../../x.md: outside the checked directory",
+				`7:18 broken link #titel: no heading or anchor "titel" in this document`,
+			},
+		},
+		{
+			name:  "links in code are not checked",
+			input: "```\n[a](nope.md)\n```\n\n`[b](nope.md)`\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range CheckMarkdownLinks(fsys, "docs/index.md", []byte(tt.input)) {
+				got = append(got, fmt.Sprintf("%d:%d %s", d.Line, d.Column, d.Message))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("CheckMarkdownLinks() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestHeadingAnchor(t *testing.T) {
+	tests := []struct {
+		heading, want string
+	}{
+		{"Getting Started", "getting-started"},
+		{"What's new in v2.0?", "whats-new-in-v20"},
+		{"snake_case & kebab-case", "snake_case--kebab-case"},
+		{"Café", "café"},
+	}
+
+	for _, tt := range tests {
+		if got := headingAnchor(tt.heading); got != tt.want {
+			t.Errorf("headingAnchor(%q) = %q, want %q", tt.heading, got, tt.want)
+		}
+	}
+}
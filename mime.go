@@ -0,0 +1,73 @@
+package serdeval
+
+import (
+	"mime"
+	"strings"
+)
+
+// mimeMap maps media types, lowercased and without parameters, to formats
+var mimeMap = map[string]Format{
+	"application/json":                  FormatJSON,
+	"text/json":                         FormatJSON,
+	"application/x-ndjson":              FormatJSONL,
+	"application/ndjson":                FormatJSONL,
+	"application/jsonl":                 FormatJSONL,
+	"application/jsonlines":             FormatJSONL,
+	"application/x-jsonlines":           FormatJSONL,
+	"application/x-ipynb+json":          FormatJupyter,
+	"application/json-patch+json":       FormatJSONPatch,
+	"application/merge-patch+json":      FormatMergePatch,
+	"application/yaml":                  FormatYAML,
+	"application/x-yaml":                FormatYAML,
+	"text/yaml":                         FormatYAML,
+	"text/x-yaml":                       FormatYAML,
+	"application/xml":                   FormatXML,
+	"text/xml":                          FormatXML,
+	"application/xliff+xml":             FormatXLIFF,
+	"application/toml":                  FormatTOML,
+	"text/csv":                          FormatCSV,
+	"text/tab-separated-values":         FormatTSV,
+	"application/graphql":               FormatGraphQL,
+	"text/markdown":                     FormatMarkdown,
+	"text/x-markdown":                   FormatMarkdown,
+	"application/ion":                   FormatIon,
+	"application/x-amzn-ion":            FormatIon,
+	"text/vtt":                          FormatWebVTT,
+	"application/x-subrip":              FormatSRT,
+	"text/x-gettext-translation":        FormatPO,
+	"application/x-gettext-translation": FormatPO,
+}
+
+// DetectFormatFromMIME returns the format of a Content-Type header value such as
+// "application/json; charset=utf-8". Structured syntax suffixes are recognized, so
+// application/problem+json is FormatJSON and image/svg+xml is FormatXML.
+//
+// Example:
+//
+//	format := DetectFormatFromMIME(r.Header.Get("Content-Type"))
+//	if format == FormatUnknown {
+//		format = DetectFormat(body)
+//	}
+//
+// Returns FormatUnknown for generic types such as text/plain and
+// application/octet-stream, and for values that cannot be parsed.
+func DetectFormatFromMIME(contentType string) Format {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return FormatUnknown
+	}
+	if format, ok := mimeMap[mediaType]; ok {
+		return format
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return FormatJSON
+	case strings.HasSuffix(mediaType, "+yaml"):
+		return FormatYAML
+	case strings.HasSuffix(mediaType, "+xml"):
+		return FormatXML
+	}
+
+	return FormatUnknown
+}
@@ -0,0 +1,43 @@
+package serdeval
+
+import "testing"
+
+func TestDetectFormatFromMIME(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Format
+	}{
+		{"application/json", FormatJSON},
+		{"application/json; charset=utf-8", FormatJSON},
+		{"Application/JSON", FormatJSON},
+		{"application/problem+json", FormatJSON},
+		{"application/x-ndjson", FormatJSONL},
+		{"application/x-ipynb+json", FormatJupyter},
+		{"application/json-patch+json", FormatJSONPatch},
+		{"application/merge-patch+json", FormatMergePatch},
+		{"text/yaml", FormatYAML},
+		{"application/x-yaml", FormatYAML},
+		{"application/openapi+yaml", FormatYAML},
+		{"text/xml; charset=iso-8859-1", FormatXML},
+		{"image/svg+xml", FormatXML},
+		{"application/xliff+xml", FormatXLIFF},
+		{"application/toml", FormatTOML},
+		{"text/csv; header=present", FormatCSV},
+		{"text/tab-separated-values", FormatTSV},
+		{"application/graphql", FormatGraphQL},
+		{"text/markdown; variant=GFM", FormatMarkdown},
+		{"text/vtt", FormatWebVTT},
+		{"text/plain", FormatUnknown},
+		{"application/octet-stream", FormatUnknown},
+		{"", FormatUnknown},
+		{"not a media type;;", FormatUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if got := DetectFormatFromMIME(tt.contentType); got != tt.want {
+				t.Errorf("DetectFormatFromMIME(%q) = %v, want %v", tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
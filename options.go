@@ -0,0 +1,462 @@
+package serdeval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Option configures optional behavior of a Validator created by NewValidator.
+//
+// Example:
+//
+//	validator, _ := NewValidator(FormatJSON, WithMaxFileSize(50<<20))
+type Option func(*options)
+
+// options holds the settings applied by Option functions.
+// The zero value disables every limit, matching validators created without options.
+type options struct {
+	maxSize            int64
+	maxFileSize        int64
+	maxErrors          int
+	maxDepth           int
+	strict             bool
+	yamlLimits         YAMLLimits
+	csvDialect         CSVDialect
+	csvSchema          *CSVSchema
+	xmlSchema          []byte
+	xmlLimits          XMLLimits
+	protoDescriptorSet []byte
+	protoMessage       string
+	terraform          bool
+	latin1             bool
+	secrets            bool
+	pii                bool
+}
+
+// configurable is implemented by validators that take format-specific options.
+// NewValidator calls configure before returning the validator.
+type configurable interface {
+	configure(o options) error
+}
+
+// WithMaxFileSize makes the validator skip inputs larger than n bytes.
+// Oversized inputs are not parsed at all: the Result has Skipped set and an
+// explicit "skipped: too large" error, so callers walking large trees can report
+// them instead of spending minutes (or exhausting memory) on a single file.
+// A value of 0 or less disables the limit.
+func WithMaxFileSize(n int64) Option {
+	return func(o *options) {
+		o.maxFileSize = n
+	}
+}
+
+// WithMaxSize makes the validator fail inputs larger than n bytes without parsing them,
+// as a hard ceiling against inputs that would exhaust memory. Unlike WithMaxFileSize, which
+// skips such inputs, the Result is a failure whose Err has Code ErrCodeTooLarge.
+// ValidateFS and ValidatePaths check each file's size before reading it, so an oversized
+// file is never loaded. When both limits apply, WithMaxSize wins.
+// A value of 0 or less disables the limit.
+func WithMaxSize(n int64) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// WithMaxErrors makes line-oriented validators (JSON Lines, CSV, TSV, Dockerfile, and
+// requirements.txt) and XML checked against a schema keep going after the first
+// failure and report up to n of them.
+// The Result's Error lists one failure per line and Diagnostics has an entry for each.
+// A value of 0 keeps the default of stopping at the first failure; a negative value
+// reports every failure. Other formats ignore it.
+func WithMaxErrors(n int) Option {
+	return func(o *options) {
+		o.maxErrors = n
+	}
+}
+
+// WithMaxDepth makes JSON, YAML, XML, and TOML validators reject documents nested more
+// than n levels deep with an "exceeds max depth" error, before deep input can exhaust the
+// stack or memory. JSON and TOML count nested objects (tables) and arrays, with the
+// document's outermost one at level 1; JSON is checked before it is parsed. For YAML and
+// XML, n replaces the MaxDepth of YAMLLimits and XMLLimits, which count nodes and elements.
+// A value of 0 keeps the defaults: no limit beyond the parser's own for JSON and TOML, and
+// 1,000 levels for YAML and XML. A negative value disables the limit. Other formats ignore it.
+func WithMaxDepth(n int) Option {
+	return func(o *options) {
+		o.maxDepth = n
+	}
+}
+
+// WithStrict makes JSON and YAML validators reject documents that their parsers would
+// otherwise accept. For FormatJSON that is duplicate keys within one object (reported with
+// the object's path) and input that is not valid UTF-8; trailing data after the document
+// is always an error. For FormatYAML it is duplicate mapping keys in any document of the
+// stream, explicit tags outside the core schema (such as !Ref), and tabs used for
+// indentation. Other formats ignore it.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithCSVDialect sets the delimiter, quote character, comment prefix, and quote leniency a
+// FormatCSV validator expects; see CSVDialect. Without it the delimiter is sniffed from the
+// first records. NewValidator returns an error if encoding/csv cannot read the dialect.
+// Other formats ignore it.
+func WithCSVDialect(dialect CSVDialect) Option {
+	return func(o *options) {
+		o.csvDialect = dialect
+	}
+}
+
+// WithCSVSchema makes a FormatCSV validator check the header and every row against schema:
+// required and unexpected columns, empty required values, and the type of each value.
+// Violations are reported by row number and column name, with the field's line and column
+// in Diagnostics; WithMaxErrors reports more than the first. NewValidator returns an error
+// if a column is unnamed, listed twice, or has an unknown type. Other formats ignore it.
+func WithCSVSchema(schema CSVSchema) Option {
+	schema.Columns = slices.Clone(schema.Columns)
+
+	return func(o *options) {
+		o.csvSchema = &schema
+	}
+}
+
+// WithYAMLLimits replaces the bounds a FormatYAML validator places on anchor and alias
+// expansion, nesting depth, and node count. Every YAML validator enforces defaults, so this
+// is only needed to raise them for unusually large trusted documents or tighten them for
+// untrusted input; see YAMLLimits. Other formats ignore it.
+func WithYAMLLimits(limits YAMLLimits) Option {
+	return func(o *options) {
+		o.yamlLimits = limits
+	}
+}
+
+// WithXMLSchema makes a FormatXML validator check documents against xsd, a W3C XML Schema
+// (XSD 1.0) document, in addition to well-formedness. Built-in simple types, facets, model
+// groups, attributes, and type derivation are supported; the schema must be self-contained
+// (no xs:import or xs:include), and identity constraints such as xs:key are not checked.
+// NewValidator returns an error if the schema cannot be compiled. Other formats ignore it.
+func WithXMLSchema(xsd []byte) Option {
+	return func(o *options) {
+		o.xmlSchema = xsd
+	}
+}
+
+// WithXMLLimits replaces the bounds a FormatXML validator places on entity references,
+// element depth, and attributes per element, and can forbid DOCTYPE declarations outright.
+// Every XML validator enforces the defaults; see XMLLimits. Other formats ignore it.
+func WithXMLLimits(limits XMLLimits) Option {
+	return func(o *options) {
+		o.xmlLimits = limits
+	}
+}
+
+// WithProtoMessage makes FormatProtoJSON and FormatProtobuf (text format) validators decode
+// payloads as messageName, resolved from descriptorSet: a serialized
+// google.protobuf.FileDescriptorSet such as the output of protoc --include_imports
+// --descriptor_set_out. NewValidator returns an error if the set cannot be parsed or does
+// not define the message. Other formats ignore it.
+func WithProtoMessage(descriptorSet []byte, messageName string) Option {
+	return func(o *options) {
+		o.protoDescriptorSet = descriptorSet
+		o.protoMessage = messageName
+	}
+}
+
+// WithTerraform makes a FormatHCL validator check that files are valid Terraform
+// configurations, beyond HCL syntax: top-level blocks must be Terraform block types with
+// the right number of valid labels, output, module, moved, import, and removed blocks must
+// set their required arguments, and variables, outputs, modules, resources, and data
+// sources must not be defined twice. Files with only arguments (.tfvars) pass unchecked.
+// Other formats ignore it.
+func WithTerraform() Option {
+	return func(o *options) {
+		o.terraform = true
+	}
+}
+
+// WithLatin1 makes validators read input that is not valid UTF-8 as ISO 8859-1 (Latin-1)
+// instead of passing it to the parser as is, for files exported by older tools. UTF-16 and
+// byte order marks are decoded without it.
+func WithLatin1() Option {
+	return func(o *options) {
+		o.latin1 = true
+	}
+}
+
+// WithSecretScan makes validators of every format scan the input for likely credentials
+// with ScanSecrets and report them as Warnings, which do not fail validation. It suits
+// CI gates over configuration files, where a committed key should be caught before it
+// is pushed.
+func WithSecretScan() Option {
+	return func(o *options) {
+		o.secrets = true
+	}
+}
+
+// WithPIIScan makes FormatCSV, FormatTSV, FormatJSON, and FormatJSONL validators scan the
+// input for personal data with ScanPII and report it as Warnings, which do not fail
+// validation, so real email addresses, phone numbers, and card numbers are caught in
+// fixtures before they are committed. Other formats ignore it.
+func WithPIIScan() Option {
+	return func(o *options) {
+		o.pii = true
+	}
+}
+
+// buildOptions applies opts in order over the zero value.
+func buildOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return o
+}
+
+// checkSize returns the Result for an input of size bytes that WithMaxSize fails or
+// WithMaxFileSize skips, and false if it is within both limits.
+func (o options) checkSize(format Format, size int64) (Result, bool) {
+	switch {
+	case o.maxSize > 0 && size > o.maxSize:
+		msg := fmt.Sprintf("input too large: %d bytes exceeds max size of %d", size, o.maxSize)
+
+		return Result{
+			Valid:  false,
+			Format: format,
+			Error:  msg,
+			Err:    &ValidationError{Code: ErrCodeTooLarge, Message: msg},
+		}, true
+	case o.maxFileSize > 0 && size > o.maxFileSize:
+		return o.tooLargeResult(format, size), true
+	}
+
+	return Result{}, false
+}
+
+// tooLargeResult is the Result returned for inputs skipped by WithMaxFileSize.
+func (o options) tooLargeResult(format Format, size int64) Result {
+	return skippedResult(format, ErrCodeTooLarge,
+		fmt.Sprintf("skipped: too large (%d bytes exceeds limit of %d)", size, o.maxFileSize))
+}
+
+// decode converts data to UTF-8 as described on DecodeText and WithLatin1. On failure it
+// returns the Result to report instead of validating.
+func (o options) decode(format Format, data []byte) ([]byte, Encoding, *Result) {
+	text, enc, err := decodeInput(data, o.latin1)
+	if err != nil {
+		result := Result{Valid: false, Format: format, Error: err.Error(), Encoding: enc}.locate(data, err)
+		result.Err.Code = ErrCodeEncoding
+
+		return nil, enc, &result
+	}
+
+	return text, enc, nil
+}
+
+// optionValidator enforces format-independent options around another validator and
+// decodes input to UTF-8 before it.
+type optionValidator struct {
+	Validator
+	opts options
+}
+
+// Validate applies the configured limits and decodes data before delegating to the
+// wrapped validator.
+func (v *optionValidator) Validate(data []byte) Result {
+	return v.opts.run(v.Format(), data, v.Validator.Validate)
+}
+
+// run applies the format-independent options around validate: it fails or skips oversized data,
+// decodes the rest to UTF-8 for validate, and records the encoding, the time taken, and
+// the size of data on the Result.
+func (o options) run(format Format, data []byte, validate func(text []byte) Result) Result {
+	start := time.Now()
+	result, tooLarge := o.checkSize(format, int64(len(data)))
+	if !tooLarge {
+		result = o.decodeAndValidate(format, data, validate)
+	}
+	result.Duration = time.Since(start)
+	result.BytesRead = int64(len(data))
+
+	return result
+}
+
+// decodeAndValidate decodes data to UTF-8 and validates the text.
+func (o options) decodeAndValidate(format Format, data []byte, validate func(text []byte) Result) Result {
+	text, enc, failed := o.decode(format, data)
+	if failed != nil {
+		return *failed
+	}
+
+	result := validate(text)
+	if !result.Valid && !result.Skipped && result.Err == nil {
+		// Registered validators may not locate their failures
+		result.Err = newValidationError(ErrCodeInvalid, text, result)
+	}
+	// ValidateAuto runs with FormatUnknown around the validator of the detected format,
+	// which scans the text itself
+	if !result.Skipped && format != FormatUnknown {
+		result.Warnings = o.scanWarnings(format, text, result.Warnings)
+	}
+	result.Encoding = enc
+	result.Lines = countLines(text)
+
+	return result
+}
+
+// scanWarnings appends the findings of the WithSecretScan and WithPIIScan scans of text to
+// warnings.
+func (o options) scanWarnings(format Format, text []byte, warnings []Diagnostic) []Diagnostic {
+	var found []Diagnostic
+	if o.secrets {
+		found = append(found, ScanSecrets(text)...)
+	}
+	if o.pii && piiFormats[format] {
+		found = append(found, ScanPII(text)...)
+	}
+	for _, d := range found {
+		warnings = warn(warnings, d)
+	}
+
+	return warnings
+}
+
+// countLines returns the number of lines in text, counting a last line without a newline.
+func countLines(text []byte) int {
+	lines := bytes.Count(text, []byte("\n"))
+	if len(text) > 0 && text[len(text)-1] != '\n' {
+		lines++
+	}
+
+	return lines
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *optionValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// configure records the WithMaxErrors limit.
+func (v *JSONLValidator) configure(o options) error {
+	v.maxErrors = o.maxErrors
+
+	return nil
+}
+
+// configure records the WithMaxErrors limit and the WithCSVDialect settings.
+func (v *CSVValidator) configure(o options) error {
+	v.maxErrors = o.maxErrors
+	if err := o.csvDialect.check(); err != nil {
+		return err
+	}
+	v.dialect = o.csvDialect
+	if o.csvSchema != nil {
+		if err := o.csvSchema.check(); err != nil {
+			return err
+		}
+		v.schema = o.csvSchema
+	}
+
+	return nil
+}
+
+// configure records the WithMaxErrors limit.
+func (v *TSVValidator) configure(o options) error {
+	v.maxErrors = o.maxErrors
+
+	return nil
+}
+
+// configure records the WithMaxErrors limit.
+func (v *RequirementsValidator) configure(o options) error {
+	v.maxErrors = o.maxErrors
+
+	return nil
+}
+
+// configure records the WithMaxErrors limit.
+func (v *DockerfileValidator) configure(o options) error {
+	v.maxErrors = o.maxErrors
+
+	return nil
+}
+
+// configure records the WithStrict setting and the WithMaxDepth limit.
+func (v *JSONValidator) configure(o options) error {
+	v.strict = o.strict
+	v.maxDepth = o.maxDepth
+
+	return nil
+}
+
+// configure records the WithMaxDepth limit.
+func (v *TOMLValidator) configure(o options) error {
+	v.maxDepth = o.maxDepth
+
+	return nil
+}
+
+// configure records the WithStrict setting and the WithYAMLLimits bounds, with the depth
+// from WithMaxDepth when it is set.
+func (v *YAMLValidator) configure(o options) error {
+	v.strict = o.strict
+	v.limits = o.yamlLimits
+	if o.maxDepth != 0 {
+		v.limits.MaxDepth = o.maxDepth
+	}
+
+	return nil
+}
+
+// configure records the WithMaxSize limit, or WithMaxFileSize without it, which bounds
+// decompressed .warc.gz files as well as the compressed input.
+func (v *WARCValidator) configure(o options) error {
+	v.maxSize = o.maxSize
+	if v.maxSize == 0 {
+		v.maxSize = o.maxFileSize
+	}
+
+	return nil
+}
+
+// configure records the WithTerraform setting.
+func (v *HCLValidator) configure(o options) error {
+	v.terraform = o.terraform
+
+	return nil
+}
+
+// errorCollector gathers failures up to the WithMaxErrors limit.
+type errorCollector struct {
+	limit int
+	errs  []error
+}
+
+// newErrorCollector returns a collector for the limit set on b.
+func (b baseValidator) newErrorCollector() *errorCollector {
+	return &errorCollector{limit: b.maxErrors}
+}
+
+// add records err and reports whether the limit is reached and validation should stop.
+func (c *errorCollector) add(err error) bool {
+	c.errs = append(c.errs, err)
+
+	return c.full()
+}
+
+// full reports whether no more failures will be accepted.
+func (c *errorCollector) full() bool {
+	return c.limit >= 0 && len(c.errs) >= max(c.limit, 1)
+}
+
+// err joins the collected failures, one per line, or returns nil if there are none.
+func (c *errorCollector) err() error {
+	return errors.Join(c.errs...)
+}
@@ -0,0 +1,190 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithMaxFileSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int64
+		input   string
+		valid   bool
+		skipped bool
+	}{
+		{"under limit", 32, `{"a": 1}`, true, false},
+		{"at limit", 8, `{"a": 1}`, true, false},
+		{"over limit", 4, `{"a": 1}`, false, true},
+		{"disabled", 0, `{"a": 1}`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatJSON, WithMaxFileSize(tt.limit))
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("Valid = %v, want %v", result.Valid, tt.valid)
+			}
+			if result.Skipped != tt.skipped {
+				t.Errorf("Skipped = %v, want %v", result.Skipped, tt.skipped)
+			}
+			if tt.skipped && !strings.HasPrefix(result.Error, "skipped: too large") {
+				t.Errorf("Error = %q, want skipped: too large prefix", result.Error)
+			}
+			if result.Format != FormatJSON {
+				t.Errorf("Format = %v, want %v", result.Format, FormatJSON)
+			}
+		})
+	}
+}
+
+func TestValidateAutoMaxFileSize(t *testing.T) {
+	result := ValidateAuto([]byte(`{"key": "value"}`), WithMaxFileSize(4))
+	if !result.Skipped || result.Valid {
+		t.Errorf("ValidateAuto() = %+v, want skipped invalid result", result)
+	}
+}
+
+func TestWithMaxSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		input   string
+		valid   bool
+		skipped bool
+		error   string
+	}{
+		{"under limit", []Option{WithMaxSize(32)}, `{"a": 1}`, true, false, ""},
+		{"at limit", []Option{WithMaxSize(8)}, `{"a": 1}`, true, false, ""},
+		{"over limit", []Option{WithMaxSize(4)}, `{"a": 1}`, false, false,
+			"input too large: 8 bytes exceeds max size of 4"},
+		{"disabled", []Option{WithMaxSize(0)}, `{"a": 1}`, true, false, ""},
+		{"wins over WithMaxFileSize", []Option{WithMaxFileSize(2), WithMaxSize(4)}, `{"a": 1}`, false, false,
+			"input too large"},
+		{"WithMaxFileSize skips below it", []Option{WithMaxFileSize(4), WithMaxSize(16)}, `{"a": 1}`, false, true,
+			"skipped: too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, validate := range []func([]byte) Result{
+				func(data []byte) Result {
+					v, _ := NewValidator(FormatJSON, tt.opts...)
+
+					return v.Validate(data)
+				},
+				func(data []byte) Result { return ValidateAuto(data, tt.opts...) },
+			} {
+				result := validate([]byte(tt.input))
+				if result.Valid != tt.valid || result.Skipped != tt.skipped || !strings.HasPrefix(result.Error, tt.error) {
+					t.Errorf("Valid, Skipped, Error = %v, %v, %q, want %v, %v, %q prefix",
+						result.Valid, result.Skipped, result.Error, tt.valid, tt.skipped, tt.error)
+				}
+				if tt.error != "" && (result.Err == nil || result.Err.Code != ErrCodeTooLarge) {
+					t.Errorf("Err = %+v, want code %s", result.Err, ErrCodeTooLarge)
+				}
+			}
+		})
+	}
+}
+
+func TestWithMaxErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		limit  int
+		input  string
+		lines  []int
+	}{
+		{"jsonl default", FormatJSONL, 0, "{\n{\"a\":1}\n[\n", []int{1}},
+		{"jsonl limit", FormatJSONL, 2, "{\n[\n{\"a\":1}\nx\n", []int{1, 2}},
+		{"jsonl unlimited", FormatJSONL, -1, "{\n[\n{\"a\":1}\nx\n", []int{1, 2, 4}},
+		{"csv", FormatCSV, -1, "a,b\n1\n2,\"x\"y\n3,4\n5\n", []int{2, 3, 5}},
+		{"requirements", FormatRequirements, -1, "django\n===\nrequests\n!!\n", []int{2, 4}},
+		{"dockerfile", FormatDockerfile, -1, "FROM alpine\nBOGUS x\nRUN ls\nNOPE\n", []int{2, 4}},
+		{"dockerfile missing from", FormatDockerfile, -1, "RUN ls\nBOGUS x\n", []int{2, 0}},
+		{"dockerfile limit before from", FormatDockerfile, 1, "RUN ls\nBOGUS x\n", []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.format, WithMaxErrors(tt.limit))
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid {
+				t.Fatal("Valid = true, want false")
+			}
+			if got := strings.Count(result.Error, "\n") + 1; got != len(tt.lines) {
+				t.Errorf("Error has %d failures, want %d: %q", got, len(tt.lines), result.Error)
+			}
+			if len(result.Diagnostics) != len(tt.lines) {
+				t.Fatalf("Diagnostics = %+v, want %d entries", result.Diagnostics, len(tt.lines))
+			}
+			for i, line := range tt.lines {
+				if result.Diagnostics[i].Line != line {
+					t.Errorf("Diagnostics[%d].Line = %d, want %d", i, result.Diagnostics[i].Line, line)
+				}
+			}
+		})
+	}
+
+	result := ValidateAuto([]byte("FROM alpine\nRUN ls\nBOGUS\nNOPE\n"), WithMaxErrors(-1))
+	if result.Format != FormatDockerfile || len(result.Diagnostics) != 2 {
+		t.Errorf("ValidateAuto() = %+v, want two Dockerfile failures", result)
+	}
+}
+
+func TestResultMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func(data []byte) Result
+		input     []byte
+		bytesRead int64
+		lines     int
+	}{
+		{"validator", func(data []byte) Result {
+			v, _ := NewValidator(FormatYAML)
+
+			return v.Validate(data)
+		}, []byte("a: 1\nb: 2\n"), 10, 2},
+		{"last line without newline", func(data []byte) Result {
+			v, _ := NewValidator(FormatCSV)
+
+			return v.Validate(data)
+		}, []byte("a,b\n1,2"), 7, 2},
+		{"invalid input", func(data []byte) Result {
+			v, _ := NewValidator(FormatJSON)
+
+			return v.Validate(data)
+		}, []byte("{\n"), 2, 1},
+		{"UTF-16 counts encoded bytes and decoded lines", func(data []byte) Result {
+			v, _ := NewValidator(FormatJSON)
+
+			return v.Validate(data)
+		}, utf16Bytes("{\n}", false), 8, 2},
+		{"ValidateAuto", func(data []byte) Result { return ValidateAuto(data) }, []byte("a,b\n1,2\n3,4\n"), 12, 3},
+		{"skipped as too large", func(data []byte) Result {
+			v, _ := NewValidator(FormatJSON, WithMaxFileSize(2))
+
+			return v.Validate(data)
+		}, []byte(`{"a": 1}`), 8, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.validate(tt.input)
+			if result.BytesRead != tt.bytesRead || result.Lines != tt.lines {
+				t.Errorf("BytesRead, Lines = %d, %d, want %d, %d", result.BytesRead, result.Lines, tt.bytesRead, tt.lines)
+			}
+			if result.Duration <= 0 {
+				t.Errorf("Duration = %v, want it measured", result.Duration)
+			}
+		})
+	}
+}
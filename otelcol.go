@@ -0,0 +1,241 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// otelSections are the top-level keys an OpenTelemetry Collector config may contain.
+var otelSections = []string{"receivers", "processors", "exporters", "extensions", "connectors", "service"}
+
+// OtelCollectorValidator validates OpenTelemetry Collector configuration files.
+// Beyond YAML syntax it checks the top-level sections, service.pipelines structure,
+// and that every component a pipeline or service.extensions references is defined.
+//
+// Example:
+//
+//	validator := &OtelCollectorValidator{baseValidator{format: FormatOtelCollector}}
+//	data, _ := os.ReadFile("otel-collector.yaml")
+//	result := validator.Validate(data)
+type OtelCollectorValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid collector configuration.
+//
+// Example:
+//
+//	validator := &OtelCollectorValidator{baseValidator{format: FormatOtelCollector}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. "service.pipelines.traces: exporter \"otlp/backend\" is not defined"
+//	}
+func (v *OtelCollectorValidator) Validate(data []byte) Result {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid YAML: " + err.Error(),
+			Suggestion: suggestFix(FormatYAML, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	err := validateOtelConfig(config)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *OtelCollectorValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateOtelConfig(config map[string]interface{}) error {
+	if err := checkAllowedKeys(config, "", otelSections); err != nil {
+		return err
+	}
+
+	defined := make(map[string]map[string]interface{})
+	for _, section := range otelSections[:5] {
+		m, err := optionalMap(config, section, section)
+		if err != nil {
+			return err
+		}
+		defined[section] = m
+		for id := range m {
+			if err := checkOtelComponentID(id); err != nil {
+				return fmt.Errorf("%s: %w", section, err)
+			}
+		}
+	}
+
+	service, ok := config["service"].(map[string]interface{})
+	if !ok {
+		return errors.New("missing required section: service")
+	}
+
+	extensions, err := optionalStringList(service, "extensions", "service.extensions")
+	if err != nil {
+		return err
+	}
+	for _, ext := range extensions {
+		if _, found := defined["extensions"][ext]; !found {
+			return fmt.Errorf("service.extensions: extension %q is not defined", ext)
+		}
+	}
+
+	pipelines, ok := service["pipelines"].(map[string]interface{})
+	if !ok || len(pipelines) == 0 {
+		return errors.New("service.pipelines must define at least one pipeline")
+	}
+
+	for _, name := range sortedKeys(pipelines) {
+		if err := checkOtelPipeline(name, pipelines[name], defined); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkOtelPipeline(name string, raw interface{}, defined map[string]map[string]interface{}) error {
+	path := "service.pipelines." + name
+
+	signal, _, _ := strings.Cut(name, "/")
+	switch signal {
+	case "traces", "metrics", "logs", "profiles":
+	default:
+		return fmt.Errorf("%s: pipeline type must be traces, metrics, logs, or profiles", path)
+	}
+
+	pipeline, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: pipeline must be a mapping", path)
+	}
+	if err := checkAllowedKeys(pipeline, path, []string{"receivers", "processors", "exporters"}); err != nil {
+		return err
+	}
+
+	refs := []struct {
+		field, kind string
+		sections    []string
+		required    bool
+	}{
+		{"receivers", "receiver", []string{"receivers", "connectors"}, true},
+		{"processors", "processor", []string{"processors"}, false},
+		{"exporters", "exporter", []string{"exporters", "connectors"}, true},
+	}
+	for _, ref := range refs {
+		ids, err := optionalStringList(pipeline, ref.field, path+"."+ref.field)
+		if err != nil {
+			return err
+		}
+		if ref.required && len(ids) == 0 {
+			return fmt.Errorf("%s: must have at least one %s", path, ref.kind)
+		}
+		for _, id := range ids {
+			found := false
+			for _, section := range ref.sections {
+				_, ok := defined[section][id]
+				found = found || ok
+			}
+			if !found {
+				return fmt.Errorf("%s: %s %q is not defined", path, ref.kind, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkOtelComponentID validates "type" or "type/name" component identifiers.
+func checkOtelComponentID(id string) error {
+	typ, name, hasName := strings.Cut(id, "/")
+	if typ == "" || (hasName && name == "") || strings.ContainsAny(typ, " \t") {
+		return fmt.Errorf("invalid component id %q (expected type or type/name)", id)
+	}
+
+	return nil
+}
+
+// checkAllowedKeys reports the first key of m (in sorted order) not in allowed.
+func checkAllowedKeys(m map[string]interface{}, path string, allowed []string) error {
+	ok := make(map[string]bool, len(allowed))
+	for _, key := range allowed {
+		ok[key] = true
+	}
+
+	for _, key := range sortedKeys(m) {
+		if !ok[key] {
+			if path == "" {
+				return fmt.Errorf("unknown top-level key %q", key)
+			}
+
+			return fmt.Errorf("%s: unknown key %q", path, key)
+		}
+	}
+
+	return nil
+}
+
+// optionalMap returns m[key] as a mapping, treating a missing or null value as empty.
+func optionalMap(m map[string]interface{}, key, path string) (map[string]interface{}, error) {
+	raw, ok := m[key]
+	if !ok || raw == nil {
+		return map[string]interface{}{}, nil
+	}
+	result, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a mapping", path)
+	}
+
+	return result, nil
+}
+
+// optionalStringList returns m[key] as a list of strings, treating a missing value as empty.
+func optionalStringList(m map[string]interface{}, key, path string) ([]string, error) {
+	raw, ok := m[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", path)
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must contain only strings", path)
+		}
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+// isOtelCollector checks for the receivers/exporters/service sections of a collector config.
+func isOtelCollector(lines []string) bool {
+	var receivers, exporters, service bool
+	for _, line := range lines {
+		switch strings.TrimRight(line, " \t\r") {
+		case "receivers:":
+			receivers = true
+		case "exporters:":
+			exporters = true
+		case "service:":
+			service = true
+		}
+	}
+
+	return receivers && exporters && service
+}
@@ -0,0 +1,99 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const otelConfig = `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+processors:
+  batch:
+exporters:
+  otlp/backend:
+    endpoint: backend:4317
+  debug:
+connectors:
+  spanmetrics:
+extensions:
+  health_check:
+service:
+  extensions: [health_check]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp/backend, spanmetrics]
+    metrics/spans:
+      receivers: [spanmetrics]
+      exporters: [debug]
+`
+
+func TestOtelCollectorValidator(t *testing.T) {
+	v := &OtelCollectorValidator{baseValidator{format: FormatOtelCollector}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"valid config", otelConfig, true, ""},
+		{"invalid yaml", "receivers:\n  otlp: [\n", false, "invalid YAML"},
+		{"unknown top-level key", otelConfig + "exporter:\n  debug:\n", false, "unknown top-level key \"exporter\""},
+		{"missing service", "receivers:\n  otlp:\nexporters:\n  debug:\n", false, "missing required section: service"},
+		{"no pipelines", "service:\n  pipelines: {}\n", false, "at least one pipeline"},
+		{
+			"undefined exporter",
+			strings.Replace(otelConfig, "[otlp/backend, spanmetrics]", "[otlp/missing]", 1),
+			false, `service.pipelines.traces: exporter "otlp/missing" is not defined`,
+		},
+		{
+			"undefined processor",
+			strings.Replace(otelConfig, "processors: [batch]", "processors: [memory_limiter]", 1),
+			false, `processor "memory_limiter" is not defined`,
+		},
+		{
+			"undefined extension",
+			strings.Replace(otelConfig, "extensions: [health_check]", "extensions: [pprof]", 1),
+			false, `extension "pprof" is not defined`,
+		},
+		{
+			"pipeline without receivers",
+			strings.Replace(otelConfig, "      receivers: [spanmetrics]\n", "", 1),
+			false, "must have at least one receiver",
+		},
+		{
+			"bad pipeline type",
+			strings.Replace(otelConfig, "metrics/spans:", "spans:", 1),
+			false, "pipeline type must be",
+		},
+		{
+			"unknown pipeline key",
+			strings.Replace(otelConfig, "      processors: [batch]", "      processor: [batch]", 1),
+			false, `unknown key "processor"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte(otelConfig)); got != FormatOtelCollector {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatOtelCollector)
+	}
+	if got := DetectFormatFromFilename("deploy/otel-collector-config.yaml"); got != FormatOtelCollector {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatOtelCollector)
+	}
+}
@@ -0,0 +1,348 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// elementPath returns the path of the element where the failure at d was found, for the
+// formats that have one: a JSON Pointer (RFC 6901) such as /spec/containers/0/image for
+// JSON, YAML, and TOML, and an XPath-like /catalog/book[2]/title for XML. As RFC 6901 has
+// it, the document root is "", while "/" is the member whose key is empty. It returns ""
+// for other formats and for failures without a position.
+//
+// Input that stops parsing is only read up to the failure, so YAML and TOML paths come
+// from the keys, sequence items, and table headers on the lines before it.
+func elementPath(format Format, data []byte, d Diagnostic) string {
+	if d.Line == 0 {
+		return ""
+	}
+
+	var segments []string
+	switch format {
+	case FormatJSON:
+		segments = jsonPathAt(data, d.Offset)
+	case FormatYAML:
+		segments = yamlPathAt(data, d.Line)
+	case FormatTOML:
+		segments = tomlPathAt(data, d.Line)
+	case FormatXML:
+		return xmlPathAt(data, d.Offset)
+	default:
+		return ""
+	}
+
+	return jsonPointer(segments)
+}
+
+// jsonPointer joins segments into a JSON Pointer, "" for no segments.
+func jsonPointer(segments []string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteString("/" + pointerSegment(s))
+	}
+
+	return b.String()
+}
+
+// pointerSegment escapes "~" and "/" in a JSON Pointer reference token.
+func pointerSegment(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// jsonPathAt returns the path of the value being read at offset in data, which may be
+// invalid from there on.
+func jsonPathAt(data []byte, offset int) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var stack []*jsonFrame
+	for dec.InputOffset() <= int64(offset) {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		if d, ok := tok.(json.Delim); ok && (d == '{' || d == '[') && dec.InputOffset() > int64(offset) {
+			// The failure is at this object or array itself, such as one nested too deep
+			break
+		}
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		if key, ok := tok.(string); ok && top != nil && top.expectKey {
+			top.key = key
+			top.expectKey = false
+
+			continue
+		}
+		stack = stepJSONFrames(stack, top, tok)
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+
+	top := stack[len(stack)-1]
+	path := top.childPath()
+	if top.keys != nil && top.expectKey {
+		// Between members: the failure belongs to the object itself
+		path = top.path
+	}
+
+	return splitFramePath(path)
+}
+
+// splitFramePath splits a jsonFrame path, whose segments are already escaped, back into
+// raw segments.
+func splitFramePath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	segments := strings.Split(path[1:], "/")
+	for i, s := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+	}
+
+	return segments
+}
+
+// yamlLineFrame is a mapping key or sequence that encloses the lines read by yamlPathAt.
+type yamlLineFrame struct {
+	indent int
+	key    string
+	seq    bool
+	index  int
+}
+
+// yamlPathAt returns the path of the node on the 1-based line of data, from the block
+// mapping keys and sequence items that enclose it. Flow collections are not followed.
+func yamlPathAt(data []byte, line int) []string {
+	var stack []yamlLineFrame
+	blockIndent := -1 // indentation of the key whose block scalar is being skipped
+	for i, raw := range bytes.Split(data, []byte("\n")) {
+		if i >= line {
+			break
+		}
+		text := strings.TrimRight(string(raw), " \t\r")
+		content := strings.TrimLeft(text, " ")
+		indent := len(text) - len(content)
+		if content == "" || content[0] == '#' || (blockIndent >= 0 && indent > blockIndent) {
+			continue
+		}
+		blockIndent = -1
+		if content == "---" || strings.HasPrefix(content, "--- ") {
+			stack = nil
+
+			continue
+		}
+		stack, blockIndent = stepYAMLLine(stack, indent, content)
+	}
+
+	segments := make([]string, 0, len(stack))
+	for _, f := range stack {
+		if f.seq {
+			segments = append(segments, strconv.Itoa(f.index))
+		} else {
+			segments = append(segments, f.key)
+		}
+	}
+
+	return segments
+}
+
+// stepYAMLLine updates stack for a line with the given indentation and content. It
+// returns the indentation of the line's key when its value starts a block scalar, else -1.
+func stepYAMLLine(stack []yamlLineFrame, indent int, content string) ([]yamlLineFrame, int) {
+	for content == "-" || strings.HasPrefix(content, "- ") {
+		for len(stack) > 0 && stack[len(stack)-1].indent > indent {
+			stack = stack[:len(stack)-1]
+		}
+		if top := len(stack) - 1; top >= 0 && stack[top].seq && stack[top].indent == indent {
+			stack[top].index++
+		} else {
+			stack = append(stack, yamlLineFrame{indent: indent, seq: true})
+		}
+		rest := strings.TrimLeft(content[1:], " ")
+		indent += len(content) - len(rest)
+		content = rest
+	}
+
+	key, value, ok := yamlLineKey(content)
+	if !ok {
+		return stack, -1
+	}
+	for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+		stack = stack[:len(stack)-1]
+	}
+	stack = append(stack, yamlLineFrame{indent: indent, key: key})
+	if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+		return stack, indent
+	}
+
+	return stack, -1
+}
+
+// yamlLineKey splits a block mapping entry such as `name: app` or `"a b": c` into its
+// key, without quotes, and value.
+func yamlLineKey(content string) (key, value string, ok bool) {
+	if content == "" || strings.ContainsAny(content[:1], "{[&*!|>%@`") {
+		return "", "", false
+	}
+
+	end := 0
+	if quote := content[0]; quote == '"' || quote == '\'' {
+		closing := strings.IndexByte(content[1:], quote)
+		if closing < 0 {
+			return "", "", false
+		}
+		end = closing + 2
+	}
+	colon := strings.Index(content[end:], ": ")
+	if colon < 0 {
+		if !strings.HasSuffix(content, ":") {
+			return "", "", false
+		}
+		colon = len(content) - 1 - end
+	}
+	key = strings.TrimSpace(content[:end+colon])
+	if end > 0 {
+		key = key[1 : len(key)-1]
+	}
+
+	return key, strings.TrimSpace(content[min(end+colon+2, len(content)):]), true
+}
+
+// tomlPathWalk tracks the tables and array tables read by tomlPathAt.
+type tomlPathWalk struct {
+	// arrays holds the current index of each array table, by its dotted name
+	arrays map[string]int
+	// counts holds how many tables each array table has, by its resolved path
+	counts map[string]int
+	// table is the resolved path of the current table
+	table []string
+}
+
+// tomlPathAt returns the path of the table, key, or table header on the 1-based line of
+// data. Keys inside inline tables and arrays are not followed.
+func tomlPathAt(data []byte, line int) []string {
+	w := &tomlPathWalk{arrays: map[string]int{}, counts: map[string]int{}}
+	var key []string
+	inString := false
+	for i, raw := range bytes.Split(data, []byte("\n")) {
+		if i >= line {
+			break
+		}
+		text := strings.TrimSpace(string(raw))
+		wasInString := inString
+		if (strings.Count(text, `"""`)+strings.Count(text, `'''`))%2 == 1 {
+			inString = !inString
+		}
+		if wasInString {
+			// Part of a multi-line string: the key that opened it still applies
+			continue
+		}
+		key = nil
+		switch {
+		case strings.HasPrefix(text, "[[") && strings.Contains(text, "]]"):
+			w.header(splitTOMLKey(text[2:strings.Index(text, "]]")]), true)
+		case strings.HasPrefix(text, "["):
+			if end := strings.IndexByte(text, ']'); end > 0 {
+				w.header(splitTOMLKey(text[1:end]), false)
+			}
+		case text != "" && text[0] != '#' && strings.Contains(text, "="):
+			key = splitTOMLKey(text[:strings.IndexByte(text, '=')])
+		}
+	}
+
+	return append(append([]string(nil), w.table...), key...)
+}
+
+// header makes the table named by the dotted key segments current, adding a table to the
+// array table of that name when array is set.
+func (w *tomlPathWalk) header(segments []string, array bool) {
+	w.table = nil
+	for i, s := range segments {
+		w.table = append(w.table, s)
+		name := strings.Join(segments[:i+1], "\x00")
+		if i == len(segments)-1 && array {
+			path := jsonPointer(w.table)
+			w.arrays[name] = w.counts[path]
+			w.counts[path]++
+		}
+		if index, ok := w.arrays[name]; ok {
+			w.table = append(w.table, strconv.Itoa(index))
+		}
+	}
+}
+
+// splitTOMLKey splits a dotted TOML key such as `server."x.y".port` into its parts,
+// without quotes.
+func splitTOMLKey(key string) []string {
+	var segments []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(key); i++ {
+		switch {
+		case i == len(key) || (quote == 0 && key[i] == '.'):
+			segments = append(segments, strings.Trim(strings.TrimSpace(key[start:i]), `"'`))
+			start = i + 1
+		case quote == 0 && (key[i] == '"' || key[i] == '\''):
+			quote = key[i]
+		case key[i] == quote:
+			quote = 0
+		}
+	}
+
+	return segments
+}
+
+// xmlPathAt returns the path of the element open at the end of the line holding offset,
+// with a 1-based position after each element that is not the first of its name among
+// its siblings.
+func xmlPathAt(data []byte, offset int) string {
+	end := len(data)
+	if offset >= 0 && offset < len(data) {
+		if next := bytes.IndexByte(data[offset:], '\n'); next >= 0 {
+			end = offset + next
+		}
+	}
+
+	type xmlFrame struct {
+		segment string
+		counts  map[string]int
+	}
+	stack := []*xmlFrame{{counts: map[string]int{}}}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for dec.InputOffset() < int64(end) {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			parent.counts[t.Name.Local]++
+			segment := t.Name.Local
+			if n := parent.counts[t.Name.Local]; n > 1 {
+				segment += "[" + strconv.Itoa(n) + "]"
+			}
+			stack = append(stack, &xmlFrame{segment: segment, counts: map[string]int{}})
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if len(stack) == 1 {
+		return "/"
+	}
+	var b strings.Builder
+	for _, f := range stack[1:] {
+		b.WriteString("/" + f.segment)
+	}
+
+	return b.String()
+}
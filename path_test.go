@@ -0,0 +1,54 @@
+package serdeval
+
+import "testing"
+
+func TestElementPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		opts   []Option
+		input  string
+		want   string
+	}{
+		{"json nested value", FormatJSON, nil,
+			`{"spec": {"containers": [{"name": "a"}, {"image": }]}}`, "/spec/containers/1/image"},
+		{"json between members", FormatJSON, nil, "{\"a\": 1,\n}", ""},
+		{"json array element", FormatJSON, nil, `{"ports": [80, 443 8080]}`, "/ports/2"},
+		{"json root", FormatJSON, nil, `nope`, ""},
+		{"json escaped key", FormatJSON, nil, `{"a/b": {"c~d": tru}}`, "/a~1b/c~0d"},
+		{"json strict duplicate key", FormatJSON, []Option{WithStrict()}, `[{"a": 1, "a": 2}]`, "/0/a"},
+		{"yaml nested key", FormatYAML, nil,
+			"spec:\n  containers:\n    - name: a\n    - name: b\n      image: a: b\n", "/spec/containers/1/image"},
+		{"yaml sequence at key indentation", FormatYAML, nil, "ports:\n- 80\n- a: b: c\n", "/ports/1/a"},
+		{"yaml quoted key and block scalar", FormatYAML, nil,
+			"\"run step\": |\n  make: all\nnext:\n  value: \"open\n", "/next/value"},
+		{"yaml strict duplicate key", FormatYAML, []Option{WithStrict()}, "a:\n  b: 1\n  b: 2\n", "/a/b"},
+		{"yaml new document", FormatYAML, []Option{WithStrict()}, "a:\n  b: 1\n---\nc:\n  d: 1\n  d: 2\n", "/c/d"},
+		{"toml key in table", FormatTOML, nil, "[server]\nhost = \"db\"\nport = 80 80\n", "/server/port"},
+		{"toml array table", FormatTOML, nil,
+			"[[servers]]\nname = \"a\"\n[[servers]]\nname = \"b\"\n[servers.tls]\ncert = \n", "/servers/1/tls/cert"},
+		{"toml dotted quoted key", FormatTOML, nil, "a.\"b.c\" = \n", "/a/b.c"},
+		{"toml multi-line string", FormatTOML, nil, "[x]\ntext = \"\"\"\nline\n\"\"\" junk\n", "/x/text"},
+		{"xml element", FormatXML, nil,
+			"<catalog>\n  <book><title>A</title></book>\n  <book><title>B</titl></book>\n</catalog>", "/catalog/book[2]/title"},
+		{"xml unclosed root", FormatXML, nil, "<a>\n  <b/>\n", "/a"},
+		{"json empty key", FormatJSON, nil, `{"": [1, }`, "//1"},
+		{"csv has no path", FormatCSV, nil, "a,b\n1\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.format, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := v.Validate([]byte(tt.input))
+			if result.Valid || result.Err == nil {
+				t.Fatalf("Validate() = %+v, want a failure", result)
+			}
+			if result.Err.Path != tt.want {
+				t.Errorf("Path = %q, want %q (error: %s)", result.Err.Path, tt.want, result.Error)
+			}
+		})
+	}
+}
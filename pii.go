@@ -0,0 +1,160 @@
+package serdeval
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// piiFormats are the data formats WithPIIScan scans.
+var piiFormats = map[Format]bool{FormatCSV: true, FormatTSV: true, FormatJSON: true, FormatJSONL: true}
+
+var (
+	// piiEmail matches email addresses.
+	piiEmail = regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9-]+\.)+[A-Za-z]{2,}`)
+	// piiPhone matches international numbers in E.164 style, such as +44 20 7946 0958, and
+	// North American numbers such as (415) 555-2671.
+	piiPhone = regexp.MustCompile(`\+[1-9][0-9 ().-]{6,18}[0-9]|\(?\b[2-9][0-9]{2}\)?[ .-][0-9]{3}[ .-][0-9]{4}\b`)
+	// piiCard matches runs of 13 to 19 digits, optionally grouped by spaces or hyphens.
+	piiCard = regexp.MustCompile(`\b[0-9](?:[ -]?[0-9]){12,18}\b`)
+	// cardPrefix matches the issuer prefixes of Visa, Mastercard, American Express,
+	// Discover, and JCB.
+	cardPrefix = regexp.MustCompile(`^(?:4|5[1-5]|2[2-7]|3[47]|6011|65|35)`)
+)
+
+// reservedEmailDomains are domains reserved for documentation and testing (RFC 2606 and
+// RFC 6761), whose addresses belong to no one.
+var reservedEmailDomains = []string{"example.com", "example.org", "example.net", "example", "test", "invalid",
+	"localhost"}
+
+// testCardNumbers are card numbers payment providers publish for testing.
+var testCardNumbers = map[string]bool{
+	"4111111111111111": true, "4242424242424242": true, "4000056655665556": true, "5555555555554444": true,
+	"5105105105105100": true, "378282246310005": true, "371449635398431": true, "6011111111111117": true,
+}
+
+// ScanPII reports values in data that look like personal data: email addresses, phone
+// numbers, and payment card numbers that pass the Luhn check. Each is reported where it
+// starts, with a message naming the kind of value but not the value itself. Addresses at
+// domains reserved for examples, such as example.com, North American numbers with the
+// fictional 555 exchange, and well-known test card numbers are not reported, so fixtures
+// can use them freely.
+//
+// The scan is a heuristic for catching real personal data in fixtures and sample files
+// before they are committed; it can miss values and flag harmless ones.
+//
+// Example:
+//
+//	for _, d := range ScanPII(data) {
+//		fmt.Printf("%d:%d: %s\n", d.Line, d.Column, d.Message)
+//	}
+func ScanPII(data []byte) []Diagnostic {
+	var found []piiMatch
+	for _, loc := range piiEmail.FindAllIndex(data, -1) {
+		if !reservedEmail(string(data[loc[0]:loc[1]])) {
+			found = append(found, piiMatch{loc[0], loc[1], "email address"})
+		}
+	}
+	for _, loc := range piiCard.FindAllIndex(data, -1) {
+		if paymentCard(string(data[loc[0]:loc[1]])) {
+			found = append(found, piiMatch{loc[0], loc[1], "payment card number"})
+		}
+	}
+	for _, loc := range piiPhone.FindAllIndex(data, -1) {
+		if phoneNumber(string(data[loc[0]:loc[1]])) && !covered(found, loc[0]) {
+			found = append(found, piiMatch{loc[0], loc[1], "phone number"})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].start < found[j].start })
+
+	diags := make([]Diagnostic, 0, len(found))
+	for _, m := range found {
+		msg := fmt.Sprintf("possible %s; use fictitious values in data files", m.kind)
+		diags = append(diags, atOffset(data, m.start, msg))
+	}
+
+	return diags
+}
+
+// piiMatch is a value ScanPII found, from start to end.
+type piiMatch struct {
+	start, end int
+	kind       string
+}
+
+// covered reports whether offset falls inside one of found, such as a phone number
+// pattern matching part of a card number.
+func covered(found []piiMatch, offset int) bool {
+	for _, m := range found {
+		if offset >= m.start && offset < m.end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reservedEmail reports whether address is at a domain reserved for examples.
+func reservedEmail(address string) bool {
+	domain := strings.ToLower(address[strings.LastIndexByte(address, '@')+1:])
+	for _, reserved := range reservedEmailDomains {
+		if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// phoneNumber reports whether a phone-like match has the 8 to 15 digits of a phone
+// number, leaving out North American numbers at the fictional 555 exchange.
+func phoneNumber(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 8 || len(digits) > 15 {
+		return false
+	}
+	national := digits
+	if strings.HasPrefix(s, "+") {
+		national = strings.TrimPrefix(digits, "1")
+	}
+
+	return len(national) != 10 || national[3:6] != "555"
+}
+
+// paymentCard reports whether a run of digits is a payment card number: it has an
+// issuer prefix, passes the Luhn check, and is not a published test number.
+func paymentCard(s string) bool {
+	digits := digitsOf(s)
+
+	return cardPrefix.MatchString(digits) && luhn(digits) && !testCardNumbers[digits]
+}
+
+// digitsOf returns the digits of s.
+func digitsOf(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// luhn reports whether digits pass the Luhn checksum used by payment cards.
+func luhn(digits string) bool {
+	sum := 0
+	for i := range len(digits) {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+
+	return sum%10 == 0
+}
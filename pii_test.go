@@ -0,0 +1,74 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestScanPII(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "csv row",
+			input: "name,email,phone,card\nAda,ada.l@gmail.com,+44 20 7946 0958,4539 1488 0343 6467\n",
+			want: []string{
+				"2:5 possible email address; use fictitious values in data files",
+				"2:21 possible phone number; use fictitious values in data files",
+				"2:38 possible payment card number; use fictitious values in data files",
+			},
+		},
+		{
+			name:  "json values",
+			input: "{\n  \"contact\": {\"tel\": \"(415) 867-5309\", \"cc\": 5425233430109903}\n}",
+			want: []string{
+				"2:23 possible phone number; use fictitious values in data files",
+				"2:46 possible payment card number; use fictitious values in data files",
+			},
+		},
+		{
+			name: "fictitious values",
+			input: `{"email": "ada@example.com", "alt": "bob@mail.test", "tel": "(415) 555-2671", ` +
+				`"card": "4242-4242-4242-4242", "id": 1700000000000, "bad": "4539148803436468"}`,
+		},
+		{
+			name:  "numbers that are not phones",
+			input: "date,version,amount\n2024-01-31,1.2.3,12345678\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range ScanPII([]byte(tt.input)) {
+				got = append(got, fmt.Sprintf("%d:%d %s", d.Line, d.Column, d.Message))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ScanPII() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestWithPIIScan(t *testing.T) {
+	tests := []struct {
+		format   Format
+		input    string
+		warnings int
+	}{
+		{FormatJSONL, "{\"email\": \"ada.l@gmail.com\"}\n{\"email\": \"bob@corp.io\"}\n", 2},
+		{FormatCSV, "email\nada.l@gmail.com\n", 1},
+		{FormatYAML, "email: ada.l@gmail.com\n", 0},
+	}
+
+	for _, tt := range tests {
+		v, _ := NewValidator(tt.format, WithPIIScan())
+		if result := v.ValidateString(tt.input); !result.Valid || len(result.Warnings) != tt.warnings {
+			t.Errorf("%s: Validate() = valid %v, warnings %v, want valid with %d warnings", tt.format, result.Valid,
+				result.Warnings, tt.warnings)
+		}
+	}
+}
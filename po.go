@@ -0,0 +1,317 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// poPluralFormsRe extracts nplurals from a Plural-Forms header.
+var poPluralFormsRe = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+
+// POValidator validates gettext PO (.po/.pot) localization files.
+// It checks msgid/msgstr pairing, string escape sequences, plural blocks (msgid_plural with
+// msgstr[0..n-1], matching the header's Plural-Forms when present), header entry syntax,
+// and duplicate message IDs.
+//
+// Example:
+//
+//	validator := &POValidator{baseValidator{format: FormatPO}}
+//	result := validator.ValidateString("msgid \"Hello\"\nmsgstr \"Bonjour\"\n")
+type POValidator struct {
+	baseValidator
+}
+
+// poEntry accumulates one message while parsing.
+type poEntry struct {
+	line     int
+	context  *string
+	id       *string
+	plural   *string
+	msgstr   *string
+	forms    []string
+	last     *string // target for continuation lines
+	obsolete bool
+}
+
+// Validate checks if the provided byte slice contains a valid PO file.
+//
+// Example:
+//
+//	validator := &POValidator{baseValidator{format: FormatPO}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. "line 12: msgid without msgstr"
+//	}
+func (v *POValidator) Validate(data []byte) Result {
+	err := validatePO(data)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *POValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// poParser holds cross-entry state: seen IDs and the header's plural count.
+type poParser struct {
+	entry    *poEntry
+	seen     map[string]int
+	nplurals int
+	entries  int
+}
+
+func validatePO(data []byte) error {
+	p := &poParser{seen: make(map[string]int)}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	for i, raw := range strings.Split(text, "\n") {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+
+		obsolete := strings.HasPrefix(line, "#~")
+		if obsolete {
+			line = strings.TrimSpace(strings.TrimPrefix(line, "#~"))
+		}
+		if line == "" || (!obsolete && strings.HasPrefix(line, "#")) {
+			// Blank lines and comments end nothing on their own; the next keyword does
+			continue
+		}
+
+		if err := p.parseLine(line, lineNo, obsolete); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+
+	return p.finish()
+}
+
+func (p *poParser) parseLine(line string, lineNo int, obsolete bool) error {
+	if strings.HasPrefix(line, `"`) {
+		if p.entry == nil || p.entry.last == nil {
+			return errors.New("string continuation without a preceding keyword")
+		}
+		s, err := parsePOString(line)
+		if err != nil {
+			return err
+		}
+		*p.entry.last += s
+
+		return nil
+	}
+
+	keyword, rest, _ := strings.Cut(line, " ")
+	value, err := parsePOString(strings.TrimSpace(rest))
+	if err != nil {
+		return fmt.Errorf("%s: %w", keyword, err)
+	}
+
+	switch {
+	case keyword == "msgctxt" || (keyword == "msgid" && (p.entry == nil || p.entry.id != nil)):
+		// A new entry begins; close the previous one first
+		if p.entry != nil {
+			if err := p.closeEntry(); err != nil {
+				return err
+			}
+		}
+		p.entry = &poEntry{line: lineNo, obsolete: obsolete}
+		if keyword == "msgctxt" {
+			p.entry.context = &value
+			p.entry.last = p.entry.context
+		} else {
+			p.entry.id = &value
+			p.entry.last = p.entry.id
+		}
+	case keyword == "msgid":
+		p.entry.id = &value
+		p.entry.last = p.entry.id
+	case keyword == "msgid_plural":
+		if p.entry == nil || p.entry.id == nil || p.entry.msgstr != nil || len(p.entry.forms) > 0 {
+			return errors.New("msgid_plural must directly follow msgid")
+		}
+		p.entry.plural = &value
+		p.entry.last = p.entry.plural
+	case keyword == "msgstr":
+		if p.entry == nil || p.entry.id == nil {
+			return errors.New("msgstr without msgid")
+		}
+		if p.entry.plural != nil {
+			return errors.New("plural entry must use msgstr[0], msgstr[1], ... instead of msgstr")
+		}
+		if p.entry.msgstr != nil {
+			return errors.New("duplicate msgstr")
+		}
+		p.entry.msgstr = &value
+		p.entry.last = p.entry.msgstr
+	case strings.HasPrefix(keyword, "msgstr["):
+		if p.entry == nil || p.entry.id == nil {
+			return errors.New("msgstr without msgid")
+		}
+		if p.entry.plural == nil {
+			return fmt.Errorf("%s used without msgid_plural", keyword)
+		}
+		index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+		if err != nil || !strings.HasSuffix(keyword, "]") {
+			return fmt.Errorf("invalid plural index in %s", keyword)
+		}
+		if index != len(p.entry.forms) {
+			return fmt.Errorf("expected msgstr[%d], got %s", len(p.entry.forms), keyword)
+		}
+		p.entry.forms = append(p.entry.forms, value)
+		p.entry.last = &p.entry.forms[len(p.entry.forms)-1]
+	default:
+		return fmt.Errorf("unknown keyword %q", keyword)
+	}
+
+	return nil
+}
+
+// closeEntry validates a completed entry and records it.
+func (p *poParser) closeEntry() error {
+	e := p.entry
+	p.entry = nil
+	// The continuation target may point into forms, which is about to be dropped
+	e.last = nil
+
+	if e.id == nil {
+		return fmt.Errorf("line %d: msgctxt without msgid", e.line)
+	}
+	if e.msgstr == nil && len(e.forms) == 0 {
+		return fmt.Errorf("line %d: msgid without msgstr", e.line)
+	}
+	if e.obsolete {
+		return nil
+	}
+	p.entries++
+
+	if *e.id == "" && e.context == nil {
+		if p.entries != 1 {
+			return fmt.Errorf("line %d: header entry (empty msgid) must be the first entry", e.line)
+		}
+		if e.msgstr != nil {
+			return p.parseHeader(*e.msgstr, e.line)
+		}
+
+		return nil
+	}
+
+	key := *e.id
+	if e.context != nil {
+		key = *e.context + "\x04" + key
+	}
+	if first, dup := p.seen[key]; dup {
+		return fmt.Errorf("line %d: duplicate message definition (first defined on line %d)", e.line, first)
+	}
+	p.seen[key] = e.line
+
+	if p.nplurals > 0 && e.plural != nil && len(e.forms) != p.nplurals {
+		return fmt.Errorf("line %d: plural entry has %d forms, header Plural-Forms declares %d",
+			e.line, len(e.forms), p.nplurals)
+	}
+
+	return nil
+}
+
+// parseHeader checks "Name: value\n" header lines and records nplurals.
+func (p *poParser) parseHeader(header string, line int) error {
+	for _, field := range strings.Split(strings.TrimSuffix(header, "\n"), "\n") {
+		if field == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(field, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("line %d: malformed header field %q (expected \"Name: value\")", line, field)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Plural-Forms") {
+			m := poPluralFormsRe.FindStringSubmatch(value)
+			if m == nil {
+				return fmt.Errorf("line %d: Plural-Forms header is missing nplurals", line)
+			}
+			p.nplurals, _ = strconv.Atoi(m[1])
+			if p.nplurals < 1 {
+				return fmt.Errorf("line %d: nplurals must be at least 1", line)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *poParser) finish() error {
+	if p.entry != nil {
+		return p.closeEntry()
+	}
+
+	return nil
+}
+
+// parsePOString decodes a C-style double-quoted string.
+func parsePOString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("expected a double-quoted string, got %q", s)
+	}
+	s = s[1 : len(s)-1]
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' {
+			return "", errors.New("unescaped '\"' inside string")
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", errors.New("string ends with a lone backslash")
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\', '\'', '?':
+			b.WriteByte(s[i])
+		case 'a', 'b', 'f', 'v':
+			b.WriteByte(' ')
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			for j := 0; j < 2 && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '7'; j++ {
+				i++
+			}
+		case 'x':
+			if i+1 >= len(s) || !isHexDigit(s[i+1]) {
+				return "", errors.New("invalid \\x escape: expected hex digits")
+			}
+			for i+1 < len(s) && isHexDigit(s[i+1]) {
+				i++
+			}
+		default:
+			return "", fmt.Errorf("invalid escape sequence \\%c", s[i])
+		}
+	}
+
+	return b.String(), nil
+}
+
+// isPO checks for msgid and msgstr keywords at the start of lines.
+func isPO(lines []string) bool {
+	var hasID, hasStr bool
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		hasID = hasID || strings.HasPrefix(line, `msgid "`)
+		hasStr = hasStr || strings.HasPrefix(line, `msgstr "`) || strings.HasPrefix(line, "msgstr[")
+	}
+
+	return hasID && hasStr
+}
@@ -0,0 +1,60 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const poHeader = `# French translations
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+`
+
+func TestPOValidator(t *testing.T) {
+	v := &POValidator{baseValidator{format: FormatPO}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"simple", "msgid \"Hello\"\nmsgstr \"Bonjour\"\n", true, ""},
+		{"header and comments", poHeader + "#: main.c:10\n#, c-format\nmsgid \"%d\"\nmsgstr \"%d\"\n", true, ""},
+		{"continuation lines", "msgid \"\"\n\"Hello \"\n\"world\"\nmsgstr \"Bonjour \"\n\"le monde\"\n", true, ""},
+		{"plural", poHeader + "msgid \"a\"\nmsgid_plural \"b\"\nmsgstr[0] \"x\"\nmsgstr[1] \"y\"\n", true, ""},
+		{"context", "msgctxt \"menu\"\nmsgid \"Open\"\nmsgstr \"Ouvrir\"\n\nmsgid \"Open\"\nmsgstr \"\"\n", true, ""},
+		{"escapes", `msgid "Tab\there \"quoted\" \\ \101 \x41"` + "\nmsgstr \"\"\n", true, ""},
+		{"obsolete", "#~ msgid \"Old\"\n#~ msgstr \"Vieux\"\n", true, ""},
+		{"missing msgstr", "msgid \"Hello\"\n\nmsgid \"Bye\"\nmsgstr \"Au revoir\"\n", false, "line 1: msgid without msgstr"},
+		{"bad escape", "msgid \"Hello\\q\"\nmsgstr \"\"\n", false, "invalid escape"},
+		{"unescaped quote", "msgid \"He said \"hi\"\"\nmsgstr \"\"\n", false, "unescaped"},
+		{"plural without forms", "msgid \"file\"\nmsgid_plural \"files\"\nmsgstr \"x\"\n", false, "msgstr[0]"},
+		{"skipped plural index", "msgid \"a\"\nmsgid_plural \"b\"\nmsgstr[0] \"x\"\nmsgstr[2] \"y\"\n", false, "msgstr[1]"},
+		{"forms mismatch header", poHeader + "msgid \"a\"\nmsgid_plural \"b\"\nmsgstr[0] \"x\"\n", false, "declares 2"},
+		{"index without plural", "msgid \"a\"\nmsgstr[0] \"x\"\n", false, "without msgid_plural"},
+		{"duplicate", "msgid \"a\"\nmsgstr \"x\"\n\nmsgid \"a\"\nmsgstr \"y\"\n", false, "duplicate message"},
+		{"malformed header", "msgid \"\"\nmsgstr \"no colon here\\n\"\n", false, "malformed header"},
+		{"unknown keyword", "msgid \"a\"\nmsgtxt \"x\"\n", false, "unknown keyword"},
+		{"orphan continuation", "\"dangling\"\n", false, "continuation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte(poHeader + "msgid \"a\"\nmsgstr \"b\"\n")); got != FormatPO {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatPO)
+	}
+}
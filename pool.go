@@ -0,0 +1,104 @@
+package serdeval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"sync"
+)
+
+// Services validating many small payloads spend most of each call allocating, so the
+// buffers the hot paths need for the length of one call come from these pools. Buffers
+// that grew past maxPooledBytes or maxPooledLines are dropped instead of pinning the
+// memory of one large input.
+const (
+	maxPooledBytes = 1 << 20
+	maxPooledLines = 1 << 14
+)
+
+var (
+	// bufioPool holds the read buffers of csv.Readers. csv.NewReader uses a *bufio.Reader
+	// of at least the default size as it is instead of wrapping it in a new one.
+	bufioPool = sync.Pool{New: func() any { return bufio.NewReader(nil) }}
+	// bytesPool holds copies of the input, such as CSV with its quote characters swapped.
+	bytesPool = sync.Pool{New: func() any { return new([]byte) }}
+	// linesPool holds the lines DetectFormat and DetectFormatAll split their input into.
+	linesPool = sync.Pool{New: func() any { return new([]string) }}
+)
+
+// pooledCSVReader is a csv.Reader over a read buffer from bufioPool, and the input copy it
+// reads from bytesPool if it needed one.
+type pooledCSVReader struct {
+	*csv.Reader
+	buf  *bufio.Reader
+	data *[]byte
+}
+
+// newPooledCSVReader returns a csv.Reader over data that reuses its read buffer and the
+// slice it returns for each record. Call release once done reading.
+func newPooledCSVReader(data []byte, copied *[]byte) *pooledCSVReader {
+	buf, _ := bufioPool.Get().(*bufio.Reader)
+	buf.Reset(bytes.NewReader(data))
+	r := csv.NewReader(buf)
+	r.ReuseRecord = true
+
+	return &pooledCSVReader{Reader: r, buf: buf, data: copied}
+}
+
+// release returns the reader's buffers to their pools. The reader must not be used after.
+func (r *pooledCSVReader) release() {
+	r.buf.Reset(nil)
+	bufioPool.Put(r.buf)
+	if r.data != nil {
+		putBytes(r.data)
+	}
+}
+
+// getBytes returns a slice from bytesPool of length n.
+func getBytes(n int) *[]byte {
+	b, _ := bytesPool.Get().(*[]byte)
+	if cap(*b) < n {
+		*b = make([]byte, n)
+	}
+	*b = (*b)[:n]
+
+	return b
+}
+
+// putBytes returns b to bytesPool unless it grew too large to keep.
+func putBytes(b *[]byte) {
+	if cap(*b) > maxPooledBytes {
+		return
+	}
+	*b = (*b)[:0]
+	bytesPool.Put(b)
+}
+
+// splitLines splits s at each "\n" like strings.Split, into a slice from linesPool that
+// putLines returns.
+func splitLines(s string) *[]string {
+	lines, _ := linesPool.Get().(*[]string)
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			break
+		}
+		*lines = append(*lines, s[:i])
+		s = s[i+1:]
+	}
+	*lines = append(*lines, s)
+
+	return lines
+}
+
+// putLines returns lines to linesPool unless it grew too large to keep, dropping the
+// strings so the pool does not keep the input alive.
+func putLines(lines *[]string) {
+	if cap(*lines) > maxPooledLines {
+		return
+	}
+	clear(*lines)
+	*lines = (*lines)[:0]
+	linesPool.Put(lines)
+}
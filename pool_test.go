@@ -0,0 +1,87 @@
+package serdeval
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Small payloads like these are validated millions of times an hour by services, where
+// the allocations per call dominate.
+var (
+	benchJSONL = []byte(strings.Repeat(`{"event":"click","user":42,"tags":["a","b"]}`+"\n", 20))
+	benchCSV   = []byte("name,age,city\n" + strings.Repeat("Ada,36,London\n", 20))
+	benchTSV   = []byte("name\tage\tcity\n" + strings.Repeat("Ada\t36\tLondon\n", 20))
+	benchYAML  = []byte("name: app\nversion: 1.2.3\nservices:\n  - web\n  - worker\n")
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []string{"", "a", "a\nb", "a\n\nb\n", "\n", "a\r\nb"}
+	for _, s := range tests {
+		// Reuse one pooled slice after another, as DetectFormat does
+		for i := 0; i < 2; i++ {
+			lines := splitLines(s)
+			if want := strings.Split(s, "\n"); !slices.Equal(*lines, want) {
+				t.Errorf("splitLines(%q) = %q, want %q", s, *lines, want)
+			}
+			putLines(lines)
+		}
+	}
+}
+
+func BenchmarkJSONLValidate(b *testing.B) {
+	v := &JSONLValidator{baseValidator{format: FormatJSONL}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if r := v.Validate(benchJSONL); !r.Valid {
+			b.Fatal(r.Error)
+		}
+	}
+}
+
+func BenchmarkCSVValidate(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		data []byte
+	}{
+		{"sniffed", benchCSV},
+		{"tsv", benchTSV},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			v := &CSVValidator{baseValidator: baseValidator{format: FormatCSV}}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if r := v.Validate(bm.data); !r.Valid {
+					b.Fatal(r.Error)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDetectFormat(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		data []byte
+	}{
+		{"jsonl", benchJSONL},
+		{"csv", benchCSV},
+		{"yaml", benchYAML},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				DetectFormat(bm.data)
+			}
+		})
+	}
+}
+
+func BenchmarkValidateAuto(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if r := ValidateAuto(benchJSONL); !r.Valid {
+			b.Fatal(r.Error)
+		}
+	}
+}
@@ -0,0 +1,1161 @@
+package serdeval
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProtoIDLValidator validates Protocol Buffers schema definitions (.proto source files).
+// It parses proto2, proto3, and editions syntax: imports, packages, options, messages,
+// enums, services, extensions, oneofs, map fields, and reserved ranges. It also reports the
+// errors protoc would give for a single file, such as duplicate field numbers or names,
+// field numbers outside the valid range or inside a reserved one, labels the syntax does not
+// allow, and proto3 enums whose first value is not zero. Imported types are not resolved.
+//
+// Example:
+//
+//	validator := &ProtoIDLValidator{baseValidator{format: FormatProtoIDL}}
+//	result := validator.ValidateString("syntax = \"proto3\";\nmessage User { string name = 1; }")
+type ProtoIDLValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid .proto file.
+//
+// Example:
+//
+//	validator := &ProtoIDLValidator{baseValidator{format: FormatProtoIDL}}
+//	result := validator.Validate([]byte("syntax = \"proto3\";\nmessage A { int32 id = 1; string id = 2; }"))
+//	// result.Error == `line 2:34: field "id" is already defined in message A`
+func (v *ProtoIDLValidator) Validate(data []byte) Result {
+	tokens, err := lexProto(data)
+	if err == nil {
+		err = parseProto(tokens)
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that validates a .proto source string.
+func (v *ProtoIDLValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// protoSyntaxRe matches the syntax or edition statement that opens most .proto files.
+var protoSyntaxRe = regexp.MustCompile(`(?m)^\s*(?:syntax|edition)\s*=\s*["'](?:proto[23]|\d{4})["']\s*;`)
+
+// protoPackageRe and protoDefinitionRe match the package statement and top-level definitions
+// of .proto files that omit the syntax statement.
+var (
+	protoPackageRe    = regexp.MustCompile(`(?m)^\s*package\s+[\w.]+\s*;`)
+	protoDefinitionRe = regexp.MustCompile(`(?m)^\s*(?:message|enum|service)\s+\w+\s*\{`)
+)
+
+// isProtoIDL checks if the content appears to be a .proto schema definition.
+func isProtoIDL(trimmed string) bool {
+	return protoSyntaxRe.MatchString(trimmed) ||
+		protoPackageRe.MatchString(trimmed) && protoDefinitionRe.MatchString(trimmed)
+}
+
+// protoTokenKind classifies the tokens of a .proto file.
+type protoTokenKind int
+
+const (
+	protoEOF protoTokenKind = iota
+	protoIdent
+	protoInt
+	protoFloat
+	protoString
+	protoSymbol
+)
+
+// protoToken is one token of a .proto file. For strings, value holds the decoded contents.
+type protoToken struct {
+	kind  protoTokenKind
+	text  string
+	value string
+	line  int
+	col   int
+}
+
+// String describes the token for error messages.
+func (t protoToken) String() string {
+	switch t.kind {
+	case protoEOF:
+		return "end of file"
+	case protoString:
+		return "string " + t.text
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// protoLexer splits a .proto file into tokens, tracking 1-based lines and byte columns.
+type protoLexer struct {
+	src  []byte
+	pos  int
+	line int
+	col  int
+}
+
+// protoError formats a failure at a line and column of a .proto file.
+func protoError(line, col int, format string, args ...interface{}) error {
+	return &positionError{line: line, column: col, msg: fmt.Sprintf("line %d:%d: ", line, col) +
+		fmt.Sprintf(format, args...)}
+}
+
+// lexProto tokenizes src, skipping whitespace and comments.
+func lexProto(src []byte) ([]protoToken, error) {
+	l := &protoLexer{src: src, line: 1, col: 1}
+	var tokens []protoToken
+	for {
+		if err := l.skipSpace(); err != nil {
+			return nil, err
+		}
+		if l.pos >= len(l.src) {
+			return append(tokens, protoToken{kind: protoEOF, line: l.line, col: l.col}), nil
+		}
+		tok, err := l.token()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+// advance moves past n bytes, none of which is a newline.
+func (l *protoLexer) advance(n int) {
+	l.pos += n
+	l.col += n
+}
+
+// skipSpace skips whitespace, line comments, and block comments.
+func (l *protoLexer) skipSpace() error {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.pos++
+			l.line++
+			l.col = 1
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			l.advance(1)
+		case strings.HasPrefix(string(l.src[l.pos:min(l.pos+2, len(l.src))]), "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		case strings.HasPrefix(string(l.src[l.pos:min(l.pos+2, len(l.src))]), "/*"):
+			if err := l.skipBlockComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// skipBlockComment skips a /* */ comment starting at the current position.
+func (l *protoLexer) skipBlockComment() error {
+	line, col := l.line, l.col
+	l.advance(2)
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '*' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '/' {
+			l.advance(2)
+
+			return nil
+		}
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.col = 0
+		}
+		l.advance(1)
+	}
+
+	return protoError(line, col, "unterminated block comment")
+}
+
+// token reads the token at the current position.
+func (l *protoLexer) token() (protoToken, error) {
+	c := l.src[l.pos]
+	switch {
+	case isProtoLetter(c):
+		return l.span(protoIdent, l.scan(isProtoIdentByte)), nil
+	case c >= '0' && c <= '9', c == '.' && l.pos+1 < len(l.src) && isProtoDigit(l.src[l.pos+1]):
+		return l.number()
+	case c == '"' || c == '\'':
+		return l.str()
+	case strings.IndexByte(";={}[]()<>,.-+:/", c) >= 0:
+		return l.span(protoSymbol, 1), nil
+	}
+
+	return protoToken{}, protoError(l.line, l.col, "unexpected character %q", c)
+}
+
+// scan returns how many bytes from the current position satisfy ok.
+func (l *protoLexer) scan(ok func(byte) bool) int {
+	n := 0
+	for l.pos+n < len(l.src) && ok(l.src[l.pos+n]) {
+		n++
+	}
+
+	return n
+}
+
+// span makes a token of the next n bytes and advances past them.
+func (l *protoLexer) span(kind protoTokenKind, n int) protoToken {
+	tok := protoToken{kind: kind, text: string(l.src[l.pos : l.pos+n]), line: l.line, col: l.col}
+	l.advance(n)
+
+	return tok
+}
+
+// number reads an integer or floating-point literal.
+func (l *protoLexer) number() (protoToken, error) {
+	tok := l.span(protoInt, l.numberLen())
+	text := strings.ToLower(tok.text)
+	hex := strings.HasPrefix(text, "0x")
+	if !hex && strings.ContainsAny(text, ".e") {
+		tok.kind = protoFloat
+		if _, err := strconv.ParseFloat(text, 64); err != nil || strings.ContainsAny(text, "_xp") {
+			return tok, protoError(tok.line, tok.col, "invalid number %s", tok.text)
+		}
+
+		return tok, nil
+	}
+	_, err := strconv.ParseUint(text, 0, 64)
+	if err != nil || strings.Contains(text, "_") || !hex && strings.ContainsAny(text, "ob") {
+		return tok, protoError(tok.line, tok.col, "invalid integer %s", tok.text)
+	}
+
+	return tok, nil
+}
+
+// numberLen returns the length of the numeric literal at the current position, which
+// includes a sign that follows the exponent of a decimal literal.
+func (l *protoLexer) numberLen() int {
+	rest := l.src[l.pos:]
+	hex := len(rest) > 1 && rest[0] == '0' && (rest[1] == 'x' || rest[1] == 'X')
+	n := 0
+	for n < len(rest) {
+		c := rest[n]
+		exponentSign := !hex && (c == '+' || c == '-') && (rest[n-1] == 'e' || rest[n-1] == 'E')
+		if !isProtoIdentByte(c) && c != '.' && !exponentSign {
+			break
+		}
+		n++
+	}
+
+	return n
+}
+
+// protoEscapes maps the single-character escapes of .proto string literals to their values.
+var protoEscapes = map[byte]byte{
+	'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v',
+	'\\': '\\', '\'': '\'', '"': '"', '?': '?',
+}
+
+// str reads a quoted string literal, decoding its escapes into value.
+func (l *protoLexer) str() (protoToken, error) {
+	quote := l.src[l.pos]
+	var value strings.Builder
+	n := 1
+	for {
+		if l.pos+n >= len(l.src) || l.src[l.pos+n] == '\n' {
+			return protoToken{}, protoError(l.line, l.col, "unterminated string")
+		}
+		c := l.src[l.pos+n]
+		if c == quote {
+			break
+		}
+		if c != '\\' {
+			value.WriteByte(c)
+			n++
+
+			continue
+		}
+		size, err := l.escape(n, &value)
+		if err != nil {
+			return protoToken{}, err
+		}
+		n += size
+	}
+
+	tok := l.span(protoString, n+1)
+	tok.value = value.String()
+
+	return tok, nil
+}
+
+// escape decodes the escape sequence at offset n of the current string into value and
+// returns its length.
+func (l *protoLexer) escape(n int, value *strings.Builder) (int, error) {
+	rest := l.src[l.pos+n+1:]
+	if len(rest) == 0 {
+		return 0, protoError(l.line, l.col+n, "unterminated string")
+	}
+	if b, ok := protoEscapes[rest[0]]; ok {
+		value.WriteByte(b)
+
+		return 2, nil
+	}
+
+	prefix, digits, base := numericEscape(rest)
+	if digits == 0 {
+		return 0, protoError(l.line, l.col+n, "invalid escape sequence in string")
+	}
+
+	code, _ := strconv.ParseUint(string(rest[prefix:prefix+digits]), base, 32)
+	if rest[0] == 'u' || rest[0] == 'U' {
+		value.WriteRune(rune(code))
+	} else {
+		value.WriteByte(byte(code))
+	}
+
+	return 1 + prefix + digits, nil
+}
+
+// numericEscape measures the octal, hex, or Unicode escape at the start of rest, which
+// follows the backslash. It returns zero digits if rest does not start a complete escape.
+func numericEscape(rest []byte) (prefix, digits, base int) {
+	switch rest[0] {
+	case 'x', 'X':
+		return 1, scanDigits(rest[1:], 2, isHexDigit), 16
+	case 'u', 'U':
+		want := 4
+		if rest[0] == 'U' {
+			want = 8
+		}
+		if scanDigits(rest[1:], want, isHexDigit) != want {
+			return 1, 0, 16
+		}
+
+		return 1, want, 16
+	}
+
+	return 0, scanDigits(rest, 3, isOctalDigit), 8
+}
+
+// scanDigits counts up to limit leading bytes of s that satisfy ok.
+func scanDigits(s []byte, limit int, ok func(byte) bool) int {
+	n := 0
+	for n < len(s) && n < limit && ok(s[n]) {
+		n++
+	}
+
+	return n
+}
+
+func isProtoLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isProtoIdentByte(c byte) bool {
+	return isProtoLetter(c) || isProtoDigit(c)
+}
+
+func isProtoDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isOctalDigit(c byte) bool {
+	return c >= '0' && c <= '7'
+}
+
+// The syntaxes a .proto file can declare; files without a syntax statement are proto2.
+const (
+	protoSyntax2  = "proto2"
+	protoSyntax3  = "proto3"
+	protoEditions = "editions"
+)
+
+// protoFieldNumbers and protoEnumNumbers bound field numbers and enum values.
+var (
+	protoFieldNumbers = protoRange{start: 1, end: 536870911}
+	protoEnumNumbers  = protoRange{start: math.MinInt32, end: math.MaxInt32}
+)
+
+// protoImplementationNumbers are the field numbers protoc reserves for itself.
+var protoImplementationNumbers = protoRange{start: 19000, end: 19999}
+
+// protoMapKeyTypes lists the types a map field key may have.
+var protoMapKeyTypes = map[string]bool{
+	"int32": true, "int64": true, "uint32": true, "uint64": true, "sint32": true, "sint64": true,
+	"fixed32": true, "fixed64": true, "sfixed32": true, "sfixed64": true, "bool": true, "string": true,
+}
+
+// protoRange is an inclusive range of field or enum numbers, with the token that starts it.
+type protoRange struct {
+	start int64
+	end   int64
+	tok   protoToken
+}
+
+// contains reports whether n is in the range.
+func (r protoRange) contains(n int64) bool {
+	return n >= r.start && n <= r.end
+}
+
+// protoScope holds the names defined in a file or message, which protoc requires to be unique.
+type protoScope struct {
+	desc  string
+	names map[string]bool
+}
+
+func newProtoScope(desc string) *protoScope {
+	return &protoScope{desc: desc, names: map[string]bool{}}
+}
+
+// protoNumbered is a field or enum value with the number assigned to it.
+type protoNumbered struct {
+	name   protoToken
+	number int64
+	tok    protoToken
+}
+
+// protoReservations holds the numbers and names reserved in a message or enum.
+type protoReservations struct {
+	ranges []protoRange
+	names  map[string]bool
+}
+
+// uses reports why a field or enum value may not use its name or number, or "" if it may.
+func (r *protoReservations) uses(f protoNumbered) string {
+	if r.names[f.name.text] {
+		return fmt.Sprintf("name %q is reserved", f.name.text)
+	}
+	for _, rng := range r.ranges {
+		if rng.contains(f.number) {
+			return fmt.Sprintf("number %d is reserved", f.number)
+		}
+	}
+
+	return ""
+}
+
+// protoMessage collects the fields of a message or extend block for the checks that
+// need the whole body, since reserved statements may follow the fields they cover.
+type protoMessage struct {
+	desc       string
+	scope      *protoScope
+	fields     []protoNumbered
+	numbers    map[int64]string
+	reserved   protoReservations
+	extensions []protoRange
+}
+
+func newProtoMessage(desc string, scope *protoScope) *protoMessage {
+	return &protoMessage{desc: desc, scope: scope, numbers: map[int64]string{},
+		reserved: protoReservations{names: map[string]bool{}}}
+}
+
+// protoParser parses the tokens of a .proto file. It keeps the first error and stops there.
+type protoParser struct {
+	tokens []protoToken
+	pos    int
+	syntax string
+	err    error
+}
+
+// parseProto parses a tokenized .proto file and checks it the way protoc checks a single file.
+func parseProto(tokens []protoToken) error {
+	p := &protoParser{tokens: tokens, syntax: protoSyntax2}
+	p.syntaxStatement()
+	scope := newProtoScope("the file")
+	imports := map[string]bool{}
+	hasPackage := false
+	for p.err == nil && p.peek().kind != protoEOF {
+		tok := p.peek()
+		switch {
+		case p.accept(";"):
+		case p.accept("import"):
+			p.importStatement(imports)
+		case p.accept("package"):
+			if hasPackage {
+				p.fail(tok, "multiple package statements")
+			}
+			hasPackage = true
+			p.fullIdent()
+			p.expect(";")
+		case p.is("option"):
+			p.optionStatement()
+		case p.is("service"):
+			p.service(scope)
+		case p.is("syntax"), p.is("edition"):
+			p.fail(tok, "%s statement must come first in the file", tok.text)
+		case !p.definition(scope):
+			p.fail(tok, "expected a top-level statement such as \"message\", found %s", tok)
+		}
+	}
+
+	return p.err
+}
+
+// peek returns the current token.
+func (p *protoParser) peek() protoToken {
+	return p.tokens[p.pos]
+}
+
+// lookahead returns the token n places after the current one.
+func (p *protoParser) lookahead(n int) protoToken {
+	return p.tokens[min(p.pos+n, len(p.tokens)-1)]
+}
+
+// next returns the current token and moves past it, stopping at the end of the file.
+func (p *protoParser) next() protoToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != protoEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+// is reports whether the current token is the keyword or symbol text.
+func (p *protoParser) is(text string) bool {
+	tok := p.peek()
+
+	return tok.text == text && (tok.kind == protoIdent || tok.kind == protoSymbol)
+}
+
+// accept moves past the current token if it is the keyword or symbol text.
+func (p *protoParser) accept(text string) bool {
+	if p.err != nil || !p.is(text) {
+		return false
+	}
+	p.next()
+
+	return true
+}
+
+// expect moves past the keyword or symbol text, failing if it is not the current token.
+func (p *protoParser) expect(text string) {
+	if !p.accept(text) {
+		p.fail(p.peek(), "expected %q, found %s", text, p.peek())
+	}
+}
+
+// fail records an error at tok unless an earlier one has been recorded.
+func (p *protoParser) fail(tok protoToken, format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = protoError(tok.line, tok.col, format, args...)
+	}
+}
+
+// more reports whether a block has another element, consuming its closing brace if not.
+func (p *protoParser) more() bool {
+	switch {
+	case p.err != nil, p.accept("}"):
+		return false
+	case p.peek().kind == protoEOF:
+		p.fail(p.peek(), "expected \"}\", found end of file")
+
+		return false
+	}
+
+	return true
+}
+
+// ident parses an identifier.
+func (p *protoParser) ident() protoToken {
+	tok := p.peek()
+	if tok.kind != protoIdent {
+		p.fail(tok, "expected identifier, found %s", tok)
+
+		return tok
+	}
+
+	return p.next()
+}
+
+// fullIdent parses a dotted name such as foo.bar.Baz.
+func (p *protoParser) fullIdent() string {
+	name := p.ident().text
+	for p.accept(".") {
+		name += "." + p.ident().text
+	}
+
+	return name
+}
+
+// typeName parses a message, enum, or scalar type name, which may be fully qualified.
+func (p *protoParser) typeName() string {
+	if p.accept(".") {
+		return "." + p.fullIdent()
+	}
+
+	return p.fullIdent()
+}
+
+// integer parses an integer literal, allowing a minus sign if signed, and returns its value
+// along with the token it starts at.
+func (p *protoParser) integer(signed bool) (int64, protoToken) {
+	tok := p.peek()
+	negative := signed && p.accept("-")
+	num := p.peek()
+	if num.kind != protoInt {
+		p.fail(num, "expected integer, found %s", num)
+
+		return 0, tok
+	}
+	p.next()
+	n, err := strconv.ParseInt(num.text, 0, 64)
+	if err != nil {
+		p.fail(num, "integer %s is too large", num.text)
+	}
+	if negative {
+		n = -n
+	}
+
+	return n, tok
+}
+
+// declare adds the name at tok to scope, failing if it is already defined there.
+func (p *protoParser) declare(scope *protoScope, kind string, tok protoToken) {
+	if p.err != nil {
+		return
+	}
+	if scope.names[tok.text] {
+		p.fail(tok, "%s %q is already defined in %s", kind, tok.text, scope.desc)
+	}
+	scope.names[tok.text] = true
+}
+
+// syntaxStatement parses the optional syntax or edition statement that opens the file.
+func (p *protoParser) syntaxStatement() {
+	kind := p.peek()
+	if !p.accept("syntax") && !p.accept("edition") {
+		return
+	}
+	p.expect("=")
+	value := p.peek()
+	if value.kind != protoString {
+		p.fail(value, "expected string, found %s", value)
+
+		return
+	}
+	p.next()
+
+	switch {
+	case kind.text == "syntax" && (value.value == protoSyntax2 || value.value == protoSyntax3):
+		p.syntax = value.value
+	case kind.text == "edition" && len(value.value) == 4 && value.value >= "2023" && value.value <= "9999":
+		p.syntax = protoEditions
+	default:
+		p.fail(value, "unrecognized %s %s", kind.text, value.text)
+	}
+	p.expect(";")
+}
+
+// importStatement parses the rest of an import statement.
+func (p *protoParser) importStatement(imports map[string]bool) {
+	_ = p.accept("weak") || p.accept("public")
+	path := p.peek()
+	if path.kind != protoString {
+		p.fail(path, "expected import path, found %s", path)
+
+		return
+	}
+	p.next()
+	if imports[path.value] {
+		p.fail(path, "import %s is listed twice", path.text)
+	}
+	imports[path.value] = true
+	p.expect(";")
+}
+
+// optionStatement parses "option name = value;" and returns the name and value.
+func (p *protoParser) optionStatement() (string, protoToken) {
+	p.next()
+	name, value := p.option()
+	p.expect(";")
+
+	return name, value
+}
+
+// option parses "name = value", where name may include extension names in parentheses.
+func (p *protoParser) option() (string, protoToken) {
+	var name strings.Builder
+	for {
+		if p.accept("(") {
+			name.WriteString("(" + p.typeName() + ")")
+			p.expect(")")
+		} else {
+			name.WriteString(p.ident().text)
+		}
+		if !p.accept(".") {
+			break
+		}
+		name.WriteByte('.')
+	}
+	p.expect("=")
+
+	return name.String(), p.constant()
+}
+
+// constant parses an option value: a scalar, an identifier, or a message literal in braces.
+func (p *protoParser) constant() protoToken {
+	tok := p.next()
+	switch {
+	case tok.kind == protoString:
+		// Adjacent string literals are concatenated.
+		for p.peek().kind == protoString {
+			p.next()
+		}
+	case tok.kind == protoInt, tok.kind == protoFloat:
+	case tok.kind == protoIdent:
+		for p.accept(".") {
+			p.ident()
+		}
+	case tok.kind == protoSymbol && (tok.text == "-" || tok.text == "+"):
+		num := p.next()
+		if num.kind != protoInt && num.kind != protoFloat && num.text != "inf" && num.text != "nan" {
+			p.fail(num, "expected number, found %s", num)
+		}
+	case tok.kind == protoSymbol && tok.text == "{":
+		p.aggregate(tok)
+	default:
+		p.fail(tok, "expected option value, found %s", tok)
+	}
+
+	return tok
+}
+
+// aggregate skips a message literal whose opening brace open has been read.
+func (p *protoParser) aggregate(open protoToken) {
+	for depth := 1; depth > 0; {
+		tok := p.next()
+		switch {
+		case tok.kind == protoEOF:
+			p.fail(open, "unterminated message literal")
+
+			return
+		case tok.kind == protoSymbol && tok.text == "{":
+			depth++
+		case tok.kind == protoSymbol && tok.text == "}":
+			depth--
+		}
+	}
+}
+
+// fieldOptions parses an optional bracketed list of field or enum value options.
+func (p *protoParser) fieldOptions() {
+	if !p.accept("[") {
+		return
+	}
+	for {
+		tok := p.peek()
+		if name, _ := p.option(); name == "default" && p.syntax == protoSyntax3 {
+			p.fail(tok, "default values are not allowed in proto3")
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect("]")
+}
+
+// definition parses a message, enum, or extend block in scope, reporting false if the
+// current token starts none of them.
+func (p *protoParser) definition(scope *protoScope) bool {
+	switch {
+	case p.is("message"):
+		p.message(scope)
+	case p.is("enum"):
+		p.enum(scope)
+	case p.is("extend"):
+		p.extend(scope)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// message parses a message definition.
+func (p *protoParser) message(scope *protoScope) {
+	p.next()
+	name := p.ident()
+	p.declare(scope, "message", name)
+	p.expect("{")
+	p.messageBody(name.text)
+}
+
+// messageBody parses the elements of a message up to its closing brace.
+func (p *protoParser) messageBody(name string) {
+	m := newProtoMessage("message "+name, newProtoScope("message "+name))
+	for p.more() {
+		switch {
+		case p.accept(";"):
+		case p.is("option"):
+			p.optionStatement()
+		case p.is("oneof"):
+			p.oneof(m)
+		case p.is("reserved"):
+			p.reserved(&m.reserved, protoFieldNumbers)
+		case p.is("extensions"):
+			p.extensions(m)
+		case p.is("map") && p.lookahead(1).text == "<":
+			p.mapField(m)
+		case !p.definition(m.scope):
+			p.field(m, false)
+		}
+	}
+	p.checkMessage(m)
+}
+
+// field parses a field of a message, oneof, or extend block, including proto2 groups.
+func (p *protoParser) field(m *protoMessage, inOneof bool) {
+	label := p.peek()
+	hasLabel := p.accept("optional") || p.accept("required") || p.accept("repeated")
+	p.checkLabel(label, hasLabel, inOneof)
+	switch {
+	case p.is("group"):
+		p.group(m)
+
+		return
+	case hasLabel && p.is("map") && p.lookahead(1).text == "<":
+		p.fail(label, "map fields must not have labels")
+	}
+
+	p.typeName()
+	name := p.ident()
+	p.declare(m.scope, "field", name)
+	p.fieldNumber(m, name)
+	p.fieldOptions()
+	p.expect(";")
+}
+
+// checkLabel reports a field label, or a missing one, that the syntax does not allow.
+func (p *protoParser) checkLabel(label protoToken, hasLabel, inOneof bool) {
+	switch {
+	case hasLabel && inOneof:
+		p.fail(label, "fields in a oneof must not have labels")
+	case hasLabel && p.syntax == protoSyntax3 && label.text == "required":
+		p.fail(label, "required fields are not allowed in proto3")
+	case hasLabel && p.syntax == protoEditions && label.text != "repeated":
+		p.fail(label, "label %q is not allowed in editions; use features.field_presence", label.text)
+	case !hasLabel && !inOneof && p.syntax == protoSyntax2:
+		p.fail(label, "expected \"optional\", \"required\", or \"repeated\" before the field type")
+	}
+}
+
+// fieldNumber parses "= number" for the field name and records the number in m.
+func (p *protoParser) fieldNumber(m *protoMessage, name protoToken) {
+	p.expect("=")
+	n, tok := p.integer(false)
+	switch {
+	case p.err != nil:
+		return
+	case !protoFieldNumbers.contains(n):
+		p.fail(tok, "field number %d is out of range %d to %d", n, protoFieldNumbers.start, protoFieldNumbers.end)
+	case protoImplementationNumbers.contains(n):
+		p.fail(tok, "field number %d is reserved for the protocol buffer implementation", n)
+	case m.numbers[n] != "":
+		p.fail(tok, "field number %d is already used by %q in %s", n, m.numbers[n], m.desc)
+	}
+	m.numbers[n] = name.text
+	m.fields = append(m.fields, protoNumbered{name: name, number: n, tok: tok})
+}
+
+// group parses a proto2 group, which defines both a nested message and a field of that type.
+func (p *protoParser) group(m *protoMessage) {
+	tok := p.next()
+	if p.syntax != protoSyntax2 {
+		p.fail(tok, "groups are not allowed in %s; use a nested message", p.syntax)
+	}
+	name := p.ident()
+	if p.err == nil && (name.text[0] < 'A' || name.text[0] > 'Z') {
+		p.fail(name, "group name %q must start with a capital letter", name.text)
+	}
+	p.declare(m.scope, "message", name)
+	field := name
+	field.text = strings.ToLower(name.text)
+	p.declare(m.scope, "field", field)
+	p.fieldNumber(m, field)
+	p.fieldOptions()
+	p.expect("{")
+	p.messageBody(name.text)
+}
+
+// mapField parses a map<key, value> field.
+func (p *protoParser) mapField(m *protoMessage) {
+	p.next()
+	p.expect("<")
+	key := p.peek()
+	if keyType := p.typeName(); p.err == nil && !protoMapKeyTypes[keyType] {
+		p.fail(key, "map key type %s is not allowed; use an integer, bool, or string type", keyType)
+	}
+	p.expect(",")
+	p.typeName()
+	p.expect(">")
+	name := p.ident()
+	p.declare(m.scope, "field", name)
+	p.fieldNumber(m, name)
+	p.fieldOptions()
+	p.expect(";")
+}
+
+// oneof parses a oneof, whose fields belong to the enclosing message.
+func (p *protoParser) oneof(m *protoMessage) {
+	p.next()
+	name := p.ident()
+	p.declare(m.scope, "oneof", name)
+	p.expect("{")
+	fields := 0
+	for p.more() {
+		tok := p.peek()
+		switch {
+		case p.accept(";"):
+		case p.is("option"):
+			p.optionStatement()
+		case p.is("map") && p.lookahead(1).text == "<":
+			p.fail(tok, "map fields are not allowed in a oneof")
+		default:
+			p.field(m, true)
+			fields++
+		}
+	}
+	if fields == 0 {
+		p.fail(name, "oneof %q must have at least one field", name.text)
+	}
+}
+
+// reserved parses a reserved statement of numbers within bounds, or of names.
+func (p *protoParser) reserved(r *protoReservations, bounds protoRange) {
+	p.next()
+	if tok := p.peek(); tok.kind == protoString || tok.kind == protoIdent {
+		p.reservedNames(r)
+	} else {
+		r.ranges = append(r.ranges, p.ranges(bounds)...)
+	}
+	p.expect(";")
+}
+
+// reservedNames parses the names of a reserved statement: strings, or identifiers in editions.
+func (p *protoParser) reservedNames(r *protoReservations) {
+	for {
+		tok := p.next()
+		name := tok.text
+		switch {
+		case tok.kind == protoString && p.syntax != protoEditions:
+			name = tok.value
+		case tok.kind == protoString:
+			p.fail(tok, "reserved names must be identifiers in editions")
+		case tok.kind == protoIdent && p.syntax != protoEditions:
+			p.fail(tok, "reserved names must be quoted strings in %s", p.syntax)
+		case tok.kind != protoIdent:
+			p.fail(tok, "expected reserved name, found %s", tok)
+		}
+		if p.err == nil && r.names[name] {
+			p.fail(tok, "name %q is reserved twice", name)
+		}
+		r.names[name] = true
+		if !p.accept(",") {
+			return
+		}
+	}
+}
+
+// ranges parses a comma-separated list of numbers and "start to end" ranges within bounds,
+// where end may be max.
+func (p *protoParser) ranges(bounds protoRange) []protoRange {
+	var ranges []protoRange
+	for {
+		start, tok := p.integer(bounds.start < 0)
+		end := start
+		if p.accept("to") {
+			if p.accept("max") {
+				end = bounds.end
+			} else {
+				end, _ = p.integer(bounds.start < 0)
+			}
+		}
+		switch {
+		case p.err != nil:
+		case end < start:
+			p.fail(tok, "range end %d is before its start %d", end, start)
+		case !bounds.contains(start) || !bounds.contains(end):
+			p.fail(tok, "range %d to %d is outside %d to %d", start, end, bounds.start, bounds.end)
+		}
+		ranges = append(ranges, protoRange{start: start, end: end, tok: tok})
+		if !p.accept(",") {
+			return ranges
+		}
+	}
+}
+
+// extensions parses an extensions statement, which proto3 does not allow.
+func (p *protoParser) extensions(m *protoMessage) {
+	tok := p.next()
+	if p.syntax == protoSyntax3 {
+		p.fail(tok, "extension ranges are not allowed in proto3")
+	}
+	m.extensions = append(m.extensions, p.ranges(protoFieldNumbers)...)
+	p.fieldOptions()
+	p.expect(";")
+}
+
+// checkMessage reports fields that use a reserved name or number or an extension number.
+func (p *protoParser) checkMessage(m *protoMessage) {
+	for _, f := range m.fields {
+		if problem := m.reserved.uses(f); problem != "" {
+			p.fail(f.name, "field %q cannot be used in %s: %s", f.name.text, m.desc, problem)
+		}
+		for _, r := range m.extensions {
+			if r.contains(f.number) {
+				p.fail(f.tok, "field number %d of %q is in an extension range of %s", f.number, f.name.text, m.desc)
+			}
+		}
+	}
+	for _, r := range m.extensions {
+		for _, reserved := range m.reserved.ranges {
+			if r.start <= reserved.end && reserved.start <= r.end {
+				p.fail(r.tok, "extension range %d to %d overlaps reserved range %d to %d",
+					r.start, r.end, reserved.start, reserved.end)
+			}
+		}
+	}
+}
+
+// extend parses an extend block. Its fields are defined in the enclosing scope.
+func (p *protoParser) extend(scope *protoScope) {
+	p.next()
+	extendee := p.typeName()
+	p.expect("{")
+	m := newProtoMessage("extensions of "+extendee, scope)
+	for p.more() {
+		if !p.accept(";") {
+			p.field(m, false)
+		}
+	}
+}
+
+// enum parses an enum definition. As in C++, its values are defined in the enclosing scope.
+func (p *protoParser) enum(scope *protoScope) {
+	p.next()
+	name := p.ident()
+	p.declare(scope, "enum", name)
+	p.expect("{")
+	var values []protoNumbered
+	reserved := protoReservations{names: map[string]bool{}}
+	allowAlias := false
+	for p.more() {
+		switch {
+		case p.accept(";"):
+		case p.is("option"):
+			if option, value := p.optionStatement(); option == "allow_alias" {
+				allowAlias = value.text == "true"
+			}
+		case p.is("reserved"):
+			p.reserved(&reserved, protoEnumNumbers)
+		default:
+			values = append(values, p.enumValue(scope))
+		}
+	}
+	p.checkEnum(name, values, &reserved, allowAlias)
+}
+
+// enumValue parses "NAME = number [options];".
+func (p *protoParser) enumValue(scope *protoScope) protoNumbered {
+	name := p.ident()
+	p.declare(scope, "enum value", name)
+	p.expect("=")
+	n, tok := p.integer(true)
+	if p.err == nil && !protoEnumNumbers.contains(n) {
+		p.fail(tok, "enum value %d is out of the int32 range", n)
+	}
+	p.fieldOptions()
+	p.expect(";")
+
+	return protoNumbered{name: name, number: n, tok: tok}
+}
+
+// checkEnum reports an enum that is empty, starts at a non-zero value where the syntax
+// requires zero, reuses numbers without allow_alias, or uses reserved names or numbers.
+func (p *protoParser) checkEnum(name protoToken, values []protoNumbered, reserved *protoReservations,
+	allowAlias bool) {
+	if len(values) == 0 {
+		p.fail(name, "enum %q must have at least one value", name.text)
+
+		return
+	}
+	if p.syntax != protoSyntax2 && values[0].number != 0 {
+		p.fail(values[0].tok, "the first value of enum %q must be zero in %s", name.text, p.syntax)
+	}
+
+	seen := make(map[int64]string, len(values))
+	for _, v := range values {
+		if prev, ok := seen[v.number]; ok && !allowAlias {
+			p.fail(v.tok, "enum value number %d is already used by %q; set option allow_alias = true to allow this",
+				v.number, prev)
+		}
+		seen[v.number] = v.name.text
+		if problem := reserved.uses(v); problem != "" {
+			p.fail(v.name, "enum value %q cannot be used in enum %s: %s", v.name.text, name.text, problem)
+		}
+	}
+}
+
+// service parses a service definition.
+func (p *protoParser) service(scope *protoScope) {
+	p.next()
+	name := p.ident()
+	p.declare(scope, "service", name)
+	p.expect("{")
+	methods := newProtoScope("service " + name.text)
+	for p.more() {
+		tok := p.peek()
+		switch {
+		case p.accept(";"):
+		case p.is("option"):
+			p.optionStatement()
+		case p.accept("rpc"):
+			p.rpc(methods)
+		default:
+			p.fail(tok, "expected \"rpc\" or \"option\", found %s", tok)
+		}
+	}
+}
+
+// rpc parses the rest of an rpc method definition.
+func (p *protoParser) rpc(methods *protoScope) {
+	name := p.ident()
+	p.declare(methods, "method", name)
+	p.rpcType()
+	p.expect("returns")
+	p.rpcType()
+	if !p.accept("{") {
+		p.expect(";")
+
+		return
+	}
+	for p.more() {
+		tok := p.peek()
+		switch {
+		case p.accept(";"):
+		case p.is("option"):
+			p.optionStatement()
+		default:
+			p.fail(tok, "expected \"option\", found %s", tok)
+		}
+	}
+}
+
+// rpcType parses a parenthesized request or response type, which may be streamed.
+func (p *protoParser) rpcType() {
+	p.expect("(")
+	if next := p.lookahead(1); p.is("stream") && next.text != ")" && next.text != "." {
+		p.next()
+	}
+	p.typeName()
+	p.expect(")")
+}
@@ -0,0 +1,200 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const protoIDLExample = `// User service.
+syntax = "proto3";
+
+package acme.v1;
+
+import "google/protobuf/timestamp.proto";
+import public "acme/v1/common.proto";
+
+option go_package = "github.com/acme/api/v1;apiv1";
+option (acme.v1.file_meta) = { owner: "team-a" labels: { key: "tier" value: "1" } };
+
+/* A registered user. */
+message User {
+  reserved 4, 8 to 10;
+  reserved "legacy_name";
+
+  string id = 1 [json_name = "userId", (acme.v1.field_meta).pii = true];
+  optional string email = 2;
+  repeated string tags = 3;
+  map<string, int64> counters = 5;
+  google.protobuf.Timestamp created_at = 6;
+  oneof contact {
+    string phone = 7;
+    Address address = 11;
+  }
+
+  message Address {
+    string line1 = 1;
+  }
+
+  enum Status {
+    option allow_alias = true;
+    STATUS_UNSPECIFIED = 0;
+    STATUS_ACTIVE = 1;
+    STATUS_ENABLED = 1;
+  }
+  Status status = 12;
+  double ratio = 13 [deprecated = true];
+}
+
+service Users {
+  option (acme.v1.service_meta) = "users";
+  rpc GetUser(GetUserRequest) returns (User);
+  rpc Watch(stream .acme.v1.GetUserRequest) returns (stream User) {
+    option idempotency_level = NO_SIDE_EFFECTS;
+  }
+}
+
+message GetUserRequest { string id = 1; }
+`
+
+const proto2Example = `syntax = "proto2";
+package legacy;
+
+message Order {
+  required int64 id = 1;
+  optional string note = 2 [default = "none\x21"];
+  optional float score = 3 [default = -inf];
+  repeated group Item = 4 {
+    required string sku = 1;
+  }
+  extensions 100 to max;
+}
+
+extend Order {
+  optional string channel = 100;
+}
+
+enum Priority {
+  LOW = 1;
+  HIGH = 2;
+  reserved -5 to -1, 10;
+  reserved "URGENT";
+}
+`
+
+func TestProtoIDLValidator(t *testing.T) {
+	v := &ProtoIDLValidator{baseValidator{format: FormatProtoIDL}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"proto3", protoIDLExample, true, ""},
+		{"proto2", proto2Example, true, ""},
+		{"no syntax statement", "package a;\nmessage M { optional int32 x = 1; }", true, ""},
+		{"editions", "edition = \"2023\";\nmessage M { int32 x = 1 [features.field_presence = IMPLICIT]; }\n" +
+			"message N { reserved foo, bar; }", true, ""},
+		{"hex and octal numbers", "syntax = \"proto3\";\nmessage M { int32 a = 0x1F; int32 b = 010; }", true, ""},
+		{"empty", "", true, ""},
+
+		{"duplicate field name", "syntax = \"proto3\";\nmessage A { int32 id = 1; string id = 2; }", false,
+			`line 2:34: field "id" is already defined in message A`},
+		{"duplicate field number", "syntax = \"proto3\";\nmessage A { int32 a = 1; int32 b = 1; }", false,
+			`line 2:36: field number 1 is already used by "a" in message A`},
+		{"field number zero", "syntax = \"proto3\";\nmessage A { int32 a = 0; }", false,
+			"field number 0 is out of range 1 to 536870911"},
+		{"implementation range", "syntax = \"proto3\";\nmessage A { int32 a = 19500; }", false,
+			"reserved for the protocol buffer implementation"},
+		{"reserved number", "syntax = \"proto3\";\nmessage A {\n  int32 a = 9;\n  reserved 8 to 10;\n}", false,
+			`line 3:9: field "a" cannot be used in message A: number 9 is reserved`},
+		{"reserved name", "syntax = \"proto3\";\nmessage A { reserved \"a\"; int32 a = 1; }", false,
+			`name "a" is reserved`},
+		{"required in proto3", "syntax = \"proto3\";\nmessage A { required int32 a = 1; }", false,
+			"line 2:13: required fields are not allowed in proto3"},
+		{"missing label in proto2", "syntax = \"proto2\";\nmessage A { int32 a = 1; }", false,
+			`line 2:13: expected "optional", "required", or "repeated"`},
+		{"label in editions", "edition = \"2023\";\nmessage A { optional int32 a = 1; }", false,
+			`label "optional" is not allowed in editions`},
+		{"label in oneof", "syntax = \"proto3\";\nmessage A { oneof o { repeated int32 a = 1; } }", false,
+			"fields in a oneof must not have labels"},
+		{"default in proto3", "syntax = \"proto3\";\nmessage A { int32 a = 1 [default = 5]; }", false,
+			"default values are not allowed in proto3"},
+		{"group in proto3", "syntax = \"proto3\";\nmessage A { group G = 1 { } }", false,
+			"groups are not allowed in proto3"},
+		{"extensions in proto3", "syntax = \"proto3\";\nmessage A { extensions 100 to 200; }", false,
+			"extension ranges are not allowed in proto3"},
+		{"field in extension range", "message A { optional int32 a = 150; extensions 100 to 200; }", false,
+			"is in an extension range of message A"},
+		{"bad map key", "syntax = \"proto3\";\nmessage A { map<double, string> m = 1; }", false,
+			"line 2:17: map key type double is not allowed"},
+		{"first enum value not zero", "syntax = \"proto3\";\nenum E { A = 1; }", false,
+			`line 2:14: the first value of enum "E" must be zero in proto3`},
+		{"enum alias", "syntax = \"proto3\";\nenum E { A = 0; B = 0; }", false,
+			`enum value number 0 is already used by "A"`},
+		{"enum value scoping", "syntax = \"proto3\";\nenum E { A = 0; }\nenum F { A = 0; }", false,
+			`line 3:10: enum value "A" is already defined in the file`},
+		{"duplicate message", "message A {}\nmessage A {}", false, `line 2:9: message "A" is already defined in the file`},
+		{"duplicate rpc", "service S { rpc M(A) returns (B); rpc M(A) returns (B); }", false,
+			`method "M" is already defined in service S`},
+		{"unknown syntax", "syntax = \"proto4\";", false, `line 1:10: unrecognized syntax "proto4"`},
+		{"syntax not first", "package a;\nsyntax = \"proto3\";", false, "syntax statement must come first"},
+		{"two packages", "package a;\npackage b;", false, "line 2:1: multiple package statements"},
+		{"duplicate import", "import \"a.proto\";\nimport \"a.proto\";", false, `import "a.proto" is listed twice`},
+		{"missing semicolon", "syntax = \"proto3\";\nmessage A { int32 a = 1 }", false,
+			`line 2:25: expected ";", found "}"`},
+		{"unclosed message", "syntax = \"proto3\";\nmessage A {\n  int32 a = 1;\n", false,
+			`line 4:1: expected "}", found end of file`},
+		{"unterminated string", "syntax = \"proto3;\n", false, "line 1:10: unterminated string"},
+		{"unterminated comment", "/* license\n", false, "line 1:1: unterminated block comment"},
+		{"bad character", "message A { int32 a = 1; } #", false, `line 1:28: unexpected character '#'`},
+		{"textproto", "name: \"x\"\nid: 5", false, `expected a top-level statement such as "message"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestProtoIDLDiagnostics(t *testing.T) {
+	result := (&ProtoIDLValidator{baseValidator{format: FormatProtoIDL}}).
+		ValidateString("syntax = \"proto3\";\nmessage A {\n  int32 a = 1;\n  string a = 2;\n}")
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Line != 4 || result.Diagnostics[0].Column != 10 {
+		t.Errorf("Diagnostics = %+v, want line 4, column 10", result.Diagnostics)
+	}
+}
+
+func TestDetectProtoIDL(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		want     Format
+	}{
+		{"syntax statement", "", protoIDLExample, FormatProtoIDL},
+		{"package and message", "", "package a.b;\n\nmessage M {\n  optional int32 x = 1;\n}\n", FormatProtoIDL},
+		{"proto extension", "api/v1/user.proto", "", FormatProtoIDL},
+		{"textproto extension", "config.textproto", "", FormatProtobuf},
+		{"pbtxt extension", "config.pbtxt", "", FormatProtobuf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectFormat([]byte(tt.input))
+			if tt.filename != "" {
+				got = DetectFormatFromFilename(tt.filename)
+			}
+			if got != tt.want {
+				t.Errorf("detected %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	// Register the remaining well-known types so Any values can name them
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// protoPositionRe matches the "(line N:M): " position protobuf parse errors embed.
+var protoPositionRe = regexp.MustCompile(`\(line \d+:\d+\):\s*`)
+
+// ProtoJSONValidator validates payloads written in the protobuf JSON mapping, such as
+// gRPC-gateway request and response fixtures.
+//
+// With WithProtoMessage the payload is decoded as the given message type, so unknown fields,
+// wrong field types, and bad well-known-type encodings (e.g. a Timestamp that is not RFC 3339
+// or a Duration without the "s" suffix) are reported. Without a message type it checks that the
+// payload is a JSON object and that every Any value whose "@type" names a well-known type
+// is encoded correctly.
+//
+// Example:
+//
+//	descriptors, _ := os.ReadFile("api.pb") // protoc --include_imports --descriptor_set_out=api.pb
+//	validator, _ := NewValidator(FormatProtoJSON, WithProtoMessage(descriptors, "acme.v1.CreateUserRequest"))
+//	result := validator.ValidateString(`{"user": {"displayName": "Ada"}}`)
+type ProtoJSONValidator struct {
+	baseValidator
+	message  protoreflect.MessageDescriptor
+	resolver *dynamicpb.Types
+}
+
+// configure resolves the message type requested with WithProtoMessage.
+func (v *ProtoJSONValidator) configure(o options) error {
+	if o.protoMessage == "" {
+		return nil
+	}
+
+	var err error
+	v.message, v.resolver, err = resolveProtoMessage(o.protoDescriptorSet, o.protoMessage)
+
+	return err
+}
+
+// configure resolves the message type requested with WithProtoMessage.
+func (v *ProtobufValidator) configure(o options) error {
+	if o.protoMessage == "" {
+		return nil
+	}
+
+	var err error
+	v.message, v.resolver, err = resolveProtoMessage(o.protoDescriptorSet, o.protoMessage)
+
+	return err
+}
+
+// resolveProtoMessage finds messageName in descriptorSet, a serialized FileDescriptorSet,
+// and returns it with a resolver for the set's types (used for Any values and extensions).
+func resolveProtoMessage(
+	descriptorSet []byte, messageName string,
+) (protoreflect.MessageDescriptor, *dynamicpb.Types, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(descriptorSet, set); err != nil {
+		return nil, nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	addWellKnownDependencies(set)
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("message %s not found in descriptor set", messageName)
+	}
+	message, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a message type", messageName)
+	}
+
+	return message, dynamicpb.NewTypes(files), nil
+}
+
+// addWellKnownDependencies appends the google/protobuf/*.proto files a descriptor set imports
+// but does not contain, so sets built without --include_imports still resolve.
+func addWellKnownDependencies(set *descriptorpb.FileDescriptorSet) {
+	have := make(map[string]bool, len(set.File))
+	for _, file := range set.File {
+		have[file.GetName()] = true
+	}
+
+	// The slice grows as dependencies are added, so index rather than range
+	for i := 0; i < len(set.File); i++ {
+		for _, dep := range set.File[i].GetDependency() {
+			if have[dep] {
+				continue
+			}
+			if file, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+				have[dep] = true
+			}
+		}
+	}
+}
+
+// Validate checks if the provided byte slice contains a valid protobuf JSON payload.
+//
+// Example:
+//
+//	validator := &ProtoJSONValidator{baseValidator: baseValidator{format: FormatProtoJSON}}
+//	result := validator.ValidateString(`{"@type": "type.googleapis.com/google.protobuf.Duration", "value": "1.5s"}`)
+func (v *ProtoJSONValidator) Validate(data []byte) Result {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return Result{
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid JSON: " + err.Error(),
+			Suggestion: suggestFix(FormatJSON, data, err.Error()),
+		}.locate(data, err)
+	}
+
+	var err error
+	if v.message != nil {
+		msg := dynamicpb.NewMessage(v.message)
+		err = protojson.UnmarshalOptions{Resolver: v.resolver}.Unmarshal(data, msg)
+	} else if _, ok := payload.(map[string]interface{}); !ok {
+		err = errors.New("protobuf JSON payload must be a JSON object")
+	} else {
+		err = checkWellKnownAnys(payload, "")
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *ProtoJSONValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// checkWellKnownAnys decodes every object whose "@type" resolves in the global registry as an Any.
+// Types that cannot be resolved without a descriptor set are skipped.
+func checkWellKnownAnys(value interface{}, path string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if typeURL, ok := v["@type"].(string); ok {
+			if _, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL); err == nil {
+				raw, _ := json.Marshal(v)
+				if err = protojson.Unmarshal(raw, &anypb.Any{}); err != nil {
+					// The position protojson reports is within the re-encoded value, not the input
+					return fmt.Errorf("%s: %s", displayPath(path), protoPositionRe.ReplaceAllString(err.Error(), ""))
+				}
+
+				return nil
+			}
+		}
+		for _, key := range sortedKeys(v) {
+			if err := checkWellKnownAnys(v[key], path+"/"+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := checkWellKnownAnys(item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// displayPath shows the document root as "/" rather than an empty path.
+func displayPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
@@ -0,0 +1,158 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// testDescriptorSet returns a descriptor set for acme.v1.User; timestamp.proto is
+// deliberately left out to exercise well-known dependency resolution.
+func testDescriptorSet(t *testing.T) []byte {
+	t.Helper()
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type,
+		label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   typ.Enum(),
+			Label:  label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+
+		return f
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:       proto.String("acme/v1/user.proto"),
+		Package:    proto.String("acme.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("display_name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				field("created_at", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, optional,
+					".google.protobuf.Timestamp"),
+				field("id", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				field("tags", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING,
+					descriptorpb.FieldDescriptorProto_LABEL_REPEATED, ""),
+			},
+		}},
+	}}}
+
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestProtoJSONValidator(t *testing.T) {
+	typed, err := NewValidator(FormatProtoJSON, WithProtoMessage(testDescriptorSet(t), "acme.v1.User"))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	untyped, _ := NewValidator(FormatProtoJSON)
+
+	tests := []struct {
+		name    string
+		v       Validator
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"typed camelCase", typed, `{"displayName": "Ada", "createdAt": "2024-01-31T12:00:00Z", "id": "42"}`, true, ""},
+		{"typed proto names", typed, `{"display_name": "Ada", "tags": ["a", "b"]}`, true, ""},
+		{"typed unknown field", typed, `{"displayName": "Ada", "email": "ada@example.com"}`, false, `unknown field "email"`},
+		{"typed bad timestamp", typed, `{"createdAt": "31/01/2024"}`, false, "Timestamp"},
+		{"typed wrong type", typed, `{"tags": "a"}`, false, "unexpected token"},
+		{"untyped object", untyped, `{"anything": {"goes": [1, 2]}}`, true, ""},
+		{"untyped well-known any", untyped,
+			`{"ttl": {"@type": "type.googleapis.com/google.protobuf.Duration", "value": "1.5s"}}`, true, ""},
+		{"untyped custom any", untyped, `{"@type": "type.googleapis.com/acme.v1.User", "whatever": 1}`, true, ""},
+		{"untyped bad duration", untyped,
+			`{"items": [{"@type": "type.googleapis.com/google.protobuf.Duration", "value": "90 seconds"}]}`,
+			false, "/items/0"},
+		{"untyped not object", untyped, `[1, 2]`, false, "must be a JSON object"},
+		{"invalid json", untyped, `{"a": }`, false, "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestProtobufTextWithMessage(t *testing.T) {
+	v, err := NewValidator(FormatProtobuf, WithProtoMessage(testDescriptorSet(t), "acme.v1.User"))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"fields", "display_name: \"Ada\"\nid: 42\ntags: \"a\"\ntags: \"b\"\n", true, ""},
+		{"list syntax and comments", "# a user\ntags: [\"a\", \"b\"]\n", true, ""},
+		{"nested well-known type", "created_at { seconds: 1706702400 }\n", true, ""},
+		{"empty message", "", true, ""},
+		{"unknown field", "display_name: \"Ada\"\nemail: \"ada@example.com\"\n", false, "unknown field: email"},
+		{"wrong type", "id: \"forty-two\"\n", false, "invalid value for int64"},
+		{"unknown nested field", "created_at { minutes: 1 }\n", false, "unknown field: minutes"},
+		{"any fields rejected", "type_url: \"x\"\n", false, "unknown field: type_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+			if !tt.valid && (len(result.Diagnostics) == 0 || result.Diagnostics[0].Line == 0) {
+				t.Errorf("Diagnostics = %+v, want a line number", result.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestWithProtoMessageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     []byte
+		message string
+		errPart string
+	}{
+		{"garbage set", []byte("not a descriptor set"), "acme.v1.User", "invalid descriptor set"},
+		{"missing message", testDescriptorSet(t), "acme.v1.Group", "not found"},
+		{"not a message", testDescriptorSet(t), "acme.v1.User.id", "not a message type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewValidator(FormatProtoJSON, WithProtoMessage(tt.set, tt.message))
+			if err == nil || !strings.Contains(err.Error(), tt.errPart) {
+				t.Errorf("NewValidator() error = %v, want it to contain %q", err, tt.errPart)
+			}
+		})
+	}
+}
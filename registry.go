@@ -0,0 +1,155 @@
+package serdeval
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Detector is implemented by registered validators that can recognize their format from
+// content. DetectFormat and ValidateAuto ask registered detectors, in registration order,
+// before trying the built-in formats, so Detect should only match content that is
+// unmistakably its format.
+type Detector interface {
+	Detect(data []byte) bool
+}
+
+// ExtensionProvider is implemented by registered validators whose files are recognized by
+// extension. DetectFormatFromFilename checks registered extensions before the built-in ones.
+type ExtensionProvider interface {
+	// Extensions returns the file extensions of the format, such as "rules" or ".rules".
+	Extensions() []string
+}
+
+// registryMu guards validatorMap, extensionMap, and the registered detectors and extensions.
+var registryMu sync.RWMutex
+
+// registeredDetector pairs a registered format with its content check.
+type registeredDetector struct {
+	format Format
+	detect func(data []byte) bool
+}
+
+var (
+	// customDetectors holds the detectors of registered formats in registration order
+	customDetectors []registeredDetector
+	// customExtensions maps the extensions of registered formats, lowercased and without a dot
+	customExtensions = map[string]Format{}
+)
+
+// Register makes format available to NewValidator, ValidateAuto, and SupportedFormats,
+// built by constructor. Registering a built-in format, or a format registered before,
+// replaces its validator. If the validators constructor returns implement Detector or
+// ExtensionProvider, the format also takes part in DetectFormat and
+// DetectFormatFromFilename. Options such as WithMaxFileSize apply to registered formats;
+// format-specific options do not.
+//
+// Register is safe for concurrent use, though it is usually called from an init function.
+// It panics if format is empty, FormatAuto, or FormatUnknown, or if constructor is nil.
+//
+// Example:
+//
+//	serdeval.Register("rules", func() serdeval.Validator {
+//		return serdeval.NewFuncValidator("rules", parseRules)
+//	})
+func Register(format Format, constructor func() Validator) {
+	if format == "" || format == FormatAuto || format == FormatUnknown {
+		panic(fmt.Sprintf("serdeval: Register called with reserved format %q", format))
+	}
+	if constructor == nil {
+		panic(fmt.Sprintf("serdeval: Register called with a nil constructor for format %q", format))
+	}
+	v := constructor()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	validatorMap[format] = constructor
+	customDetectors = slices.DeleteFunc(customDetectors, func(d registeredDetector) bool {
+		return d.format == format
+	})
+	if d, ok := v.(Detector); ok {
+		customDetectors = append(customDetectors, registeredDetector{format: format, detect: d.Detect})
+	}
+	for ext, f := range customExtensions {
+		if f == format {
+			delete(customExtensions, ext)
+		}
+	}
+	if e, ok := v.(ExtensionProvider); ok {
+		for _, ext := range e.Extensions() {
+			customExtensions[strings.ToLower(strings.TrimPrefix(ext, "."))] = format
+		}
+	}
+}
+
+// lookupValidator returns the constructor registered for format.
+func lookupValidator(format Format) (func() Validator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	constructor, ok := validatorMap[format]
+
+	return constructor, ok
+}
+
+// detectRegistered returns the first registered format whose detector matches data.
+func detectRegistered(data []byte) Format {
+	registryMu.RLock()
+	detectors := customDetectors
+	registryMu.RUnlock()
+
+	for _, d := range detectors {
+		if d.detect(data) {
+			return d.format
+		}
+	}
+
+	return FormatUnknown
+}
+
+// registeredExtension returns the registered format for ext, if any.
+func registeredExtension(ext string) (Format, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	format, ok := customExtensions[ext]
+
+	return format, ok
+}
+
+// NewFuncValidator returns a Validator for format that checks data with validate, for use
+// with Register. Results from validate that leave Format empty are given format.
+// To add content detection or extensions, embed the Validator in a type that implements
+// Detector or ExtensionProvider.
+//
+// Example:
+//
+//	v := NewFuncValidator("rules", func(data []byte) Result {
+//		if !bytes.HasPrefix(data, []byte("rules:")) {
+//			return Result{Error: "missing rules: header"}
+//		}
+//		return Result{Valid: true}
+//	})
+func NewFuncValidator(format Format, validate func(data []byte) Result) Validator {
+	return &funcValidator{baseValidator: baseValidator{format: format}, validate: validate}
+}
+
+// funcValidator adapts a function to the Validator interface.
+type funcValidator struct {
+	baseValidator
+	validate func(data []byte) Result
+}
+
+// Validate calls the wrapped function.
+func (v *funcValidator) Validate(data []byte) Result {
+	result := v.validate(data)
+	if result.Format == "" {
+		result.Format = v.format
+	}
+
+	return result
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *funcValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
@@ -0,0 +1,135 @@
+package serdeval
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+)
+
+// rulesValidator is a custom format that can be detected by content and extension.
+type rulesValidator struct {
+	Validator
+}
+
+func (rulesValidator) Detect(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("rules:"))
+}
+
+func (rulesValidator) Extensions() []string {
+	return []string{".rules", "RULESET"}
+}
+
+func newRulesValidator() Validator {
+	return rulesValidator{NewFuncValidator("rules", func(data []byte) Result {
+		if !bytes.HasPrefix(data, []byte("rules:\n")) {
+			return Result{Error: "missing rules: header"}
+		}
+
+		return Result{Valid: true}
+	})}
+}
+
+// unregister removes a format added by a test.
+func unregister(t *testing.T, format Format) {
+	t.Helper()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(validatorMap, format)
+		for ext, f := range customExtensions {
+			if f == format {
+				delete(customExtensions, ext)
+			}
+		}
+		customDetectors = slices.DeleteFunc(customDetectors, func(d registeredDetector) bool {
+			return d.format == format
+		})
+	})
+}
+
+func TestRegister(t *testing.T) {
+	unregister(t, "rules")
+	Register("rules", newRulesValidator)
+
+	if !slices.Contains(SupportedFormats(), "rules") {
+		t.Errorf("SupportedFormats() = %v, want it to contain rules", SupportedFormats())
+	}
+	v, err := NewValidator("rules", WithMaxFileSize(100))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	if result := v.ValidateString("rules:\nallow all\n"); !result.Valid || result.Format != "rules" {
+		t.Errorf("ValidateString() = %+v, want a valid rules result", result)
+	}
+	if result := v.ValidateString("allow all\n"); result.Valid || result.Error != "missing rules: header" {
+		t.Errorf("ValidateString() = %+v, want the validator's error", result)
+	}
+	if result := ValidateContext(context.Background(), v, bytes.Repeat([]byte("x"), 101)); !result.Skipped {
+		t.Errorf("ValidateContext() = %+v, want the size limit applied", result)
+	}
+
+	if got := DetectFormat([]byte("rules:\n")); got != "rules" {
+		t.Errorf("DetectFormat() = %v, want rules", got)
+	}
+	if result := ValidateAuto([]byte("rules:\nallow all\n")); !result.Valid || result.Format != "rules" {
+		t.Errorf("ValidateAuto() = %+v, want a valid rules result", result)
+	}
+	if got := DetectFormat([]byte(`{"a": 1}`)); got != FormatJSON {
+		t.Errorf("DetectFormat() = %v, want built-in detection for other content", got)
+	}
+	for _, name := range []string{"acl.rules", "prod.ruleset"} {
+		if got := DetectFormatFromFilename(name); got != "rules" {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want rules", name, got)
+		}
+	}
+	if got := FormatExtensions("rules"); !slices.Equal(got, []string{"rules", "ruleset"}) {
+		t.Errorf("FormatExtensions() = %v, want [rules ruleset]", got)
+	}
+}
+
+func TestRegisterReplaces(t *testing.T) {
+	unregister(t, "rules")
+	Register("rules", newRulesValidator)
+	Register("rules", func() Validator {
+		return NewFuncValidator("rules", func([]byte) Result { return Result{Valid: true} })
+	})
+
+	if got := DetectFormat([]byte("rules:\n")); got == "rules" {
+		t.Errorf("DetectFormat() = %v, want the replaced detector gone", got)
+	}
+	if got := DetectFormatFromFilename("acl.rules"); got == "rules" {
+		t.Errorf("DetectFormatFromFilename() = %v, want the replaced extensions gone", got)
+	}
+	v, err := NewValidator("rules")
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	if result := v.ValidateString("anything"); !result.Valid {
+		t.Errorf("ValidateString() = %+v, want the replacement validator", result)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      Format
+		constructor func() Validator
+	}{
+		{"empty format", "", newRulesValidator},
+		{"auto", FormatAuto, newRulesValidator},
+		{"unknown", FormatUnknown, newRulesValidator},
+		{"nil constructor", "rules", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register() did not panic")
+				}
+			}()
+			Register(tt.format, tt.constructor)
+		})
+	}
+}
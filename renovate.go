@@ -0,0 +1,269 @@
+package serdeval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// renovateOptions are the top-level keys Renovate accepts in a repository config,
+// including per-manager blocks such as "npm" or "github-actions".
+var renovateOptions = []string{
+	"$schema", "addLabels", "assignees", "assigneesFromCodeOwners", "automerge", "automergeSchedule",
+	"automergeStrategy", "automergeType", "baseBranches", "baseBranchPatterns", "branchConcurrentLimit",
+	"branchPrefix", "branchTopic", "commitBody", "commitMessage", "commitMessageAction", "commitMessageExtra",
+	"commitMessagePrefix", "commitMessageSuffix", "commitMessageTopic", "configMigration", "constraints",
+	"customDatasources", "customManagers", "dependencyDashboard", "dependencyDashboardApproval",
+	"dependencyDashboardAutoclose", "dependencyDashboardFooter", "dependencyDashboardHeader",
+	"dependencyDashboardLabels", "dependencyDashboardTitle", "description", "digest", "draftPR", "enabled",
+	"enabledManagers", "encrypted", "extends", "followTag", "gitAuthor", "group", "groupName", "groupSlug",
+	"hostRules", "ignoreDeps", "ignorePaths", "ignorePresets", "ignoreTests", "ignoreUnstable", "includePaths",
+	"internalChecksFilter", "labels", "lockFileMaintenance", "major", "minimumReleaseAge", "minor", "npmrc",
+	"osvVulnerabilityAlerts", "packageRules", "patch", "pin", "pinDigests", "platformAutomerge",
+	"postUpdateOptions", "prBodyNotes", "prConcurrentLimit", "prCreation", "prHourlyLimit", "prNotPendingHours",
+	"prPriority", "rangeStrategy", "rebaseWhen", "recreateWhen", "regexManagers", "registryAliases",
+	"respectLatest", "reviewers", "reviewersFromCodeOwners", "schedule", "semanticCommitScope",
+	"semanticCommitType", "semanticCommits", "separateMajorMinor", "separateMinorPatch", "separateMultipleMajor",
+	"stabilityDays", "timezone", "transitiveRemediation", "updateNotScheduled", "vulnerabilityAlerts",
+	// Manager-specific blocks
+	"ansible", "bazel", "bundler", "cargo", "cocoapods", "composer", "custom", "devcontainer", "docker-compose",
+	"dockerfile", "github-actions", "gitlabci", "gomod", "gradle", "helm-values", "helmv3", "kubernetes",
+	"maven", "mix", "nix", "npm", "nuget", "pep621", "pip_requirements", "pip_setup", "pipenv", "poetry",
+	"pre-commit", "pub", "regex", "swift", "terraform",
+}
+
+// renovateStringLists are top-level options whose value must be a list of strings.
+var renovateStringLists = []string{
+	"extends", "labels", "addLabels", "assignees", "reviewers", "ignoreDeps", "ignorePaths", "ignorePresets",
+	"includePaths", "enabledManagers", "baseBranches", "baseBranchPatterns", "postUpdateOptions",
+}
+
+// renovateBooleans are top-level options whose value must be true or false.
+var renovateBooleans = []string{
+	"enabled", "automerge", "dependencyDashboard", "dependencyDashboardApproval", "separateMajorMinor",
+	"separateMinorPatch", "separateMultipleMajor", "pinDigests", "platformAutomerge", "configMigration",
+	"draftPR", "ignoreTests", "ignoreUnstable", "respectLatest",
+}
+
+// RenovateValidator validates Renovate bot configuration (renovate.json, renovate.json5, .renovaterc).
+// Input may use JSON5 syntax: comments, trailing commas, single-quoted strings, and unquoted keys.
+// Beyond syntax it flags unknown top-level keys and checks the types of common options.
+//
+// Example:
+//
+//	validator := &RenovateValidator{baseValidator{format: FormatRenovate}}
+//	data, _ := os.ReadFile("renovate.json")
+//	result := validator.Validate(data)
+type RenovateValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid Renovate configuration.
+//
+// Example:
+//
+//	validator := &RenovateValidator{baseValidator{format: FormatRenovate}}
+//	result := validator.ValidateString(`{extends: ['config:recommended'], labels: ['deps'],}`)
+func (v *RenovateValidator) Validate(data []byte) Result {
+	converted, err := json5ToJSON(string(data))
+	if err != nil {
+		return Result{Valid: false, Format: v.format, Error: "invalid JSON5: " + err.Error()}.locate(data, nil)
+	}
+
+	var config map[string]interface{}
+	if err = json.Unmarshal([]byte(converted), &config); err != nil {
+		return Result{Valid: false, Format: v.format, Error: "invalid JSON5: " + err.Error()}.locate(data, nil)
+	}
+
+	err = validateRenovateConfig(config)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *RenovateValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateRenovateConfig(config map[string]interface{}) error {
+	if err := checkAllowedKeys(config, "", renovateOptions); err != nil {
+		return err
+	}
+
+	for _, key := range renovateStringLists {
+		if _, err := optionalStringList(config, key, key); err != nil {
+			return err
+		}
+	}
+	for _, key := range renovateBooleans {
+		if value, ok := config[key]; ok {
+			if _, isBool := value.(bool); !isBool {
+				return fmt.Errorf("%s must be a boolean", key)
+			}
+		}
+	}
+
+	if schedule, ok := config["schedule"]; ok {
+		if _, isString := schedule.(string); !isString {
+			if _, err := optionalStringList(config, "schedule", "schedule"); err != nil {
+				return errors.New("schedule must be a string or a list of strings")
+			}
+		}
+	}
+
+	if raw, ok := config["packageRules"]; ok {
+		rules, isList := raw.([]interface{})
+		if !isList {
+			return errors.New("packageRules must be a list")
+		}
+		for i, rule := range rules {
+			if err := checkRenovatePackageRule(rule, fmt.Sprintf("packageRules[%d]", i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRenovatePackageRule requires each rule to be an object with at least one match or exclude condition.
+func checkRenovatePackageRule(raw interface{}, path string) error {
+	rule, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be an object", path)
+	}
+
+	for key := range rule {
+		if strings.HasPrefix(key, "match") || strings.HasPrefix(key, "exclude") {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: rule has no match or exclude condition", path)
+}
+
+// json5ToJSON rewrites JSON5 input as plain JSON: comments are stripped, trailing commas
+// dropped, single-quoted strings and identifier keys converted to double-quoted strings.
+func json5ToJSON(src string) (string, error) {
+	var out strings.Builder
+	pendingComma := false
+	line := 1
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			out.WriteByte(c)
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			out.WriteByte(c)
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return "", fmt.Errorf("line %d: unterminated block comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == ',':
+			if pendingComma {
+				return "", fmt.Errorf("line %d: unexpected ','", line)
+			}
+			pendingComma = true
+			i++
+		default:
+			if pendingComma && c != '}' && c != ']' {
+				out.WriteByte(',')
+			}
+			pendingComma = false
+
+			next, err := json5Token(src, i, line, &out)
+			if err != nil {
+				return "", err
+			}
+			i = next
+		}
+	}
+	if pendingComma {
+		out.WriteByte(',')
+	}
+
+	return out.String(), nil
+}
+
+// json5Token writes the token starting at src[i] to out and returns the index after it.
+func json5Token(src string, i, line int, out *strings.Builder) (int, error) {
+	c := src[i]
+	switch {
+	case c == '"' || c == '\'':
+		return json5String(src, i, line, out)
+	case isJSON5IdentByte(c) && (c < '0' || c > '9'):
+		end := i
+		for end < len(src) && isJSON5IdentByte(src[end]) {
+			end++
+		}
+		word := src[i:end]
+		switch word {
+		case "true", "false", "null":
+			out.WriteString(word)
+		default:
+			rest := strings.TrimLeft(src[end:], " \t\r\n")
+			if !strings.HasPrefix(rest, ":") {
+				return 0, fmt.Errorf("line %d: unexpected identifier %q", line, word)
+			}
+			out.WriteString(`"` + word + `"`)
+		}
+
+		return end, nil
+	}
+	out.WriteByte(c)
+
+	return i + 1, nil
+}
+
+// json5String copies a single- or double-quoted JSON5 string to out as a JSON string.
+func json5String(src string, i, line int, out *strings.Builder) (int, error) {
+	quote := src[i]
+	out.WriteByte('"')
+	for j := i + 1; j < len(src); j++ {
+		switch c := src[j]; {
+		case c == quote:
+			out.WriteByte('"')
+
+			return j + 1, nil
+		case c == '\n':
+			return 0, fmt.Errorf("line %d: unterminated string", line)
+		case c == '\\' && j+1 < len(src):
+			j++
+			switch src[j] {
+			case '\'':
+				out.WriteByte('\'')
+			case '\n':
+				// Line continuation
+			default:
+				out.WriteByte('\\')
+				out.WriteByte(src[j])
+			}
+		case c == '"':
+			out.WriteString(`\"`)
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return 0, fmt.Errorf("line %d: unterminated string", line)
+}
+
+func isJSON5IdentByte(c byte) bool {
+	return c == '_' || c == '$' || (c|0x20 >= 'a' && c|0x20 <= 'z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,62 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenovateValidator(t *testing.T) {
+	v := &RenovateValidator{baseValidator{format: FormatRenovate}}
+
+	json5 := `// Renovate config
+{
+  $schema: 'https://docs.renovatebot.com/renovate-schema.json',
+  extends: ['config:recommended', "group:allNonMajor",],
+  /* weekly batches */
+  schedule: 'before 6am on monday',
+  packageRules: [
+    {matchUpdateTypes: ['major'], automerge: false, labels: ['it\'s "major"']},
+  ],
+}
+`
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"plain json", `{"extends": ["config:recommended"], "labels": ["deps"], "enabled": true}`, true, ""},
+		{"json5", json5, true, ""},
+		{"manager block", `{"npm": {"enabled": false}, "schedule": ["every weekend"]}`, true, ""},
+		{"unknown key", `{"extends": [], "automergee": true}`, false, `unknown top-level key "automergee"`},
+		{"extends not list", `{"extends": "config:recommended"}`, false, "extends must be a list"},
+		{"boolean type", `{"automerge": "yes"}`, false, "automerge must be a boolean"},
+		{"schedule type", `{"schedule": 5}`, false, "schedule must be a string"},
+		{"rule without match", `{"packageRules": [{"automerge": true}]}`, false, "packageRules[0]: rule has no match"},
+		{"rule not object", `{"packageRules": ["x"]}`, false, "packageRules[0] must be an object"},
+		{"unterminated comment", "{/* oops\n}", false, "unterminated block comment"},
+		{"unterminated string", "{extends: ['a]}", false, "unterminated string"},
+		{"bare value", "{extends: [foo]}", false, `unexpected identifier "foo"`},
+		{"double comma", `{"extends": [],, "labels": []}`, false, "unexpected ','"},
+		{"not an object", `["config:recommended"]`, false, "invalid JSON5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	for _, name := range []string{"renovate.json", ".github/renovate.json5", ".renovaterc"} {
+		if got := DetectFormatFromFilename(name); got != FormatRenovate {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want %v", name, got, FormatRenovate)
+		}
+	}
+}
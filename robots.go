@@ -0,0 +1,138 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// RobotsValidator validates robots.txt files (RFC 9309).
+// It checks that rules belong to a user-agent group, that directive names are known
+// (User-agent, Allow, Disallow, Sitemap, Crawl-delay), that paths start with '/' or '*'
+// and use '$' only as a final anchor, and that sitemap URLs are absolute.
+//
+// Example:
+//
+//	validator := &RobotsValidator{baseValidator{format: FormatRobots}}
+//	result := validator.ValidateString("User-agent: *\nDisallow: /admin/\nSitemap: https://example.com/sitemap.xml\n")
+type RobotsValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid robots.txt file.
+//
+// Example:
+//
+//	validator := &RobotsValidator{baseValidator{format: FormatRobots}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. "line 3: Disallow before any User-agent line"
+//	}
+func (v *RobotsValidator) Validate(data []byte) Result {
+	var err error
+	inGroup := false
+	for i, line := range strings.Split(string(data), "\n") {
+		if inGroup, err = checkRobotsLine(line, inGroup); err != nil {
+			err = fmt.Errorf("line %d: %w", i+1, err)
+
+			break
+		}
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *RobotsValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// checkRobotsLine validates one line and reports whether a user-agent group is open afterwards.
+func checkRobotsLine(line string, inGroup bool) (bool, error) {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return inGroup, nil
+	}
+
+	name, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return inGroup, fmt.Errorf("expected \"Directive: value\", got %q", line)
+	}
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(name) {
+	case "user-agent":
+		if value == "" {
+			return inGroup, errors.New("missing User-agent value (use * for all crawlers)")
+		}
+
+		return true, nil
+	case "allow", "disallow":
+		if !inGroup {
+			return inGroup, fmt.Errorf("%s before any User-agent line", name)
+		}
+
+		return inGroup, checkRobotsPath(name, value)
+	case "crawl-delay":
+		if !inGroup {
+			return inGroup, fmt.Errorf("%s before any User-agent line", name)
+		}
+		if delay, err := strconv.ParseFloat(value, 64); err != nil || delay < 0 {
+			return inGroup, fmt.Errorf("invalid Crawl-delay %q: must be a non-negative number", value)
+		}
+
+		return inGroup, nil
+	case "sitemap":
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return inGroup, fmt.Errorf("invalid Sitemap %q: must be an absolute http(s) URL", value)
+		}
+
+		return inGroup, nil
+	}
+
+	return inGroup, fmt.Errorf("unknown directive %q (expected User-agent, Allow, Disallow, Sitemap, or Crawl-delay)",
+		name)
+}
+
+// checkRobotsPath validates an Allow/Disallow path pattern. An empty Disallow is allowed.
+func checkRobotsPath(name, path string) error {
+	if path == "" {
+		return nil
+	}
+	if path[0] != '/' && path[0] != '*' {
+		return fmt.Errorf("%s path %q must start with '/' or '*'", name, path)
+	}
+	if i := strings.IndexByte(path, '$'); i >= 0 && i != len(path)-1 {
+		return fmt.Errorf("%s path %q may only use '$' at the end", name, path)
+	}
+	if strings.ContainsAny(path, " \t") {
+		return fmt.Errorf("%s path %q contains whitespace", name, path)
+	}
+
+	return nil
+}
+
+// isRobots checks whether the first directive is a User-agent line.
+func isRobots(lines []string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		return strings.HasPrefix(strings.ToLower(line), "user-agent:")
+	}
+
+	return false
+}
@@ -0,0 +1,53 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRobotsValidator(t *testing.T) {
+	v := &RobotsValidator{baseValidator{format: FormatRobots}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"allow all", "User-agent: *\nDisallow:\n", true, ""},
+		{
+			"groups and sitemap",
+			"# comment\nUser-agent: Googlebot\nUser-agent: Bingbot\nDisallow: /private/ # inline\nAllow: /private/ok$\n\n" +
+				"User-agent: *\nCrawl-delay: 1.5\nDisallow: /*.pdf$\n\nSitemap: https://example.com/sitemap.xml\n",
+			true, "",
+		},
+		{"case insensitive", "user-agent: *\ndisallow: /tmp\n", true, ""},
+		{"rule before group", "Disallow: /admin\nUser-agent: *\n", false, "line 1: Disallow before any User-agent"},
+		{"unknown directive", "User-agent: *\nDisalow: /admin\n", false, "unknown directive \"Disalow\""},
+		{"missing colon", "User-agent: *\nDisallow /admin\n", false, "expected \"Directive: value\""},
+		{"relative path", "User-agent: *\nDisallow: admin/\n", false, "must start with '/'"},
+		{"dollar in middle", "User-agent: *\nDisallow: /a$b\n", false, "only use '$' at the end"},
+		{"bad crawl delay", "User-agent: *\nCrawl-delay: soon\n", false, "non-negative number"},
+		{"relative sitemap", "Sitemap: /sitemap.xml\n", false, "absolute http(s) URL"},
+		{"empty user agent", "User-agent:\n", false, "missing User-agent value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormatFromFilename("public/robots.txt"); got != FormatRobots {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatRobots)
+	}
+	if got := DetectFormat([]byte("User-agent: *\nDisallow: /\n")); got != FormatRobots {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatRobots)
+	}
+}
@@ -0,0 +1,135 @@
+package serdeval
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+)
+
+// secretPattern is a kind of credential recognized by its shape.
+type secretPattern struct {
+	re   *regexp.Regexp
+	kind string
+}
+
+// secretPatterns are the credentials ScanSecrets recognizes by shape, most specific first.
+var secretPatterns = []secretPattern{
+	{regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`), "private key block"},
+	{regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`), "AWS access key ID"},
+	{regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+]{40}\b`), "AWS secret access key"},
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), "GitHub token"},
+	{regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{50,}\b`), "GitHub token"},
+	{regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`), "Slack token"},
+	{regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), "Google API key"},
+	{regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`), "Stripe live key"},
+}
+
+// secretAssignment matches a value assigned to a key whose name ends like a credential, as
+// in db_password: hunter2 or "apiKey": "...", but not secretName or tokenizer. The key is
+// the first group and the value the second.
+var secretAssignment = regexp.MustCompile(`(?i)([\w.-]*(?:password|passwd|pwd|secret|token|api[_-]?key|` +
+	`access[_-]?key|private[_-]?key|credentials?))["']?\s*[:=]\s*["']?([^\s"',;}]{8,})`)
+
+// placeholderSecret matches values that stand in for a credential rather than holding one:
+// references to variables, templates, and obvious examples.
+var placeholderSecret = regexp.MustCompile(`(?i)^(?:\$|\{\{|<|%|\*+$|x+$)|changeme|example|placeholder|redacted|` +
+	`your[_-]|dummy|secretref|secretkeyref|valuefrom`)
+
+// highEntropyToken matches long runs of base64 and URL-safe characters that may be random
+// tokens.
+var highEntropyToken = regexp.MustCompile(`[A-Za-z0-9+/=_-]{32,}`)
+
+// ignoreSecretMarker on a line stops ScanSecrets from reporting it, for test fixtures and
+// known false positives.
+const ignoreSecretMarker = "serdeval:ignore-secret"
+
+// ScanSecrets reports the lines of data that likely hold credentials: private key blocks,
+// AWS access keys, GitHub, Slack, Google, and Stripe tokens, values of keys named like
+// password, secret, token, or api_key, and long random-looking tokens. Each line is
+// reported once, with a message naming the kind of credential but not its value, so the
+// report can go to CI logs. Values that are references or placeholders, such as ${TOKEN}
+// or changeme, are not reported, nor are lines containing "serdeval:ignore-secret".
+//
+// The scan is a heuristic: it can miss credentials and flag harmless random strings.
+//
+// Example:
+//
+//	for _, d := range ScanSecrets(data) {
+//		fmt.Printf("%d:%d: %s\n", d.Line, d.Column, d.Message)
+//	}
+func ScanSecrets(data []byte) []Diagnostic {
+	var diags []Diagnostic
+	for offset := 0; offset < len(data); {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += offset
+		}
+		line := data[offset:end]
+		if at, kind := findSecret(line); at >= 0 && !bytes.Contains(line, []byte(ignoreSecretMarker)) {
+			diags = append(diags, atOffset(data, offset+at, "possible "+kind+"; keep credentials out of files"))
+		}
+		offset = end + 1
+	}
+
+	return diags
+}
+
+// findSecret returns the offset and kind of the first likely credential on line, or -1.
+func findSecret(line []byte) (int, string) {
+	for _, p := range secretPatterns {
+		if loc := p.re.FindIndex(line); loc != nil {
+			return loc[0], p.kind
+		}
+	}
+	for _, m := range secretAssignment.FindAllSubmatchIndex(line, -1) {
+		value := string(line[m[4]:m[5]])
+		if !placeholderSecret.MatchString(value) && shannonEntropy(value) >= 3 {
+			return m[4], "secret in " + string(line[m[2]:m[3]])
+		}
+	}
+	for _, loc := range highEntropyToken.FindAllIndex(line, -1) {
+		if randomToken(string(line[loc[0]:loc[1]])) {
+			return loc[0], "high-entropy token"
+		}
+	}
+
+	return -1, ""
+}
+
+// randomToken reports whether token looks randomly generated: it mixes upper case, lower
+// case, and digits, and has high entropy per character. Hex strings such as checksums and
+// commit hashes never mix cases and are left alone.
+func randomToken(token string) bool {
+	var upper, lower, digit bool
+	for _, r := range token {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		}
+	}
+
+	return upper && lower && digit && shannonEntropy(token) >= 4.5
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	entropy := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
@@ -0,0 +1,179 @@
+package serdeval
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sitemapNamespace is the XML namespace required by the sitemaps.org protocol.
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	// sitemapMaxEntries is the protocol limit on URLs or sitemaps per file.
+	sitemapMaxEntries = 50000
+	// sitemapMaxLocLength is the protocol limit on loc URL length.
+	sitemapMaxLocLength = 2048
+)
+
+// sitemapLastmodLayouts are the W3C Datetime forms allowed in lastmod.
+var sitemapLastmodLayouts = []string{
+	"2006",
+	"2006-01",
+	"2006-01-02",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z07:00",
+	time.RFC3339Nano,
+}
+
+// SitemapValidator validates XML sitemaps and sitemap index files (sitemaps.org protocol 0.9).
+// It checks the namespace, urlset/sitemapindex structure, loc URLs, lastmod dates,
+// changefreq and priority values, and the 50,000 entry limit.
+//
+// Example:
+//
+//	validator := &SitemapValidator{baseValidator{format: FormatSitemap}}
+//	data, _ := os.ReadFile("public/sitemap.xml")
+//	result := validator.Validate(data)
+type SitemapValidator struct {
+	baseValidator
+}
+
+type sitemapEntry struct {
+	Loc        *string `xml:"loc"`
+	Lastmod    *string `xml:"lastmod"`
+	Changefreq *string `xml:"changefreq"`
+	Priority   *string `xml:"priority"`
+}
+
+type sitemapDoc struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+// Validate checks if the provided byte slice contains a valid sitemap or sitemap index.
+//
+// Example:
+//
+//	validator := &SitemapValidator{baseValidator{format: FormatSitemap}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. "url 3: invalid lastmod \"yesterday\""
+//	}
+func (v *SitemapValidator) Validate(data []byte) Result {
+	var doc sitemapDoc
+	err := xml.Unmarshal(data, &doc)
+	if err != nil {
+		err = fmt.Errorf("invalid XML: %w", err)
+	} else {
+		err = validateSitemap(&doc)
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *SitemapValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateSitemap(doc *sitemapDoc) error {
+	var kind string
+	var entries []sitemapEntry
+	switch doc.XMLName.Local {
+	case "urlset":
+		kind, entries = "url", doc.URLs
+		if len(doc.Sitemaps) > 0 {
+			return errors.New("urlset must not contain <sitemap> elements")
+		}
+	case "sitemapindex":
+		kind, entries = "sitemap", doc.Sitemaps
+		if len(doc.URLs) > 0 {
+			return errors.New("sitemapindex must not contain <url> elements")
+		}
+	default:
+		return fmt.Errorf("root element must be <urlset> or <sitemapindex>, got <%s>", doc.XMLName.Local)
+	}
+
+	if doc.XMLName.Space != sitemapNamespace {
+		return fmt.Errorf("%s must declare xmlns=%q", doc.XMLName.Local, sitemapNamespace)
+	}
+	if len(entries) > sitemapMaxEntries {
+		return fmt.Errorf("%d %s entries exceeds the protocol limit of %d; split into multiple files",
+			len(entries), kind, sitemapMaxEntries)
+	}
+
+	for i, e := range entries {
+		if err := checkSitemapEntry(e, kind); err != nil {
+			return fmt.Errorf("%s %d: %w", kind, i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func checkSitemapEntry(e sitemapEntry, kind string) error {
+	if e.Loc == nil {
+		return errors.New("missing required <loc>")
+	}
+	loc := strings.TrimSpace(*e.Loc)
+	u, err := url.Parse(loc)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("loc %q must be an absolute http(s) URL", loc)
+	}
+	if len(loc) > sitemapMaxLocLength {
+		return fmt.Errorf("loc is %d characters, exceeding the limit of %d", len(loc), sitemapMaxLocLength)
+	}
+
+	if e.Lastmod != nil && !isW3CDatetime(strings.TrimSpace(*e.Lastmod)) {
+		return fmt.Errorf("invalid lastmod %q (expected W3C Datetime, e.g. 2024-01-05)", *e.Lastmod)
+	}
+
+	if kind == "sitemap" {
+		if e.Changefreq != nil || e.Priority != nil {
+			return errors.New("sitemap index entries only allow <loc> and <lastmod>")
+		}
+
+		return nil
+	}
+
+	if e.Changefreq != nil {
+		switch strings.TrimSpace(*e.Changefreq) {
+		case "always", "hourly", "daily", "weekly", "monthly", "yearly", "never":
+		default:
+			return fmt.Errorf("invalid changefreq %q", *e.Changefreq)
+		}
+	}
+	if e.Priority != nil {
+		p, err := strconv.ParseFloat(strings.TrimSpace(*e.Priority), 64)
+		if err != nil || p < 0 || p > 1 {
+			return fmt.Errorf("invalid priority %q (expected 0.0 to 1.0)", *e.Priority)
+		}
+	}
+
+	return nil
+}
+
+func isW3CDatetime(s string) bool {
+	for _, layout := range sitemapLastmodLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSitemap checks for a urlset or sitemapindex root element.
+func isSitemap(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "<") &&
+		(strings.Contains(trimmed, "<urlset") || strings.Contains(trimmed, "<sitemapindex"))
+}
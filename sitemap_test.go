@@ -0,0 +1,103 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const sitemapURLSet = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/</loc>
+    <lastmod>2024-01-05</lastmod>
+    <changefreq>weekly</changefreq>
+    <priority>0.8</priority>
+  </url>
+  <url><loc>https://example.com/about</loc><lastmod>2024-01-05T10:00:00+00:00</lastmod></url>
+</urlset>`
+
+func TestSitemapValidator(t *testing.T) {
+	v := &SitemapValidator{baseValidator{format: FormatSitemap}}
+
+	index := `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<sitemap><loc>https://example.com/s1.xml.gz</loc><lastmod>2024-01</lastmod></sitemap></sitemapindex>`
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"urlset", sitemapURLSet, true, ""},
+		{"sitemap index", index, true, ""},
+		{"malformed", `<urlset><url>`, false, "invalid XML"},
+		{"wrong root", `<feed xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"/>`, false, "root element"},
+		{
+			"missing namespace",
+			strings.Replace(sitemapURLSet, ` xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"`, "", 1),
+			false,
+			"xmlns",
+		},
+		{
+			"missing loc",
+			strings.Replace(sitemapURLSet, "<loc>https://example.com/about</loc>", "", 1),
+			false,
+			"url 2: missing required <loc>",
+		},
+		{
+			"relative loc",
+			strings.Replace(sitemapURLSet, "https://example.com/about", "/about", 1),
+			false,
+			"absolute http(s) URL",
+		},
+		{"bad lastmod", strings.Replace(sitemapURLSet, "2024-01-05<", "05/01/2024<", 1), false, "invalid lastmod"},
+		{"bad changefreq", strings.Replace(sitemapURLSet, "weekly", "fortnightly", 1), false, "invalid changefreq"},
+		{"priority out of range", strings.Replace(sitemapURLSet, "0.8", "1.5", 1), false, "invalid priority"},
+		{
+			"index with priority",
+			strings.Replace(index, "</lastmod>", "</lastmod><priority>1</priority>", 1),
+			false,
+			"only allow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestSitemapValidatorEntryLimit(t *testing.T) {
+	v := &SitemapValidator{baseValidator{format: FormatSitemap}}
+
+	var b strings.Builder
+	b.WriteString(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	for i := 0; i <= sitemapMaxEntries; i++ {
+		b.WriteString("<url><loc>https://example.com/</loc></url>")
+	}
+	b.WriteString("</urlset>")
+
+	result := v.ValidateString(b.String())
+	if result.Valid || !strings.Contains(result.Error, "protocol limit") {
+		t.Errorf("ValidateString() = %v (error: %q), want entry limit error", result.Valid, result.Error)
+	}
+}
+
+func TestDetectSitemap(t *testing.T) {
+	if got := DetectFormat([]byte(sitemapURLSet)); got != FormatSitemap {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatSitemap)
+	}
+	if got := DetectFormatFromFilename("public/sitemap-posts.xml"); got != FormatSitemap {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatSitemap)
+	}
+	if got := DetectFormatFromFilename("pom.xml"); got != FormatXML {
+		t.Errorf("DetectFormatFromFilename(pom.xml) = %v, want %v", got, FormatXML)
+	}
+}
@@ -0,0 +1,454 @@
+package serdeval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	spdxIDRe      = regexp.MustCompile(`^SPDXRef-[A-Za-z0-9.-]+$`)
+	spdxRefRe     = regexp.MustCompile(`^(?:DocumentRef-[A-Za-z0-9.-]+:)?LicenseRef-[A-Za-z0-9.-]+$`)
+	spdxVersionRe = regexp.MustCompile(`^SPDX-2\.[0-3]$`)
+	spdxCreatorRe = regexp.MustCompile(`^(Tool|Person|Organization):\s*\S`)
+)
+
+// spdxLicenses holds common SPDX license identifiers, keyed in lower case since matching is case-insensitive.
+var spdxLicenses = lowerSet(
+	"0BSD", "AFL-3.0", "AGPL-1.0", "AGPL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.0", "Apache-1.1",
+	"Apache-2.0", "APSL-2.0", "Artistic-1.0", "Artistic-2.0", "BlueOak-1.0.0", "BSD-1-Clause", "BSD-2-Clause",
+	"BSD-2-Clause-Patent", "BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0", "BUSL-1.1",
+	"CAL-1.0", "CC-BY-3.0", "CC-BY-4.0", "CC-BY-NC-4.0", "CC-BY-NC-SA-4.0", "CC-BY-ND-4.0", "CC-BY-SA-3.0",
+	"CC-BY-SA-4.0", "CC0-1.0", "CDDL-1.0", "CDDL-1.1", "CECILL-2.1", "CPAL-1.0", "CPL-1.0", "ECL-2.0",
+	"EFL-2.0", "EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2", "FSFAP", "FTL", "GFDL-1.3", "GFDL-1.3-only",
+	"GFDL-1.3-or-later", "GPL-1.0", "GPL-1.0-only", "GPL-1.0-or-later", "GPL-2.0", "GPL-2.0-only",
+	"GPL-2.0-or-later", "GPL-3.0", "GPL-3.0-only", "GPL-3.0-or-later", "HPND", "ICU", "IJG", "ISC",
+	"LGPL-2.0", "LGPL-2.0-only", "LGPL-2.0-or-later", "LGPL-2.1", "LGPL-2.1-only", "LGPL-2.1-or-later",
+	"LGPL-3.0", "LGPL-3.0-only", "LGPL-3.0-or-later", "Libpng", "libpng-2.0", "LPL-1.02", "LPPL-1.3c",
+	"MIT", "MIT-0", "MIT-CMU", "MPL-1.0", "MPL-1.1", "MPL-2.0", "MPL-2.0-no-copyleft-exception", "MS-PL",
+	"MS-RL", "MulanPSL-2.0", "NCSA", "ODbL-1.0", "OFL-1.0", "OFL-1.1", "OpenSSL", "OSL-3.0", "PDDL-1.0",
+	"PHP-3.0", "PHP-3.01", "PostgreSQL", "PSF-2.0", "Python-2.0", "Python-2.0.1", "QPL-1.0", "Ruby",
+	"SGI-B-2.0", "SISSL", "Sleepycat", "SSPL-1.0", "TCL", "Unicode-3.0", "Unicode-DFS-2016", "Unlicense",
+	"UPL-1.0", "Vim", "W3C", "WTFPL", "X11", "XFree86-1.1", "Zlib", "zlib-acknowledgement", "ZPL-2.0",
+	"ZPL-2.1",
+)
+
+// spdxExceptions holds common SPDX license exception identifiers, keyed in lower case.
+var spdxExceptions = lowerSet(
+	"389-exception", "Autoconf-exception-2.0", "Autoconf-exception-3.0", "Bison-exception-2.2",
+	"Bootloader-exception", "Classpath-exception-2.0", "CLISP-exception-2.0", "eCos-exception-2.0",
+	"Font-exception-2.0", "freertos-exception-2.0", "GCC-exception-2.0", "GCC-exception-3.1",
+	"GNAT-exception", "GPL-3.0-linking-exception", "GPL-3.0-linking-source-exception",
+	"i2p-gpl-java-exception", "LGPL-3.0-linking-exception", "Libtool-exception", "Linux-syscall-note",
+	"LLVM-exception", "mif-exception", "Nokia-Qt-exception-1.1", "OCaml-LGPL-linking-exception",
+	"OpenJDK-assembly-exception-1.0", "openvpn-openssl-exception", "Qt-GPL-exception-1.0",
+	"Qt-LGPL-exception-1.1", "Swift-exception", "u-boot-exception-2.0", "Universal-FOSS-exception-1.0",
+	"WxWindows-exception-3.1",
+)
+
+func lowerSet(items ...string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[strings.ToLower(item)] = true
+	}
+
+	return set
+}
+
+// SPDXValidator validates SPDX license expressions and SPDX 2.x SBOM documents.
+// Input starting with '{' is treated as an SPDX JSON document, input with an "SPDXVersion:"
+// tag as a tag-value document, and anything else as a single license expression such as
+// "(MIT OR Apache-2.0) AND BSD-3-Clause". License identifiers are checked against the common
+// SPDX list; custom licenses must use the LicenseRef- prefix.
+//
+// Example:
+//
+//	validator := &SPDXValidator{baseValidator{format: FormatSPDX}}
+//	result := validator.ValidateString("GPL-2.0-or-later WITH Classpath-exception-2.0")
+type SPDXValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid SPDX expression or document.
+//
+// Example:
+//
+//	validator := &SPDXValidator{baseValidator{format: FormatSPDX}}
+//	data, _ := os.ReadFile("sbom.spdx.json")
+//	result := validator.Validate(data)
+func (v *SPDXValidator) Validate(data []byte) Result {
+	trimmed := strings.TrimSpace(string(data))
+
+	var err error
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		var doc map[string]interface{}
+		if jsonErr := json.Unmarshal(data, &doc); jsonErr != nil {
+			return Result{
+				Valid:      false,
+				Format:     v.format,
+				Error:      "invalid JSON: " + jsonErr.Error(),
+				Suggestion: suggestFix(FormatJSON, data, jsonErr.Error()),
+			}.locate(data, jsonErr)
+		}
+		err = validateSPDXJSON(doc)
+	case isSPDXTagValue(trimmed):
+		err = validateSPDXTagValue(string(data))
+	default:
+		err = validateSPDXExpression(trimmed)
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *SPDXValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// validateSPDXExpression parses a license expression with the SPDX grammar:
+// OR binds loosest, then AND, then WITH; parentheses group sub-expressions.
+func validateSPDXExpression(expr string) error {
+	if expr == "" {
+		return errors.New("empty license expression")
+	}
+
+	p := &spdxExprParser{tokens: tokenizeSPDX(expr)}
+	if err := p.parseOr(); err != nil {
+		return err
+	}
+	if p.pos < len(p.tokens) {
+		return fmt.Errorf("unexpected %q in license expression", p.tokens[p.pos])
+	}
+
+	return nil
+}
+
+func tokenizeSPDX(expr string) []string {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+
+	return strings.Fields(expr)
+}
+
+type spdxExprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *spdxExprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *spdxExprParser) parseOr() error {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *spdxExprParser) parseAnd() error {
+	return p.parseBinary("AND", p.parseWith)
+}
+
+func (p *spdxExprParser) parseBinary(op string, operand func() error) error {
+	if err := operand(); err != nil {
+		return err
+	}
+	for {
+		tok := p.peek()
+		if tok != op {
+			if strings.EqualFold(tok, op) {
+				return fmt.Errorf("operator %q must be upper case", tok)
+			}
+
+			return nil
+		}
+		p.pos++
+		if err := operand(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *spdxExprParser) parseWith() error {
+	if err := p.parseOperand(); err != nil {
+		return err
+	}
+
+	tok := p.peek()
+	if strings.EqualFold(tok, "WITH") {
+		if tok != "WITH" {
+			return fmt.Errorf("operator %q must be upper case", tok)
+		}
+		p.pos++
+		exception := p.peek()
+		if exception == "" {
+			return errors.New("missing exception after WITH")
+		}
+		p.pos++
+		if !spdxExceptions[strings.ToLower(exception)] && !strings.HasPrefix(exception, "AdditionRef-") {
+			return fmt.Errorf("unknown SPDX license exception %q", exception)
+		}
+	}
+
+	return nil
+}
+
+func (p *spdxExprParser) parseOperand() error {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return errors.New("unexpected end of license expression")
+	case tok == "(":
+		p.pos++
+		if err := p.parseOr(); err != nil {
+			return err
+		}
+		if p.peek() != ")" {
+			return errors.New("missing ')' in license expression")
+		}
+		p.pos++
+
+		return nil
+	case tok == ")" || tok == "AND" || tok == "OR" || tok == "WITH":
+		return fmt.Errorf("unexpected %q in license expression", tok)
+	}
+
+	p.pos++
+	if spdxRefRe.MatchString(tok) {
+		return nil
+	}
+	if !spdxLicenses[strings.ToLower(strings.TrimSuffix(tok, "+"))] {
+		return fmt.Errorf("unknown SPDX license identifier %q (use LicenseRef- for custom licenses)", tok)
+	}
+
+	return nil
+}
+
+// checkSPDXLicenseField validates a license field that may also be NONE or NOASSERTION.
+func checkSPDXLicenseField(value, field string) error {
+	if value == "NONE" || value == "NOASSERTION" {
+		return nil
+	}
+	if err := validateSPDXExpression(value); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+
+	return nil
+}
+
+// checkSPDXDocumentFields checks the document creation fields shared by JSON and tag-value documents.
+func checkSPDXDocumentFields(version, dataLicense, id, namespace, created string, creators []string) error {
+	if !spdxVersionRe.MatchString(version) {
+		return fmt.Errorf("unsupported SPDX version %q (expected SPDX-2.0 to SPDX-2.3)", version)
+	}
+	if dataLicense != "CC0-1.0" {
+		return fmt.Errorf("data license must be CC0-1.0, got %q", dataLicense)
+	}
+	if id != "SPDXRef-DOCUMENT" {
+		return fmt.Errorf("document SPDX identifier must be SPDXRef-DOCUMENT, got %q", id)
+	}
+	if u, err := url.Parse(namespace); err != nil || u.Scheme == "" || u.Fragment != "" {
+		return fmt.Errorf("document namespace %q must be an absolute URI without a '#' fragment", namespace)
+	}
+	if _, err := time.Parse(time.RFC3339, created); err != nil {
+		return fmt.Errorf("created timestamp %q must be RFC 3339 (e.g. 2024-01-31T12:00:00Z)", created)
+	}
+	if len(creators) == 0 {
+		return errors.New("at least one creator is required")
+	}
+	for _, creator := range creators {
+		if !spdxCreatorRe.MatchString(creator) {
+			return fmt.Errorf("creator %q must start with Tool:, Person:, or Organization:", creator)
+		}
+	}
+
+	return nil
+}
+
+func validateSPDXJSON(doc map[string]interface{}) error {
+	for _, key := range []string{"spdxVersion", "dataLicense", "SPDXID", "name", "documentNamespace"} {
+		if spdxString(doc, key) == "" {
+			return fmt.Errorf("missing required field: %s", key)
+		}
+	}
+	info, ok := doc["creationInfo"].(map[string]interface{})
+	if !ok {
+		return errors.New("missing required field: creationInfo")
+	}
+	creators, err := optionalStringList(info, "creators", "creationInfo.creators")
+	if err != nil {
+		return err
+	}
+	if err = checkSPDXDocumentFields(spdxString(doc, "spdxVersion"), spdxString(doc, "dataLicense"),
+		spdxString(doc, "SPDXID"), spdxString(doc, "documentNamespace"), spdxString(info, "created"),
+		creators); err != nil {
+		return err
+	}
+
+	packages, _ := doc["packages"].([]interface{})
+	for i, raw := range packages {
+		if err = checkSPDXJSONPackage(raw, fmt.Sprintf("packages[%d]", i)); err != nil {
+			return err
+		}
+	}
+
+	relationships, _ := doc["relationships"].([]interface{})
+	for i, raw := range relationships {
+		rel, _ := raw.(map[string]interface{})
+		for _, key := range []string{"spdxElementId", "relationshipType", "relatedSpdxElement"} {
+			if spdxString(rel, key) == "" {
+				return fmt.Errorf("relationships[%d]: missing required field: %s", i, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkSPDXJSONPackage(raw interface{}, path string) error {
+	pkg, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be an object", path)
+	}
+	for _, key := range []string{"name", "SPDXID", "downloadLocation"} {
+		if spdxString(pkg, key) == "" {
+			return fmt.Errorf("%s: missing required field: %s", path, key)
+		}
+	}
+	if id := spdxString(pkg, "SPDXID"); !spdxIDRe.MatchString(id) {
+		return fmt.Errorf("%s: invalid SPDXID %q", path, id)
+	}
+	for _, key := range []string{"licenseConcluded", "licenseDeclared"} {
+		if value := spdxString(pkg, key); value != "" {
+			if err := checkSPDXLicenseField(value, path+"."+key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// spdxString returns m[key] as a string, or "" if it is missing or not a string.
+func spdxString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+
+	return s
+}
+
+// spdxTagValueDoc collects document-level tags and tracks the package being read.
+type spdxTagValueDoc struct {
+	tags        map[string]string
+	creators    []string
+	packageLine int
+	packageTags map[string]bool
+}
+
+// validateSPDXTagValue checks a tag-value document line by line, then its required document fields.
+func validateSPDXTagValue(text string) error {
+	doc := &spdxTagValueDoc{tags: make(map[string]string)}
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tag, value, ok := strings.Cut(line, ":")
+		if !ok || strings.ContainsAny(tag, " \t") {
+			return fmt.Errorf("line %d: expected \"Tag: value\"", i+1)
+		}
+		value = strings.TrimSpace(value)
+
+		// <text>...</text> values may span several lines
+		if strings.HasPrefix(value, "<text>") && !strings.Contains(value, "</text>") {
+			start := i
+			for !strings.Contains(lines[i], "</text>") {
+				i++
+				if i == len(lines) {
+					return fmt.Errorf("line %d: unterminated <text> value", start+1)
+				}
+			}
+		}
+
+		if err := doc.add(tag, value, i+1); err != nil {
+			return err
+		}
+	}
+	if err := doc.endPackage(); err != nil {
+		return err
+	}
+
+	for _, tag := range []string{"SPDXVersion", "DataLicense", "SPDXID", "DocumentName", "DocumentNamespace",
+		"Created"} {
+		if _, ok := doc.tags[tag]; !ok {
+			return fmt.Errorf("missing required tag: %s", tag)
+		}
+	}
+
+	return checkSPDXDocumentFields(doc.tags["SPDXVersion"], doc.tags["DataLicense"], doc.tags["SPDXID"],
+		doc.tags["DocumentNamespace"], doc.tags["Created"], doc.creators)
+}
+
+func (d *spdxTagValueDoc) add(tag, value string, lineNum int) error {
+	switch tag {
+	case "PackageName":
+		if err := d.endPackage(); err != nil {
+			return err
+		}
+		d.packageLine, d.packageTags = lineNum, map[string]bool{}
+	case "Creator":
+		d.creators = append(d.creators, value)
+	case "SPDXID":
+		if !spdxIDRe.MatchString(value) {
+			return fmt.Errorf("line %d: invalid SPDXID %q", lineNum, value)
+		}
+	case "PackageLicenseConcluded", "PackageLicenseDeclared", "LicenseConcluded":
+		if err := checkSPDXLicenseField(value, tag); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+
+	if d.packageTags != nil {
+		d.packageTags[tag] = true
+	} else if _, seen := d.tags[tag]; !seen {
+		d.tags[tag] = value
+	}
+
+	return nil
+}
+
+// endPackage checks the fields every package section requires.
+func (d *spdxTagValueDoc) endPackage() error {
+	if d.packageTags == nil {
+		return nil
+	}
+	for _, tag := range []string{"SPDXID", "PackageDownloadLocation"} {
+		if !d.packageTags[tag] {
+			return fmt.Errorf("line %d: package is missing %s", d.packageLine, tag)
+		}
+	}
+
+	return nil
+}
+
+// isSPDXTagValue checks for the SPDXVersion tag that opens a tag-value document.
+func isSPDXTagValue(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "SPDXVersion:") || strings.Contains(trimmed, "\nSPDXVersion:")
+}
+
+// isSPDXJSON checks if JSON content appears to be an SPDX document.
+func isSPDXJSON(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "{") &&
+		strings.Contains(trimmed, "\"spdxVersion\"") &&
+		strings.Contains(trimmed, "\"SPDXID\"")
+}
@@ -0,0 +1,104 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const spdxTagValue = `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: serdeval
+DocumentNamespace: https://example.com/spdx/serdeval-1.0
+Creator: Tool: serdeval-1.0
+Creator: Organization: Example Inc.
+Created: 2024-01-31T12:00:00Z
+
+PackageName: serdeval
+SPDXID: SPDXRef-Package-serdeval
+PackageDownloadLocation: git+https://github.com/akhilesharora/serdeval
+PackageLicenseConcluded: MIT
+PackageLicenseDeclared: (MIT OR Apache-2.0)
+PackageCopyrightText: <text>Copyright (c) 2024
+Example Inc.</text>
+`
+
+const spdxJSON = `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "serdeval",
+  "documentNamespace": "https://example.com/spdx/serdeval-1.0",
+  "creationInfo": {"created": "2024-01-31T12:00:00Z", "creators": ["Tool: serdeval-1.0"]},
+  "packages": [{"name": "yaml", "SPDXID": "SPDXRef-Package-yaml", "downloadLocation": "NOASSERTION",
+    "licenseConcluded": "MIT AND Apache-2.0", "licenseDeclared": "NOASSERTION"}],
+  "relationships": [{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES",
+    "relatedSpdxElement": "SPDXRef-Package-yaml"}]
+}`
+
+func TestSPDXValidator(t *testing.T) {
+	v := &SPDXValidator{baseValidator{format: FormatSPDX}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"single license", "MIT", true, ""},
+		{"or later", "GPL-2.0+", true, ""},
+		{"compound", "(MIT OR Apache-2.0) AND BSD-3-Clause", true, ""},
+		{"with exception", "GPL-2.0-or-later WITH Classpath-exception-2.0", true, ""},
+		{"license ref", "DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2 OR LicenseRef-custom", true, ""},
+		{"case-insensitive id", "apache-2.0", true, ""},
+		{"tag-value document", spdxTagValue, true, ""},
+		{"json document", spdxJSON, true, ""},
+		{"unknown license", "MIT OR Foo-1.0", false, `unknown SPDX license identifier "Foo-1.0"`},
+		{"lowercase operator", "MIT or Apache-2.0", false, `operator "or" must be upper case`},
+		{"dangling operator", "MIT AND", false, "unexpected end"},
+		{"unbalanced paren", "(MIT OR Apache-2.0", false, "missing ')'"},
+		{"unknown exception", "GPL-2.0-only WITH Foo-exception", false, "unknown SPDX license exception"},
+		{"trailing token", "MIT Apache-2.0", false, `unexpected "Apache-2.0"`},
+		{"empty", "  ", false, "empty license expression"},
+		{"tag-value bad license", strings.Replace(spdxTagValue, "PackageLicenseConcluded: MIT",
+			"PackageLicenseConcluded: MIT-ish", 1), false, "line 13: PackageLicenseConcluded: unknown"},
+		{"tag-value missing namespace", strings.Replace(spdxTagValue,
+			"DocumentNamespace: https://example.com/spdx/serdeval-1.0\n", "", 1), false, "DocumentNamespace"},
+		{"tag-value bad package", strings.Replace(spdxTagValue, "PackageDownloadLocation", "PackageHomePage", 1),
+			false, "line 10: package is missing PackageDownloadLocation"},
+		{"tag-value unterminated text", strings.Replace(spdxTagValue, "</text>", "", 1), false, "unterminated <text>"},
+		{"tag-value bad data license", strings.Replace(spdxTagValue, "CC0-1.0", "MIT", 1), false, "CC0-1.0"},
+		{"json bad version", strings.Replace(spdxJSON, "SPDX-2.3", "SPDX-3.0", 1), false, "unsupported SPDX version"},
+		{"json bad created", strings.Replace(spdxJSON, "2024-01-31T12:00:00Z", "yesterday", 1), false, "RFC 3339"},
+		{"json bad creator", strings.Replace(spdxJSON, "Tool: serdeval-1.0", "serdeval", 1), false, "creator"},
+		{"json bad package id", strings.Replace(spdxJSON, "SPDXRef-Package-yaml", "Package yaml", 1),
+			false, "packages[0]: invalid SPDXID"},
+		{"json bad license", strings.Replace(spdxJSON, "MIT AND Apache-2.0", "MIT AND", 1),
+			false, "packages[0].licenseConcluded"},
+		{"json bad relationship", strings.Replace(spdxJSON, `"relationshipType": "DESCRIBES",`, "", 1),
+			false, "relationships[0]: missing required field: relationshipType"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	for _, input := range []string{spdxTagValue, spdxJSON} {
+		if got := DetectFormat([]byte(input)); got != FormatSPDX {
+			t.Errorf("DetectFormat() = %v, want %v", got, FormatSPDX)
+		}
+	}
+	for _, name := range []string{"sbom.spdx", "sbom.spdx.json"} {
+		if got := DetectFormatFromFilename(name); got != FormatSPDX {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want %v", name, got, FormatSPDX)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package serdeval
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+// sshString encodes s as an SSH wire-format length-prefixed string.
+func sshString(s string) []byte {
+	b := make([]byte, 4, 4+len(s))
+	binary.BigEndian.PutUint32(b, uint32(len(s)))
+
+	return append(b, s...)
+}
+
+func testSSHKeys(t *testing.T) (pub, opensshPriv, pkcs8Priv string) {
+	t.Helper()
+
+	pk, sk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := append(sshString("ssh-ed25519"), sshString(string(pk))...)
+	pub = "ssh-ed25519 " + base64.StdEncoding.EncodeToString(blob) + " user@host"
+
+	body := []byte(opensshKeyMagic)
+	body = append(body, sshString("none")...)
+	body = append(body, sshString("none")...)
+	body = append(body, sshString("")...)
+	body = append(body, 0, 0, 0, 1)
+	body = append(body, sshString(string(blob))...)
+	body = append(body, sshString("private section")...)
+	opensshPriv = string(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: body}))
+
+	der, err := x509.MarshalPKCS8PrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8Priv = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	return pub, opensshPriv, pkcs8Priv
+}
+
+func TestSSHKeyValidator(t *testing.T) {
+	v := &SSHKeyValidator{baseValidator{format: FormatSSHKey}}
+	pub, opensshPriv, pkcs8Priv := testSSHKeys(t)
+	blob := strings.Fields(pub)[1]
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"public key", pub, true, ""},
+		{"public key without comment", "ssh-ed25519 " + blob, true, ""},
+		{
+			"authorized_keys with options",
+			"# keys\n" + `from="10.0.0.0/8 ,192.168.0.0/16",no-pty ` + pub + "\n" + pub,
+			true,
+			"",
+		},
+		{"openssh private key", opensshPriv, true, ""},
+		{"pkcs8 private key", pkcs8Priv, true, ""},
+		{"empty", "", false, "no keys found"},
+		{"unknown type", "ssh-foo " + blob, false, "unknown key type"},
+		{"bad base64", "ssh-ed25519 not!base64", false, "invalid base64"},
+		{"missing blob", "ssh-ed25519", false, "missing its base64"},
+		{"type mismatch", "ssh-rsa " + blob, false, "does not match"},
+		{
+			"truncated private key",
+			strings.Replace(opensshPriv, "-----END OPENSSH PRIVATE KEY-----", "", 1),
+			false,
+			"BEGIN/END",
+		},
+		{
+			"corrupt private body",
+			string(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte("garbage")})),
+			false, "magic",
+		},
+		{"trailing data", opensshPriv + "junk", false, "after END"},
+		{
+			"unsupported block",
+			string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}})),
+			false, "unsupported key block",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	for _, input := range []string{pub, opensshPriv} {
+		if got := DetectFormat([]byte(input)); got != FormatSSHKey {
+			t.Errorf("DetectFormat() = %v, want %v", got, FormatSSHKey)
+		}
+	}
+	for _, name := range []string{"/home/me/.ssh/id_ed25519", "id_rsa.pub", "authorized_keys"} {
+		if got := DetectFormatFromFilename(name); got != FormatSSHKey {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want %v", name, got, FormatSSHKey)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package serdeval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// statsFormats are the formats ComputeStats reads.
+var statsFormats = []Format{FormatJSON, FormatJSONL, FormatYAML, FormatTOML, FormatCSV, FormatTSV}
+
+// Stats describes the structure of a document, for sanity-checking a data drop at a
+// glance. Counts cover every document of a YAML stream and every record of JSON Lines.
+type Stats struct {
+	// Format is the format the document was read as
+	Format Format `json:"format"`
+	// Documents is the number of documents in a YAML stream or records in JSON Lines, and
+	// 1 for other formats
+	Documents int `json:"documents"`
+	// MaxDepth is the deepest nesting of objects and arrays: 0 for a scalar, 1 for {"a": 1}
+	MaxDepth int `json:"max_depth"`
+	// Objects is the number of objects (mappings and tables)
+	Objects int `json:"objects"`
+	// Keys is the number of object members, UniqueKeys the number of distinct key names,
+	// and MaxKeys the most members of any one object
+	Keys       int `json:"keys"`
+	UniqueKeys int `json:"unique_keys"`
+	MaxKeys    int `json:"max_keys"`
+	// Arrays is the number of arrays, and ArrayItems the items they hold between them
+	Arrays     int `json:"arrays"`
+	ArrayItems int `json:"array_items"`
+	// MinArrayLength and MaxArrayLength are the shortest and longest array lengths
+	MinArrayLength int `json:"min_array_length"`
+	MaxArrayLength int `json:"max_array_length"`
+	// Rows and Columns are the data rows, after the header, and header columns of CSV and
+	// TSV, and EmptyCells the cells left blank
+	Rows       int `json:"rows,omitempty"`
+	Columns    int `json:"columns,omitempty"`
+	EmptyCells int `json:"empty_cells,omitempty"`
+	// Types counts values by type: object, array, string, integer, number, boolean, null,
+	// and datetime. CSV and TSV cells count as integer, number, boolean, or string by how
+	// they read.
+	Types map[string]int `json:"types"`
+
+	keys map[string]bool
+}
+
+// ComputeStats validates data as format and returns its structure. An empty format or
+// FormatAuto is detected from the content. JSON, JSON Lines, YAML, TOML, CSV, and TSV
+// are supported; a document that fails validation returns its *ValidationError.
+//
+// Example:
+//
+//	stats, err := ComputeStats([]byte(`{"users": [{"id": 1}, {"id": 2}]}`), FormatJSON)
+//	// stats.MaxDepth is 3, stats.Keys is 3, and stats.Types["integer"] is 2
+func ComputeStats(data []byte, format Format) (*Stats, error) {
+	text, _, err := DecodeText(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(text)
+	}
+	if !slices.Contains(statsFormats, format) {
+		return nil, fmt.Errorf("cannot compute stats of %s: supported formats are json, jsonl, yaml, toml, csv, "+
+			"and tsv", format)
+	}
+	validator, err := NewValidator(format)
+	if err != nil {
+		return nil, err
+	}
+	if result := validator.Validate(text); !result.Valid {
+		return nil, result.Err
+	}
+
+	stats := &Stats{Format: format, Types: map[string]int{}, keys: map[string]bool{}}
+	switch format {
+	case FormatCSV, FormatTSV:
+		err = stats.addTable(text, format)
+	default:
+		var docs []any
+		if docs, err = decodeStatsDocuments(text, format); err == nil {
+			stats.Documents = len(docs)
+			for _, doc := range docs {
+				stats.MaxDepth = max(stats.MaxDepth, stats.add(doc))
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats.UniqueKeys = len(stats.keys)
+
+	return stats, nil
+}
+
+// decodeStatsDocuments returns the documents of text, which is valid in format.
+func decodeStatsDocuments(text []byte, format Format) ([]any, error) {
+	switch format {
+	case FormatYAML:
+		return decodeYAMLSamples(text)
+	case FormatTOML:
+		var v map[string]any
+		if _, err := toml.Decode(string(text), &v); err != nil {
+			return nil, err
+		}
+
+		return []any{v}, nil
+	case FormatJSONL:
+		var docs []any
+		scanner := bufio.NewScanner(bytes.NewReader(text))
+		scanner.Buffer(nil, len(text)+1)
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				v, err := decodeJSONNumbers(line)
+				if err != nil {
+					return nil, err
+				}
+				docs = append(docs, v)
+			}
+		}
+
+		return docs, scanner.Err()
+	}
+
+	v, err := decodeJSONNumbers(text)
+
+	return []any{v}, err
+}
+
+// decodeJSONNumbers decodes a JSON value, keeping numbers as json.Number so integers and
+// fractions can be told apart.
+func decodeJSONNumbers(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	err := dec.Decode(&v)
+
+	return v, err
+}
+
+// add counts v and everything in it, and returns its depth.
+func (s *Stats) add(v any) int {
+	switch t := v.(type) {
+	case map[string]any:
+		s.Types["object"]++
+		s.Objects++
+		s.Keys += len(t)
+		s.MaxKeys = max(s.MaxKeys, len(t))
+		depth := 0
+		for k, child := range t {
+			s.keys[k] = true
+			depth = max(depth, s.add(child))
+		}
+
+		return depth + 1
+	case []map[string]any:
+		// TOML arrays of tables
+		items := make([]any, len(t))
+		for i, item := range t {
+			items[i] = item
+		}
+
+		return s.add(items)
+	case map[any]any:
+		// YAML mappings with keys that are not all strings
+		object := make(map[string]any, len(t))
+		for k, child := range t {
+			object[fmt.Sprint(k)] = child
+		}
+
+		return s.add(object)
+	case []any:
+		s.Types["array"]++
+		if s.Arrays == 0 || len(t) < s.MinArrayLength {
+			s.MinArrayLength = len(t)
+		}
+		s.Arrays++
+		s.ArrayItems += len(t)
+		s.MaxArrayLength = max(s.MaxArrayLength, len(t))
+		depth := 0
+		for _, child := range t {
+			depth = max(depth, s.add(child))
+		}
+
+		return depth + 1
+	}
+	s.Types[statsScalarType(v)]++
+
+	return 0
+}
+
+// statsScalarType names the type of a scalar value as Stats.Types counts it.
+func statsScalarType(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(string(t), ".eE") {
+			return "number"
+		}
+
+		return "integer"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	case time.Time:
+		return "datetime"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+// addTable counts the rows, columns, and cells of CSV or TSV text.
+func (s *Stats) addTable(text []byte, format Format) error {
+	records, err := tableRecords(text, format)
+	if err != nil {
+		return err
+	}
+
+	s.Documents = 1
+	if len(records) == 0 {
+		return nil
+	}
+	s.Columns = len(records[0])
+	s.Rows = len(records) - 1
+	for _, record := range records[1:] {
+		for _, cell := range record {
+			if cell == "" {
+				s.EmptyCells++
+
+				continue
+			}
+			s.Types[statsScalarType(csvCellValue(cell))]++
+		}
+	}
+
+	return nil
+}
+
+// tableRecords splits CSV or TSV text into records. TSV fields are never quoted, and
+// its blank lines are skipped.
+func tableRecords(text []byte, format Format) ([][]string, error) {
+	if format == FormatCSV {
+		r := csv.NewReader(bytes.NewReader(text))
+		r.Comma = CSVDialect{}.DelimiterFor(text)
+
+		return r.ReadAll()
+	}
+
+	var records [][]string
+	for _, line := range strings.Split(string(text), "\n") {
+		if line = strings.TrimSuffix(line, "\r"); line != "" {
+			records = append(records, strings.Split(line, "\t"))
+		}
+	}
+
+	return records, nil
+}
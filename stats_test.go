@@ -0,0 +1,111 @@
+package serdeval
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format Format
+		want   Stats
+	}{
+		{
+			name:   "json",
+			input:  `{"users": [{"id": 1, "tags": []}, {"id": 2.5, "name": null}], "ok": true}`,
+			format: FormatJSON,
+			want: Stats{
+				Format: FormatJSON, Documents: 1, MaxDepth: 4, Objects: 3, Keys: 6, UniqueKeys: 5, MaxKeys: 2,
+				Arrays: 2, ArrayItems: 2, MinArrayLength: 0, MaxArrayLength: 2,
+				Types: map[string]int{"object": 3, "array": 2, "integer": 1, "number": 1, "null": 1, "boolean": 1},
+			},
+		},
+		{
+			name:   "jsonl records",
+			input:  "{\"a\": \"x\"}\n\n{\"a\": \"y\", \"b\": [1, 2, 3]}\n",
+			format: FormatJSONL,
+			want: Stats{
+				Format: FormatJSONL, Documents: 2, MaxDepth: 2, Objects: 2, Keys: 3, UniqueKeys: 2, MaxKeys: 2,
+				Arrays: 1, ArrayItems: 3, MinArrayLength: 3, MaxArrayLength: 3,
+				Types: map[string]int{"object": 2, "array": 1, "string": 2, "integer": 3},
+			},
+		},
+		{
+			name:   "yaml stream",
+			input:  "a: 1\n---\n- 2020-01-02T03:04:05Z\n- {1: x}\n",
+			format: FormatYAML,
+			want: Stats{
+				Format: FormatYAML, Documents: 2, MaxDepth: 2, Objects: 2, Keys: 2, UniqueKeys: 2, MaxKeys: 1,
+				Arrays: 1, ArrayItems: 2, MinArrayLength: 2, MaxArrayLength: 2,
+				Types: map[string]int{"object": 2, "array": 1, "integer": 1, "datetime": 1, "string": 1},
+			},
+		},
+		{
+			name:   "toml array of tables",
+			input:  "title = \"x\"\n[[item]]\nid = 1\n[[item]]\nid = 2\n",
+			format: FormatTOML,
+			want: Stats{
+				Format: FormatTOML, Documents: 1, MaxDepth: 3, Objects: 3, Keys: 4, UniqueKeys: 3, MaxKeys: 2,
+				Arrays: 1, ArrayItems: 2, MinArrayLength: 2, MaxArrayLength: 2,
+				Types: map[string]int{"object": 3, "array": 1, "string": 1, "integer": 2},
+			},
+		},
+		{
+			name:   "csv",
+			input:  "id,name,score\n1,Ada,\n2,\"Lin, B\",3.5\n",
+			format: FormatCSV,
+			want: Stats{
+				Format: FormatCSV, Documents: 1, Rows: 2, Columns: 3, EmptyCells: 1,
+				Types: map[string]int{"integer": 2, "string": 2, "number": 1},
+			},
+		},
+		{
+			name:   "tsv keeps quotes",
+			input:  "a\tb\n\"x\ttrue\n",
+			format: FormatTSV,
+			want: Stats{
+				Format: FormatTSV, Documents: 1, Rows: 1, Columns: 2,
+				Types: map[string]int{"string": 1, "boolean": 1},
+			},
+		},
+		{
+			name:   "auto",
+			input:  `[1, "a"]`,
+			format: FormatAuto,
+			want: Stats{
+				Format: FormatJSON, Documents: 1, MaxDepth: 1, Arrays: 1, ArrayItems: 2, MinArrayLength: 2,
+				MaxArrayLength: 2, Types: map[string]int{"array": 1, "integer": 1, "string": 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeStats([]byte(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("ComputeStats() error = %v", err)
+			}
+			got.keys = nil
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ComputeStats() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeStatsErrors(t *testing.T) {
+	_, err := ComputeStats([]byte(`{"a": }`), FormatJSON)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("ComputeStats() error = %v, want a *ValidationError", err)
+	}
+
+	_, err = ComputeStats([]byte("<a/>"), FormatXML)
+	if err == nil || !strings.Contains(err.Error(), "cannot compute stats of xml") {
+		t.Errorf("ComputeStats() error = %v, want it to reject xml", err)
+	}
+}
@@ -0,0 +1,272 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// checkStrictJSON reports the first problem that json.Unmarshal tolerates in a syntactically
+// valid document: invalid UTF-8, which it silently replaces, and duplicate object keys,
+// where it silently keeps the last value.
+func checkStrictJSON(data []byte) error {
+	if !utf8.Valid(data) {
+		offset := invalidUTF8Offset(data)
+
+		return &offsetError{offset: offset, msg: fmt.Sprintf("invalid UTF-8 at byte offset %d", offset)}
+	}
+
+	return checkDuplicateJSONKeys(data)
+}
+
+// invalidUTF8Offset returns the offset of the first byte that is not valid UTF-8.
+func invalidUTF8Offset(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+
+	return len(data)
+}
+
+// jsonFrame is an object or array being walked by checkDuplicateJSONKeys.
+type jsonFrame struct {
+	path      string
+	keys      map[string]bool // nil for arrays
+	key       string
+	expectKey bool
+	index     int
+}
+
+// childPath returns the path of the value about to be read in f.
+func (f *jsonFrame) childPath() string {
+	if f.keys != nil {
+		return f.path + "/" + pointerSegment(f.key)
+	}
+
+	return fmt.Sprintf("%s/%d", f.path, f.index)
+}
+
+// valueDone records that a value of f was read.
+func (f *jsonFrame) valueDone() {
+	if f.keys != nil {
+		f.expectKey = true
+	} else {
+		f.index++
+	}
+}
+
+// checkDuplicateJSONKeys walks the tokens of a valid document and reports the first key
+// that appears twice in the same object, with the path of that object.
+func checkDuplicateJSONKeys(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var stack []*jsonFrame
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		if key, ok := tok.(string); ok && top != nil && top.expectKey {
+			if top.keys[key] {
+				return &offsetError{
+					offset: jsonKeyStart(data, int(dec.InputOffset())),
+					msg:    fmt.Sprintf("duplicate key %q in object at %s", key, displayPath(top.path)),
+				}
+			}
+			top.keys[key] = true
+			top.key = key
+			top.expectKey = false
+
+			continue
+		}
+		stack = stepJSONFrames(stack, top, tok)
+	}
+}
+
+// stepJSONFrames updates the frame stack after a value or delimiter token; top is the
+// innermost frame, or nil at the document root.
+func stepJSONFrames(stack []*jsonFrame, top *jsonFrame, tok json.Token) []*jsonFrame {
+	path := ""
+	if top != nil {
+		path = top.childPath()
+	}
+	switch tok {
+	case json.Delim('{'):
+		return append(stack, &jsonFrame{path: path, keys: map[string]bool{}, expectKey: true})
+	case json.Delim('['):
+		return append(stack, &jsonFrame{path: path})
+	case json.Delim('}'), json.Delim(']'):
+		stack = stack[:len(stack)-1]
+		if len(stack) > 0 {
+			stack[len(stack)-1].valueDone()
+		}
+
+		return stack
+	}
+	if top != nil {
+		top.valueDone()
+	}
+
+	return stack
+}
+
+// jsonKeyStart returns the offset of the opening quote of the string that ends just before end.
+func jsonKeyStart(data []byte, end int) int {
+	for i := end - 2; i >= 0; i-- {
+		if data[i] != '"' {
+			continue
+		}
+		backslashes := 0
+		for j := i - 1; j >= 0 && data[j] == '\\'; j-- {
+			backslashes++
+		}
+		if backslashes%2 == 0 {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// yamlKnownTags are the tags of the YAML 1.2 core schema plus the yaml.org types that
+// yaml.v3 resolves. Any other explicit tag is application-specific.
+var yamlKnownTags = map[string]bool{
+	"!": true, "!!null": true, "!!bool": true, "!!int": true, "!!float": true, "!!str": true,
+	"!!map": true, "!!seq": true, "!!binary": true, "!!timestamp": true, "!!merge": true,
+}
+
+// yamlStrictWalk collects what checkStrictYAML needs from the node trees of a stream.
+type yamlStrictWalk struct {
+	// starts holds the line of every node
+	starts []int
+	// blocks holds the line of every literal or folded block scalar
+	blocks []int
+}
+
+// checkStrictYAML reports the first problem that yaml.Unmarshal tolerates in a valid
+// stream: duplicate mapping keys in any document (Unmarshal only decodes the first),
+// explicit tags outside the core schema, and tabs used for indentation in flow content.
+func checkStrictYAML(data []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	walk := &yamlStrictWalk{}
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := walk.node(&doc, ""); err != nil {
+			return err
+		}
+	}
+
+	return walk.checkTabs(data)
+}
+
+// node checks n and its children; path is the location of n in the document.
+func (w *yamlStrictWalk) node(n *yaml.Node, path string) error {
+	w.starts = append(w.starts, n.Line)
+	if n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		w.blocks = append(w.blocks, n.Line)
+	}
+	if n.Style&yaml.TaggedStyle != 0 && !yamlKnownTags[n.Tag] {
+		return fmt.Errorf("line %d, column %d: unknown tag %s at %s", n.Line, n.Column, n.Tag, displayPath(path))
+	}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, child := range n.Content {
+			if err := w.node(child, path); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range n.Content {
+			if err := w.node(child, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		return w.mapping(n, path)
+	}
+
+	return nil
+}
+
+// mapping checks the keys and values of the mapping n for duplicate scalar keys.
+func (w *yamlStrictWalk) mapping(n *yaml.Node, path string) error {
+	seen := map[string]int{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Kind == yaml.ScalarNode && key.Tag != "!!merge" {
+			if line, ok := seen[key.Value]; ok {
+				return fmt.Errorf("line %d, column %d: duplicate key %q in mapping at %s (first defined at line %d)",
+					key.Line, key.Column, key.Value, displayPath(path), line)
+			}
+			seen[key.Value] = key.Line
+		}
+		if err := w.node(key, path); err != nil {
+			return err
+		}
+		if err := w.node(value, path+"/"+pointerSegment(key.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkTabs reports the first line indented with a tab. Comment lines and the content
+// of block scalars, where tabs are ordinary text, are skipped.
+func (w *yamlStrictWalk) checkTabs(data []byte) error {
+	sort.Ints(w.starts)
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		lineNum := i + 1
+		content := bytes.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(content)]
+		tab := bytes.IndexByte(indent, '\t')
+		if tab < 0 || len(bytes.TrimSpace(content)) == 0 || content[0] == '#' || w.inBlockScalar(lineNum) {
+			continue
+		}
+
+		return fmt.Errorf("line %d, column %d: tab character used for indentation", lineNum, tab+1)
+	}
+
+	return nil
+}
+
+// inBlockScalar reports whether line falls between a block scalar's header and the next node.
+func (w *yamlStrictWalk) inBlockScalar(line int) bool {
+	for _, block := range w.blocks {
+		if line <= block {
+			continue
+		}
+		next := sort.SearchInts(w.starts, block+1)
+		if next == len(w.starts) || line < w.starts[next] {
+			return true
+		}
+	}
+
+	return false
+}
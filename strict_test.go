@@ -0,0 +1,127 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithStrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		valid  bool
+		errSub string
+		line   int
+		column int
+	}{
+		{"plain object", `{"a": 1, "b": [1, 2]}`, true, "", 0, 0},
+		{"same key in sibling objects", `[{"a": 1}, {"a": 2}]`, true, "", 0, 0},
+		{"same key at different depths", `{"a": {"a": 1}}`, true, "", 0, 0},
+		{"duplicate at root", `{"a": 1, "a": 2}`, false, `duplicate key "a" in object at /`, 1, 10},
+		{"duplicate nested", "{\n  \"x\": {\"k\": 1,\n    \"k\": 2}\n}", false,
+			`duplicate key "k" in object at /x`, 3, 5},
+		{"duplicate inside array", `{"items": [{}, {"id": 1, "id": 2}]}`, false,
+			`duplicate key "id" in object at /items/1`, 1, 26},
+		{"escaped quote in key", `{"a\"b": 1, "a\"b": 2}`, false, `duplicate key "a\"b"`, 1, 13},
+		{"invalid utf-8", "{\"a\": \"\xff\"}", false, "invalid UTF-8 at byte offset 7", 1, 8},
+		{"trailing data", `{"a": 1} {"b": 2}`, false, "invalid character", 1, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatJSON, WithStrict())
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Fatalf("Valid = %v, want %v (error %q)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.valid {
+				return
+			}
+			if !strings.Contains(result.Error, tt.errSub) {
+				t.Errorf("Error = %q, want substring %q", result.Error, tt.errSub)
+			}
+			if len(result.Diagnostics) != 1 {
+				t.Fatalf("Diagnostics = %+v, want one entry", result.Diagnostics)
+			}
+			if d := result.Diagnostics[0]; d.Line != tt.line || d.Column != tt.column {
+				t.Errorf("position = %d:%d, want %d:%d", d.Line, d.Column, tt.line, tt.column)
+			}
+		})
+	}
+}
+
+func TestWithStrictDefaults(t *testing.T) {
+	tests := []struct {
+		format Format
+		input  string
+	}{
+		{FormatJSON, `{"a": 1, "a": 2}`},
+		{FormatJSON, "{\"a\": \"\xff\"}"},
+		{FormatYAML, "Name: !Ref Bucket\n"},
+		{FormatYAML, "a: [1,\n\t2]\n"},
+	}
+
+	for _, tt := range tests {
+		v, err := NewValidator(tt.format)
+		if err != nil {
+			t.Fatalf("NewValidator() error = %v", err)
+		}
+		if result := v.ValidateString(tt.input); !result.Valid {
+			t.Errorf("%s Validate(%q) without WithStrict = %+v, want valid", tt.format, tt.input, result)
+		}
+	}
+}
+
+func TestWithStrictYAML(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		errSub string
+		line   int
+		column int
+	}{
+		{"plain mapping", "a: 1\nb:\n  - x\n  - y\n", "", 0, 0},
+		{"core tags", "a: !!str 1\nb: !!binary aGk=\n", "", 0, 0},
+		{"merge keys", "base: &b {x: 1}\nc:\n  <<: *b\n  y: 2\n", "", 0, 0},
+		{"tabs in block scalar", "script: |\n  make\n  \tindented\nnext: 1\n", "", 0, 0},
+		{"tab inside value", "a: \"x\ty\"\n", "", 0, 0},
+		{"duplicate in first document", "a: 1\na: 2\n", `mapping key "a" already defined`, 2, 1},
+		{"duplicate in later document", "a: 1\n---\nb:\n  c: 1\n  c: 2\n",
+			`duplicate key "c" in mapping at /b (first defined at line 4)`, 5, 3},
+		{"unknown tag", "Resources:\n  Name: !Ref Bucket\n", "unknown tag !Ref at /Resources/Name", 2, 9},
+		{"unknown secondary tag", "- !!set {a}\n", "unknown tag !!set at /0", 1, 3},
+		{"tab indentation in flow", "a: [1,\n\t2]\n", "tab character used for indentation", 2, 1},
+		{"syntax error in later document", "a: 1\n---\nb: [\n", "did not find expected", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatYAML, WithStrict())
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != (tt.errSub == "") {
+				t.Fatalf("Valid = %v, want %v (error %q)", result.Valid, tt.errSub == "", result.Error)
+			}
+			if result.Valid {
+				return
+			}
+			if !strings.Contains(result.Error, tt.errSub) {
+				t.Errorf("Error = %q, want substring %q", result.Error, tt.errSub)
+			}
+			if tt.line == 0 {
+				return
+			}
+			if len(result.Diagnostics) != 1 {
+				t.Fatalf("Diagnostics = %+v, want one entry", result.Diagnostics)
+			}
+			if d := result.Diagnostics[0]; d.Line != tt.line || d.Column != tt.column {
+				t.Errorf("position = %d:%d, want %d:%d", d.Line, d.Column, tt.line, tt.column)
+			}
+		})
+	}
+}
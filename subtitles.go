@@ -0,0 +1,277 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SRTValidator validates SubRip (.srt) subtitle files.
+// Each cue must have the next sequence number, a "00:00:01,000 --> 00:00:04,000" timing line
+// whose end is not before its start, and at least one line of text. Cues must be in start-time order.
+//
+// Example:
+//
+//	validator := &SRTValidator{baseValidator{format: FormatSRT}}
+//	result := validator.ValidateString("1\n00:00:01,000 --> 00:00:04,000\nHello!\n")
+type SRTValidator struct {
+	baseValidator
+}
+
+// WebVTTValidator validates WebVTT (.vtt) subtitle files.
+// It checks the WEBVTT header, cue timing syntax and settings, and start-time ordering,
+// and skips NOTE, STYLE, and REGION blocks.
+//
+// Example:
+//
+//	validator := &WebVTTValidator{baseValidator{format: FormatWebVTT}}
+//	result := validator.ValidateString("WEBVTT\n\n00:01.000 --> 00:04.000 align:start\nHello!\n")
+type WebVTTValidator struct {
+	baseValidator
+}
+
+// subtitleBlock is a run of non-blank lines and the 1-based line number it starts on.
+type subtitleBlock struct {
+	line  int
+	lines []string
+}
+
+// splitSubtitleBlocks normalizes line endings and groups lines into blank-line separated blocks.
+func splitSubtitleBlocks(data []byte) []subtitleBlock {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var blocks []subtitleBlock
+	var current *subtitleBlock
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			current = nil
+
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, subtitleBlock{line: i + 1})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+
+	return blocks
+}
+
+// Validate checks if the provided byte slice contains a valid SRT file.
+//
+// Example:
+//
+//	validator := &SRTValidator{baseValidator{format: FormatSRT}}
+//	result := validator.Validate(data)
+func (v *SRTValidator) Validate(data []byte) Result {
+	err := validateSRT(data)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *SRTValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateSRT(data []byte) error {
+	blocks := splitSubtitleBlocks(data)
+	if len(blocks) == 0 {
+		return errors.New("no subtitle cues found")
+	}
+
+	var prevStart time.Duration
+	for i, block := range blocks {
+		if n, err := strconv.Atoi(strings.TrimSpace(block.lines[0])); err != nil || n != i+1 {
+			return fmt.Errorf("line %d: expected sequence number %d, got %q", block.line, i+1, block.lines[0])
+		}
+		if len(block.lines) < 2 {
+			return fmt.Errorf("line %d: cue %d is missing its timing line", block.line, i+1)
+		}
+
+		start, _, _, err := parseCueTiming(block.lines[1], ',', true)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", block.line+1, err)
+		}
+		if start < prevStart {
+			return fmt.Errorf("line %d: cue %d starts before the previous cue", block.line+1, i+1)
+		}
+		prevStart = start
+
+		if len(block.lines) < 3 {
+			return fmt.Errorf("line %d: cue %d has no text", block.line+1, i+1)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks if the provided byte slice contains a valid WebVTT file.
+//
+// Example:
+//
+//	validator := &WebVTTValidator{baseValidator{format: FormatWebVTT}}
+//	result := validator.Validate(data)
+func (v *WebVTTValidator) Validate(data []byte) Result {
+	err := validateWebVTT(data)
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *WebVTTValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateWebVTT(data []byte) error {
+	blocks := splitSubtitleBlocks(data)
+	if len(blocks) == 0 || blocks[0].line != 1 {
+		return errors.New("line 1: missing WEBVTT header")
+	}
+	header := blocks[0].lines[0]
+	if header != "WEBVTT" && !strings.HasPrefix(header, "WEBVTT ") && !strings.HasPrefix(header, "WEBVTT\t") {
+		return fmt.Errorf("line 1: file must start with WEBVTT, got %q", header)
+	}
+	for j, line := range blocks[0].lines[1:] {
+		if strings.Contains(line, "-->") {
+			return fmt.Errorf("line %d: a blank line is required after the header", j+2)
+		}
+	}
+
+	var prevStart time.Duration
+	for _, block := range blocks[1:] {
+		first := block.lines[0]
+		if first == "NOTE" || strings.HasPrefix(first, "NOTE ") || strings.HasPrefix(first, "NOTE\t") ||
+			first == "STYLE" || first == "REGION" {
+			continue
+		}
+
+		// An optional cue identifier precedes the timing line
+		timing, timingLine := first, block.line
+		if !strings.Contains(first, "-->") {
+			if len(block.lines) < 2 {
+				return fmt.Errorf("line %d: expected a cue timing line", block.line)
+			}
+			timing, timingLine = block.lines[1], block.line+1
+		}
+
+		start, _, settings, err := parseCueTiming(timing, '.', false)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", timingLine, err)
+		}
+		if err := checkVTTSettings(settings); err != nil {
+			return fmt.Errorf("line %d: %w", timingLine, err)
+		}
+		if start < prevStart {
+			return fmt.Errorf("line %d: cue starts before the previous cue", timingLine)
+		}
+		prevStart = start
+	}
+
+	return nil
+}
+
+// parseCueTiming parses "start --> end [settings]". fracSep is ',' for SRT and '.' for WebVTT;
+// SRT requires the hours field while WebVTT makes it optional.
+func parseCueTiming(line string, fracSep byte, requireHours bool) (time.Duration, time.Duration, string, error) {
+	left, right, ok := strings.Cut(line, "-->")
+	if !ok {
+		return 0, 0, "", fmt.Errorf("invalid timing line %q: missing '-->'", line)
+	}
+
+	start, err := parseCueTimestamp(strings.TrimSpace(left), fracSep, requireHours)
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	right = strings.TrimLeft(right, " \t")
+	endText, settings, _ := strings.Cut(right, " ")
+	end, err := parseCueTimestamp(endText, fracSep, requireHours)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if end < start {
+		return 0, 0, "", fmt.Errorf("cue ends (%s) before it starts (%s)", endText, strings.TrimSpace(left))
+	}
+
+	return start, end, strings.TrimSpace(settings), nil
+}
+
+// parseCueTimestamp parses [hh:]mm:ss<sep>ttt.
+func parseCueTimestamp(ts string, fracSep byte, requireHours bool) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid timestamp %q (expected hh:mm:ss%cmmm)", ts, fracSep)
+
+	clock, millis, ok := strings.Cut(ts, string(fracSep))
+	if !ok || len(millis) != 3 || !isDecimalString(millis) {
+		return 0, invalid
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) != 3 && (requireHours || len(parts) != 2) {
+		return 0, invalid
+	}
+
+	var hours int
+	if len(parts) == 3 {
+		if len(parts[0]) < 2 || !isDecimalString(parts[0]) {
+			return 0, invalid
+		}
+		hours, _ = strconv.Atoi(parts[0])
+		parts = parts[1:]
+	}
+	for _, p := range parts {
+		if len(p) != 2 || !isDecimalString(p) {
+			return 0, invalid
+		}
+	}
+	minutes, _ := strconv.Atoi(parts[0])
+	seconds, _ := strconv.Atoi(parts[1])
+	if minutes > 59 || seconds > 59 {
+		return 0, invalid
+	}
+	ms, _ := strconv.Atoi(millis)
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+// checkVTTSettings validates cue settings such as "align:start line:0 position:10%".
+func checkVTTSettings(settings string) error {
+	for _, setting := range strings.Fields(settings) {
+		name, value, ok := strings.Cut(setting, ":")
+		if !ok || value == "" {
+			return fmt.Errorf("invalid cue setting %q: expected name:value", setting)
+		}
+		switch name {
+		case "vertical", "line", "position", "size", "align", "region":
+		default:
+			return fmt.Errorf("unknown cue setting %q", name)
+		}
+	}
+
+	return nil
+}
+
+// detectSubtitles identifies WebVTT by its header and SRT by a leading "1" cue with a timing line.
+func detectSubtitles(trimmed string, lines []string) Format {
+	if trimmed == "WEBVTT" || strings.HasPrefix(trimmed, "WEBVTT\n") || strings.HasPrefix(trimmed, "WEBVTT ") ||
+		strings.HasPrefix(trimmed, "WEBVTT\r") {
+		return FormatWebVTT
+	}
+	if len(lines) >= 2 && strings.TrimSpace(lines[0]) == "1" && strings.Contains(lines[1], "-->") {
+		return FormatSRT
+	}
+
+	return FormatUnknown
+}
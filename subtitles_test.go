@@ -0,0 +1,115 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSRTValidator(t *testing.T) {
+	v := &SRTValidator{baseValidator{format: FormatSRT}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"single cue", "1\n00:00:01,000 --> 00:00:04,000\nHello!\n", true, ""},
+		{
+			"two cues crlf",
+			"1\r\n00:00:01,000 --> 00:00:02,500\r\nOne\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nTwo\r\n",
+			true, "",
+		},
+		{"multi-line text", "1\n00:00:01,000 --> 00:00:04,000\nline one\nline two\n", true, ""},
+		{"empty", "", false, "no subtitle cues"},
+		{
+			"wrong sequence",
+			"1\n00:00:01,000 --> 00:00:02,000\nA\n\n3\n00:00:03,000 --> 00:00:04,000\nB\n",
+			false, "expected sequence number 2",
+		},
+		{"dot separator", "1\n00:00:01.000 --> 00:00:04.000\nHi\n", false, "invalid timestamp"},
+		{"missing arrow", "1\n00:00:01,000 00:00:04,000\nHi\n", false, "missing '-->'"},
+		{"end before start", "1\n00:00:05,000 --> 00:00:04,000\nHi\n", false, "before it starts"},
+		{
+			"out of order",
+			"1\n00:00:05,000 --> 00:00:06,000\nA\n\n2\n00:00:01,000 --> 00:00:02,000\nB\n",
+			false, "before the previous",
+		},
+		{"missing text", "1\n00:00:01,000 --> 00:00:04,000\n", false, "has no text"},
+		{"bad minutes", "1\n00:75:01,000 --> 00:76:04,000\nHi\n", false, "invalid timestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestWebVTTValidator(t *testing.T) {
+	v := &WebVTTValidator{baseValidator{format: FormatWebVTT}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"minimal", "WEBVTT\n", true, ""},
+		{"short timestamps", "WEBVTT\n\n00:01.000 --> 00:04.000\nHello\n", true, ""},
+		{
+			"identifier and settings",
+			"WEBVTT - Title\n\nintro\n00:00:01.000 --> 00:00:04.000 align:start line:0\nHi\n",
+			true, "",
+		},
+		{
+			"note and style",
+			"WEBVTT\n\nNOTE a comment\n\nSTYLE\n::cue { color: red }\n\n00:01.000 --> 00:02.000\nA\n",
+			true, "",
+		},
+		{"missing header", "00:01.000 --> 00:04.000\nHello\n", false, "WEBVTT"},
+		{"wrong header", "WEBVTTX\n", false, "must start with WEBVTT"},
+		{"comma separator", "WEBVTT\n\n00:01,000 --> 00:04,000\nHi\n", false, "invalid timestamp"},
+		{"unknown setting", "WEBVTT\n\n00:01.000 --> 00:04.000 color:red\nHi\n", false, "unknown cue setting"},
+		{"cue in header block", "WEBVTT\n00:01.000 --> 00:04.000\nHi\n", false, "blank line is required"},
+		{
+			"out of order",
+			"WEBVTT\n\n00:05.000 --> 00:06.000\nA\n\n00:01.000 --> 00:02.000\nB\n",
+			false, "before the previous",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestDetectSubtitles(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Format
+	}{
+		{"WEBVTT\n\n00:01.000 --> 00:04.000\nHello\n", FormatWebVTT},
+		{"1\n00:00:01,000 --> 00:00:04,000\nHello!\n", FormatSRT},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat([]byte(tt.input)); got != tt.want {
+			t.Errorf("DetectFormat(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
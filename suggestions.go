@@ -0,0 +1,82 @@
+package serdeval
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// trailingCommaRe matches a comma followed only by whitespace before a closing bracket
+	trailingCommaRe = regexp.MustCompile(`,\s*[}\]]`)
+	// tomlBareValueRe extracts the unquoted word from BurntSushi/toml "expected value" errors
+	tomlBareValueRe = regexp.MustCompile(`last key "([^"]+)"\): expected value but found "([^"]*)" instead`)
+)
+
+// suggestFix returns an actionable hint for common mistakes behind a validation
+// error, or an empty string when no specific advice applies.
+//
+// Example:
+//
+//	suggestFix(FormatJSON, []byte(`{"a": 1,}`), err.Error())
+//	// "remove the trailing comma before the closing bracket; JSON does not allow trailing commas"
+func suggestFix(format Format, data []byte, errMsg string) string {
+	if errMsg == "" {
+		return ""
+	}
+
+	switch format {
+	case FormatJSON, FormatJSONL, FormatJupyter:
+		return suggestJSONFix(data, errMsg)
+	case FormatYAML:
+		return suggestYAMLFix(data, errMsg)
+	case FormatTOML:
+		return suggestTOMLFix(errMsg)
+	default:
+		return ""
+	}
+}
+
+// suggestJSONFix recognizes trailing commas, single quotes, unquoted keys, and comments.
+func suggestJSONFix(data []byte, errMsg string) string {
+	switch {
+	case (strings.Contains(errMsg, "invalid character '}'") || strings.Contains(errMsg, "invalid character ']'")) &&
+		trailingCommaRe.Match(data):
+		return "remove the trailing comma before the closing bracket; JSON does not allow trailing commas"
+	case strings.Contains(errMsg, `invalid character '\''`):
+		return "use double quotes for strings and keys; JSON does not allow single quotes"
+	case strings.Contains(errMsg, "invalid character '/'") || strings.Contains(errMsg, "invalid character '#'"):
+		return "remove comments; JSON does not support comments"
+	case strings.Contains(errMsg, "looking for beginning of object key string"):
+		return `wrap object keys in double quotes, e.g. {"key": 1}`
+	}
+
+	return ""
+}
+
+// suggestYAMLFix recognizes tab indentation and unquoted values containing ": ".
+func suggestYAMLFix(data []byte, errMsg string) string {
+	switch {
+	case strings.Contains(errMsg, "found character that cannot start any token") &&
+		(bytes.HasPrefix(data, []byte("\t")) || bytes.Contains(data, []byte("\n\t"))):
+		return "replace tabs with spaces; YAML does not allow tabs for indentation"
+	case strings.Contains(errMsg, "mapping values are not allowed in this context"):
+		return `quote values that contain ": ", e.g. key: "a: b"`
+	}
+
+	return ""
+}
+
+// suggestTOMLFix recognizes unquoted string values and unterminated strings.
+func suggestTOMLFix(errMsg string) string {
+	if m := tomlBareValueRe.FindStringSubmatch(errMsg); m != nil {
+		return fmt.Sprintf(`quote string values, e.g. %s = "%s"`, m[1], m[2])
+	}
+	if strings.Contains(errMsg, "unexpected EOF; expected \"'\"") ||
+		strings.Contains(errMsg, `unexpected EOF; expected "\""`) {
+		return "close the quoted string"
+	}
+
+	return ""
+}
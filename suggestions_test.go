@@ -0,0 +1,45 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		want   string
+	}{
+		{"json trailing comma object", FormatJSON, `{"a": 1,}`, "trailing comma"},
+		{"json trailing comma array", FormatJSON, `[1, 2, ]`, "trailing comma"},
+		{"json single quotes", FormatJSON, `{'a': 1}`, "double quotes"},
+		{"json unquoted key", FormatJSON, `{a: 1}`, "wrap object keys"},
+		{"json comment", FormatJSON, "{\"a\": 1 // note\n}", "comments"},
+		{"jsonl trailing comma", FormatJSONL, "{\"a\": 1}\n{\"b\": 2,}", "trailing comma"},
+		{"yaml tab indentation", FormatYAML, "a:\n\tb: 1", "tabs"},
+		{"yaml unquoted colon", FormatYAML, "title: note: read this", `quote values that contain ": "`},
+		{"toml bare string", FormatTOML, "name = hello", `name = "hello"`},
+		{"toml unterminated", FormatTOML, "a = 'x", "close the quoted string"},
+		{"json no hint", FormatJSON, `{"a": }`, ""},
+		{"valid json", FormatJSON, `{"a": 1}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, _ := NewValidator(tt.format)
+			result := v.ValidateString(tt.input)
+			if tt.want == "" {
+				if result.Suggestion != "" {
+					t.Errorf("Suggestion = %q, want none", result.Suggestion)
+				}
+
+				return
+			}
+			if !strings.Contains(result.Suggestion, tt.want) {
+				t.Errorf("Suggestion = %q, want it to contain %q", result.Suggestion, tt.want)
+			}
+		})
+	}
+}
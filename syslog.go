@@ -0,0 +1,285 @@
+package serdeval
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyslogValidator validates syslog messages, one per line.
+// RFC 5424 messages are checked field by field (PRI, version, timestamp, header fields,
+// and structured data brackets); legacy RFC 3164 messages are checked for a valid PRI,
+// BSD timestamp, and hostname.
+//
+// Example:
+//
+//	validator := &SyslogValidator{baseValidator{format: FormatSyslog}}
+//	result := validator.ValidateString(`<34>1 2003-10-11T22:14:15.003Z host su - ID47 - 'su root' failed`)
+type SyslogValidator struct {
+	baseValidator
+}
+
+// Validate checks if every non-blank line of the provided byte slice is a valid syslog message.
+//
+// Example:
+//
+//	validator := &SyslogValidator{baseValidator{format: FormatSyslog}}
+//	result := validator.Validate([]byte("<13>Oct 11 22:14:15 mymachine su: 'su root' failed\n"))
+func (v *SyslogValidator) Validate(data []byte) Result {
+	var err error
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if lineErr := parseSyslogMessage(line); lineErr != nil {
+			err = fmt.Errorf("line %d: %w", i+1, lineErr)
+
+			break
+		}
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *SyslogValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// parseSyslogMessage validates a single message, choosing RFC 5424 or RFC 3164
+// based on what follows the PRI.
+func parseSyslogMessage(line string) error {
+	rest, err := parseSyslogPRI(line)
+	if err != nil {
+		return err
+	}
+
+	if rest != "" && rest[0] >= '1' && rest[0] <= '9' {
+		return parseSyslog5424(rest)
+	}
+
+	return parseSyslog3164(rest)
+}
+
+// parseSyslogPRI consumes "<N>" where N is 0-191 without leading zeros.
+func parseSyslogPRI(line string) (string, error) {
+	if !strings.HasPrefix(line, "<") {
+		return "", errors.New("missing PRI: message must start with '<'")
+	}
+	end := strings.IndexByte(line, '>')
+	if end < 0 {
+		return "", errors.New("unterminated PRI: missing '>'")
+	}
+	pri := line[1:end]
+	if pri == "" || len(pri) > 3 || (len(pri) > 1 && pri[0] == '0') || !isDecimalString(pri) {
+		return "", fmt.Errorf("invalid PRI %q", pri)
+	}
+	if n, _ := strconv.Atoi(pri); n > 191 {
+		return "", fmt.Errorf("PRI %d out of range (0-191)", n)
+	}
+
+	return line[end+1:], nil
+}
+
+// parseSyslog5424 validates VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID SP SD [SP MSG].
+func parseSyslog5424(rest string) error {
+	fields := strings.SplitN(rest, " ", 7)
+	if len(fields) < 7 {
+		return fmt.Errorf("RFC 5424 header has %d fields, expected 7 before structured data", len(fields))
+	}
+
+	if version := fields[0]; len(version) > 3 || !isDecimalString(version) {
+		return fmt.Errorf("invalid version %q", version)
+	}
+	if err := checkSyslogTimestamp(fields[1]); err != nil {
+		return err
+	}
+
+	limits := []struct {
+		name string
+		max  int
+	}{
+		{"hostname", 255},
+		{"app-name", 48},
+		{"procid", 128},
+		{"msgid", 32},
+	}
+	for i, limit := range limits {
+		value := fields[i+2]
+		if value == "" {
+			return fmt.Errorf("empty %s; use '-' for a nil value", limit.name)
+		}
+		if len(value) > limit.max {
+			return fmt.Errorf("%s longer than %d characters", limit.name, limit.max)
+		}
+		if !isPrintASCII(value) {
+			return fmt.Errorf("%s contains non-printable characters", limit.name)
+		}
+	}
+
+	rest = fields[6]
+	if rest == "-" || strings.HasPrefix(rest, "- ") {
+		return nil
+	}
+	rest, err := parseSyslogStructuredData(rest)
+	if err != nil {
+		return err
+	}
+	if rest != "" && rest[0] != ' ' {
+		return errors.New("missing space between structured data and message")
+	}
+
+	return nil
+}
+
+// checkSyslogTimestamp accepts "-" or an RFC 3339 timestamp with at most microsecond precision.
+func checkSyslogTimestamp(ts string) error {
+	if ts == "-" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		return fmt.Errorf("invalid timestamp %q: expected RFC 3339", ts)
+	}
+	if dot := strings.IndexByte(ts, '.'); dot >= 0 {
+		frac := strings.IndexAny(ts[dot:], "Z+-")
+		if frac-1 > 6 {
+			return fmt.Errorf("invalid timestamp %q: more than 6 fractional digits", ts)
+		}
+	}
+
+	return nil
+}
+
+// parseSyslogStructuredData consumes one or more [SD-ID param="value" ...] elements.
+func parseSyslogStructuredData(s string) (string, error) {
+	if s == "" || s[0] != '[' {
+		return "", errors.New("structured data must be '-' or start with '['")
+	}
+
+	for s != "" && s[0] == '[' {
+		s = s[1:]
+		id, rest := scanSyslogName(s)
+		if id == "" {
+			return "", errors.New("structured data element missing SD-ID")
+		}
+		s = rest
+
+		for s != "" && s[0] == ' ' {
+			name, rest := scanSyslogName(s[1:])
+			if name == "" {
+				return "", fmt.Errorf("element [%s]: missing parameter name", id)
+			}
+			if !strings.HasPrefix(rest, `="`) {
+				return "", fmt.Errorf("element [%s]: parameter %s must be followed by =\"value\"", id, name)
+			}
+			s = rest[2:]
+
+			closed := false
+			for i := 0; i < len(s); i++ {
+				switch s[i] {
+				case '\\':
+					i++
+				case ']':
+					return "", fmt.Errorf("element [%s]: unescaped ']' in parameter %s", id, name)
+				case '"':
+					s, closed = s[i+1:], true
+				}
+				if closed {
+					break
+				}
+			}
+			if !closed {
+				return "", fmt.Errorf("element [%s]: unterminated value for parameter %s", id, name)
+			}
+		}
+
+		if s == "" || s[0] != ']' {
+			return "", fmt.Errorf("element [%s]: missing closing ']'", id)
+		}
+		s = s[1:]
+	}
+
+	return s, nil
+}
+
+// scanSyslogName reads an SD-NAME: 1-32 printable characters other than '=', ' ', ']', and '"'.
+func scanSyslogName(s string) (string, string) {
+	i := 0
+	for i < len(s) && i < 32 && s[i] > ' ' && s[i] < 0x7F && s[i] != '=' && s[i] != ']' && s[i] != '"' {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// parseSyslog3164 validates the BSD "Mmm dd hh:mm:ss HOSTNAME" header.
+func parseSyslog3164(rest string) error {
+	const stamp = "Jan _2 15:04:05"
+	if len(rest) < len(stamp)+1 {
+		return errors.New("missing RFC 3164 timestamp (Mmm dd hh:mm:ss)")
+	}
+	if _, err := time.Parse(stamp, rest[:len(stamp)]); err != nil {
+		return fmt.Errorf("invalid RFC 3164 timestamp %q", rest[:len(stamp)])
+	}
+	rest = rest[len(stamp):]
+	if rest[0] != ' ' {
+		return errors.New("missing space after timestamp")
+	}
+
+	host, _, _ := strings.Cut(rest[1:], " ")
+	if host == "" {
+		return errors.New("missing hostname after timestamp")
+	}
+	if !isPrintASCII(host) {
+		return errors.New("hostname contains non-printable characters")
+	}
+
+	return nil
+}
+
+func isDecimalString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isIonDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isPrintASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '!' || s[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// detectSyslog reports whether every non-blank line is a syslog message.
+func detectSyslog(lines []string) bool {
+	found := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if parseSyslogMessage(line) != nil {
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
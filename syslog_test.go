@@ -0,0 +1,61 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyslogValidator(t *testing.T) {
+	v := &SyslogValidator{baseValidator{format: FormatSyslog}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"rfc5424 minimal", `<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - 'su root' failed`, true, ""},
+		{"rfc5424 nil fields", `<165>1 - - - - - -`, true, ""},
+		{
+			"rfc5424 structured data",
+			`<165>1 2003-10-11T22:14:15.003-07:00 host evntslog - ID47 ` +
+				`[exampleSDID@32473 iut="3" eventSource="App\]lication"][examplePriority@32473 class="high"] msg`,
+			true, "",
+		},
+		{"rfc3164", `<13>Oct  1 22:14:15 mymachine su: 'su root' failed`, true, ""},
+		{"multiple lines", "<13>Oct 11 22:14:15 host app: a\n<14>1 - host app - - - b\n", true, ""},
+		{"missing pri", `Oct 11 22:14:15 host app: a`, false, "missing PRI"},
+		{"pri out of range", `<192>1 - - - - - -`, false, "out of range"},
+		{"pri leading zero", `<013>1 - - - - - -`, false, "invalid PRI"},
+		{"bad timestamp", `<34>1 2003-13-11T22:14:15Z host su - ID47 -`, false, "invalid timestamp"},
+		{"too many fraction digits", `<34>1 2003-10-11T22:14:15.0000001Z host su - ID47 -`, false, "fractional"},
+		{"missing fields", `<34>1 2003-10-11T22:14:15Z host`, false, "fields"},
+		{"unclosed sd", `<34>1 - host app - - [id a="1"`, false, "missing closing"},
+		{"unescaped bracket", `<34>1 - host app - - [id a="x]y"]`, false, "unescaped"},
+		{"sd without quotes", `<34>1 - host app - - [id a=1]`, false, "=\"value\""},
+		{"bad bsd timestamp", "<13>Foo 11 22:14:15 host app: a", false, "3164 timestamp"},
+		{"error line number", "<13>Oct 11 22:14:15 host a\n<13>bad", false, "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestDetectSyslog(t *testing.T) {
+	input := "<34>1 2003-10-11T22:14:15.003Z host su - ID47 - failed\n<13>Oct 11 22:14:15 host app: ok"
+	if got := DetectFormat([]byte(input)); got != FormatSyslog {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatSyslog)
+	}
+	if got := DetectFormat([]byte("<root><a>1</a></root>")); got != FormatXML {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatXML)
+	}
+}
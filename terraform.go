@@ -0,0 +1,149 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+)
+
+// parseHCL parses data as HCL native syntax, or as HCL JSON syntax (.tf.json) when the
+// first non-space byte opens a JSON object, which native syntax never starts with.
+func parseHCL(data []byte) (*hcl.File, hcl.Diagnostics) {
+	trimmed := strings.TrimLeft(string(data), " \t\r\n\ufeff")
+	if strings.HasPrefix(trimmed, "{") {
+		return hcljson.Parse(data, "hcl")
+	}
+
+	return hclsyntax.ParseConfig(data, "hcl", hcl.InitialPos)
+}
+
+// terraformSchema lists the top-level blocks of a Terraform configuration and their labels.
+var terraformSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "terraform"},
+		{Type: "provider", LabelNames: []string{"name"}},
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "locals"},
+		{Type: "output", LabelNames: []string{"name"}},
+		{Type: "module", LabelNames: []string{"name"}},
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+		{Type: "data", LabelNames: []string{"type", "name"}},
+		{Type: "ephemeral", LabelNames: []string{"type", "name"}},
+		{Type: "check", LabelNames: []string{"name"}},
+		{Type: "moved"},
+		{Type: "import"},
+		{Type: "removed"},
+	},
+}
+
+// terraformRequired lists the arguments each block type must set.
+var terraformRequired = map[string][]string{
+	"output":  {"value"},
+	"module":  {"source"},
+	"moved":   {"from", "to"},
+	"import":  {"to"},
+	"removed": {"from"},
+}
+
+// terraformUnique holds the block types whose labels must not repeat within a module.
+// Provider blocks may repeat with different aliases.
+var terraformUnique = map[string]bool{
+	"variable": true, "output": true, "module": true, "resource": true, "data": true, "ephemeral": true, "check": true,
+}
+
+// terraformReservedVariables are the variable names Terraform rejects because module blocks
+// use them as meta-arguments.
+var terraformReservedVariables = map[string]bool{
+	"source": true, "version": true, "providers": true, "count": true, "for_each": true,
+	"lifecycle": true, "depends_on": true, "locals": true,
+}
+
+// checkTerraform checks the block structure of a Terraform configuration file: known
+// top-level block types with the right number of valid labels, the arguments output,
+// module, moved, import, and removed blocks require, and names defined twice.
+// A body with only arguments is a variable definitions (.tfvars) file and passes.
+func checkTerraform(body hcl.Body) hcl.Diagnostics {
+	if isTerraformVariables(body) {
+		return nil
+	}
+
+	content, diags := body.Content(terraformSchema)
+	if content == nil {
+		return diags
+	}
+	seen := make(map[string]*hcl.Block, len(content.Blocks))
+	for _, block := range content.Blocks {
+		diags = append(diags, checkTerraformLabels(block)...)
+		if terraformUnique[block.Type] {
+			key := block.Type + " " + strings.Join(quoteLabels(block.Labels), " ")
+			if first, ok := seen[key]; ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate " + block.Type + " block",
+					Detail:   fmt.Sprintf("A %s was already defined at line %d.", key, first.DefRange.Start.Line),
+					Subject:  block.DefRange.Ptr(),
+				})
+			}
+			seen[key] = block
+		}
+		if required := terraformRequired[block.Type]; len(required) > 0 {
+			schema := &hcl.BodySchema{}
+			for _, name := range required {
+				schema.Attributes = append(schema.Attributes, hcl.AttributeSchema{Name: name, Required: true})
+			}
+			_, _, attrDiags := block.Body.PartialContent(schema)
+			diags = append(diags, attrDiags...)
+		}
+	}
+
+	return diags
+}
+
+// isTerraformVariables reports whether body defines only arguments and no Terraform blocks.
+func isTerraformVariables(body hcl.Body) bool {
+	if native, ok := body.(*hclsyntax.Body); ok && len(native.Blocks) > 0 {
+		return false
+	}
+	content, _, _ := body.PartialContent(terraformSchema)
+
+	return content == nil || len(content.Blocks) == 0
+}
+
+// checkTerraformLabels reports block labels that are not valid Terraform names.
+func checkTerraformLabels(block *hcl.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for i, label := range block.Labels {
+		var detail string
+		switch {
+		case !hclsyntax.ValidIdentifier(label):
+			detail = "A name must start with a letter or underscore and may contain only letters, digits, " +
+				"underscores, and dashes."
+		case block.Type == "variable" && terraformReservedVariables[label]:
+			detail = fmt.Sprintf("The variable name %q is reserved due to its special meaning inside module blocks.",
+				label)
+		default:
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid " + block.Type + " name",
+			Detail:   detail,
+			Subject:  block.LabelRanges[i].Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// quoteLabels returns labels as quoted strings, as they appear in native syntax.
+func quoteLabels(labels []string) []string {
+	quoted := make([]string, len(labels))
+	for i, label := range labels {
+		quoted[i] = fmt.Sprintf("%q", label)
+	}
+
+	return quoted
+}
@@ -0,0 +1,95 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithTerraform(t *testing.T) {
+	v, err := NewValidator(FormatHCL, WithTerraform())
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"configuration", `terraform {
+  required_version = ">= 1.5"
+}
+
+variable "region" {
+  default = "us-west-2"
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+
+output "ip" {
+  value = aws_instance.web.public_ip
+}
+
+module "vpc" {
+  source = "./vpc"
+}
+
+moved {
+  from = aws_instance.old
+  to   = aws_instance.web
+}`, true, ""},
+		{"variable definitions", "region = \"eu-west-1\"\ncount = 3\n", true, ""},
+		{"json configuration", `{"resource": {"aws_instance": {"web": {"ami": "ami-123"}}},
+"output": {"ip": {"value": "${aws_instance.web.public_ip}"}}}`, true, ""},
+		{"json variable definitions", `{"region": "eu-west-1"}`, true, ""},
+		{"unknown block", `resources "aws_instance" "web" {}`, false, `Unsupported block type`},
+		{"missing resource name", `resource "aws_instance" {}`, false, `Missing name for resource`},
+		{"extra variable label", `variable "a" "b" {}`, false, `Extraneous label for variable`},
+		{"invalid name", `resource "aws_instance" "1web" {}`, false, `Invalid resource name`},
+		{"reserved variable", `variable "count" {}`, false, `The variable name "count" is reserved`},
+		{"output without value", `output "ip" { description = "x" }`, false,
+			`Missing required argument; The argument "value" is required`},
+		{"module without source", `module "vpc" {}`, false, `The argument "source" is required`},
+		{"duplicate resource", "resource \"aws_instance\" \"web\" {}\nresource \"aws_instance\" \"web\" {}", false,
+			`Duplicate resource block; A resource "aws_instance" "web" was already defined at line 1.`},
+		{"top-level argument", "region = \"x\"\nresource \"a\" \"b\" {}", false, `Unsupported argument`},
+		{"json missing value", `{"output": {"ip": {"description": "x"}}}`, false, `The argument "value" is required`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestWithTerraformDiagnostics(t *testing.T) {
+	v, err := NewValidator(FormatHCL, WithTerraform())
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	result := v.ValidateString("resource \"a\" \"b\" {}\n\nresource \"a\" \"b\" {}\n")
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Line != 3 || result.Diagnostics[0].Column != 1 {
+		t.Errorf("Diagnostics = %+v, want line 3, column 1", result.Diagnostics)
+	}
+}
+
+func TestDetectHCLJSON(t *testing.T) {
+	for _, filename := range []string{"main.tf.json", "prod.tfvars.json", "infra/OVERRIDE.TF.JSON"} {
+		if got := DetectFormatFromFilename(filename); got != FormatHCL {
+			t.Errorf("DetectFormatFromFilename(%q) = %v, want %v", filename, got, FormatHCL)
+		}
+	}
+	if got := DetectFormatFromFilename("package.json"); got != FormatJSON {
+		t.Errorf("DetectFormatFromFilename(package.json) = %v, want %v", got, FormatJSON)
+	}
+}
@@ -0,0 +1,284 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// traefikServiceTypes are the mutually exclusive kinds of Traefik service.
+var traefikServiceTypes = []string{"loadBalancer", "weighted", "mirroring", "failover"}
+
+// TraefikValidator validates Traefik dynamic configuration in YAML or TOML.
+// It checks the http/tcp/udp/tls structure, that each router has a rule (HTTP/TCP) and service,
+// that each service and middleware declares exactly one type, and that routers and
+// weighted/mirroring services reference services and middlewares defined in the same file.
+// References to other providers (name@provider) are not checked.
+//
+// Example:
+//
+//	validator := &TraefikValidator{baseValidator{format: FormatTraefik}}
+//	data, _ := os.ReadFile("dynamic/routes.yml")
+//	result := validator.Validate(data)
+type TraefikValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a valid Traefik dynamic configuration.
+// Documents starting with a [table] header are parsed as TOML, everything else as YAML.
+//
+// Example:
+//
+//	validator := &TraefikValidator{baseValidator{format: FormatTraefik}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. "http.routers.api: service \"api-svc\" is not defined"
+//	}
+func (v *TraefikValidator) Validate(data []byte) Result {
+	var config map[string]interface{}
+	var err error
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		if err = toml.Unmarshal(data, &config); err != nil {
+			err = fmt.Errorf("invalid TOML: %w", err)
+		}
+	} else if err = yaml.Unmarshal(data, &config); err != nil {
+		err = fmt.Errorf("invalid YAML: %w", err)
+	}
+	if err == nil {
+		err = validateTraefikConfig(normalizeTOMLValue(config).(map[string]interface{}))
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *TraefikValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// normalizeTOMLValue converts TOML arrays of tables into the []interface{} form YAML produces.
+func normalizeTOMLValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if t == nil {
+			return map[string]interface{}{}
+		}
+		for k, item := range t {
+			t[k] = normalizeTOMLValue(item)
+		}
+
+		return t
+	case []map[string]interface{}:
+		items := make([]interface{}, len(t))
+		for i, item := range t {
+			items[i] = normalizeTOMLValue(item)
+		}
+
+		return items
+	case []interface{}:
+		for i, item := range t {
+			t[i] = normalizeTOMLValue(item)
+		}
+
+		return t
+	}
+
+	return v
+}
+
+func validateTraefikConfig(config map[string]interface{}) error {
+	if err := checkAllowedKeys(config, "", []string{"http", "tcp", "udp", "tls"}); err != nil {
+		return err
+	}
+
+	for _, protocol := range []string{"http", "tcp", "udp"} {
+		section, err := optionalMap(config, protocol, protocol)
+		if err != nil {
+			return err
+		}
+		if err := validateTraefikProtocol(protocol, section); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateTraefikProtocol(protocol string, section map[string]interface{}) error {
+	allowed := []string{"routers", "services"}
+	if protocol != "udp" {
+		allowed = append(allowed, "middlewares")
+	}
+	if protocol == "http" {
+		allowed = append(allowed, "serversTransports")
+	}
+	if err := checkAllowedKeys(section, protocol, allowed); err != nil {
+		return err
+	}
+
+	services, err := optionalMap(section, "services", protocol+".services")
+	if err != nil {
+		return err
+	}
+	middlewares, err := optionalMap(section, "middlewares", protocol+".middlewares")
+	if err != nil {
+		return err
+	}
+	routers, err := optionalMap(section, "routers", protocol+".routers")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(services) {
+		if err := checkTraefikService(protocol, name, services[name], services); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(middlewares) {
+		m, ok := middlewares[name].(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return fmt.Errorf("%s.middlewares.%s: must declare exactly one middleware type", protocol, name)
+		}
+	}
+	for _, name := range sortedKeys(routers) {
+		if err := checkTraefikRouter(protocol, name, routers[name], services, middlewares); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkTraefikRouter(protocol, name string, raw interface{}, services, middlewares map[string]interface{}) error {
+	path := fmt.Sprintf("%s.routers.%s", protocol, name)
+	router, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: router must be a mapping", path)
+	}
+
+	if protocol != "udp" {
+		if rule, _ := router["rule"].(string); rule == "" {
+			return fmt.Errorf("%s: missing required field rule", path)
+		}
+	}
+	service, _ := router["service"].(string)
+	if service == "" {
+		return fmt.Errorf("%s: missing required field service", path)
+	}
+	if !traefikRefDefined(service, services) {
+		return fmt.Errorf("%s: service %q is not defined", path, service)
+	}
+
+	refs, err := optionalStringList(router, "middlewares", path+".middlewares")
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if !traefikRefDefined(ref, middlewares) {
+			return fmt.Errorf("%s: middleware %q is not defined", path, ref)
+		}
+	}
+
+	return nil
+}
+
+func checkTraefikService(protocol, name string, raw interface{}, services map[string]interface{}) error {
+	path := fmt.Sprintf("%s.services.%s", protocol, name)
+	service, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: service must be a mapping", path)
+	}
+
+	var kinds []string
+	for _, kind := range traefikServiceTypes {
+		if _, ok := service[kind]; ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) != 1 || len(service) != 1 {
+		return fmt.Errorf("%s: must declare exactly one of %s", path, strings.Join(traefikServiceTypes, ", "))
+	}
+
+	body, _ := service[kinds[0]].(map[string]interface{})
+	switch kinds[0] {
+	case "loadBalancer":
+		servers, _ := body["servers"].([]interface{})
+		if len(servers) == 0 {
+			return fmt.Errorf("%s.loadBalancer: servers must list at least one server", path)
+		}
+		key := "url"
+		if protocol != "http" {
+			key = "address"
+		}
+		for i, s := range servers {
+			server, _ := s.(map[string]interface{})
+			if value, _ := server[key].(string); value == "" {
+				return fmt.Errorf("%s.loadBalancer.servers[%d]: missing required field %s", path, i, key)
+			}
+		}
+	case "weighted", "mirroring":
+		var refs []string
+		if ref, ok := body["service"].(string); ok {
+			refs = append(refs, ref)
+		}
+		for _, listKey := range []string{"services", "mirrors"} {
+			items, _ := body[listKey].([]interface{})
+			for _, item := range items {
+				m, _ := item.(map[string]interface{})
+				ref, _ := m["name"].(string)
+				refs = append(refs, ref)
+			}
+		}
+		for _, ref := range refs {
+			if ref == name || !traefikRefDefined(ref, services) {
+				return fmt.Errorf("%s.%s: service %q is not defined", path, kinds[0], ref)
+			}
+		}
+	}
+
+	return nil
+}
+
+// traefikRefDefined reports whether ref names a local entry; name@provider refs are assumed valid.
+func traefikRefDefined(ref string, defined map[string]interface{}) bool {
+	if ref == "" {
+		return false
+	}
+	if strings.Contains(ref, "@") {
+		local, provider, _ := strings.Cut(ref, "@")
+		if provider != "file" {
+			return true
+		}
+		ref = local
+	}
+	_, ok := defined[ref]
+
+	return ok
+}
+
+// isTraefik checks for Traefik dynamic configuration router sections in YAML or TOML.
+func isTraefik(trimmed string, lines []string) bool {
+	if strings.Contains(trimmed, "[http.routers") || strings.Contains(trimmed, "[tcp.routers") {
+		return true
+	}
+
+	var protocol bool
+	for _, line := range lines {
+		switch strings.TrimRight(line, " \t\r") {
+		case "http:", "tcp:", "udp:":
+			protocol = true
+		case "  routers:":
+			if protocol {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const traefikYAML = `http:
+  routers:
+    api:
+      rule: "Host(` + "`api.example.com`" + `)"
+      service: api-svc
+      middlewares: [auth, compress@docker]
+    dashboard:
+      rule: "PathPrefix(` + "`/dashboard`" + `)"
+      service: api@internal
+  services:
+    api-svc:
+      loadBalancer:
+        servers:
+          - url: http://10.0.0.1:8080
+    canary:
+      weighted:
+        services:
+          - name: api-svc
+            weight: 3
+  middlewares:
+    auth:
+      basicAuth:
+        users: ["admin:hash"]
+tcp:
+  routers:
+    db:
+      rule: "HostSNI(` + "`*`" + `)"
+      service: db
+  services:
+    db:
+      loadBalancer:
+        servers:
+          - address: 10.0.0.2:5432
+`
+
+const traefikTOML = `[http.routers.web]
+  rule = "Host(` + "`example.com`" + `)"
+  service = "web"
+
+[http.services.web.loadBalancer]
+  [[http.services.web.loadBalancer.servers]]
+    url = "http://127.0.0.1:8000"
+`
+
+func TestTraefikValidator(t *testing.T) {
+	v := &TraefikValidator{baseValidator{format: FormatTraefik}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"yaml", traefikYAML, true, ""},
+		{"toml", traefikTOML, true, ""},
+		{"invalid yaml", "http:\n  routers: [\n", false, "invalid YAML"},
+		{"invalid toml", "[http.routers.web\n", false, "invalid TOML"},
+		{"unknown top-level key", traefikYAML + "entryPoints:\n  web: {}\n", false, `unknown top-level key "entryPoints"`},
+		{
+			"undefined service",
+			strings.Replace(traefikYAML, "service: api-svc", "service: missing", 1),
+			false, `http.routers.api: service "missing" is not defined`,
+		},
+		{
+			"undefined middleware",
+			strings.Replace(traefikYAML, "[auth, compress@docker]", "[ratelimit]", 1),
+			false, `middleware "ratelimit" is not defined`,
+		},
+		{
+			"file provider reference is checked",
+			strings.Replace(traefikYAML, "[auth, compress@docker]", "[ratelimit@file]", 1),
+			false, `middleware "ratelimit@file" is not defined`,
+		},
+		{
+			"missing rule",
+			strings.Replace(traefikYAML, "      rule: \"Host(`api.example.com`)\"\n", "", 1),
+			false, "http.routers.api: missing required field rule",
+		},
+		{
+			"weighted reference",
+			strings.Replace(traefikYAML, "- name: api-svc", "- name: gone", 1),
+			false, `http.services.canary.weighted: service "gone" is not defined`,
+		},
+		{
+			"server without url",
+			strings.Replace(traefikYAML, "- url: http://10.0.0.1:8080", "- addr: http://10.0.0.1:8080", 1),
+			false, "missing required field url",
+		},
+		{
+			"two middleware types",
+			strings.Replace(traefikYAML, "    auth:\n", "    auth:\n      compress: {}\n", 1),
+			false, "exactly one middleware type",
+		},
+		{
+			"tcp server without address",
+			strings.Replace(traefikYAML, "- address: 10.0.0.2:5432", "- url: 10.0.0.2:5432", 1),
+			false, "missing required field address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	for _, input := range []string{traefikYAML, traefikTOML} {
+		if got := DetectFormat([]byte(input)); got != FormatTraefik {
+			t.Errorf("DetectFormat() = %v, want %v", got, FormatTraefik)
+		}
+	}
+}
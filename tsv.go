@@ -0,0 +1,77 @@
+package serdeval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TSVValidator validates tab-separated values as registered with IANA (text/tab-separated-values):
+// one record per line, fields separated by single tabs, and every record with as many fields
+// as the header line. Fields are not quoted, so quotes and commas are ordinary characters.
+// Blank lines are skipped and CRLF line endings are accepted.
+//
+// Example:
+//
+//	validator := &TSVValidator{baseValidator{format: FormatTSV}}
+//	result := validator.ValidateString("name\tage\nJohn\t30\n")
+type TSVValidator struct {
+	baseValidator
+}
+
+// Validate checks that every record in data has the same number of fields as the header.
+// With WithMaxErrors it reports each mismatched record rather than only the first.
+//
+// Example:
+//
+//	validator := &TSVValidator{baseValidator{format: FormatTSV}}
+//	result := validator.Validate([]byte("id\tname\n1\tAda\n2\n"))
+//	// result.Error == "line 3: wrong number of fields: got 1, header has 2"
+func (v *TSVValidator) Validate(data []byte) Result {
+	return v.validate(context.Background(), data)
+}
+
+// validate checks each line of data, checking ctx between batches of lines.
+func (v *TSVValidator) validate(ctx context.Context, data []byte) Result {
+	errs := v.newErrorCollector()
+	header := 0
+	for i, line := range strings.Split(string(data), "\n") {
+		if i%contextCheckInterval == 0 && ctx.Err() != nil {
+			return canceledResult(v.format, ctx.Err())
+		}
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		fields := strings.Count(line, "\t") + 1
+		if header == 0 {
+			header = fields
+
+			continue
+		}
+		if fields != header {
+			err := fmt.Errorf("line %d: wrong number of fields: got %d, header has %d", i+1, fields, header)
+			if errs.add(err) {
+				break
+			}
+		}
+	}
+	err := errs.err()
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that validates a TSV string.
+func (v *TSVValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// detectTSV reports whether lines look like tab-separated values: a header with at least one
+// tab and the same number of tabs on the following lines.
+func detectTSV(lines []string) bool {
+	return len(lines) > 1 && consistentDelimiters(lines, "\t")
+}
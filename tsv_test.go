@@ -0,0 +1,75 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTSVValidator(t *testing.T) {
+	v := &TSVValidator{baseValidator{format: FormatTSV}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"simple", "name\tage\nJohn\t30\nJane\t25\n", true, ""},
+		{"quotes and commas are data", "title\tnote\n\"Hi\", she said\ta,b,c\n", true, ""},
+		{"empty fields", "a\tb\tc\n\t\t\n", true, ""},
+		{"single column", "name\nJohn\n", true, ""},
+		{"crlf and blank lines", "a\tb\r\n\r\n1\t2\r\n", true, ""},
+		{"empty", "", true, ""},
+		{"missing field", "id\tname\n1\tAda\n2\n", false, "line 3: wrong number of fields: got 1, header has 2"},
+		{"extra field", "id\tname\n1\tAda\tx\n", false, "got 3, header has 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestTSVMaxErrors(t *testing.T) {
+	v, err := NewValidator(FormatTSV, WithMaxErrors(-1))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	result := v.ValidateString("a\tb\n1\n2\t3\n4\t5\t6\n")
+	if len(result.Diagnostics) != 2 || result.Diagnostics[0].Line != 2 || result.Diagnostics[1].Line != 4 {
+		t.Errorf("Diagnostics = %+v, want lines 2 and 4", result.Diagnostics)
+	}
+}
+
+func TestDetectTSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		input    string
+		want     Format
+	}{
+		{"content", "", "id\tname\tcity\n1\tAda\tLondon, UK\n2\tAlan\tWilmslow\n", FormatTSV},
+		{"tsv extension", "export.tsv", "", FormatTSV},
+		{"tab extension", "data.TAB", "", FormatTSV},
+		{"csv content stays csv", "", "a,b\n1,2\n", FormatCSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectFormat([]byte(tt.input))
+			if tt.filename != "" {
+				got = DetectFormatFromFilename(tt.filename)
+			}
+			if got != tt.want {
+				t.Errorf("detected %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
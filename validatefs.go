@@ -0,0 +1,171 @@
+package serdeval
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+)
+
+// BatchOption configures ValidateFS.
+//
+// Example:
+//
+//	results, err := ValidateFS(os.DirFS("."), WithRoot("config"), WithValidatorOptions(WithStrict()))
+type BatchOption func(*batchOptions)
+
+// batchOptions holds the settings applied by BatchOption functions.
+type batchOptions struct {
+	root          string
+	format        Format
+	validatorOpts []Option
+}
+
+// WithRoot makes ValidateFS walk only the directory dir, a slash-separated path within the
+// file system, or validate just that file if it is not a directory. The default is ".".
+func WithRoot(dir string) BatchOption {
+	return func(o *batchOptions) {
+		o.root = dir
+	}
+}
+
+// WithFormat makes ValidateFS validate every file as format. By default only files whose
+// names DetectFormatFromFilename recognizes are validated, each as its own format.
+func WithFormat(format Format) BatchOption {
+	return func(o *batchOptions) {
+		o.format = format
+	}
+}
+
+// WithValidatorOptions passes opts to NewValidator for every file ValidateFS validates,
+// and to ValidateAuto for files whose format comes from their content.
+func WithValidatorOptions(opts ...Option) BatchOption {
+	return func(o *batchOptions) {
+		o.validatorOpts = append(o.validatorOpts, opts...)
+	}
+}
+
+// ValidateFS validates the files in fsys, such as an embed.FS, a zip.Reader, an
+// fstest.MapFS, or os.DirFS, the way the serdeval command validates a directory: files
+// whose names map to a format (see DetectFormatFromFilename) are validated as that format
+// and other files are skipped, unless WithFormat forces one format for every file. A
+// single file given with WithRoot is always validated, detecting its format from content
+// if its name is not recognized.
+//
+// Results are in lexical path order with FileName set to the slash-separated path within
+// fsys. A file that cannot be read gets a failed Result rather than stopping the walk;
+// the error is non-nil only if the walk itself fails, for example because the root does
+// not exist, in which case the results gathered so far are returned with it.
+//
+// Example:
+//
+//	//go:embed testdata
+//	var fixtures embed.FS
+//
+//	results, err := ValidateFS(fixtures, WithRoot("testdata"))
+//	for _, r := range results {
+//		if !r.Valid {
+//			fmt.Printf("%s: %s\n", r.FileName, r.Error)
+//		}
+//	}
+func ValidateFS(fsys fs.FS, opts ...BatchOption) ([]Result, error) {
+	o := buildBatchOptions(opts)
+	if o.format != "" && o.format != FormatAuto {
+		if _, err := NewValidator(o.format, o.validatorOpts...); err != nil {
+			return nil, err
+		}
+	}
+
+	b := newFSBatch(fsys, o)
+	var results []Result
+	err := fs.WalkDir(fsys, o.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		format := b.formatOf(path)
+		if format == FormatUnknown && path != o.root {
+			return nil
+		}
+		results = append(results, b.validate(context.Background(), path, d, format))
+
+		return nil
+	})
+	if err != nil {
+		return results, fmt.Errorf("walking %s: %w", o.root, err)
+	}
+
+	return results, nil
+}
+
+// buildBatchOptions applies opts in order over the defaults.
+func buildBatchOptions(opts []BatchOption) batchOptions {
+	o := batchOptions{root: "."}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return o
+}
+
+// fsBatch validates the files of one ValidateFS or ValidatePaths call, reusing a validator
+// per format.
+type fsBatch struct {
+	fsys       fs.FS
+	opts       batchOptions
+	limits     options
+	validators map[Format]Validator
+}
+
+// newFSBatch returns an fsBatch that reads files from fsys.
+func newFSBatch(fsys fs.FS, o batchOptions) *fsBatch {
+	return &fsBatch{fsys: fsys, opts: o, limits: buildOptions(o.validatorOpts), validators: map[Format]Validator{}}
+}
+
+// formatOf returns the format to validate path as, or FormatUnknown to detect it from
+// content.
+func (b *fsBatch) formatOf(path string) Format {
+	if b.opts.format != "" && b.opts.format != FormatAuto {
+		return b.opts.format
+	}
+
+	return DetectFormatFromFilename(path)
+}
+
+// validate reads and validates one file with ctx.
+func (b *fsBatch) validate(ctx context.Context, path string, d fs.DirEntry, format Format) Result {
+	// Check the size before reading so huge files never get loaded into memory
+	if info, err := d.Info(); err == nil {
+		if result, tooLarge := b.limits.checkSize(format, info.Size()); tooLarge {
+			result.FileName = path
+
+			return result
+		}
+	}
+
+	data, err := fs.ReadFile(b.fsys, path)
+	if err != nil {
+		return Result{Valid: false, Format: format, Error: fmt.Sprintf("cannot read file: %v", err), FileName: path}
+	}
+
+	var result Result
+	if format == FormatUnknown {
+		result = ValidateAuto(data, b.opts.validatorOpts...)
+	} else {
+		v, ok := b.validators[format]
+		if !ok {
+			v, err = NewValidator(format, b.opts.validatorOpts...)
+			if err != nil {
+				return Result{Valid: false, Format: format, Error: err.Error(), FileName: path}
+			}
+			b.validators[format] = v
+		}
+		result = ValidateContext(ctx, v, data)
+	}
+	result.FileName = path
+
+	return result
+}
@@ -0,0 +1,88 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var fixtureFS = fstest.MapFS{
+	"config/app.json":      {Data: []byte(`{"name": "app"}`)},
+	"config/broken.yaml":   {Data: []byte("name: [app")},
+	"config/db.toml":       {Data: []byte("host = \"localhost\"")},
+	"config/notes.txt":     {Data: []byte("not validated")},
+	"data/users.csv":       {Data: []byte("id,name\n1,ada\n")},
+	"data/nested/log.json": {Data: []byte(`{"level": "info"`)},
+	"README":               {Data: []byte("name: app")},
+}
+
+func TestValidateFS(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []BatchOption
+		want  []string // FileName and Valid of each result, in order
+		error string
+	}{
+		{"whole file system", nil, []string{
+			"config/app.json true", "config/broken.yaml false", "config/db.toml true",
+			"data/nested/log.json false", "data/users.csv true",
+		}, ""},
+		{"root directory", []BatchOption{WithRoot("data")},
+			[]string{"data/nested/log.json false", "data/users.csv true"}, ""},
+		{"single file detected from content", []BatchOption{WithRoot("README")}, []string{"README true"}, ""},
+		{"forced format", []BatchOption{WithRoot("config"), WithFormat(FormatYAML)}, []string{
+			"config/app.json true", "config/broken.yaml false", "config/db.toml true", "config/notes.txt true",
+		}, ""},
+		{"validator options", []BatchOption{WithRoot("data"), WithValidatorOptions(WithMaxFileSize(15))},
+			[]string{"data/nested/log.json false", "data/users.csv true"}, ""},
+		{"missing root", []BatchOption{WithRoot("nope")}, nil, "walking nope"},
+		{"unsupported format", []BatchOption{WithFormat("nope")}, nil, "unsupported format: nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ValidateFS(fixtureFS, tt.opts...)
+			if tt.error != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.error) {
+					t.Errorf("error = %v, want it to contain %q", err, tt.error)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(results))
+			for i, r := range results {
+				got[i] = fmt.Sprintf("%s %t", r.FileName, r.Valid)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("results:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidateFSSkipsLargeFiles(t *testing.T) {
+	results, err := ValidateFS(fixtureFS, WithRoot("data"), WithValidatorOptions(WithMaxFileSize(15)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Skipped || !strings.Contains(results[0].Error, "16 bytes exceeds limit of 15") {
+		t.Errorf("result = %+v, want log.json skipped as too large", results[0])
+	}
+}
+
+func TestValidateFSMaxSize(t *testing.T) {
+	results, err := ValidateFS(fixtureFS, WithRoot("data"), WithValidatorOptions(WithMaxSize(15)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.Valid || r.Skipped || !strings.Contains(r.Error, "16 bytes exceeds max size of 15") {
+		t.Errorf("result = %+v, want log.json failed as too large", r)
+	}
+	if r := results[1]; !r.Valid {
+		t.Errorf("result = %+v, want users.csv valid", r)
+	}
+}
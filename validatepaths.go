@@ -0,0 +1,87 @@
+package serdeval
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// ValidatePaths validates the files named on paths as they arrive and sends a Result for
+// each, with FileName set to the path, so callers can report results while they are
+// still walking a large tree instead of collecting every path first. Each file is
+// validated as the format its name maps to (see DetectFormatFromFilename), else as the
+// format detected from its content, unless WithFormat forces one. WithRoot is ignored.
+//
+// A path that cannot be read, or names a directory, gets a failed Result. The returned
+// channel is closed once paths is closed and drained, or as soon as ctx is done; the
+// validation in progress then ends with a canceled Result where the validator allows it.
+//
+// Example:
+//
+//	paths := make(chan string)
+//	go func() {
+//		defer close(paths)
+//		_ = filepath.WalkDir("deploy", func(path string, d fs.DirEntry, err error) error {
+//			if err == nil && !d.IsDir() && DetectFormatFromFilename(path) != FormatUnknown {
+//				paths <- path
+//			}
+//			return nil
+//		})
+//	}()
+//	for result := range ValidatePaths(ctx, paths) {
+//		report(result)
+//	}
+func ValidatePaths(ctx context.Context, paths <-chan string, opts ...BatchOption) <-chan Result {
+	b := newFSBatch(osFS{}, buildBatchOptions(opts))
+	results := make(chan Result)
+	go func() {
+		defer close(results)
+		for {
+			var path string
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case path, ok = <-paths:
+				if !ok {
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case results <- b.validatePath(ctx, path):
+			}
+		}
+	}()
+
+	return results
+}
+
+// validatePath validates the file at path on the operating system's file system.
+func (b *fsBatch) validatePath(ctx context.Context, path string) Result {
+	format := b.formatOf(path)
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return Result{Valid: false, Format: format, Error: fmt.Sprintf("cannot access file: %v", err), FileName: path}
+	case info.IsDir():
+		return Result{Valid: false, Format: format, Error: "cannot validate a directory", FileName: path}
+	}
+
+	return b.validate(ctx, path, fs.FileInfoToDirEntry(info), format)
+}
+
+// osFS reads files by operating system path, relative or absolute, for ValidatePaths.
+type osFS struct{}
+
+// Open opens the named file.
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name) // #nosec G304 - ValidatePaths reads the files its caller names
+}
+
+// ReadFile reads the named file.
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name) // #nosec G304 - ValidatePaths reads the files its caller names
+}
@@ -0,0 +1,79 @@
+package serdeval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidatePaths(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.json":    `{"name": "app"}`,
+		"broken.yaml": "name: [app",
+		"notes":       "a,b\n1,2\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts []BatchOption
+		want []string // base name, format, validity, and error of each result
+	}{
+		{"detected formats", nil, []string{
+			"app.json json true ", "broken.yaml yaml false ", "notes csv true ",
+			"missing.json json false cannot access file", ". unknown false cannot validate a directory",
+		}},
+		{"forced format", []BatchOption{WithFormat(FormatYAML)}, []string{
+			"app.json yaml true ", "broken.yaml yaml false ", "notes yaml true ",
+			"missing.json yaml false cannot access file", ". yaml false cannot validate a directory",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := make(chan string)
+			go func() {
+				defer close(paths)
+				for _, name := range []string{"app.json", "broken.yaml", "notes", "missing.json", "."} {
+					paths <- filepath.Join(dir, name)
+				}
+			}()
+
+			var got []string
+			for r := range ValidatePaths(context.Background(), paths, tt.opts...) {
+				errPart := ""
+				for _, e := range []string{"cannot access file", "cannot validate a directory"} {
+					if strings.Contains(r.Error, e) {
+						errPart = e
+					}
+				}
+				base := filepath.Base(r.FileName)
+				if r.FileName == dir {
+					base = "."
+				}
+				got = append(got, fmt.Sprintf("%s %s %t %s", base, r.Format, r.Valid, errPart))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("results:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidatePathsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	paths := make(chan string) // never closed: only ctx ends the stream
+	results := ValidatePaths(ctx, paths)
+	cancel()
+	if _, ok := <-results; ok {
+		t.Error("got a result after cancel, want the channel closed")
+	}
+}
@@ -0,0 +1,110 @@
+package serdeval
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// ErrorCode classifies a failed or skipped validation.
+type ErrorCode string
+
+// Error codes set on ValidationError.Code
+const (
+	// ErrCodeInvalid means the input is not valid for its format
+	ErrCodeInvalid ErrorCode = "invalid"
+	// ErrCodeUnknownFormat means ValidateAuto could not detect a format
+	ErrCodeUnknownFormat ErrorCode = "unknown_format"
+	// ErrCodeEncoding means the input could not be decoded to UTF-8
+	ErrCodeEncoding ErrorCode = "encoding"
+	// ErrCodeTooLarge means the input was skipped for exceeding WithMaxFileSize
+	ErrCodeTooLarge ErrorCode = "too_large"
+	// ErrCodeBinary means ValidateAuto skipped binary content
+	ErrCodeBinary ErrorCode = "binary"
+	// ErrCodeCanceled means the context ended before validation finished
+	ErrCodeCanceled ErrorCode = "canceled"
+)
+
+// maxSnippet is the longest Snippet in bytes; longer lines are cut at a character boundary.
+const maxSnippet = 120
+
+// ValidationError describes a failed or skipped validation in fields programs can act on
+// without parsing Result.Error, which holds the same text as Message.
+//
+// Example:
+//
+//	result := serdeval.ValidateAuto(data)
+//	if e := result.Err; e != nil && e.Code == serdeval.ErrCodeInvalid {
+//		fmt.Printf("%d:%d: %s at %s\n\t%s\n", e.Line, e.Column, e.Message, e.Path, e.Snippet)
+//	}
+type ValidationError struct {
+	// Code classifies the failure
+	Code ErrorCode `json:"code"`
+	// Message is the full error message, the same as Result.Error
+	Message string `json:"message"`
+	// Line and Column locate the first failure, 1-based; zero when it has no position
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Path names the element where validation failed in JSON, YAML, TOML, and XML input:
+	// a JSON Pointer such as /spec/containers/0/image, or /catalog/book[2]/title for XML.
+	// A failure in the document root itself has the empty pointer, so no path.
+	Path string `json:"path,omitempty"`
+	// Snippet is the text of the line holding the first failure
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Error returns the message.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// newValidationError builds the ValidationError for r with code, locating it at r's first
+// diagnostic in data.
+func newValidationError(code ErrorCode, data []byte, r Result) *ValidationError {
+	e := &ValidationError{Code: code, Message: r.Error}
+	if len(r.Diagnostics) > 0 {
+		e.Line, e.Column = r.Diagnostics[0].Line, r.Diagnostics[0].Column
+		e.Snippet = snippet(data, e.Line)
+		e.Path = elementPath(r.Format, data, r.Diagnostics[0])
+	}
+
+	return e
+}
+
+// skippedResult is the Result for input that was not validated, with code saying why.
+func skippedResult(format Format, code ErrorCode, message string) Result {
+	return Result{
+		Valid:   false,
+		Skipped: true,
+		Format:  format,
+		Error:   message,
+		Err:     &ValidationError{Code: code, Message: message},
+	}
+}
+
+// snippet returns the 1-based line of data without its line ending, cut to maxSnippet
+// bytes, or "" if data has no such line.
+func snippet(data []byte, line int) string {
+	if line < 1 {
+		return ""
+	}
+	for i := 1; i < line; i++ {
+		next := bytes.IndexByte(data, '\n')
+		if next < 0 {
+			return ""
+		}
+		data = data[next+1:]
+	}
+	if end := bytes.IndexByte(data, '\n'); end >= 0 {
+		data = data[:end]
+	}
+	data = bytes.TrimRight(data, "\r")
+	if len(data) > maxSnippet {
+		cut := maxSnippet
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		data = data[:cut]
+	}
+
+	return string(data)
+}
@@ -0,0 +1,76 @@
+package serdeval
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	validate := func(format Format, opts ...Option) func([]byte) Result {
+		return func(data []byte) Result {
+			v, err := NewValidator(format, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return v.Validate(data)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		validate func([]byte) Result
+		input    []byte
+		want     *ValidationError // Message is only checked to be the Result's Error
+	}{
+		{"valid", validate(FormatJSON), []byte(`{"a": 1}`), nil},
+		{"syntax error", validate(FormatJSON), []byte("{\n  \"a\": ]\n}"),
+			&ValidationError{Code: ErrCodeInvalid, Line: 2, Column: 8, Path: "/a", Snippet: `  "a": ]`}},
+		{"CRLF line ending left out of snippet", validate(FormatYAML), []byte("a: 1\r\nb: [\r\n"),
+			&ValidationError{Code: ErrCodeInvalid, Line: 2, Column: 1, Path: "/b", Snippet: "b: ["}},
+		{"long line cut at a character boundary", validate(FormatJSON),
+			[]byte(strings.Repeat("é", 70) + "}"),
+			&ValidationError{Code: ErrCodeInvalid, Line: 1, Column: 2, Snippet: strings.Repeat("é", 60)}},
+		{"failure without a position", validate(FormatDockerfile), []byte("RUN ls\n"),
+			&ValidationError{Code: ErrCodeInvalid}},
+		{"too large", validate(FormatJSON, WithMaxFileSize(2)), []byte(`{"a": 1}`),
+			&ValidationError{Code: ErrCodeTooLarge}},
+		{"undecodable", validate(FormatJSON), []byte{0xff, 0xfe, '{'}, &ValidationError{Code: ErrCodeEncoding}},
+		{"unknown format", func(data []byte) Result { return ValidateAuto(data) }, []byte("???"),
+			&ValidationError{Code: ErrCodeUnknownFormat}},
+		{"binary", func(data []byte) Result { return ValidateAuto(data) }, []byte("\x89PNG\r\n\x1a\n\x00\x00"),
+			&ValidationError{Code: ErrCodeBinary}},
+		{"canceled", func(data []byte) Result {
+			v, _ := NewValidator(FormatJSON)
+
+			return ValidateContext(canceled, v, data)
+		}, []byte(`{}`), &ValidationError{Code: ErrCodeCanceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.validate(tt.input)
+			if tt.want == nil {
+				if result.Err != nil {
+					t.Errorf("Err = %+v, want nil", result.Err)
+				}
+
+				return
+			}
+			if result.Err == nil {
+				t.Fatalf("Err = nil for %+v", result)
+			}
+			got := *result.Err
+			if got.Message != result.Error || got.Error() != result.Error {
+				t.Errorf("Message = %q, want the Result's Error %q", got.Message, result.Error)
+			}
+			got.Message = ""
+			if got != *tt.want {
+				t.Errorf("Err = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
@@ -1,22 +1,27 @@
 // Package serdeval provides data format validation for JSON, YAML, XML, TOML, CSV, GraphQL, INI, HCL,
-// Protobuf text format, Markdown, JSON Lines, Jupyter Notebooks, Requirements.txt, and Dockerfile
+// Protobuf text format, Markdown, JSON Lines, Jupyter Notebooks, Requirements.txt, Dockerfile,
+// and log and interchange formats such as Amazon Ion, logfmt, syslog, and web access logs
 package serdeval
 
 import (
-	"encoding/csv"
+	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
-	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/yuin/goldmark"
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
 	"google.golang.org/protobuf/types/known/anypb"
 	"gopkg.in/ini.v1"
 	"gopkg.in/yaml.v3"
@@ -37,6 +42,8 @@ const (
 	FormatTOML Format = "toml"
 	// FormatCSV represents CSV format
 	FormatCSV Format = "csv"
+	// FormatTSV represents tab-separated values
+	FormatTSV Format = "tsv"
 	// FormatGraphQL represents GraphQL query/schema format
 	FormatGraphQL Format = "graphql"
 	// FormatINI represents INI configuration format
@@ -59,6 +66,60 @@ const (
 	FormatR Format = "r"
 	// FormatRMarkdown represents R Markdown format
 	FormatRMarkdown Format = "rmarkdown"
+	// FormatIon represents Amazon Ion format (text or binary)
+	FormatIon Format = "ion"
+	// FormatLogfmt represents logfmt structured log lines
+	FormatLogfmt Format = "logfmt"
+	// FormatSyslog represents RFC 5424 and RFC 3164 syslog messages
+	FormatSyslog Format = "syslog"
+	// FormatAccessLog represents Common and Combined Log Format web access logs
+	FormatAccessLog Format = "accesslog"
+	// FormatHAR represents HTTP Archive files
+	FormatHAR Format = "har"
+	// FormatWARC represents Web ARChive files
+	FormatWARC Format = "warc"
+	// FormatSRT represents SubRip subtitle files
+	FormatSRT Format = "srt"
+	// FormatWebVTT represents WebVTT subtitle files
+	FormatWebVTT Format = "webvtt"
+	// FormatPO represents gettext PO localization files
+	FormatPO Format = "po"
+	// FormatXLIFF represents XLIFF localization interchange files
+	FormatXLIFF Format = "xliff"
+	// FormatARB represents Flutter Application Resource Bundle files
+	FormatARB Format = "arb"
+	// FormatRobots represents robots.txt crawler rules
+	FormatRobots Format = "robots"
+	// FormatSitemap represents XML sitemaps and sitemap indexes
+	FormatSitemap Format = "sitemap"
+	// FormatSSHKey represents OpenSSH private keys, public keys, and authorized_keys files
+	FormatSSHKey Format = "sshkey"
+	// FormatOtelCollector represents OpenTelemetry Collector configuration files
+	FormatOtelCollector Format = "otelcol"
+	// FormatTraefik represents Traefik dynamic configuration (YAML or TOML)
+	FormatTraefik Format = "traefik"
+	// FormatAzurePipelines represents Azure DevOps pipeline definitions (azure-pipelines.yml)
+	FormatAzurePipelines Format = "azure-pipelines"
+	// FormatRenovate represents Renovate bot configuration (renovate.json, renovate.json5)
+	FormatRenovate Format = "renovate"
+	// FormatDependabot represents GitHub Dependabot configuration (.github/dependabot.yml)
+	FormatDependabot Format = "dependabot"
+	// FormatCodeowners represents CODEOWNERS files (GitHub and GitLab)
+	FormatCodeowners Format = "codeowners"
+	// FormatChangelog represents CHANGELOG.md files following Keep a Changelog
+	FormatChangelog Format = "changelog"
+	// FormatSPDX represents SPDX license expressions and SPDX SBOM documents
+	FormatSPDX Format = "spdx"
+	// FormatCommitMsg represents Conventional Commits commit messages
+	FormatCommitMsg Format = "commitmsg"
+	// FormatProtoJSON represents payloads in the protobuf JSON mapping
+	FormatProtoJSON Format = "protojson"
+	// FormatProtoIDL represents Protocol Buffers schema definitions (.proto files)
+	FormatProtoIDL Format = "protoidl"
+	// FormatJSONPatch represents JSON Patch documents (RFC 6902)
+	FormatJSONPatch Format = "jsonpatch"
+	// FormatMergePatch represents JSON Merge Patch documents (RFC 7386)
+	FormatMergePatch Format = "mergepatch"
 	// FormatAuto represents automatic format detection
 	FormatAuto Format = "auto"
 	// FormatUnknown represents unknown format
@@ -87,8 +148,35 @@ type Result struct {
 	Format Format `json:"format"`
 	// Error contains the validation error message if Valid is false
 	Error string `json:"error,omitempty"`
+	// Err describes the failure in machine-readable fields; nil when Valid is true
+	Err *ValidationError `json:"validation_error,omitempty"`
+	// Suggestion is an actionable hint for common mistakes, e.g. a trailing comma in JSON
+	Suggestion string `json:"suggestion,omitempty"`
 	// FileName is an optional field to track which file was validated
 	FileName string `json:"filename,omitempty"`
+	// Skipped indicates the input was not parsed, e.g. because it exceeded WithMaxFileSize
+	Skipped bool `json:"skipped,omitempty"`
+	// Diagnostics locates each failure by line, column, and byte offset; empty when Valid is true
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+	// Warnings locates issues that do not make the input invalid, such as tabs in YAML
+	// indentation or trailing whitespace in CSV rows
+	Warnings []Diagnostic `json:"warnings,omitempty"`
+	// Confidence is how sure ValidateAuto is of the detected Format, from 0 to 1; see DetectFormatAll
+	Confidence float64 `json:"confidence,omitempty"`
+	// Attempted lists the formats ValidateAuto tried to parse and rejected before Format,
+	// when no detection heuristic matched
+	Attempted []Format `json:"attempted,omitempty"`
+	// Encoding is the encoding the input was decoded from when it was not plain UTF-8, such as
+	// EncodingUTF16LE; Diagnostics then locate failures in the decoded text
+	Encoding Encoding `json:"encoding,omitempty"`
+	// Duration is how long validation took, including detection for ValidateAuto.
+	// Duration, BytesRead, and Lines are set on Results from ValidateAuto and from
+	// validators created by NewValidator
+	Duration time.Duration `json:"duration_ns,omitempty"`
+	// BytesRead is the size of the input in bytes, before any decoding
+	BytesRead int64 `json:"bytes_read,omitempty"`
+	// Lines is the number of lines in the input; zero if it was skipped before decoding
+	Lines int `json:"lines,omitempty"`
 }
 
 // Validator is the main interface for validating data formats.
@@ -125,6 +213,15 @@ type Validator interface {
 // It is embedded in specific validator types to share the Format() method.
 type baseValidator struct {
 	format Format
+	// maxErrors is the WithMaxErrors limit for validators that can report several failures
+	maxErrors int
+	// strict is the WithStrict setting for validators that have a strict mode
+	strict bool
+	// maxDepth is the WithMaxDepth limit for validators that bound nesting; 0 or less is none
+	maxDepth int
+	// maxSize is the WithMaxSize or WithMaxFileSize limit for validators that expand their
+	// input, such as by decompressing it; 0 is their own default
+	maxSize int64
 }
 
 // JSONValidator validates JSON data according to RFC 7159.
@@ -143,23 +240,30 @@ type JSONValidator struct {
 //
 // Example:
 //
-//	validator := &YAMLValidator{baseValidator{format: FormatYAML}}
+//	validator := &YAMLValidator{baseValidator: baseValidator{format: FormatYAML}}
 //	result := validator.ValidateString("name: test\nvalue: 123")
 type YAMLValidator struct {
 	baseValidator
+	// limits bounds alias expansion, depth, and node count; zero fields use the defaults
+	limits YAMLLimits
 }
 
 // XMLValidator validates XML data for well-formedness.
 // It checks that the XML is properly structured with matching tags and valid syntax.
 //
-// Note: This validator checks for well-formedness only, not validity against a schema.
+// With WithXMLSchema it also checks that documents are valid against a W3C XML Schema:
+// element structure, attributes, and the values of simple types. Every document is first
+// checked against XMLLimits, which WithXMLLimits can change.
 //
 // Example:
 //
-//	validator := &XMLValidator{baseValidator{format: FormatXML}}
+//	validator := &XMLValidator{baseValidator: baseValidator{format: FormatXML}}
 //	result := validator.ValidateString(`<root><item>test</item></root>`)
 type XMLValidator struct {
 	baseValidator
+	schema *xsdSchema
+	// limits bounds entities, depth, and attributes; zero fields use the defaults
+	limits XMLLimits
 }
 
 // TOMLValidator validates TOML (Tom's Obvious, Minimal Language) data.
@@ -175,13 +279,17 @@ type TOMLValidator struct {
 
 // CSVValidator validates CSV (Comma-Separated Values) data.
 // It checks that the data can be parsed as valid CSV with consistent column counts.
+// The delimiter is sniffed from the first records unless WithCSVDialect sets one, and
+// WithCSVSchema adds checks of the header and of every value against column types.
 //
 // Example:
 //
-//	validator := &CSVValidator{baseValidator{format: FormatCSV}}
+//	validator := &CSVValidator{baseValidator: baseValidator{format: FormatCSV}}
 //	result := validator.ValidateString("name,age\nJohn,30\nJane,25")
 type CSVValidator struct {
 	baseValidator
+	dialect CSVDialect
+	schema  *CSVSchema
 }
 
 // GraphQLValidator validates GraphQL queries, mutations, subscriptions, and schema definitions.
@@ -207,25 +315,34 @@ type INIValidator struct {
 }
 
 // HCLValidator validates HCL (HashiCorp Configuration Language) data.
-// It supports HCL2 syntax used in Terraform, Packer, and other HashiCorp tools.
+// It supports HCL2 native syntax used in Terraform, Packer, and other HashiCorp tools, and
+// the HCL JSON syntax of .tf.json files. With WithTerraform it also checks that the blocks
+// form a valid Terraform configuration.
 //
 // Example:
 //
-//	validator := &HCLValidator{baseValidator{format: FormatHCL}}
+//	validator := &HCLValidator{baseValidator: baseValidator{format: FormatHCL}}
 //	result := validator.ValidateString(`resource "aws_instance" "example" { ami = "ami-123" }`)
 type HCLValidator struct {
 	baseValidator
+	terraform bool
 }
 
 // ProtobufValidator validates Protocol Buffers text format data.
 // It checks that the data can be parsed as valid protobuf text format.
 //
+// With WithProtoMessage the text is decoded as the given message type from a descriptor set,
+// so field names, value types, enum values, nested messages, and extensions are checked
+// against the real schema. Without one it is decoded as a google.protobuf.Any.
+//
 // Example:
 //
-//	validator := &ProtobufValidator{baseValidator{format: FormatProtobuf}}
+//	validator := &ProtobufValidator{baseValidator: baseValidator{format: FormatProtobuf}}
 //	result := validator.ValidateString(`type_url: "type.googleapis.com/Example" value: "\x08\x01"`)
 type ProtobufValidator struct {
 	baseValidator
+	message  protoreflect.MessageDescriptor
+	resolver *dynamicpb.Types
 }
 
 // MarkdownValidator validates Markdown formatted text.
@@ -307,22 +424,58 @@ type RMarkdownValidator struct {
 
 // validatorMap maps formats to their validator constructors
 var validatorMap = map[Format]func() Validator{
-	FormatJSON:         func() Validator { return &JSONValidator{baseValidator{format: FormatJSON}} },
-	FormatYAML:         func() Validator { return &YAMLValidator{baseValidator{format: FormatYAML}} },
-	FormatXML:          func() Validator { return &XMLValidator{baseValidator{format: FormatXML}} },
-	FormatTOML:         func() Validator { return &TOMLValidator{baseValidator{format: FormatTOML}} },
-	FormatCSV:          func() Validator { return &CSVValidator{baseValidator{format: FormatCSV}} },
-	FormatGraphQL:      func() Validator { return &GraphQLValidator{baseValidator{format: FormatGraphQL}} },
-	FormatINI:          func() Validator { return &INIValidator{baseValidator{format: FormatINI}} },
-	FormatHCL:          func() Validator { return &HCLValidator{baseValidator{format: FormatHCL}} },
-	FormatProtobuf:     func() Validator { return &ProtobufValidator{baseValidator{format: FormatProtobuf}} },
-	FormatMarkdown:     func() Validator { return &MarkdownValidator{baseValidator{format: FormatMarkdown}} },
-	FormatJSONL:        func() Validator { return &JSONLValidator{baseValidator{format: FormatJSONL}} },
-	FormatJupyter:      func() Validator { return &JupyterValidator{baseValidator{format: FormatJupyter}} },
-	FormatRequirements: func() Validator { return &RequirementsValidator{baseValidator{format: FormatRequirements}} },
-	FormatDockerfile:   func() Validator { return &DockerfileValidator{baseValidator{format: FormatDockerfile}} },
-	FormatR:            func() Validator { return &RValidator{baseValidator{format: FormatR}} },
-	FormatRMarkdown:    func() Validator { return &RMarkdownValidator{baseValidator{format: FormatRMarkdown}} },
+	FormatJSON:    func() Validator { return &JSONValidator{baseValidator{format: FormatJSON}} },
+	FormatYAML:    func() Validator { return &YAMLValidator{baseValidator: baseValidator{format: FormatYAML}} },
+	FormatXML:     func() Validator { return &XMLValidator{baseValidator: baseValidator{format: FormatXML}} },
+	FormatTOML:    func() Validator { return &TOMLValidator{baseValidator{format: FormatTOML}} },
+	FormatCSV:     func() Validator { return &CSVValidator{baseValidator: baseValidator{format: FormatCSV}} },
+	FormatTSV:     func() Validator { return &TSVValidator{baseValidator{format: FormatTSV}} },
+	FormatGraphQL: func() Validator { return &GraphQLValidator{baseValidator{format: FormatGraphQL}} },
+	FormatINI:     func() Validator { return &INIValidator{baseValidator{format: FormatINI}} },
+	FormatHCL:     func() Validator { return &HCLValidator{baseValidator: baseValidator{format: FormatHCL}} },
+	FormatProtobuf: func() Validator {
+		return &ProtobufValidator{baseValidator: baseValidator{format: FormatProtobuf}}
+	},
+	FormatMarkdown:      func() Validator { return &MarkdownValidator{baseValidator{format: FormatMarkdown}} },
+	FormatJSONL:         func() Validator { return &JSONLValidator{baseValidator{format: FormatJSONL}} },
+	FormatJupyter:       func() Validator { return &JupyterValidator{baseValidator{format: FormatJupyter}} },
+	FormatRequirements:  func() Validator { return &RequirementsValidator{baseValidator{format: FormatRequirements}} },
+	FormatDockerfile:    func() Validator { return &DockerfileValidator{baseValidator{format: FormatDockerfile}} },
+	FormatR:             func() Validator { return &RValidator{baseValidator{format: FormatR}} },
+	FormatRMarkdown:     func() Validator { return &RMarkdownValidator{baseValidator{format: FormatRMarkdown}} },
+	FormatIon:           func() Validator { return &IonValidator{baseValidator{format: FormatIon}} },
+	FormatLogfmt:        func() Validator { return &LogfmtValidator{baseValidator{format: FormatLogfmt}} },
+	FormatSyslog:        func() Validator { return &SyslogValidator{baseValidator{format: FormatSyslog}} },
+	FormatAccessLog:     func() Validator { return &AccessLogValidator{baseValidator{format: FormatAccessLog}} },
+	FormatHAR:           func() Validator { return &HARValidator{baseValidator{format: FormatHAR}} },
+	FormatWARC:          func() Validator { return &WARCValidator{baseValidator{format: FormatWARC}} },
+	FormatSRT:           func() Validator { return &SRTValidator{baseValidator{format: FormatSRT}} },
+	FormatWebVTT:        func() Validator { return &WebVTTValidator{baseValidator{format: FormatWebVTT}} },
+	FormatPO:            func() Validator { return &POValidator{baseValidator{format: FormatPO}} },
+	FormatXLIFF:         func() Validator { return &XLIFFValidator{baseValidator{format: FormatXLIFF}} },
+	FormatARB:           func() Validator { return &ARBValidator{baseValidator{format: FormatARB}} },
+	FormatRobots:        func() Validator { return &RobotsValidator{baseValidator{format: FormatRobots}} },
+	FormatSitemap:       func() Validator { return &SitemapValidator{baseValidator{format: FormatSitemap}} },
+	FormatSSHKey:        func() Validator { return &SSHKeyValidator{baseValidator{format: FormatSSHKey}} },
+	FormatOtelCollector: func() Validator { return &OtelCollectorValidator{baseValidator{format: FormatOtelCollector}} },
+	FormatTraefik:       func() Validator { return &TraefikValidator{baseValidator{format: FormatTraefik}} },
+	FormatAzurePipelines: func() Validator {
+		return &AzurePipelinesValidator{baseValidator{format: FormatAzurePipelines}}
+	},
+	FormatRenovate:   func() Validator { return &RenovateValidator{baseValidator{format: FormatRenovate}} },
+	FormatDependabot: func() Validator { return &DependabotValidator{baseValidator{format: FormatDependabot}} },
+	FormatCodeowners: func() Validator { return &CodeownersValidator{baseValidator{format: FormatCodeowners}} },
+	FormatChangelog:  func() Validator { return &ChangelogValidator{baseValidator{format: FormatChangelog}} },
+	FormatSPDX:       func() Validator { return &SPDXValidator{baseValidator{format: FormatSPDX}} },
+	FormatCommitMsg:  func() Validator { return &CommitMsgValidator{baseValidator{format: FormatCommitMsg}} },
+	FormatProtoJSON: func() Validator {
+		return &ProtoJSONValidator{baseValidator: baseValidator{format: FormatProtoJSON}}
+	},
+	FormatProtoIDL:  func() Validator { return &ProtoIDLValidator{baseValidator{format: FormatProtoIDL}} },
+	FormatJSONPatch: func() Validator { return &JSONPatchValidator{baseValidator{format: FormatJSONPatch}} },
+	FormatMergePatch: func() Validator {
+		return &MergePatchValidator{baseValidator{format: FormatMergePatch}}
+	},
 }
 
 // NewValidator creates a new validator for the specified format.
@@ -338,17 +491,86 @@ var validatorMap = map[Format]func() Validator{
 //		fmt.Println("Valid JSON!")
 //	}
 //
-// Supported formats: FormatJSON, FormatYAML, FormatXML, FormatTOML, FormatCSV, FormatGraphQL,
-// FormatINI, FormatHCL, FormatProtobuf, FormatMarkdown, FormatJSONL, FormatJupyter,
-// FormatRequirements, FormatDockerfile
+// Supported formats: FormatJSON, FormatYAML, FormatXML, FormatTOML, FormatCSV, FormatTSV,
+// FormatGraphQL, FormatINI, FormatHCL, FormatProtobuf, FormatMarkdown, FormatJSONL, FormatJupyter,
+// FormatRequirements, FormatDockerfile, FormatRMarkdown, FormatIon, FormatLogfmt,
+// FormatSyslog, FormatAccessLog, FormatHAR, FormatWARC, FormatSRT, FormatWebVTT,
+// FormatPO, FormatXLIFF, FormatARB, FormatRobots, FormatSitemap, FormatSSHKey,
+// FormatOtelCollector, FormatTraefik, FormatAzurePipelines, FormatRenovate, FormatDependabot,
+// FormatCodeowners, FormatChangelog, FormatSPDX, FormatCommitMsg, FormatProtoJSON, FormatProtoIDL,
+// FormatJSONPatch, FormatMergePatch
+// Formats added with Register are supported too.
 // Returns an error if an unsupported format is specified.
-func NewValidator(format Format) (Validator, error) {
-	constructor, ok := validatorMap[format]
+//
+// Options such as WithMaxFileSize customize the returned validator; an error is also
+// returned if a format-specific option such as WithProtoMessage cannot be applied.
+func NewValidator(format Format, opts ...Option) (Validator, error) {
+	constructor, ok := lookupValidator(format)
 	if !ok {
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 
-	return constructor(), nil
+	v := constructor()
+	o := buildOptions(opts)
+	if c, ok := v.(configurable); ok {
+		if err := c.configure(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return &optionValidator{Validator: v, opts: o}, nil
+}
+
+// SupportedFormats returns every format NewValidator accepts, including those added with
+// Register, sorted by name.
+//
+// Example:
+//
+//	for _, format := range SupportedFormats() {
+//		fmt.Println(format, FormatExtensions(format))
+//	}
+func SupportedFormats() []Format {
+	registryMu.RLock()
+	formats := make([]Format, 0, len(validatorMap))
+	for format := range validatorMap {
+		formats = append(formats, format)
+	}
+	registryMu.RUnlock()
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+
+	return formats
+}
+
+// FormatExtensions returns the file extensions, without the leading dot, that
+// DetectFormatFromFilename maps to format, sorted. Formats recognized only by
+// file name (such as Dockerfile or CODEOWNERS) or by content return nil.
+//
+// Example:
+//
+//	exts := FormatExtensions(FormatYAML)
+//	// exts == []string{"yaml", "yml"}
+func FormatExtensions(format Format) []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	seen := make(map[string]bool)
+	var exts []string
+	for ext, f := range extensionMap {
+		// Lookups are case-insensitive, so "R" and "r" are the same extension
+		ext = strings.ToLower(ext)
+		if _, claimed := customExtensions[ext]; f == format && !seen[ext] && !claimed {
+			seen[ext] = true
+			exts = append(exts, ext)
+		}
+	}
+	for ext, f := range customExtensions {
+		if f == format && !seen[ext] {
+			seen[ext] = true
+			exts = append(exts, ext)
+		}
+	}
+	sort.Strings(exts)
+
+	return exts
 }
 
 // Format returns the data format type associated with this validator.
@@ -379,14 +601,24 @@ func (v baseValidator) Format() Format {
 //		fmt.Println("Valid JSON!")
 //	}
 func (v *JSONValidator) Validate(data []byte) Result {
-	var jsonData interface{}
-	err := json.Unmarshal(data, &jsonData)
+	var err error
+	if v.maxDepth > 0 {
+		err = checkJSONDepth(data, v.maxDepth)
+	}
+	if err == nil {
+		var jsonData interface{}
+		err = json.Unmarshal(data, &jsonData)
+	}
+	if err == nil && v.strict {
+		err = checkStrictJSON(data)
+	}
 
 	return Result{
-		Valid:  err == nil,
-		Format: v.format,
-		Error:  errorString(err),
-	}
+		Valid:      err == nil,
+		Format:     v.format,
+		Error:      errorString(err),
+		Suggestion: suggestFix(v.format, data, errorString(err)),
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a JSON string.
@@ -405,17 +637,29 @@ func (v *JSONValidator) ValidateString(data string) Result {
 //
 // Example:
 //
-//	validator := &YAMLValidator{baseValidator{format: FormatYAML}}
+//	validator := &YAMLValidator{baseValidator: baseValidator{format: FormatYAML}}
 //	result := validator.Validate([]byte("key: value\nlist:\n  - item1\n  - item2"))
 func (v *YAMLValidator) Validate(data []byte) Result {
-	var yamlData interface{}
-	err := yaml.Unmarshal(data, &yamlData)
+	err := checkYAMLLimits(data, v.limits.withDefaults())
+	if err == nil {
+		var yamlData interface{}
+		err = yaml.Unmarshal(data, &yamlData)
+	}
+	if err == nil && v.strict {
+		err = checkStrictYAML(data)
+	}
+	var warnings []Diagnostic
+	if err == nil {
+		warnings = yamlTabWarnings(data)
+	}
 
 	return Result{
-		Valid:  err == nil,
-		Format: v.format,
-		Error:  errorString(err),
-	}
+		Valid:      err == nil,
+		Format:     v.format,
+		Error:      errorString(err),
+		Suggestion: suggestFix(v.format, data, errorString(err)),
+		Warnings:   warnings,
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a YAML string.
@@ -423,28 +667,34 @@ func (v *YAMLValidator) Validate(data []byte) Result {
 //
 // Example:
 //
-//	validator := &YAMLValidator{baseValidator{format: FormatYAML}}
+//	validator := &YAMLValidator{baseValidator: baseValidator{format: FormatYAML}}
 //	result := validator.ValidateString("name: test\nvalue: 123")
 func (v *YAMLValidator) ValidateString(data string) Result {
 	return v.Validate([]byte(data))
 }
 
-// Validate checks if the provided byte slice contains well-formed XML data.
-// Note that this only checks for well-formedness, not validity against a schema.
+// Validate checks if the provided byte slice contains well-formed XML data,
+// and, when a schema was given with WithXMLSchema, that it is valid against it.
 //
 // Example:
 //
-//	validator := &XMLValidator{baseValidator{format: FormatXML}}
+//	validator := &XMLValidator{baseValidator: baseValidator{format: FormatXML}}
 //	result := validator.Validate([]byte(`<?xml version="1.0"?><root></root>`))
 func (v *XMLValidator) Validate(data []byte) Result {
-	var xmlData interface{}
-	err := xml.Unmarshal(data, &xmlData)
+	err := checkXMLLimits(data, v.limits.withDefaults())
+	if err == nil && v.schema != nil {
+		return v.validateSchema(data)
+	}
+	if err == nil {
+		var xmlData interface{}
+		err = xml.Unmarshal(data, &xmlData)
+	}
 
 	return Result{
 		Valid:  err == nil,
 		Format: v.format,
 		Error:  errorString(err),
-	}
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates an XML string.
@@ -452,7 +702,7 @@ func (v *XMLValidator) Validate(data []byte) Result {
 //
 // Example:
 //
-//	validator := &XMLValidator{baseValidator{format: FormatXML}}
+//	validator := &XMLValidator{baseValidator: baseValidator{format: FormatXML}}
 //	result := validator.ValidateString(`<root><item>test</item></root>`)
 func (v *XMLValidator) ValidateString(data string) Result {
 	return v.Validate([]byte(data))
@@ -468,12 +718,16 @@ func (v *XMLValidator) ValidateString(data string) Result {
 func (v *TOMLValidator) Validate(data []byte) Result {
 	var tomlData interface{}
 	err := toml.Unmarshal(data, &tomlData)
+	if err == nil && v.maxDepth > 0 {
+		err = checkTOMLDepth(tomlData, v.maxDepth)
+	}
 
 	return Result{
-		Valid:  err == nil,
-		Format: v.format,
-		Error:  errorString(err),
-	}
+		Valid:      err == nil,
+		Format:     v.format,
+		Error:      errorString(err),
+		Suggestion: suggestFix(v.format, data, errorString(err)),
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a TOML string.
@@ -492,18 +746,56 @@ func (v *TOMLValidator) ValidateString(data string) Result {
 //
 // Example:
 //
-//	validator := &CSVValidator{baseValidator{format: FormatCSV}}
+//	validator := &CSVValidator{baseValidator: baseValidator{format: FormatCSV}}
 //	result := validator.Validate([]byte("name,age\nJohn,30"))
 func (v *CSVValidator) Validate(data []byte) Result {
-	r := csv.NewReader(strings.NewReader(string(data)))
-	// Read all records to validate
-	_, err := r.ReadAll()
+	return v.validate(context.Background(), data)
+}
+
+// validate reads every record of data, checking ctx between batches of records.
+func (v *CSVValidator) validate(ctx context.Context, data []byte) Result {
+	errs := v.newErrorCollector()
+	r := v.dialect.reader(data)
+	defer r.release()
+	var table *csvTable
+	var warnings []Diagnostic
+	if v.schema != nil {
+		table = &csvTable{schema: v.schema}
+	}
+	// Read every record; the reader resumes at the next line after a parse error
+	for n := 0; ; n++ {
+		if n%contextCheckInterval == 0 && ctx.Err() != nil {
+			return canceledResult(v.format, ctx.Err())
+		}
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if errs.add(err) {
+				break
+			}
 
-	return Result{
-		Valid:  err == nil,
-		Format: v.format,
-		Error:  errorString(err),
+			continue
+		}
+		if offset := csvTrailingSpace(data, r.Reader); offset >= 0 {
+			warnings = warn(warnings, atOffset(data, offset, "trailing whitespace after the last field"))
+		}
+		if table != nil && table.record(r.Reader, fields, errs) {
+			break
+		}
 	}
+	if table != nil {
+		table.finish(errs)
+	}
+	err := errs.err()
+
+	return Result{
+		Valid:    err == nil,
+		Format:   v.format,
+		Error:    errorString(err),
+		Warnings: warnings,
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a CSV string.
@@ -511,7 +803,7 @@ func (v *CSVValidator) Validate(data []byte) Result {
 //
 // Example:
 //
-//	validator := &CSVValidator{baseValidator{format: FormatCSV}}
+//	validator := &CSVValidator{baseValidator: baseValidator{format: FormatCSV}}
 //	result := validator.ValidateString("header1,header2\nvalue1,value2")
 func (v *CSVValidator) ValidateString(data string) Result {
 	return v.Validate([]byte(data))
@@ -532,7 +824,7 @@ func (v *GraphQLValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  "empty GraphQL content",
-		}
+		}.locate(data, nil)
 	}
 	s := source.NewSource(&source.Source{
 		Body: data,
@@ -544,7 +836,7 @@ func (v *GraphQLValidator) Validate(data []byte) Result {
 		Valid:  err == nil,
 		Format: v.format,
 		Error:  errorString(err),
-	}
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a GraphQL string.
@@ -572,7 +864,7 @@ func (v *INIValidator) Validate(data []byte) Result {
 		Valid:  err == nil,
 		Format: v.format,
 		Error:  errorString(err),
-	}
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates an INI string.
@@ -587,24 +879,28 @@ func (v *INIValidator) ValidateString(data string) Result {
 }
 
 // Validate checks if the provided byte slice contains valid HCL2 syntax.
-// It uses the HashiCorp HCL parser to validate the configuration.
+// It uses the HashiCorp HCL parser to validate the configuration; input that starts
+// with "{" is parsed as HCL JSON.
 //
 // Example:
 //
-//	validator := &HCLValidator{baseValidator{format: FormatHCL}}
+//	validator := &HCLValidator{baseValidator: baseValidator{format: FormatHCL}}
 //	result := validator.Validate([]byte(`variable "region" { default = "us-west-2" }`))
 func (v *HCLValidator) Validate(data []byte) Result {
-	_, diags := hclsyntax.ParseConfig(data, "hcl", hcl.InitialPos)
-	var errStr string
+	file, diags := parseHCL(data)
+	if !diags.HasErrors() && v.terraform {
+		diags = checkTerraform(file.Body)
+	}
+	var err error
 	if diags.HasErrors() {
-		errStr = diags.Error()
+		err = diags
 	}
 
 	return Result{
-		Valid:  !diags.HasErrors(),
+		Valid:  err == nil,
 		Format: v.format,
-		Error:  errStr,
-	}
+		Error:  errorString(err),
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates an HCL string.
@@ -612,7 +908,7 @@ func (v *HCLValidator) Validate(data []byte) Result {
 //
 // Example:
 //
-//	validator := &HCLValidator{baseValidator{format: FormatHCL}}
+//	validator := &HCLValidator{baseValidator: baseValidator{format: FormatHCL}}
 //	result := validator.ValidateString(`resource "aws_instance" "web" { ami = "ami-123" }`)
 func (v *HCLValidator) ValidateString(data string) Result {
 	return v.Validate([]byte(data))
@@ -623,18 +919,23 @@ func (v *HCLValidator) ValidateString(data string) Result {
 //
 // Example:
 //
-//	validator := &ProtobufValidator{baseValidator{format: FormatProtobuf}}
+//	validator := &ProtobufValidator{baseValidator: baseValidator{format: FormatProtobuf}}
 //	result := validator.Validate([]byte(`type_url: "example.com/Type"`))
 func (v *ProtobufValidator) Validate(data []byte) Result {
-	// Try to unmarshal as protobuf text format into Any message
-	msg := &anypb.Any{}
-	err := prototext.Unmarshal(data, msg)
+	var err error
+	if v.message != nil {
+		msg := dynamicpb.NewMessage(v.message)
+		err = prototext.UnmarshalOptions{Resolver: v.resolver}.Unmarshal(data, msg)
+	} else {
+		// Try to unmarshal as protobuf text format into Any message
+		err = prototext.Unmarshal(data, &anypb.Any{})
+	}
 
 	return Result{
 		Valid:  err == nil,
 		Format: v.format,
 		Error:  errorString(err),
-	}
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a Protobuf text format string.
@@ -642,7 +943,7 @@ func (v *ProtobufValidator) Validate(data []byte) Result {
 //
 // Example:
 //
-//	validator := &ProtobufValidator{baseValidator{format: FormatProtobuf}}
+//	validator := &ProtobufValidator{baseValidator: baseValidator{format: FormatProtobuf}}
 //	result := validator.ValidateString(`type_url: "type.googleapis.com/Example"`)
 func (v *ProtobufValidator) ValidateString(data string) Result {
 	return v.Validate([]byte(data))
@@ -663,7 +964,7 @@ func (v *MarkdownValidator) Validate(data []byte) Result {
 		Valid:  err == nil,
 		Format: v.format,
 		Error:  errorString(err),
-	}
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a Markdown string.
@@ -686,6 +987,11 @@ func (v *MarkdownValidator) ValidateString(data string) Result {
 //	validator := &JSONLValidator{baseValidator{format: FormatJSONL}}
 //	result := validator.Validate([]byte(`{"id":1}\n{"id":2}`))
 func (v *JSONLValidator) Validate(data []byte) Result {
+	return v.validate(context.Background(), data)
+}
+
+// validate checks each line of data, checking ctx between batches of lines.
+func (v *JSONLValidator) validate(ctx context.Context, data []byte) Result {
 	if len(data) == 0 {
 		return Result{
 			Valid:  true,
@@ -694,30 +1000,48 @@ func (v *JSONLValidator) Validate(data []byte) Result {
 		}
 	}
 
-	lines := strings.Split(string(data), "\n")
-	for i, line := range lines {
+	errs := v.newErrorCollector()
+	var suggestion string
+	// Lines are read in place: copying each one would cost more than checking it
+	rest := data
+	for i := 0; rest != nil; i++ {
+		if i%contextCheckInterval == 0 && ctx.Err() != nil {
+			return canceledResult(v.format, ctx.Err())
+		}
+		line := rest
+		rest = nil
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line, rest = line[:end], line[end+1:]
+		}
 		// Skip empty lines
-		line = strings.TrimSpace(line)
-		if line == "" {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
 			continue
 		}
 
-		// Each line must be valid JSON
+		// Each line must be valid JSON; json.Valid checks it without building its value,
+		// so only a failing line is decoded again for a message that explains why
+		if json.Valid(line) {
+			continue
+		}
 		var jsonData interface{}
-		if err := json.Unmarshal([]byte(line), &jsonData); err != nil {
-			return Result{
-				Valid:  false,
-				Format: v.format,
-				Error:  fmt.Sprintf("invalid JSON on line %d: %s", i+1, err.Error()),
-			}
+		err := json.Unmarshal(line, &jsonData)
+		if suggestion == "" {
+			suggestion = suggestFix(v.format, line, err.Error())
+		}
+		// err's offset is relative to the line, so it is not wrapped and the line number locates it
+		if errs.add(fmt.Errorf("invalid JSON on line %d: %s", i+1, err.Error())) {
+			break
 		}
 	}
+	err := errs.err()
 
 	return Result{
-		Valid:  true,
-		Format: v.format,
-		Error:  "",
-	}
+		Valid:      err == nil,
+		Format:     v.format,
+		Error:      errorString(err),
+		Suggestion: suggestion,
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a JSON Lines string.
@@ -744,10 +1068,11 @@ func (v *JupyterValidator) Validate(data []byte) Result {
 	var notebook map[string]interface{}
 	if err := json.Unmarshal(data, &notebook); err != nil {
 		return Result{
-			Valid:  false,
-			Format: v.format,
-			Error:  "invalid JSON: " + err.Error(),
-		}
+			Valid:      false,
+			Format:     v.format,
+			Error:      "invalid JSON: " + err.Error(),
+			Suggestion: suggestFix(v.format, data, err.Error()),
+		}.locate(data, err)
 	}
 
 	// Check for required notebook fields
@@ -756,21 +1081,21 @@ func (v *JupyterValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  "missing required field: cells",
-		}
+		}.locate(data, nil)
 	}
 	if _, ok := notebook["metadata"]; !ok {
 		return Result{
 			Valid:  false,
 			Format: v.format,
 			Error:  "missing required field: metadata",
-		}
+		}.locate(data, nil)
 	}
 	if _, ok := notebook["nbformat"]; !ok {
 		return Result{
 			Valid:  false,
 			Format: v.format,
 			Error:  "missing required field: nbformat",
-		}
+		}.locate(data, nil)
 	}
 
 	return Result{
@@ -800,6 +1125,7 @@ func (v *JupyterValidator) ValidateString(data string) Result {
 //	validator := &RequirementsValidator{baseValidator{format: FormatRequirements}}
 //	result := validator.Validate([]byte("django==3.2\nrequests>=2.25.0"))
 func (v *RequirementsValidator) Validate(data []byte) Result {
+	errs := v.newErrorCollector()
 	lines := strings.Split(string(data), "\n")
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
@@ -810,20 +1136,18 @@ func (v *RequirementsValidator) Validate(data []byte) Result {
 
 		// Basic validation: check if line contains package name
 		// Valid formats: package, package==version, package>=version, etc.
-		if !strings.ContainsAny(line, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-") {
-			return Result{
-				Valid:  false,
-				Format: v.format,
-				Error:  fmt.Sprintf("invalid requirement on line %d: %s", i+1, line),
-			}
+		if !strings.ContainsAny(line, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-") &&
+			errs.add(fmt.Errorf("invalid requirement on line %d: %s", i+1, line)) {
+			break
 		}
 	}
+	err := errs.err()
 
 	return Result{
-		Valid:  true,
+		Valid:  err == nil,
 		Format: v.format,
-		Error:  "",
-	}
+		Error:  errorString(err),
+	}.locate(data, err)
 }
 
 // ValidateString is a convenience method that validates a requirements.txt string.
@@ -846,8 +1170,10 @@ func (v *RequirementsValidator) ValidateString(data string) Result {
 //	validator := &DockerfileValidator{baseValidator{format: FormatDockerfile}}
 //	result := validator.Validate([]byte("FROM alpine:latest\nRUN apk add --no-cache curl"))
 func (v *DockerfileValidator) Validate(data []byte) Result {
+	errs := v.newErrorCollector()
 	lines := strings.Split(string(data), "\n")
 	hasFrom := false
+	var warnings []Diagnostic
 
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
@@ -862,46 +1188,53 @@ func (v *DockerfileValidator) Validate(data []byte) Result {
 		}
 
 		// Basic validation: check if line starts with valid instruction
-		upperLine := strings.ToUpper(line)
-		validInstructions := []string{"FROM", "RUN", "CMD", "LABEL", "EXPOSE", "ENV", "ADD", "COPY",
-			"ENTRYPOINT", "VOLUME", "USER", "WORKDIR", "ARG", "ONBUILD", "STOPSIGNAL", "HEALTHCHECK", "SHELL"}
-
-		hasValidInstruction := false
-		for _, instruction := range validInstructions {
-			if strings.HasPrefix(upperLine, instruction+" ") || upperLine == instruction {
-				hasValidInstruction = true
-
-				break
-			}
-		}
+		instruction := dockerfileInstruction(strings.ToUpper(line))
 
 		// Allow line continuations
 		if i > 0 && strings.HasSuffix(lines[i-1], "\\") {
 			continue
 		}
 
-		if !hasValidInstruction {
-			return Result{
-				Valid:  false,
-				Format: v.format,
-				Error:  fmt.Sprintf("invalid instruction on line %d: %s", i+1, line),
-			}
+		if hint, ok := deprecatedDockerfileInstructions[instruction]; ok {
+			warnings = warn(warnings, atLine(data, i+1, 0, instruction+" is deprecated; "+hint))
+		}
+		if instruction == "" && errs.add(fmt.Errorf("invalid instruction on line %d: %s", i+1, line)) {
+			break
 		}
 	}
 
-	if !hasFrom {
-		return Result{
-			Valid:  false,
-			Format: v.format,
-			Error:  "missing required FROM instruction",
-		}
+	if !hasFrom && !errs.full() {
+		errs.add(errors.New("missing required FROM instruction"))
 	}
+	err := errs.err()
 
 	return Result{
-		Valid:  true,
-		Format: v.format,
-		Error:  "",
+		Valid:    err == nil,
+		Format:   v.format,
+		Error:    errorString(err),
+		Warnings: warnings,
+	}.locate(data, err)
+}
+
+// dockerfileInstructions are the instructions a Dockerfile line may start with.
+var dockerfileInstructions = []string{"FROM", "RUN", "CMD", "LABEL", "EXPOSE", "ENV", "ADD", "COPY",
+	"ENTRYPOINT", "VOLUME", "USER", "WORKDIR", "ARG", "ONBUILD", "STOPSIGNAL", "HEALTHCHECK", "SHELL", "MAINTAINER"}
+
+// deprecatedDockerfileInstructions maps instructions Docker still accepts but has deprecated
+// to what to use instead.
+var deprecatedDockerfileInstructions = map[string]string{
+	"MAINTAINER": `use LABEL maintainer="..." instead`,
+}
+
+// dockerfileInstruction returns the instruction upperLine starts with, or "" if none.
+func dockerfileInstruction(upperLine string) string {
+	for _, instruction := range dockerfileInstructions {
+		if strings.HasPrefix(upperLine, instruction+" ") || upperLine == instruction {
+			return instruction
+		}
 	}
+
+	return ""
 }
 
 // ValidateString is a convenience method that validates a Dockerfile string.
@@ -929,7 +1262,7 @@ func (v *RValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  "empty R code",
-		}
+		}.locate(data, nil)
 	}
 
 	// Basic R syntax validation
@@ -940,7 +1273,7 @@ func (v *RValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  "unmatched parentheses",
-		}
+		}.locate(data, nil)
 	}
 
 	openBrackets := strings.Count(content, "{")
@@ -950,7 +1283,7 @@ func (v *RValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  "unmatched curly brackets",
-		}
+		}.locate(data, nil)
 	}
 
 	openSquare := strings.Count(content, "[")
@@ -960,7 +1293,7 @@ func (v *RValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  "unmatched square brackets",
-		}
+		}.locate(data, nil)
 	}
 
 	// Check for unterminated strings
@@ -990,7 +1323,7 @@ func (v *RValidator) Validate(data []byte) Result {
 				Valid:  false,
 				Format: v.format,
 				Error:  fmt.Sprintf("unterminated string on line %d", i+1),
-			}
+			}.locate(data, nil)
 		}
 	}
 
@@ -1022,7 +1355,7 @@ func (v *RMarkdownValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  "empty R Markdown content",
-		}
+		}.locate(data, nil)
 	}
 
 	// Check for R code chunks
@@ -1038,7 +1371,7 @@ func (v *RMarkdownValidator) Validate(data []byte) Result {
 					Valid:  false,
 					Format: v.format,
 					Error:  fmt.Sprintf("nested code chunk at line %d", i+1),
-				}
+				}.locate(data, nil)
 			}
 			hasRChunk = true
 			inChunk = true
@@ -1053,7 +1386,7 @@ func (v *RMarkdownValidator) Validate(data []byte) Result {
 			Valid:  false,
 			Format: v.format,
 			Error:  fmt.Sprintf("unclosed code chunk starting at line %d", chunkStart+1),
-		}
+		}.locate(data, nil)
 	}
 
 	// Valid R Markdown can have no chunks (just markdown)
@@ -1082,7 +1415,7 @@ func (v *RMarkdownValidator) Validate(data []byte) Result {
 		Valid:  false,
 		Format: v.format,
 		Error:  "not a valid R Markdown file",
-	}
+	}.locate(data, nil)
 }
 
 // ValidateString is a convenience method that accepts a string instead of []byte.
@@ -1092,6 +1425,11 @@ func (v *RMarkdownValidator) ValidateString(data string) Result {
 
 // ValidateAuto validates data with automatic format detection.
 // It first attempts to detect the format, then validates using the appropriate validator.
+// The result's Confidence is that of the detected format among DetectFormatAll's candidates.
+// When no detection heuristic matches, ValidateAuto tries parsing data as JSON, XML, TOML,
+// HCL, YAML, and INI in turn and returns the first that succeeds, listing the formats it
+// rejected in Attempted. Binary input such as an image or archive is not parsed: the
+// Result has Skipped set and names the kind of file; see IsBinary.
 //
 // Example:
 //
@@ -1101,26 +1439,39 @@ func (v *RMarkdownValidator) ValidateString(data string) Result {
 //	// Output: Format: json, Valid: true
 //
 // Returns a Result with Format=FormatUnknown if the format cannot be detected.
-func ValidateAuto(data []byte) Result {
-	format := DetectFormat(data)
-	if format == FormatUnknown {
-		return Result{
-			Valid:  false,
-			Format: FormatUnknown,
-			Error:  "unable to detect format",
+// Options are passed through to NewValidator for the detected format.
+func ValidateAuto(data []byte, opts ...Option) Result {
+	// Oversized input is skipped before running detection heuristics over it
+	return buildOptions(opts).run(FormatUnknown, data, func(text []byte) Result {
+		return validateAuto(context.Background(), text, opts)
+	})
+}
+
+// validateAuto detects the format of data, already decoded to UTF-8, and validates it
+// with ctx.
+func validateAuto(ctx context.Context, data []byte, opts []Option) Result {
+	candidates := DetectFormatAll(data)
+	if len(candidates) == 0 {
+		if kind := binaryKind(data); kind != "" {
+			return binaryResult(kind)
 		}
+
+		return validateFallback(data, opts)
 	}
+	format := candidates[0].Format
 
-	validator, err := NewValidator(format)
+	validator, err := NewValidator(format, opts...)
 	if err != nil {
 		return Result{
-			Valid:  false,
-			Format: format,
-			Error:  err.Error(),
-		}
+			Valid:      false,
+			Format:     format,
+			Error:      err.Error(),
+			Confidence: candidates[0].Confidence,
+		}.locate(data, err)
 	}
 
-	result := validator.Validate(data)
+	result := ValidateContext(ctx, validator, data)
+	result.Confidence = candidates[0].Confidence
 
 	return result
 }
@@ -1183,6 +1534,21 @@ func detectJSONFamily(trimmed string, lines []string) Format {
 		return FormatJupyter
 	}
 
+	// Check for Flutter resource bundles, which are also JSON
+	if isARB(trimmed) {
+		return FormatARB
+	}
+
+	// Check for HTTP Archives, which are also JSON
+	if isHAR(trimmed) {
+		return FormatHAR
+	}
+
+	// Check for SPDX SBOM documents, which are also JSON
+	if isSPDXJSON(trimmed) {
+		return FormatSPDX
+	}
+
 	// Check for JSON Lines before regular JSON
 	if isJSONLines(lines) {
 		return FormatJSONL
@@ -1314,11 +1680,16 @@ func isRMarkdown(trimmed string, lines []string) bool {
 }
 
 // detectDeveloperFormats attempts to detect developer tool formats.
-// It checks for Dockerfile, HCL, GraphQL, and Protobuf formats in order of specificity.
+// It checks for .proto schemas, Dockerfile, HCL, GraphQL, and Protobuf formats in order of specificity.
 // Returns FormatUnknown if no developer format is detected.
 func detectDeveloperFormats(trimmed string, lines []string) Format {
 	upperTrimmed := strings.ToUpper(trimmed)
 
+	// Check .proto schemas first: their syntax statement and braces resemble HCL and GraphQL
+	if isProtoIDL(trimmed) {
+		return FormatProtoIDL
+	}
+
 	// Check Dockerfile - look for common Docker instructions
 	if isDockerfile(upperTrimmed) {
 		return FormatDockerfile
@@ -1339,6 +1710,11 @@ func detectDeveloperFormats(trimmed string, lines []string) Format {
 		return FormatProtobuf
 	}
 
+	// Check gettext catalogs before R, whose heuristics match translated code snippets
+	if isPO(lines) {
+		return FormatPO
+	}
+
 	// Check R Markdown first (more specific than R)
 	if isRMarkdown(trimmed, lines) {
 		return FormatRMarkdown
@@ -1352,25 +1728,46 @@ func detectDeveloperFormats(trimmed string, lines []string) Format {
 	return FormatUnknown
 }
 
-// detectCSV checks if the content appears to be CSV format.
-// It verifies that the content has commas and consistent column counts across rows.
-func detectCSV(trimmed string, lines []string) bool {
-	if !strings.Contains(trimmed, ",") || len(lines) <= 1 {
-		return false
+// detectLineFormats attempts to detect line-oriented log and table formats.
+// Returns FormatUnknown if none of them is detected.
+func detectLineFormats(trimmed string, lines []string) Format {
+	// Check syslog before the config formats, which would claim "<PRI>" as XML
+	if detectSyslog(lines) {
+		return FormatSyslog
 	}
 
-	firstLineCommas := strings.Count(lines[0], ",")
-	if firstLineCommas == 0 {
-		return false
+	// Check access logs before CSV and logfmt
+	if detectAccessLog(lines) {
+		return FormatAccessLog
 	}
 
-	for i := 1; i < len(lines) && i < 5; i++ {
-		if lines[i] != "" && strings.Count(lines[i], ",") != firstLineCommas {
-			return false
-		}
+	// Check logfmt before CSV, since quoted values may contain commas
+	if detectLogfmt(lines) {
+		return FormatLogfmt
+	}
+
+	// Check TSV before CSV, since tab-separated fields may contain commas
+	if detectTSV(lines) {
+		return FormatTSV
+	}
+
+	// Check CSV
+	if detectCSV(trimmed, lines) {
+		return FormatCSV
+	}
+
+	return FormatUnknown
+}
+
+// detectCSV checks if the content appears to be CSV format.
+// It verifies that the content has commas, or semicolons as spreadsheets in many European
+// locales write, and consistent column counts across rows.
+func detectCSV(trimmed string, lines []string) bool {
+	if strings.Contains(trimmed, ",") && len(lines) > 1 && consistentDelimiters(lines, ",") {
+		return true
 	}
 
-	return true
+	return detectSemicolonCSV(lines)
 }
 
 // detectMarkdown checks if the content appears to be Markdown format.
@@ -1414,9 +1811,47 @@ func detectRequirements(trimmed string, lines []string) bool {
 // It checks for CSV, Markdown, and Requirements.txt formats.
 // Returns FormatUnknown if no data format is detected.
 func detectDataFormats(trimmed string, lines []string) Format {
-	// Check CSV
-	if detectCSV(trimmed, lines) {
-		return FormatCSV
+	// Check SSH keys, which have fixed framing or a leading key type
+	if isSSHKey(trimmed) {
+		return FormatSSHKey
+	}
+
+	// Check SPDX tag-value documents
+	if isSPDXTagValue(trimmed) {
+		return FormatSPDX
+	}
+
+	// Check WARC, which always opens with its version line
+	if strings.HasPrefix(trimmed, "WARC/1.") {
+		return FormatWARC
+	}
+
+	// Check robots.txt before YAML claims its "Directive: value" lines
+	if isRobots(lines) {
+		return FormatRobots
+	}
+
+	// Check XLIFF and sitemaps before the config formats claim them as generic XML
+	if isXLIFF(trimmed) {
+		return FormatXLIFF
+	}
+	if isSitemap(trimmed) {
+		return FormatSitemap
+	}
+
+	// Check subtitles, which have distinctive headers and timing arrows
+	if format := detectSubtitles(trimmed, lines); format != FormatUnknown {
+		return format
+	}
+
+	// Check logs and delimited tables before the config formats
+	if format := detectLineFormats(trimmed, lines); format != FormatUnknown {
+		return format
+	}
+
+	// Check Keep a Changelog files before generic Markdown
+	if isChangelog(trimmed) {
+		return FormatChangelog
 	}
 
 	// Check Markdown
@@ -1494,6 +1929,17 @@ func isTOML(trimmed string) bool {
 // It checks for XML, INI, YAML, and TOML formats in order of specificity.
 // Returns FormatUnknown if no config format is detected.
 func detectConfigFormats(trimmed string, lines []string) Format {
+	// Check YAML-based tool configs before generic YAML
+	if isOtelCollector(lines) {
+		return FormatOtelCollector
+	}
+	if isTraefik(trimmed, lines) {
+		return FormatTraefik
+	}
+	if isAzurePipelines(trimmed, lines) {
+		return FormatAzurePipelines
+	}
+
 	// Check XML
 	if isXML(trimmed) {
 		return FormatXML
@@ -1518,9 +1964,12 @@ func detectConfigFormats(trimmed string, lines []string) Format {
 }
 
 // DetectFormat attempts to detect the data format by analyzing the content.
-// Uses simple heuristics to identify various data formats.
+// Uses simple heuristics to identify various data formats. UTF-16 input and byte order
+// marks are decoded first; see DecodeText. Binary input other than binary Ion is
+// FormatUnknown; see IsBinary.
 //
 // Detection rules:
+//   - Formats added with Register: their Detector, if any, matches
 //   - JSON: Starts with '{' or '['
 //   - XML: Starts with '<?xml' or '<'
 //   - YAML: Contains '---' or has key:value pattern
@@ -1531,16 +1980,41 @@ func detectConfigFormats(trimmed string, lines []string) Format {
 //   - Dockerfile: Starts with FROM instruction
 //   - Markdown: Contains markdown syntax like #, *, -, ```
 //   - Requirements.txt: Contains package names with version specifiers
+//   - Logfmt: Every line has two or more key=value pairs
+//   - Syslog: Every line starts with an RFC 5424 or RFC 3164 header
+//   - Access log: Every line is a Common or Combined Log Format entry
 //
 // Returns FormatUnknown if the format cannot be determined.
 func DetectFormat(data []byte) Format {
+	// Look at UTF-16 and BOM-prefixed input as the text it encodes
+	if text, _, err := DecodeText(data); err == nil {
+		data = text
+	}
+
+	// Formats added with Register get the first say
+	if format := detectRegistered(data); format != FormatUnknown {
+		return format
+	}
+
+	// Binary Ion carries an unambiguous version marker
+	if bytes.HasPrefix(data, ionBVM) {
+		return FormatIon
+	}
+
+	// Text heuristics would only guess wrong on images, archives, and executables
+	if IsBinary(data) {
+		return FormatUnknown
+	}
+
 	trimmed := strings.TrimSpace(string(data))
 	if len(trimmed) == 0 {
 		return FormatUnknown
 	}
 
 	// Split into lines for multi-line format detection
-	lines := strings.Split(trimmed, "\n")
+	pooled := splitLines(trimmed)
+	defer putLines(pooled)
+	lines := *pooled
 
 	// Sequential detection for now (parallel overhead not worth it for simple string checks)
 	// Try detection in order of specificity
@@ -1575,6 +2049,8 @@ var extensionMap = map[string]Format{
 	"xml":           FormatXML,
 	"toml":          FormatTOML,
 	"csv":           FormatCSV,
+	"tsv":           FormatTSV,
+	"tab":           FormatTSV,
 	"graphql":       FormatGraphQL,
 	"gql":           FormatGraphQL,
 	"ini":           FormatINI,
@@ -1584,7 +2060,7 @@ var extensionMap = map[string]Format{
 	"tf":            FormatHCL,
 	"tfvars":        FormatHCL,
 	"pb":            FormatProtobuf,
-	"proto":         FormatProtobuf,
+	"proto":         FormatProtoIDL,
 	"textproto":     FormatProtobuf,
 	"pbtxt":         FormatProtobuf,
 	"md":            FormatMarkdown,
@@ -1604,6 +2080,21 @@ var extensionMap = map[string]Format{
 	"R":             FormatR,
 	"rmd":           FormatRMarkdown,
 	"Rmd":           FormatRMarkdown,
+	"ion":           FormatIon,
+	"10n":           FormatIon,
+	"logfmt":        FormatLogfmt,
+	"syslog":        FormatSyslog,
+	"har":           FormatHAR,
+	"warc":          FormatWARC,
+	"spdx":          FormatSPDX,
+	"srt":           FormatSRT,
+	"vtt":           FormatWebVTT,
+	"po":            FormatPO,
+	"pot":           FormatPO,
+	"xlf":           FormatXLIFF,
+	"xliff":         FormatXLIFF,
+	"arb":           FormatARB,
+	"pub":           FormatSSHKey,
 }
 
 // DetectFormatFromFilename attempts to detect format from filename extension.
@@ -1614,6 +2105,8 @@ var extensionMap = map[string]Format{
 //   - .xml → FormatXML
 //   - .toml → FormatTOML
 //
+// Extensions of formats added with Register take precedence over the built-in ones.
+//
 // Example:
 //
 //	format := DetectFormatFromFilename("config.json")
@@ -1628,17 +2121,76 @@ func DetectFormatFromFilename(filename string) Format {
 		return FormatDockerfile
 	}
 
+	// SSH keys are usually named by convention rather than extension
+	switch baseName {
+	case "id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", "id_ecdsa_sk", "id_ed25519_sk", "authorized_keys":
+		return FormatSSHKey
+	case "renovate.json", "renovate.json5", ".renovaterc", ".renovaterc.json", ".renovaterc.json5":
+		return FormatRenovate
+	case "dependabot.yml", "dependabot.yaml":
+		return FormatDependabot
+	case "codeowners":
+		return FormatCodeowners
+	case "changelog.md":
+		return FormatChangelog
+	case "commit_editmsg":
+		return FormatCommitMsg
+	}
+
 	lastDot := strings.LastIndex(filename, ".")
 	if lastDot == -1 {
 		return FormatUnknown
 	}
 	ext := strings.ToLower(strings.TrimPrefix(filename[lastDot:], "."))
+	if format, ok := registeredExtension(ext); ok {
+		return format
+	}
+
+	// Collector configs are YAML files named after the collector
+	isYAMLExt := ext == "yaml" || ext == "yml"
+	if isYAMLExt && (strings.Contains(baseName, "otelcol") || strings.Contains(baseName, "otel-collector")) {
+		return FormatOtelCollector
+	}
+	if isYAMLExt && (strings.HasPrefix(baseName, "azure-pipelines") ||
+		strings.Contains(filename, ".azure-pipelines/")) {
+		return FormatAzurePipelines
+	}
+
+	// robots.txt has a fixed name
+	if baseName == "robots.txt" {
+		return FormatRobots
+	}
+
+	// Sitemaps are conventionally named sitemap.xml or sitemap-*.xml
+	if ext == "xml" && strings.HasPrefix(baseName, "sitemap") {
+		return FormatSitemap
+	}
 
 	// Special case for txt files
 	if ext == "txt" && strings.Contains(strings.ToLower(filename), "requirements") {
 		return FormatRequirements
 	}
 
+	// SPDX JSON documents use a double extension
+	if strings.HasSuffix(baseName, ".spdx.json") {
+		return FormatSPDX
+	}
+
+	// Terraform configurations and variables in HCL JSON syntax do too
+	if strings.HasSuffix(baseName, ".tf.json") || strings.HasSuffix(baseName, ".tfvars.json") {
+		return FormatHCL
+	}
+
+	// Compressed web archives keep the .warc in the name
+	if strings.HasSuffix(baseName, ".warc.gz") {
+		return FormatWARC
+	}
+
+	// Special case for web server access logs (access.log, nginx-access.log)
+	if ext == "log" && strings.Contains(baseName, "access") {
+		return FormatAccessLog
+	}
+
 	if format, ok := extensionMap[ext]; ok {
 		return format
 	}
@@ -1,6 +1,7 @@
 package serdeval
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -23,6 +24,34 @@ func TestNewValidator(t *testing.T) {
 		{FormatJupyter, false},
 		{FormatRequirements, false},
 		{FormatDockerfile, false},
+		{FormatIon, false},
+		{FormatLogfmt, false},
+		{FormatSyslog, false},
+		{FormatAccessLog, false},
+		{FormatHAR, false},
+		{FormatWARC, false},
+		{FormatTSV, false},
+		{FormatSRT, false},
+		{FormatWebVTT, false},
+		{FormatPO, false},
+		{FormatXLIFF, false},
+		{FormatARB, false},
+		{FormatRobots, false},
+		{FormatSitemap, false},
+		{FormatSSHKey, false},
+		{FormatOtelCollector, false},
+		{FormatTraefik, false},
+		{FormatAzurePipelines, false},
+		{FormatRenovate, false},
+		{FormatDependabot, false},
+		{FormatCodeowners, false},
+		{FormatChangelog, false},
+		{FormatSPDX, false},
+		{FormatCommitMsg, false},
+		{FormatProtoJSON, false},
+		{FormatProtoIDL, false},
+		{FormatJSONPatch, false},
+		{FormatMergePatch, false},
 		{Format("invalid"), true},
 	}
 
@@ -39,6 +68,39 @@ func TestNewValidator(t *testing.T) {
 	}
 }
 
+func TestSupportedFormats(t *testing.T) {
+	formats := SupportedFormats()
+	if len(formats) != len(validatorMap) {
+		t.Fatalf("SupportedFormats() returned %d formats, want %d", len(formats), len(validatorMap))
+	}
+	for i, format := range formats {
+		if i > 0 && formats[i-1] >= format {
+			t.Errorf("SupportedFormats() not sorted: %q before %q", formats[i-1], format)
+		}
+		if _, err := NewValidator(format); err != nil {
+			t.Errorf("NewValidator(%q) error = %v", format, err)
+		}
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatYAML, "yaml,yml"},
+		{FormatR, "r"},
+		{FormatDockerfile, "containerfile,dockerfile"},
+		{FormatCodeowners, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			if got := strings.Join(FormatExtensions(tt.format), ","); got != tt.want {
+				t.Errorf("FormatExtensions(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestJSONValidator(t *testing.T) {
 	v := &JSONValidator{baseValidator{format: FormatJSON}}
 
@@ -129,7 +191,7 @@ value: "test"`, FormatProtobuf, true},
 }
 
 func TestCSVValidator(t *testing.T) {
-	v := &CSVValidator{baseValidator{format: FormatCSV}}
+	v := &CSVValidator{baseValidator: baseValidator{format: FormatCSV}}
 
 	tests := []struct {
 		name  string
@@ -214,7 +276,7 @@ func TestINIValidator(t *testing.T) {
 }
 
 func TestHCLValidator(t *testing.T) {
-	v := &HCLValidator{baseValidator{format: FormatHCL}}
+	v := &HCLValidator{baseValidator: baseValidator{format: FormatHCL}}
 
 	tests := []struct {
 		name  string
@@ -226,6 +288,10 @@ func TestHCLValidator(t *testing.T) {
 		{"invalid syntax", `resource "test" {`, false},
 		{"empty", "", true},
 		{"nested blocks", `provider "aws" { region = var.region }`, true},
+		{"hcl json", `{"resource": {"aws_instance": {"web": {"ami": "ami-123"}}}}`, true},
+		{"hcl json template", `{"output": {"ip": {"value": "${aws_instance.web.public_ip}"}}}`, true},
+		{"hcl json syntax error", `{"resource": {`, false},
+		{"hcl json trailing comma", `{"variable": {"region": {}},}`, false},
 	}
 
 	for _, tt := range tests {
@@ -242,7 +308,7 @@ func TestHCLValidator(t *testing.T) {
 }
 
 func TestProtobufValidator(t *testing.T) {
-	v := &ProtobufValidator{baseValidator{format: FormatProtobuf}}
+	v := &ProtobufValidator{baseValidator: baseValidator{format: FormatProtobuf}}
 
 	tests := []struct {
 		name  string
@@ -518,6 +584,13 @@ pandas>=1.3.0`, FormatRequirements},
 		{"protobuf", `type_url: "example.com/Type"
 value: "data"`, FormatProtobuf},
 
+		// Protobuf schema
+		{"proto schema", `syntax = "proto3";
+
+message User {
+  string name = 1;
+}`, FormatProtoIDL},
+
 		// Unknown
 		{"plain text", `just some random text`, FormatUnknown},
 		{"empty", ``, FormatUnknown},
@@ -552,7 +625,7 @@ func TestDetectFormatFromFilename(t *testing.T) {
 		{"test.cfg", FormatINI},
 		{"test.hcl", FormatHCL},
 		{"test.tf", FormatHCL},
-		{"test.proto", FormatProtobuf},
+		{"test.proto", FormatProtoIDL},
 		{"test.pb", FormatProtobuf},
 		{"test.textproto", FormatProtobuf},
 		{"test.pbtxt", FormatProtobuf},
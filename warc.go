@@ -0,0 +1,171 @@
+package serdeval
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// warcMaxDecompressed bounds decompressed .warc.gz files when no WithMaxSize or
+// WithMaxFileSize limit is set, so a small gzip bomb cannot exhaust memory.
+const warcMaxDecompressed = 1 << 30
+
+// warcRequiredFields are the named fields every WARC record must carry.
+var warcRequiredFields = []string{"WARC-Type", "WARC-Record-ID", "WARC-Date", "Content-Length"}
+
+// WARCValidator validates Web ARChive (WARC 1.0 and 1.1) files.
+// It checks each record's version line, header fields, Content-Length against the actual block,
+// and the CRLF CRLF record boundary. Gzip-compressed files (.warc.gz) are decompressed first,
+// up to the WithMaxSize or WithMaxFileSize limit, or 1 GiB without one.
+//
+// Example:
+//
+//	validator := &WARCValidator{baseValidator{format: FormatWARC}}
+//	data, _ := os.ReadFile("crawl.warc.gz")
+//	result := validator.Validate(data)
+type WARCValidator struct {
+	baseValidator
+}
+
+// Validate checks if the provided byte slice contains a well-formed sequence of WARC records.
+//
+// Example:
+//
+//	validator := &WARCValidator{baseValidator{format: FormatWARC}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. "record 3: Content-Length 512 exceeds remaining 100 bytes"
+//	}
+func (v *WARCValidator) Validate(data []byte) Result {
+	var err error
+	if bytes.HasPrefix(data, []byte{0x1F, 0x8B}) {
+		limit := v.maxSize
+		if limit <= 0 {
+			limit = warcMaxDecompressed
+		}
+		data, err = gunzipAll(data, limit)
+	}
+	if err == nil {
+		err = validateWARC(data)
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *WARCValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+// gunzipAll decompresses a (possibly multi-member) gzip stream, failing once more than
+// limit bytes come out of it.
+func gunzipAll(data []byte, limit int64) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip stream: %w", err)
+	}
+	defer zr.Close()
+
+	out, err := io.ReadAll(io.LimitReader(zr, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip stream: %w", err)
+	}
+	if int64(len(out)) > limit {
+		return nil, fmt.Errorf("gzip stream: decompressed size exceeds limit of %d bytes", limit)
+	}
+
+	return out, nil
+}
+
+func validateWARC(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return fmt.Errorf("no WARC records found")
+	}
+
+	pos := 0
+	for record := 1; pos < len(data); record++ {
+		next, err := parseWARCRecord(data, pos)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", record, err)
+		}
+		pos = next
+	}
+
+	return nil
+}
+
+// parseWARCRecord validates the record starting at pos and returns the offset of the next one.
+func parseWARCRecord(data []byte, pos int) (int, error) {
+	line, pos, err := readCRLFLine(data, pos)
+	if err != nil {
+		return 0, err
+	}
+	if line != "WARC/1.0" && line != "WARC/1.1" {
+		return 0, fmt.Errorf("invalid version line %q (expected WARC/1.0 or WARC/1.1)", line)
+	}
+
+	fields := make(map[string]string)
+	for {
+		line, pos, err = readCRLFLine(data, pos)
+		if err != nil {
+			return 0, err
+		}
+		if line == "" {
+			break
+		}
+		// Lines starting with whitespace continue the previous field
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return 0, fmt.Errorf("malformed header line %q", line)
+		}
+		fields[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+
+	for _, name := range warcRequiredFields {
+		if _, ok := fields[strings.ToLower(name)]; !ok {
+			return 0, fmt.Errorf("missing required field: %s", name)
+		}
+	}
+	if id := fields["warc-record-id"]; !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, ">") {
+		return 0, fmt.Errorf("record ID %q must be enclosed in angle brackets", id)
+	}
+
+	length, err := strconv.Atoi(fields["content-length"])
+	if err != nil || length < 0 {
+		return 0, fmt.Errorf("invalid Content-Length %q", fields["content-length"])
+	}
+	if remaining := len(data) - pos; length > remaining {
+		return 0, fmt.Errorf("content length %d exceeds remaining %d bytes", length, remaining)
+	}
+	pos += length
+
+	if !bytes.HasPrefix(data[pos:], []byte("\r\n\r\n")) {
+		return 0, fmt.Errorf("record block not followed by CRLF CRLF; Content-Length may be wrong")
+	}
+
+	return pos + 4, nil
+}
+
+// readCRLFLine returns the line at pos without its CRLF terminator.
+func readCRLFLine(data []byte, pos int) (string, int, error) {
+	end := bytes.IndexByte(data[pos:], '\n')
+	if end < 0 {
+		return "", 0, fmt.Errorf("unexpected end of data in record header")
+	}
+	line := data[pos : pos+end]
+	if !bytes.HasSuffix(line, []byte("\r")) {
+		return "", 0, fmt.Errorf("header line %q must end with CRLF", string(line))
+	}
+
+	return string(line[:len(line)-1]), pos + end + 1, nil
+}
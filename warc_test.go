@@ -0,0 +1,112 @@
+package serdeval
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func warcRecord(block string, lengthDelta int) string {
+	return "WARC/1.1\r\n" +
+		"WARC-Type: resource\r\n" +
+		"WARC-Record-ID: <urn:uuid:12345678-9abc-def0-1234-56789abcdef0>\r\n" +
+		"WARC-Date: 2024-01-05T10:00:00Z\r\n" +
+		"Content-Type: text/plain\r\n" +
+		fmt.Sprintf("Content-Length: %d\r\n", len(block)+lengthDelta) +
+		"\r\n" + block + "\r\n\r\n"
+}
+
+func TestWARCValidator(t *testing.T) {
+	v := &WARCValidator{baseValidator{format: FormatWARC}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"single record", warcRecord("hello world", 0), true, ""},
+		{"two records", warcRecord("a", 0) + warcRecord("", 0), true, ""},
+		{"empty", "", false, "no WARC records"},
+		{"bad version", strings.Replace(warcRecord("a", 0), "WARC/1.1", "WARC/2.0", 1), false, "invalid version"},
+		{"length too short", warcRecord("hello", -2), false, "CRLF CRLF"},
+		{"length too long", warcRecord("hello", 50), false, "exceeds remaining"},
+		{
+			"missing record id",
+			strings.Replace(warcRecord("a", 0), "WARC-Record-ID: <urn:uuid:12345678-9abc-def0-1234-56789abcdef0>\r\n", "", 1),
+			false, "missing required field: WARC-Record-ID",
+		},
+		{"lf only", strings.ReplaceAll(warcRecord("a", 0), "\r\n", "\n"), false, "CRLF"},
+		{"second record broken", warcRecord("a", 0) + "WARC/1.1\r\nbroken\r\n\r\n", false, "record 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestWARCValidatorGzip(t *testing.T) {
+	var buf bytes.Buffer
+	// Each record is its own gzip member, as in real .warc.gz files
+	for _, record := range []string{warcRecord("one", 0), warcRecord("two", 0)} {
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(record))
+		_ = zw.Close()
+	}
+
+	v := &WARCValidator{baseValidator{format: FormatWARC}}
+	if result := v.Validate(buf.Bytes()); !result.Valid {
+		t.Errorf("Validate() = false, want true (error: %s)", result.Error)
+	}
+	if got := DetectFormatFromFilename("crawl-00001.warc.gz"); got != FormatWARC {
+		t.Errorf("DetectFormatFromFilename() = %v, want %v", got, FormatWARC)
+	}
+	if got := DetectFormat([]byte(warcRecord("x", 0))); got != FormatWARC {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatWARC)
+	}
+}
+
+func TestWARCValidatorGzipBomb(t *testing.T) {
+	// 16 MiB of zeros compress to a few KiB, far under the limits below
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(make([]byte, 16<<20))
+	_ = zw.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"max size", []Option{WithMaxSize(1 << 20)}},
+		{"max file size", []Option{WithMaxFileSize(1 << 20)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatWARC, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.Validate(buf.Bytes())
+			want := "decompressed size exceeds limit of 1048576 bytes"
+			if result.Valid || result.Skipped || !strings.Contains(result.Error, want) {
+				t.Errorf("Validate() = %+v, want an error containing %q", result, want)
+			}
+		})
+	}
+
+	// gunzipAll stops once the limit is passed, as it does at warcMaxDecompressed without options
+	if _, err := gunzipAll(buf.Bytes(), 1<<10); err == nil || !strings.Contains(err.Error(), "exceeds limit") {
+		t.Errorf("gunzipAll() error = %v, want the decompressed size limit", err)
+	}
+}
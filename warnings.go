@@ -0,0 +1,58 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/csv"
+	"regexp"
+)
+
+// maxWarnings caps the warnings reported for one input, so a file with the same harmless
+// issue on every line does not produce a Result larger than the file itself.
+const maxWarnings = 100
+
+// yamlTabSeparatorRe matches a mapping key followed by a tab instead of a space.
+var yamlTabSeparatorRe = regexp.MustCompile(`^\s*(?:- +)?[\w.-]+:\t`)
+
+// warn appends w to warnings unless maxWarnings is reached.
+func warn(warnings []Diagnostic, w Diagnostic) []Diagnostic {
+	if len(warnings) >= maxWarnings {
+		return warnings
+	}
+
+	return append(warnings, w)
+}
+
+// yamlTabWarnings reports the lines of a valid YAML document that use tabs as whitespace:
+// in their indentation, which YAML only tolerates inside block scalars, or between a key
+// and its value. Other tools reading the file often reject or misread them.
+func yamlTabWarnings(data []byte) []Diagnostic {
+	var warnings []Diagnostic
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+		switch {
+		case bytes.IndexByte(indent, '\t') >= 0:
+			warnings = warn(warnings, atLine(data, i+1, bytes.IndexByte(indent, '\t')+1, "tab character in indentation"))
+		case yamlTabSeparatorRe.Match(line):
+			warnings = warn(warnings, atLine(data, i+1, bytes.IndexByte(line, '\t')+1, "tab character after mapping key"))
+		}
+	}
+
+	return warnings
+}
+
+// csvTrailingSpace returns the offset of unquoted whitespace ending the record r has just
+// read, found in the raw input up to the reader's offset, or -1 if there is none. Tabs
+// count only when they are not the delimiter.
+func csvTrailingSpace(data []byte, r *csv.Reader) int {
+	raw := bytes.TrimRight(data[:r.InputOffset()], "\r\n")
+	space := " \t"
+	if r.Comma == '\t' {
+		space = " "
+	}
+	trimmed := bytes.TrimRight(raw, space)
+	if len(trimmed) == len(raw) {
+		return -1
+	}
+
+	return len(trimmed)
+}
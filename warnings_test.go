@@ -0,0 +1,60 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		opts   []Option
+		input  string
+		valid  bool
+		want   []string // line:column message of each warning
+	}{
+		{"yaml clean", FormatYAML, nil, "a: 1\nb:\n  - x\n", true, nil},
+		{"yaml tab after key", FormatYAML, nil, "a: 1\nb:\t2\n", true, []string{"2:3 tab character after mapping key"}},
+		{"yaml tab in block scalar indentation", FormatYAML, nil, "run: |\n  make\n  \tbuild\n", true,
+			[]string{"3:3 tab character in indentation"}},
+		{"yaml tab inside quoted value", FormatYAML, nil, "a: \"x\ty\"\n", true, nil},
+		{"yaml invalid has no warnings", FormatYAML, nil, "a:\n\t- x\n", false, nil},
+		{"csv trailing whitespace", FormatCSV, nil, "a,b \n1,2\n3,4\t\r\n", true,
+			[]string{"1:4 trailing whitespace after the last field", "3:4 trailing whitespace after the last field"}},
+		{"csv quoted whitespace", FormatCSV, nil, "a,\"b \"\n1,2\n", true, nil},
+		{"tsv trailing tab is an empty field", FormatCSV, []Option{WithCSVDialect(CSVDialect{Delimiter: '\t'})},
+			"a\tb\t\n1\t2\t\n", true, nil},
+		{"dockerfile deprecated instruction", FormatDockerfile, nil, "FROM alpine\nMAINTAINER ops@example.com\n", true,
+			[]string{"2:1 MAINTAINER is deprecated; use LABEL maintainer=\"...\" instead"}},
+		{"json never warns", FormatJSON, nil, "{\"a\":\t1} ", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(tt.format, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := v.Validate([]byte(tt.input))
+			if result.Valid != tt.valid {
+				t.Fatalf("Valid = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			got := make([]string, len(result.Warnings))
+			for i, w := range result.Warnings {
+				got[i] = fmt.Sprintf("%d:%d %s", w.Line, w.Column, w.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("warnings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestWarningsCapped(t *testing.T) {
+	result := ValidateAuto([]byte("a,b\n" + strings.Repeat("1,2 \n", 2*maxWarnings)))
+	if !result.Valid || len(result.Warnings) != maxWarnings {
+		t.Errorf("Valid = %v with %d warnings, want valid with %d", result.Valid, len(result.Warnings), maxWarnings)
+	}
+}
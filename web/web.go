@@ -0,0 +1,14 @@
+// Package web holds the static files of the serdeval web interface, embedded so the
+// binary serves them from any working directory or a scratch container.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static
+var files embed.FS
+
+// Static is the web interface's static files, with index.html at its root.
+var Static, _ = fs.Sub(files, "static")
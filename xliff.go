@@ -0,0 +1,213 @@
+package serdeval
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// XLIFFValidator validates XLIFF 1.2 and 2.x localization files.
+// Beyond XML well-formedness it checks the version, required file/unit attributes,
+// unit ID uniqueness, and that every translation unit or segment has exactly one source.
+//
+// Example:
+//
+//	validator := &XLIFFValidator{baseValidator{format: FormatXLIFF}}
+//	data, _ := os.ReadFile("messages.fr.xlf")
+//	result := validator.Validate(data)
+type XLIFFValidator struct {
+	baseValidator
+}
+
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	ID             string       `xml:"id,attr"`
+	Original       string       `xml:"original,attr"`
+	SourceLanguage string       `xml:"source-language,attr"`
+	Datatype       string       `xml:"datatype,attr"`
+	Body           *xliffGroup  `xml:"body"`
+	Units          []xliffUnit  `xml:"unit"`
+	Groups         []xliffGroup `xml:"group"`
+}
+
+// xliffGroup holds 1.2 <body>/<group> and 2.x <group> contents.
+type xliffGroup struct {
+	TransUnits []xliffTransUnit `xml:"trans-unit"`
+	Units      []xliffUnit      `xml:"unit"`
+	Groups     []xliffGroup     `xml:"group"`
+}
+
+// xliffTransUnit is an XLIFF 1.2 <trans-unit>.
+type xliffTransUnit struct {
+	ID      string     `xml:"id,attr"`
+	Sources []struct{} `xml:"source"`
+	Targets []struct{} `xml:"target"`
+}
+
+// xliffUnit is an XLIFF 2.x <unit>.
+type xliffUnit struct {
+	ID       string         `xml:"id,attr"`
+	Segments []xliffSegment `xml:"segment"`
+}
+
+type xliffSegment struct {
+	Sources []struct{} `xml:"source"`
+	Targets []struct{} `xml:"target"`
+}
+
+// Validate checks if the provided byte slice contains a structurally valid XLIFF document.
+//
+// Example:
+//
+//	validator := &XLIFFValidator{baseValidator{format: FormatXLIFF}}
+//	result := validator.Validate(data)
+//	if !result.Valid {
+//		fmt.Println(result.Error) // e.g. "file \"app\": unit \"greeting\" has no segment"
+//	}
+func (v *XLIFFValidator) Validate(data []byte) Result {
+	var doc xliffDoc
+	err := xml.Unmarshal(data, &doc)
+	if err != nil {
+		err = fmt.Errorf("invalid XML: %w", err)
+	} else {
+		err = validateXLIFF(&doc)
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// ValidateString is a convenience method that accepts a string instead of []byte.
+func (v *XLIFFValidator) ValidateString(data string) Result {
+	return v.Validate([]byte(data))
+}
+
+func validateXLIFF(doc *xliffDoc) error {
+	if len(doc.Files) == 0 {
+		return errors.New("xliff must contain at least one <file>")
+	}
+
+	switch doc.Version {
+	case "1.2":
+		for i := range doc.Files {
+			if err := validateXLIFF12File(&doc.Files[i], i); err != nil {
+				return err
+			}
+		}
+	case "2.0", "2.1":
+		if doc.SrcLang == "" {
+			return errors.New("xliff: missing required attribute srcLang")
+		}
+		fileIDs := make(map[string]bool)
+		for i := range doc.Files {
+			f := &doc.Files[i]
+			if f.ID == "" {
+				return fmt.Errorf("file %d: missing required attribute id", i+1)
+			}
+			if fileIDs[f.ID] {
+				return fmt.Errorf("duplicate file id %q", f.ID)
+			}
+			fileIDs[f.ID] = true
+			if err := validateXLIFF2Units(f.Units, f.Groups, f.ID, make(map[string]bool)); err != nil {
+				return err
+			}
+		}
+	case "":
+		return errors.New("xliff: missing required attribute version")
+	default:
+		return fmt.Errorf("xliff: unsupported version %q (expected 1.2, 2.0, or 2.1)", doc.Version)
+	}
+
+	return nil
+}
+
+func validateXLIFF12File(f *xliffFile, index int) error {
+	name := fmt.Sprintf("file %d", index+1)
+	if f.Original != "" {
+		name = fmt.Sprintf("file %q", f.Original)
+	}
+
+	required := [][2]string{
+		{"original", f.Original}, {"source-language", f.SourceLanguage}, {"datatype", f.Datatype},
+	}
+	for _, attr := range required {
+		if attr[1] == "" {
+			return fmt.Errorf("%s: missing required attribute %s", name, attr[0])
+		}
+	}
+	if f.Body == nil {
+		return fmt.Errorf("%s: missing required <body>", name)
+	}
+
+	return validateXLIFF12Group(f.Body, name, make(map[string]bool))
+}
+
+func validateXLIFF12Group(g *xliffGroup, file string, seen map[string]bool) error {
+	for _, tu := range g.TransUnits {
+		if tu.ID == "" {
+			return fmt.Errorf("%s: trans-unit missing required attribute id", file)
+		}
+		if seen[tu.ID] {
+			return fmt.Errorf("%s: duplicate trans-unit id %q", file, tu.ID)
+		}
+		seen[tu.ID] = true
+		if len(tu.Sources) != 1 {
+			return fmt.Errorf("%s: trans-unit %q must have exactly one <source>", file, tu.ID)
+		}
+		if len(tu.Targets) > 1 {
+			return fmt.Errorf("%s: trans-unit %q has more than one <target>", file, tu.ID)
+		}
+	}
+	for i := range g.Groups {
+		if err := validateXLIFF12Group(&g.Groups[i], file, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateXLIFF2Units(units []xliffUnit, groups []xliffGroup, fileID string, seen map[string]bool) error {
+	for _, u := range units {
+		if u.ID == "" {
+			return fmt.Errorf("file %q: unit missing required attribute id", fileID)
+		}
+		if seen[u.ID] {
+			return fmt.Errorf("file %q: duplicate unit id %q", fileID, u.ID)
+		}
+		seen[u.ID] = true
+		if len(u.Segments) == 0 {
+			return fmt.Errorf("file %q: unit %q has no segment", fileID, u.ID)
+		}
+		for j, s := range u.Segments {
+			if len(s.Sources) != 1 {
+				return fmt.Errorf("file %q: unit %q segment %d must have exactly one <source>", fileID, u.ID, j+1)
+			}
+			if len(s.Targets) > 1 {
+				return fmt.Errorf("file %q: unit %q segment %d has more than one <target>", fileID, u.ID, j+1)
+			}
+		}
+	}
+	for _, g := range groups {
+		if err := validateXLIFF2Units(g.Units, g.Groups, fileID, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isXLIFF checks for an <xliff root element.
+func isXLIFF(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "<") && strings.Contains(trimmed, "<xliff")
+}
@@ -0,0 +1,85 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const xliff12 = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="app.ts" source-language="en" target-language="fr" datatype="plaintext">
+    <body>
+      <trans-unit id="greeting"><source>Hello</source><target>Bonjour</target></trans-unit>
+      <group id="menu">
+        <trans-unit id="open"><source>Open</source></trans-unit>
+      </group>
+    </body>
+  </file>
+</xliff>`
+
+const xliff20 = `<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en" trgLang="fr">
+  <file id="app">
+    <unit id="greeting"><segment><source>Hello</source><target>Bonjour</target></segment></unit>
+    <group id="menu">
+      <unit id="open"><segment><source>Open</source></segment></unit>
+    </group>
+  </file>
+</xliff>`
+
+func TestXLIFFValidator(t *testing.T) {
+	v := &XLIFFValidator{baseValidator{format: FormatXLIFF}}
+
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"xliff 1.2", xliff12, true, ""},
+		{"xliff 2.0", xliff20, true, ""},
+		{"malformed xml", `<xliff version="2.0"><file>`, false, "invalid XML"},
+		{"wrong root", `<root/>`, false, "invalid XML"},
+		{"no files", `<xliff version="2.0" srcLang="en"></xliff>`, false, "at least one <file>"},
+		{"missing version", `<xliff srcLang="en"><file id="a"/></xliff>`, false, "missing required attribute version"},
+		{"unsupported version", `<xliff version="3.0"><file id="a"/></xliff>`, false, "unsupported version"},
+		{"1.2 missing datatype", strings.Replace(xliff12, ` datatype="plaintext"`, "", 1), false, "datatype"},
+		{
+			"1.2 missing body",
+			`<xliff version="1.2"><file original="a" source-language="en" datatype="x"/></xliff>`,
+			false,
+			"<body>",
+		},
+		{"1.2 duplicate id", strings.Replace(xliff12, `id="open"`, `id="greeting"`, 1), false, "duplicate trans-unit"},
+		{"1.2 missing source", strings.Replace(xliff12, "<source>Open</source>", "", 1), false, "exactly one <source>"},
+		{"2.0 missing srcLang", strings.Replace(xliff20, ` srcLang="en"`, "", 1), false, "srcLang"},
+		{"2.0 missing file id", strings.Replace(xliff20, `<file id="app">`, "<file>", 1), false, "attribute id"},
+		{
+			"2.0 unit without segment",
+			strings.Replace(xliff20, "<segment><source>Open</source></segment>", "", 1),
+			false,
+			"no segment",
+		},
+		{
+			"2.0 two targets",
+			strings.Replace(xliff20, "<target>Bonjour</target>", "<target>a</target><target>b</target>", 1),
+			false,
+			"more than one <target>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+
+	if got := DetectFormat([]byte(xliff12)); got != FormatXLIFF {
+		t.Errorf("DetectFormat() = %v, want %v", got, FormatXLIFF)
+	}
+}
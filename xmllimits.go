@@ -0,0 +1,116 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// XMLLimits bounds the structure an XMLValidator accepts, so that hostile input cannot make
+// the CLI or web server do unbounded work. encoding/xml never expands entities declared in a
+// DTD (references to them are reported as undefined), so a billion-laughs payload already
+// fails; MaxEntities and ForbidDOCTYPE reject such payloads before any other processing.
+// A zero numeric field uses its default and a negative one disables that limit.
+type XMLLimits struct {
+	// MaxEntities is the number of entity and character references (&amp;, &#65;) allowed,
+	// plus entity declarations in the DOCTYPE; default 1,000,000.
+	MaxEntities int
+	// MaxDepth is the deepest element nesting allowed; default 1,000.
+	MaxDepth int
+	// MaxAttributes is the number of attributes allowed on a single element; default 1,000.
+	MaxAttributes int
+	// ForbidDOCTYPE rejects documents that contain a document type declaration at all.
+	ForbidDOCTYPE bool
+}
+
+const (
+	defaultXMLMaxEntities   = 1000000
+	defaultXMLMaxDepth      = 1000
+	defaultXMLMaxAttributes = 1000
+)
+
+// withDefaults fills zero numeric fields with the default limits.
+func (l XMLLimits) withDefaults() XMLLimits {
+	if l.MaxEntities == 0 {
+		l.MaxEntities = defaultXMLMaxEntities
+	}
+	if l.MaxDepth == 0 {
+		l.MaxDepth = defaultXMLMaxDepth
+	}
+	if l.MaxAttributes == 0 {
+		l.MaxAttributes = defaultXMLMaxAttributes
+	}
+
+	return l
+}
+
+// xmlLimitWalk tracks the counters checked by checkXMLLimits.
+type xmlLimitWalk struct {
+	limits   XMLLimits
+	depth    int
+	entities int
+}
+
+// checkXMLLimits reports the first place data breaks limits. Documents that are not
+// well-formed are left to the parser, which reports a better error than the limit check could.
+func checkXMLLimits(data []byte, limits XMLLimits) error {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	walk := &xmlLimitWalk{limits: limits}
+	start := 0
+	for {
+		tok, err := d.RawToken()
+		if err != nil {
+			return nil
+		}
+		end := int(d.InputOffset())
+		line, _ := d.InputPos()
+		if err := walk.token(tok, data[start:end], line); err != nil {
+			return err
+		}
+		start = end
+	}
+}
+
+// token checks one raw token; raw is its source text.
+func (w *xmlLimitWalk) token(tok xml.Token, raw []byte, line int) error {
+	switch t := tok.(type) {
+	case xml.StartElement:
+		w.depth++
+		if exceeds(w.depth, w.limits.MaxDepth) {
+			return fmt.Errorf("line %d: element nesting exceeds max depth of %d", line, w.limits.MaxDepth)
+		}
+		if exceeds(len(t.Attr), w.limits.MaxAttributes) {
+			return fmt.Errorf("line %d: element <%s> has more than %d attributes",
+				line, t.Name.Local, w.limits.MaxAttributes)
+		}
+
+		return w.countEntities(bytes.Count(raw, []byte("&")), line)
+	case xml.EndElement:
+		w.depth--
+	case xml.CharData:
+		if !bytes.HasPrefix(raw, []byte("<![CDATA[")) {
+			return w.countEntities(bytes.Count(raw, []byte("&")), line)
+		}
+	case xml.Directive:
+		if !bytes.HasPrefix(t, []byte("DOCTYPE")) {
+			return nil
+		}
+		if w.limits.ForbidDOCTYPE {
+			return fmt.Errorf("line %d: DOCTYPE declarations are not allowed", line)
+		}
+
+		return w.countEntities(bytes.Count(t, []byte("<!ENTITY")), line)
+	}
+
+	return nil
+}
+
+// countEntities adds n references or declarations to the running total.
+func (w *xmlLimitWalk) countEntities(n, line int) error {
+	w.entities += n
+	if exceeds(w.entities, w.limits.MaxEntities) {
+		return fmt.Errorf("line %d: more than %d entity references", line, w.limits.MaxEntities)
+	}
+
+	return nil
+}
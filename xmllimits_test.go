@@ -0,0 +1,89 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const xmlBillionLaughs = `<?xml version="1.0"?>
+<!DOCTYPE lolz [
+  <!ENTITY lol "lol">
+  <!ENTITY lol2 "&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;">
+  <!ENTITY lol3 "&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;">
+]>
+<lolz>&lol3;</lolz>`
+
+// manyAttributes returns an element with n distinct attributes.
+func manyAttributes(n int) string {
+	var b strings.Builder
+	b.WriteString("<root")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, ` a%d="x"`, i)
+	}
+	b.WriteString("/>")
+
+	return b.String()
+}
+
+// nestedXML returns depth nested elements.
+func nestedXML(depth int) string {
+	return strings.Repeat("<a>", depth) + strings.Repeat("</a>", depth)
+}
+
+func TestXMLLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits XMLLimits
+		input  string
+		errSub string
+	}{
+		{"ordinary document", XMLLimits{}, `<a x="1 &amp; 2"><b>&lt;&#65;</b></a>`, ""},
+		{"doctype allowed", XMLLimits{}, "<!DOCTYPE note SYSTEM \"note.dtd\">\n<note/>", ""},
+		{"forbid doctype", XMLLimits{ForbidDOCTYPE: true}, "<!DOCTYPE note SYSTEM \"note.dtd\">\n<note/>",
+			"line 1: DOCTYPE declarations are not allowed"},
+		{"billion laughs forbidden", XMLLimits{ForbidDOCTYPE: true}, xmlBillionLaughs, "DOCTYPE declarations"},
+		{"billion laughs undefined entity", XMLLimits{}, xmlBillionLaughs, "invalid character entity &lol3;"},
+		{"entity limit on declarations", XMLLimits{MaxEntities: 2}, xmlBillionLaughs, "more than 2 entity references"},
+		{"entity limit on references", XMLLimits{MaxEntities: 3}, `<a t="&amp;&amp;">&lt;&gt;</a>`,
+			"line 1: more than 3 entity references"},
+		{"cdata is not counted", XMLLimits{MaxEntities: 1}, `<a><![CDATA[&&&&]]>&amp;</a>`, ""},
+		{"entity limit disabled", XMLLimits{MaxEntities: -1}, `<a>` + strings.Repeat("&amp;", 100) + `</a>`, ""},
+		{"default depth", XMLLimits{}, nestedXML(1001), "element nesting exceeds max depth of 1000"},
+		{"depth limit", XMLLimits{MaxDepth: 2}, "<a>\n<b>\n<c/></b></a>", "line 3: element nesting exceeds max depth of 2"},
+		{"depth limit disabled", XMLLimits{MaxDepth: -1}, nestedXML(2000), ""},
+		{"default attributes", XMLLimits{}, manyAttributes(1001), "element <root> has more than 1000 attributes"},
+		{"attribute limit", XMLLimits{MaxAttributes: 2}, manyAttributes(3), "has more than 2 attributes"},
+		{"attribute limit disabled", XMLLimits{MaxAttributes: -1}, manyAttributes(1500), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatXML, WithXMLLimits(tt.limits))
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != (tt.errSub == "") {
+				t.Fatalf("Valid = %v, want %v (error %q)", result.Valid, tt.errSub == "", result.Error)
+			}
+			if !strings.Contains(result.Error, tt.errSub) {
+				t.Errorf("Error = %q, want substring %q", result.Error, tt.errSub)
+			}
+		})
+	}
+}
+
+func TestXMLLimitsWithSchema(t *testing.T) {
+	schema := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="note"/></xs:schema>`
+	v, err := NewValidator(FormatXML, WithXMLSchema([]byte(schema)), WithXMLLimits(XMLLimits{ForbidDOCTYPE: true}))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+	if result := v.ValidateString("<!DOCTYPE note>\n<note/>"); result.Valid {
+		t.Errorf("Validate() = %+v, want DOCTYPE rejected before schema validation", result)
+	}
+	if result := v.ValidateString("<note/>"); !result.Valid {
+		t.Errorf("Validate() = %+v, want valid", result)
+	}
+}
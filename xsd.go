@@ -0,0 +1,1094 @@
+package serdeval
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	xsdNamespace = "http://www.w3.org/2001/XMLSchema"
+	xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+	xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+)
+
+// configure compiles the schema given with WithXMLSchema and records the WithXMLLimits bounds.
+func (v *XMLValidator) configure(o options) error {
+	v.maxErrors = o.maxErrors
+	v.limits = o.xmlLimits
+	if o.maxDepth != 0 {
+		v.limits.MaxDepth = o.maxDepth
+	}
+	if o.xmlSchema == nil {
+		return nil
+	}
+
+	schema, err := parseXSD(o.xmlSchema)
+	if err != nil {
+		return err
+	}
+	v.schema = schema
+
+	return nil
+}
+
+// validateSchema checks that data is well-formed and valid against v.schema.
+func (v *XMLValidator) validateSchema(data []byte) Result {
+	root, err := parseXMLTree(data)
+	if err == nil {
+		errs := v.newErrorCollector()
+		v.schema.validate(root, errs)
+		err = errs.err()
+	}
+
+	return Result{
+		Valid:  err == nil,
+		Format: v.format,
+		Error:  errorString(err),
+	}.locate(data, err)
+}
+
+// xmlNode is an element of a parsed XML document.
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string            // character data directly inside the element
+	prefixes map[string]string // namespace prefixes in scope; "" is the default namespace
+	line     int
+}
+
+// parseXMLTree parses data into a tree of elements, recording the line of each start tag.
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			line, _ := d.InputPos()
+			n := &xmlNode{name: t.Name, attrs: t.Attr, line: line}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("line %d: more than one root element", line)
+				}
+				root = n
+				n.prefixes = scopePrefixes(nil, t.Attr)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+				n.prefixes = scopePrefixes(parent.prefixes, t.Attr)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element")
+	}
+
+	return root, nil
+}
+
+// scopePrefixes returns the prefixes in scope for an element with attrs, sharing parent's
+// map when the element declares no namespaces.
+func scopePrefixes(parent map[string]string, attrs []xml.Attr) map[string]string {
+	var scope map[string]string
+	for _, a := range attrs {
+		var prefix string
+		switch {
+		case a.Name.Space == "xmlns":
+			prefix = a.Name.Local
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			prefix = ""
+		default:
+			continue
+		}
+		if scope == nil {
+			scope = make(map[string]string, len(parent)+1)
+			for k, v := range parent {
+				scope[k] = v
+			}
+		}
+		scope[prefix] = a.Value
+	}
+	if scope == nil {
+		return parent
+	}
+
+	return scope
+}
+
+// attr returns the value of the unqualified attribute local and whether it is present.
+func (n *xmlNode) attr(local string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// attrValue returns the value of the unqualified attribute local, or "" if it is absent.
+func (n *xmlNode) attrValue(local string) string {
+	value, _ := n.attr(local)
+
+	return value
+}
+
+// resolveQName splits a prefixed name from an attribute value into its namespace and local name.
+func (n *xmlNode) resolveQName(qname string) (string, string, error) {
+	prefix, local, ok := strings.Cut(qname, ":")
+	if !ok {
+		prefix, local = "", qname
+	}
+	ns, found := n.prefixes[prefix]
+	if !found && prefix != "" {
+		return "", "", fmt.Errorf("line %d: undeclared namespace prefix in %q", n.line, qname)
+	}
+
+	return ns, local, nil
+}
+
+// schemaChildren returns n's children in the XSD namespace, skipping annotations.
+func (n *xmlNode) schemaChildren() []*xmlNode {
+	var children []*xmlNode
+	for _, child := range n.children {
+		if child.name.Space == xsdNamespace && child.name.Local != "annotation" {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+// xsdSchema is a compiled W3C XML Schema.
+type xsdSchema struct {
+	targetNS string
+	elements map[string]*xsdElement // global element declarations by local name
+	types    map[string]*xsdType    // named types by local name
+}
+
+// xsdType is a simple or complex type. Both simple and complex are nil for xs:anyType,
+// which accepts any attributes and content.
+type xsdType struct {
+	name    string
+	simple  *xsdSimpleType
+	complex *xsdComplexType
+}
+
+// xsdAnyType is the type of elements declared without one.
+var xsdAnyType = &xsdType{name: "anyType"}
+
+// xsdComplexType describes the attributes and content of elements of a complex type.
+type xsdComplexType struct {
+	attributes    []*xsdAttribute
+	anyAttribute  bool
+	mixed         bool
+	content       *xsdParticle   // nil for empty or simple content
+	simpleContent *xsdSimpleType // set when the content is text of a simple type
+}
+
+// xsdElement is an element declaration.
+type xsdElement struct {
+	name     string
+	ns       string
+	typ      *xsdType
+	nillable bool
+	fixed    *string
+}
+
+// xsdAttribute is an attribute declaration or use.
+type xsdAttribute struct {
+	name       string
+	ns         string
+	typ        *xsdSimpleType
+	required   bool
+	prohibited bool
+	fixed      *string
+}
+
+// xsdParticleKind distinguishes the terms of a content model.
+type xsdParticleKind int
+
+const (
+	particleElement xsdParticleKind = iota
+	particleSequence
+	particleChoice
+	particleAll
+	particleAny
+)
+
+// xsdParticle is one term of a content model with its occurrence range.
+type xsdParticle struct {
+	kind     xsdParticleKind
+	min, max int // max is -1 for unbounded
+	element  *xsdElement
+	children []*xsdParticle
+	wildcard *xsdWildcard
+}
+
+// xsdWildcard is an xs:any or xs:anyAttribute namespace constraint.
+type xsdWildcard struct {
+	anyNS      bool
+	other      bool   // ##other: any namespace except otherThan and no namespace
+	otherThan  string // the target namespace for ##other
+	namespaces []string
+	process    string // strict, lax, or skip
+}
+
+// allows reports whether an item in namespace ns matches the wildcard.
+func (w *xsdWildcard) allows(ns string) bool {
+	switch {
+	case w.anyNS:
+		return true
+	case w.other:
+		return ns != w.otherThan && ns != ""
+	}
+
+	return slices.Contains(w.namespaces, ns)
+}
+
+// xsdCompiler turns a parsed schema document into an xsdSchema.
+type xsdCompiler struct {
+	schema              *xsdSchema
+	qualifiedElements   bool
+	qualifiedAttributes bool
+	defs                map[string]map[string]*xmlNode // top-level definitions by kind and name
+	groups              map[string]*xsdParticle
+	attributes          map[string]*xsdAttribute
+	inProgress          map[string]bool // derivations and groups being compiled, to reject cycles
+}
+
+// parseXSD compiles an XSD 1.0 schema. Every global declaration and named type is compiled
+// up front so mistakes in the schema are reported before any document is checked.
+func parseXSD(data []byte) (*xsdSchema, error) {
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if root.name.Space != xsdNamespace || root.name.Local != "schema" {
+		return nil, errors.New("invalid schema: root element must be xs:schema")
+	}
+
+	c := &xsdCompiler{
+		schema: &xsdSchema{
+			targetNS: root.attrValue("targetNamespace"),
+			elements: map[string]*xsdElement{},
+			types:    map[string]*xsdType{},
+		},
+		qualifiedElements:   root.attrValue("elementFormDefault") == "qualified",
+		qualifiedAttributes: root.attrValue("attributeFormDefault") == "qualified",
+		defs:                map[string]map[string]*xmlNode{},
+		groups:              map[string]*xsdParticle{},
+		attributes:          map[string]*xsdAttribute{},
+		inProgress:          map[string]bool{},
+	}
+	if err = c.collect(root); err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(c.defs["type"]) {
+		if _, err = c.namedType(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedKeys(c.defs["element"]) {
+		if _, err = c.globalElement(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.schema, nil
+}
+
+// collect indexes the top-level definitions of the schema by kind and name.
+func (c *xsdCompiler) collect(root *xmlNode) error {
+	for _, n := range root.schemaChildren() {
+		kind := n.name.Local
+		switch kind {
+		case "notation":
+			continue
+		case "import", "include", "redefine", "override":
+			return fmt.Errorf("line %d: xs:%s is not supported; combine the schemas into one document", n.line, kind)
+		case "simpleType", "complexType":
+			kind = "type"
+		case "element", "attribute", "group", "attributeGroup":
+		default:
+			return fmt.Errorf("line %d: unsupported top-level xs:%s", n.line, kind)
+		}
+
+		name := n.attrValue("name")
+		if name == "" {
+			return fmt.Errorf("line %d: top-level xs:%s needs a name", n.line, n.name.Local)
+		}
+		if c.defs[kind] == nil {
+			c.defs[kind] = map[string]*xmlNode{}
+		}
+		if _, dup := c.defs[kind][name]; dup {
+			return fmt.Errorf("line %d: duplicate definition of %s %q", n.line, kind, name)
+		}
+		c.defs[kind][name] = n
+	}
+
+	return nil
+}
+
+// reference resolves a QName attribute naming a component of this schema to its local name.
+func (c *xsdCompiler) reference(n *xmlNode, qname string) (string, error) {
+	ns, local, err := n.resolveQName(qname)
+	if err != nil {
+		return "", err
+	}
+	if ns != c.schema.targetNS {
+		return "", fmt.Errorf("line %d: %q is not in the schema's target namespace", n.line, qname)
+	}
+
+	return local, nil
+}
+
+// typeRef resolves a type attribute to a built-in or named type.
+func (c *xsdCompiler) typeRef(n *xmlNode, qname string) (*xsdType, error) {
+	ns, local, err := n.resolveQName(qname)
+	if err != nil {
+		return nil, err
+	}
+	if ns == xsdNamespace {
+		if local == "anyType" {
+			return xsdAnyType, nil
+		}
+		if !isXSDBuiltin(local) {
+			return nil, fmt.Errorf("line %d: unsupported built-in type %q", n.line, qname)
+		}
+
+		return &xsdType{name: local, simple: builtinSimpleType(local)}, nil
+	}
+	if local, err = c.reference(n, qname); err != nil {
+		return nil, err
+	}
+
+	return c.namedType(local)
+}
+
+// baseTypeRef resolves the base of a derivation, rejecting circular definitions.
+func (c *xsdCompiler) baseTypeRef(n *xmlNode, qname string) (*xsdType, error) {
+	if ns, local, err := n.resolveQName(qname); err == nil && ns == c.schema.targetNS && c.inProgress["type:"+local] {
+		return nil, fmt.Errorf("line %d: type %q is derived from itself", n.line, qname)
+	}
+
+	return c.typeRef(n, qname)
+}
+
+// simpleTypeRef resolves a type attribute that must name a simple type.
+func (c *xsdCompiler) simpleTypeRef(n *xmlNode, qname string) (*xsdSimpleType, error) {
+	t, err := c.baseTypeRef(n, qname)
+	if err != nil {
+		return nil, err
+	}
+	if t.simple == nil {
+		return nil, fmt.Errorf("line %d: %q is not a simple type", n.line, qname)
+	}
+
+	return t.simple, nil
+}
+
+// builtinSimpleType returns the simple type for a built-in type name.
+func builtinSimpleType(name string) *xsdSimpleType {
+	return &xsdSimpleType{name: name, builtin: name, facets: noFacets()}
+}
+
+// namedType compiles the top-level type name on first use.
+func (c *xsdCompiler) namedType(name string) (*xsdType, error) {
+	if t, ok := c.schema.types[name]; ok {
+		return t, nil
+	}
+	n, ok := c.defs["type"][name]
+	if !ok {
+		return nil, fmt.Errorf("type %q is not defined", name)
+	}
+
+	// Register the type before compiling it so recursive content models can refer to it
+	t := &xsdType{name: name}
+	c.schema.types[name] = t
+	c.inProgress["type:"+name] = true
+	defer delete(c.inProgress, "type:"+name)
+
+	if n.name.Local == "complexType" {
+		return t, c.complexType(n, t)
+	}
+	st, err := c.simpleType(n)
+	if err != nil {
+		return nil, err
+	}
+	st.name = name
+	t.simple = st
+
+	return t, nil
+}
+
+// simpleType compiles an xs:simpleType definition.
+func (c *xsdCompiler) simpleType(n *xmlNode) (*xsdSimpleType, error) {
+	for _, child := range n.schemaChildren() {
+		switch child.name.Local {
+		case "restriction":
+			base, err := c.inlineOrRef(child, "base")
+			if err != nil {
+				return nil, err
+			}
+			t := &xsdSimpleType{base: base, facets: noFacets()}
+
+			return t, c.facets(child.schemaChildren(), &t.facets)
+		case "list":
+			item, err := c.inlineOrRef(child, "itemType")
+			if err != nil {
+				return nil, err
+			}
+
+			return &xsdSimpleType{item: item, facets: noFacets()}, nil
+		case "union":
+			return c.union(child)
+		}
+	}
+
+	return nil, fmt.Errorf("line %d: xs:simpleType needs a restriction, list, or union", n.line)
+}
+
+// inlineOrRef resolves the simple type named by attr, or else an inline xs:simpleType child.
+func (c *xsdCompiler) inlineOrRef(n *xmlNode, attr string) (*xsdSimpleType, error) {
+	if qname := n.attrValue(attr); qname != "" {
+		return c.simpleTypeRef(n, qname)
+	}
+	for _, child := range n.schemaChildren() {
+		if child.name.Local == "simpleType" {
+			return c.simpleType(child)
+		}
+	}
+
+	return nil, fmt.Errorf("line %d: xs:%s needs a %s attribute or an inline xs:simpleType", n.line, n.name.Local, attr)
+}
+
+// union compiles an xs:union from its memberTypes attribute and inline member types.
+func (c *xsdCompiler) union(n *xmlNode) (*xsdSimpleType, error) {
+	t := &xsdSimpleType{facets: noFacets()}
+	for _, qname := range strings.Fields(n.attrValue("memberTypes")) {
+		member, err := c.simpleTypeRef(n, qname)
+		if err != nil {
+			return nil, err
+		}
+		t.members = append(t.members, member)
+	}
+	for _, child := range n.schemaChildren() {
+		if child.name.Local != "simpleType" {
+			continue
+		}
+		member, err := c.simpleType(child)
+		if err != nil {
+			return nil, err
+		}
+		t.members = append(t.members, member)
+	}
+	if len(t.members) == 0 {
+		return nil, fmt.Errorf("line %d: xs:union has no member types", n.line)
+	}
+
+	return t, nil
+}
+
+// facets reads the constraining facets of a restriction into f. Attribute declarations
+// and inline base types are left to the caller.
+func (c *xsdCompiler) facets(children []*xmlNode, f *xsdFacets) error {
+	counts := map[string]*int{
+		"length": &f.length, "minLength": &f.minLength, "maxLength": &f.maxLength,
+		"totalDigits": &f.totalDigits, "fractionDigits": &f.fractionDigits,
+	}
+	bounds := map[string]*string{
+		"minInclusive": &f.minInclusive, "maxInclusive": &f.maxInclusive,
+		"minExclusive": &f.minExclusive, "maxExclusive": &f.maxExclusive,
+	}
+
+	for _, n := range children {
+		kind, value := n.name.Local, n.attrValue("value")
+		switch {
+		case kind == "simpleType" || isAttributeDecl(kind):
+			continue
+		case kind == "enumeration":
+			f.enumeration = append(f.enumeration, value)
+		case kind == "pattern":
+			re, err := translateXSDPattern(value)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", n.line, err)
+			}
+			f.patterns = append(f.patterns, re)
+		case kind == "whiteSpace":
+			f.whiteSpace = value
+		case counts[kind] != nil:
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 0 {
+				return fmt.Errorf("line %d: xs:%s value %q must be a non-negative integer", n.line, kind, value)
+			}
+			*counts[kind] = count
+		case bounds[kind] != nil:
+			*bounds[kind] = value
+		default:
+			return fmt.Errorf("line %d: unsupported facet xs:%s", n.line, kind)
+		}
+	}
+
+	return nil
+}
+
+// isAttributeDecl reports whether an XSD element name declares attributes.
+func isAttributeDecl(kind string) bool {
+	return kind == "attribute" || kind == "attributeGroup" || kind == "anyAttribute"
+}
+
+// complexType compiles an xs:complexType definition into t.
+func (c *xsdCompiler) complexType(n *xmlNode, t *xsdType) error {
+	ct := &xsdComplexType{mixed: n.attrValue("mixed") == "true"}
+	t.complex = ct
+
+	for _, child := range n.schemaChildren() {
+		var err error
+		switch child.name.Local {
+		case "simpleContent":
+			err = c.simpleContent(child, ct)
+		case "complexContent":
+			err = c.complexContent(child, ct)
+		default:
+			err = c.contentChild(child, ct)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// contentChild adds a model group or attribute declaration to ct.
+func (c *xsdCompiler) contentChild(n *xmlNode, ct *xsdComplexType) error {
+	kind := n.name.Local
+	switch kind {
+	case "sequence", "choice", "all", "group":
+		if ct.content != nil {
+			return fmt.Errorf("line %d: a complex type can have only one model group", n.line)
+		}
+		p, err := c.particle(n)
+		ct.content = p
+
+		return err
+	case "attribute", "attributeGroup", "anyAttribute":
+		return c.attributeDecl(n, ct)
+	case "assert", "openContent":
+		return fmt.Errorf("line %d: xs:%s (XSD 1.1) is not supported", n.line, kind)
+	}
+
+	return fmt.Errorf("line %d: unexpected xs:%s in a complex type", n.line, kind)
+}
+
+// derivation resolves the base of an extension or restriction and compiles the attribute
+// declarations and model group it adds.
+func (c *xsdCompiler) derivation(n *xmlNode) (*xsdType, *xsdComplexType, error) {
+	base, err := c.baseTypeRef(n, n.attrValue("base"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	own := &xsdComplexType{}
+	for _, child := range n.schemaChildren() {
+		if child.name.Local == "simpleType" || isFacet(child.name.Local) {
+			continue
+		}
+		if err = c.contentChild(child, own); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return base, own, nil
+}
+
+// isFacet reports whether an XSD element name is a constraining facet.
+func isFacet(kind string) bool {
+	switch kind {
+	case "enumeration", "pattern", "whiteSpace", "length", "minLength", "maxLength", "totalDigits",
+		"fractionDigits", "minInclusive", "maxInclusive", "minExclusive", "maxExclusive":
+		return true
+	}
+
+	return false
+}
+
+// derivationChild returns the xs:extension or xs:restriction inside simple or complex content.
+func derivationChild(n *xmlNode) (*xmlNode, error) {
+	for _, child := range n.schemaChildren() {
+		if child.name.Local == "extension" || child.name.Local == "restriction" {
+			return child, nil
+		}
+	}
+
+	return nil, fmt.Errorf("line %d: xs:%s needs an extension or restriction", n.line, n.name.Local)
+}
+
+// simpleContent compiles text-only content with attributes.
+func (c *xsdCompiler) simpleContent(n *xmlNode, ct *xsdComplexType) error {
+	d, err := derivationChild(n)
+	if err != nil {
+		return err
+	}
+	base, own, err := c.derivation(d)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case base.simple != nil:
+		ct.simpleContent = base.simple
+	case base.complex != nil && base.complex.simpleContent != nil:
+		ct.simpleContent = base.complex.simpleContent
+		ct.inherit(base.complex)
+	default:
+		return fmt.Errorf("line %d: simple content must derive from a simple type or simple content", d.line)
+	}
+	if d.name.Local == "restriction" {
+		st := &xsdSimpleType{base: ct.simpleContent, facets: noFacets()}
+		if err = c.facets(d.schemaChildren(), &st.facets); err != nil {
+			return err
+		}
+		ct.simpleContent = st
+	}
+	ct.merge(own)
+
+	return nil
+}
+
+// complexContent compiles content derived from another complex type. An extension appends
+// its model group to the base's; a restriction replaces it.
+func (c *xsdCompiler) complexContent(n *xmlNode, ct *xsdComplexType) error {
+	d, err := derivationChild(n)
+	if err != nil {
+		return err
+	}
+	base, own, err := c.derivation(d)
+	if err != nil {
+		return err
+	}
+	if base.simple != nil {
+		return fmt.Errorf("line %d: complex content cannot derive from simple type %q", d.line, base.name)
+	}
+	if mixed, ok := n.attr("mixed"); ok {
+		ct.mixed = mixed == "true"
+	}
+
+	if base.complex == nil {
+		// Deriving from xs:anyType adds nothing
+		ct.content = own.content
+	} else {
+		ct.inherit(base.complex)
+		ct.content = own.content
+		if d.name.Local == "extension" {
+			ct.mixed = ct.mixed || base.complex.mixed
+			ct.content = appendParticle(base.complex.content, own.content)
+		}
+	}
+	ct.merge(own)
+
+	return nil
+}
+
+// appendParticle returns a model group matching base followed by extra.
+func appendParticle(base, extra *xsdParticle) *xsdParticle {
+	switch {
+	case base == nil:
+		return extra
+	case extra == nil:
+		return base
+	}
+
+	return &xsdParticle{kind: particleSequence, min: 1, max: 1, children: []*xsdParticle{base, extra}}
+}
+
+// inherit copies the attribute declarations of base into ct.
+func (ct *xsdComplexType) inherit(base *xsdComplexType) {
+	ct.attributes = append(ct.attributes, base.attributes...)
+	ct.anyAttribute = ct.anyAttribute || base.anyAttribute
+}
+
+// merge applies the attribute declarations of own over ct's inherited ones.
+func (ct *xsdComplexType) merge(own *xsdComplexType) {
+	for _, a := range own.attributes {
+		ct.setAttribute(a)
+	}
+	ct.anyAttribute = ct.anyAttribute || own.anyAttribute
+}
+
+// setAttribute adds a, replacing any declaration with the same name; prohibited uses remove it.
+func (ct *xsdComplexType) setAttribute(a *xsdAttribute) {
+	kept := ct.attributes[:0:0]
+	for _, existing := range ct.attributes {
+		if existing.name != a.name || existing.ns != a.ns {
+			kept = append(kept, existing)
+		}
+	}
+	if !a.prohibited {
+		kept = append(kept, a)
+	}
+	ct.attributes = kept
+}
+
+// attributeDecl adds an xs:attribute, xs:attributeGroup reference, or xs:anyAttribute to ct.
+func (c *xsdCompiler) attributeDecl(n *xmlNode, ct *xsdComplexType) error {
+	switch n.name.Local {
+	case "anyAttribute":
+		ct.anyAttribute = true
+
+		return nil
+	case "attributeGroup":
+		return c.attributeGroup(n, ct)
+	}
+
+	a, err := c.attribute(n)
+	if err != nil {
+		return err
+	}
+	ct.setAttribute(a)
+
+	return nil
+}
+
+// attributeGroup adds the declarations of a referenced xs:attributeGroup to ct.
+func (c *xsdCompiler) attributeGroup(n *xmlNode, ct *xsdComplexType) error {
+	name, err := c.reference(n, n.attrValue("ref"))
+	if err != nil {
+		return err
+	}
+	def, ok := c.defs["attributeGroup"][name]
+	if !ok {
+		return fmt.Errorf("line %d: attribute group %q is not defined", n.line, name)
+	}
+	if c.inProgress["attributeGroup:"+name] {
+		return fmt.Errorf("line %d: attribute group %q refers to itself", n.line, name)
+	}
+	c.inProgress["attributeGroup:"+name] = true
+	defer delete(c.inProgress, "attributeGroup:"+name)
+
+	for _, child := range def.schemaChildren() {
+		if err = c.attributeDecl(child, ct); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attribute compiles a local attribute declaration or a reference to a global one.
+func (c *xsdCompiler) attribute(n *xmlNode) (*xsdAttribute, error) {
+	var a xsdAttribute
+	if ref := n.attrValue("ref"); ref != "" {
+		global, err := c.attributeRef(n, ref)
+		if err != nil {
+			return nil, err
+		}
+		a = *global
+	} else {
+		decl, err := c.attributeDef(n, c.qualified(n, c.qualifiedAttributes))
+		if err != nil {
+			return nil, err
+		}
+		a = *decl
+	}
+
+	switch use := n.attrValue("use"); use {
+	case "required":
+		a.required = true
+	case "prohibited":
+		a.prohibited = true
+	case "", "optional":
+	default:
+		return nil, fmt.Errorf("line %d: invalid attribute use %q", n.line, use)
+	}
+	if fixed, ok := n.attr("fixed"); ok {
+		a.fixed = &fixed
+	}
+
+	return &a, nil
+}
+
+// attributeRef resolves a reference to a global attribute; xml:lang and the other
+// attributes in the XML namespace are accepted as strings.
+func (c *xsdCompiler) attributeRef(n *xmlNode, ref string) (*xsdAttribute, error) {
+	ns, local, err := n.resolveQName(ref)
+	if err != nil {
+		return nil, err
+	}
+	if ns == xmlNamespace {
+		return &xsdAttribute{name: local, ns: xmlNamespace, typ: builtinSimpleType("string")}, nil
+	}
+	if local, err = c.reference(n, ref); err != nil {
+		return nil, err
+	}
+	if a, ok := c.attributes[local]; ok {
+		return a, nil
+	}
+	def, ok := c.defs["attribute"][local]
+	if !ok {
+		return nil, fmt.Errorf("line %d: attribute %q is not defined", n.line, ref)
+	}
+	a, err := c.attributeDef(def, c.schema.targetNS)
+	if err != nil {
+		return nil, err
+	}
+	c.attributes[local] = a
+
+	return a, nil
+}
+
+// attributeDef compiles the name and type of an attribute declaration in namespace ns.
+func (c *xsdCompiler) attributeDef(n *xmlNode, ns string) (*xsdAttribute, error) {
+	a := &xsdAttribute{name: n.attrValue("name"), ns: ns, typ: builtinSimpleType("anySimpleType")}
+	if a.name == "" {
+		return nil, fmt.Errorf("line %d: xs:attribute needs a name or ref", n.line)
+	}
+	if n.attrValue("type") != "" || len(n.schemaChildren()) > 0 {
+		typ, err := c.inlineOrRef(n, "type")
+		if err != nil {
+			return nil, err
+		}
+		a.typ = typ
+	}
+
+	return a, nil
+}
+
+// qualified returns the namespace of a local element or attribute declaration, which is the
+// target namespace when its form (or the schema default) is qualified.
+func (c *xsdCompiler) qualified(n *xmlNode, byDefault bool) string {
+	form, ok := n.attr("form")
+	if (ok && form == "qualified") || (!ok && byDefault) {
+		return c.schema.targetNS
+	}
+
+	return ""
+}
+
+// particle compiles an element, wildcard, model group, or group reference with its occurrences.
+func (c *xsdCompiler) particle(n *xmlNode) (*xsdParticle, error) {
+	minOccurs, maxOccurs, err := occurrences(n)
+	if err != nil {
+		return nil, err
+	}
+	p := &xsdParticle{min: minOccurs, max: maxOccurs}
+
+	switch kind := n.name.Local; kind {
+	case "element":
+		p.kind = particleElement
+		p.element, err = c.localElement(n)
+	case "any":
+		p.kind = particleAny
+		p.wildcard = c.wildcard(n)
+	case "group":
+		var group *xsdParticle
+		group, err = c.groupRef(n)
+		p.kind, p.children = particleSequence, []*xsdParticle{group}
+	case "sequence", "choice", "all":
+		p.kind = map[string]xsdParticleKind{
+			"sequence": particleSequence, "choice": particleChoice, "all": particleAll}[kind]
+		p.children, err = c.particles(n)
+	default:
+		err = fmt.Errorf("line %d: unexpected xs:%s in a model group", n.line, kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// particles compiles the children of a model group.
+func (c *xsdCompiler) particles(n *xmlNode) ([]*xsdParticle, error) {
+	var children []*xsdParticle
+	for _, child := range n.schemaChildren() {
+		p, err := c.particle(child)
+		if err != nil {
+			return nil, err
+		}
+		if n.name.Local == "all" && (p.kind != particleElement || p.max > 1) {
+			return nil, fmt.Errorf("line %d: xs:all may only contain elements that occur at most once", child.line)
+		}
+		children = append(children, p)
+	}
+
+	return children, nil
+}
+
+// occurrences reads minOccurs and maxOccurs, returning -1 for an unbounded maximum.
+func occurrences(n *xmlNode) (int, int, error) {
+	minOccurs, maxOccurs := 1, 1
+	if v, ok := n.attr("minOccurs"); ok {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("line %d: invalid minOccurs %q", n.line, v)
+		}
+		minOccurs = parsed
+	}
+	if v, ok := n.attr("maxOccurs"); ok {
+		parsed, err := strconv.Atoi(v)
+		switch {
+		case v == "unbounded":
+			parsed = -1
+		case err != nil || parsed < minOccurs:
+			return 0, 0, fmt.Errorf("line %d: invalid maxOccurs %q", n.line, v)
+		}
+		maxOccurs = parsed
+	}
+
+	return minOccurs, maxOccurs, nil
+}
+
+// groupRef compiles the model group of a referenced xs:group.
+func (c *xsdCompiler) groupRef(n *xmlNode) (*xsdParticle, error) {
+	name, err := c.reference(n, n.attrValue("ref"))
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := c.groups[name]; ok {
+		return p, nil
+	}
+	def, ok := c.defs["group"][name]
+	if !ok {
+		return nil, fmt.Errorf("line %d: group %q is not defined", n.line, name)
+	}
+	if c.inProgress["group:"+name] {
+		return nil, fmt.Errorf("line %d: group %q refers to itself", n.line, name)
+	}
+	c.inProgress["group:"+name] = true
+	defer delete(c.inProgress, "group:"+name)
+
+	children := def.schemaChildren()
+	if len(children) != 1 {
+		return nil, fmt.Errorf("line %d: group %q must contain exactly one model group", def.line, name)
+	}
+	p, err := c.particle(children[0])
+	if err != nil {
+		return nil, err
+	}
+	c.groups[name] = p
+
+	return p, nil
+}
+
+// wildcard compiles the namespace constraint and processContents of an xs:any.
+func (c *xsdCompiler) wildcard(n *xmlNode) *xsdWildcard {
+	w := &xsdWildcard{process: n.attrValue("processContents")}
+	if w.process == "" {
+		w.process = "strict"
+	}
+
+	namespace := n.attrValue("namespace")
+	switch namespace {
+	case "", "##any":
+		w.anyNS = true
+	case "##other":
+		w.other, w.otherThan = true, c.schema.targetNS
+	default:
+		for _, ns := range strings.Fields(namespace) {
+			switch ns {
+			case "##targetNamespace":
+				ns = c.schema.targetNS
+			case "##local":
+				ns = ""
+			}
+			w.namespaces = append(w.namespaces, ns)
+		}
+	}
+
+	return w
+}
+
+// localElement compiles an element declared inside a model group, or resolves its ref.
+func (c *xsdCompiler) localElement(n *xmlNode) (*xsdElement, error) {
+	if ref := n.attrValue("ref"); ref != "" {
+		name, err := c.reference(n, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.globalElement(name)
+	}
+
+	e := &xsdElement{ns: c.qualified(n, c.qualifiedElements)}
+
+	return e, c.element(n, e)
+}
+
+// globalElement compiles the top-level element declaration name on first use.
+func (c *xsdCompiler) globalElement(name string) (*xsdElement, error) {
+	if e, ok := c.schema.elements[name]; ok {
+		return e, nil
+	}
+	n, ok := c.defs["element"][name]
+	if !ok {
+		return nil, fmt.Errorf("element %q is not declared", name)
+	}
+
+	// Register the element first so content models can refer to it recursively
+	e := &xsdElement{ns: c.schema.targetNS}
+	c.schema.elements[name] = e
+
+	return e, c.element(n, e)
+}
+
+// element fills e from an xs:element declaration.
+func (c *xsdCompiler) element(n *xmlNode, e *xsdElement) error {
+	e.name = n.attrValue("name")
+	e.nillable = n.attrValue("nillable") == "true"
+	e.typ = xsdAnyType
+	if e.name == "" {
+		return fmt.Errorf("line %d: xs:element needs a name or ref", n.line)
+	}
+	if n.attrValue("substitutionGroup") != "" || n.attrValue("abstract") == "true" {
+		return fmt.Errorf("line %d: substitution groups are not supported", n.line)
+	}
+	if fixed, ok := n.attr("fixed"); ok {
+		e.fixed = &fixed
+	}
+
+	if qname := n.attrValue("type"); qname != "" {
+		t, err := c.typeRef(n, qname)
+		e.typ = t
+
+		return err
+	}
+	for _, child := range n.schemaChildren() {
+		switch child.name.Local {
+		case "simpleType":
+			st, err := c.simpleType(child)
+			e.typ = &xsdType{simple: st}
+
+			return err
+		case "complexType":
+			e.typ = &xsdType{}
+
+			return c.complexType(child, e.typ)
+		}
+	}
+
+	return nil
+}
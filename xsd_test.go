@@ -0,0 +1,282 @@
+package serdeval
+
+import (
+	"strings"
+	"testing"
+)
+
+const orderSchema = `<?xml version="1.0"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+           xmlns="urn:example:orders" targetNamespace="urn:example:orders"
+           elementFormDefault="qualified">
+  <xs:element name="order" type="OrderType"/>
+
+  <xs:complexType name="OrderType">
+    <xs:sequence>
+      <xs:element name="customer" type="xs:string"/>
+      <xs:element name="placed" type="xs:date"/>
+      <xs:element name="item" type="ItemType" maxOccurs="unbounded"/>
+      <xs:choice minOccurs="0">
+        <xs:element name="note" type="xs:string"/>
+        <xs:element name="gift" type="xs:boolean"/>
+      </xs:choice>
+      <xs:any namespace="##other" processContents="skip" minOccurs="0"/>
+    </xs:sequence>
+    <xs:attribute name="id" type="OrderID" use="required"/>
+    <xs:attribute name="status" type="Status" default="open"/>
+  </xs:complexType>
+
+  <xs:complexType name="ItemType">
+    <xs:simpleContent>
+      <xs:extension base="Quantity">
+        <xs:attribute name="sku" type="xs:NCName" use="required"/>
+        <xs:attribute name="currency" type="xs:string" fixed="EUR"/>
+      </xs:extension>
+    </xs:simpleContent>
+  </xs:complexType>
+
+  <xs:simpleType name="OrderID">
+    <xs:restriction base="xs:string">
+      <xs:pattern value="ORD-\d{4}"/>
+    </xs:restriction>
+  </xs:simpleType>
+
+  <xs:simpleType name="Status">
+    <xs:restriction base="xs:token">
+      <xs:enumeration value="open"/>
+      <xs:enumeration value="shipped"/>
+    </xs:restriction>
+  </xs:simpleType>
+
+  <xs:simpleType name="Quantity">
+    <xs:restriction base="xs:positiveInteger">
+      <xs:maxInclusive value="100"/>
+    </xs:restriction>
+  </xs:simpleType>
+</xs:schema>`
+
+// orderXML wraps body in an order element with the given attributes.
+func orderXML(attrs, body string) string {
+	return `<order xmlns="urn:example:orders" ` + attrs + ">" + body + "</order>"
+}
+
+func TestXMLSchemaValidation(t *testing.T) {
+	v, err := NewValidator(FormatXML, WithXMLSchema([]byte(orderSchema)))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+
+	valid := `<customer>Ada</customer><placed>2024-05-01</placed><item sku="a1">3</item>`
+	tests := []struct {
+		name    string
+		input   string
+		valid   bool
+		errPart string
+	}{
+		{"valid", orderXML(`id="ORD-0001"`, valid), true, ""},
+		{"repeated items and choice", orderXML(`id="ORD-0001" status="shipped"`,
+			valid+`<item sku="b2" currency="EUR">100</item><gift>true</gift>`), true, ""},
+		{"foreign wildcard", orderXML(`id="ORD-0001"`, valid+`<x:ext xmlns:x="urn:other"><x:any/></x:ext>`), true, ""},
+		{"not well-formed", orderXML(`id="ORD-0001"`, "<customer>"), false, "syntax error"},
+		{"undeclared root", `<invoice xmlns="urn:example:orders"/>`, false, "root element"},
+		{"wrong namespace", `<order id="ORD-0001">` + valid + `</order>`, false, "root element <order>"},
+		{"missing attribute", orderXML("", valid), false, `missing required attribute "id"`},
+		{"pattern", orderXML(`id="ORD-1"`, valid), false, "pattern"},
+		{"enumeration", orderXML(`id="ORD-0001" status="lost"`, valid), false, "allowed values"},
+		{"unexpected attribute", orderXML(`id="ORD-0001" rush="yes"`, valid), false, `unexpected attribute "rush"`},
+		{"bad date", orderXML(`id="ORD-0001"`, strings.Replace(valid, "2024-05-01", "2024-13-01", 1)), false,
+			"not a valid date"},
+		{"missing child", orderXML(`id="ORD-0001"`, `<customer>Ada</customer><placed>2024-05-01</placed>`), false,
+			"missing required children (expected <item>)"},
+		{"wrong order", orderXML(`id="ORD-0001"`, `<placed>2024-05-01</placed><customer>Ada</customer>`), false,
+			"unexpected child <placed> (expected <customer>)"},
+		{"both choices", orderXML(`id="ORD-0001"`, valid+`<note>x</note><gift>true</gift>`), false,
+			"unexpected child <gift>"},
+		{"quantity range", orderXML(`id="ORD-0001"`, strings.Replace(valid, ">3<", ">101<", 1)), false,
+			"greater than 100"},
+		{"quantity type", orderXML(`id="ORD-0001"`, strings.Replace(valid, ">3<", ">three<", 1)), false,
+			"positiveInteger"},
+		{"fixed attribute", orderXML(`id="ORD-0001"`, valid+`<item sku="b2" currency="USD">1</item>`), false,
+			"fixed value"},
+		{"text in element-only content", orderXML(`id="ORD-0001"`, valid+"stray"), false, "must not contain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if result.Valid != tt.valid {
+				t.Errorf("ValidateString() = %v, want %v (error: %s)", result.Valid, tt.valid, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestXMLSchemaFeatures(t *testing.T) {
+	schema := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"
+	  xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <xs:element name="root">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element ref="node" minOccurs="0" maxOccurs="unbounded"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+  <xs:element name="node" type="Node"/>
+  <xs:complexType name="Node">
+    <xs:sequence>
+      <xs:element name="label" type="Label" nillable="true"/>
+      <xs:group ref="extras"/>
+      <xs:element ref="node" minOccurs="0" maxOccurs="unbounded"/>
+    </xs:sequence>
+    <xs:attributeGroup ref="common"/>
+  </xs:complexType>
+  <xs:complexType name="Leaf">
+    <xs:complexContent>
+      <xs:extension base="Node">
+        <xs:attribute name="weight" type="xs:decimal"/>
+      </xs:extension>
+    </xs:complexContent>
+  </xs:complexType>
+  <xs:group name="extras">
+    <xs:all>
+      <xs:element name="color" type="xs:string" minOccurs="0"/>
+      <xs:element name="tags" type="Tags" minOccurs="0"/>
+    </xs:all>
+  </xs:group>
+  <xs:attributeGroup name="common">
+    <xs:attribute name="lang" type="xs:language"/>
+  </xs:attributeGroup>
+  <xs:simpleType name="Label">
+    <xs:restriction base="xs:string">
+      <xs:minLength value="1"/>
+      <xs:maxLength value="5"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:simpleType name="Tags">
+    <xs:restriction>
+      <xs:simpleType>
+        <xs:list itemType="xs:NCName"/>
+      </xs:simpleType>
+      <xs:maxLength value="2"/>
+    </xs:restriction>
+  </xs:simpleType>
+</xs:schema>`
+
+	v, err := NewValidator(FormatXML, WithXMLSchema([]byte(schema)), WithMaxErrors(-1))
+	if err != nil {
+		t.Fatalf("NewValidator() error = %v", err)
+	}
+
+	xsi := ` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"`
+	tests := []struct {
+		name     string
+		input    string
+		failures int
+		errPart  string
+	}{
+		{"recursive", `<root><node lang="en"><label>a</label><node><label>b</label></node></node></root>`, 0, ""},
+		{"all in any order", `<root><node><label>a</label><tags>x y</tags><color>red</color></node></root>`, 0, ""},
+		{"nil", `<root` + xsi + `><node><label xsi:nil="true"/></node></root>`, 0, ""},
+		{"xsi:type extension", `<root` + xsi + `><node xsi:type="Leaf" weight="1.5"><label>a</label></node></root>`,
+			0, ""},
+		{"nil with content", `<root` + xsi + `><node><label xsi:nil="true">a</label></node></root>`, 1, "xsi:nil"},
+		{"length", `<root><node><label></label></node><node><label>toolong</label></node></root>`, 2,
+			"minimum length"},
+		{"list length", `<root><node><label>a</label><tags>x y z</tags></node></root>`, 1, "maximum length 2"},
+		{"list item", `<root><node><label>a</label><tags>x 1y</tags></node></root>`, 1, "list item"},
+		{"all twice", `<root><node><label>a</label><color>r</color><color>g</color></node></root>`, 1,
+			"unexpected child <color>"},
+		{"attribute group", `<root><node lang="not a tag"><label>a</label></node></root>`, 1, "language"},
+		{"unknown xsi:type", `<root` + xsi + `><node xsi:type="Nope"><label>a</label></node></root>`, 1,
+			"xsi:type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateString(tt.input)
+			if got := len(result.Diagnostics); got != tt.failures {
+				t.Errorf("failures = %d, want %d (error: %s)", got, tt.failures, result.Error)
+			}
+			if tt.errPart != "" && !strings.Contains(result.Error, tt.errPart) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestXMLSchemaErrors(t *testing.T) {
+	wrap := func(body string) string {
+		return `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">` + body + `</xs:schema>`
+	}
+
+	tests := []struct {
+		name    string
+		schema  string
+		errPart string
+	}{
+		{"not a schema", `<root/>`, "xs:schema"},
+		{"malformed", `<xs:schema`, "invalid schema"},
+		{"import", wrap(`<xs:import namespace="urn:x"/>`), "xs:import is not supported"},
+		{"undefined type", wrap(`<xs:element name="a" type="Missing"/>`), `type "Missing" is not defined`},
+		{"unknown builtin", wrap(`<xs:element name="a" type="xs:nope"/>`), "unsupported built-in type"},
+		{"undeclared prefix", wrap(`<xs:element name="a" type="p:T"/>`), "undeclared namespace prefix"},
+		{"duplicate", wrap(`<xs:element name="a"/><xs:element name="a"/>`), "duplicate"},
+		{"circular", wrap(`<xs:simpleType name="A"><xs:restriction base="A"/></xs:simpleType>`), "derived from itself"},
+		{"bad pattern", wrap(`<xs:simpleType name="A"><xs:restriction base="xs:string">` +
+			`<xs:pattern value="[a-z-[aeiou]]"/></xs:restriction></xs:simpleType>`), "subtraction"},
+		{"bad occurs", wrap(`<xs:complexType name="T"><xs:sequence>` +
+			`<xs:element name="a" minOccurs="2" maxOccurs="1"/></xs:sequence></xs:complexType>`), "maxOccurs"},
+		{"substitution group", wrap(`<xs:element name="a" substitutionGroup="b"/>`), "substitution groups"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewValidator(FormatXML, WithXMLSchema([]byte(tt.schema)))
+			if err == nil || !strings.Contains(err.Error(), tt.errPart) {
+				t.Errorf("NewValidator() error = %v, want it to contain %q", err, tt.errPart)
+			}
+		})
+	}
+}
+
+func TestXSDBuiltinTypes(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value string
+		valid bool
+	}{
+		{"int", " 42 ", true},
+		{"int", "2147483648", false},
+		{"unsignedByte", "-1", false},
+		{"decimal", "-1.50", true},
+		{"decimal", "1e3", false},
+		{"double", "1e3", true},
+		{"double", "-INF", true},
+		{"boolean", "yes", false},
+		{"dateTime", "2024-02-29T12:30:00Z", true},
+		{"dateTime", "2023-02-29T12:30:00Z", false},
+		{"time", "25:00:00", false},
+		{"duration", "P1Y2MT3H", true},
+		{"duration", "PT", false},
+		{"hexBinary", "0fA1", true},
+		{"hexBinary", "abc", false},
+		{"base64Binary", "aGVs bG8=", true},
+		{"NMTOKENS", "a b:c", true},
+		{"QName", "xs:string", true},
+		{"NCName", "a:b", false},
+		{"gYearMonth", "2024-13", false},
+		{"string", "  kept  ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.value, func(t *testing.T) {
+			err := builtinSimpleType(tt.typ).validate(tt.value)
+			if (err == nil) != tt.valid {
+				t.Errorf("validate(%q) error = %v, want valid %v", tt.value, err, tt.valid)
+			}
+		})
+	}
+}
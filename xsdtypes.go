@@ -0,0 +1,531 @@
+package serdeval
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// xsdSimpleType is a built-in or user-defined XSD simple type.
+// Exactly one of builtin, base, item, or members is set.
+type xsdSimpleType struct {
+	name    string
+	builtin string           // a built-in type such as "int"
+	base    *xsdSimpleType   // restriction base
+	item    *xsdSimpleType   // list item type
+	members []*xsdSimpleType // union member types
+	facets  xsdFacets
+}
+
+// xsdFacets are the constraining facets added by one restriction step.
+// Unset lengths and digit counts are -1; unset bounds are "".
+type xsdFacets struct {
+	enumeration    []string
+	patterns       []*regexp.Regexp
+	length         int
+	minLength      int
+	maxLength      int
+	minInclusive   string
+	maxInclusive   string
+	minExclusive   string
+	maxExclusive   string
+	totalDigits    int
+	fractionDigits int
+	whiteSpace     string
+}
+
+// noFacets returns facets with every limit unset.
+func noFacets() xsdFacets {
+	return xsdFacets{length: -1, minLength: -1, maxLength: -1, totalDigits: -1, fractionDigits: -1}
+}
+
+var (
+	xsdTZ     = `(Z|[+-]\d{2}:\d{2})?`
+	xsdNCName = regexp.MustCompile(`^[\pL_][\pL\pN._\-\p{Mn}]*$`)
+	xsdNumber = regexp.MustCompile(`^([+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?|[+-]?INF|NaN)$`)
+	// xsdLexical holds the lexical space of built-in types that a pattern fully describes
+	xsdLexical = map[string]*regexp.Regexp{
+		"boolean":    regexp.MustCompile(`^(true|false|1|0)$`),
+		"decimal":    regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`),
+		"float":      xsdNumber,
+		"double":     xsdNumber,
+		"gYear":      regexp.MustCompile(`^-?\d{4,}` + xsdTZ + `$`),
+		"gYearMonth": regexp.MustCompile(`^-?\d{4,}-(0[1-9]|1[0-2])` + xsdTZ + `$`),
+		"gMonth":     regexp.MustCompile(`^--(0[1-9]|1[0-2])` + xsdTZ + `$`),
+		"gDay":       regexp.MustCompile(`^---(0[1-9]|[12]\d|3[01])` + xsdTZ + `$`),
+		"gMonthDay":  regexp.MustCompile(`^--(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])` + xsdTZ + `$`),
+		"language":   regexp.MustCompile(`^[a-zA-Z]{1,8}(-[a-zA-Z0-9]{1,8})*$`),
+		"Name":       regexp.MustCompile(`^[\pL_:][\pL\pN._:\-\p{Mn}]*$`),
+		"NCName":     xsdNCName,
+		"ID":         xsdNCName,
+		"IDREF":      xsdNCName,
+		"ENTITY":     xsdNCName,
+		"QName":      regexp.MustCompile(`^([\pL_][\pL\pN._\-\p{Mn}]*:)?[\pL_][\pL\pN._\-\p{Mn}]*$`),
+		"NMTOKEN":    regexp.MustCompile(`^[\pL\pN._:\-\p{Mn}]+$`),
+		"duration": regexp.MustCompile(
+			`^-?P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`),
+		"date":     regexp.MustCompile(`^-?\d{4,}-\d{2}-\d{2}` + xsdTZ + `$`),
+		"time":     regexp.MustCompile(`^\d{2}:\d{2}:\d{2}(\.\d+)?` + xsdTZ + `$`),
+		"dateTime": regexp.MustCompile(`^-?\d{4,}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?` + xsdTZ + `$`),
+	}
+
+	// xsdIntegerBounds holds the inclusive value range of each integer type; "" is unbounded
+	xsdIntegerBounds = map[string][2]string{
+		"integer":            {"", ""},
+		"long":               {"-9223372036854775808", "9223372036854775807"},
+		"int":                {"-2147483648", "2147483647"},
+		"short":              {"-32768", "32767"},
+		"byte":               {"-128", "127"},
+		"nonNegativeInteger": {"0", ""},
+		"positiveInteger":    {"1", ""},
+		"nonPositiveInteger": {"", "0"},
+		"negativeInteger":    {"", "-1"},
+		"unsignedLong":       {"0", "18446744073709551615"},
+		"unsignedInt":        {"0", "4294967295"},
+		"unsignedShort":      {"0", "65535"},
+		"unsignedByte":       {"0", "255"},
+	}
+
+	// xsdListTypes are the built-in list types and their item types
+	xsdListTypes = map[string]string{"IDREFS": "IDREF", "ENTITIES": "ENTITY", "NMTOKENS": "NMTOKEN"}
+
+	// xsdChecks holds the built-in types that need more than a pattern
+	xsdChecks = map[string]func(string) error{
+		"string":            func(string) error { return nil },
+		"anySimpleType":     func(string) error { return nil },
+		"normalizedString":  func(string) error { return nil },
+		"token":             func(string) error { return nil },
+		"anyURI":            checkXSDAnyURI,
+		"base64Binary":      checkXSDBase64,
+		"hexBinary":         checkXSDHex,
+		"date":              checkXSDDateTime,
+		"time":              checkXSDDateTime,
+		"dateTime":          checkXSDDateTime,
+		"dateTimeStamp":     checkXSDDateTime,
+		"duration":          checkXSDDuration,
+		"yearMonthDuration": checkXSDDuration,
+		"dayTimeDuration":   checkXSDDuration,
+	}
+)
+
+// isXSDBuiltin reports whether name is a supported built-in simple type.
+func isXSDBuiltin(name string) bool {
+	_, lexical := xsdLexical[name]
+	_, integer := xsdIntegerBounds[name]
+	_, list := xsdListTypes[name]
+	_, check := xsdChecks[name]
+
+	return lexical || integer || list || check
+}
+
+// validate checks value against t, normalizing whitespace first.
+func (t *xsdSimpleType) validate(value string) error {
+	value = xsdWhiteSpace(value, t.whiteSpace())
+
+	switch {
+	case t.builtin != "":
+		return checkXSDBuiltin(t.builtin, value)
+	case t.item != nil:
+		for _, item := range strings.Fields(value) {
+			if err := t.item.validate(item); err != nil {
+				return fmt.Errorf("list item %w", err)
+			}
+		}
+
+		return nil
+	case len(t.members) > 0:
+		for _, member := range t.members {
+			if member.validate(value) == nil {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%q does not match any member type of %s", value, t.describe())
+	}
+
+	if err := t.base.validate(value); err != nil {
+		return err
+	}
+
+	return t.checkFacets(value)
+}
+
+// describe names t for error messages.
+func (t *xsdSimpleType) describe() string {
+	if t.name != "" {
+		return t.name
+	}
+	if t.builtin != "" {
+		return t.builtin
+	}
+
+	return "anonymous type"
+}
+
+// primitive returns the built-in type at the root of t's restriction chain,
+// or "" for list and union types.
+func (t *xsdSimpleType) primitive() string {
+	for ; t != nil; t = t.base {
+		if t.builtin != "" {
+			return t.builtin
+		}
+	}
+
+	return ""
+}
+
+// isList reports whether t or a type it restricts is a list type.
+func (t *xsdSimpleType) isList() bool {
+	for ; t != nil; t = t.base {
+		if t.item != nil {
+			return true
+		}
+		if _, ok := xsdListTypes[t.builtin]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// whiteSpace returns the whitespace facet in effect for t.
+func (t *xsdSimpleType) whiteSpace() string {
+	for s := t; s != nil; s = s.base {
+		if s.facets.whiteSpace != "" {
+			return s.facets.whiteSpace
+		}
+		switch {
+		case s.builtin == "string" || s.builtin == "anySimpleType":
+			return "preserve"
+		case s.builtin == "normalizedString":
+			return "replace"
+		case s.builtin != "":
+			return "collapse"
+		}
+	}
+
+	return "collapse"
+}
+
+// xsdWhiteSpace applies an XSD whitespace facet to value.
+func xsdWhiteSpace(value, mode string) string {
+	switch mode {
+	case "replace":
+		return strings.Map(func(r rune) rune {
+			if r == '\t' || r == '\n' || r == '\r' {
+				return ' '
+			}
+
+			return r
+		}, value)
+	case "collapse":
+		return strings.Join(strings.Fields(value), " ")
+	}
+
+	return value
+}
+
+// checkFacets checks value against the facets of one restriction step.
+func (t *xsdSimpleType) checkFacets(value string) error {
+	f := t.facets
+	if len(f.enumeration) > 0 && !slices.Contains(f.enumeration, value) {
+		return fmt.Errorf("%q is not one of the allowed values %s", value, strings.Join(quoteAll(f.enumeration), ", "))
+	}
+	if len(f.patterns) > 0 && !anyMatch(f.patterns, value) {
+		return fmt.Errorf("%q does not match the pattern of %s", value, t.describe())
+	}
+	if err := f.checkLength(t.valueLength(value)); err != nil {
+		return fmt.Errorf("%q %w", value, err)
+	}
+	if err := f.checkBounds(t.primitive(), value); err != nil {
+		return fmt.Errorf("%q %w", value, err)
+	}
+
+	return f.checkDigits(value)
+}
+
+// valueLength measures value in the units the length facets use for t.
+func (t *xsdSimpleType) valueLength(value string) int {
+	switch {
+	case t.isList():
+		return len(strings.Fields(value))
+	case t.primitive() == "hexBinary":
+		return len(value) / 2
+	case t.primitive() == "base64Binary":
+		decoded, _ := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+
+		return len(decoded)
+	}
+
+	return utf8.RuneCountInString(value)
+}
+
+// checkLength applies the length, minLength, and maxLength facets.
+func (f xsdFacets) checkLength(n int) error {
+	switch {
+	case f.length >= 0 && n != f.length:
+		return fmt.Errorf("has length %d, want %d", n, f.length)
+	case f.minLength >= 0 && n < f.minLength:
+		return fmt.Errorf("is shorter than the minimum length %d", f.minLength)
+	case f.maxLength >= 0 && n > f.maxLength:
+		return fmt.Errorf("is longer than the maximum length %d", f.maxLength)
+	}
+
+	return nil
+}
+
+// checkBounds applies the inclusive and exclusive range facets.
+func (f xsdFacets) checkBounds(primitive, value string) error {
+	bounds := []struct {
+		limit string
+		ok    func(int) bool
+		desc  string
+	}{
+		{f.minInclusive, func(c int) bool { return c >= 0 }, "less than"},
+		{f.maxInclusive, func(c int) bool { return c <= 0 }, "greater than"},
+		{f.minExclusive, func(c int) bool { return c > 0 }, "less than or equal to"},
+		{f.maxExclusive, func(c int) bool { return c < 0 }, "greater than or equal to"},
+	}
+	for _, b := range bounds {
+		if b.limit == "" {
+			continue
+		}
+		c, err := compareXSD(primitive, value, b.limit)
+		if err != nil {
+			return err
+		}
+		if !b.ok(c) {
+			return fmt.Errorf("is %s %s", b.desc, b.limit)
+		}
+	}
+
+	return nil
+}
+
+// checkDigits applies the totalDigits and fractionDigits facets to a decimal value.
+func (f xsdFacets) checkDigits(value string) error {
+	if f.totalDigits < 0 && f.fractionDigits < 0 {
+		return nil
+	}
+
+	whole, fraction, _ := strings.Cut(strings.TrimLeft(value, "+-"), ".")
+	whole = strings.TrimLeft(whole, "0")
+	fraction = strings.TrimRight(fraction, "0")
+	if f.fractionDigits >= 0 && len(fraction) > f.fractionDigits {
+		return fmt.Errorf("%q has more than %d fraction digits", value, f.fractionDigits)
+	}
+	if f.totalDigits >= 0 && len(whole)+len(fraction) > f.totalDigits {
+		return fmt.Errorf("%q has more than %d digits", value, f.totalDigits)
+	}
+
+	return nil
+}
+
+// compareXSD orders two values of a built-in primitive type, returning -1, 0, or +1.
+func compareXSD(primitive, a, b string) (int, error) {
+	switch primitive {
+	case "date", "time", "dateTime", "dateTimeStamp":
+		ta, err := parseXSDTime(a)
+		if err != nil {
+			return 0, err
+		}
+		tb, err := parseXSDTime(b)
+		if err != nil {
+			return 0, err
+		}
+
+		return ta.Compare(tb), nil
+	}
+
+	ra, ok := new(big.Rat).SetString(a)
+	if !ok {
+		return 0, fmt.Errorf("%q is not comparable as a %s", a, primitive)
+	}
+	rb, ok := new(big.Rat).SetString(b)
+	if !ok {
+		return 0, fmt.Errorf("bound %q is not comparable as a %s", b, primitive)
+	}
+
+	return ra.Cmp(rb), nil
+}
+
+// checkXSDBuiltin checks value against the lexical space of a built-in type.
+func checkXSDBuiltin(name, value string) error {
+	if item, ok := xsdListTypes[name]; ok {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("empty %s", name)
+		}
+		for _, field := range strings.Fields(value) {
+			if err := checkXSDBuiltin(item, field); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if re, ok := xsdLexical[name]; ok && !re.MatchString(value) {
+		return fmt.Errorf("%q is not a valid %s", value, name)
+	}
+	if bounds, ok := xsdIntegerBounds[name]; ok {
+		return checkXSDInteger(name, value, bounds)
+	}
+	if check, ok := xsdChecks[name]; ok {
+		if err := check(value); err != nil {
+			return fmt.Errorf("%q is not a valid %s: %w", value, name, err)
+		}
+	}
+
+	return nil
+}
+
+// checkXSDInteger checks that value is an integer within bounds.
+func checkXSDInteger(name, value string, bounds [2]string) error {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(value, "+"), 10)
+	if !ok {
+		return fmt.Errorf("%q is not a valid %s", value, name)
+	}
+	if lo, ok := new(big.Int).SetString(bounds[0], 10); ok && n.Cmp(lo) < 0 {
+		return fmt.Errorf("%q is out of range for %s (minimum %s)", value, name, bounds[0])
+	}
+	if hi, ok := new(big.Int).SetString(bounds[1], 10); ok && n.Cmp(hi) > 0 {
+		return fmt.Errorf("%q is out of range for %s (maximum %s)", value, name, bounds[1])
+	}
+
+	return nil
+}
+
+// checkXSDDateTime checks the field ranges of a date, time, or dateTime.
+func checkXSDDateTime(value string) error {
+	_, err := parseXSDTime(value)
+
+	return err
+}
+
+// parseXSDTime parses a date, time, or dateTime value for range checks and comparisons.
+func parseXSDTime(value string) (time.Time, error) {
+	layouts := []string{"2006-01-02T15:04:05.999999999Z07:00", "2006-01-02T15:04:05.999999999",
+		"2006-01-02Z07:00", "2006-01-02", "15:04:05.999999999Z07:00", "15:04:05.999999999"}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.New("field out of range")
+}
+
+// checkXSDDuration rejects durations with no fields, such as "P" or "PT".
+func checkXSDDuration(value string) error {
+	if strings.HasSuffix(value, "P") || strings.HasSuffix(value, "T") {
+		return errors.New("at least one field is required")
+	}
+
+	return nil
+}
+
+// checkXSDAnyURI checks that value parses as a URI reference.
+func checkXSDAnyURI(value string) error {
+	_, err := url.Parse(value)
+
+	return err
+}
+
+// checkXSDBase64 checks that value is base64 once whitespace is removed.
+func checkXSDBase64(value string) error {
+	_, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+
+	return err
+}
+
+// checkXSDHex checks that value is an even number of hex digits.
+func checkXSDHex(value string) error {
+	_, err := hex.DecodeString(value)
+
+	return err
+}
+
+// translateXSDPattern compiles an XSD regular expression as an anchored Go regexp.
+// XSD patterns always match the whole value, treat ^ and $ literally, and add the
+// \i and \c name-character escapes; character class subtraction is not supported.
+func translateXSDPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.Contains(pattern, "-[") {
+		return nil, fmt.Errorf("pattern %q uses character class subtraction, which is not supported", pattern)
+	}
+
+	var b strings.Builder
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(xsdEscape(pattern[i], inClass))
+		case c == '[':
+			inClass = true
+			b.WriteByte(c)
+		case c == ']':
+			inClass = false
+			b.WriteByte(c)
+		case (c == '^' && !inClass) || c == '$':
+			b.WriteString(`\` + string(c))
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	re, err := regexp.Compile(`^(?:` + b.String() + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	return re, nil
+}
+
+// xsdEscape translates the escape \c in an XSD pattern to Go regexp syntax.
+func xsdEscape(c byte, inClass bool) string {
+	classes := map[byte]string{
+		'i': `_:A-Za-z\p{L}`,
+		'c': `\-._:A-Za-z0-9\p{L}\p{N}\p{Mn}`,
+	}
+	if set, ok := classes[c]; ok {
+		if inClass {
+			return set
+		}
+
+		return "[" + set + "]"
+	}
+	if set, ok := classes[c+'a'-'A']; ok && c >= 'A' && c <= 'Z' && !inClass {
+		return "[^" + set + "]"
+	}
+
+	return `\` + string(c)
+}
+
+// anyMatch reports whether value matches at least one of patterns.
+func anyMatch(patterns []*regexp.Regexp, value string) bool {
+	for _, re := range patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteAll quotes each string for an error message.
+func quoteAll(list []string) []string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+
+	return quoted
+}
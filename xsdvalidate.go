@@ -0,0 +1,456 @@
+package serdeval
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// xsdValidation checks one document against a schema, collecting failures.
+type xsdValidation struct {
+	schema *xsdSchema
+	errs   *errorCollector
+}
+
+// validate checks root against the schema's global element declarations.
+func (s *xsdSchema) validate(root *xmlNode, errs *errorCollector) {
+	v := &xsdValidation{schema: s, errs: errs}
+	decl, ok := s.elements[root.name.Local]
+	if !ok || decl.ns != root.name.Space {
+		v.fail(root, "root element <%s> is not declared in the schema", displayXMLName(root.name))
+
+		return
+	}
+	v.element(root, decl)
+}
+
+// fail records a failure located at n and reports whether validation should stop.
+func (v *xsdValidation) fail(n *xmlNode, format string, args ...interface{}) bool {
+	return v.errs.add(fmt.Errorf("line %d: %s", n.line, fmt.Sprintf(format, args...)))
+}
+
+// displayXMLName formats a name for error messages, adding the namespace when there is one.
+func displayXMLName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return "{" + name.Space + "}" + name.Local
+}
+
+// element checks n, its attributes, and its content against decl.
+func (v *xsdValidation) element(n *xmlNode, decl *xsdElement) {
+	typ, ok := v.instanceType(n, decl.typ)
+	if !ok || v.nilled(n, decl) {
+		return
+	}
+
+	switch {
+	case typ.simple != nil:
+		v.simpleElement(n, decl, typ.simple)
+	case typ.complex != nil:
+		v.complexElement(n, decl, typ.complex)
+	}
+}
+
+// instanceType applies an xsi:type override to the declared type.
+func (v *xsdValidation) instanceType(n *xmlNode, declared *xsdType) (*xsdType, bool) {
+	qname := xsiAttr(n, "type")
+	if qname == "" {
+		return declared, true
+	}
+
+	ns, local, err := n.resolveQName(qname)
+	if err != nil {
+		return nil, !v.fail(n, "%s", err)
+	}
+	if ns == xsdNamespace && isXSDBuiltin(local) {
+		return &xsdType{name: local, simple: builtinSimpleType(local)}, true
+	}
+	t, ok := v.schema.types[local]
+	if !ok || ns != v.schema.targetNS {
+		v.fail(n, "element <%s>: xsi:type %q is not defined in the schema", n.name.Local, qname)
+
+		return nil, false
+	}
+
+	return t, true
+}
+
+// nilled reports whether n is an xsi:nil element, checking that it is allowed and empty.
+func (v *xsdValidation) nilled(n *xmlNode, decl *xsdElement) bool {
+	if value := xsiAttr(n, "nil"); value != "true" && value != "1" {
+		return false
+	}
+
+	switch {
+	case !decl.nillable:
+		v.fail(n, "element <%s> is not nillable", n.name.Local)
+	case len(n.children) > 0 || strings.TrimSpace(n.text) != "":
+		v.fail(n, "element <%s> has xsi:nil=\"true\" but is not empty", n.name.Local)
+	}
+
+	return true
+}
+
+// xsiAttr returns the value of the XML Schema instance attribute local on n.
+func xsiAttr(n *xmlNode, local string) string {
+	for _, a := range n.attrs {
+		if a.Name.Space == xsiNamespace && a.Name.Local == local {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// simpleElement checks an element whose type is simple: no attributes, no children, valid text.
+func (v *xsdValidation) simpleElement(n *xmlNode, decl *xsdElement, t *xsdSimpleType) {
+	if v.attributes(n, &xsdComplexType{}) {
+		return
+	}
+	v.text(n, decl, t)
+}
+
+// text checks that n has no child elements and that its text is a valid value of t.
+func (v *xsdValidation) text(n *xmlNode, decl *xsdElement, t *xsdSimpleType) {
+	if len(n.children) > 0 {
+		v.fail(n, "element <%s> must not contain child elements, found <%s>",
+			n.name.Local, n.children[0].name.Local)
+
+		return
+	}
+	if err := t.validate(n.text); err != nil {
+		v.fail(n, "element <%s>: %s", n.name.Local, err)
+
+		return
+	}
+	if decl.fixed != nil && xsdWhiteSpace(n.text, t.whiteSpace()) != *decl.fixed {
+		v.fail(n, "element <%s> must have the fixed value %q", n.name.Local, *decl.fixed)
+	}
+}
+
+// complexElement checks the attributes and content of an element of a complex type.
+func (v *xsdValidation) complexElement(n *xmlNode, decl *xsdElement, ct *xsdComplexType) {
+	if v.attributes(n, ct) {
+		return
+	}
+
+	switch {
+	case ct.simpleContent != nil:
+		v.text(n, decl, ct.simpleContent)
+	case !ct.mixed && strings.TrimSpace(n.text) != "":
+		v.fail(n, "element <%s> must not contain text", n.name.Local)
+	default:
+		v.content(n, ct.content)
+	}
+}
+
+// attributes checks n's attributes against ct and reports whether validation should stop.
+func (v *xsdValidation) attributes(n *xmlNode, ct *xsdComplexType) bool {
+	seen := map[*xsdAttribute]bool{}
+	for _, a := range n.attrs {
+		if isNamespaceAttr(a) || a.Name.Space == xsiNamespace {
+			continue
+		}
+		decl := ct.attribute(a.Name)
+		if decl != nil {
+			seen[decl] = true
+		}
+		if v.attributeValue(n, a, decl, ct.anyAttribute) {
+			return true
+		}
+	}
+
+	for _, decl := range ct.attributes {
+		if decl.required && !seen[decl] && v.fail(n, "element <%s> is missing required attribute %q",
+			n.name.Local, decl.name) {
+			return true
+		}
+	}
+
+	return v.errs.full()
+}
+
+// attributeValue checks one attribute against its declaration, or against the wildcard when it
+// is undeclared, and reports whether validation should stop.
+func (v *xsdValidation) attributeValue(n *xmlNode, a xml.Attr, decl *xsdAttribute, anyAttribute bool) bool {
+	switch {
+	case decl == nil && anyAttribute:
+		return false
+	case decl == nil:
+		return v.fail(n, "element <%s>: unexpected attribute %q", n.name.Local, a.Name.Local)
+	}
+
+	if err := decl.typ.validate(a.Value); err != nil {
+		return v.fail(n, "element <%s>: attribute %q: %s", n.name.Local, a.Name.Local, err)
+	}
+	if decl.fixed != nil && xsdWhiteSpace(a.Value, decl.typ.whiteSpace()) != *decl.fixed {
+		return v.fail(n, "element <%s>: attribute %q must have the fixed value %q", n.name.Local, a.Name.Local,
+			*decl.fixed)
+	}
+
+	return false
+}
+
+// isNamespaceAttr reports whether a is a namespace declaration.
+func isNamespaceAttr(a xml.Attr) bool {
+	return a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns")
+}
+
+// attribute returns the declaration for an attribute name, or nil if it is not declared.
+func (ct *xsdComplexType) attribute(name xml.Name) *xsdAttribute {
+	for _, a := range ct.attributes {
+		if a.name == name.Local && a.ns == name.Space {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// content matches n's child elements against the content model and checks each child.
+func (v *xsdValidation) content(n *xmlNode, model *xsdParticle) {
+	if model == nil {
+		if len(n.children) > 0 {
+			v.fail(n.children[0], "element <%s> must be empty, found <%s>", n.name.Local, n.children[0].name.Local)
+		}
+
+		return
+	}
+
+	m := &contentMatcher{children: n.children, memo: map[matchKey][]int{}, expected: map[string]bool{}}
+	m.matched = make([]*xsdElement, len(n.children))
+	m.wildcards = make([]*xsdWildcard, len(n.children))
+	if ends := m.particle(model, 0); !containsInt(ends, len(n.children)) {
+		v.contentError(n, m)
+
+		return
+	}
+
+	for i, child := range n.children {
+		if v.errs.full() {
+			return
+		}
+		if decl := m.matched[i]; decl != nil {
+			v.element(child, decl)
+		} else if w := m.wildcards[i]; w != nil {
+			v.wildcardElement(child, w)
+		}
+	}
+}
+
+// contentError reports where the children of n stopped matching the content model.
+func (v *xsdValidation) contentError(n *xmlNode, m *contentMatcher) {
+	expected := ""
+	if len(m.expected) > 0 {
+		names := make([]string, 0, len(m.expected))
+		for name := range m.expected {
+			names = append(names, "<"+name+">")
+		}
+		sort.Strings(names)
+		expected = " (expected " + strings.Join(names, ", ") + ")"
+	}
+
+	if m.furthest < len(n.children) {
+		child := n.children[m.furthest]
+		v.fail(child, "element <%s>: unexpected child <%s>%s", n.name.Local, child.name.Local, expected)
+
+		return
+	}
+	v.fail(n, "element <%s> is missing required children%s", n.name.Local, expected)
+}
+
+// wildcardElement checks an element matched by xs:any according to its processContents.
+func (v *xsdValidation) wildcardElement(n *xmlNode, w *xsdWildcard) {
+	if w.process == "skip" {
+		return
+	}
+
+	decl, ok := v.schema.elements[n.name.Local]
+	switch {
+	case ok && decl.ns == n.name.Space:
+		v.element(n, decl)
+	case w.process == "strict" && n.name.Space == v.schema.targetNS:
+		v.fail(n, "element <%s> is not declared in the schema", n.name.Local)
+	}
+}
+
+// matchKey identifies a particle tried at a child position.
+type matchKey struct {
+	p   *xsdParticle
+	pos int
+}
+
+// contentMatcher matches a list of child elements against a content model, recording the
+// declaration or wildcard each child matched and the furthest position reached.
+type contentMatcher struct {
+	children  []*xmlNode
+	memo      map[matchKey][]int
+	matched   []*xsdElement
+	wildcards []*xsdWildcard
+	furthest  int
+	expected  map[string]bool // element names that would have been accepted at furthest
+}
+
+// particle returns every child position where a match of p starting at pos can end.
+func (m *contentMatcher) particle(p *xsdParticle, pos int) []int {
+	key := matchKey{p, pos}
+	if ends, ok := m.memo[key]; ok {
+		return ends
+	}
+
+	var ends []int
+	if p.min == 0 {
+		ends = []int{pos}
+	}
+	current := []int{pos}
+	for count := 1; p.max < 0 || count <= p.max; count++ {
+		var next []int
+		for _, start := range current {
+			next = mergeInts(next, m.term(p, start))
+		}
+		if len(next) == 0 {
+			break
+		}
+		if count >= p.min {
+			grown := mergeInts(ends, next)
+			// Stop once another repetition cannot reach a new position
+			if len(grown) == len(ends) && count > p.min {
+				break
+			}
+			ends = grown
+		}
+		if count > p.min+len(m.children) {
+			break
+		}
+		current = next
+	}
+	m.memo[key] = ends
+
+	return ends
+}
+
+// term matches a single occurrence of p starting at pos.
+func (m *contentMatcher) term(p *xsdParticle, pos int) []int {
+	switch p.kind {
+	case particleElement:
+		return m.elementTerm(p.element, pos)
+	case particleAny:
+		if pos < len(m.children) && p.wildcard.allows(m.children[pos].name.Space) {
+			m.wildcards[pos] = p.wildcard
+			m.reached(pos + 1)
+
+			return []int{pos + 1}
+		}
+	case particleSequence:
+		positions := []int{pos}
+		for _, child := range p.children {
+			var next []int
+			for _, start := range positions {
+				next = mergeInts(next, m.particle(child, start))
+			}
+			positions = next
+		}
+
+		return positions
+	case particleChoice:
+		var ends []int
+		for _, child := range p.children {
+			ends = mergeInts(ends, m.particle(child, pos))
+		}
+
+		return ends
+	case particleAll:
+		return m.allTerm(p, pos)
+	}
+
+	return nil
+}
+
+// elementTerm matches one child against an element declaration.
+func (m *contentMatcher) elementTerm(decl *xsdElement, pos int) []int {
+	if pos < len(m.children) {
+		name := m.children[pos].name
+		if name.Local == decl.name && name.Space == decl.ns {
+			m.matched[pos] = decl
+			m.reached(pos + 1)
+
+			return []int{pos + 1}
+		}
+	}
+	if pos == m.furthest {
+		m.expected[decl.name] = true
+	}
+
+	return nil
+}
+
+// allTerm matches the members of an xs:all group in any order, each at most once.
+func (m *contentMatcher) allTerm(p *xsdParticle, pos int) []int {
+	used := make([]bool, len(p.children))
+	for pos < len(m.children) {
+		found := false
+		for i, member := range p.children {
+			if !used[i] && len(m.elementTerm(member.element, pos)) > 0 {
+				used[i], found = true, true
+
+				break
+			}
+		}
+		if !found {
+			break
+		}
+		pos++
+	}
+
+	for i, member := range p.children {
+		if !used[i] && member.min > 0 {
+			if pos == m.furthest {
+				m.expected[member.element.name] = true
+			}
+
+			return nil
+		}
+	}
+
+	return []int{pos}
+}
+
+// reached records that matching got as far as pos.
+func (m *contentMatcher) reached(pos int) {
+	if pos > m.furthest {
+		m.furthest = pos
+		m.expected = map[string]bool{}
+	}
+}
+
+// mergeInts returns the sorted union of two sorted, duplicate-free slices.
+func mergeInts(a, b []int) []int {
+	merged := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			merged = append(merged, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			merged = append(merged, b[j])
+			j++
+		default:
+			merged = append(merged, a[i])
+			i++
+			j++
+		}
+	}
+
+	return merged
+}
+
+// containsInt reports whether the sorted slice list contains n.
+func containsInt(list []int, n int) bool {
+	i := sort.SearchInts(list, n)
+
+	return i < len(list) && list[i] == n
+}
@@ -0,0 +1,140 @@
+package serdeval
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLLimits bounds the work a YAMLValidator does on one input, so that a small document
+// cannot exhaust memory by nesting aliases to aliases (the "billion laughs" attack).
+// A zero field uses its default and a negative field disables that limit.
+type YAMLLimits struct {
+	// MaxAliases is the number of alias nodes (*name) allowed in the stream; default 10,000.
+	MaxAliases int
+	// MaxDepth is the deepest nesting allowed, counted through aliases; default 1,000.
+	MaxDepth int
+	// MaxNodes is the number of nodes allowed once every alias is expanded; default 1,000,000.
+	MaxNodes int
+}
+
+const (
+	defaultYAMLMaxAliases = 10000
+	defaultYAMLMaxDepth   = 1000
+	defaultYAMLMaxNodes   = 1000000
+)
+
+// withDefaults fills zero fields with the default limits.
+func (l YAMLLimits) withDefaults() YAMLLimits {
+	if l.MaxAliases == 0 {
+		l.MaxAliases = defaultYAMLMaxAliases
+	}
+	if l.MaxDepth == 0 {
+		l.MaxDepth = defaultYAMLMaxDepth
+	}
+	if l.MaxNodes == 0 {
+		l.MaxNodes = defaultYAMLMaxNodes
+	}
+
+	return l
+}
+
+// exceeds reports whether n is over limit, where a negative limit never is.
+func exceeds(n, limit int) bool {
+	return limit >= 0 && n > limit
+}
+
+// yamlExtent is the expanded size of a node: how many nodes it stands for and how deep it nests.
+type yamlExtent struct {
+	nodes int
+	depth int
+}
+
+// yamlLimitWalk measures node trees against YAMLLimits without expanding aliases,
+// memoizing each anchored subtree so that measuring takes time linear in the input.
+type yamlLimitWalk struct {
+	limits  YAMLLimits
+	aliases int
+	memo    map[*yaml.Node]yamlExtent
+	active  map[*yaml.Node]bool
+}
+
+// checkYAMLLimits reports the first document in data that breaks limits. Streams that do not
+// parse are left to the parser, which reports a better error than the limit check could.
+func checkYAMLLimits(data []byte, limits YAMLLimits) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	walk := &yamlLimitWalk{limits: limits, memo: map[*yaml.Node]yamlExtent{}, active: map[*yaml.Node]bool{}}
+	total := 0
+	for {
+		var doc yaml.Node
+		if dec.Decode(&doc) != nil {
+			return nil
+		}
+		extent, err := walk.extent(&doc)
+		if err != nil {
+			return err
+		}
+		total = saturatingAdd(total, extent.nodes)
+		if exceeds(total, limits.MaxNodes) {
+			return fmt.Errorf("line %d: stream expands to more than %d nodes", doc.Line, limits.MaxNodes)
+		}
+	}
+}
+
+// extent measures n, following aliases to their anchors.
+func (w *yamlLimitWalk) extent(n *yaml.Node) (yamlExtent, error) {
+	if n.Kind == yaml.AliasNode {
+		w.aliases++
+		if exceeds(w.aliases, w.limits.MaxAliases) {
+			return yamlExtent{}, fmt.Errorf("line %d, column %d: more than %d aliases",
+				n.Line, n.Column, w.limits.MaxAliases)
+		}
+		if n.Alias == nil || w.active[n.Alias] {
+			return yamlExtent{}, fmt.Errorf("line %d, column %d: alias *%s refers to its own anchor",
+				n.Line, n.Column, n.Value)
+		}
+
+		return w.extent(n.Alias)
+	}
+	if e, ok := w.memo[n]; ok {
+		return e, nil
+	}
+
+	w.active[n] = true
+	defer delete(w.active, n)
+	e := yamlExtent{nodes: 1}
+	for _, child := range n.Content {
+		c, err := w.extent(child)
+		if err != nil {
+			return yamlExtent{}, err
+		}
+		e.nodes = saturatingAdd(e.nodes, c.nodes)
+		e.depth = max(e.depth, c.depth)
+	}
+	if n.Kind != yaml.DocumentNode {
+		e.depth++
+	}
+
+	switch {
+	case exceeds(e.depth, w.limits.MaxDepth):
+		return yamlExtent{}, fmt.Errorf("line %d, column %d: nesting exceeds max depth of %d",
+			n.Line, n.Column, w.limits.MaxDepth)
+	case exceeds(e.nodes, w.limits.MaxNodes):
+		return yamlExtent{}, fmt.Errorf("line %d, column %d: value expands to more than %d nodes",
+			n.Line, n.Column, w.limits.MaxNodes)
+	}
+	w.memo[n] = e
+
+	return e, nil
+}
+
+// saturatingAdd adds two non-negative counts, stopping at math.MaxInt instead of overflowing.
+func saturatingAdd(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+
+	return a + b
+}
@@ -0,0 +1,63 @@
+package serdeval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// billionLaughs builds the classic alias bomb: each of levels anchors lists ten aliases
+// to the previous anchor, so the last value expands to 10^levels scalars.
+func billionLaughs(levels int) string {
+	var b strings.Builder
+	b.WriteString("a0: &a0 lol\n")
+	for i := 1; i <= levels; i++ {
+		prev := fmt.Sprintf("*a%d", i-1)
+		fmt.Fprintf(&b, "a%d: &a%d [%s]\n", i, i, strings.TrimSuffix(strings.Repeat(prev+", ", 10), ", "))
+	}
+
+	return b.String()
+}
+
+// nestedYAML returns a flow sequence nested depth levels deep.
+func nestedYAML(depth int) string {
+	return strings.Repeat("[", depth) + strings.Repeat("]", depth)
+}
+
+func TestYAMLLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits YAMLLimits
+		input  string
+		errSub string
+	}{
+		{"ordinary anchors", YAMLLimits{}, "base: &b {x: 1}\nc: *b\nd: *b\n", ""},
+		{"small alias tree", YAMLLimits{}, billionLaughs(3), ""},
+		{"billion laughs", YAMLLimits{}, billionLaughs(9), "expands to more than 1000000 nodes"},
+		{"node limit", YAMLLimits{MaxNodes: 100}, billionLaughs(3), "expands to more than 100 nodes"},
+		{"node limit across documents", YAMLLimits{MaxNodes: 5}, "a: 1\n---\nb: 2\n---\nc: 3\n",
+			"stream expands to more than 5 nodes"},
+		{"node limit disabled", YAMLLimits{MaxNodes: -1}, billionLaughs(3), ""},
+		{"alias limit", YAMLLimits{MaxAliases: 2}, "a: &a 1\nb: *a\nc: *a\nd: *a\n", "line 4, column 4: more than 2 aliases"},
+		{"default depth", YAMLLimits{}, nestedYAML(1001), "nesting exceeds max depth of 1000"},
+		{"depth limit", YAMLLimits{MaxDepth: 3}, "a:\n  b:\n    c: 1\n", "nesting exceeds max depth of 3"},
+		{"depth through aliases", YAMLLimits{MaxDepth: 4}, "x: &x [[1]]\ny: [[*x]]\n", "nesting exceeds max depth of 4"},
+		{"depth limit disabled", YAMLLimits{MaxDepth: -1}, nestedYAML(2000), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(FormatYAML, WithYAMLLimits(tt.limits))
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			result := v.ValidateString(tt.input)
+			if result.Valid != (tt.errSub == "") {
+				t.Fatalf("Valid = %v, want %v (error %q)", result.Valid, tt.errSub == "", result.Error)
+			}
+			if !strings.Contains(result.Error, tt.errSub) {
+				t.Errorf("Error = %q, want substring %q", result.Error, tt.errSub)
+			}
+		})
+	}
+}